@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/joho/godotenv"
+
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/backfill"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+	"github.com/trigg3rX/triggerx-backend/pkg/env"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	tables := flag.String("tables", strings.Join(backfill.Tables, ","), "comma separated lookup tables to fill")
+	pageSize := flag.Int("page-size", 1000, "rows read from a base table per page")
+	dryRun := flag.Bool("dry-run", false, "count the rows to write without writing them")
+	flag.Parse()
+
+	logger, err := logging.NewZapLogger(logging.LoggerConfig{
+		ProcessName:   logging.DatabaseProcess,
+		IsDevelopment: true,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+
+	dbConfig := &database.Config{
+		Hosts:       []string{env.GetEnvString("DATABASE_HOST_ADDRESS", "localhost") + ":" + env.GetEnvString("DATABASE_HOST_PORT", "9042")},
+		Keyspace:    "triggerx",
+		Consistency: gocql.Quorum,
+		Timeout:     30 * time.Second,
+		Retries:     3,
+		ConnectWait: 5 * time.Second,
+		RetryConfig: retry.DefaultRetryConfig(),
+	}
+	conn, err := database.NewConnection(dbConfig, logger)
+	if err != nil || conn == nil {
+		logger.Fatalf("Failed to initialize database connection: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	backfiller := backfill.NewBackfiller(conn.Session(), *pageSize, *dryRun, logger)
+	results, err := backfiller.Run(ctx, strings.Split(*tables, ","))
+	if err != nil {
+		logger.Error("Backfill failed", "error", err)
+		conn.Close()
+		os.Exit(1)
+	}
+
+	conflicts := 0
+	for _, result := range results {
+		conflicts += result.Conflicts
+	}
+	if conflicts > 0 {
+		logger.Warn("Backfill complete with addresses registered more than once, resolve them by hand", "conflicts", conflicts)
+		return
+	}
+	logger.Info("Backfill complete")
+}
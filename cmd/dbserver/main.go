@@ -15,11 +15,13 @@ import (
 
 	dbserver "github.com/trigg3rX/triggerx-backend/internal/dbserver"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/config"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/migrations"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/database"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
 )
 
 const shutdownTimeout = 30 * time.Second
@@ -66,6 +68,20 @@ func main() {
 		logger.Fatalf("Database session cannot be nil")
 	}
 
+	if config.IsAutoMigrate() {
+		loaded, err := database.LoadMigrations(migrations.FS)
+		if err != nil {
+			logger.Fatalf("Failed to load migrations: %v", err)
+		}
+		// Instances starting together wait on the migration lock, one of them migrates
+		migrator := database.NewMigrator(mainSession, loaded, database.DefaultMigratorConfig(), logger)
+		applied, err := migrator.Up(context.Background(), false)
+		if err != nil {
+			logger.Fatalf("Failed to apply migrations: %v", err)
+		}
+		logger.Info("Schema migrations up to date", "applied", len(applied))
+	}
+
 	var wg sync.WaitGroup
 	serverErrors := make(chan error, 1)
 	ready := make(chan struct{})
@@ -99,6 +115,30 @@ func main() {
 			serverErrors <- fmt.Errorf("HTTP server error: %v", err)
 		}
 	}()
+	servers := []*http.Server{srv}
+
+	// Services holding a certificate of the internal CA call internal endpoints over mutual TLS
+	if config.GetRPCTLSConfig().Enabled() {
+		tlsConfig, err := mtls.ServerTLSConfig(config.GetRPCTLSConfig())
+		if err != nil {
+			logger.Fatalf("Failed to load internal listener certificate: %v", err)
+		}
+		internalSrv := &http.Server{
+			Addr:      fmt.Sprintf(":%s", config.GetInternalPort()),
+			Handler:   dbServer.GetRouter(),
+			TLSConfig: tlsConfig,
+		}
+		servers = append(servers, internalSrv)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Infof("Starting internal mTLS server on port %s...", config.GetInternalPort())
+			if err := internalSrv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				serverErrors <- fmt.Errorf("internal HTTP server error: %v", err)
+			}
+		}()
+	}
 
 	close(ready)
 	logger.Infof("Database Server initialized, starting on port %s...", config.GetDBServerRPCPort())
@@ -113,19 +153,21 @@ func main() {
 		logger.Info("Received shutdown signal", "signal", sig.String())
 	}
 
-	performGracefulShutdown(srv, &wg, logger, dockerExecutor)
+	performGracefulShutdown(servers, &wg, logger, dockerExecutor)
 }
 
-func performGracefulShutdown(srv *http.Server, wg *sync.WaitGroup, logger logging.Logger, dockerExecutor dockerexecutor.DockerExecutorAPI) {
+func performGracefulShutdown(servers []*http.Server, wg *sync.WaitGroup, logger logging.Logger, dockerExecutor dockerexecutor.DockerExecutorAPI) {
 	logger.Info("Initiating graceful shutdown...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
-		logger.Error("HTTP server shutdown error", "error", err)
-		if err := srv.Close(); err != nil {
-			logger.Error("Forced HTTP server close error", "error", err)
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			logger.Error("HTTP server shutdown error", "error", err)
+			if err := srv.Close(); err != nil {
+				logger.Error("Forced HTTP server close error", "error", err)
+			}
 		}
 	}
 
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/joho/godotenv"
+
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/migrations"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+	"github.com/trigg3rX/triggerx-backend/pkg/env"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+)
+
+const usage = `Usage: migrate [flags] <command>
+
+Commands:
+  up        apply pending migrations, -dry-run lists them instead
+  status    list migrations and when they were applied
+  baseline  record migrations up to -version as applied without running them,
+            for keyspaces created before migrations were tracked. Those hold 001
+            and 002 only: run baseline -version 2, then up to apply 003 onwards
+
+Flags:
+`
+
+func main() {
+	_ = godotenv.Load()
+
+	keyspace := flag.String("keyspace", "triggerx", "keyspace to migrate")
+	dryRun := flag.Bool("dry-run", false, "list the migrations up would apply without applying them")
+	version := flag.Int("version", 0, "last migration version baseline records as applied")
+	lockWait := flag.Duration("lock-wait", database.DefaultMigratorConfig().LockWait, "how long to wait for another instance's migration lock")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger, err := logging.NewZapLogger(logging.LoggerConfig{
+		ProcessName:   logging.DatabaseProcess,
+		IsDevelopment: true,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+
+	loaded, err := database.LoadMigrations(migrations.FS)
+	if err != nil {
+		logger.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	dbConfig := &database.Config{
+		Hosts:       []string{env.GetEnvString("DATABASE_HOST_ADDRESS", "localhost") + ":" + env.GetEnvString("DATABASE_HOST_PORT", "9042")},
+		Keyspace:    *keyspace,
+		Consistency: gocql.Quorum,
+		Timeout:     30 * time.Second,
+		Retries:     3,
+		ConnectWait: 5 * time.Second,
+		RetryConfig: retry.DefaultRetryConfig(),
+	}
+	conn, err := database.NewConnection(dbConfig, logger)
+	if err != nil || conn == nil {
+		logger.Fatalf("Failed to initialize database connection: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	config := database.DefaultMigratorConfig()
+	config.LockWait = *lockWait
+	migrator := database.NewMigrator(conn.Session(), loaded, config, logger)
+
+	if err := run(ctx, migrator, flag.Arg(0), *dryRun, *version); err != nil {
+		logger.Error("Migration failed", "command", flag.Arg(0), "error", err)
+		conn.Close()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, migrator *database.Migrator, command string, dryRun bool, version int) error {
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx, dryRun)
+		if dryRun {
+			fmt.Printf("%d pending migrations\n", len(applied))
+		} else {
+			fmt.Printf("Applied %d migrations\n", len(applied))
+		}
+		for _, migration := range applied {
+			fmt.Printf("  %03d_%s\n", migration.Version, migration.Name)
+			if dryRun {
+				for _, statement := range migration.Statements {
+					fmt.Printf("      %s;\n", statement)
+				}
+			}
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			switch {
+			case status.Modified:
+				state = "MODIFIED since applied " + status.AppliedAt.Format(time.RFC3339)
+			case status.Applied:
+				state = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%03d_%-32s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+
+	case "baseline":
+		if version <= 0 {
+			return fmt.Errorf("baseline needs -version")
+		}
+		baselined, err := migrator.Baseline(ctx, version)
+		fmt.Printf("Recorded %d migrations as applied\n", len(baselined))
+		for _, migration := range baselined {
+			fmt.Printf("  %03d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
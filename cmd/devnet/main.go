@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/trigg3rX/triggerx-backend/internal/devnet"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+func main() {
+	scenarioName := flag.String("scenario", "", fmt.Sprintf("scenario to run, one of: %s. Without it the devnet keeps running until interrupted", strings.Join(devnet.ScenarioNames(), ", ")))
+	keepers := flag.Int("keepers", 3, "number of keepers to boot")
+	quorum := flag.Uint64("quorum", 66, "aggregator quorum in percent of attesters")
+	flag.Parse()
+
+	// Initialize logger
+	logger, err := logging.NewZapLogger(logging.LoggerConfig{
+		ProcessName:   logging.TestProcess,
+		IsDevelopment: true,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+
+	var scenario devnet.Scenario
+	if *scenarioName != "" {
+		newScenario, ok := devnet.Scenarios[*scenarioName]
+		if !ok {
+			logger.Fatal("Unknown scenario", "scenario", *scenarioName, "available", devnet.ScenarioNames())
+		}
+		scenario = newScenario()
+	}
+
+	d, err := devnet.New(logger, devnet.Config{Keepers: *keepers, QuorumPercent: *quorum})
+	if err != nil {
+		logger.Fatal("Failed to start devnet", "error", err)
+	}
+	defer d.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if *scenarioName != "" {
+		if err := scenario.Run(ctx, d); err != nil {
+			logger.Error("Scenario failed", "error", err)
+			d.Close()
+			os.Exit(1)
+		}
+		logger.Info("Scenario passed", "scenario", scenario.Name)
+		return
+	}
+
+	logger.Infof("Devnet is ready, dbserver at %s, health at %s, aggregator at %s, attestation chain at %s/chain, Scylla at %s:%s, Redis at %s",
+		d.DBServerURL, d.HealthURL, d.AggregatorURL, d.AggregatorURL, d.Database.Host(), d.Database.Port(), d.Redis.Addr())
+	<-ctx.Done()
+	logger.Info("Shutdown complete")
+}
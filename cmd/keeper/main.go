@@ -18,6 +18,7 @@ import (
 	"github.com/trigg3rX/triggerx-backend/internal/keeper/metrics"
 	"github.com/trigg3rX/triggerx-backend/pkg/client/aggregator"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor"
+	dockerconfig "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
 	"github.com/trigg3rX/triggerx-backend/pkg/ipfs"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 )
@@ -90,7 +91,13 @@ func main() {
 	}
 	logger.Info("[3/6] Dependency: Aggregator client Initialised")
 
-	dockerManager, err := dockerexecutor.NewDockerExecutorFromFile("config/docker-executor.yaml", logger)
+	executorConfig, err := dockerconfig.NewConfigProvider("config/docker-executor.yaml")
+	if err != nil {
+		logger.Fatal("Failed to load code executor config", "error", err)
+	}
+	// Keepers only run tasks assigned to them, tenant quotas are enforced by the dbserver
+	executorConfig.DisableQuotas()
+	dockerManager, err := dockerexecutor.NewDockerExecutorFromConfig(executorConfig, logger)
 	if err != nil {
 		logger.Fatal("Failed to initialize code executor", "error", err)
 	}
@@ -101,8 +108,7 @@ func main() {
 	}
 	logger.Infof("[4/6] Dependency: Code executor Initialised")
 
-	ipfsCfg := ipfs.NewConfig(config.GetIpfsHost(), config.GetPinataJWT())
-	ipfsClient, err := ipfs.NewClient(ipfsCfg, logger)
+	ipfsClient, err := ipfs.NewStorage(config.GetIPFSStorageConfig(), logger)
 	if err != nil {
 		logger.Fatal("Failed to initialize IPFS client", "error", err)
 	}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/trigg3rX/triggerx-backend/internal/localaggregator"
+	"github.com/trigg3rX/triggerx-backend/internal/localaggregator/config"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+const shutdownTimeout = 30 * time.Second
+
+func main() {
+	// Initialize configuration
+	if err := config.Init(); err != nil {
+		panic(fmt.Sprintf("Failed to initialize config: %v", err))
+	}
+
+	// Initialize logger
+	logConfig := logging.LoggerConfig{
+		ProcessName:   logging.AggregatorProcess,
+		IsDevelopment: config.IsDevMode(),
+	}
+
+	logger, err := logging.NewZapLogger(logConfig)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+
+	logger.Info("Starting local aggregator stand-in ...")
+
+	chain, err := localaggregator.NewChain(config.GetChainID(), config.GetAttestationCenterAddress())
+	if err != nil {
+		logger.Fatal("Failed to create simulated chain", "error", err)
+	}
+	chainServer, err := chain.RPCServer()
+	if err != nil {
+		logger.Fatal("Failed to create simulated chain RPC server", "error", err)
+	}
+	defer chainServer.Stop()
+
+	aggregator, err := localaggregator.NewAggregator(logger, localaggregator.Config{
+		QuorumPercent:  config.GetQuorumPercent(),
+		RequestTimeout: config.GetRequestTimeout(),
+	}, chain)
+	if err != nil {
+		logger.Fatal("Failed to create aggregator", "error", err)
+	}
+	for _, keeper := range config.GetKeepers() {
+		if err := aggregator.RegisterKeeper(keeper); err != nil {
+			logger.Fatal("Failed to register keeper", "address", keeper.Address, "error", err)
+		}
+	}
+
+	// The aggregator methods are served at the root, as the Othentic aggregator does, and the
+	// simulated chain at /chain for taskmonitor's listener
+	mux := http.NewServeMux()
+	mux.Handle("/chain", chainServer)
+	mux.Handle("/", aggregator)
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%s", config.GetRPCPort()),
+		Handler: mux,
+	}
+
+	serverErrors := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serverErrors <- fmt.Errorf("HTTP server error: %v", err)
+		}
+	}()
+
+	logger.Infof("Local aggregator is ready on port %s, simulated chain %d at /chain", config.GetRPCPort(), config.GetChainID())
+
+	// Handle graceful shutdown
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErrors:
+		logger.Error("Server error received", "error", err)
+	case sig := <-shutdown:
+		logger.Info("Received shutdown signal", "signal", sig.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("HTTP server shutdown error", "error", err)
+	}
+	aggregator.Wait()
+	logger.Info("Shutdown complete")
+}
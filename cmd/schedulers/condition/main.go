@@ -12,9 +12,12 @@ import (
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/api"
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/config"
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/metrics"
+	schedulerrpc "github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/rpc"
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/scheduler"
 	"github.com/trigg3rX/triggerx-backend/pkg/client/dbserver"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	rpcserver "github.com/trigg3rX/triggerx-backend/pkg/rpc/server"
 )
 
 const shutdownTimeout = 30 * time.Second
@@ -46,6 +49,9 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to initialize database client", "error", err)
 	}
+	if err := dbClient.SetCredentials(dbserver.NewServiceCredentials("condition-scheduler", config.GetServiceTokenKey(), config.GetRPCTLSConfig())); err != nil {
+		logger.Fatal("Failed to configure database client credentials", "error", err)
+	}
 
 	// Perform initial health check
 	logger.Info("Performing initial health check...")
@@ -89,6 +95,21 @@ func main() {
 		}
 	}()
 
+	// Start the typed gRPC API when a port is configured
+	var rpcServer *rpcserver.Server
+	if port := config.GetSchedulerGRPCPort(); port != "" {
+		auth := rpcserver.NewServiceAuthConfig(rpcpkg.ServiceConditionScheduler, config.GetServiceTokenPublicKeys(), schedulerrpc.MethodPolicy)
+		if config.IsDevMode() && !config.GetRPCTLSConfig().Enabled() && len(config.GetServiceTokenPublicKeys()) == 0 {
+			logger.Warn("gRPC server accepts unauthenticated calls - no service token keys or RPC TLS certificate in dev mode")
+			auth = nil
+		}
+		rpcServer, err = schedulerrpc.StartRPCServer(ctx, logger, conditionScheduler, port, config.GetRPCTLSConfig(), auth)
+		if err != nil {
+			logger.Fatal("Failed to start gRPC server", "error", err)
+		}
+		logger.Info("gRPC server for condition scheduler API started", "port", port)
+	}
+
 	// Log comprehensive service status
 	serviceStatus := map[string]interface{}{
 		"manager_id":           managerID,
@@ -114,10 +135,10 @@ func main() {
 
 	<-shutdown
 
-	performGracefulShutdown(cancel, srv, conditionScheduler, dbClient, logger)
+	performGracefulShutdown(cancel, srv, rpcServer, conditionScheduler, dbClient, logger)
 }
 
-func performGracefulShutdown(cancel context.CancelFunc, srv *api.Server, conditionScheduler *scheduler.ConditionBasedScheduler, dbClient *dbserver.DBServerClient, logger logging.Logger) {
+func performGracefulShutdown(cancel context.CancelFunc, srv *api.Server, rpcServer *rpcserver.Server, conditionScheduler *scheduler.ConditionBasedScheduler, dbClient *dbserver.DBServerClient, logger logging.Logger) {
 	shutdownStart := time.Now()
 	logger.Info("Initiating graceful shutdown...")
 
@@ -138,6 +159,11 @@ func performGracefulShutdown(cancel context.CancelFunc, srv *api.Server, conditi
 	if err := srv.Stop(shutdownCtx); err != nil {
 		logger.Error("Server forced to shutdown", "error", err)
 	}
+	if rpcServer != nil {
+		if err := rpcServer.Stop(shutdownCtx); err != nil {
+			logger.Error("gRPC server forced to shutdown", "error", err)
+		}
+	}
 
 	shutdownDuration := time.Since(shutdownStart)
 
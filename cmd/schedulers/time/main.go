@@ -11,9 +11,12 @@ import (
 
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/time/api"
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/time/config"
+	schedulerrpc "github.com/trigg3rX/triggerx-backend/internal/schedulers/time/rpc"
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/time/scheduler"
 	"github.com/trigg3rX/triggerx-backend/pkg/client/dbserver"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	rpcserver "github.com/trigg3rX/triggerx-backend/pkg/rpc/server"
 )
 
 const shutdownTimeout = 30 * time.Second
@@ -43,6 +46,9 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to initialize database client", "error", err)
 	}
+	if err := dbClient.SetCredentials(dbserver.NewServiceCredentials("time-scheduler", config.GetServiceTokenKey(), config.GetRPCTLSConfig())); err != nil {
+		logger.Fatal("Failed to configure database client credentials", "error", err)
+	}
 	logger.Info("Database client initialized successfully")
 
 	// Initialize time-based scheduler with Redis integration via HTTP API
@@ -78,6 +84,21 @@ func main() {
 		}
 	}()
 
+	// Start the typed gRPC API when a port is configured
+	var rpcServer *rpcserver.Server
+	if port := config.GetSchedulerGRPCPort(); port != "" {
+		auth := rpcserver.NewServiceAuthConfig(rpcpkg.ServiceTimeScheduler, config.GetServiceTokenPublicKeys(), schedulerrpc.MethodPolicy)
+		if config.IsDevMode() && !config.GetRPCTLSConfig().Enabled() && len(config.GetServiceTokenPublicKeys()) == 0 {
+			logger.Warn("gRPC server accepts unauthenticated calls - no service token keys or RPC TLS certificate in dev mode")
+			auth = nil
+		}
+		rpcServer, err = schedulerrpc.StartRPCServer(ctx, logger, timeScheduler, port, config.GetRPCTLSConfig(), auth)
+		if err != nil {
+			logger.Fatal("Failed to start gRPC server", "error", err)
+		}
+		logger.Info("gRPC server for time scheduler API started", "port", port)
+	}
+
 	// Log comprehensive service status
 	serviceStatus := map[string]interface{}{
 		"manager_id":            managerID,
@@ -100,10 +121,10 @@ func main() {
 
 	<-shutdown
 
-	performGracefulShutdown(cancel, srv, timeScheduler, dbClient, logger)
+	performGracefulShutdown(cancel, srv, rpcServer, timeScheduler, dbClient, logger)
 }
 
-func performGracefulShutdown(cancel context.CancelFunc, srv *api.Server, timeScheduler *scheduler.TimeBasedScheduler, dbClient *dbserver.DBServerClient, logger logging.Logger) {
+func performGracefulShutdown(cancel context.CancelFunc, srv *api.Server, rpcServer *rpcserver.Server, timeScheduler *scheduler.TimeBasedScheduler, dbClient *dbserver.DBServerClient, logger logging.Logger) {
 	shutdownStart := time.Now()
 	logger.Info("Initiating graceful shutdown...")
 
@@ -124,6 +145,11 @@ func performGracefulShutdown(cancel context.CancelFunc, srv *api.Server, timeSch
 	if err := srv.Stop(shutdownCtx); err != nil {
 		logger.Error("Server forced to shutdown", "error", err)
 	}
+	if rpcServer != nil {
+		if err := rpcServer.Stop(shutdownCtx); err != nil {
+			logger.Error("gRPC server forced to shutdown", "error", err)
+		}
+	}
 
 	shutdownDuration := time.Since(shutdownStart)
 
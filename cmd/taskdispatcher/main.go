@@ -16,6 +16,8 @@ import (
 	"github.com/trigg3rX/triggerx-backend/pkg/client/aggregator"
 	"github.com/trigg3rX/triggerx-backend/pkg/client/redis"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 	rpcserver "github.com/trigg3rX/triggerx-backend/pkg/rpc/server"
 )
 
@@ -99,6 +101,7 @@ func main() {
 		healthClient,
 		config.GetTaskDispatcherSigningKey(),
 		config.GetTaskDispatcherSigningAddress(),
+		config.GetSignatureFormat(),
 	)
 	if err != nil {
 		logger.Fatal("Failed to initialize TaskDispatcher", "error", err)
@@ -111,13 +114,28 @@ func main() {
 		Version: "1.0.0",
 		Address: "0.0.0.0",
 		Port:    config.GetTaskDispatcherRPCPort(),
+		TLS:     config.GetRPCTLSConfig(),
+	}
+	// Only the schedulers may submit tasks, authenticated by their client certificate or service token
+	serverConfig.Auth = rpcserver.NewServiceAuthConfig(rpcpkg.ServiceTaskDispatcher, config.GetServiceTokenPublicKeys(), rpc.MethodPolicy)
+	if config.IsDevMode() && !serverConfig.TLS.Enabled() && len(config.GetServiceTokenPublicKeys()) == 0 {
+		logger.Warn("RPC server accepts unauthenticated calls - no service token keys or RPC TLS certificate in dev mode")
+		serverConfig.Auth = nil
+	}
+	if rps, burst := config.GetRPCRateLimit(); rps > 0 {
+		serverConfig.RateLimit = &rpcserver.RateLimitConfig{
+			RequestsPerSecond: float64(rps),
+			Burst:             burst,
+		}
 	}
 	srv := rpcserver.NewServer(serverConfig, logger)
 	srv.AddInterceptor(rpcserver.LoggingInterceptor(logger))
+	srv.AddStreamInterceptor(rpcserver.LoggingStreamInterceptor(logger))
 
 	// Create and register the generic RPC handler
 	handler := rpc.NewTaskDispatcherHandler(logger, dispatcher)
 	srv.RegisterHandler("TaskDispatcher", handler)
+	rpcproto.RegisterTaskDispatcherServiceServer(srv, rpc.NewTaskDispatcherService(logger, dispatcher))
 
 	// 6. Start everything
 	ctx, cancel := context.WithCancel(context.Background())
@@ -52,7 +52,12 @@ func main() {
 	defer cancel()
 
 	// Initialize and start gRPC server
-	rpcServer, err := rpc.StartRPCServer(ctx, logger, taskManager, "0.0.0.0", config.GetTaskMonitorRPCPort())
+	auth := rpc.NewAuthConfig(config.GetServiceTokenPublicKeys())
+	if config.IsDevMode() && len(config.GetServiceTokenPublicKeys()) == 0 {
+		logger.Warn("gRPC server accepts unauthenticated calls - no service token keys in dev mode")
+		auth = nil
+	}
+	rpcServer, err := rpc.StartRPCServer(ctx, logger, taskManager, "0.0.0.0", config.GetTaskMonitorRPCPort(), auth)
 	if err != nil {
 		logger.Fatal("Failed to start gRPC server", "error", err)
 	}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKeyHashing(t *testing.T) {
+	key, id, err := GenerateApiKey()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, id))
+	assert.Len(t, id, apiKeyIDLength)
+
+	parsedID, ok := ApiKeyID(key)
+	require.True(t, ok)
+	assert.Equal(t, id, parsedID)
+
+	salt, err := NewApiKeySalt()
+	require.NoError(t, err)
+	hash := HashApiKey(key, salt)
+	assert.True(t, VerifyApiKey(key, salt, hash))
+	assert.False(t, VerifyApiKey(id+"-wrong", salt, hash))
+	assert.False(t, VerifyApiKey(key, []byte("other salt"), hash))
+	assert.False(t, VerifyApiKey(key, salt, nil))
+
+	// Keys made before hashing are identified by the first group of their UUID
+	legacyID, ok := ApiKeyID("TGRX-1a2b3c4d-0000-4000-8000-000000000000")
+	require.True(t, ok)
+	assert.Equal(t, "TGRX-1a2b3c4d", legacyID)
+	_, ok = ApiKeyID("ADMIN")
+	assert.False(t, ok)
+	_, ok = ApiKeyID("TGRX-1a2b3c4d")
+	assert.False(t, ok)
+}
+
+func TestApiKeyAllowsIP(t *testing.T) {
+	assert.True(t, ApiKeyAllowsIP(nil, "203.0.113.7"))
+
+	allowed := []string{"203.0.113.7", "10.0.0.0/8", "2001:db8::/32"}
+	assert.True(t, ApiKeyAllowsIP(allowed, "203.0.113.7"))
+	assert.True(t, ApiKeyAllowsIP(allowed, "10.20.30.40"))
+	assert.True(t, ApiKeyAllowsIP(allowed, "2001:db8::1"))
+	assert.False(t, ApiKeyAllowsIP(allowed, "203.0.113.8"))
+	assert.False(t, ApiKeyAllowsIP(allowed, "not an ip"))
+}
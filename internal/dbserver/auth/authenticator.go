@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Session is a session token issued on login
+type Session struct {
+	Token     string    `json:"token"`
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Authenticator logs users in with signed sign-in messages
+type Authenticator struct {
+	domain   string
+	nonces   *NonceStore
+	verifier *SignatureVerifier
+	sessions *Sessions
+}
+
+// NewAuthenticator creates an authenticator accepting messages made for domain
+func NewAuthenticator(domain string, nonces *NonceStore, verifier *SignatureVerifier, sessions *Sessions) *Authenticator {
+	return &Authenticator{
+		domain:   domain,
+		nonces:   nonces,
+		verifier: verifier,
+		sessions: sessions,
+	}
+}
+
+// Nonce issues a nonce for a sign-in message
+func (a *Authenticator) Nonce(ctx context.Context) (string, error) {
+	return a.nonces.Issue(ctx)
+}
+
+// Login verifies the signed sign-in message and issues a session of its address. The
+// nonce is used up before the signature is checked, so a message is only ever tried once.
+func (a *Authenticator) Login(ctx context.Context, text, signature string) (*Session, error) {
+	msg, err := ParseMessage(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := msg.Validate(a.domain, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := a.nonces.Consume(ctx, msg.Nonce); err != nil {
+		return nil, err
+	}
+	if err := a.verifier.Verify(ctx, msg.ChainID, msg.Address, text, signature); err != nil {
+		return nil, err
+	}
+
+	// A session never outlives the message it was issued for
+	var notAfter time.Time
+	if msg.ExpirationTime != nil {
+		notAfter = *msg.ExpirationTime
+	}
+	token, expiresAt, err := a.sessions.Issue(msg.Address, msg.ChainID, notAfter)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{Token: token, Address: strings.ToLower(msg.Address), ExpiresAt: expiresAt}, nil
+}
@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNonceBackend keeps nonces in memory
+type fakeNonceBackend struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (f *fakeNonceBackend) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[key] = true
+	return nil
+}
+
+func (f *fakeNonceBackend) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.keys[keys[0]] {
+		return int64(0), nil
+	}
+	delete(f.keys, keys[0])
+	return int64(1), nil
+}
+
+// fakeChain answers isValidSignature for a contract wallet and getOwners for a Safe
+type fakeChain struct {
+	wallet       common.Address
+	walletSigner common.Address
+	safe         common.Address
+	owners       []common.Address
+	ownerCalls   int
+}
+
+func (f *fakeChain) Caller(ctx context.Context, chainID string) (bind.ContractCaller, error) {
+	if chainID != "84532" {
+		return nil, errors.New("unsupported chain")
+	}
+	return f, nil
+}
+
+func (f *fakeChain) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if contract == f.wallet || contract == f.safe {
+		return []byte{0x60, 0x80}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeChain) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	switch {
+	case *call.To == f.wallet && bytes.Equal(call.Data[:4], eip1271.Methods["isValidSignature"].ID):
+		args, err := eip1271.Methods["isValidSignature"].Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		hash, sig := args[0].([32]byte), args[1].([]byte)
+		// The wallet accepts signatures of its signer key
+		sig = append([]byte(nil), sig...)
+		sig[64] -= 27
+		pub, err := crypto.SigToPub(hash[:], sig)
+		if err != nil || crypto.PubkeyToAddress(*pub) != f.walletSigner {
+			return nil, errors.New("execution reverted")
+		}
+		return common.RightPadBytes(eip1271MagicValue, 32), nil
+	case *call.To == f.safe:
+		f.ownerCalls++
+		return safeContract.Methods["getOwners"].Outputs.Pack(f.owners)
+	default:
+		return nil, errors.New("execution reverted")
+	}
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, message string) string {
+	t.Helper()
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	require.NoError(t, err)
+	sig[64] += 27
+	return hexutil.Encode(sig)
+}
+
+func newTestAuthenticator(t *testing.T, chain *fakeChain) *Authenticator {
+	t.Helper()
+	sessions, err := NewSessions([]byte("0123456789abcdef0123456789abcdef"), "app.triggerx.network", 15*time.Minute)
+	require.NoError(t, err)
+	nonces := NewNonceStore(&fakeNonceBackend{keys: make(map[string]bool)}, time.Minute)
+	return NewAuthenticator("app.triggerx.network", nonces, NewSignatureVerifier(chain), sessions)
+}
+
+func loginMessage(t *testing.T, a *Authenticator, address common.Address) string {
+	t.Helper()
+	nonce, err := a.Nonce(context.Background())
+	require.NoError(t, err)
+	msg := testMessage(time.Now())
+	msg.Address = address.Hex()
+	msg.Nonce = nonce
+	return msg.String()
+}
+
+func TestAuthenticator_Login(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	a := newTestAuthenticator(t, &fakeChain{})
+
+	message := loginMessage(t, a, address)
+	session, err := a.Login(context.Background(), message, sign(t, key, message))
+	require.NoError(t, err)
+	assert.Equal(t, hexutil.Encode(address.Bytes()), session.Address)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), session.ExpiresAt, 5*time.Second)
+
+	claims, err := a.sessions.Verify(session.Token)
+	require.NoError(t, err)
+	assert.Equal(t, session.Address, claims.Address)
+	assert.Equal(t, "84532", claims.ChainID)
+
+	// The signed message cannot be replayed
+	_, err = a.Login(context.Background(), message, sign(t, key, message))
+	assert.ErrorIs(t, err, ErrNonceUsed)
+}
+
+func TestAuthenticator_RejectsOtherSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	a := newTestAuthenticator(t, &fakeChain{})
+
+	message := loginMessage(t, a, crypto.PubkeyToAddress(key.PublicKey))
+	_, err = a.Login(context.Background(), message, sign(t, other, message))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Unknown nonces are rejected
+	msg := testMessage(time.Now())
+	msg.Address = crypto.PubkeyToAddress(key.PublicKey).Hex()
+	_, err = a.Login(context.Background(), msg.String(), sign(t, key, msg.String()))
+	assert.ErrorIs(t, err, ErrNonceUsed)
+}
+
+func TestAuthenticator_LoginContractWallet(t *testing.T) {
+	signer, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chain := &fakeChain{
+		wallet:       common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		walletSigner: crypto.PubkeyToAddress(signer.PublicKey),
+	}
+	a := newTestAuthenticator(t, chain)
+
+	message := loginMessage(t, a, chain.wallet)
+	session, err := a.Login(context.Background(), message, sign(t, signer, message))
+	require.NoError(t, err)
+	assert.Equal(t, hexutil.Encode(chain.wallet.Bytes()), session.Address)
+
+	message = loginMessage(t, a, chain.wallet)
+	_, err = a.Login(context.Background(), message, sign(t, other, message))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSessions_Verify(t *testing.T) {
+	sessions, err := NewSessions([]byte("0123456789abcdef0123456789abcdef"), "app.triggerx.network", time.Minute)
+	require.NoError(t, err)
+	other, err := NewSessions([]byte("fedcba9876543210fedcba9876543210"), "app.triggerx.network", time.Minute)
+	require.NoError(t, err)
+
+	token, _, err := sessions.Issue("0xABC0000000000000000000000000000000000001", "1", time.Time{})
+	require.NoError(t, err)
+	claims, err := sessions.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "0xabc0000000000000000000000000000000000001", claims.Address)
+
+	_, err = other.Verify(token)
+	assert.Error(t, err)
+
+	expired, _, err := sessions.Issue("0xABC0000000000000000000000000000000000001", "1", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	_, err = sessions.Verify(expired)
+	assert.Error(t, err)
+
+	_, err = NewSessions([]byte("short"), "app.triggerx.network", time.Minute)
+	assert.Error(t, err)
+}
+
+func TestSafeOwners_IsOwner(t *testing.T) {
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	chain := &fakeChain{
+		safe:   common.HexToAddress("0x00000000000000000000000000000000000000cc"),
+		owners: []common.Address{owner, common.HexToAddress("0x00000000000000000000000000000000000000b2")},
+	}
+	safes := NewSafeOwners(chain, time.Minute)
+
+	isOwner, err := safes.IsOwner(context.Background(), "84532", chain.safe.Hex(), owner.Hex())
+	require.NoError(t, err)
+	assert.True(t, isOwner)
+
+	isOwner, err = safes.IsOwner(context.Background(), "84532", chain.safe.Hex(), "0x00000000000000000000000000000000000000b3")
+	require.NoError(t, err)
+	assert.False(t, isOwner)
+	// The owners are read from the chain once
+	assert.Equal(t, 1, chain.ownerCalls)
+
+	_, err = safes.IsOwner(context.Background(), "1", chain.safe.Hex(), owner.Hex())
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// CallerProvider returns a client for read-only contract calls on a chain
+type CallerProvider interface {
+	Caller(ctx context.Context, chainID string) (bind.ContractCaller, error)
+}
+
+// ChainClients dials one client per chain and keeps it for later calls
+type ChainClients struct {
+	rpcURL func(chainID string) string
+
+	mu      sync.Mutex
+	clients map[string]*ethclient.Client
+}
+
+// NewChainClients creates chain clients resolving each chain's RPC URL with rpcURL
+func NewChainClients(rpcURL func(chainID string) string) *ChainClients {
+	return &ChainClients{
+		rpcURL:  rpcURL,
+		clients: make(map[string]*ethclient.Client),
+	}
+}
+
+// Caller returns the client of the chain, dialing it on first use
+func (c *ChainClients) Caller(ctx context.Context, chainID string) (bind.ContractCaller, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.clients[chainID]; ok {
+		return client, nil
+	}
+	url := c.rpcURL(chainID)
+	if url == "" {
+		return nil, fmt.Errorf("unsupported chain: %s", chainID)
+	}
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to chain %s: %w", chainID, err)
+	}
+	c.clients[chainID] = client
+	return client, nil
+}
+
+// Close closes the clients of every chain
+func (c *ChainClients) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for chainID, client := range c.clients {
+		client.Close()
+		delete(c.clients, chainID)
+	}
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const nonceKeyPrefix = "siwe:nonce:"
+
+// consumeNonceScript deletes the nonce and reports whether it existed, so concurrent
+// logins with the same nonce cannot both succeed
+const consumeNonceScript = `return redis.call('DEL', KEYS[1])`
+
+// ErrNonceUsed is returned for nonces that were never issued, expired or already used
+var ErrNonceUsed = errors.New("nonce is unknown, expired or already used")
+
+// NonceBackend stores nonces with an expiry, the dbserver Redis client implements it
+type NonceBackend interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// NonceStore issues single-use sign-in nonces
+type NonceStore struct {
+	backend NonceBackend
+	ttl     time.Duration
+}
+
+// NewNonceStore creates a nonce store whose nonces expire after ttl
+func NewNonceStore(backend NonceBackend, ttl time.Duration) *NonceStore {
+	return &NonceStore{backend: backend, ttl: ttl}
+}
+
+// Issue returns a new nonce
+func (s *NonceStore) Issue(ctx context.Context) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(buf)
+	if err := s.backend.Set(ctx, nonceKeyPrefix+nonce, 1, s.ttl); err != nil {
+		return "", fmt.Errorf("failed to store nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// Consume uses up the nonce, failing if it cannot be used
+func (s *NonceStore) Consume(ctx context.Context, nonce string) error {
+	result, err := s.backend.Eval(ctx, consumeNonceScript, []string{nonceKeyPrefix + nonce})
+	if err != nil {
+		return fmt.Errorf("failed to consume nonce: %w", err)
+	}
+	if deleted, ok := result.(int64); !ok || deleted != 1 {
+		return ErrNonceUsed
+	}
+	return nil
+}
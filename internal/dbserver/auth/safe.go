@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const safeABI = `[{"type":"function","name":"getOwners","stateMutability":"view",
+	"inputs":[],"outputs":[{"name":"","type":"address[]"}]}]`
+
+var safeContract = mustParseABI(safeABI)
+
+// safeOwnersEntry caches the owners of a Safe on a chain
+type safeOwnersEntry struct {
+	owners    []common.Address
+	fetchedAt time.Time
+}
+
+// SafeOwners reads the owners of Safe wallets from the chain, caching them for a while so
+// each request of a Safe owner does not call the chain
+type SafeOwners struct {
+	callers CallerProvider
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	cache map[string]safeOwnersEntry
+}
+
+// NewSafeOwners creates a reader of Safe owners caching them for ttl
+func NewSafeOwners(callers CallerProvider, ttl time.Duration) *SafeOwners {
+	return &SafeOwners{
+		callers: callers,
+		ttl:     ttl,
+		cache:   make(map[string]safeOwnersEntry),
+	}
+}
+
+// Owners returns the owners of the Safe on the chain
+func (s *SafeOwners) Owners(ctx context.Context, chainID, safeAddress string) ([]common.Address, error) {
+	if !common.IsHexAddress(safeAddress) {
+		return nil, fmt.Errorf("invalid safe address: %s", safeAddress)
+	}
+	key := chainID + "/" + strings.ToLower(safeAddress)
+
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < s.ttl {
+		return entry.owners, nil
+	}
+
+	caller, err := s.callers.Caller(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := safeContract.Pack("getOwners")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack getOwners call: %w", err)
+	}
+	safe := common.HexToAddress(safeAddress)
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &safe, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owners of safe %s: %w", safeAddress, err)
+	}
+	var owners []common.Address
+	if err := safeContract.UnpackIntoInterface(&owners, "getOwners", result); err != nil {
+		return nil, fmt.Errorf("failed to unpack owners of safe %s: %w", safeAddress, err)
+	}
+
+	s.mu.Lock()
+	s.cache[key] = safeOwnersEntry{owners: owners, fetchedAt: time.Now()}
+	s.mu.Unlock()
+	return owners, nil
+}
+
+// IsOwner reports whether address is an owner of the Safe on the chain
+func (s *SafeOwners) IsOwner(ctx context.Context, chainID, safeAddress, address string) (bool, error) {
+	owners, err := s.Owners(ctx, chainID, safeAddress)
+	if err != nil {
+		return false, err
+	}
+	target := common.HexToAddress(address)
+	for _, owner := range owners {
+		if owner == target {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SessionIssuer is the issuer of session tokens
+const SessionIssuer = "triggerx-dbserver"
+
+// SessionClaims are the claims of a session token, the subject is the wallet address
+type SessionClaims struct {
+	Address string `json:"address"`
+	ChainID string `json:"chain_id"`
+	jwt.RegisteredClaims
+}
+
+// Sessions issues and verifies HMAC signed session tokens
+type Sessions struct {
+	secret   []byte
+	audience string
+	ttl      time.Duration
+}
+
+// NewSessions creates session tokens for audience, valid for ttl
+func NewSessions(secret []byte, audience string, ttl time.Duration) (*Sessions, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("session secret must be at least 32 bytes")
+	}
+	return &Sessions{secret: secret, audience: audience, ttl: ttl}, nil
+}
+
+// Issue returns a session token of the address and its expiry, which is at most notAfter
+// when it is set
+func (s *Sessions) Issue(address, chainID string, notAfter time.Time) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.ttl)
+	if !notAfter.IsZero() && notAfter.Before(expiresAt) {
+		expiresAt = notAfter
+	}
+	claims := &SessionClaims{
+		Address: strings.ToLower(address),
+		ChainID: chainID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    SessionIssuer,
+			Subject:   strings.ToLower(address),
+			Audience:  []string{s.audience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign session token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// Verify checks the token's signature, expiry, issuer and audience and returns its claims
+func (s *Sessions) Verify(tokenString string) (*SessionClaims, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(SessionIssuer),
+		jwt.WithAudience(s.audience),
+		jwt.WithExpirationRequired(),
+	)
+	claims := &SessionClaims{}
+	if _, err := parser.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}); err != nil {
+		return nil, fmt.Errorf("invalid session token: %w", err)
+	}
+	if claims.Address == "" {
+		return nil, errors.New("missing address in session token")
+	}
+	return claims, nil
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
+)
+
+// ErrInvalidSignature is returned when a signature was not made by the claimed address
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// eip1271MagicValue is returned by isValidSignature for a valid contract signature
+var eip1271MagicValue = []byte{0x16, 0x26, 0xba, 0x7e}
+
+const eip1271ABI = `[{"type":"function","name":"isValidSignature","stateMutability":"view",
+	"inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],
+	"outputs":[{"name":"magicValue","type":"bytes4"}]}]`
+
+var eip1271 = mustParseABI(eip1271ABI)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// SignatureVerifier verifies personal_sign signatures of wallets, checking signatures of
+// smart contract wallets with EIP-1271
+type SignatureVerifier struct {
+	callers CallerProvider
+}
+
+// NewSignatureVerifier creates a verifier calling contract wallets through callers, which
+// may be nil to accept only externally owned accounts
+func NewSignatureVerifier(callers CallerProvider) *SignatureVerifier {
+	return &SignatureVerifier{callers: callers}
+}
+
+// Verify checks that address signed message on the chain
+func (v *SignatureVerifier) Verify(ctx context.Context, chainID, address, message, signature string) error {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	// An externally owned account signs with its key
+	if len(sig) == 65 {
+		if ok, err := cryptography.VerifySignature(message, signature, address); err == nil && ok {
+			return nil
+		}
+	}
+
+	if v.callers == nil {
+		return ErrInvalidSignature
+	}
+	caller, err := v.callers.Caller(ctx, chainID)
+	if err != nil {
+		return err
+	}
+
+	// A contract wallet validates the signature itself
+	contract := common.HexToAddress(address)
+	code, err := caller.CodeAt(ctx, contract, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get code of %s: %w", address, err)
+	}
+	if len(code) == 0 {
+		return ErrInvalidSignature
+	}
+
+	data, err := eip1271.Pack("isValidSignature", [32]byte(accounts.TextHash([]byte(message))), sig)
+	if err != nil {
+		return fmt.Errorf("failed to pack isValidSignature call: %w", err)
+	}
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		// Contracts revert on signatures they reject
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if len(result) < len(eip1271MagicValue) || !bytes.Equal(result[:len(eip1271MagicValue)], eip1271MagicValue) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
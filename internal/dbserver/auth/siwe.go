@@ -0,0 +1,183 @@
+// Package auth authenticates dbserver users by their wallet: users sign a Sign-In With
+// Ethereum (EIP-4361) message and receive a short-lived session token binding their
+// requests to their address.
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+	siweVersion      = "1"
+
+	// maxClockSkew tolerates clients whose clock is slightly ahead of the server
+	maxClockSkew = time.Minute
+)
+
+var nonceRegex = regexp.MustCompile(`^[a-zA-Z0-9]{8,}$`)
+
+// ErrInvalidMessage is returned for malformed, expired or foreign sign-in messages
+var ErrInvalidMessage = errors.New("invalid sign-in message")
+
+// Message is a Sign-In With Ethereum message
+type Message struct {
+	Domain         string
+	Address        string
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        string
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// ParseMessage parses the text of a sign-in message
+func ParseMessage(text string) (*Message, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(lines) < 2 || !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidMessage)
+	}
+
+	msg := &Message{
+		Domain:  strings.TrimSuffix(lines[0], siweHeaderSuffix),
+		Address: strings.TrimSpace(lines[1]),
+	}
+
+	// The statement is optional and surrounded by empty lines
+	i := 2
+	for i < len(lines) && lines[i] == "" {
+		i++
+	}
+	if i < len(lines) && !strings.HasPrefix(lines[i], "URI: ") {
+		msg.Statement = lines[i]
+		i++
+	}
+
+	var issuedAt string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+		if line == "Resources:" {
+			for i++; i < len(lines) && strings.HasPrefix(lines[i], "- "); i++ {
+				msg.Resources = append(msg.Resources, strings.TrimPrefix(lines[i], "- "))
+			}
+			i--
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected line %q", ErrInvalidMessage, line)
+		}
+		switch key {
+		case "URI":
+			msg.URI = value
+		case "Version":
+			msg.Version = value
+		case "Chain ID":
+			msg.ChainID = value
+		case "Nonce":
+			msg.Nonce = value
+		case "Issued At":
+			issuedAt = value
+		case "Expiration Time":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: expiration time: %v", ErrInvalidMessage, err)
+			}
+			msg.ExpirationTime = &t
+		case "Not Before":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: not before: %v", ErrInvalidMessage, err)
+			}
+			msg.NotBefore = &t
+		case "Request ID":
+			msg.RequestID = value
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidMessage, key)
+		}
+	}
+
+	if msg.URI == "" || msg.Version == "" || msg.ChainID == "" || msg.Nonce == "" || issuedAt == "" {
+		return nil, fmt.Errorf("%w: missing required field", ErrInvalidMessage)
+	}
+	t, err := time.Parse(time.RFC3339, issuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: issued at: %v", ErrInvalidMessage, err)
+	}
+	msg.IssuedAt = t
+
+	return msg, nil
+}
+
+// String formats the message as the text the wallet signs
+func (m *Message) String() string {
+	var b strings.Builder
+	b.WriteString(m.Domain + siweHeaderSuffix + "\n")
+	b.WriteString(m.Address + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString("URI: " + m.URI + "\n")
+	b.WriteString("Version: " + m.Version + "\n")
+	b.WriteString("Chain ID: " + m.ChainID + "\n")
+	b.WriteString("Nonce: " + m.Nonce + "\n")
+	b.WriteString("Issued At: " + m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		b.WriteString("\nExpiration Time: " + m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		b.WriteString("\nNot Before: " + m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		b.WriteString("\nRequest ID: " + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			b.WriteString("\n- " + resource)
+		}
+	}
+	return b.String()
+}
+
+// Validate checks the message was made for domain and is valid at now
+func (m *Message) Validate(domain string, now time.Time) error {
+	if !strings.EqualFold(m.Domain, domain) {
+		return fmt.Errorf("%w: domain %s does not match %s", ErrInvalidMessage, m.Domain, domain)
+	}
+	if !common.IsHexAddress(m.Address) {
+		return fmt.Errorf("%w: invalid address %s", ErrInvalidMessage, m.Address)
+	}
+	if m.Version != siweVersion {
+		return fmt.Errorf("%w: unsupported version %s", ErrInvalidMessage, m.Version)
+	}
+	if !nonceRegex.MatchString(m.Nonce) {
+		return fmt.Errorf("%w: invalid nonce", ErrInvalidMessage)
+	}
+	if m.IssuedAt.After(now.Add(maxClockSkew)) {
+		return fmt.Errorf("%w: issued in the future", ErrInvalidMessage)
+	}
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return fmt.Errorf("%w: expired", ErrInvalidMessage)
+	}
+	if m.NotBefore != nil && now.Add(maxClockSkew).Before(*m.NotBefore) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidMessage)
+	}
+	return nil
+}
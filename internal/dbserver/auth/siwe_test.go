@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessage(now time.Time) *Message {
+	expires := now.Add(10 * time.Minute)
+	return &Message{
+		Domain:         "app.triggerx.network",
+		Address:        "0x71C7656EC7ab88b098defB751B7401B5f6d8976F",
+		Statement:      "Sign in to TriggerX",
+		URI:            "https://app.triggerx.network",
+		Version:        "1",
+		ChainID:        "84532",
+		Nonce:          "a1b2c3d4e5f60718",
+		IssuedAt:       now.Truncate(time.Second),
+		ExpirationTime: &expires,
+		Resources:      []string{"https://app.triggerx.network/jobs"},
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	msg := testMessage(time.Now().UTC())
+
+	parsed, err := ParseMessage(msg.String())
+	require.NoError(t, err)
+	assert.Equal(t, msg.Domain, parsed.Domain)
+	assert.Equal(t, msg.Address, parsed.Address)
+	assert.Equal(t, msg.Statement, parsed.Statement)
+	assert.Equal(t, msg.ChainID, parsed.ChainID)
+	assert.Equal(t, msg.Nonce, parsed.Nonce)
+	assert.True(t, msg.IssuedAt.Equal(parsed.IssuedAt))
+	require.NotNil(t, parsed.ExpirationTime)
+	assert.True(t, msg.ExpirationTime.Truncate(time.Second).Equal(*parsed.ExpirationTime))
+	assert.Equal(t, msg.Resources, parsed.Resources)
+	assert.Equal(t, msg.String(), parsed.String())
+
+	// The statement is optional
+	msg.Statement = ""
+	parsed, err = ParseMessage(msg.String())
+	require.NoError(t, err)
+	assert.Empty(t, parsed.Statement)
+	assert.Equal(t, msg.URI, parsed.URI)
+}
+
+func TestParseMessage_Invalid(t *testing.T) {
+	_, err := ParseMessage("hello")
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, err = ParseMessage("app.triggerx.network wants you to sign in with your Ethereum account:\n0x71C7656EC7ab88b098defB751B7401B5f6d8976F\n\nURI: https://app.triggerx.network")
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+func TestMessage_Validate(t *testing.T) {
+	now := time.Now()
+
+	assert.NoError(t, testMessage(now).Validate("app.triggerx.network", now))
+	assert.ErrorIs(t, testMessage(now).Validate("evil.example", now), ErrInvalidMessage)
+	assert.ErrorIs(t, testMessage(now).Validate("app.triggerx.network", now.Add(time.Hour)), ErrInvalidMessage)
+
+	msg := testMessage(now)
+	msg.Nonce = "short"
+	assert.ErrorIs(t, msg.Validate("app.triggerx.network", now), ErrInvalidMessage)
+
+	msg = testMessage(now)
+	msg.Address = "0x1234"
+	assert.ErrorIs(t, msg.Validate("app.triggerx.network", now), ErrInvalidMessage)
+
+	msg = testMessage(now.Add(time.Hour))
+	assert.ErrorIs(t, msg.Validate("app.triggerx.network", now), ErrInvalidMessage)
+}
@@ -0,0 +1,419 @@
+// Package backfill fills the dbserver lookup tables from the base tables, for rows written
+// before the repositories started writing the lookups themselves, and hashes API keys stored
+// raw. Every write is an upsert, or a lightweight transaction for address claims and hashed
+// keys, so a backfill can be re-run safely.
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// Lookup tables the backfill fills
+const (
+	JobsByUser        = "jobs_by_user"
+	JobsBySafeAddress = "jobs_by_safe_address"
+	TasksByJob        = "tasks_by_job"
+	TasksByDay        = "tasks_by_day"
+	UsersByAddress    = "users_by_address"
+	KeepersByAddress  = "keepers_by_address"
+	ApiKeysByOwner    = "apikeys_by_owner"
+	ActiveJobsByType  = "active_jobs_by_type"
+	TimeJobsByMinute  = "time_jobs_by_minute"
+	// Not a lookup table, moves API keys stored raw to their ID and a hash of the key
+	ApiKeys = "apikeys"
+)
+
+// Tables lists every lookup table, in the order they are filled
+var Tables = []string{JobsByUser, JobsBySafeAddress, TasksByJob, TasksByDay, UsersByAddress, KeepersByAddress, ApiKeysByOwner, ActiveJobsByType, TimeJobsByMinute, ApiKeys}
+
+// Result counts the rows a table's backfill scanned, wrote and could not write because the
+// address, or the ID of an API key, is claimed by another row
+type Result struct {
+	Table     string
+	Scanned   int
+	Written   int
+	Conflicts int
+}
+
+// Backfiller pages through base tables and writes their lookup rows
+type Backfiller struct {
+	session  database.Sessioner
+	pageSize int
+	dryRun   bool
+	logger   logging.Logger
+}
+
+// NewBackfiller creates a backfiller reading pageSize rows at a time. A dry run only counts
+// the rows it would write.
+func NewBackfiller(session database.Sessioner, pageSize int, dryRun bool, logger logging.Logger) *Backfiller {
+	return &Backfiller{
+		session:  session,
+		pageSize: pageSize,
+		dryRun:   dryRun,
+		logger:   logger,
+	}
+}
+
+// Run fills the given lookup tables
+func (b *Backfiller) Run(ctx context.Context, tables []string) ([]Result, error) {
+	fills := map[string]func(context.Context) (Result, error){
+		JobsByUser:        b.fillJobsByUser,
+		JobsBySafeAddress: b.fillJobsBySafeAddress,
+		TasksByJob:        b.fillTasksByJob,
+		TasksByDay:        b.fillTasksByDay,
+		UsersByAddress:    b.fillUsersByAddress,
+		KeepersByAddress:  b.fillKeepersByAddress,
+		ApiKeysByOwner:    b.fillApiKeysByOwner,
+		ActiveJobsByType:  b.fillActiveJobsByType,
+		TimeJobsByMinute:  b.fillTimeJobsByMinute,
+		ApiKeys:           b.hashApiKeys,
+	}
+
+	var results []Result
+	for _, table := range tables {
+		fill, ok := fills[table]
+		if !ok {
+			return results, fmt.Errorf("unknown lookup table %s", table)
+		}
+		start := time.Now()
+		result, err := fill(ctx)
+		if err != nil {
+			return results, fmt.Errorf("failed to backfill %s: %w", table, err)
+		}
+		b.logger.Info("Backfilled lookup table",
+			"table", table,
+			"scanned", result.Scanned,
+			"written", result.Written,
+			"conflicts", result.Conflicts,
+			"dry_run", b.dryRun,
+			"duration", time.Since(start))
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (b *Backfiller) fillJobsByUser(ctx context.Context) (Result, error) {
+	result := Result{Table: JobsByUser}
+	iter := b.scan(ctx, `SELECT job_id, user_id, created_chain_id, created_at FROM triggerx.job_data`)
+
+	var userID int64
+	var createdChainID string
+	var createdAt time.Time
+	for {
+		var jobID *big.Int
+		if !iter.Scan(&jobID, &userID, &createdChainID, &createdAt) {
+			break
+		}
+		result.Scanned++
+		if err := b.write(ctx, `INSERT INTO triggerx.jobs_by_user (user_id, created_chain_id, created_at, job_id) VALUES (?, ?, ?, ?)`,
+			userID, createdChainID, clusteringTime(createdAt), jobID); err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		result.Written++
+	}
+	return result, iter.Close()
+}
+
+func (b *Backfiller) fillJobsBySafeAddress(ctx context.Context) (Result, error) {
+	result := Result{Table: JobsBySafeAddress}
+	iter := b.scan(ctx, `SELECT job_id, safe_address, created_at FROM triggerx.job_data`)
+
+	var safeAddress string
+	var createdAt time.Time
+	for {
+		var jobID *big.Int
+		if !iter.Scan(&jobID, &safeAddress, &createdAt) {
+			break
+		}
+		result.Scanned++
+		if safeAddress == "" {
+			continue
+		}
+		if err := b.write(ctx, `INSERT INTO triggerx.jobs_by_safe_address (safe_address, created_at, job_id) VALUES (?, ?, ?)`,
+			safeAddress, clusteringTime(createdAt), jobID); err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		result.Written++
+	}
+	return result, iter.Close()
+}
+
+func (b *Backfiller) fillTasksByJob(ctx context.Context) (Result, error) {
+	result := Result{Table: TasksByJob}
+	iter := b.scan(ctx, `SELECT task_id, job_id, created_at FROM triggerx.task_data`)
+
+	var taskID int64
+	var createdAt time.Time
+	for {
+		var jobID *big.Int
+		if !iter.Scan(&taskID, &jobID, &createdAt) {
+			break
+		}
+		result.Scanned++
+		if jobID == nil {
+			continue
+		}
+		if err := b.write(ctx, `INSERT INTO triggerx.tasks_by_job (job_id, created_at, task_id) VALUES (?, ?, ?)`,
+			jobID, clusteringTime(createdAt), taskID); err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		result.Written++
+	}
+	return result, iter.Close()
+}
+
+func (b *Backfiller) fillTasksByDay(ctx context.Context) (Result, error) {
+	result := Result{Table: TasksByDay}
+	iter := b.scan(ctx, `SELECT task_id, created_at FROM triggerx.task_data`)
+
+	var taskID int64
+	var createdAt time.Time
+	for iter.Scan(&taskID, &createdAt) {
+		result.Scanned++
+		createdAt = clusteringTime(createdAt)
+		year, month, day := createdAt.UTC().Date()
+		if err := b.write(ctx, `INSERT INTO triggerx.tasks_by_day (day, created_at, task_id) VALUES (?, ?, ?)`,
+			time.Date(year, month, day, 0, 0, 0, 0, time.UTC), createdAt, taskID); err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		result.Written++
+	}
+	return result, iter.Close()
+}
+
+func (b *Backfiller) fillUsersByAddress(ctx context.Context) (Result, error) {
+	return b.fillAddressClaims(ctx, UsersByAddress,
+		`SELECT user_id, user_address FROM triggerx.user_data`,
+		`INSERT INTO triggerx.users_by_address (user_address, user_id) VALUES (?, ?) IF NOT EXISTS`)
+}
+
+func (b *Backfiller) fillKeepersByAddress(ctx context.Context) (Result, error) {
+	return b.fillAddressClaims(ctx, KeepersByAddress,
+		`SELECT keeper_id, keeper_address FROM triggerx.keeper_data`,
+		`INSERT INTO triggerx.keepers_by_address (keeper_address, keeper_id) VALUES (?, ?) IF NOT EXISTS`)
+}
+
+// fillAddressClaims claims the address of every row with the same lightweight transaction
+// the repositories use. An address already claimed by another ID is a duplicate registration
+// and is reported, not overwritten.
+func (b *Backfiller) fillAddressClaims(ctx context.Context, table, source, claim string) (Result, error) {
+	result := Result{Table: table}
+	iter := b.scan(ctx, source)
+
+	var id int64
+	var address string
+	for iter.Scan(&id, &address) {
+		result.Scanned++
+		if address == "" {
+			continue
+		}
+		if b.dryRun {
+			result.Written++
+			continue
+		}
+
+		var existingAddress string
+		var existingID int64
+		applied, err := b.session.Query(claim, address, id).WithContext(ctx).ScanCAS(&existingAddress, &existingID)
+		if err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		if !applied && existingID != id {
+			b.logger.Warn("Address claimed by another row", "table", table, "address", address, "id", id, "claimed_by", existingID)
+			result.Conflicts++
+			continue
+		}
+		result.Written++
+	}
+	return result, iter.Close()
+}
+
+func (b *Backfiller) fillApiKeysByOwner(ctx context.Context) (Result, error) {
+	result := Result{Table: ApiKeysByOwner}
+	iter := b.scan(ctx, `SELECT key, owner, created_at FROM triggerx.apikeys`)
+
+	var key, owner string
+	var createdAt time.Time
+	for iter.Scan(&key, &owner, &createdAt) {
+		result.Scanned++
+		if owner == "" {
+			continue
+		}
+		if err := b.write(ctx, `INSERT INTO triggerx.apikeys_by_owner (owner, key, created_at) VALUES (?, ?, ?)`,
+			owner, key, createdAt); err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		result.Written++
+	}
+	return result, iter.Close()
+}
+
+// fillActiveJobsByType adds the active jobs of each job type table
+func (b *Backfiller) fillActiveJobsByType(ctx context.Context) (Result, error) {
+	result := Result{Table: ActiveJobsByType}
+	for jobType, table := range map[string]string{
+		"time":      "time_job_data",
+		"event":     "event_job_data",
+		"condition": "condition_job_data",
+	} {
+		iter := b.scan(ctx, `SELECT job_id, is_active FROM triggerx.`+table)
+
+		var isActive bool
+		for {
+			var jobID *big.Int
+			if !iter.Scan(&jobID, &isActive) {
+				break
+			}
+			result.Scanned++
+			if !isActive {
+				continue
+			}
+			if err := b.write(ctx, `INSERT INTO triggerx.active_jobs_by_type (job_type, job_id) VALUES (?, ?)`,
+				jobType, jobID); err != nil {
+				_ = iter.Close()
+				return result, err
+			}
+			result.Written++
+		}
+		if err := iter.Close(); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// fillTimeJobsByMinute adds the next execution of time jobs due from now on, with the TTL
+// the repository writes
+func (b *Backfiller) fillTimeJobsByMinute(ctx context.Context) (Result, error) {
+	result := Result{Table: TimeJobsByMinute}
+	iter := b.scan(ctx, `SELECT job_id, next_execution_timestamp, is_active FROM triggerx.time_job_data`)
+
+	var nextExecution time.Time
+	var isActive bool
+	for {
+		var jobID *big.Int
+		if !iter.Scan(&jobID, &nextExecution, &isActive) {
+			break
+		}
+		result.Scanned++
+		if !isActive || nextExecution.Before(time.Now()) {
+			continue
+		}
+		ttl := time.Until(nextExecution) + time.Hour
+		if err := b.write(ctx, `INSERT INTO triggerx.time_jobs_by_minute (minute, next_execution_timestamp, job_id) VALUES (?, ?, ?) USING TTL ?`,
+			nextExecution.UTC().Truncate(time.Minute), nextExecution, jobID, int(ttl.Seconds())); err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		result.Written++
+	}
+	return result, iter.Close()
+}
+
+// hashApiKeys moves every API key stored raw to the row of its ID, with a salted hash of the
+// key, and swaps its owner lookup. Clients keep using the same key. The row of the ID is
+// claimed with a lightweight transaction, keys whose ID is taken are reported and keep
+// working raw until they are rotated.
+func (b *Backfiller) hashApiKeys(ctx context.Context) (Result, error) {
+	result := Result{Table: ApiKeys}
+	iter := b.scan(ctx, `SELECT key, owner, is_active, rate_limit, tier, key_hash, scopes, expires_at, allowed_ips, success_count, failed_count, last_used, last_used_ip, created_at FROM triggerx.apikeys`)
+
+	for {
+		var key, owner, tier, lastUsedIP string
+		var isActive bool
+		var rateLimit int
+		var keyHash []byte
+		var scopes, allowedIPs []string
+		var successCount, failedCount int64
+		var expiresAt, lastUsed, createdAt time.Time
+		if !iter.Scan(&key, &owner, &isActive, &rateLimit, &tier, &keyHash, &scopes, &expiresAt, &allowedIPs,
+			&successCount, &failedCount, &lastUsed, &lastUsedIP, &createdAt) {
+			break
+		}
+		result.Scanned++
+		if len(keyHash) > 0 {
+			continue
+		}
+		id, ok := auth.ApiKeyID(key)
+		if !ok {
+			b.logger.Warn("API key has no ID, rotate it to hash it", "owner", owner)
+			result.Conflicts++
+			continue
+		}
+		if b.dryRun {
+			result.Written++
+			continue
+		}
+
+		salt, err := auth.NewApiKeySalt()
+		if err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		existing := map[string]interface{}{}
+		applied, err := b.session.Query(`INSERT INTO triggerx.apikeys (key, owner, is_active, rate_limit, tier, key_hash, key_salt, scopes, expires_at, allowed_ips, success_count, failed_count, last_used, last_used_ip, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`,
+			id, owner, isActive, rateLimit, tier, auth.HashApiKey(key, salt), salt, scopes, expiresAt, allowedIPs,
+			successCount, failedCount, lastUsed, lastUsedIP, createdAt).WithContext(ctx).MapScanCAS(existing)
+		if err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		// A row of the key itself is left by a backfill stopped before it removed the raw row
+		existingHash, _ := existing["key_hash"].([]byte)
+		existingSalt, _ := existing["key_salt"].([]byte)
+		if !applied && !auth.VerifyApiKey(key, existingSalt, existingHash) {
+			b.logger.Warn("API key ID claimed by another key, rotate the key to hash it", "owner", owner, "key_id", id)
+			result.Conflicts++
+			continue
+		}
+
+		batch := b.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		if owner != "" {
+			batch.Query(`INSERT INTO triggerx.apikeys_by_owner (owner, key, created_at) VALUES (?, ?, ?)`, owner, id, createdAt)
+			batch.Query(`DELETE FROM triggerx.apikeys_by_owner WHERE owner = ? AND key = ?`, owner, key)
+		}
+		batch.Query(`DELETE FROM triggerx.apikeys WHERE key = ?`, key)
+		if err := b.session.ExecuteBatch(batch); err != nil {
+			_ = iter.Close()
+			return result, err
+		}
+		result.Written++
+	}
+	return result, iter.Close()
+}
+
+// scan pages through a base table
+func (b *Backfiller) scan(ctx context.Context, query string) *gocql.Iter {
+	return b.session.Query(query).WithContext(ctx).PageSize(b.pageSize).Iter()
+}
+
+// write upserts a lookup row, unless this is a dry run
+func (b *Backfiller) write(ctx context.Context, query string, values ...interface{}) error {
+	if b.dryRun {
+		return nil
+	}
+	return b.session.Query(query, values...).WithContext(ctx).Exec()
+}
+
+// clusteringTime returns t, or the epoch for rows written before created_at existed, as a
+// clustering column cannot be empty
+func clusteringTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.UnixMilli(0)
+	}
+	return t
+}
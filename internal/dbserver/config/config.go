@@ -1,12 +1,17 @@
 package config
 
 import (
+	"crypto/ed25519"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/env"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
 )
 
 type Config struct {
@@ -23,6 +28,9 @@ type Config struct {
 	databaseHostAddress string
 	databaseHostPort    string
 
+	// Apply pending schema migrations on startup
+	autoMigrate bool
+
 	// Email User and Password
 	emailUser     string
 	emailPassword string
@@ -42,6 +50,25 @@ type Config struct {
 
 	// Polling Look Ahead
 	timeSchedulerPollingLookAhead int
+
+	// Sign-In With Ethereum domain and session tokens
+	siweDomain         string
+	siweNonceTTL       time.Duration
+	sessionTokenSecret string
+	sessionTokenTTL    time.Duration
+	safeOwnersCacheTTL time.Duration
+
+	// Internal endpoints: keys of the service tokens accepted, and the mutual TLS listener
+	// services may call them on instead
+	serviceTokenPublicKeys []ed25519.PublicKey
+	internalPort           string
+	rpcTLS                 mtls.Config
+
+	// Token bucket of each client IP on routes without an API key
+	ipRateLimitPerMinute int
+	ipRateLimitBurst     int
+	// Proxies whose X-Forwarded-For is trusted for the client IP, none when empty
+	trustedProxies []string
 }
 
 var cfg Config
@@ -50,6 +77,12 @@ func Init() error {
 	if err := godotenv.Load(); err != nil {
 		return fmt.Errorf("error loading .env file: %w", err)
 	}
+	return Load()
+}
+
+// Load reads the configuration from the environment only, for processes that set it
+// themselves instead of shipping a .env file.
+func Load() error {
 	cfg = Config{
 		timeSchedulerRPCUrl:           env.GetEnvString("TIME_SCHEDULER_RPC_URL", "http://localhost:9005"),
 		conditionSchedulerRPCUrl:      env.GetEnvString("CONDITION_SCHEDULER_RPC_URL", "http://localhost:9006"),
@@ -67,7 +100,31 @@ func Init() error {
 		otTempoEndpoint:               env.GetEnvString("TEMPO_OTLP_ENDPOINT", "localhost:4318"),
 		devMode:                       env.GetEnvBool("DEV_MODE", false),
 		timeSchedulerPollingLookAhead: env.GetEnvInt("TIME_SCHEDULER_POLLING_LOOKAHEAD", 40),
+		siweDomain:                    env.GetEnvString("SIWE_DOMAIN", "app.triggerx.network"),
+		siweNonceTTL:                  env.GetEnvDuration("SIWE_NONCE_TTL", 5*time.Minute),
+		sessionTokenSecret:            env.GetEnvString("SESSION_TOKEN_SECRET", ""),
+		sessionTokenTTL:               env.GetEnvDuration("SESSION_TOKEN_TTL", 15*time.Minute),
+		safeOwnersCacheTTL:            env.GetEnvDuration("SAFE_OWNERS_CACHE_TTL", time.Minute),
+	}
+	cfg.internalPort = env.GetEnvString("DBSERVER_INTERNAL_PORT", "9008")
+	cfg.autoMigrate = env.GetEnvBool("DBSERVER_AUTO_MIGRATE", false)
+	cfg.ipRateLimitPerMinute = env.GetEnvInt("DBSERVER_IP_RATE_LIMIT_PER_MINUTE", 120)
+	cfg.ipRateLimitBurst = env.GetEnvInt("DBSERVER_IP_RATE_LIMIT_BURST", 40)
+	for _, proxy := range strings.Split(env.GetEnvString("DBSERVER_TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			cfg.trustedProxies = append(cfg.trustedProxies, proxy)
+		}
+	}
+	cfg.rpcTLS = mtls.Config{
+		CertFile: env.GetEnvString("RPC_TLS_CERT_FILE", ""),
+		KeyFile:  env.GetEnvString("RPC_TLS_KEY_FILE", ""),
+		CAFile:   env.GetEnvString("RPC_TLS_CA_FILE", ""),
+	}
+	keys, err := jwt.ParseServiceTokenPublicKeys(env.GetEnvString("SERVICE_TOKEN_PUBLIC_KEYS", ""))
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
 	}
+	cfg.serviceTokenPublicKeys = keys
 	if err := validateConfig(cfg); err != nil {
 		return fmt.Errorf("invalid config: %w", err)
 	}
@@ -99,6 +156,18 @@ func validateConfig(cfg Config) error {
 	if !env.IsValidPrivateKey(cfg.faucetPrivateKey) {
 		return fmt.Errorf("invalid faucet private key: %s", cfg.faucetPrivateKey)
 	}
+	if !env.IsValidPort(cfg.internalPort) {
+		return fmt.Errorf("invalid internal port: %s", cfg.internalPort)
+	}
+	if env.IsEmpty(cfg.siweDomain) {
+		return fmt.Errorf("invalid siwe domain: %s", cfg.siweDomain)
+	}
+	if cfg.sessionTokenTTL <= 0 || cfg.siweNonceTTL <= 0 {
+		return fmt.Errorf("invalid session token ttl: %s or siwe nonce ttl: %s", cfg.sessionTokenTTL, cfg.siweNonceTTL)
+	}
+	if cfg.ipRateLimitPerMinute <= 0 || cfg.ipRateLimitBurst <= 0 {
+		return fmt.Errorf("invalid ip rate limit: %d per minute, burst %d", cfg.ipRateLimitPerMinute, cfg.ipRateLimitBurst)
+	}
 	if env.IsEmpty(cfg.otTempoEndpoint) {
 		return fmt.Errorf("invalid tempo otlp endpoint: %s", cfg.otTempoEndpoint)
 	}
@@ -118,6 +187,12 @@ func validateConfig(cfg Config) error {
 		if env.IsEmpty(cfg.botToken) {
 			return fmt.Errorf("invalid bot token: %s", cfg.botToken)
 		}
+		if len(cfg.sessionTokenSecret) < 32 {
+			return fmt.Errorf("invalid session token secret: must be at least 32 characters")
+		}
+		if len(cfg.serviceTokenPublicKeys) == 0 && !cfg.rpcTLS.Enabled() {
+			return fmt.Errorf("internal endpoints need service token public keys or an RPC TLS certificate")
+		}
 	}
 	return nil
 }
@@ -174,6 +249,11 @@ func GetUpstashRedisRestToken() string {
 	return cfg.upstashRedisRestToken
 }
 
+// IsAutoMigrate returns whether the server applies pending schema migrations on startup
+func IsAutoMigrate() bool {
+	return cfg.autoMigrate
+}
+
 func GetOTTempoEndpoint() string {
 	return cfg.otTempoEndpoint
 }
@@ -185,3 +265,79 @@ func IsDevMode() bool {
 func GetPollingLookAhead() int {
 	return cfg.timeSchedulerPollingLookAhead
 }
+
+func GetSIWEDomain() string {
+	return cfg.siweDomain
+}
+
+func GetSIWENonceTTL() time.Duration {
+	return cfg.siweNonceTTL
+}
+
+func GetSessionTokenSecret() string {
+	return cfg.sessionTokenSecret
+}
+
+func GetSessionTokenTTL() time.Duration {
+	return cfg.sessionTokenTTL
+}
+
+func GetSafeOwnersCacheTTL() time.Duration {
+	return cfg.safeOwnersCacheTTL
+}
+
+// GetServiceTokenPublicKeys returns the keys service tokens of internal calls are verified
+// with
+func GetServiceTokenPublicKeys() []ed25519.PublicKey {
+	return cfg.serviceTokenPublicKeys
+}
+
+// GetInternalPort returns the port of the mutual TLS listener of internal calls
+func GetInternalPort() string {
+	return cfg.internalPort
+}
+
+// GetRPCTLSConfig returns the certificate of the internal listener, disabled when no
+// certificate file is set
+func GetRPCTLSConfig() mtls.Config {
+	return cfg.rpcTLS
+}
+
+// GetIPRateLimit returns the requests per minute and burst of each client IP on routes
+// without an API key
+func GetIPRateLimit() (int, int) {
+	return cfg.ipRateLimitPerMinute, cfg.ipRateLimitBurst
+}
+
+// GetTrustedProxies returns the IPs and CIDRs of the proxies in front of the dbserver, whose
+// X-Forwarded-For header gives the client IP
+func GetTrustedProxies() []string {
+	return cfg.trustedProxies
+}
+
+// GetChainRpcUrl returns the Alchemy RPC URL of a supported chain, or "" for others
+func GetChainRpcUrl(chainID string) string {
+	switch chainID {
+	// Testnets
+	case "11155111":
+		return fmt.Sprintf("https://eth-sepolia.g.alchemy.com/v2/%s", cfg.alchemyAPIKey)
+	case "11155420":
+		return fmt.Sprintf("https://opt-sepolia.g.alchemy.com/v2/%s", cfg.alchemyAPIKey)
+	case "84532":
+		return fmt.Sprintf("https://base-sepolia.g.alchemy.com/v2/%s", cfg.alchemyAPIKey)
+	case "421614":
+		return fmt.Sprintf("https://arb-sepolia.g.alchemy.com/v2/%s", cfg.alchemyAPIKey)
+
+	// Mainnets
+	case "1":
+		return fmt.Sprintf("https://eth-mainnet.g.alchemy.com/v2/%s", cfg.alchemyAPIKey)
+	case "10":
+		return fmt.Sprintf("https://opt-mainnet.g.alchemy.com/v2/%s", cfg.alchemyAPIKey)
+	case "8453":
+		return fmt.Sprintf("https://base-mainnet.g.alchemy.com/v2/%s", cfg.alchemyAPIKey)
+	case "42161":
+		return fmt.Sprintf("https://arb-mainnet.g.alchemy.com/v2/%s", cfg.alchemyAPIKey)
+	default:
+		return ""
+	}
+}
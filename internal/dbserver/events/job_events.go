@@ -0,0 +1,26 @@
+package events
+
+import (
+	"time"
+)
+
+// JobEventType represents the type of job event
+type JobEventType string
+
+const (
+	JobEventTypeLifecycleChanged JobEventType = "JOB_LIFECYCLE_CHANGED"
+)
+
+// JobLifecycleChangedEvent represents a job being paused, resumed or cancelled, or having a
+// pause scheduled
+type JobLifecycleChangedEvent struct {
+	JobID     string     `json:"job_id"`
+	Action    string     `json:"action"`
+	OldStatus string     `json:"old_status"`
+	NewStatus string     `json:"new_status"`
+	PauseAt   *time.Time `json:"pause_at,omitempty"`
+	// Resume mode and next execution of a resumed time job
+	ResumeMode             string     `json:"resume_mode,omitempty"`
+	NextExecutionTimestamp *time.Time `json:"next_execution_timestamp,omitempty"`
+	UserID                 string     `json:"user_id,omitempty"`
+}
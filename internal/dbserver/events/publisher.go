@@ -91,6 +91,18 @@ func (p *Publisher) PublishTaskFeeUpdated(taskID int64, jobID string, oldFee, ne
 	p.logger.Infof("Published task fee updated event for task %d: %.2f -> %.2f", taskID, oldFee, newFee)
 }
 
+// PublishJobLifecycleChanged publishes a job lifecycle changed event
+func (p *Publisher) PublishJobLifecycleChanged(event *JobLifecycleChangedEvent) {
+	jobEventData := &websocket.JobEventData{
+		JobID:   event.JobID,
+		UserID:  event.UserID,
+		Changes: event,
+	}
+
+	p.hub.BroadcastJobLifecycleChanged(jobEventData)
+	p.logger.Infof("Published job lifecycle event for job %s: %s (%s -> %s)", event.JobID, event.Action, event.OldStatus, event.NewStatus)
+}
+
 // Shutdown gracefully shuts down the publisher
 func (p *Publisher) Shutdown() {
 	p.logger.Info("Shutting down task event publisher")
@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
@@ -20,6 +23,59 @@ func MaskApiKey(key string) string {
 	return key[:4] + strings.Repeat("*", len(key)-8) + key[len(key)-4:]
 }
 
+// displayApiKeyID returns how a key is shown: its ID, or masked for keys stored raw
+func displayApiKeyID(apiKey *commonTypes.ApiKey) string {
+	if len(apiKey.KeyHash) > 0 {
+		return apiKey.Key
+	}
+	return MaskApiKey(apiKey.Key)
+}
+
+// newApiKeyResponse returns a new key with its settings, the only time the key is shown
+func newApiKeyResponse(key string, apiKey *commonTypes.ApiKey) types.CreateApiKeyResponse {
+	return types.CreateApiKeyResponse{
+		Key:        key,
+		KeyID:      apiKey.Key,
+		Owner:      apiKey.Owner,
+		IsActive:   apiKey.IsActive,
+		RateLimit:  apiKey.RateLimit,
+		Tier:       apiKey.Tier,
+		Scopes:     apiKey.Scopes,
+		ExpiresAt:  apiKey.ExpiresAt,
+		AllowedIPs: apiKey.AllowedIPs,
+		LastUsed:   apiKey.LastUsed,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+// storeNewApiKey generates a key for apiKey and stores it under its ID with its hash,
+// returning the key. Another key is generated when the ID is taken.
+func (h *Handler) storeNewApiKey(apiKey *commonTypes.ApiKey) (string, error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		key, id, err := auth.GenerateApiKey()
+		if err != nil {
+			return "", err
+		}
+		salt, err := auth.NewApiKeySalt()
+		if err != nil {
+			return "", err
+		}
+		apiKey.Key, apiKey.KeySalt, apiKey.KeyHash = id, salt, auth.HashApiKey(key, salt)
+
+		trackDBOp := metrics.TrackDBOperation("create", "apikey_data")
+		err = h.apiKeysRepository.CreateApiKey(apiKey)
+		trackDBOp(err)
+		if errors.Is(err, repository.ErrApiKeyIDTaken) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return key, nil
+	}
+	return "", repository.ErrApiKeyIDTaken
+}
+
 func (h *Handler) CreateApiKey(c *gin.Context) {
 	traceID := h.getTraceID(c)
 	h.logger.Infof("[CreateApiKey] trace_id=%s - Creating API key", traceID)
@@ -41,28 +97,49 @@ func (h *Handler) CreateApiKey(c *gin.Context) {
 		req.RateLimit = 60
 	}
 
+	if err := types.ValidateApiKeySettings(req.Tier, req.Scopes, req.AllowedIPs); err != nil {
+		h.logger.Warnf("[CreateApiKey] Validation failed: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now().UTC()
+	var expiresAt time.Time
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+			return
+		}
+		expiresAt = req.ExpiresAt.UTC()
+	}
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = types.DefaultApiKeyScopes
+	}
+
 	// No longer check for existing API key for this owner; allow multiple API keys per owner
 
 	apiKey := commonTypes.ApiKey{
-		Key:       "TGRX-" + uuid.New().String(),
-		Owner:     req.Owner,
-		IsActive:  true,
-		RateLimit: req.RateLimit,
-		LastUsed:  time.Now().UTC(),
-		CreatedAt: time.Now().UTC(),
+		Owner:      req.Owner,
+		IsActive:   true,
+		RateLimit:  req.RateLimit,
+		Tier:       req.Tier,
+		Scopes:     scopes,
+		ExpiresAt:  expiresAt,
+		AllowedIPs: req.AllowedIPs,
+		LastUsed:   now,
+		CreatedAt:  now,
 	}
 
-	trackDBOp := metrics.TrackDBOperation("create", "apikey_data")
-	if err := h.apiKeysRepository.CreateApiKey(&apiKey); err != nil {
-		trackDBOp(err)
+	key, err := h.storeNewApiKey(&apiKey)
+	if err != nil {
 		h.logger.Errorf("[CreateApiKey] Failed to insert API key: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
 		return
 	}
-	trackDBOp(nil)
 
-	h.logger.Infof("[CreateApiKey] Successfully created new API key for owner %s (Key: %s)", req.Owner, apiKey.Key)
-	c.JSON(http.StatusCreated, apiKey)
+	h.logger.Infof("[CreateApiKey] Successfully created new API key for owner %s (Key ID: %s)", req.Owner, apiKey.Key)
+	c.JSON(http.StatusCreated, newApiKeyResponse(key, &apiKey))
 }
 
 func (h *Handler) UpdateApiKey(c *gin.Context) {
@@ -94,8 +171,36 @@ func (h *Handler) UpdateApiKey(c *gin.Context) {
 		apiKey.RateLimit = *req.RateLimit
 	}
 
+	// An empty tier goes back to the key's rate_limit, a zero expires_at never expires and an
+	// empty allowlist allows every IP
+	if req.Tier != nil {
+		apiKey.Tier = *req.Tier
+	}
+	if req.Scopes != nil {
+		apiKey.Scopes = *req.Scopes
+	}
+	if req.ExpiresAt != nil {
+		apiKey.ExpiresAt = req.ExpiresAt.UTC()
+	}
+	if req.AllowedIPs != nil {
+		apiKey.AllowedIPs = *req.AllowedIPs
+	}
+	if err := types.ValidateApiKeySettings(apiKey.Tier, apiKey.Scopes, apiKey.AllowedIPs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := types.UpdateApiKeyRequest{
+		Key:        apiKey.Key,
+		IsActive:   &apiKey.IsActive,
+		RateLimit:  &apiKey.RateLimit,
+		Tier:       &apiKey.Tier,
+		Scopes:     &apiKey.Scopes,
+		ExpiresAt:  &apiKey.ExpiresAt,
+		AllowedIPs: &apiKey.AllowedIPs,
+	}
 	trackDBOp = metrics.TrackDBOperation("update", "apikey_data")
-	if err := h.apiKeysRepository.UpdateApiKey(&req); err != nil {
+	if err := h.apiKeysRepository.UpdateApiKey(&update); err != nil {
 		trackDBOp(err)
 		h.logger.Errorf("Failed to update API key: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update API key"})
@@ -103,9 +208,101 @@ func (h *Handler) UpdateApiKey(c *gin.Context) {
 	}
 	trackDBOp(nil)
 
+	apiKey.Key = displayApiKeyID(apiKey)
 	c.JSON(http.StatusOK, apiKey)
 }
 
+// RotateApiKey handles POST /admin/api-keys/:key/rotate. The new key has the settings of the
+// old one, which keeps working until the grace period ends.
+func (h *Handler) RotateApiKey(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[RotateApiKey] trace_id=%s - Rotating API key", traceID)
+	keyID := c.Param("key")
+
+	var req types.RotateApiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	gracePeriod := types.DefaultApiKeyGracePeriod
+	if req.GracePeriod != "" {
+		var err error
+		gracePeriod, err = time.ParseDuration(req.GracePeriod)
+		if err != nil || gracePeriod < 0 || gracePeriod > types.MaxApiKeyGracePeriod {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "grace_period must be a duration of at most 720h"})
+			return
+		}
+	}
+
+	trackDBOp := metrics.TrackDBOperation("read", "apikey_data")
+	current, err := h.apiKeysRepository.GetApiKeyDataByKey(keyID)
+	trackDBOp(err)
+	if err != nil {
+		h.logger.Errorf("[RotateApiKey] API key not found: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+	now := time.Now().UTC()
+	if current.ReplacedBy != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "API key has already been rotated", "replaced_by": current.ReplacedBy})
+		return
+	}
+	if !current.IsActive || types.ApiKeyExpired(current, now) {
+		c.JSON(http.StatusConflict, gin.H{"error": "API key is inactive or expired"})
+		return
+	}
+
+	apiKey := commonTypes.ApiKey{
+		Owner:      current.Owner,
+		IsActive:   true,
+		RateLimit:  current.RateLimit,
+		Tier:       current.Tier,
+		Scopes:     current.Scopes,
+		ExpiresAt:  current.ExpiresAt,
+		AllowedIPs: current.AllowedIPs,
+		LastUsed:   now,
+		CreatedAt:  now,
+	}
+	if len(apiKey.Scopes) == 0 {
+		apiKey.Scopes = types.DefaultApiKeyScopes
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+			return
+		}
+		apiKey.ExpiresAt = req.ExpiresAt.UTC()
+	}
+
+	key, err := h.storeNewApiKey(&apiKey)
+	if err != nil {
+		h.logger.Errorf("[RotateApiKey] Failed to insert API key: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	// The old key never outlives its own expiry
+	graceUntil := now.Add(gracePeriod)
+	if !current.ExpiresAt.IsZero() && current.ExpiresAt.Before(graceUntil) {
+		graceUntil = current.ExpiresAt
+	}
+	trackDBOp = metrics.TrackDBOperation("update", "apikey_data")
+	err = h.apiKeysRepository.ReplaceApiKey(current.Key, apiKey.Key, graceUntil)
+	trackDBOp(err)
+	if err != nil {
+		h.logger.Errorf("[RotateApiKey] Failed to end the grace period of %s, new key %s: %v", displayApiKeyID(current), apiKey.Key, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate API key"})
+		return
+	}
+
+	h.logger.Infof("[RotateApiKey] Rotated API key %s to %s, the old key expires at %s", displayApiKeyID(current), apiKey.Key, graceUntil)
+	c.JSON(http.StatusCreated, types.RotateApiKeyResponse{
+		CreateApiKeyResponse: newApiKeyResponse(key, &apiKey),
+		ReplacedKeyID:        displayApiKeyID(current),
+		ReplacedKeyExpiresAt: graceUntil,
+	})
+}
+
 func (h *Handler) DeleteApiKey(c *gin.Context) {
 	traceID := h.getTraceID(c)
 	h.logger.Infof("[DeleteApiKey] trace_id=%s - Deleting API key", traceID)
@@ -173,13 +370,19 @@ func (h *Handler) GetApiKeysByOwner(c *gin.Context) {
 	masked := make([]map[string]interface{}, 0, len(apiKeys))
 	for _, k := range apiKeys {
 		masked = append(masked, map[string]interface{}{
-			"key":           MaskApiKey(k.Key),
+			"key":           displayApiKeyID(k),
 			"owner":         k.Owner,
 			"is_active":     k.IsActive,
 			"rate_limit":    k.RateLimit,
+			"tier":          k.Tier,
+			"scopes":        k.Scopes,
+			"expires_at":    k.ExpiresAt,
+			"allowed_ips":   k.AllowedIPs,
+			"replaced_by":   k.ReplacedBy,
 			"success_count": k.SuccessCount,
 			"failed_count":  k.FailedCount,
 			"last_used":     k.LastUsed,
+			"last_used_ip":  k.LastUsedIP,
 			"created_at":    k.CreatedAt,
 		})
 	}
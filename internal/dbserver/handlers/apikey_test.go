@@ -88,6 +88,24 @@ func (m *MockApiKeysRepository) UpdateApiKeyLastUsed(key string, isSuccess bool)
 	return args.Error(0)
 }
 
+func (m *MockApiKeysRepository) AuthenticateApiKey(key string) (*commonTypes.ApiKey, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*commonTypes.ApiKey), args.Error(1)
+}
+
+func (m *MockApiKeysRepository) UpdateApiKeyLastUsedIP(key, clientIP string) error {
+	args := m.Called(key, clientIP)
+	return args.Error(0)
+}
+
+func (m *MockApiKeysRepository) ReplaceApiKey(key, replacedBy string, expiresAt time.Time) error {
+	args := m.Called(key, replacedBy, expiresAt)
+	return args.Error(0)
+}
+
 func (m *MockApiKeysRepository) DeleteApiKey(key string) error {
 	args := m.Called(key)
 	return args.Error(0)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/events"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/redis"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
@@ -36,8 +37,12 @@ type Handler struct {
 	keeperRepository        repository.KeeperRepository
 	apiKeysRepository       repository.ApiKeysRepository
 	safeAddressRepository   repository.SafeAddressRepository
+	workflowRepository      repository.WorkflowRepository
 	httpClient              http.HTTPClientInterface
 	redisClient             *redis.Client
+	// Wallet login and Safe ownership checks, nil when unavailable
+	authenticator *auth.Authenticator
+	safeOwners    *auth.SafeOwners
 	// WebSocket components
 	hub       *websocket.Hub
 	publisher *events.Publisher
@@ -45,7 +50,7 @@ type Handler struct {
 	scanNowQuery func(*time.Time) error // for testability
 }
 
-func NewHandler(db *database.Connection, logger logging.Logger, config NotificationConfig, dockerExecutor dockerexecutor.DockerExecutorAPI, hub *websocket.Hub, publisher *events.Publisher, httpClient http.HTTPClientInterface, redisClient *redis.Client) *Handler {
+func NewHandler(db *database.Connection, logger logging.Logger, config NotificationConfig, dockerExecutor dockerexecutor.DockerExecutorAPI, hub *websocket.Hub, publisher *events.Publisher, httpClient http.HTTPClientInterface, redisClient *redis.Client, authenticator *auth.Authenticator, safeOwners *auth.SafeOwners) *Handler {
 	h := &Handler{
 		db:                      db,
 		logger:                  logger,
@@ -62,10 +67,13 @@ func NewHandler(db *database.Connection, logger logging.Logger, config Notificat
 		keeperRepository:        repository.NewKeeperRepository(db),
 		apiKeysRepository:       repository.NewApiKeysRepository(db),
 		safeAddressRepository:   repository.NewSafeAddressRepository(db),
+		workflowRepository:      repository.NewWorkflowRepository(db),
 		hub:                     hub,
 		publisher:               publisher,
 		httpClient:              httpClient,
 		redisClient:             redisClient,
+		authenticator:           authenticator,
+		safeOwners:              safeOwners,
 	}
 	h.scanNowQuery = h.defaultScanNowQuery
 
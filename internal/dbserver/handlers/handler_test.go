@@ -40,6 +40,10 @@ func (m *MockSession) Query(query string, args ...interface{}) *gocql.Query {
 	return &gocql.Query{}
 }
 
+func (m *MockSession) NewBatch(typ gocql.BatchType) *gocql.Batch {
+	return &gocql.Batch{Type: typ}
+}
+
 func (m *MockSession) ExecuteBatch(batch *gocql.Batch) error {
 	args := m.Called(batch)
 	return args.Error(0)
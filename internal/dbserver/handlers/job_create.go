@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"net/http"
@@ -11,9 +14,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gocql/gocql"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/middleware"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	"github.com/trigg3rX/triggerx-backend/pkg/parser"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/config"
+	dockertypes "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
 	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
@@ -39,6 +45,24 @@ func (h *Handler) CreateJobData(c *gin.Context) {
 		return
 	}
 
+	// Jobs are created for the session's address, the body may only repeat it
+	caller := strings.ToLower(c.GetString(middleware.WalletAddressKey))
+	if caller == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token is required"})
+		return
+	}
+	for i := range tempJobs {
+		if tempJobs[i].UserAddress != "" && strings.ToLower(tempJobs[i].UserAddress) != caller {
+			h.logger.Warnf("[CreateJobData] Session of %s tried to create a job for %s", caller, tempJobs[i].UserAddress)
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Jobs can only be created for the session's address",
+				"code":  "USER_ADDRESS_MISMATCH",
+			})
+			return
+		}
+		tempJobs[i].UserAddress = caller
+	}
+
 	var existingUserID int64
 	var existingUser commonTypes.UserData
 	var err error
@@ -97,349 +121,12 @@ func (h *Handler) CreateJobData(c *gin.Context) {
 			linkJobID = createdJobs.JobIDs[i+1].ToBigInt()
 		}
 
-		jobID := new(big.Int)
-		if _, ok := jobID.SetString(tempJobs[i].JobID, 10); !ok {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job_id format"})
-			return
-		}
-
-		jobData := &commonTypes.JobData{
-			JobID:             commonTypes.FromBigInt(jobID),
-			JobTitle:          tempJobs[i].JobTitle,
-			TaskDefinitionID:  tempJobs[i].TaskDefinitionID,
-			UserID:            existingUser.UserID,
-			LinkJobID:         commonTypes.FromBigInt(linkJobID),
-			ChainStatus:       chainStatus,
-			Custom:            tempJobs[i].Custom,
-			TimeFrame:         tempJobs[i].TimeFrame,
-			Recurring:         tempJobs[i].Recurring,
-			Status:            "pending",
-			JobCostPrediction: tempJobs[i].JobCostPrediction,
-			Timezone:          tempJobs[i].Timezone,
-			IsImua:            tempJobs[i].IsImua,
-			CreatedChainID:    tempJobs[i].CreatedChainID,
-			SafeAddress:       "",
-		}
-
-		// Before creating job, validate IPFS code for dynamic jobs (TaskDefinitionID==2,4,6,7 & DynamicArgumentsScriptUrl)
-		if (tempJobs[i].TaskDefinitionID == 2 || tempJobs[i].TaskDefinitionID == 4 || tempJobs[i].TaskDefinitionID == 6 || tempJobs[i].TaskDefinitionID == 7) && tempJobs[i].DynamicArgumentsScriptUrl != "" {
-			// Parse CID or gateway URL if needed
-			ipfsUrl := tempJobs[i].DynamicArgumentsScriptUrl
-			ctx := c.Request.Context()
-			resp, err := h.httpClient.Get(ctx, ipfsUrl)
-			if err != nil {
-				h.logger.Errorf("[CreateJobData] Failed to download file: %v", err)
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to download file: " + err.Error()})
-				return
-			}
-			defer func() {
-				if err := resp.Body.Close(); err != nil {
-					h.logger.Errorf("[CreateJobData] Error closing response body: %v", err)
-				}
-			}()
-
-			if resp.StatusCode != http.StatusOK {
-				h.logger.Errorf("[CreateJobData] Unexpected status code: %d", resp.StatusCode)
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Unexpected status code: " + strconv.Itoa(resp.StatusCode)})
-				return
-			}
-
-			content, err := io.ReadAll(resp.Body)
-			if err != nil {
-				h.logger.Errorf("[CreateJobData] Failed to read response body: %v", err)
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read response body: " + err.Error()})
-				return
-			}
-			ipfsCode := string(content)
-
-			valReq := ValidateCodeRequest{
-				Code:             ipfsCode,
-				Language:         tempJobs[i].Language,
-				SelectedSafe:     tempJobs[i].SafeAddress,
-				TargetFunction:   tempJobs[i].TargetFunction,
-				TaskDefinitionID: tempJobs[i].TaskDefinitionID,
-				IsSafe:           tempJobs[i].IsSafe,
-			}
-			valResp, _ := h.ValidateCodeInternal(ctx, valReq, ipfsUrl, config.GetAlchemyAPIKey())
-			if !valResp.Executable || !valResp.SafeMatch {
-				errMsg := "IPFS code validation failed: "
-				if valResp.Error != "" {
-					errMsg += valResp.Error
-				} else if !valResp.SafeMatch {
-					errMsg += "SafeAddress does not match code output."
-				} else {
-					errMsg += "Code not executable."
-				}
-				// Emit a log for observability of why the request is not proceeding
-				// outputPreview := valResp.Output
-				// if len(outputPreview) > 200 {
-				// 	outputPreview = outputPreview[:200] + "..."
-				// }
-				// h.logger.Infof("[CreateJobData] IPFS code validation failed | user=%s jobID=%s taskDefID=%d isSafe=%t selectedSafe=%s executable=%t safeMatch=%t error=%q outputPreview=%q",
-				// 	tempJobs[i].UserAddress, tempJobs[i].JobID, tempJobs[i].TaskDefinitionID, valReq.IsSafe, valReq.SelectedSafe, valResp.Executable, valResp.SafeMatch, valResp.Error, outputPreview)
-				// Return 200 with structured validation failure so clients can display message without treating as transport error
-				c.JSON(http.StatusOK, gin.H{
-					"status":                "validation_failed",
-					"message":               errMsg,
-					"validation_executable": valResp.Executable,
-					"validation_safe_match": valResp.SafeMatch,
-					"validation_output":     valResp.Output,
-				})
-				return
-			}
-		}
-
-		// Handle safe address if IsSafe is true
-		if tempJobs[i].IsSafe {
-			if tempJobs[i].SafeAddress == "" {
-				h.logger.Errorf("[CreateJobData] IsSafe is true but SafeAddress is empty for job %s", tempJobs[i].JobID)
-				c.JSON(http.StatusBadRequest, gin.H{"error": "SafeAddress is required when IsSafe is true"})
-				return
-			}
-
-			// Lowercase the safe address for consistency
-			safeAddr := strings.ToLower(tempJobs[i].SafeAddress)
-
-			// Check if safe address already exists for this user
-			exists, err := h.safeAddressRepository.CheckSafeAddressExists(strings.ToLower(tempJobs[i].UserAddress), safeAddr)
-			if err != nil {
-				h.logger.Errorf("[CreateJobData] Error checking safe address existence: %v", err)
-			} else if !exists {
-				// Create safe address entry if it doesn't exist
-				if err := h.safeAddressRepository.CreateSafeAddress(strings.ToLower(tempJobs[i].UserAddress), safeAddr, tempJobs[i].SafeName); err != nil {
-					h.logger.Errorf("[CreateJobData] Error creating safe address: %v", err)
-				} else {
-					h.logger.Infof("[CreateJobData] Created safe address %s for user %s", safeAddr, tempJobs[i].UserAddress)
-				}
-			}
-
-			// Set the safe address in job data
-			jobData.SafeAddress = safeAddr
-		}
-
-		// Track job creation
-		trackDBOp = metrics.TrackDBOperation("create", "jobs")
-		jobID, err := h.jobRepository.CreateNewJob(jobData)
-		trackDBOp(err)
-
+		jobID, err := h.createJob(c.Request.Context(), existingUser, tempJobs[i], linkJobID, chainStatus)
 		if err != nil {
-			h.logger.Errorf("[CreateJobData] Error creating job: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-			return
-		}
-
-		createdJobs.JobIDs[i] = commonTypes.NewBigInt(jobID)
-		expirationTime := time.Now().Add(time.Duration(tempJobs[i].TimeFrame) * time.Second)
-		var scheduleConditionJobData commonTypes.ScheduleConditionJobData
-
-		switch tempJobs[i].TaskDefinitionID {
-		case 1, 2:
-			// Time-based job
-
-			var nextExecutionTimestamp time.Time
-			nextExecutionTimestamp, err := parser.CalculateNextExecutionTime(time.Now(), "interval", tempJobs[i].TimeInterval, tempJobs[i].CronExpression, tempJobs[i].SpecificSchedule)
-			if err != nil {
-				h.logger.Errorf("[getNextExecutionTimestamp] Error calculating next execution timestamp: %v", err)
-				nextExecutionTimestamp = time.Now().Add(time.Duration(tempJobs[i].TimeInterval) * time.Second)
-			}
-
-			timeJobData := commonTypes.TimeJobData{
-				JobID:            commonTypes.NewBigInt(jobID),
-				TaskDefinitionID: tempJobs[i].TaskDefinitionID,
-				ExpirationTime:   expirationTime,
-				// Recurring:                 tempJobs[i].Recurring,
-				TimeInterval:              tempJobs[i].TimeInterval,
-				ScheduleType:              "interval",
-				CronExpression:            tempJobs[i].CronExpression,
-				SpecificSchedule:          tempJobs[i].SpecificSchedule,
-				NextExecutionTimestamp:    nextExecutionTimestamp,
-				TargetChainID:             tempJobs[i].TargetChainID,
-				TargetContractAddress:     tempJobs[i].TargetContractAddress,
-				TargetFunction:            tempJobs[i].TargetFunction,
-				ABI:                       tempJobs[i].ABI,
-				ArgType:                   tempJobs[i].ArgType,
-				Arguments:                 tempJobs[i].Arguments,
-				DynamicArgumentsScriptUrl: tempJobs[i].DynamicArgumentsScriptUrl,
-				IsCompleted:               false,
-				IsActive:                  true,
-			}
-
-			// Track time job creation
-			trackDBOp = metrics.TrackDBOperation("create", "time_jobs")
-			if err := h.timeJobRepository.CreateTimeJob(&timeJobData); err != nil {
-				trackDBOp(err)
-				h.logger.Errorf("[CreateJobData] Error inserting time job data for jobID %d: %v", jobID, err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-				return
-			}
-			trackDBOp(nil)
-			h.logger.Infof("[CreateJobData] Successfully created time-based job %d with interval %d seconds",
-				jobID, timeJobData.TimeInterval)
-
-		case 3, 4:
-			// Event-based job
-			eventJobData := commonTypes.EventJobData{
-				JobID:                     commonTypes.NewBigInt(jobID),
-				TaskDefinitionID:          tempJobs[i].TaskDefinitionID,
-				ExpirationTime:            expirationTime,
-				Recurring:                 tempJobs[i].Recurring,
-				TriggerChainID:            tempJobs[i].TriggerChainID,
-				TriggerContractAddress:    tempJobs[i].TriggerContractAddress,
-				TriggerEvent:              tempJobs[i].TriggerEvent,
-				EventFilterParaName:       tempJobs[i].EventFilterParaName,
-				EventFilterValue:          tempJobs[i].EventFilterValue,
-				TargetChainID:             tempJobs[i].TargetChainID,
-				TargetContractAddress:     tempJobs[i].TargetContractAddress,
-				TargetFunction:            tempJobs[i].TargetFunction,
-				ABI:                       tempJobs[i].ABI,
-				ArgType:                   tempJobs[i].ArgType,
-				Arguments:                 tempJobs[i].Arguments,
-				DynamicArgumentsScriptUrl: tempJobs[i].DynamicArgumentsScriptUrl,
-				IsCompleted:               false,
-				IsActive:                  true,
-			}
-
-			if err := h.eventJobRepository.CreateEventJob(&eventJobData); err != nil {
-				h.logger.Errorf("[CreateJobData] Error inserting event job data for jobID %d: %v", jobID, err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-				return
-			}
-			scheduleConditionJobData.JobID = commonTypes.NewBigInt(jobID)
-			scheduleConditionJobData.TaskDefinitionID = tempJobs[i].TaskDefinitionID
-			scheduleConditionJobData.LastExecutedAt = time.Now()
-			scheduleConditionJobData.TaskTargetData = commonTypes.TaskTargetData{
-				JobID:                     commonTypes.NewBigInt(jobID),
-				TaskDefinitionID:          tempJobs[i].TaskDefinitionID,
-				TargetChainID:             tempJobs[i].TargetChainID,
-				TargetContractAddress:     tempJobs[i].TargetContractAddress,
-				TargetFunction:            tempJobs[i].TargetFunction,
-				ABI:                       tempJobs[i].ABI,
-				ArgType:                   tempJobs[i].ArgType,
-				Arguments:                 tempJobs[i].Arguments,
-				DynamicArgumentsScriptUrl: tempJobs[i].DynamicArgumentsScriptUrl,
-			}
-			scheduleConditionJobData.EventWorkerData = commonTypes.EventWorkerData{
-				JobID:                  commonTypes.NewBigInt(jobID),
-				ExpirationTime:         expirationTime,
-				Recurring:              tempJobs[i].Recurring,
-				TriggerChainID:         tempJobs[i].TriggerChainID,
-				TriggerContractAddress: tempJobs[i].TriggerContractAddress,
-				TriggerEvent:           tempJobs[i].TriggerEvent,
-				EventFilterParaName:    tempJobs[i].EventFilterParaName,
-				EventFilterValue:       tempJobs[i].EventFilterValue,
-			}
-			filterEnabled := eventJobData.EventFilterParaName != "" && eventJobData.EventFilterValue != ""
-			h.logger.Infof("[CreateJobData] Successfully created event-based job %d for event %s on contract %s (filter_enabled=%t)",
-				jobID, eventJobData.TriggerEvent, eventJobData.TriggerContractAddress, filterEnabled)
-
-		case 5, 6:
-			// Condition-based job
-			conditionJobData := commonTypes.ConditionJobData{
-				JobID:                     commonTypes.NewBigInt(jobID),
-				TaskDefinitionID:          tempJobs[i].TaskDefinitionID,
-				ExpirationTime:            expirationTime,
-				Recurring:                 tempJobs[i].Recurring,
-				ConditionType:             tempJobs[i].ConditionType,
-				UpperLimit:                tempJobs[i].UpperLimit,
-				LowerLimit:                tempJobs[i].LowerLimit,
-				ValueSourceType:           tempJobs[i].ValueSourceType,
-				ValueSourceUrl:            tempJobs[i].ValueSourceUrl,
-				TargetChainID:             tempJobs[i].TargetChainID,
-				TargetContractAddress:     tempJobs[i].TargetContractAddress,
-				TargetFunction:            tempJobs[i].TargetFunction,
-				ABI:                       tempJobs[i].ABI,
-				ArgType:                   tempJobs[i].ArgType,
-				Arguments:                 tempJobs[i].Arguments,
-				DynamicArgumentsScriptUrl: tempJobs[i].DynamicArgumentsScriptUrl,
-				IsCompleted:               false,
-				IsActive:                  true,
-				SelectedKeyRoute:          tempJobs[i].SelectedKeyRoute,
-			}
-
-			if err := h.conditionJobRepository.CreateConditionJob(&conditionJobData); err != nil {
-				h.logger.Errorf("[CreateJobData] Error inserting condition job data for jobID %d: %v", jobID, err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-				return
-			}
-			scheduleConditionJobData.JobID = commonTypes.NewBigInt(jobID)
-			scheduleConditionJobData.TaskDefinitionID = tempJobs[i].TaskDefinitionID
-			scheduleConditionJobData.LastExecutedAt = time.Now()
-			scheduleConditionJobData.TaskTargetData = commonTypes.TaskTargetData{
-				JobID:                     commonTypes.NewBigInt(jobID),
-				TaskDefinitionID:          tempJobs[i].TaskDefinitionID,
-				TargetChainID:             tempJobs[i].TargetChainID,
-				TargetContractAddress:     tempJobs[i].TargetContractAddress,
-				TargetFunction:            tempJobs[i].TargetFunction,
-				ABI:                       tempJobs[i].ABI,
-				ArgType:                   tempJobs[i].ArgType,
-				Arguments:                 tempJobs[i].Arguments,
-				DynamicArgumentsScriptUrl: tempJobs[i].DynamicArgumentsScriptUrl,
-			}
-			scheduleConditionJobData.ConditionWorkerData = commonTypes.ConditionWorkerData{
-				JobID:            commonTypes.NewBigInt(jobID),
-				ExpirationTime:   expirationTime,
-				Recurring:        tempJobs[i].Recurring,
-				ConditionType:    tempJobs[i].ConditionType,
-				UpperLimit:       tempJobs[i].UpperLimit,
-				LowerLimit:       tempJobs[i].LowerLimit,
-				ValueSourceType:  tempJobs[i].ValueSourceType,
-				ValueSourceUrl:   tempJobs[i].ValueSourceUrl,
-				SelectedKeyRoute: tempJobs[i].SelectedKeyRoute,
-			}
-			h.logger.Infof("[CreateJobData] Successfully created condition-based job %d with condition type %s (limits: %f-%f)",
-				jobID, conditionJobData.ConditionType, conditionJobData.LowerLimit, conditionJobData.UpperLimit)
-
-		case 7:
-			// Custom script job (TaskDefinitionID = 7)
-			var nextExecutionTime time.Time
-			nextExecutionTime, err := parser.CalculateNextExecutionTime(time.Now(), "interval", tempJobs[i].TimeInterval, "", "")
-			if err != nil {
-				h.logger.Errorf("[CreateJobData] Error calculating next execution time for custom job: %v", err)
-				nextExecutionTime = time.Now().Add(time.Duration(tempJobs[i].TimeInterval) * time.Second)
-			}
-
-			customJobData := commonTypes.CustomJobData{
-				JobID:             commonTypes.NewBigInt(jobID),
-				TargetChainID:     tempJobs[i].TargetChainID,
-				TaskDefinitionID:  7,
-				ExpirationTime:    expirationTime,
-				Recurring:         tempJobs[i].Recurring,
-				CustomScriptUrl:   tempJobs[i].DynamicArgumentsScriptUrl,
-				TimeInterval:      tempJobs[i].TimeInterval,
-				ScriptLanguage:    tempJobs[i].Language,
-				NextExecutionTime: nextExecutionTime,
-				LastExecutedAt:    time.Now(),
-				IsCompleted:       false,
-				IsActive:          true,
-			}
-
-			// Track custom job creation
-			trackDBOp = metrics.TrackDBOperation("create", "custom_jobs")
-			if err := h.customJobRepository.CreateCustomJob(&customJobData); err != nil {
-				trackDBOp(err)
-				h.logger.Errorf("[CreateJobData] Error inserting custom job data for jobID %d: %v", jobID, err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-				return
-			}
-			trackDBOp(nil)
-			h.logger.Infof("[CreateJobData] Successfully created custom script job %d with interval %d seconds, language %s",
-				jobID, customJobData.TimeInterval, customJobData.ScriptLanguage)
-
-		default:
-			h.logger.Errorf("[CreateJobData] Invalid task definition ID %d for job %d", tempJobs[i].TaskDefinitionID, i)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task definition ID"})
+			h.respondCreateJobError(c, err)
 			return
 		}
 
-		if tempJobs[i].TaskDefinitionID == 3 || tempJobs[i].TaskDefinitionID == 4 || tempJobs[i].TaskDefinitionID == 5 || tempJobs[i].TaskDefinitionID == 6 {
-			success, err := h.notifyConditionScheduler(jobID, scheduleConditionJobData)
-			if !success {
-				h.logger.Errorf("[CreateJobData] Error notifying condition scheduler for jobID %d: %v", jobID, err)
-			} else {
-				h.logger.Infof("[CreateJobData] Successfully notified condition scheduler for jobID %d", jobID)
-			}
-		}
-
 		pointsToAdd := 10.0
 		if tempJobs[i].Custom {
 			pointsToAdd = 20.0
@@ -486,3 +173,390 @@ func (h *Handler) CreateJobData(c *gin.Context) {
 	h.logger.Infof("[CreateJobData] Successfully completed job creation for user %d with %d new jobs",
 		existingUser.UserID, len(tempJobs))
 }
+
+// jobRequestError is a job creation failure the client can fix, answered with its status and body
+type jobRequestError struct {
+	status int
+	body   gin.H
+}
+
+func (e *jobRequestError) Error() string {
+	if message, ok := e.body["message"].(string); ok {
+		return message
+	}
+	return fmt.Sprint(e.body["error"])
+}
+
+// respondCreateJobError answers a failed job creation, with the request error's own response
+// when the client can fix it
+func (h *Handler) respondCreateJobError(c *gin.Context, err error) {
+	var requestErr *jobRequestError
+	if errors.As(err, &requestErr) {
+		c.JSON(requestErr.status, requestErr.body)
+		return
+	}
+	if quotaErr, ok := dockertypes.AsQuotaExceeded(err); ok {
+		h.respondQuotaExceeded(c, quotaErr)
+		return
+	}
+	if errors.Is(err, repository.ErrJobIDTaken) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Job ID is already taken",
+			"code":  "JOB_ID_TAKEN",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
+
+// createJob creates a job of a user with its type-specific data and registers event and
+// condition jobs with the condition scheduler. linkJobID is the job it runs before in a chain.
+func (h *Handler) createJob(ctx context.Context, user commonTypes.UserData, job types.CreateJobData, linkJobID *big.Int, chainStatus int) (*big.Int, error) {
+	jobID := new(big.Int)
+	if _, ok := jobID.SetString(job.JobID, 10); !ok {
+		return nil, &jobRequestError{status: http.StatusBadRequest, body: gin.H{"error": "Invalid job_id format"}}
+	}
+
+	jobData := &commonTypes.JobData{
+		JobID:             commonTypes.FromBigInt(jobID),
+		JobTitle:          job.JobTitle,
+		TaskDefinitionID:  job.TaskDefinitionID,
+		UserID:            user.UserID,
+		LinkJobID:         commonTypes.FromBigInt(linkJobID),
+		ChainStatus:       chainStatus,
+		Custom:            job.Custom,
+		TimeFrame:         job.TimeFrame,
+		Recurring:         job.Recurring,
+		Status:            "pending",
+		JobCostPrediction: job.JobCostPrediction,
+		Timezone:          job.Timezone,
+		IsImua:            job.IsImua,
+		CreatedChainID:    job.CreatedChainID,
+		SafeAddress:       "",
+	}
+
+	// Before creating job, validate IPFS code for dynamic jobs (TaskDefinitionID==2,4,6,7 & DynamicArgumentsScriptUrl)
+	if (job.TaskDefinitionID == 2 || job.TaskDefinitionID == 4 || job.TaskDefinitionID == 6 || job.TaskDefinitionID == 7) && job.DynamicArgumentsScriptUrl != "" {
+		// Parse CID or gateway URL if needed
+		ipfsUrl := job.DynamicArgumentsScriptUrl
+		resp, err := h.httpClient.Get(ctx, ipfsUrl)
+		if err != nil {
+			h.logger.Errorf("[CreateJobData] Failed to download file: %v", err)
+			return nil, &jobRequestError{status: http.StatusBadRequest, body: gin.H{"error": "Failed to download file: " + err.Error()}}
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				h.logger.Errorf("[CreateJobData] Error closing response body: %v", err)
+			}
+		}()
+
+		if resp.StatusCode != http.StatusOK {
+			h.logger.Errorf("[CreateJobData] Unexpected status code: %d", resp.StatusCode)
+			return nil, &jobRequestError{status: http.StatusBadRequest, body: gin.H{"error": "Unexpected status code: " + strconv.Itoa(resp.StatusCode)}}
+		}
+
+		content, err := io.ReadAll(resp.Body)
+		if err != nil {
+			h.logger.Errorf("[CreateJobData] Failed to read response body: %v", err)
+			return nil, &jobRequestError{status: http.StatusBadRequest, body: gin.H{"error": "Failed to read response body: " + err.Error()}}
+		}
+		ipfsCode := string(content)
+
+		valReq := ValidateCodeRequest{
+			Code:             ipfsCode,
+			Language:         job.Language,
+			SelectedSafe:     job.SafeAddress,
+			TargetFunction:   job.TargetFunction,
+			TaskDefinitionID: job.TaskDefinitionID,
+			IsSafe:           job.IsSafe,
+			TenantID:         strings.ToLower(job.UserAddress),
+		}
+		valResp, err := h.ValidateCodeInternal(ctx, valReq, ipfsUrl, config.GetAlchemyAPIKey())
+		if quotaErr, ok := dockertypes.AsQuotaExceeded(err); ok {
+			h.logger.Warnf("[CreateJobData] Execution quota exceeded for user %s: %v", job.UserAddress, quotaErr)
+			return nil, quotaErr
+		}
+		if !valResp.Executable || !valResp.SafeMatch {
+			errMsg := "IPFS code validation failed: "
+			if valResp.Error != "" {
+				errMsg += valResp.Error
+			} else if !valResp.SafeMatch {
+				errMsg += "SafeAddress does not match code output."
+			} else {
+				errMsg += "Code not executable."
+			}
+			// Emit a log for observability of why the request is not proceeding
+			// outputPreview := valResp.Output
+			// if len(outputPreview) > 200 {
+			// 	outputPreview = outputPreview[:200] + "..."
+			// }
+			// h.logger.Infof("[CreateJobData] IPFS code validation failed | user=%s jobID=%s taskDefID=%d isSafe=%t selectedSafe=%s executable=%t safeMatch=%t error=%q outputPreview=%q",
+			// 	job.UserAddress, job.JobID, job.TaskDefinitionID, valReq.IsSafe, valReq.SelectedSafe, valResp.Executable, valResp.SafeMatch, valResp.Error, outputPreview)
+			// Return 200 with structured validation failure so clients can display message without treating as transport error
+			return nil, &jobRequestError{status: http.StatusOK, body: gin.H{
+				"status":                "validation_failed",
+				"message":               errMsg,
+				"validation_executable": valResp.Executable,
+				"validation_safe_match": valResp.SafeMatch,
+				"validation_output":     valResp.Output,
+			}}
+		}
+	}
+
+	// Handle safe address if IsSafe is true
+	if job.IsSafe {
+		if job.SafeAddress == "" {
+			h.logger.Errorf("[CreateJobData] IsSafe is true but SafeAddress is empty for job %s", job.JobID)
+			return nil, &jobRequestError{status: http.StatusBadRequest, body: gin.H{"error": "SafeAddress is required when IsSafe is true"}}
+		}
+
+		// Lowercase the safe address for consistency
+		safeAddr := strings.ToLower(job.SafeAddress)
+
+		// Check if safe address already exists for this user
+		exists, err := h.safeAddressRepository.CheckSafeAddressExists(strings.ToLower(job.UserAddress), safeAddr)
+		if err != nil {
+			h.logger.Errorf("[CreateJobData] Error checking safe address existence: %v", err)
+		} else if !exists {
+			// Only an owner of the Safe may register it
+			if h.safeOwners != nil {
+				isOwner, err := h.safeOwners.IsOwner(ctx, job.CreatedChainID, safeAddr, job.UserAddress)
+				if err != nil {
+					h.logger.Errorf("[CreateJobData] Error checking owners of safe %s: %v", safeAddr, err)
+					return nil, &jobRequestError{status: http.StatusServiceUnavailable, body: gin.H{"error": "Failed to check safe owners"}}
+				}
+				if !isOwner {
+					h.logger.Warnf("[CreateJobData] User %s is not an owner of safe %s", job.UserAddress, safeAddr)
+					return nil, &jobRequestError{status: http.StatusForbidden, body: gin.H{"error": "User is not an owner of the safe"}}
+				}
+			}
+			// Create safe address entry if it doesn't exist
+			if err := h.safeAddressRepository.CreateSafeAddress(strings.ToLower(job.UserAddress), safeAddr, job.SafeName); err != nil {
+				h.logger.Errorf("[CreateJobData] Error creating safe address: %v", err)
+			} else {
+				h.logger.Infof("[CreateJobData] Created safe address %s for user %s", safeAddr, job.UserAddress)
+			}
+		}
+
+		// Set the safe address in job data
+		jobData.SafeAddress = safeAddr
+	}
+
+	// Track job creation
+	trackDBOp := metrics.TrackDBOperation("create", "jobs")
+	jobID, err := h.jobRepository.CreateNewJob(jobData)
+	trackDBOp(err)
+
+	if err != nil {
+		h.logger.Errorf("[CreateJobData] Error creating job: %v", err)
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	expirationTime := time.Now().Add(time.Duration(job.TimeFrame) * time.Second)
+	var scheduleConditionJobData commonTypes.ScheduleConditionJobData
+
+	switch job.TaskDefinitionID {
+	case 1, 2:
+		// Time-based job
+
+		var nextExecutionTimestamp time.Time
+		nextExecutionTimestamp, err := parser.CalculateNextExecutionTime(time.Now(), "interval", job.TimeInterval, job.CronExpression, job.SpecificSchedule)
+		if err != nil {
+			h.logger.Errorf("[getNextExecutionTimestamp] Error calculating next execution timestamp: %v", err)
+			nextExecutionTimestamp = time.Now().Add(time.Duration(job.TimeInterval) * time.Second)
+		}
+
+		timeJobData := commonTypes.TimeJobData{
+			JobID:            commonTypes.NewBigInt(jobID),
+			TaskDefinitionID: job.TaskDefinitionID,
+			ExpirationTime:   expirationTime,
+			// Recurring:                 job.Recurring,
+			TimeInterval:              job.TimeInterval,
+			ScheduleType:              "interval",
+			CronExpression:            job.CronExpression,
+			SpecificSchedule:          job.SpecificSchedule,
+			NextExecutionTimestamp:    nextExecutionTimestamp,
+			TargetChainID:             job.TargetChainID,
+			TargetContractAddress:     job.TargetContractAddress,
+			TargetFunction:            job.TargetFunction,
+			ABI:                       job.ABI,
+			ArgType:                   job.ArgType,
+			Arguments:                 job.Arguments,
+			DynamicArgumentsScriptUrl: job.DynamicArgumentsScriptUrl,
+			IsCompleted:               false,
+			IsActive:                  true,
+		}
+
+		// Track time job creation
+		trackDBOp = metrics.TrackDBOperation("create", "time_jobs")
+		if err := h.timeJobRepository.CreateTimeJob(&timeJobData); err != nil {
+			trackDBOp(err)
+			h.logger.Errorf("[CreateJobData] Error inserting time job data for jobID %d: %v", jobID, err)
+			return nil, fmt.Errorf("failed to create time job: %w", err)
+		}
+		trackDBOp(nil)
+		h.logger.Infof("[CreateJobData] Successfully created time-based job %d with interval %d seconds",
+			jobID, timeJobData.TimeInterval)
+
+	case 3, 4:
+		// Event-based job
+		eventJobData := commonTypes.EventJobData{
+			JobID:                     commonTypes.NewBigInt(jobID),
+			TaskDefinitionID:          job.TaskDefinitionID,
+			ExpirationTime:            expirationTime,
+			Recurring:                 job.Recurring,
+			TriggerChainID:            job.TriggerChainID,
+			TriggerContractAddress:    job.TriggerContractAddress,
+			TriggerEvent:              job.TriggerEvent,
+			EventFilterParaName:       job.EventFilterParaName,
+			EventFilterValue:          job.EventFilterValue,
+			TargetChainID:             job.TargetChainID,
+			TargetContractAddress:     job.TargetContractAddress,
+			TargetFunction:            job.TargetFunction,
+			ABI:                       job.ABI,
+			ArgType:                   job.ArgType,
+			Arguments:                 job.Arguments,
+			DynamicArgumentsScriptUrl: job.DynamicArgumentsScriptUrl,
+			IsCompleted:               false,
+			IsActive:                  true,
+		}
+
+		if err := h.eventJobRepository.CreateEventJob(&eventJobData); err != nil {
+			h.logger.Errorf("[CreateJobData] Error inserting event job data for jobID %d: %v", jobID, err)
+			return nil, fmt.Errorf("failed to create event job: %w", err)
+		}
+		scheduleConditionJobData.JobID = commonTypes.NewBigInt(jobID)
+		scheduleConditionJobData.TaskDefinitionID = job.TaskDefinitionID
+		scheduleConditionJobData.LastExecutedAt = time.Now()
+		scheduleConditionJobData.TaskTargetData = commonTypes.TaskTargetData{
+			JobID:                     commonTypes.NewBigInt(jobID),
+			TaskDefinitionID:          job.TaskDefinitionID,
+			TargetChainID:             job.TargetChainID,
+			TargetContractAddress:     job.TargetContractAddress,
+			TargetFunction:            job.TargetFunction,
+			ABI:                       job.ABI,
+			ArgType:                   job.ArgType,
+			Arguments:                 job.Arguments,
+			DynamicArgumentsScriptUrl: job.DynamicArgumentsScriptUrl,
+		}
+		scheduleConditionJobData.EventWorkerData = commonTypes.EventWorkerData{
+			JobID:                  commonTypes.NewBigInt(jobID),
+			ExpirationTime:         expirationTime,
+			Recurring:              job.Recurring,
+			TriggerChainID:         job.TriggerChainID,
+			TriggerContractAddress: job.TriggerContractAddress,
+			TriggerEvent:           job.TriggerEvent,
+			EventFilterParaName:    job.EventFilterParaName,
+			EventFilterValue:       job.EventFilterValue,
+		}
+		filterEnabled := eventJobData.EventFilterParaName != "" && eventJobData.EventFilterValue != ""
+		h.logger.Infof("[CreateJobData] Successfully created event-based job %d for event %s on contract %s (filter_enabled=%t)",
+			jobID, eventJobData.TriggerEvent, eventJobData.TriggerContractAddress, filterEnabled)
+
+	case 5, 6:
+		// Condition-based job
+		conditionJobData := commonTypes.ConditionJobData{
+			JobID:                     commonTypes.NewBigInt(jobID),
+			TaskDefinitionID:          job.TaskDefinitionID,
+			ExpirationTime:            expirationTime,
+			Recurring:                 job.Recurring,
+			ConditionType:             job.ConditionType,
+			UpperLimit:                job.UpperLimit,
+			LowerLimit:                job.LowerLimit,
+			ValueSourceType:           job.ValueSourceType,
+			ValueSourceUrl:            job.ValueSourceUrl,
+			TargetChainID:             job.TargetChainID,
+			TargetContractAddress:     job.TargetContractAddress,
+			TargetFunction:            job.TargetFunction,
+			ABI:                       job.ABI,
+			ArgType:                   job.ArgType,
+			Arguments:                 job.Arguments,
+			DynamicArgumentsScriptUrl: job.DynamicArgumentsScriptUrl,
+			IsCompleted:               false,
+			IsActive:                  true,
+			SelectedKeyRoute:          job.SelectedKeyRoute,
+		}
+
+		if err := h.conditionJobRepository.CreateConditionJob(&conditionJobData); err != nil {
+			h.logger.Errorf("[CreateJobData] Error inserting condition job data for jobID %d: %v", jobID, err)
+			return nil, fmt.Errorf("failed to create condition job: %w", err)
+		}
+		scheduleConditionJobData.JobID = commonTypes.NewBigInt(jobID)
+		scheduleConditionJobData.TaskDefinitionID = job.TaskDefinitionID
+		scheduleConditionJobData.LastExecutedAt = time.Now()
+		scheduleConditionJobData.TaskTargetData = commonTypes.TaskTargetData{
+			JobID:                     commonTypes.NewBigInt(jobID),
+			TaskDefinitionID:          job.TaskDefinitionID,
+			TargetChainID:             job.TargetChainID,
+			TargetContractAddress:     job.TargetContractAddress,
+			TargetFunction:            job.TargetFunction,
+			ABI:                       job.ABI,
+			ArgType:                   job.ArgType,
+			Arguments:                 job.Arguments,
+			DynamicArgumentsScriptUrl: job.DynamicArgumentsScriptUrl,
+		}
+		scheduleConditionJobData.ConditionWorkerData = commonTypes.ConditionWorkerData{
+			JobID:            commonTypes.NewBigInt(jobID),
+			ExpirationTime:   expirationTime,
+			Recurring:        job.Recurring,
+			ConditionType:    job.ConditionType,
+			UpperLimit:       job.UpperLimit,
+			LowerLimit:       job.LowerLimit,
+			ValueSourceType:  job.ValueSourceType,
+			ValueSourceUrl:   job.ValueSourceUrl,
+			SelectedKeyRoute: job.SelectedKeyRoute,
+		}
+		h.logger.Infof("[CreateJobData] Successfully created condition-based job %d with condition type %s (limits: %f-%f)",
+			jobID, conditionJobData.ConditionType, conditionJobData.LowerLimit, conditionJobData.UpperLimit)
+
+	case 7:
+		// Custom script job (TaskDefinitionID = 7)
+		var nextExecutionTime time.Time
+		nextExecutionTime, err := parser.CalculateNextExecutionTime(time.Now(), "interval", job.TimeInterval, "", "")
+		if err != nil {
+			h.logger.Errorf("[CreateJobData] Error calculating next execution time for custom job: %v", err)
+			nextExecutionTime = time.Now().Add(time.Duration(job.TimeInterval) * time.Second)
+		}
+
+		customJobData := commonTypes.CustomJobData{
+			JobID:             commonTypes.NewBigInt(jobID),
+			TargetChainID:     job.TargetChainID,
+			TaskDefinitionID:  7,
+			ExpirationTime:    expirationTime,
+			Recurring:         job.Recurring,
+			CustomScriptUrl:   job.DynamicArgumentsScriptUrl,
+			TimeInterval:      job.TimeInterval,
+			ScriptLanguage:    job.Language,
+			NextExecutionTime: nextExecutionTime,
+			LastExecutedAt:    time.Now(),
+			IsCompleted:       false,
+			IsActive:          true,
+		}
+
+		// Track custom job creation
+		trackDBOp = metrics.TrackDBOperation("create", "custom_jobs")
+		if err := h.customJobRepository.CreateCustomJob(&customJobData); err != nil {
+			trackDBOp(err)
+			h.logger.Errorf("[CreateJobData] Error inserting custom job data for jobID %d: %v", jobID, err)
+			return nil, fmt.Errorf("failed to create custom job: %w", err)
+		}
+		trackDBOp(nil)
+		h.logger.Infof("[CreateJobData] Successfully created custom script job %d with interval %d seconds, language %s",
+			jobID, customJobData.TimeInterval, customJobData.ScriptLanguage)
+
+	default:
+		h.logger.Errorf("[CreateJobData] Invalid task definition ID %d for job %s", job.TaskDefinitionID, jobID)
+		return nil, &jobRequestError{status: http.StatusBadRequest, body: gin.H{"error": "Invalid task definition ID"}}
+	}
+
+	if job.TaskDefinitionID == 3 || job.TaskDefinitionID == 4 || job.TaskDefinitionID == 5 || job.TaskDefinitionID == 6 {
+		success, err := h.notifyConditionScheduler(jobID, scheduleConditionJobData)
+		if !success {
+			h.logger.Errorf("[CreateJobData] Error notifying condition scheduler for jobID %d: %v", jobID, err)
+		} else {
+			h.logger.Infof("[CreateJobData] Successfully notified condition scheduler for jobID %d", jobID)
+		}
+	}
+
+	return jobID, nil
+}
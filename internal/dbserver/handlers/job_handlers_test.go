@@ -29,6 +29,9 @@ func (f *fakeJobRepo) UpdateJobLastExecutedAt(jobID *big.Int, taskID int64, jobC
 	return nil
 }
 func (f *fakeJobRepo) UpdateJobStatus(jobID *big.Int, status string) error { return nil }
+func (f *fakeJobRepo) UpdateActiveJobStatus(jobID *big.Int, current, status dbtypes.JobStatus) error {
+	return nil
+}
 func (f *fakeJobRepo) GetJobByID(jobID *big.Int) (*pkgtypes.JobData, error) {
 	return f.jobByID, f.jobByIDErr
 }
@@ -39,6 +42,25 @@ func (f *fakeJobRepo) GetTaskFeesByJobID(jobID *big.Int) ([]dbtypes.TaskFeeRespo
 func (f *fakeJobRepo) GetJobsByUserIDAndChainID(userID int64, createdChainID string) ([]pkgtypes.JobData, error) {
 	return nil, nil
 }
+func (f *fakeJobRepo) ListJobsByUserID(userID int64, opts dbtypes.ListOptions) (dbtypes.Page[pkgtypes.JobData], error) {
+	return dbtypes.Page[pkgtypes.JobData]{}, nil
+}
+func (f *fakeJobRepo) GetJobLifecycle(jobID *big.Int) (dbtypes.JobLifecycle, error) {
+	return dbtypes.JobLifecycle{Status: dbtypes.JobStatusPending}, nil
+}
+func (f *fakeJobRepo) TransitionJobStatus(jobID *big.Int, current dbtypes.JobLifecycle, status dbtypes.JobStatus) error {
+	return nil
+}
+func (f *fakeJobRepo) RestoreJobLifecycle(jobID *big.Int, status dbtypes.JobStatus, previous dbtypes.JobLifecycle) error {
+	return nil
+}
+func (f *fakeJobRepo) SchedulePause(jobID *big.Int, current dbtypes.JobLifecycle, pauseAt *time.Time) error {
+	return nil
+}
+func (f *fakeJobRepo) GetDueScheduledPauses(now time.Time) ([]dbtypes.ScheduledPause, error) {
+	return nil, nil
+}
+func (f *fakeJobRepo) DeleteScheduledPause(pause dbtypes.ScheduledPause) error { return nil }
 func (f *fakeJobRepo) GetJobsBySafeAddress(safeAddress string) ([]pkgtypes.JobData, error) {
 	return nil, nil
 }
@@ -58,6 +80,9 @@ func (f *fakeApiKeysRepo) GetApiKeyDataByKey(apiKey string) (*pkgtypes.ApiKey, e
 	}
 	return &pkgtypes.ApiKey{Owner: f.owner, IsActive: true}, nil
 }
+func (f *fakeApiKeysRepo) AuthenticateApiKey(apiKey string) (*pkgtypes.ApiKey, error) {
+	return f.GetApiKeyDataByKey(apiKey)
+}
 func (f *fakeApiKeysRepo) GetApiKeyCounters(key string) (*dbtypes.ApiKeyCounters, error) {
 	return nil, nil
 }
@@ -68,7 +93,11 @@ func (f *fakeApiKeysRepo) UpdateApiKeyStatus(req *dbtypes.UpdateApiKeyStatusRequ
 	return nil
 }
 func (f *fakeApiKeysRepo) UpdateApiKeyLastUsed(key string, isSuccess bool) error { return nil }
-func (f *fakeApiKeysRepo) DeleteApiKey(key string) error                         { return nil }
+func (f *fakeApiKeysRepo) UpdateApiKeyLastUsedIP(key, clientIP string) error     { return nil }
+func (f *fakeApiKeysRepo) ReplaceApiKey(key, replacedBy string, expiresAt time.Time) error {
+	return nil
+}
+func (f *fakeApiKeysRepo) DeleteApiKey(key string) error { return nil }
 
 func TestGetJobDataByJobID_ValidationAndRepoError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -0,0 +1,534 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/config"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/events"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// scheduledPauseInterval is how often scheduled pauses that are due are applied
+const scheduledPauseInterval = time.Minute
+
+// errSchedulerSync is returned when the job type data or the schedulers could not be updated
+// to match a job's new status, the job keeps its previous status
+var errSchedulerSync = errors.New("job could not be synced with the schedulers, its status was not changed")
+
+// PauseJob handles PUT /jobs/:job_id/pause
+func (h *Handler) PauseJob(c *gin.Context) {
+	h.changeJobLifecycleFromRequest(c, "PauseJob", types.JobActionPause, "")
+}
+
+// ResumeJob handles PUT /jobs/:job_id/resume, the optional body chooses whether a time job
+// skips the executions it missed while paused (the default) or catches up on them
+func (h *Handler) ResumeJob(c *gin.Context) {
+	var request types.ResumeJobRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.logger.Errorf("[ResumeJob] Invalid request body: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format, mode must be skip or catch_up",
+				"code":  "INVALID_REQUEST",
+			})
+			return
+		}
+	}
+	if request.Mode == "" {
+		request.Mode = types.ResumeSkip
+	}
+	h.changeJobLifecycleFromRequest(c, "ResumeJob", types.JobActionResume, request.Mode)
+}
+
+// CancelJob handles PUT /jobs/:job_id/cancel, a cancelled job can not be resumed
+func (h *Handler) CancelJob(c *gin.Context) {
+	h.changeJobLifecycleFromRequest(c, "CancelJob", types.JobActionCancel, "")
+}
+
+// SchedulePauseJob handles PUT /jobs/:job_id/pause-at, scheduling a pause of an active job,
+// or clearing the scheduled pause when pause_at is null
+func (h *Handler) SchedulePauseJob(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[SchedulePauseJob] trace_id=%s - Scheduling job pause", traceID)
+
+	var request types.SchedulePauseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Errorf("[SchedulePauseJob] Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"code":  "INVALID_REQUEST",
+		})
+		return
+	}
+	if request.PauseAt != nil && !request.PauseAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "pause_at must be in the future",
+			"code":  "INVALID_PAUSE_AT",
+		})
+		return
+	}
+
+	job, lifecycle, ok := h.getJobLifecycle(c, "SchedulePauseJob")
+	if !ok {
+		return
+	}
+	if _, err := types.NextJobStatus(lifecycle.Status, types.JobActionSchedulePause); err != nil {
+		h.respondLifecycleError(c, "SchedulePauseJob", err)
+		return
+	}
+
+	jobID := job.JobID.ToBigInt()
+	trackDBOp := metrics.TrackDBOperation("update", "job_data")
+	err := h.jobRepository.SchedulePause(jobID, lifecycle, request.PauseAt)
+	trackDBOp(err)
+	if err != nil {
+		h.respondLifecycleError(c, "SchedulePauseJob", err)
+		return
+	}
+
+	response := types.JobLifecycleResponse{
+		JobID:     jobID.String(),
+		Action:    types.JobActionSchedulePause,
+		OldStatus: lifecycle.Status,
+		JobLifecycle: types.JobLifecycle{
+			Status:  lifecycle.Status,
+			PauseAt: request.PauseAt,
+		},
+	}
+	h.publishJobLifecycle(job, response)
+
+	h.logger.Infof("[SchedulePauseJob] Scheduled pause of job %s at %v", jobID, request.PauseAt)
+	c.JSON(http.StatusOK, response)
+}
+
+// changeJobLifecycleFromRequest applies a lifecycle action to the job of the request
+func (h *Handler) changeJobLifecycleFromRequest(c *gin.Context, logTag string, action types.JobLifecycleAction, mode types.ResumeMode) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[%s] trace_id=%s - Changing job lifecycle: %s", logTag, traceID, action)
+
+	job, lifecycle, ok := h.getJobLifecycle(c, logTag)
+	if !ok {
+		return
+	}
+
+	response, err := h.changeJobLifecycle(job, lifecycle, action, mode)
+	if err != nil {
+		h.respondLifecycleError(c, logTag, err)
+		return
+	}
+
+	h.logger.Infof("[%s] Job %s is now %s", logTag, response.JobID, response.Status)
+	c.JSON(http.StatusOK, response)
+}
+
+// getJobLifecycle reads the job of the job_id route parameter and its lifecycle
+func (h *Handler) getJobLifecycle(c *gin.Context, logTag string) (*commonTypes.JobData, types.JobLifecycle, bool) {
+	jobID, ok := new(big.Int).SetString(c.Param("job_id"), 10)
+	if !ok {
+		h.logger.Errorf("[%s] Invalid job ID format: %v", logTag, c.Param("job_id"))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid job ID format",
+			"code":  "INVALID_JOB_ID",
+		})
+		return nil, types.JobLifecycle{}, false
+	}
+
+	trackDBOp := metrics.TrackDBOperation("read", "job_data")
+	job, err := h.jobRepository.GetJobByID(jobID)
+	trackDBOp(err)
+	if err != nil {
+		h.logger.Errorf("[%s] Error getting job %s: %v", logTag, jobID, err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Job not found",
+			"code":  "JOB_NOT_FOUND",
+		})
+		return nil, types.JobLifecycle{}, false
+	}
+
+	trackDBOp = metrics.TrackDBOperation("read", "job_data")
+	lifecycle, err := h.jobRepository.GetJobLifecycle(jobID)
+	trackDBOp(err)
+	if err != nil {
+		h.logger.Errorf("[%s] Error getting lifecycle of job %s: %v", logTag, jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get job status",
+			"code":  "JOB_LIFECYCLE_ERROR",
+		})
+		return nil, types.JobLifecycle{}, false
+	}
+	return job, lifecycle, true
+}
+
+// respondLifecycleError answers a lifecycle action the job's status does not allow, or that
+// raced another change of the job, with 409
+func (h *Handler) respondLifecycleError(c *gin.Context, logTag string, err error) {
+	h.logger.Errorf("[%s] %v", logTag, err)
+
+	var invalidTransition *types.ErrInvalidJobTransition
+	switch {
+	case errors.As(err, &invalidTransition):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_JOB_TRANSITION",
+		})
+	case errors.Is(err, repository.ErrJobStatusChanged):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Job status changed concurrently, retry with its current status",
+			"code":  "JOB_STATUS_CHANGED",
+		})
+	case errors.Is(err, errSchedulerSync):
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "SCHEDULER_NOTIFY_ERROR",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to change job status",
+			"code":  "JOB_LIFECYCLE_ERROR",
+		})
+	}
+}
+
+// changeJobLifecycle moves a job to the status the action leads to, then stops or restarts
+// it in its job type table and scheduler, and publishes the change. The status is claimed
+// first so concurrent actions on the job do not both reach the scheduler. If the job cannot
+// be stopped or restarted, its previous lifecycle is restored, so its status keeps matching
+// what its scheduler runs.
+func (h *Handler) changeJobLifecycle(job *commonTypes.JobData, lifecycle types.JobLifecycle, action types.JobLifecycleAction, mode types.ResumeMode) (types.JobLifecycleResponse, error) {
+	status, err := types.NextJobStatus(lifecycle.Status, action)
+	if err != nil {
+		return types.JobLifecycleResponse{}, err
+	}
+
+	jobID := job.JobID.ToBigInt()
+	trackDBOp := metrics.TrackDBOperation("update", "job_data")
+	err = h.jobRepository.TransitionJobStatus(jobID, lifecycle, status)
+	trackDBOp(err)
+	if err != nil {
+		return types.JobLifecycleResponse{}, err
+	}
+
+	response := types.JobLifecycleResponse{
+		JobID:        jobID.String(),
+		Action:       action,
+		OldStatus:    lifecycle.Status,
+		JobLifecycle: types.JobLifecycle{Status: status},
+	}
+	if status == types.JobStatusPaused {
+		now := time.Now()
+		response.PausedAt = &now
+	}
+
+	if action == types.JobActionResume {
+		response.ResumeMode = mode
+		response.NextExecutionTimestamp, err = h.startJob(job, mode)
+	} else if lifecycle.Status != types.JobStatusPaused {
+		// A paused job is already stopped
+		err = h.stopJob(job)
+	}
+	if err != nil {
+		trackDBOp = metrics.TrackDBOperation("update", "job_data")
+		restoreErr := h.jobRepository.RestoreJobLifecycle(jobID, status, lifecycle)
+		trackDBOp(restoreErr)
+		if restoreErr != nil {
+			h.logger.Errorf("[JobLifecycle] Failed to restore status %s of job %s: %v", lifecycle.Status, jobID, restoreErr)
+			err = errors.Join(err, restoreErr)
+		}
+		return types.JobLifecycleResponse{}, fmt.Errorf("%w: job %s: %v", errSchedulerSync, jobID, err)
+	}
+
+	h.publishJobLifecycle(job, response)
+	return response, nil
+}
+
+// stopJob deactivates a job in its job type table and stops its scheduler from running it.
+// A job its scheduler could not be told about is activated again, as the scheduler still
+// runs it.
+func (h *Handler) stopJob(job *commonTypes.JobData) error {
+	if err := h.setJobTypeStatus(job, false); err != nil {
+		return err
+	}
+
+	jobID := job.JobID.ToBigInt()
+	var err error
+	switch job.TaskDefinitionID {
+	case 1, 2:
+		_, err = h.notifyTimeScheduler("/api/v1/job/pause", jobID)
+	case 3, 4, 5, 6:
+		_, err = h.notifyPauseToConditionScheduler(jobID)
+	}
+	if err != nil {
+		return errors.Join(err, h.setJobTypeStatus(job, true))
+	}
+	return nil
+}
+
+// setJobTypeStatus activates or deactivates a job in its job type table
+func (h *Handler) setJobTypeStatus(job *commonTypes.JobData, isActive bool) error {
+	jobID := job.JobID.ToBigInt()
+
+	switch job.TaskDefinitionID {
+	case 1, 2:
+		trackDBOp := metrics.TrackDBOperation("update", "time_job")
+		err := h.timeJobRepository.UpdateTimeJobStatus(jobID, isActive)
+		trackDBOp(err)
+		return err
+
+	case 3, 4:
+		trackDBOp := metrics.TrackDBOperation("update", "event_job")
+		err := h.eventJobRepository.UpdateEventJobStatus(jobID, isActive)
+		trackDBOp(err)
+		return err
+
+	case 5, 6:
+		trackDBOp := metrics.TrackDBOperation("update", "condition_job")
+		err := h.conditionJobRepository.UpdateConditionJobStatus(jobID, isActive)
+		trackDBOp(err)
+		return err
+
+	case 7:
+		trackDBOp := metrics.TrackDBOperation("update", "custom_jobs")
+		err := h.customJobRepository.UpdateCustomJobStatus(jobID, isActive, false)
+		trackDBOp(err)
+		return err
+	}
+	return fmt.Errorf("unknown task definition ID %d", job.TaskDefinitionID)
+}
+
+// startJob reactivates a paused job in its job type table and has its scheduler run it
+// again. Time and custom jobs get the next execution time the resume mode gives, which is
+// returned. A job its scheduler could not be told about is deactivated again.
+func (h *Handler) startJob(job *commonTypes.JobData, mode types.ResumeMode) (*time.Time, error) {
+	jobID := job.JobID.ToBigInt()
+	now := time.Now()
+	lookAhead := time.Duration(config.GetPollingLookAhead()) * time.Second
+
+	switch job.TaskDefinitionID {
+	case 1, 2:
+		timeJob, err := h.timeJobRepository.GetTimeJobByJobID(jobID)
+		if err != nil {
+			return nil, err
+		}
+		next := resumeExecutionTime(timeJob.NextExecutionTimestamp, now, lookAhead, mode, timeJob.ScheduleType, timeJob.TimeInterval)
+		trackDBOp := metrics.TrackDBOperation("update", "time_job")
+		err = h.timeJobRepository.UpdateTimeJobNextExecutionTimestamp(jobID, next)
+		if err == nil {
+			err = h.timeJobRepository.UpdateTimeJobStatus(jobID, true)
+		}
+		trackDBOp(err)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := h.notifyTimeScheduler("/api/v1/job/resume", jobID); err != nil {
+			return nil, errors.Join(err, h.setJobTypeStatus(job, false))
+		}
+		return &next, nil
+
+	case 3, 4:
+		eventJob, err := h.eventJobRepository.GetEventJobByJobID(jobID)
+		if err != nil {
+			return nil, err
+		}
+		trackDBOp := metrics.TrackDBOperation("update", "event_job")
+		err = h.eventJobRepository.UpdateEventJobStatus(jobID, true)
+		trackDBOp(err)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := h.notifyConditionScheduler(jobID, eventJobScheduleData(job, &eventJob)); err != nil {
+			return nil, errors.Join(err, h.setJobTypeStatus(job, false))
+		}
+		return nil, nil
+
+	case 5, 6:
+		conditionJob, err := h.conditionJobRepository.GetConditionJobByJobID(jobID)
+		if err != nil {
+			return nil, err
+		}
+		trackDBOp := metrics.TrackDBOperation("update", "condition_job")
+		err = h.conditionJobRepository.UpdateConditionJobStatus(jobID, true)
+		trackDBOp(err)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := h.notifyConditionScheduler(jobID, conditionJobScheduleData(job, &conditionJob)); err != nil {
+			return nil, errors.Join(err, h.setJobTypeStatus(job, false))
+		}
+		return nil, nil
+
+	case 7:
+		customJob, err := h.customJobRepository.GetCustomJobByID(jobID)
+		if err != nil {
+			return nil, err
+		}
+		next := resumeExecutionTime(customJob.NextExecutionTime, now, lookAhead, mode, "interval", customJob.TimeInterval)
+		trackDBOp := metrics.TrackDBOperation("update", "custom_jobs")
+		err = h.customJobRepository.UpdateNextExecutionTime(jobID, next, customJob.LastExecutedAt)
+		if err == nil {
+			err = h.customJobRepository.UpdateCustomJobStatus(jobID, true, false)
+		}
+		trackDBOp(err)
+		if err != nil {
+			return nil, err
+		}
+		return &next, nil
+	}
+	return nil, fmt.Errorf("unknown task definition ID %d", job.TaskDefinitionID)
+}
+
+// resumeExecutionTime returns the next execution time of a job resumed at now. The time
+// scheduler only picks up executions at least a poll look-ahead away, so earlier ones are
+// missed: ResumeCatchUp runs the job once at the earliest time the scheduler picks up, and
+// ResumeSkip moves to the first execution on the job's schedule from then. A next execution
+// that was not missed is kept, unless ResumeNow runs the job at the earliest time regardless.
+func resumeExecutionTime(next, now time.Time, lookAhead time.Duration, mode types.ResumeMode, scheduleType string, timeInterval int64) time.Time {
+	earliest := now.Add(lookAhead)
+	if mode == types.ResumeNow {
+		return earliest
+	}
+	if !next.Before(earliest) {
+		return next
+	}
+	if mode == types.ResumeCatchUp || scheduleType != "interval" || timeInterval <= 0 || next.IsZero() {
+		return earliest
+	}
+
+	interval := time.Duration(timeInterval) * time.Second
+	missed := (earliest.Sub(next) + interval - 1) / interval
+	return next.Add(missed * interval)
+}
+
+// eventJobScheduleData is the data the condition scheduler starts an event job's worker with
+func eventJobScheduleData(job *commonTypes.JobData, eventJob *commonTypes.EventJobData) commonTypes.ScheduleConditionJobData {
+	return commonTypes.ScheduleConditionJobData{
+		JobID:            job.JobID,
+		TaskDefinitionID: job.TaskDefinitionID,
+		LastExecutedAt:   time.Now(),
+		TaskTargetData: commonTypes.TaskTargetData{
+			JobID:                     job.JobID,
+			TaskDefinitionID:          job.TaskDefinitionID,
+			TargetChainID:             eventJob.TargetChainID,
+			TargetContractAddress:     eventJob.TargetContractAddress,
+			TargetFunction:            eventJob.TargetFunction,
+			ABI:                       eventJob.ABI,
+			ArgType:                   eventJob.ArgType,
+			Arguments:                 eventJob.Arguments,
+			DynamicArgumentsScriptUrl: eventJob.DynamicArgumentsScriptUrl,
+		},
+		EventWorkerData: commonTypes.EventWorkerData{
+			JobID:                  job.JobID,
+			ExpirationTime:         eventJob.ExpirationTime,
+			Recurring:              eventJob.Recurring,
+			TriggerChainID:         eventJob.TriggerChainID,
+			TriggerContractAddress: eventJob.TriggerContractAddress,
+			TriggerEvent:           eventJob.TriggerEvent,
+			EventFilterParaName:    eventJob.EventFilterParaName,
+			EventFilterValue:       eventJob.EventFilterValue,
+		},
+		IsImua: job.IsImua,
+	}
+}
+
+// conditionJobScheduleData is the data the condition scheduler starts a condition job's
+// worker with
+func conditionJobScheduleData(job *commonTypes.JobData, conditionJob *commonTypes.ConditionJobData) commonTypes.ScheduleConditionJobData {
+	return commonTypes.ScheduleConditionJobData{
+		JobID:            job.JobID,
+		TaskDefinitionID: job.TaskDefinitionID,
+		LastExecutedAt:   time.Now(),
+		TaskTargetData: commonTypes.TaskTargetData{
+			JobID:                     job.JobID,
+			TaskDefinitionID:          job.TaskDefinitionID,
+			TargetChainID:             conditionJob.TargetChainID,
+			TargetContractAddress:     conditionJob.TargetContractAddress,
+			TargetFunction:            conditionJob.TargetFunction,
+			ABI:                       conditionJob.ABI,
+			ArgType:                   conditionJob.ArgType,
+			Arguments:                 conditionJob.Arguments,
+			DynamicArgumentsScriptUrl: conditionJob.DynamicArgumentsScriptUrl,
+		},
+		ConditionWorkerData: commonTypes.ConditionWorkerData{
+			JobID:            job.JobID,
+			ExpirationTime:   conditionJob.ExpirationTime,
+			Recurring:        conditionJob.Recurring,
+			ConditionType:    conditionJob.ConditionType,
+			UpperLimit:       conditionJob.UpperLimit,
+			LowerLimit:       conditionJob.LowerLimit,
+			ValueSourceType:  conditionJob.ValueSourceType,
+			ValueSourceUrl:   conditionJob.ValueSourceUrl,
+			SelectedKeyRoute: conditionJob.SelectedKeyRoute,
+		},
+		IsImua: job.IsImua,
+	}
+}
+
+// publishJobLifecycle publishes a lifecycle change of a job on the websocket hub
+func (h *Handler) publishJobLifecycle(job *commonTypes.JobData, response types.JobLifecycleResponse) {
+	if h.publisher == nil {
+		return
+	}
+	h.publisher.PublishJobLifecycleChanged(&events.JobLifecycleChangedEvent{
+		JobID:                  response.JobID,
+		Action:                 string(response.Action),
+		OldStatus:              string(response.OldStatus),
+		NewStatus:              string(response.Status),
+		PauseAt:                response.PauseAt,
+		ResumeMode:             string(response.ResumeMode),
+		NextExecutionTimestamp: response.NextExecutionTimestamp,
+		UserID:                 strconv.FormatInt(job.UserID, 10),
+	})
+}
+
+// StartScheduledPauseLoop periodically pauses the jobs whose scheduled pause is due
+func (h *Handler) StartScheduledPauseLoop() {
+	ticker := time.NewTicker(scheduledPauseInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.applyScheduledPauses(time.Now())
+	}
+}
+
+// applyScheduledPauses pauses the jobs whose scheduled pause is due at now. Pauses the job
+// no longer has, because it was rescheduled or changed status, are dropped.
+func (h *Handler) applyScheduledPauses(now time.Time) {
+	pauses, err := h.jobRepository.GetDueScheduledPauses(now)
+	if err != nil {
+		h.logger.Errorf("[ScheduledPauses] Failed to get due scheduled pauses: %v", err)
+		return
+	}
+
+	for _, pause := range pauses {
+		lifecycle, err := h.jobRepository.GetJobLifecycle(pause.JobID)
+		if err != nil {
+			h.logger.Errorf("[ScheduledPauses] Failed to get lifecycle of job %s: %v", pause.JobID, err)
+			continue
+		}
+		if lifecycle.PauseAt == nil || !lifecycle.PauseAt.Equal(pause.PauseAt) || !lifecycle.Status.IsActive() {
+			if err := h.jobRepository.DeleteScheduledPause(pause); err != nil {
+				h.logger.Errorf("[ScheduledPauses] Failed to drop stale scheduled pause of job %s: %v", pause.JobID, err)
+			}
+			continue
+		}
+
+		job, err := h.jobRepository.GetJobByID(pause.JobID)
+		if err != nil {
+			h.logger.Errorf("[ScheduledPauses] Failed to get job %s: %v", pause.JobID, err)
+			continue
+		}
+		if _, err := h.changeJobLifecycle(job, lifecycle, types.JobActionPause, ""); err != nil {
+			h.logger.Errorf("[ScheduledPauses] Failed to pause job %s: %v", pause.JobID, err)
+			continue
+		}
+		h.logger.Infof("[ScheduledPauses] Paused job %s as scheduled at %s", pause.JobID, pause.PauseAt.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func TestNextJobStatus(t *testing.T) {
+	tests := []struct {
+		status   types.JobStatus
+		action   types.JobLifecycleAction
+		expected types.JobStatus
+		valid    bool
+	}{
+		{types.JobStatusRunning, types.JobActionPause, types.JobStatusPaused, true},
+		{types.JobStatusPending, types.JobActionSchedulePause, types.JobStatusPending, true},
+		{types.JobStatusPaused, types.JobActionResume, types.JobStatusPending, true},
+		{types.JobStatusPaused, types.JobActionCancel, types.JobStatusCancelled, true},
+		{types.JobStatusInQueue, types.JobActionCancel, types.JobStatusCancelled, true},
+		{types.JobStatusPaused, types.JobActionPause, "", false},
+		{types.JobStatusPaused, types.JobActionSchedulePause, "", false},
+		{types.JobStatusRunning, types.JobActionResume, "", false},
+		{types.JobStatusCancelled, types.JobActionResume, "", false},
+		{types.JobStatusDeleted, types.JobActionCancel, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status)+"/"+string(tt.action), func(t *testing.T) {
+			status, err := types.NextJobStatus(tt.status, tt.action)
+			if !tt.valid {
+				var invalid *types.ErrInvalidJobTransition
+				assert.ErrorAs(t, err, &invalid)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, status)
+		})
+	}
+}
+
+func TestResumeExecutionTime(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	lookAhead := 40 * time.Second
+	earliest := now.Add(lookAhead)
+
+	// An execution that was not missed is kept
+	future := now.Add(time.Hour)
+	assert.Equal(t, future, resumeExecutionTime(future, now, lookAhead, types.ResumeSkip, "interval", 60))
+
+	// Catching up runs the missed execution as soon as the scheduler picks it up
+	missed := now.Add(-10 * time.Minute)
+	assert.Equal(t, earliest, resumeExecutionTime(missed, now, lookAhead, types.ResumeCatchUp, "interval", 60))
+
+	// Skipping moves to the next execution on the interval schedule
+	next := resumeExecutionTime(missed, now, lookAhead, types.ResumeSkip, "interval", 300)
+	assert.Equal(t, now.Add(5*time.Minute), next)
+	next = resumeExecutionTime(now.Add(-10*time.Second), now, lookAhead, types.ResumeSkip, "interval", 30)
+	assert.Equal(t, now.Add(50*time.Second), next)
+
+	// Workflow runs start the job right away even before its next execution
+	assert.Equal(t, earliest, resumeExecutionTime(future, now, lookAhead, types.ResumeNow, "interval", 60))
+
+	// Schedules without an interval resume as soon as possible
+	assert.Equal(t, earliest, resumeExecutionTime(missed, now, lookAhead, types.ResumeSkip, "cron", 0))
+}
+
+func TestChangeJobLifecycleRestoresStatus(t *testing.T) {
+	jobID := big.NewInt(42)
+	job := &commonTypes.JobData{JobID: commonTypes.NewBigInt(jobID), TaskDefinitionID: 1}
+	pauseAt := time.Now().Add(time.Hour)
+	lifecycle := types.JobLifecycle{Status: types.JobStatusRunning, PauseAt: &pauseAt}
+
+	tests := []struct {
+		name       string
+		restoreErr error
+	}{
+		{name: "status restored"},
+		{name: "restore fails", restoreErr: errors.New("restore failed")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobRepo := new(MockJobRepository)
+			timeJobRepo := new(MockTimeJobRepository)
+			h := &Handler{jobRepository: jobRepo, timeJobRepository: timeJobRepo, logger: &MockLogger{}}
+
+			jobRepo.On("TransitionJobStatus", jobID, lifecycle, types.JobStatusPaused).Return(nil)
+			timeJobRepo.On("UpdateTimeJobStatus", jobID, false).Return(errors.New("write failed"))
+			jobRepo.On("RestoreJobLifecycle", jobID, types.JobStatusPaused, lifecycle).Return(tt.restoreErr)
+
+			_, err := h.changeJobLifecycle(job, lifecycle, types.JobActionPause, "")
+			assert.ErrorIs(t, err, errSchedulerSync)
+			if tt.restoreErr != nil {
+				assert.ErrorContains(t, err, "restore failed")
+			}
+			jobRepo.AssertExpectations(t)
+			timeJobRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,571 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/middleware"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	dockertypes "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// manifestJobState is an existing job of a user and its description in a manifest
+type manifestJobState struct {
+	job      *commonTypes.JobData
+	manifest types.ManifestJob
+}
+
+// ExportJobManifest handles GET /jobs/manifest, the manifest of the caller's active and
+// paused jobs. It is YAML with ?format=yaml and JSON otherwise.
+func (h *Handler) ExportJobManifest(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[ExportJobManifest] trace_id=%s - Exporting job manifest", traceID)
+
+	caller := strings.ToLower(c.GetString(middleware.WalletAddressKey))
+	if caller == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token is required"})
+		return
+	}
+	h.exportJobManifest(c, "ExportJobManifest", caller)
+}
+
+// ExportJobManifestByApiKey handles GET /jobs/manifest/by-apikey, the manifest of the jobs of
+// the owner of the X-Api-Key
+func (h *Handler) ExportJobManifestByApiKey(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[ExportJobManifestByApiKey] trace_id=%s - Exporting job manifest by API key", traceID)
+
+	apiKeyData, err := h.apiKeysRepository.AuthenticateApiKey(c.GetHeader("X-Api-Key"))
+	if err != nil {
+		h.logger.Errorf("[ExportJobManifestByApiKey] Invalid API key: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		return
+	}
+	if apiKeyData.Owner == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No owner found for API key"})
+		return
+	}
+	h.exportJobManifest(c, "ExportJobManifestByApiKey", strings.ToLower(apiKeyData.Owner))
+}
+
+func (h *Handler) exportJobManifest(c *gin.Context, logTag, userAddress string) {
+	manifest := types.JobManifest{Version: types.JobManifestVersion, Jobs: []types.ManifestJob{}}
+
+	trackDBOp := metrics.TrackDBOperation("read", "user_data")
+	userID, err := h.userRepository.GetUserIDByAddress(userAddress)
+	trackDBOp(err)
+	if errors.Is(err, gocql.ErrNotFound) {
+		h.respondJobManifest(c, manifest)
+		return
+	}
+	if err != nil {
+		h.logger.Errorf("[%s] Error getting user ID for address %s: %v", logTag, userAddress, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	jobs, err := h.manifestJobsOfUser(userID)
+	if err != nil {
+		h.logger.Errorf("[%s] Error reading jobs of user %d: %v", logTag, userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve jobs",
+			"code":  "JOB_RETRIEVAL_ERROR",
+		})
+		return
+	}
+	for _, job := range jobs {
+		status := types.JobStatus(job.job.Status)
+		if status.IsActive() || status == types.JobStatusPaused {
+			manifest.Jobs = append(manifest.Jobs, job.manifest)
+		}
+	}
+
+	h.logger.Infof("[%s] Exported %d jobs of user %d", logTag, len(manifest.Jobs), userID)
+	h.respondJobManifest(c, manifest)
+}
+
+func (h *Handler) respondJobManifest(c *gin.Context, manifest types.JobManifest) {
+	if c.Query("format") == "yaml" {
+		c.YAML(http.StatusOK, manifest)
+		return
+	}
+	c.JSON(http.StatusOK, manifest)
+}
+
+// ApplyJobManifest handles POST /jobs/manifest/apply, converging the caller's jobs on a YAML or
+// JSON manifest: jobs it adds are created, jobs it changes are updated, paused or resumed, and
+// active jobs it leaves out are deactivated. With ?dry_run=true only the plan is returned.
+// Jobs of workflows are paused and resumed by their workflow runs, so they are not deactivated.
+func (h *Handler) ApplyJobManifest(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[ApplyJobManifest] trace_id=%s - Applying job manifest", traceID)
+
+	caller := strings.ToLower(c.GetString(middleware.WalletAddressKey))
+	if caller == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token is required"})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "dry_run must be true or false",
+			"code":  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+		return
+	}
+	manifest, err := types.ParseJobManifest(body, c.ContentType())
+	if err == nil {
+		err = manifest.Validate()
+	}
+	if err != nil {
+		h.logger.Errorf("[ApplyJobManifest] Invalid manifest: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_MANIFEST",
+		})
+		return
+	}
+
+	trackDBOp := metrics.TrackDBOperation("read", "users")
+	_, user, err := h.userRepository.GetUserDataByAddress(caller)
+	trackDBOp(err)
+	userExists := err == nil
+	if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+		h.logger.Errorf("[ApplyJobManifest] Error getting user for address %s: %v", caller, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	current := map[string]manifestJobState{}
+	if userExists {
+		jobs, err := h.manifestJobsOfUser(user.UserID)
+		if err != nil {
+			h.logger.Errorf("[ApplyJobManifest] Error reading jobs of user %d: %v", user.UserID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to retrieve jobs",
+				"code":  "JOB_RETRIEVAL_ERROR",
+			})
+			return
+		}
+		for _, job := range jobs {
+			current[job.manifest.JobID] = job
+		}
+	}
+
+	plan, err := h.planJobManifest(manifest, current)
+	if err != nil {
+		h.logger.Errorf("[ApplyJobManifest] Error planning manifest of user %s: %v", caller, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if len(plan.Conflicts) > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "The manifest conflicts with existing jobs",
+			"code":  "MANIFEST_CONFLICT",
+			"plan":  plan,
+		})
+		return
+	}
+
+	response := types.JobManifestApplyResponse{DryRun: dryRun, Plan: plan}
+	if dryRun {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if !userExists && len(plan.Changes) > 0 {
+		trackDBOp = metrics.TrackDBOperation("create", "users")
+		user, err = h.userRepository.CreateNewUser(&types.CreateUserDataRequest{
+			UserAddress:  caller,
+			EtherBalance: commonTypes.NewBigInt(big.NewInt(0)),
+			TokenBalance: commonTypes.NewBigInt(big.NewInt(0)),
+		})
+		trackDBOp(err)
+		if err != nil {
+			h.logger.Errorf("[ApplyJobManifest] Error creating user for address %s: %v", caller, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	response.Applied, err = h.applyJobManifest(c.Request.Context(), &user, manifest, current, plan)
+	if err != nil {
+		h.logger.Errorf("[ApplyJobManifest] Applied %d of %d changes for user %s: %v", response.Applied, len(plan.Changes), caller, err)
+		response.Error = err.Error()
+		c.JSON(manifestApplyErrorStatus(err), response)
+		return
+	}
+
+	h.logger.Infof("[ApplyJobManifest] Applied %d changes for user %s", response.Applied, caller)
+	c.JSON(http.StatusOK, response)
+}
+
+// manifestApplyErrorStatus is the status of a failed manifest application, that of a job
+// creation failure the client can fix when it is one
+func manifestApplyErrorStatus(err error) int {
+	var requestErr *jobRequestError
+	if errors.As(err, &requestErr) {
+		if requestErr.status == http.StatusOK {
+			// The job's script failed validation
+			return http.StatusUnprocessableEntity
+		}
+		return requestErr.status
+	}
+	if _, ok := dockertypes.AsQuotaExceeded(err); ok {
+		return http.StatusTooManyRequests
+	}
+	if errors.Is(err, repository.ErrJobIDTaken) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// manifestJobsOfUser reads every job of a user with its type-specific data
+func (h *Handler) manifestJobsOfUser(userID int64) ([]manifestJobState, error) {
+	var jobs []manifestJobState
+	opts := types.ListOptions{Limit: types.MaxListLimit, Order: types.SortAsc}
+	for {
+		trackDBOp := metrics.TrackDBOperation("read", "job_data")
+		page, err := h.jobRepository.ListJobsByUserID(userID, opts)
+		trackDBOp(err)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range page.Items {
+			jobData := page.Items[i]
+			var manifestJob types.ManifestJob
+			if jobData.TaskDefinitionID == 7 {
+				trackDBOp = metrics.TrackDBOperation("read", "custom_jobs")
+				customJob, err := h.customJobRepository.GetCustomJobByID(jobData.JobID.ToBigInt())
+				trackDBOp(err)
+				if err != nil {
+					return nil, fmt.Errorf("error getting custom job data for jobID %s: %w", jobData.JobID, err)
+				}
+				manifestJob = types.NewManifestJob(types.JobResponse{JobData: jobData}, customJob)
+			} else {
+				response, err := h.jobResponse(jobData)
+				if err != nil {
+					return nil, err
+				}
+				manifestJob = types.NewManifestJob(response, nil)
+			}
+			jobs = append(jobs, manifestJobState{job: &jobData, manifest: manifestJob})
+		}
+
+		if !page.HasMore {
+			return jobs, nil
+		}
+		opts.Cursor = page.NextCursor
+	}
+}
+
+// planJobManifest diffs a manifest against the current jobs of its user. Jobs are created
+// before the jobs they link to, and deactivations come last.
+func (h *Handler) planJobManifest(manifest types.JobManifest, current map[string]manifestJobState) (types.JobManifestPlan, error) {
+	plan := types.JobManifestPlan{Changes: []types.JobManifestChange{}, Unchanged: []string{}}
+	conflict := func(jobID, format string, args ...interface{}) {
+		plan.Conflicts = append(plan.Conflicts, types.JobManifestConflict{JobID: jobID, Error: fmt.Sprintf(format, args...)})
+	}
+
+	declared := make(map[string]bool, len(manifest.Jobs))
+	for _, job := range manifest.Jobs {
+		declared[job.JobID] = true
+	}
+
+	var creates []types.ManifestJob
+	var updates, lifecycle []types.JobManifestChange
+	for _, job := range manifest.Jobs {
+		if job.LinkJobID != "" && !declared[job.LinkJobID] {
+			if _, ok := current[job.LinkJobID]; !ok {
+				conflict(job.JobID, "links to job %s, which is not a job of the user", job.LinkJobID)
+				continue
+			}
+		}
+
+		existing, ok := current[job.JobID]
+		if !ok {
+			jobID, _ := new(big.Int).SetString(job.JobID, 10)
+			trackDBOp := metrics.TrackDBOperation("read", "job_data")
+			taken, err := h.jobRepository.GetJobByID(jobID)
+			trackDBOp(err)
+			// A job without a status is the ID claim of a create that was interrupted, creating
+			// the job claims it again when it is the user's
+			if err == nil && taken.Status != "" {
+				conflict(job.JobID, "job ID is taken by a job of another user")
+				continue
+			}
+			if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+				return plan, err
+			}
+			creates = append(creates, job)
+			continue
+		}
+
+		status := types.JobStatus(existing.job.Status)
+		if !status.IsActive() && status != types.JobStatusPaused {
+			conflict(job.JobID, "job is %s", status)
+			continue
+		}
+
+		var mutable, immutable []string
+		for _, field := range types.ChangedFields(job, existing.manifest) {
+			if job.IsMutable(field) {
+				mutable = append(mutable, field)
+			} else {
+				immutable = append(immutable, field)
+			}
+		}
+		if len(immutable) > 0 {
+			conflict(job.JobID, "%s can not be changed once the job is created", strings.Join(immutable, ", "))
+			continue
+		}
+
+		var action types.JobManifestAction
+		if job.Paused && status.IsActive() {
+			action = types.ManifestActionPause
+		} else if !job.Paused && status == types.JobStatusPaused {
+			action = types.ManifestActionResume
+		}
+		if action != "" {
+			inWorkflow, err := h.isWorkflowJob(existing.job.JobID.ToBigInt())
+			if err != nil {
+				return plan, err
+			}
+			if inWorkflow {
+				conflict(job.JobID, "job is part of a workflow, whose runs pause and resume it")
+				continue
+			}
+			lifecycle = append(lifecycle, types.JobManifestChange{JobID: job.JobID, Action: action})
+		}
+		if len(mutable) > 0 {
+			updates = append(updates, types.JobManifestChange{JobID: job.JobID, Action: types.ManifestActionUpdate, Fields: mutable})
+		}
+		if action == "" && len(mutable) == 0 {
+			plan.Unchanged = append(plan.Unchanged, job.JobID)
+		}
+	}
+
+	for _, job := range orderManifestCreates(creates) {
+		plan.Changes = append(plan.Changes, types.JobManifestChange{JobID: job.JobID, Action: types.ManifestActionCreate})
+	}
+	plan.Changes = append(plan.Changes, updates...)
+	plan.Changes = append(plan.Changes, lifecycle...)
+
+	var left []manifestJobState
+	for jobID, existing := range current {
+		if !declared[jobID] && types.JobStatus(existing.job.Status).IsActive() {
+			left = append(left, existing)
+		}
+	}
+	sort.Slice(left, func(i, j int) bool {
+		return left[i].job.JobID.ToBigInt().Cmp(left[j].job.JobID.ToBigInt()) < 0
+	})
+	for _, existing := range left {
+		inWorkflow, err := h.isWorkflowJob(existing.job.JobID.ToBigInt())
+		if err != nil {
+			return plan, err
+		}
+		if !inWorkflow {
+			plan.Changes = append(plan.Changes, types.JobManifestChange{JobID: existing.manifest.JobID, Action: types.ManifestActionDeactivate})
+		}
+	}
+	return plan, nil
+}
+
+// orderManifestCreates orders new jobs so that a job is created after the new job it links
+// to, as job creation requests are
+func orderManifestCreates(creates []types.ManifestJob) []types.ManifestJob {
+	pending := make(map[string]bool, len(creates))
+	for _, job := range creates {
+		pending[job.JobID] = true
+	}
+
+	// The manifest's links do not loop, so every pass creates at least one job
+	ordered := make([]types.ManifestJob, 0, len(creates))
+	for len(ordered) < len(creates) {
+		for _, job := range creates {
+			if pending[job.JobID] && !pending[job.LinkJobID] {
+				ordered = append(ordered, job)
+				delete(pending, job.JobID)
+			}
+		}
+	}
+	return ordered
+}
+
+// isWorkflowJob reports whether a job is part of a workflow
+func (h *Handler) isWorkflowJob(jobID *big.Int) (bool, error) {
+	trackDBOp := metrics.TrackDBOperation("read", "workflow_jobs")
+	_, err := h.workflowRepository.GetWorkflowIDByJobID(jobID)
+	trackDBOp(err)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// applyJobManifest makes the changes of a plan in order, returning how many were made. Changes
+// are not rolled back when one fails: applying the manifest again plans against the jobs as
+// the made changes left them, so it resumes from the failed change.
+func (h *Handler) applyJobManifest(ctx context.Context, user *commonTypes.UserData, manifest types.JobManifest, current map[string]manifestJobState, plan types.JobManifestPlan) (int, error) {
+	declared := make(map[string]types.ManifestJob, len(manifest.Jobs))
+	linked := make(map[string]bool)
+	for _, job := range manifest.Jobs {
+		declared[job.JobID] = job
+		linked[job.LinkJobID] = true
+	}
+	for _, existing := range current {
+		linked[existing.manifest.LinkJobID] = true
+	}
+
+	for i, change := range plan.Changes {
+		var err error
+		switch change.Action {
+		case types.ManifestActionCreate:
+			err = h.createManifestJob(ctx, user, declared[change.JobID], linked[change.JobID])
+		case types.ManifestActionUpdate:
+			err = h.updateManifestJob(current[change.JobID].job, declared[change.JobID], change.Fields)
+		case types.ManifestActionPause, types.ManifestActionDeactivate:
+			err = h.setManifestJobLifecycle(current[change.JobID].job, types.JobActionPause)
+		case types.ManifestActionResume:
+			err = h.setManifestJobLifecycle(current[change.JobID].job, types.JobActionResume)
+		}
+		if err != nil {
+			return i, fmt.Errorf("failed to %s job %s: %w", change.Action, change.JobID, err)
+		}
+	}
+	return len(plan.Changes), nil
+}
+
+// createManifestJob creates a job of a manifest like a job creation request does, paused when
+// the manifest declares it so. A job another job links to is not the head of its chain.
+func (h *Handler) createManifestJob(ctx context.Context, user *commonTypes.UserData, job types.ManifestJob, isLinked bool) error {
+	var linkJobID *big.Int
+	if job.LinkJobID != "" {
+		linkJobID, _ = new(big.Int).SetString(job.LinkJobID, 10)
+	}
+	chainStatus := 0
+	if isLinked {
+		chainStatus = 1
+	}
+
+	jobID, err := h.createJob(ctx, *user, job.CreateJobData(user.UserAddress), linkJobID, chainStatus)
+	if err != nil {
+		return err
+	}
+
+	pointsToAdd := 10.0
+	if job.Custom {
+		pointsToAdd = 20.0
+	}
+	user.UserPoints += pointsToAdd
+	trackDBOp := metrics.TrackDBOperation("update", "users")
+	err = h.userRepository.UpdateUserTasksAndPoints(user.UserID, user.TotalTasks, user.UserPoints)
+	trackDBOp(err)
+	if err != nil {
+		return fmt.Errorf("failed to update user points: %w", err)
+	}
+
+	user.JobIDs = append(user.JobIDs, commonTypes.NewBigInt(jobID))
+	jobIDs := make([]*big.Int, len(user.JobIDs))
+	for i, id := range user.JobIDs {
+		jobIDs[i] = id.ToBigInt()
+	}
+	trackDBOp = metrics.TrackDBOperation("update", "users")
+	err = h.userRepository.UpdateUserJobIDs(user.UserID, jobIDs)
+	trackDBOp(err)
+	if err != nil {
+		return fmt.Errorf("failed to update user job IDs: %w", err)
+	}
+
+	if !job.Paused {
+		return nil
+	}
+	created, err := h.jobRepository.GetJobByID(jobID)
+	if err != nil {
+		return err
+	}
+	return h.setManifestJobLifecycle(created, types.JobActionPause)
+}
+
+// updateManifestJob updates the mutable fields of a job to those of the manifest, keeping its
+// status. A time job whose interval changes runs next an interval from now.
+func (h *Handler) updateManifestJob(job *commonTypes.JobData, desired types.ManifestJob, fields []string) error {
+	jobID := job.JobID.ToBigInt()
+	trackDBOp := metrics.TrackDBOperation("update", "job_data")
+	err := h.jobRepository.UpdateJobFromUserInDB(jobID, &types.UpdateJobDataFromUserRequest{
+		JobID:             desired.JobID,
+		JobTitle:          desired.JobTitle,
+		Recurring:         desired.Recurring,
+		Status:            job.Status,
+		TimeFrame:         desired.TimeFrame,
+		JobCostPrediction: desired.JobCostPrediction,
+		Timezone:          job.Timezone,
+		TimeInterval:      desired.TimeInterval,
+	})
+	trackDBOp(err)
+	if err != nil {
+		return err
+	}
+
+	intervalChanged := slices.Contains(fields, "time_interval")
+	switch job.TaskDefinitionID {
+	case 1, 2:
+		if !intervalChanged {
+			return nil
+		}
+		trackDBOp = metrics.TrackDBOperation("update", "time_jobs")
+		err = h.timeJobRepository.UpdateTimeJobInterval(jobID, desired.TimeInterval)
+		if err == nil {
+			err = h.timeJobRepository.UpdateTimeJobNextExecutionTimestamp(jobID, time.Now().Add(time.Duration(desired.TimeInterval)*time.Second))
+		}
+		trackDBOp(err)
+	case 7:
+		trackDBOp = metrics.TrackDBOperation("update", "custom_jobs")
+		err = h.customJobRepository.UpdateCustomJob(jobID, desired.DynamicArgumentsScriptUrl, desired.TimeInterval, desired.Recurring)
+		trackDBOp(err)
+	}
+	return err
+}
+
+// setManifestJobLifecycle pauses or resumes a job like the lifecycle API, resumed jobs skip
+// the executions they missed
+func (h *Handler) setManifestJobLifecycle(job *commonTypes.JobData, action types.JobLifecycleAction) error {
+	trackDBOp := metrics.TrackDBOperation("read", "job_data")
+	lifecycle, err := h.jobRepository.GetJobLifecycle(job.JobID.ToBigInt())
+	trackDBOp(err)
+	if err != nil {
+		return err
+	}
+
+	var mode types.ResumeMode
+	if action == types.JobActionResume {
+		mode = types.ResumeSkip
+	}
+	_, err = h.changeJobLifecycle(job, lifecycle, action, mode)
+	return err
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func TestOrderManifestCreates(t *testing.T) {
+	// 1 links to 2, which links to 3, and 4 links to an existing job
+	creates := []types.ManifestJob{
+		{JobID: "1", LinkJobID: "2"},
+		{JobID: "2", LinkJobID: "3"},
+		{JobID: "3"},
+		{JobID: "4", LinkJobID: "10"},
+	}
+
+	var order []string
+	for _, job := range orderManifestCreates(creates) {
+		order = append(order, job.JobID)
+	}
+	assert.Equal(t, []string{"3", "4", "2", "1"}, order)
+}
+
+// fakeCustomJobRepo records status changes of custom jobs
+type fakeCustomJobRepo struct {
+	repository.CustomJobRepository
+	active map[string]bool
+}
+
+func (f *fakeCustomJobRepo) UpdateCustomJobStatus(jobID *big.Int, isActive, isCompleted bool) error {
+	f.active[jobID.String()] = isActive
+	return nil
+}
+
+type fakeWorkflowRepo struct {
+	repository.WorkflowRepository
+}
+
+func (f *fakeWorkflowRepo) GetWorkflowIDByJobID(jobID *big.Int) (string, error) {
+	return "", gocql.ErrNotFound
+}
+
+func TestApplyJobManifestResumes(t *testing.T) {
+	running := types.JobLifecycle{Status: types.JobStatusRunning}
+	current := map[string]manifestJobState{}
+	for _, id := range []string{"1", "2"} {
+		jobID, _ := new(big.Int).SetString(id, 10)
+		current[id] = manifestJobState{
+			job:      &commonTypes.JobData{JobID: commonTypes.NewBigInt(jobID), TaskDefinitionID: 7, Status: string(running.Status)},
+			manifest: types.ManifestJob{JobID: id},
+		}
+	}
+
+	jobRepo := new(MockJobRepository)
+	customJobRepo := &fakeCustomJobRepo{active: map[string]bool{}}
+	h := &Handler{jobRepository: jobRepo, customJobRepository: customJobRepo, workflowRepository: &fakeWorkflowRepo{}, logger: &MockLogger{}}
+
+	// Both jobs are left out of the manifest, deactivating the second fails
+	jobRepo.On("GetJobLifecycle", big.NewInt(1)).Return(running, nil)
+	jobRepo.On("TransitionJobStatus", big.NewInt(1), running, types.JobStatusPaused).Return(nil)
+	jobRepo.On("GetJobLifecycle", big.NewInt(2)).Return(types.JobLifecycle{}, errors.New("read failed"))
+
+	manifest := types.JobManifest{}
+	plan, err := h.planJobManifest(manifest, current)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Changes, 2)
+
+	applied, err := h.applyJobManifest(context.Background(), &commonTypes.UserData{}, manifest, current, plan)
+	assert.Equal(t, 1, applied)
+	assert.ErrorContains(t, err, "failed to deactivate job 2")
+	assert.Equal(t, map[string]bool{"1": false}, customJobRepo.active)
+
+	// The made change is kept, planning again only has the failed one
+	current["1"].job.Status = string(types.JobStatusPaused)
+	plan, err = h.planJobManifest(manifest, current)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.JobManifestChange{{JobID: "2", Action: types.ManifestActionDeactivate}}, plan.Changes)
+	jobRepo.AssertExpectations(t)
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	// "fmt"
+	"fmt"
 	"math/big"
 	"net/http"
 	"strings"
@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
 // GetJobDataByJobIDForUser handles GET /jobs/user/:user_address/:job_id
@@ -76,114 +77,60 @@ func (h *Handler) GetJobsByUserAddress(c *gin.Context) {
 		return
 	}
 
+	opts, err := parseListOptions(c, types.DefaultListLimit)
+	if err != nil {
+		h.logger.Errorf("[GetJobsByUserAddress] Invalid list options: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_LIST_OPTIONS",
+		})
+		return
+	}
+
 	h.logger.Infof("[GetJobsByUserAddress] Retrieving jobs for user address: %s", userAddress)
+	h.listJobsOfUser(c, "GetJobsByUserAddress", userAddress, opts)
+}
 
-	// First get user ID and job IDs
+// listJobsOfUser answers with a page of the jobs of a user and their type-specific data
+func (h *Handler) listJobsOfUser(c *gin.Context, logTag, userAddress string, opts types.ListOptions) {
 	trackDBOp := metrics.TrackDBOperation("read", "user_data")
-	userID, jobIDs, err := h.userRepository.GetUserJobIDsByAddress(userAddress)
+	userID, err := h.userRepository.GetUserIDByAddress(userAddress)
 	trackDBOp(err)
 	if err != nil {
-		if err.Error() == "user address not found" {
-			h.logger.Infof("[GetJobsByUserAddress] No user found for address %s", userAddress)
-		} else {
-			h.logger.Errorf("[GetJobsByUserAddress] Error getting user data for address %s: %v", userAddress, err)
-		}
+		h.logger.Infof("[%s] No user found for address %s: %v", logTag, userAddress, err)
 		c.JSON(http.StatusOK, gin.H{
-			"message": "No jobs found for this user",
-			"jobs":    []types.JobResponse{},
+			"message":  "No jobs found for this user",
+			"jobs":     []types.JobResponse{},
+			"has_more": false,
 		})
 		return
 	}
 
-	if len(jobIDs) == 0 {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "No jobs found for this user",
-			"jobs":    []types.JobResponse{},
-		})
+	trackDBOp = metrics.TrackDBOperation("read", "job_data")
+	page, err := h.jobRepository.ListJobsByUserID(userID, opts)
+	trackDBOp(err)
+	if err != nil {
+		h.logger.Errorf("[%s] Error listing jobs for user ID %d: %v", logTag, userID, err)
+		respondListError(c, err, http.StatusInternalServerError, "Failed to retrieve jobs", "JOB_RETRIEVAL_ERROR")
 		return
 	}
 
-	var jobs []types.JobResponse
+	jobsAPI := make([]types.JobResponseAPI, 0, len(page.Items))
 	var hasErrors bool
-
-	for _, jobID := range jobIDs {
-		// Get basic job data
-		trackDBOp = metrics.TrackDBOperation("read", "job_data")
-		jobData, err := h.jobRepository.GetJobByID(jobID)
-		trackDBOp(err)
+	for _, jobData := range page.Items {
+		jobResponse, err := h.jobResponse(jobData)
 		if err != nil {
-			h.logger.Errorf("[GetJobsByUserAddress] Error getting job data for jobID %d: %v", jobID, err)
+			h.logger.Errorf("[%s] %v", logTag, err)
 			hasErrors = true
 			continue
 		}
-
-		jobResponse := types.JobResponse{JobData: *jobData}
-
-		// Check task_definition_id to determine job type
-		switch jobData.TaskDefinitionID {
-		case 1, 2:
-			// Time-based job
-			trackDBOp = metrics.TrackDBOperation("read", "time_job")
-			timeJobData, err := h.timeJobRepository.GetTimeJobByJobID(jobID)
-			trackDBOp(err)
-			if err != nil {
-				h.logger.Errorf("[GetJobsByUserAddress] Error getting time job data for jobID %d: %v", jobID, err)
-				hasErrors = true
-				continue
-			}
-			jobResponse.TimeJobData = &timeJobData
-
-		case 3, 4:
-			// Event-based job
-			trackDBOp = metrics.TrackDBOperation("read", "event_job")
-			eventJobData, err := h.eventJobRepository.GetEventJobByJobID(jobID)
-			trackDBOp(err)
-			if err != nil {
-				h.logger.Errorf("[GetJobsByUserAddress] Error getting event job data for jobID %d: %v", jobID, err)
-				hasErrors = true
-				continue
-			}
-			jobResponse.EventJobData = &eventJobData
-
-		case 5, 6:
-			// Condition-based job
-			trackDBOp = metrics.TrackDBOperation("read", "condition_job")
-			conditionJobData, err := h.conditionJobRepository.GetConditionJobByJobID(jobID)
-			trackDBOp(err)
-			if err != nil {
-				h.logger.Errorf("[GetJobsByUserAddress] Error getting condition job data for jobID %d: %v", jobID, err)
-				hasErrors = true
-				continue
-			}
-			jobResponse.ConditionJobData = &conditionJobData
-
-		default:
-			h.logger.Errorf("[GetJobsByUserAddress] Unknown task definition ID %d for jobID %d", jobData.TaskDefinitionID, jobID)
-			hasErrors = true
-			continue
-		}
-
-		jobs = append(jobs, jobResponse)
-	}
-
-	h.logger.Infof("[GetJobsByUserAddress] Found %d jobs for user ID %d", len(jobs), userID)
-
-	var jobsAPI []types.JobResponseAPI
-	for _, job := range jobs {
-		jobsAPI = append(jobsAPI, types.ConvertJobResponseToAPI(job))
+		jobsAPI = append(jobsAPI, types.ConvertJobResponseToAPI(jobResponse))
 	}
 
-	// If we have both jobs and errors, return a partial success response
-	if len(jobs) > 0 && hasErrors {
-		c.JSON(http.StatusPartialContent, gin.H{
-			"message": "Some jobs were retrieved successfully, but there were errors with others",
-			"jobs":    jobs,
-		})
-		return
-	}
+	h.logger.Infof("[%s] Found %d jobs for user ID %d", logTag, len(jobsAPI), userID)
 
 	// If we have only errors and no jobs, return an error response
-	if len(jobs) == 0 && hasErrors {
+	if len(jobsAPI) == 0 && hasErrors {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve jobs",
 			"code":  "JOB_RETRIEVAL_ERROR",
@@ -191,10 +138,61 @@ func (h *Handler) GetJobsByUserAddress(c *gin.Context) {
 		return
 	}
 
-	// If we have only jobs and no errors, return success
-	c.JSON(http.StatusOK, gin.H{
-		"jobs": jobsAPI,
-	})
+	status := http.StatusOK
+	response := gin.H{
+		"jobs":        jobsAPI,
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
+	}
+	// If we have both jobs and errors, return a partial success response
+	if hasErrors {
+		status = http.StatusPartialContent
+		response["message"] = "Some jobs were retrieved successfully, but there were errors with others"
+	}
+	c.JSON(status, response)
+}
+
+// jobResponse adds the type-specific data of a job, chosen by its task definition
+func (h *Handler) jobResponse(jobData commonTypes.JobData) (types.JobResponse, error) {
+	jobResponse := types.JobResponse{JobData: jobData}
+	jobID := jobData.JobID.ToBigInt()
+
+	switch jobData.TaskDefinitionID {
+	case 1, 2:
+		// Time-based job
+		trackDBOp := metrics.TrackDBOperation("read", "time_job")
+		timeJobData, err := h.timeJobRepository.GetTimeJobByJobID(jobID)
+		trackDBOp(err)
+		if err != nil {
+			return jobResponse, fmt.Errorf("error getting time job data for jobID %s: %w", jobID, err)
+		}
+		jobResponse.TimeJobData = &timeJobData
+
+	case 3, 4:
+		// Event-based job
+		trackDBOp := metrics.TrackDBOperation("read", "event_job")
+		eventJobData, err := h.eventJobRepository.GetEventJobByJobID(jobID)
+		trackDBOp(err)
+		if err != nil {
+			return jobResponse, fmt.Errorf("error getting event job data for jobID %s: %w", jobID, err)
+		}
+		jobResponse.EventJobData = &eventJobData
+
+	case 5, 6:
+		// Condition-based job
+		trackDBOp := metrics.TrackDBOperation("read", "condition_job")
+		conditionJobData, err := h.conditionJobRepository.GetConditionJobByJobID(jobID)
+		trackDBOp(err)
+		if err != nil {
+			return jobResponse, fmt.Errorf("error getting condition job data for jobID %s: %w", jobID, err)
+		}
+		jobResponse.ConditionJobData = &conditionJobData
+
+	default:
+		return jobResponse, fmt.Errorf("unknown task definition ID %d for jobID %s", jobData.TaskDefinitionID, jobID)
+	}
+
+	return jobResponse, nil
 }
 
 // GetTaskFeesByJobID handles GET /jobs/:job_id/task-fees
@@ -238,7 +236,7 @@ func (h *Handler) GetJobsByApiKey(c *gin.Context) {
 		return
 	}
 
-	apiKeyData, err := h.apiKeysRepository.GetApiKeyDataByKey(apiKey)
+	apiKeyData, err := h.apiKeysRepository.AuthenticateApiKey(apiKey)
 	if err != nil {
 		h.logger.Errorf("[GetJobsByApiKey] Invalid API key: %v", err)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
@@ -311,128 +309,19 @@ func (h *Handler) GetJobsByUserAddressAndChainID(c *gin.Context) {
 		return
 	}
 
-	h.logger.Infof("[GetJobsByUserAddressAndChainID] Retrieving jobs for user address: %s and created_chain_id: %s", userAddress, createdChainIDParam)
-
-	// First get user ID and job IDs
-	trackDBOp := metrics.TrackDBOperation("read", "user_data")
-	_, jobIDs, err := h.userRepository.GetUserJobIDsByAddress(userAddress)
-	trackDBOp(err)
+	opts, err := parseListOptions(c, types.DefaultListLimit)
 	if err != nil {
-		if err.Error() == "user address not found" {
-			h.logger.Infof("[GetJobsByUserAddressAndChainID] No user found for address %s", userAddress)
-		} else {
-			h.logger.Errorf("[GetJobsByUserAddressAndChainID] Error getting user data for address %s: %v", userAddress, err)
-		}
-		c.JSON(http.StatusOK, gin.H{
-			"message": "No jobs found for this user",
-			"jobs":    []types.JobResponse{},
-		})
-		return
-	}
-
-	if len(jobIDs) == 0 {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "No jobs found for this user",
-			"jobs":    []types.JobResponse{},
-		})
-		return
-	}
-
-	var jobs []types.JobResponse
-	var hasErrors bool
-
-	for _, jobID := range jobIDs {
-		// Get basic job data
-		trackDBOp = metrics.TrackDBOperation("read", "job_data")
-		jobData, err := h.jobRepository.GetJobByID(jobID)
-		trackDBOp(err)
-		if err != nil {
-			h.logger.Errorf("[GetJobsByUserAddressAndChainID] Error getting job data for jobID %d: %v", jobID, err)
-			hasErrors = true
-			continue
-		}
-
-		// Filter by created_chain_id
-		if strings.ToLower(jobData.CreatedChainID) != createdChainIDParam {
-			continue
-		}
-
-		jobResponse := types.JobResponse{JobData: *jobData}
-
-		// Check task_definition_id to determine job type
-		switch jobData.TaskDefinitionID {
-		case 1, 2:
-			// Time-based job
-			trackDBOp = metrics.TrackDBOperation("read", "time_job")
-			timeJobData, err := h.timeJobRepository.GetTimeJobByJobID(jobID)
-			trackDBOp(err)
-			if err != nil {
-				h.logger.Errorf("[GetJobsByUserAddressAndChainID] Error getting time job data for jobID %d: %v", jobID, err)
-				hasErrors = true
-				continue
-			}
-			jobResponse.TimeJobData = &timeJobData
-
-		case 3, 4:
-			// Event-based job
-			trackDBOp = metrics.TrackDBOperation("read", "event_job")
-			eventJobData, err := h.eventJobRepository.GetEventJobByJobID(jobID)
-			trackDBOp(err)
-			if err != nil {
-				h.logger.Errorf("[GetJobsByUserAddressAndChainID] Error getting event job data for jobID %d: %v", jobID, err)
-				hasErrors = true
-				continue
-			}
-			jobResponse.EventJobData = &eventJobData
-
-		case 5, 6:
-			// Condition-based job
-			trackDBOp = metrics.TrackDBOperation("read", "condition_job")
-			conditionJobData, err := h.conditionJobRepository.GetConditionJobByJobID(jobID)
-			trackDBOp(err)
-			if err != nil {
-				h.logger.Errorf("[GetJobsByUserAddressAndChainID] Error getting condition job data for jobID %d: %v", jobID, err)
-				hasErrors = true
-				continue
-			}
-			jobResponse.ConditionJobData = &conditionJobData
-
-		default:
-			h.logger.Errorf("[GetJobsByUserAddressAndChainID] Unknown task definition ID %d for jobID %d", jobData.TaskDefinitionID, jobID)
-			hasErrors = true
-			continue
-		}
-
-		jobs = append(jobs, jobResponse)
-	}
-
-	var jobsAPI []types.JobResponseAPI
-	for _, job := range jobs {
-		jobsAPI = append(jobsAPI, types.ConvertJobResponseToAPI(job))
-	}
-
-	// If we have both jobs and errors, return a partial success response
-	if len(jobs) > 0 && hasErrors {
-		c.JSON(http.StatusPartialContent, gin.H{
-			"message": "Some jobs were retrieved successfully, but there were errors with others",
-			"jobs":    jobsAPI,
-		})
-		return
-	}
-
-	// If we have only errors and no jobs, return an error response
-	if len(jobs) == 0 && hasErrors {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve jobs",
-			"code":  "JOB_RETRIEVAL_ERROR",
+		h.logger.Errorf("[GetJobsByUserAddressAndChainID] Invalid list options: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_LIST_OPTIONS",
 		})
 		return
 	}
+	opts.ChainID = createdChainIDParam
 
-	// If we have only jobs and no errors, return success
-	c.JSON(http.StatusOK, gin.H{
-		"jobs": jobsAPI,
-	})
+	h.logger.Infof("[GetJobsByUserAddressAndChainID] Retrieving jobs for user address: %s and created_chain_id: %s", userAddress, createdChainIDParam)
+	h.listJobsOfUser(c, "GetJobsByUserAddressAndChainID", userAddress, opts)
 }
 
 // parseInt64 is a helper to parse int64 from string
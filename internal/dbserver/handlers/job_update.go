@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"math/big"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 )
 
@@ -125,6 +127,12 @@ func (h *Handler) UpdateJobDataFromUser(c *gin.Context) {
 		return
 	}
 
+	// The route's job, whose ownership was checked, is the one updated
+	if pathID := c.Param("id"); pathID != "" && pathID != jobID.String() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "job_id does not match the job in the path"})
+		return
+	}
+
 	trackDBOp := metrics.TrackDBOperation("update", "job_data")
 	err := h.jobRepository.UpdateJobFromUserInDB(jobID, &updateData)
 	trackDBOp(err)
@@ -195,15 +203,39 @@ func (h *Handler) UpdateJobStatus(c *gin.Context) {
 		return
 	}
 
-	// Update the job status
-	trackDBOp := metrics.TrackDBOperation("update", "job_data")
-	if err := h.jobRepository.UpdateJobStatus(jobIDBig, status); err != nil {
-		trackDBOp(err)
+	// Paused, cancelled and finished jobs only change status through the lifecycle endpoints
+	trackDBOp := metrics.TrackDBOperation("read", "job_data")
+	lifecycle, err := h.jobRepository.GetJobLifecycle(jobIDBig)
+	trackDBOp(err)
+	if err != nil {
+		h.logger.Errorf("[UpdateJobStatus] Error getting job status: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !lifecycle.Status.IsActive() {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Job is " + string(lifecycle.Status) + ", use the lifecycle endpoints to change its status",
+			"code":  "INVALID_JOB_TRANSITION",
+		})
+		return
+	}
+
+	// Update the job status, unless a pause or cancel landed since it was read
+	trackDBOp = metrics.TrackDBOperation("update", "job_data")
+	err = h.jobRepository.UpdateActiveJobStatus(jobIDBig, lifecycle.Status, types.JobStatus(status))
+	trackDBOp(err)
+	if errors.Is(err, repository.ErrJobStatusChanged) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Job status changed concurrently, retry with its current status",
+			"code":  "JOB_STATUS_CHANGED",
+		})
+		return
+	}
+	if err != nil {
 		h.logger.Errorf("[UpdateJobStatus] Error updating job status: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	trackDBOp(nil)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Job status updated successfully",
@@ -224,6 +256,11 @@ func (h *Handler) UpdateJobLastExecutedAt(c *gin.Context) {
 		return
 	}
 
+	if pathID := c.Param("job_id"); pathID != "" && (updateData.JobID == nil || pathID != updateData.JobID.String()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "job_id does not match the job in the path"})
+		return
+	}
+
 	// Update main job_data table
 	trackDBOp := metrics.TrackDBOperation("update", "job_data")
 	if err := h.jobRepository.UpdateJobLastExecutedAt(updateData.JobID, updateData.TaskIDs, updateData.JobCostActual, updateData.LastExecutedAt); err != nil {
@@ -12,6 +12,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/middleware"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
@@ -115,6 +117,11 @@ func (m *MockUserRepository) GetUserIDByAddress(address string) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockUserRepository) GetUserAddressByID(id int64) (string, error) {
+	args := m.Called(id)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockJobRepository) CreateNewJob(job *commonTypes.JobData) (*big.Int, error) {
 	args := m.Called(job)
 	return args.Get(0).(*big.Int), args.Error(1)
@@ -130,6 +137,11 @@ func (m *MockJobRepository) UpdateJobStatus(jobID *big.Int, status string) error
 	return args.Error(0)
 }
 
+func (m *MockJobRepository) UpdateActiveJobStatus(jobID *big.Int, current, status types.JobStatus) error {
+	args := m.Called(jobID, current, status)
+	return args.Error(0)
+}
+
 func (m *MockJobRepository) UpdateJobLastExecutedAt(jobID *big.Int, taskID int64, jobCostActual float64, lastExecutedAt time.Time) error {
 	args := m.Called(jobID, taskID, jobCostActual, lastExecutedAt)
 	return args.Error(0)
@@ -155,6 +167,41 @@ func (m *MockJobRepository) GetJobsByUserIDAndChainID(userID int64, createdChain
 	return args.Get(0).([]commonTypes.JobData), args.Error(1)
 }
 
+func (m *MockJobRepository) ListJobsByUserID(userID int64, opts types.ListOptions) (types.Page[commonTypes.JobData], error) {
+	args := m.Called(userID, opts)
+	return args.Get(0).(types.Page[commonTypes.JobData]), args.Error(1)
+}
+
+func (m *MockJobRepository) GetJobLifecycle(jobID *big.Int) (types.JobLifecycle, error) {
+	args := m.Called(jobID)
+	return args.Get(0).(types.JobLifecycle), args.Error(1)
+}
+
+func (m *MockJobRepository) TransitionJobStatus(jobID *big.Int, current types.JobLifecycle, status types.JobStatus) error {
+	args := m.Called(jobID, current, status)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) RestoreJobLifecycle(jobID *big.Int, status types.JobStatus, previous types.JobLifecycle) error {
+	args := m.Called(jobID, status, previous)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) SchedulePause(jobID *big.Int, current types.JobLifecycle, pauseAt *time.Time) error {
+	args := m.Called(jobID, current, pauseAt)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) GetDueScheduledPauses(now time.Time) ([]types.ScheduledPause, error) {
+	args := m.Called(now)
+	return args.Get(0).([]types.ScheduledPause), args.Error(1)
+}
+
+func (m *MockJobRepository) DeleteScheduledPause(pause types.ScheduledPause) error {
+	args := m.Called(pause)
+	return args.Error(0)
+}
+
 func (m *MockJobRepository) GetJobsBySafeAddress(safeAddress string) ([]commonTypes.JobData, error) {
 	args := m.Called(safeAddress)
 	return args.Get(0).([]commonTypes.JobData), args.Error(1)
@@ -267,6 +314,7 @@ func TestCreateJobData(t *testing.T) {
 	tests := []struct {
 		name          string
 		input         []types.CreateJobData
+		session       string
 		setupMocks    func()
 		expectedCode  int
 		expectedError string
@@ -300,15 +348,32 @@ func TestCreateJobData(t *testing.T) {
 				mockUserRepo.On("UpdateUserTasksAndPoints", int64(1), int64(0), 10.0).Return(nil)
 				mockUserRepo.On("UpdateUserJobIDs", int64(1), []*big.Int{big.NewInt(1)}).Return(nil)
 			},
+			session:      "0x123",
 			expectedCode: http.StatusOK,
 		},
 		{
 			name:          "Error - Empty Job List",
 			input:         []types.CreateJobData{},
+			session:       "0x123",
 			setupMocks:    func() {},
 			expectedCode:  http.StatusBadRequest,
 			expectedError: "No jobs provided",
 		},
+		{
+			name:          "Error - No Session",
+			input:         []types.CreateJobData{{UserAddress: "0x123"}},
+			setupMocks:    func() {},
+			expectedCode:  http.StatusUnauthorized,
+			expectedError: "Session token is required",
+		},
+		{
+			name:          "Error - Job For Another Address",
+			input:         []types.CreateJobData{{UserAddress: "0x456"}},
+			session:       "0x123",
+			setupMocks:    func() {},
+			expectedCode:  http.StatusForbidden,
+			expectedError: "session's address",
+		},
 		{
 			name: "Error - Invalid User Address",
 			input: []types.CreateJobData{
@@ -316,6 +381,7 @@ func TestCreateJobData(t *testing.T) {
 					UserAddress: "0x123",
 				},
 			},
+			session: "0x123",
 			setupMocks: func() {
 				mockUserRepo.On("GetUserDataByAddress", "0x123").Return(int64(0), commonTypes.UserData{}, assert.AnError)
 			},
@@ -335,6 +401,9 @@ func TestCreateJobData(t *testing.T) {
 			body, _ := json.Marshal(tt.input)
 			c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
 			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.session != "" {
+				c.Set(middleware.WalletAddressKey, tt.session)
+			}
 
 			// Setup mocks
 			tt.setupMocks()
@@ -370,10 +439,32 @@ func TestUpdateJobStatus(t *testing.T) {
 			jobID:  "1",
 			status: "running",
 			setupMocks: func() {
-				mockJobRepo.On("UpdateJobStatus", big.NewInt(1), "running").Return(nil)
+				mockJobRepo.On("GetJobLifecycle", big.NewInt(1)).Return(types.JobLifecycle{Status: types.JobStatusPending}, nil).Once()
+				mockJobRepo.On("UpdateActiveJobStatus", big.NewInt(1), types.JobStatusPending, types.JobStatusRunning).Return(nil).Once()
 			},
 			expectedCode: http.StatusOK,
 		},
+		{
+			name:   "Error - Status Changed Concurrently",
+			jobID:  "1",
+			status: "running",
+			setupMocks: func() {
+				mockJobRepo.On("GetJobLifecycle", big.NewInt(1)).Return(types.JobLifecycle{Status: types.JobStatusPending}, nil).Once()
+				mockJobRepo.On("UpdateActiveJobStatus", big.NewInt(1), types.JobStatusPending, types.JobStatusRunning).Return(repository.ErrJobStatusChanged).Once()
+			},
+			expectedCode:  http.StatusConflict,
+			expectedError: "Job status changed concurrently",
+		},
+		{
+			name:   "Error - Paused Job",
+			jobID:  "1",
+			status: "running",
+			setupMocks: func() {
+				mockJobRepo.On("GetJobLifecycle", big.NewInt(1)).Return(types.JobLifecycle{Status: types.JobStatusPaused}, nil).Once()
+			},
+			expectedCode:  http.StatusConflict,
+			expectedError: "Job is paused",
+		},
 		{
 			name:          "Error - Invalid Status",
 			jobID:         "1",
@@ -509,14 +600,12 @@ func TestGetJobsByUserAddress(t *testing.T) {
 			name:        "Success - Get User Jobs",
 			userAddress: "0x123",
 			setupMocks: func() {
-				mockUserRepo.On("GetUserJobIDsByAddress", "0x123").Return(int64(1), []*big.Int{big.NewInt(1), big.NewInt(2)}, nil)
-				mockJobRepo.On("GetJobByID", big.NewInt(1)).Return(&commonTypes.JobData{
-					JobID:            commonTypes.NewBigInt(big.NewInt(1)),
-					TaskDefinitionID: 1,
-				}, nil)
-				mockJobRepo.On("GetJobByID", big.NewInt(2)).Return(&commonTypes.JobData{
-					JobID:            commonTypes.NewBigInt(big.NewInt(2)),
-					TaskDefinitionID: 3,
+				mockUserRepo.On("GetUserIDByAddress", "0x123").Return(int64(1), nil)
+				mockJobRepo.On("ListJobsByUserID", int64(1), mock.AnythingOfType("types.ListOptions")).Return(types.Page[commonTypes.JobData]{
+					Items: []commonTypes.JobData{
+						{JobID: commonTypes.NewBigInt(big.NewInt(1)), TaskDefinitionID: 1},
+						{JobID: commonTypes.NewBigInt(big.NewInt(2)), TaskDefinitionID: 3},
+					},
 				}, nil)
 				mockTimeJobRepo.On("GetTimeJobByJobID", big.NewInt(1)).Return(commonTypes.TimeJobData{}, nil)
 				mockEventJobRepo.On("GetEventJobByJobID", big.NewInt(2)).Return(commonTypes.EventJobData{}, nil)
@@ -534,7 +623,7 @@ func TestGetJobsByUserAddress(t *testing.T) {
 			name:        "Error - User Not Found",
 			userAddress: "0x123",
 			setupMocks: func() {
-				mockUserRepo.On("GetUserJobIDsByAddress", "0x123").Return(int64(0), []*big.Int{}, assert.AnError)
+				mockUserRepo.On("GetUserIDByAddress", "0x123").Return(int64(0), assert.AnError)
 			},
 			expectedCode:  http.StatusOK,
 			expectedError: "",
@@ -549,6 +638,7 @@ func TestGetJobsByUserAddress(t *testing.T) {
 			c, _ := gin.CreateTestContext(w)
 
 			// Setup request
+			c.Request = httptest.NewRequest("GET", "/", nil)
 			c.Params = []gin.Param{
 				{Key: "user_address", Value: tt.userAddress},
 			}
@@ -567,9 +657,13 @@ func TestGetJobsByUserAddress(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Contains(t, response["error"], tt.expectedError)
 			} else if tt.expectedCode == http.StatusOK {
-				var response []types.JobResponse
+				var response struct {
+					Jobs    []types.JobResponseAPI `json:"jobs"`
+					HasMore bool                   `json:"has_more"`
+				}
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
+				assert.False(t, response.HasMore)
 			}
 		})
 	}
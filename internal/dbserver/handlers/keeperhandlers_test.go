@@ -148,9 +148,14 @@ func (m *MockTaskRepository) GetCreatedChainIDByJobID(jobID *big.Int) (string, e
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockTaskRepository) GetRecentTasks(limit int) ([]types.RecentTaskResponse, error) {
-	args := m.Called(limit)
-	return args.Get(0).([]types.RecentTaskResponse), args.Error(1)
+func (m *MockTaskRepository) ListTasksByJobID(jobID *big.Int, opts types.ListOptions) (types.Page[types.GetTasksByJobID], error) {
+	args := m.Called(jobID, opts)
+	return args.Get(0).(types.Page[types.GetTasksByJobID]), args.Error(1)
+}
+
+func (m *MockTaskRepository) ListRecentTasks(opts types.ListOptions) (types.Page[types.RecentTaskResponse], error) {
+	args := m.Called(opts)
+	return args.Get(0).(types.Page[types.RecentTaskResponse]), args.Error(1)
 }
 
 // Test setup helper
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+)
+
+// parseListOptions reads the paging, sorting and filter query parameters of a list endpoint:
+// limit, cursor, order (asc or desc), status, task_definition_id, chain_id, and from and to as
+// RFC 3339 times or unix seconds
+func parseListOptions(c *gin.Context, defaultLimit int) (types.ListOptions, error) {
+	opts := types.ListOptions{
+		Limit:   defaultLimit,
+		Cursor:  c.Query("cursor"),
+		Order:   types.SortDesc,
+		Status:  c.Query("status"),
+		ChainID: c.Query("chain_id"),
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return opts, fmt.Errorf("invalid limit %q", limitStr)
+		}
+		opts.Limit = min(limit, types.MaxListLimit)
+	}
+
+	switch order := types.SortOrder(strings.ToLower(c.DefaultQuery("order", string(types.SortDesc)))); order {
+	case types.SortAsc, types.SortDesc:
+		opts.Order = order
+	default:
+		return opts, fmt.Errorf("invalid order %q, expected asc or desc", order)
+	}
+
+	if taskDefinitionID := c.Query("task_definition_id"); taskDefinitionID != "" {
+		id, err := strconv.Atoi(taskDefinitionID)
+		if err != nil || id <= 0 {
+			return opts, fmt.Errorf("invalid task_definition_id %q", taskDefinitionID)
+		}
+		opts.TaskDefinitionID = id
+	}
+
+	var err error
+	if opts.From, err = parseListTime(c.Query("from")); err != nil {
+		return opts, fmt.Errorf("invalid from: %w", err)
+	}
+	if opts.To, err = parseListTime(c.Query("to")); err != nil {
+		return opts, fmt.Errorf("invalid to: %w", err)
+	}
+	if !opts.From.IsZero() && !opts.To.IsZero() && !opts.From.Before(opts.To) {
+		return opts, errors.New("from must be before to")
+	}
+	return opts, nil
+}
+
+func parseListTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// respondListError answers a list request whose options the repository rejected with 400, and
+// any other error with the given status and code
+func respondListError(c *gin.Context, err error, status int, message, code string) {
+	switch {
+	case errors.Is(err, repository.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid cursor, it must be used with the filters and order it was returned for",
+			"code":  "INVALID_CURSOR",
+		})
+	case errors.Is(err, repository.ErrRangeTooLong):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Date range too long",
+			"code":  "INVALID_LIST_OPTIONS",
+		})
+	default:
+		c.JSON(status, gin.H{
+			"error": message,
+			"code":  code,
+		})
+	}
+}
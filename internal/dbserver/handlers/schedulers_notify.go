@@ -16,7 +16,7 @@ import (
 
 // notifyConditionScheduler sends a notification to the condition scheduler
 func (h *Handler) notifyConditionScheduler(jobID *big.Int, scheduleConditionJobData commonTypes.ScheduleConditionJobData) (bool, error) {
-	success, err := h.sendDataToScheduler("/api/v1/job/schedule", scheduleConditionJobData)
+	success, err := h.sendDataToScheduler(config.GetConditionSchedulerRPCUrl(), "/api/v1/job/schedule", scheduleConditionJobData)
 	if err != nil {
 		h.logger.Errorf("[NotifyConditionScheduler] Failed to notify condition scheduler for job %d: %v", jobID, err)
 		return false, err
@@ -30,7 +30,7 @@ func (h *Handler) notifyConditionScheduler(jobID *big.Int, scheduleConditionJobD
 
 // SendPauseToEventScheduler sends a DELETE request to the event scheduler
 func (h *Handler) notifyPauseToConditionScheduler(jobID *big.Int) (bool, error) {
-	success, err := h.sendDataToScheduler("/api/v1/job/pause", commonTypes.ScheduleConditionJobData{JobID: commonTypes.NewBigInt(jobID)})
+	success, err := h.sendDataToScheduler(config.GetConditionSchedulerRPCUrl(), "/api/v1/job/pause", commonTypes.ScheduleConditionJobData{JobID: commonTypes.NewBigInt(jobID)})
 	if err != nil {
 		h.logger.Errorf("[NotifyEventScheduler] Failed to notify event scheduler for job %d: %v", jobID, err)
 		return false, err
@@ -43,14 +43,29 @@ func (h *Handler) notifyPauseToConditionScheduler(jobID *big.Int) (bool, error)
 	return true, nil
 }
 
+// notifyTimeScheduler tells the time scheduler that a job was paused or resumed, route is
+// /api/v1/job/pause or /api/v1/job/resume
+func (h *Handler) notifyTimeScheduler(route string, jobID *big.Int) (bool, error) {
+	success, err := h.sendDataToScheduler(config.GetTimeSchedulerRPCUrl(), route, commonTypes.JobLifecycleNotice{JobID: commonTypes.NewBigInt(jobID)})
+	if err != nil {
+		h.logger.Errorf("[NotifyTimeScheduler] Failed to notify time scheduler for job %d: %v", jobID, err)
+		return false, err
+	}
+	if !success {
+		h.logger.Errorf("[NotifyTimeScheduler] Failed to notify time scheduler for job %d", jobID)
+		return false, fmt.Errorf("failed to notify time scheduler for job %d", jobID)
+	}
+	return true, nil
+}
+
 // sendDataToScheduler is a generic function to send data to any scheduler
-func (h *Handler) sendDataToScheduler(route string, data commonTypes.ScheduleConditionJobData) (bool, error) {
+func (h *Handler) sendDataToScheduler(schedulerURL, route string, data interface{}) (bool, error) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return false, fmt.Errorf("error marshaling data: %v", err)
 	}
 
-	apiURL := fmt.Sprintf("%s%s", config.GetConditionSchedulerRPCUrl(), route)
+	apiURL := fmt.Sprintf("%s%s", schedulerURL, route)
 
 	client, err := httppkg.NewHTTPClient(httppkg.DefaultHTTPRetryConfig(), h.logger)
 	if err != nil {
@@ -66,7 +81,7 @@ func (h *Handler) sendDataToScheduler(route string, data commonTypes.ScheduleCon
 
 	resp, err := client.DoWithRetry(context.Background(), req)
 	if err != nil {
-		return false, fmt.Errorf("error sending data to condition scheduler: %v", err)
+		return false, fmt.Errorf("error sending data to scheduler: %v", err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -76,9 +91,9 @@ func (h *Handler) sendDataToScheduler(route string, data commonTypes.ScheduleCon
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return false, fmt.Errorf("condition scheduler service error (status=%d): %s", resp.StatusCode, string(body))
+		return false, fmt.Errorf("scheduler service error (status=%d): %s", resp.StatusCode, string(body))
 	}
 
-	h.logger.Infof("Successfully sent data to condition scheduler")
+	h.logger.Infof("Successfully sent data to scheduler at %s", apiURL)
 	return true, nil
 }
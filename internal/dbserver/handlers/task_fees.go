@@ -6,16 +6,22 @@ import (
 	"math/big"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/config"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/middleware"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
-func (h *Handler) CalculateTaskFees(ipfsURLs string, taskDefinitionID int, targetChainID, targetContractAddress, targetFunction, abi, args, fromAddress string) (*big.Int, *big.Int, error) {
+// CalculateTaskFees estimates the fees of a task by running it through the docker executor.
+// tenantID is the wallet address or API key ID the executions are scheduled against; a
+// *types.QuotaExceededError is returned when that tenant is over its execution quota.
+func (h *Handler) CalculateTaskFees(ipfsURLs string, taskDefinitionID int, targetChainID, targetContractAddress, targetFunction, abi, args, fromAddress, tenantID string) (*big.Int, *big.Int, error) {
 	// Only for taskDefinitionID 2, 4, 6 require ipfsURL(s)
 	needsIPFS := taskDefinitionID == 2 || taskDefinitionID == 4 || taskDefinitionID == 6
 
@@ -30,6 +36,7 @@ func (h *Handler) CalculateTaskFees(ipfsURLs string, taskDefinitionID int, targe
 	ctx := context.Background()
 	var mu sync.Mutex
 	var wg sync.WaitGroup
+	var quotaErr error
 
 	if needsIPFS {
 		urlList := strings.Split(ipfsURLs, ",")
@@ -48,11 +55,17 @@ func (h *Handler) CalculateTaskFees(ipfsURLs string, taskDefinitionID int, targe
 					"abi":                     abi,
 					"on_chain_args":           args,
 					"from_address":            from,
+					types.MetadataKeyTenantID: tenantID,
 				}
 
 				result, err := h.dockerExecutor.Execute(ctx, url, string(types.LanguageGo), 10, config.GetAlchemyAPIKey(), metadata)
 				if err != nil {
 					h.logger.Errorf("Error executing code: %v", err)
+					if _, ok := types.AsQuotaExceeded(err); ok {
+						mu.Lock()
+						quotaErr = err
+						mu.Unlock()
+					}
 					return
 				}
 
@@ -68,6 +81,10 @@ func (h *Handler) CalculateTaskFees(ipfsURLs string, taskDefinitionID int, targe
 			}(ipfsURL, fromAddress)
 		}
 		wg.Wait()
+
+		if quotaErr != nil {
+			return big.NewInt(0), big.NewInt(0), quotaErr
+		}
 	} else {
 		// No IPFS required; just invoke Execute with empty code/url and rely on metadata for fee calculation
 		metadata := map[string]string{
@@ -78,6 +95,7 @@ func (h *Handler) CalculateTaskFees(ipfsURLs string, taskDefinitionID int, targe
 			"abi":                     abi,
 			"on_chain_args":           args,
 			"from_address":            fromAddress,
+			types.MetadataKeyTenantID: tenantID,
 		}
 		result, err := h.dockerExecutor.Execute(ctx, "", string(types.LanguageGo), 10, config.GetAlchemyAPIKey(), metadata)
 		if err != nil {
@@ -131,9 +149,15 @@ func (h *Handler) GetTaskFees(c *gin.Context) {
 		h.logger.Warnf("[GetTaskFees] Invalid task_definition_id: %s, using 0", taskDefID)
 	}
 
-	totalFee, currentTotalFee, err := h.CalculateTaskFees(ipfsURLs, taskDefinitionID, targetChainID, targetContractAddress, targetFunction, abi, args, fromAddress)
+	tenantID := h.getTenantID(c)
+
+	totalFee, currentTotalFee, err := h.CalculateTaskFees(ipfsURLs, taskDefinitionID, targetChainID, targetContractAddress, targetFunction, abi, args, fromAddress, tenantID)
 	if err != nil {
 		h.logger.Errorf("[GetTaskFees] Error calculating fees: %v", err)
+		if quotaErr, ok := types.AsQuotaExceeded(err); ok {
+			h.respondQuotaExceeded(c, quotaErr)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -145,3 +169,35 @@ func (h *Handler) GetTaskFees(c *gin.Context) {
 		"current_total_fee_wei": currentTotalFee.String(),
 	})
 }
+
+// getTenantID identifies who executions are scheduled against: the address of the
+// caller's wallet session, otherwise the ID of its API key. Anonymous callers share the
+// default tenant. Never taken from the request itself, which the caller controls.
+func (h *Handler) getTenantID(c *gin.Context) string {
+	if address := c.GetString(middleware.WalletAddressKey); address != "" {
+		return strings.ToLower(address)
+	}
+	if value, ok := c.Get(middleware.ApiKeyContextKey); ok {
+		if apiKey, ok := value.(*commonTypes.ApiKey); ok {
+			return apiKey.Key
+		}
+	}
+	return ""
+}
+
+// respondQuotaExceeded rejects a request whose tenant is over its docker executor quota
+func (h *Handler) respondQuotaExceeded(c *gin.Context, quotaErr *types.QuotaExceededError) {
+	retryAfter := int(quotaErr.RetryAfter.Seconds())
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "Execution quota exceeded",
+		"code":        "QUOTA_EXCEEDED",
+		"resource":    quotaErr.Resource,
+		"limit":       quotaErr.Limit,
+		"used":        quotaErr.Used,
+		"retry_after": retryAfter,
+	})
+}
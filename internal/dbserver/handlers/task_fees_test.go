@@ -2,16 +2,20 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/middleware"
 	dexconfig "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
 	dexexec "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/execution"
 	dextypes "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
 // helper to create execution result with given cost and success
@@ -75,6 +79,9 @@ func (f *FakeDockerExecutor) GetSupportedLanguages() []dextypes.Language {
 	return []dextypes.Language{}
 }
 func (f *FakeDockerExecutor) IsLanguageSupported(language dextypes.Language) bool { return true }
+func (f *FakeDockerExecutor) SchedulePrewarm(language dextypes.Language, timestamps []time.Time) error {
+	return nil
+}
 func (f *FakeDockerExecutor) GetActiveExecutions() []*dextypes.ExecutionContext {
 	return []*dextypes.ExecutionContext{}
 }
@@ -93,7 +100,7 @@ func TestCalculateTaskFees_EmptyInput(t *testing.T) {
 		logger:         &MockLogger{},
 	}
 
-	total, _, err := h.CalculateTaskFees("", 0, "", "", "", "", "", "")
+	total, _, err := h.CalculateTaskFees("", 0, "", "", "", "", "", "", "")
 	if err == nil {
 		t.Fatalf("expected error for empty input, got nil")
 	}
@@ -111,7 +118,7 @@ func TestCalculateTaskFees_SingleURL_Success(t *testing.T) {
 		logger:         &MockLogger{},
 	}
 
-	total, _, err := h.CalculateTaskFees("ipfs://file1", 0, "", "", "", "", "", "")
+	total, _, err := h.CalculateTaskFees("ipfs://file1", 0, "", "", "", "", "", "", "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -131,7 +138,7 @@ func TestCalculateTaskFees_MultipleURLs_PartialFailures(t *testing.T) {
 		logger:         &MockLogger{},
 	}
 
-	total, _, err := h.CalculateTaskFees("ipfs://file1, ipfs://file2, ipfs://file3", 0, "", "", "", "", "", "")
+	total, _, err := h.CalculateTaskFees("ipfs://file1, ipfs://file2, ipfs://file3", 0, "", "", "", "", "", "", "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -140,6 +147,66 @@ func TestCalculateTaskFees_MultipleURLs_PartialFailures(t *testing.T) {
 	}
 }
 
+func TestCalculateTaskFees_QuotaExceeded_ReturnsTypedError(t *testing.T) {
+	fake := NewFakeDockerExecutor()
+	fake.responses["ipfs://file1"] = &dextypes.ExecutionResult{
+		Stats:   dextypes.DockerResourceStats{TotalCost: big.NewInt(100), CurrentTotalCost: big.NewInt(100)},
+		Success: true,
+	}
+	fake.errors["ipfs://file2"] = fmt.Errorf("execution failed: %w", &dextypes.QuotaExceededError{
+		TenantID: "0xabc",
+		Resource: dextypes.QuotaResourceCPUSeconds,
+	})
+
+	h := &Handler{
+		dockerExecutor: fake,
+		logger:         &MockLogger{},
+	}
+
+	total, _, err := h.CalculateTaskFees("ipfs://file1, ipfs://file2", 2, "", "", "", "", "", "", "0xabc")
+	quotaErr, ok := dextypes.AsQuotaExceeded(err)
+	if !ok {
+		t.Fatalf("expected quota exceeded error, got %v", err)
+	}
+	if quotaErr.TenantID != "0xabc" {
+		t.Fatalf("expected tenant 0xabc, got %s", quotaErr.TenantID)
+	}
+	if total.Cmp(big.NewInt(0)) != 0 {
+		t.Fatalf("expected zero total, got %s", total.String())
+	}
+}
+
+func TestGetTaskFees_QuotaExceeded_Returns429(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("TEST_TASK_EXECUTION_ADDRESS", "0xexecutor")
+
+	fake := NewFakeDockerExecutor()
+	fake.errors["ipfs://a"] = &dextypes.QuotaExceededError{
+		TenantID:   "0xabc",
+		Resource:   dextypes.QuotaResourceConcurrency,
+		RetryAfter: 30 * time.Second,
+	}
+
+	h := &Handler{dockerExecutor: fake, logger: &MockLogger{}}
+
+	r := gin.New()
+	r.GET("/fees", h.GetTaskFees)
+
+	req := httptest.NewRequest(http.MethodGet, "/fees?task_definition_id=2&ipfs_url=ipfs://a&user_address=0xABC", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "30" {
+		t.Fatalf("expected Retry-After 30, got %q", w.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(w.Body.String(), "QUOTA_EXCEEDED") {
+		t.Fatalf("expected QUOTA_EXCEEDED code, got %s", w.Body.String())
+	}
+}
+
 func TestGetTaskFees_HTTP(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -165,3 +232,35 @@ func TestGetTaskFees_HTTP(t *testing.T) {
 		t.Fatalf("expected response to contain 120, got %s", w.Body.String())
 	}
 }
+
+func TestGetTenantID_FromCredentialsOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &Handler{logger: &MockLogger{}}
+
+	tests := []struct {
+		name     string
+		setup    func(c *gin.Context)
+		expected string
+	}{
+		{"anonymous callers share the default tenant", func(c *gin.Context) {}, ""},
+		{"wallet session", func(c *gin.Context) { c.Set(middleware.WalletAddressKey, "0xABC") }, "0xabc"},
+		{"api key", func(c *gin.Context) {
+			c.Set(middleware.ApiKeyContextKey, &commonTypes.ApiKey{Key: "TGRX-1a2b3c4d"})
+		}, "TGRX-1a2b3c4d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			// Neither the query nor the API key header may choose the tenant
+			c.Request = httptest.NewRequest(http.MethodGet, "/fees?user_address=0xdef", nil)
+			c.Request.Header.Set("X-Api-Key", "unverified")
+			tt.setup(c)
+
+			if got := h.getTenantID(c); got != tt.expected {
+				t.Fatalf("expected tenant %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
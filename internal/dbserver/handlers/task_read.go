@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
@@ -72,20 +73,32 @@ func (h *Handler) GetTasksByJobID(c *gin.Context) {
 		return
 	}
 
+	opts, err := parseListOptions(c, types.DefaultListLimit)
+	if err != nil {
+		h.logger.Errorf("[GetTasksByJobID] Invalid list options: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_LIST_OPTIONS",
+		})
+		return
+	}
+
 	h.logger.Infof("[GetTasksByJobID] Retrieving tasks for job ID: %s | %s", jobIDStr, jobID.String())
 
-	tasks, err := h.fetchTasksForJob(jobID)
+	page, err := h.fetchTasksForJob(jobID, opts)
 	if err != nil {
 		h.logger.Errorf("[GetTasksByJobID] Error retrieving tasks for jobID %s: %v", jobID.String(), err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "No tasks found for this job",
-			"code":  "TASKS_NOT_FOUND",
-		})
+		respondListError(c, err, http.StatusNotFound, "No tasks found for this job", "TASKS_NOT_FOUND")
 		return
 	}
 
-	h.logger.Infof("[GetTasksByJobID] Successfully retrieved %d tasks for job ID: %s", len(tasks), jobID.String())
-	c.JSON(http.StatusOK, tasks)
+	h.logger.Infof("[GetTasksByJobID] Successfully retrieved %d tasks for job ID: %s", len(page.Items), jobID.String())
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":      jobID.String(),
+		"tasks":       page.Items,
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
+	})
 }
 
 func (h *Handler) GetTasksByUserAddress(c *gin.Context) {
@@ -102,11 +115,21 @@ func (h *Handler) GetTasksByUserAddress(c *gin.Context) {
 		return
 	}
 
+	opts, err := parseListOptions(c, types.DefaultTaskGroupsLimit)
+	if err != nil {
+		h.logger.Errorf("[GetTasksByUserAddress] Invalid list options: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_LIST_OPTIONS",
+		})
+		return
+	}
+
 	trackDBOp := metrics.TrackDBOperation("read", "user_data")
-	_, jobIDs, err := h.userRepository.GetUserJobIDsByAddress(userAddress)
+	userID, err := h.userRepository.GetUserIDByAddress(userAddress)
 	trackDBOp(err)
 	if err != nil {
-		h.logger.Errorf("[GetTasksByUserAddress] Error retrieving jobs for user %s: %v", userAddress, err)
+		h.logger.Errorf("[GetTasksByUserAddress] Error retrieving user %s: %v", userAddress, err)
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "User not found",
 			"code":  "USER_NOT_FOUND",
@@ -114,16 +137,27 @@ func (h *Handler) GetTasksByUserAddress(c *gin.Context) {
 		return
 	}
 
-	if len(jobIDs) == 0 {
-		h.logger.Infof("[GetTasksByUserAddress] No jobs found for user %s", userAddress)
-		c.JSON(http.StatusOK, gin.H{
-			"user_address": userAddress,
-			"task_groups":  []types.TasksByJobGroupResponse{},
-		})
+	// The page is a page of the user's jobs, filtered by chain and task definition, each with
+	// the first page of its tasks, filtered by status and creation time
+	jobOpts := opts
+	jobOpts.Status, jobOpts.From, jobOpts.To = "", time.Time{}, time.Time{}
+	trackDBOp = metrics.TrackDBOperation("read", "job_data")
+	jobs, err := h.jobRepository.ListJobsByUserID(userID, jobOpts)
+	trackDBOp(err)
+	if err != nil {
+		h.logger.Errorf("[GetTasksByUserAddress] Error retrieving jobs for user %s: %v", userAddress, err)
+		respondListError(c, err, http.StatusNotFound, "No tasks found for the requested user", "TASKS_NOT_FOUND")
 		return
 	}
 
-	taskGroups, err := h.getTasksGroupedByJob(jobIDs)
+	jobIDs := make([]*big.Int, 0, len(jobs.Items))
+	for _, job := range jobs.Items {
+		if job.JobID != nil {
+			jobIDs = append(jobIDs, job.JobID.ToBigInt())
+		}
+	}
+
+	taskGroups, err := h.getTasksGroupedByJob(jobIDs, taskGroupOptions(opts))
 	if err != nil {
 		h.logger.Errorf("[GetTasksByUserAddress] Error retrieving tasks for user %s: %v", userAddress, err)
 		c.JSON(http.StatusNotFound, gin.H{
@@ -137,6 +171,8 @@ func (h *Handler) GetTasksByUserAddress(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"user_address": userAddress,
 		"task_groups":  taskGroups,
+		"next_cursor":  jobs.NextCursor,
+		"has_more":     jobs.HasMore,
 	})
 }
 
@@ -157,7 +193,7 @@ func (h *Handler) GetTasksByApiKey(c *gin.Context) {
 	h.logger.Infof("[GetTasksByApiKey] Using api_key parameter for lookup")
 
 	trackDBOp := metrics.TrackDBOperation("read", "apikeys")
-	apiKeyData, err := h.apiKeysRepository.GetApiKeyDataByKey(requestedAPIKey)
+	apiKeyData, err := h.apiKeysRepository.AuthenticateApiKey(requestedAPIKey)
 	trackDBOp(err)
 	if err != nil {
 		h.logger.Errorf("[GetTasksByApiKey] Invalid API key: %v", err)
@@ -233,7 +269,7 @@ func (h *Handler) GetTasksBySafeAddress(c *gin.Context) {
 		return
 	}
 
-	taskGroups, err := h.getTasksGroupedByJob(jobIDs)
+	taskGroups, err := h.getTasksGroupedByJob(jobIDs, taskGroupOptions(types.ListOptions{Order: types.SortDesc}))
 	if err != nil {
 		h.logger.Errorf("[GetTasksBySafeAddress] Error retrieving tasks for safe address %s: %v", safeAddress, err)
 		c.JSON(http.StatusNotFound, gin.H{
@@ -277,7 +313,20 @@ func getExplorerBaseURL(chainID string) string {
 	}
 }
 
-func (h *Handler) getTasksGroupedByJob(jobIDs []*big.Int) ([]types.TasksByJobGroupResponse, error) {
+// taskGroupOptions returns the options of each job's tasks when tasks are listed grouped by
+// job, the filters of opts that apply to tasks. A group's next_cursor continues its tasks on
+// /tasks/job/:job_id with the same status, from, to and order.
+func taskGroupOptions(opts types.ListOptions) types.ListOptions {
+	return types.ListOptions{
+		Limit:  types.DefaultTasksPerJob,
+		Order:  opts.Order,
+		Status: opts.Status,
+		From:   opts.From,
+		To:     opts.To,
+	}
+}
+
+func (h *Handler) getTasksGroupedByJob(jobIDs []*big.Int, opts types.ListOptions) ([]types.TasksByJobGroupResponse, error) {
 	taskGroups := make([]types.TasksByJobGroupResponse, 0, len(jobIDs))
 	seen := make(map[string]struct{})
 
@@ -292,35 +341,37 @@ func (h *Handler) getTasksGroupedByJob(jobIDs []*big.Int) ([]types.TasksByJobGro
 		}
 		seen[jobIDStr] = struct{}{}
 
-		tasks, err := h.fetchTasksForJob(jobID)
+		page, err := h.fetchTasksForJob(jobID, opts)
 		if err != nil {
 			return nil, err
 		}
 
 		taskGroups = append(taskGroups, types.TasksByJobGroupResponse{
-			JobID: jobIDStr,
-			Tasks: tasks,
+			JobID:      jobIDStr,
+			Tasks:      page.Items,
+			NextCursor: page.NextCursor,
+			HasMore:    page.HasMore,
 		})
 	}
 
 	return taskGroups, nil
 }
 
-func (h *Handler) fetchTasksForJob(jobID *big.Int) ([]types.TasksByJobIDResponse, error) {
+func (h *Handler) fetchTasksForJob(jobID *big.Int, opts types.ListOptions) (types.Page[types.TasksByJobIDResponse], error) {
 	trackTasksOp := metrics.TrackDBOperation("read", "task_data")
-	tasksData, err := h.taskRepository.GetTasksByJobID(jobID)
+	page, err := h.taskRepository.ListTasksByJobID(jobID, opts)
 	trackTasksOp(err)
 	if err != nil {
-		return nil, err
+		return types.Page[types.TasksByJobIDResponse]{}, err
 	}
 
-	tasks := convertTasksData(tasksData)
+	tasks := convertTasksData(page.Items)
 
 	trackChainOp := metrics.TrackDBOperation("read", "job_data")
 	createdChainID, err := h.taskRepository.GetCreatedChainIDByJobID(jobID)
 	trackChainOp(err)
 	if err != nil {
-		return nil, err
+		return types.Page[types.TasksByJobIDResponse]{}, err
 	}
 
 	explorerBaseURL := getExplorerBaseURL(createdChainID)
@@ -330,7 +381,11 @@ func (h *Handler) fetchTasksForJob(jobID *big.Int) ([]types.TasksByJobIDResponse
 		}
 	}
 
-	return tasks, nil
+	return types.Page[types.TasksByJobIDResponse]{
+		Items:      tasks,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}, nil
 }
 
 func convertTasksData(tasksData []types.GetTasksByJobID) []types.TasksByJobIDResponse {
@@ -357,41 +412,39 @@ func (h *Handler) GetRecentTasks(c *gin.Context) {
 	traceID := h.getTraceID(c)
 	h.logger.Infof("[GetRecentTasks] trace_id=%s - Retrieving recent tasks", traceID)
 
-	// Parse limit from query parameter, default to 200
-	limitStr := c.DefaultQuery("limit", "200")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		h.logger.Errorf("[GetRecentTasks] Invalid limit parameter: %s", limitStr)
+	// Pages default to the maximum of 200 tasks
+	opts, err := parseListOptions(c, types.MaxListLimit)
+	if err != nil {
+		h.logger.Errorf("[GetRecentTasks] Invalid list options: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid limit parameter",
-			"code":  "INVALID_LIMIT",
+			"error": err.Error(),
+			"code":  "INVALID_LIST_OPTIONS",
 		})
 		return
 	}
 
-	// Enforce maximum limit of 200
-	if limit > 200 {
-		limit = 200
-	}
-
-	h.logger.Infof("[GetRecentTasks] Fetching recent tasks with limit: %d", limit)
+	h.logger.Infof("[GetRecentTasks] Fetching recent tasks with limit: %d", opts.Limit)
 
 	trackDBOp := metrics.TrackDBOperation("read", "task_data")
-	tasks, err := h.taskRepository.GetRecentTasks(limit)
+	page, err := h.taskRepository.ListRecentTasks(opts)
 	trackDBOp(err)
 	if err != nil {
 		h.logger.Errorf("[GetRecentTasks] Error retrieving recent tasks: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve recent tasks",
-			"code":  "TASKS_FETCH_ERROR",
-		})
+		respondListError(c, err, http.StatusInternalServerError, "Failed to retrieve recent tasks", "TASKS_FETCH_ERROR")
 		return
 	}
 
+	tasks := page.Items
+	if tasks == nil {
+		tasks = []types.RecentTaskResponse{}
+	}
+
 	h.logger.Infof("[GetRecentTasks] Successfully retrieved %d recent tasks", len(tasks))
 	c.JSON(http.StatusOK, gin.H{
-		"tasks": tasks,
-		"count": len(tasks),
-		"limit": limit,
+		"tasks":       tasks,
+		"count":       len(tasks),
+		"limit":       opts.Limit,
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
 	})
 }
@@ -51,8 +51,11 @@ func (f *fakeTaskRepo) GetTaskFee(taskID int64) (float64, error)          { retu
 func (f *fakeTaskRepo) GetCreatedChainIDByJobID(jobID *big.Int) (string, error) {
 	return f.createdChainID, f.createdChainIDErr
 }
-func (f *fakeTaskRepo) GetRecentTasks(limit int) ([]dbtypes.RecentTaskResponse, error) {
-	return f.getRecentTasksResp, f.getRecentTasksErr
+func (f *fakeTaskRepo) ListTasksByJobID(jobID *big.Int, opts dbtypes.ListOptions) (dbtypes.Page[dbtypes.GetTasksByJobID], error) {
+	return dbtypes.Page[dbtypes.GetTasksByJobID]{Items: f.tasksByJob}, f.getTasksErr
+}
+func (f *fakeTaskRepo) ListRecentTasks(opts dbtypes.ListOptions) (dbtypes.Page[dbtypes.RecentTaskResponse], error) {
+	return dbtypes.Page[dbtypes.RecentTaskResponse]{Items: f.getRecentTasksResp}, f.getRecentTasksErr
 }
 
 func TestGetTaskDataByID_ErrorsAndSuccess(t *testing.T) {
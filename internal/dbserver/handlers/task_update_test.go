@@ -44,8 +44,11 @@ func (f *fakeTaskRepoUpdate) AddTaskIDToJob(jobID *big.Int, taskID int64) error
 func (f *fakeTaskRepoUpdate) UpdateTaskFee(taskID int64, fee float64) error           { return f.updateFeeErr }
 func (f *fakeTaskRepoUpdate) GetTaskFee(taskID int64) (float64, error)                { return 0, nil }
 func (f *fakeTaskRepoUpdate) GetCreatedChainIDByJobID(jobID *big.Int) (string, error) { return "", nil }
-func (f *fakeTaskRepoUpdate) GetRecentTasks(limit int) ([]dbtypes.RecentTaskResponse, error) {
-	return []dbtypes.RecentTaskResponse{}, nil
+func (f *fakeTaskRepoUpdate) ListTasksByJobID(jobID *big.Int, opts dbtypes.ListOptions) (dbtypes.Page[dbtypes.GetTasksByJobID], error) {
+	return dbtypes.Page[dbtypes.GetTasksByJobID]{}, nil
+}
+func (f *fakeTaskRepoUpdate) ListRecentTasks(opts dbtypes.ListOptions) (dbtypes.Page[dbtypes.RecentTaskResponse], error) {
+	return dbtypes.Page[dbtypes.RecentTaskResponse]{}, nil
 }
 
 func TestUpdateTaskExecutionData_ValidationAndRepoErrors(t *testing.T) {
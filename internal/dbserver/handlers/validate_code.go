@@ -12,6 +12,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/config"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/analysis"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
 )
 
 type ValidateCodeRequest struct {
@@ -21,6 +23,7 @@ type ValidateCodeRequest struct {
 	TargetFunction   string `json:"target_function" binding:"required_unless=TaskDefinitionID 7"`
 	TaskDefinitionID int    `json:"task_definition_id" binding:"required"`
 	IsSafe           bool   `json:"is_safe"`
+	TenantID         string `json:"-"` // Tenant the validation run is scheduled against, set from the caller's credentials
 }
 
 type ValidateCodeResponse struct {
@@ -28,6 +31,19 @@ type ValidateCodeResponse struct {
 	Output     string `json:"output"`
 	Error      string `json:"error,omitempty"`
 	SafeMatch  bool   `json:"safe_match"`
+
+	// Static analysis findings with line numbers, warnings are reported even when the code executes
+	Findings []analysis.Finding `json:"findings,omitempty"`
+}
+
+func countErrors(findings []analysis.Finding) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Severity == analysis.SeverityError {
+			count++
+		}
+	}
+	return count
 }
 
 // generateCacheKey creates a cache key from validation parameters
@@ -60,28 +76,39 @@ func (h *Handler) ValidateCodeInternal(ctx context.Context, req ValidateCodeRequ
 		}
 	}
 
-	result, err := h.dockerExecutor.ExecuteSource(ctx, req.Code, req.Language, alchemyAPIKey)
+	// Reject scripts with static analysis errors before spending a container on them
+	findings := analysis.Analyze(req.Code, types.Language(strings.ToLower(req.Language)))
+	if analysis.HasErrors(findings) {
+		resp := ValidateCodeResponse{
+			Executable: false,
+			Error:      fmt.Sprintf("static analysis found %d error(s)", countErrors(findings)),
+			SafeMatch:  !req.IsSafe,
+			Findings:   findings,
+		}
+		h.logger.Infof("[ValidateCodeInternal] Static analysis rejected code | lang=%s target=%s findings=%d",
+			req.Language, req.TargetFunction, len(findings))
+		h.cacheValidationResponse(ctx, ipfsUrl, req, resp)
+		return resp, nil
+	}
+
+	metadata := map[string]string{
+		types.MetadataKeyTenantID: req.TenantID,
+	}
+	result, err := h.dockerExecutor.ExecuteSource(ctx, req.Code, req.Language, alchemyAPIKey, metadata)
 	if err != nil {
+		// Over-quota rejections say nothing about the code, so they are returned to the caller and never cached
+		if _, ok := types.AsQuotaExceeded(err); ok {
+			return ValidateCodeResponse{Executable: false, Error: err.Error(), SafeMatch: !req.IsSafe}, err
+		}
 		// If IsSafe is false, SafeMatch is always true
 		safeMatch := !req.IsSafe
-		resp := ValidateCodeResponse{Executable: false, Output: "", Error: err.Error(), SafeMatch: safeMatch}
+		resp := ValidateCodeResponse{Executable: false, Output: "", Error: err.Error(), SafeMatch: safeMatch, Findings: findings}
 		// Log validation result (failure)
 		h.logger.Infof("[ValidateCodeInternal] Validation result | lang=%s target=%s isSafe=%t selectedSafe=%s executable=%t safeMatch=%t error=%s",
 			req.Language, req.TargetFunction, req.IsSafe, req.SelectedSafe, resp.Executable, resp.SafeMatch, err.Error())
 
-		// Cache the failure response if Redis client is available and IPFS URL is provided
-		if h.redisClient != nil && ipfsUrl != "" {
-			cacheKey := h.generateCacheKey(ipfsUrl, req)
-			respJSON, err := json.Marshal(resp)
-			if err == nil {
-				// Cache for 24 hours
-				if err := h.redisClient.Set(ctx, cacheKey, string(respJSON), 24*time.Hour); err != nil {
-					h.logger.Warnf("[ValidateCodeInternal] Failed to cache validation result: %v", err)
-				} else {
-					h.logger.Infof("[ValidateCodeInternal] Cached validation result for IPFS URL: %s", ipfsUrl)
-				}
-			}
-		}
+		// Cache the failure response
+		h.cacheValidationResponse(ctx, ipfsUrl, req, resp)
 
 		return resp, nil
 	}
@@ -134,29 +161,36 @@ func (h *Handler) ValidateCodeInternal(ctx context.Context, req ValidateCodeRequ
 		Executable: result.Success,
 		Output:     result.Output,
 		SafeMatch:  safeMatch,
+		Findings:   findings,
 	}
 	if result.Error != nil {
 		resp.Error = result.Error.Error()
 	}
 
-	// Cache the full response if Redis client is available and IPFS URL is provided
 	// Cache both success and failure cases with validation-parameter key
-	if h.redisClient != nil && ipfsUrl != "" {
-		cacheKey := h.generateCacheKey(ipfsUrl, req)
-		respJSON, err := json.Marshal(resp)
-		if err == nil {
-			// Cache for 24 hours
-			if err := h.redisClient.Set(ctx, cacheKey, string(respJSON), 24*time.Hour); err != nil {
-				h.logger.Warnf("[ValidateCodeInternal] Failed to cache validation result: %v", err)
-			} else {
-				h.logger.Infof("[ValidateCodeInternal] Cached validation result for IPFS URL: %s", ipfsUrl)
-			}
-		}
-	}
+	h.cacheValidationResponse(ctx, ipfsUrl, req, resp)
 
 	return resp, nil
 }
 
+// cacheValidationResponse caches a response if Redis client is available and IPFS URL is provided
+func (h *Handler) cacheValidationResponse(ctx context.Context, ipfsUrl string, req ValidateCodeRequest, resp ValidateCodeResponse) {
+	if h.redisClient == nil || ipfsUrl == "" {
+		return
+	}
+	cacheKey := h.generateCacheKey(ipfsUrl, req)
+	respJSON, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	// Cache for 24 hours
+	if err := h.redisClient.Set(ctx, cacheKey, string(respJSON), 24*time.Hour); err != nil {
+		h.logger.Warnf("[ValidateCodeInternal] Failed to cache validation result: %v", err)
+	} else {
+		h.logger.Infof("[ValidateCodeInternal] Cached validation result for IPFS URL: %s", ipfsUrl)
+	}
+}
+
 // ValidateCodeExecutable validates if provided code compiles/executes successfully in a sandbox (HTTP handler)
 func (h *Handler) ValidateCodeExecutable(c *gin.Context) {
 	var req ValidateCodeRequest
@@ -164,8 +198,13 @@ func (h *Handler) ValidateCodeExecutable(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.TenantID = h.getTenantID(c)
 	// For HTTP endpoint, no IPFS URL is provided, so pass empty string
-	resp, _ := h.ValidateCodeInternal(c.Request.Context(), req, "", config.GetAlchemyAPIKey())
+	resp, err := h.ValidateCodeInternal(c.Request.Context(), req, "", config.GetAlchemyAPIKey())
+	if quotaErr, ok := types.AsQuotaExceeded(err); ok {
+		h.respondQuotaExceeded(c, quotaErr)
+		return
+	}
 	// Log the HTTP request + response coupling with trace if available
 	traceID := h.getTraceID(c)
 	h.logger.Infof("[ValidateCodeExecutable] trace=%s lang=%s target=%s isSafe=%t selectedSafe=%s -> executable=%t safeMatch=%t error=%q",
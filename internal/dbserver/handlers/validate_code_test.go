@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/analysis"
+)
+
+func TestValidateCodeInternal_StaticAnalysisErrors_SkipsExecution(t *testing.T) {
+	code := "import subprocess\nprint(subprocess.check_output(['ls']))\n"
+	fake := NewFakeDockerExecutor()
+	fake.errors[code] = errors.New("executor must not be called")
+	h := &Handler{dockerExecutor: fake, logger: &MockLogger{}}
+
+	resp, err := h.ValidateCodeInternal(context.Background(), ValidateCodeRequest{Code: code, Language: "py"}, "", "")
+
+	require.NoError(t, err)
+	assert.False(t, resp.Executable)
+	assert.True(t, resp.SafeMatch)
+	assert.Equal(t, "static analysis found 1 error(s)", resp.Error)
+	require.Len(t, resp.Findings, 1)
+	assert.Equal(t, analysis.RuleProcessExec, resp.Findings[0].Rule)
+	assert.Equal(t, 1, resp.Findings[0].Line)
+}
+
+func TestValidateCodeInternal_StaticAnalysisWarnings_StillExecutes(t *testing.T) {
+	code := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfor {\n\t\tfmt.Println(\"[]\")\n\t}\n}\n"
+	fake := NewFakeDockerExecutor()
+	h := &Handler{dockerExecutor: fake, logger: &MockLogger{}}
+
+	resp, err := h.ValidateCodeInternal(context.Background(), ValidateCodeRequest{Code: code, Language: "Go"}, "", "")
+
+	require.NoError(t, err)
+	assert.True(t, resp.Executable)
+	require.Len(t, resp.Findings, 1)
+	assert.Equal(t, analysis.RuleInfiniteLoop, resp.Findings[0].Rule)
+	assert.Equal(t, analysis.SeverityWarning, resp.Findings[0].Severity)
+	assert.Equal(t, 6, resp.Findings[0].Line)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+)
+
+// GetAuthNonce handles GET /auth/nonce, issuing the nonce of a sign-in message
+func (h *Handler) GetAuthNonce(c *gin.Context) {
+	if h.authenticator == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Wallet login is not available"})
+		return
+	}
+
+	nonce, err := h.authenticator.Nonce(c.Request.Context())
+	if err != nil {
+		h.logger.Errorf("[GetAuthNonce] Error issuing nonce: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue nonce"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"nonce": nonce})
+}
+
+// WalletLogin handles POST /auth/login, exchanging a signed sign-in message for a session
+// token
+func (h *Handler) WalletLogin(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	if h.authenticator == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Wallet login is not available"})
+		return
+	}
+
+	var req types.WalletLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message and signature are required"})
+		return
+	}
+
+	session, err := h.authenticator.Login(c.Request.Context(), req.Message, req.Signature)
+	switch {
+	case errors.Is(err, auth.ErrInvalidMessage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, auth.ErrNonceUsed), errors.Is(err, auth.ErrInvalidSignature):
+		h.logger.Warnf("[WalletLogin] trace_id=%s - Rejected login: %v", traceID, err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		h.logger.Errorf("[WalletLogin] trace_id=%s - Error logging in: %v", traceID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log in"})
+		return
+	}
+
+	h.logger.Infof("[WalletLogin] trace_id=%s - Logged in %s", traceID, session.Address)
+	c.JSON(http.StatusOK, session)
+}
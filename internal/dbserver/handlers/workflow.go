@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/middleware"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// CreateWorkflow handles POST /workflows. The jobs of a workflow must belong to the caller and
+// be in no other workflow, they are paused so they only run when the workflow runs them.
+func (h *Handler) CreateWorkflow(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[CreateWorkflow] trace_id=%s - Creating workflow", traceID)
+
+	userID, ok := h.getCallerUserID(c, "CreateWorkflow")
+	if !ok {
+		return
+	}
+	request, jobs, ok := h.bindWorkflowRequest(c, "CreateWorkflow", userID)
+	if !ok {
+		return
+	}
+
+	workflow := &types.Workflow{
+		UserID:     userID,
+		Name:       request.Name,
+		Definition: request.Definition,
+	}
+	trackDBOp := metrics.TrackDBOperation("create", "workflows")
+	err := h.workflowRepository.CreateWorkflow(workflow)
+	trackDBOp(err)
+	if err != nil {
+		h.respondWorkflowError(c, "CreateWorkflow", err)
+		return
+	}
+
+	if err := h.pauseWorkflowJobs(jobs); err != nil {
+		h.logger.Errorf("[CreateWorkflow] Failed to pause the jobs of workflow %s: %v", workflow.WorkflowID, err)
+		if deleteErr := h.workflowRepository.DeleteWorkflow(*workflow); deleteErr != nil {
+			h.logger.Errorf("[CreateWorkflow] Failed to delete workflow %s: %v", workflow.WorkflowID, deleteErr)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to pause the jobs of the workflow",
+			"code":  "JOB_LIFECYCLE_ERROR",
+		})
+		return
+	}
+
+	h.logger.Infof("[CreateWorkflow] Created workflow %s with %d jobs", workflow.WorkflowID, len(workflow.Definition.Nodes))
+	c.JSON(http.StatusCreated, workflow)
+}
+
+// GetWorkflows handles GET /workflows, listing the caller's workflows
+func (h *Handler) GetWorkflows(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[GetWorkflows] trace_id=%s - Listing workflows", traceID)
+
+	opts, err := parseListOptions(c, types.DefaultListLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_LIST_OPTIONS",
+		})
+		return
+	}
+	userID, ok := h.getCallerUserID(c, "GetWorkflows")
+	if !ok {
+		return
+	}
+
+	trackDBOp := metrics.TrackDBOperation("read", "workflows")
+	page, err := h.workflowRepository.ListWorkflowsByUserID(userID, opts)
+	trackDBOp(err)
+	if err != nil {
+		h.logger.Errorf("[GetWorkflows] Error listing workflows of user %d: %v", userID, err)
+		respondListError(c, err, http.StatusInternalServerError, "Failed to retrieve workflows", "WORKFLOW_RETRIEVAL_ERROR")
+		return
+	}
+
+	workflows := page.Items
+	if workflows == nil {
+		workflows = []types.Workflow{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workflows":   workflows,
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
+	})
+}
+
+// GetWorkflow handles GET /workflows/:workflow_id
+func (h *Handler) GetWorkflow(c *gin.Context) {
+	workflow, ok := h.getOwnedWorkflow(c, "GetWorkflow")
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, workflow)
+}
+
+// UpdateWorkflow handles PUT /workflows/:workflow_id, replacing the name and definition of a
+// workflow without an active run. Jobs it adds are paused, jobs it drops stay paused.
+func (h *Handler) UpdateWorkflow(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[UpdateWorkflow] trace_id=%s - Updating workflow", traceID)
+
+	current, ok := h.getOwnedWorkflow(c, "UpdateWorkflow")
+	if !ok {
+		return
+	}
+	request, jobs, ok := h.bindWorkflowRequest(c, "UpdateWorkflow", current.UserID)
+	if !ok {
+		return
+	}
+
+	workflow := &types.Workflow{
+		Name:       request.Name,
+		Definition: request.Definition,
+	}
+	trackDBOp := metrics.TrackDBOperation("update", "workflows")
+	err := h.workflowRepository.UpdateWorkflow(current, workflow)
+	trackDBOp(err)
+	if err != nil {
+		h.respondWorkflowError(c, "UpdateWorkflow", err)
+		return
+	}
+
+	if err := h.pauseWorkflowJobs(jobs); err != nil {
+		h.logger.Errorf("[UpdateWorkflow] Failed to pause the jobs of workflow %s: %v", workflow.WorkflowID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Workflow updated but its jobs could not all be paused, update it again to retry",
+			"code":  "JOB_LIFECYCLE_ERROR",
+		})
+		return
+	}
+
+	h.logger.Infof("[UpdateWorkflow] Updated workflow %s", workflow.WorkflowID)
+	c.JSON(http.StatusOK, workflow)
+}
+
+// DeleteWorkflow handles DELETE /workflows/:workflow_id, deleting a workflow without an
+// active run and its run history. Its jobs stay paused.
+func (h *Handler) DeleteWorkflow(c *gin.Context) {
+	workflow, ok := h.getOwnedWorkflow(c, "DeleteWorkflow")
+	if !ok {
+		return
+	}
+
+	trackDBOp := metrics.TrackDBOperation("delete", "workflows")
+	err := h.workflowRepository.DeleteWorkflow(workflow)
+	trackDBOp(err)
+	if err != nil {
+		h.respondWorkflowError(c, "DeleteWorkflow", err)
+		return
+	}
+
+	h.logger.Infof("[DeleteWorkflow] Deleted workflow %s", workflow.WorkflowID)
+	c.JSON(http.StatusOK, gin.H{"message": "Workflow deleted successfully"})
+}
+
+// GetWorkflowRuns handles GET /workflows/:workflow_id/runs, the run history of a workflow
+// newest first, filtered by run status and start time
+func (h *Handler) GetWorkflowRuns(c *gin.Context) {
+	opts, err := parseListOptions(c, types.DefaultListLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_LIST_OPTIONS",
+		})
+		return
+	}
+	workflow, ok := h.getOwnedWorkflow(c, "GetWorkflowRuns")
+	if !ok {
+		return
+	}
+
+	trackDBOp := metrics.TrackDBOperation("read", "workflow_runs")
+	page, err := h.workflowRepository.ListWorkflowRuns(workflow.WorkflowID, opts)
+	trackDBOp(err)
+	if err != nil {
+		h.logger.Errorf("[GetWorkflowRuns] Error listing runs of workflow %s: %v", workflow.WorkflowID, err)
+		respondListError(c, err, http.StatusInternalServerError, "Failed to retrieve workflow runs", "WORKFLOW_RETRIEVAL_ERROR")
+		return
+	}
+
+	runs := page.Items
+	if runs == nil {
+		runs = []types.WorkflowRun{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"runs":        runs,
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
+	})
+}
+
+// GetWorkflowRun handles GET /workflows/:workflow_id/runs/:run_id
+func (h *Handler) GetWorkflowRun(c *gin.Context) {
+	workflow, ok := h.getOwnedWorkflow(c, "GetWorkflowRun")
+	if !ok {
+		return
+	}
+
+	trackDBOp := metrics.TrackDBOperation("read", "workflow_runs")
+	run, err := h.workflowRepository.GetWorkflowRun(workflow.WorkflowID, c.Param("run_id"))
+	trackDBOp(err)
+	if err != nil {
+		h.respondWorkflowError(c, "GetWorkflowRun", err)
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// getCallerUserID returns the user ID of the caller's wallet session
+func (h *Handler) getCallerUserID(c *gin.Context, logTag string) (int64, bool) {
+	caller := strings.ToLower(c.GetString(middleware.WalletAddressKey))
+	if caller == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token is required"})
+		return 0, false
+	}
+
+	trackDBOp := metrics.TrackDBOperation("read", "users")
+	userID, err := h.userRepository.GetUserIDByAddress(caller)
+	trackDBOp(err)
+	if errors.Is(err, gocql.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+			"code":  "USER_NOT_FOUND",
+		})
+		return 0, false
+	}
+	if err != nil {
+		h.logger.Errorf("[%s] Error getting user ID for address %s: %v", logTag, caller, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// getOwnedWorkflow reads the workflow of the workflow_id route parameter, which must belong
+// to the caller
+func (h *Handler) getOwnedWorkflow(c *gin.Context, logTag string) (types.Workflow, bool) {
+	userID, ok := h.getCallerUserID(c, logTag)
+	if !ok {
+		return types.Workflow{}, false
+	}
+
+	trackDBOp := metrics.TrackDBOperation("read", "workflows")
+	workflow, err := h.workflowRepository.GetWorkflow(c.Param("workflow_id"))
+	trackDBOp(err)
+	if err != nil {
+		h.respondWorkflowError(c, logTag, err)
+		return types.Workflow{}, false
+	}
+	if workflow.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Caller does not own this workflow"})
+		return types.Workflow{}, false
+	}
+	return workflow, true
+}
+
+// bindWorkflowRequest decodes and validates a workflow, whose jobs must belong to userID and
+// not be finished, and returns the jobs
+func (h *Handler) bindWorkflowRequest(c *gin.Context, logTag string, userID int64) (types.WorkflowRequest, []*commonTypes.JobData, bool) {
+	var request types.WorkflowRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Errorf("[%s] Invalid request body: %v", logTag, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"code":  "INVALID_REQUEST",
+		})
+		return request, nil, false
+	}
+	if err := request.Definition.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_WORKFLOW",
+		})
+		return request, nil, false
+	}
+
+	jobs := make([]*commonTypes.JobData, 0, len(request.Definition.Nodes))
+	for _, node := range request.Definition.Nodes {
+		jobID, _ := new(big.Int).SetString(node.JobID, 10)
+		trackDBOp := metrics.TrackDBOperation("read", "job_data")
+		job, err := h.jobRepository.GetJobByID(jobID)
+		trackDBOp(err)
+		if err != nil {
+			h.logger.Errorf("[%s] Error getting job %s: %v", logTag, jobID, err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Job " + node.JobID + " not found",
+				"code":  "JOB_NOT_FOUND",
+			})
+			return request, nil, false
+		}
+		if job.UserID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Caller does not own job " + node.JobID})
+			return request, nil, false
+		}
+		if status := types.JobStatus(job.Status); !status.IsActive() && status != types.JobStatusPaused {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Job " + node.JobID + " is " + job.Status,
+				"code":  "INVALID_JOB_TRANSITION",
+			})
+			return request, nil, false
+		}
+		jobs = append(jobs, job)
+	}
+	return request, jobs, true
+}
+
+// pauseWorkflowJobs pauses the active jobs of a workflow
+func (h *Handler) pauseWorkflowJobs(jobs []*commonTypes.JobData) error {
+	for _, job := range jobs {
+		if err := h.stopWorkflowJob(job.JobID.ToBigInt()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// respondWorkflowError answers a failed workflow operation
+func (h *Handler) respondWorkflowError(c *gin.Context, logTag string, err error) {
+	h.logger.Errorf("[%s] %v", logTag, err)
+
+	switch {
+	case errors.Is(err, gocql.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Workflow not found",
+			"code":  "WORKFLOW_NOT_FOUND",
+		})
+	case errors.Is(err, repository.ErrJobInWorkflow):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+			"code":  "JOB_IN_WORKFLOW",
+		})
+	case errors.Is(err, repository.ErrWorkflowRunning):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Workflow has an active run, wait for it to finish or cancel it",
+			"code":  "WORKFLOW_RUNNING",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to process workflow",
+			"code":  "WORKFLOW_ERROR",
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+)
+
+// maxRunUpdateAttempts bounds the retries of a run update that raced another one, such as
+// two task completions of a fan-in
+const maxRunUpdateAttempts = 5
+
+// StartWorkflowRun handles POST /workflows/:workflow_id/runs, running the root jobs of a
+// workflow without an active run
+func (h *Handler) StartWorkflowRun(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[StartWorkflowRun] trace_id=%s - Starting workflow run", traceID)
+
+	workflow, ok := h.getOwnedWorkflow(c, "StartWorkflowRun")
+	if !ok {
+		return
+	}
+
+	run := types.NewWorkflowRun(workflow.WorkflowID, workflow.Definition, time.Now())
+	trackDBOp := metrics.TrackDBOperation("create", "workflow_runs")
+	err := h.workflowRepository.StartWorkflowRun(run)
+	trackDBOp(err)
+	if err != nil {
+		h.respondWorkflowError(c, "StartWorkflowRun", err)
+		return
+	}
+
+	run, err = h.startWorkflowJobs(workflow, run, run.Running())
+	if err != nil {
+		h.respondWorkflowError(c, "StartWorkflowRun", err)
+		return
+	}
+
+	h.logger.Infof("[StartWorkflowRun] Started run %s of workflow %s", run.RunID, workflow.WorkflowID)
+	c.JSON(http.StatusCreated, run)
+}
+
+// CancelWorkflowRun handles PUT /workflows/:workflow_id/runs/:run_id/cancel, skipping the
+// nodes that did not finish and pausing the jobs still running
+func (h *Handler) CancelWorkflowRun(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[CancelWorkflowRun] trace_id=%s - Cancelling workflow run", traceID)
+
+	workflow, ok := h.getOwnedWorkflow(c, "CancelWorkflowRun")
+	if !ok {
+		return
+	}
+
+	var running []string
+	run, err := h.advanceWorkflowRun(workflow, c.Param("run_id"), func(run *types.WorkflowRun) []string {
+		running = run.Running()
+		run.Cancel(time.Now())
+		return nil
+	})
+	if err != nil {
+		h.respondWorkflowError(c, "CancelWorkflowRun", err)
+		return
+	}
+
+	for _, jobID := range running {
+		if err := h.stopWorkflowJobByID(jobID); err != nil {
+			h.logger.Errorf("[CancelWorkflowRun] Failed to pause job %s: %v", jobID, err)
+		}
+	}
+
+	h.logger.Infof("[CancelWorkflowRun] Run %s of workflow %s is %s", run.RunID, workflow.WorkflowID, run.Status)
+	c.JSON(http.StatusOK, run)
+}
+
+// TaskCompleted handles POST /tasks/:id/completed, sent by the task monitor once a task's
+// result is submitted on chain. When the task's job is running for a workflow run, the job
+// is paused and the jobs the result leads to are started.
+func (h *Handler) TaskCompleted(c *gin.Context) {
+	traceID := h.getTraceID(c)
+	h.logger.Infof("[TaskCompleted] trace_id=%s - Task completed", traceID)
+
+	var request types.TaskCompletedRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Errorf("[TaskCompleted] Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"code":  "INVALID_REQUEST",
+		})
+		return
+	}
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || taskID != request.TaskID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "task_id does not match the task in the path",
+			"code":  "INVALID_REQUEST",
+		})
+		return
+	}
+	jobID, ok := new(big.Int).SetString(request.JobID, 10)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid job ID format",
+			"code":  "INVALID_JOB_ID",
+		})
+		return
+	}
+
+	run, err := h.completeWorkflowTask(jobID, request)
+	if err != nil {
+		h.respondWorkflowError(c, "TaskCompleted", err)
+		return
+	}
+	if run == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Task is not part of a workflow run"})
+		return
+	}
+
+	h.logger.Infof("[TaskCompleted] Task %d completed job %s in run %s, run is %s", taskID, jobID, run.RunID, run.Status)
+	c.JSON(http.StatusOK, run)
+}
+
+// completeWorkflowTask records the task's result in the active run of the workflow of its
+// job, nil when the job is not running for a run
+func (h *Handler) completeWorkflowTask(jobID *big.Int, request types.TaskCompletedRequest) (*types.WorkflowRun, error) {
+	workflowID, err := h.workflowRepository.GetWorkflowIDByJobID(jobID)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow of job %s: %w", jobID, err)
+	}
+	workflow, err := h.workflowRepository.GetWorkflow(workflowID)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if workflow.ActiveRunID == "" {
+		return nil, nil
+	}
+
+	run, err := h.workflowRepository.GetWorkflowRun(workflow.WorkflowID, workflow.ActiveRunID)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := run.Nodes[jobID.String()]
+	if !ok || node.Status != types.WorkflowNodeRunning {
+		return nil, nil
+	}
+
+	// The job ran once for the run, it stays paused until a run starts it again
+	if err := h.stopWorkflowJob(jobID); err != nil {
+		h.logger.Errorf("[TaskCompleted] Failed to pause job %s after its task: %v", jobID, err)
+	}
+
+	result := types.WorkflowTaskResult{
+		TaskID:  request.TaskID,
+		Success: request.IsAccepted,
+		Output:  request.Output,
+	}
+	if !request.IsAccepted {
+		result.Error = "task was rejected"
+	}
+	return h.advanceWorkflowRun(workflow, run.RunID, func(run *types.WorkflowRun) []string {
+		return run.Complete(workflow.Definition, jobID.String(), result, time.Now())
+	})
+}
+
+// advanceWorkflowRun applies change to the latest state of a run and stores it, retrying when
+// the run changed concurrently, then starts the jobs of the nodes change returned
+func (h *Handler) advanceWorkflowRun(workflow types.Workflow, runID string, change func(*types.WorkflowRun) []string) (*types.WorkflowRun, error) {
+	for attempt := 0; attempt < maxRunUpdateAttempts; attempt++ {
+		trackDBOp := metrics.TrackDBOperation("read", "workflow_runs")
+		run, err := h.workflowRepository.GetWorkflowRun(workflow.WorkflowID, runID)
+		trackDBOp(err)
+		if err != nil {
+			return nil, err
+		}
+		if run.IsFinished() {
+			return &run, nil
+		}
+
+		started := change(&run)
+		trackDBOp = metrics.TrackDBOperation("update", "workflow_runs")
+		err = h.workflowRepository.UpdateWorkflowRun(&run)
+		trackDBOp(err)
+		if errors.Is(err, repository.ErrWorkflowRunChanged) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return h.startWorkflowJobs(workflow, &run, started)
+	}
+	return nil, repository.ErrWorkflowRunChanged
+}
+
+// startWorkflowJobs starts the jobs of nodes of a run, a node whose job can not be started
+// fails. It returns the run as it is after the failures.
+func (h *Handler) startWorkflowJobs(workflow types.Workflow, run *types.WorkflowRun, jobIDs []string) (*types.WorkflowRun, error) {
+	for _, jobID := range jobIDs {
+		startErr := h.startWorkflowJob(jobID)
+		if startErr == nil {
+			continue
+		}
+
+		h.logger.Warnf("Failed to start job %s for run %s of workflow %s: %v", jobID, run.RunID, workflow.WorkflowID, startErr)
+		result := types.WorkflowTaskResult{Error: "failed to start job: " + startErr.Error()}
+		updated, err := h.advanceWorkflowRun(workflow, run.RunID, func(run *types.WorkflowRun) []string {
+			return run.Complete(workflow.Definition, jobID, result, time.Now())
+		})
+		if err != nil {
+			return nil, err
+		}
+		run = updated
+	}
+	return run, nil
+}
+
+// startWorkflowJob runs a paused job of a workflow once, as soon as its scheduler picks it up
+func (h *Handler) startWorkflowJob(jobID string) error {
+	id, ok := new(big.Int).SetString(jobID, 10)
+	if !ok {
+		return fmt.Errorf("invalid job ID %s", jobID)
+	}
+	job, err := h.jobRepository.GetJobByID(id)
+	if err != nil {
+		return err
+	}
+	lifecycle, err := h.jobRepository.GetJobLifecycle(id)
+	if err != nil {
+		return err
+	}
+	if lifecycle.Status.IsActive() {
+		return nil
+	}
+	_, err = h.changeJobLifecycle(job, lifecycle, types.JobActionResume, types.ResumeNow)
+	return err
+}
+
+// stopWorkflowJobByID pauses an active job of a workflow
+func (h *Handler) stopWorkflowJobByID(jobID string) error {
+	id, ok := new(big.Int).SetString(jobID, 10)
+	if !ok {
+		return fmt.Errorf("invalid job ID %s", jobID)
+	}
+	return h.stopWorkflowJob(id)
+}
+
+// stopWorkflowJob pauses a job of a workflow when it is active
+func (h *Handler) stopWorkflowJob(jobID *big.Int) error {
+	lifecycle, err := h.jobRepository.GetJobLifecycle(jobID)
+	if err != nil {
+		return err
+	}
+	if !lifecycle.Status.IsActive() {
+		return nil
+	}
+	job, err := h.jobRepository.GetJobByID(jobID)
+	if err != nil {
+		return err
+	}
+	_, err = h.changeJobLifecycle(job, lifecycle, types.JobActionPause, "")
+	return err
+}
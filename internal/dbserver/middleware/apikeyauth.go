@@ -1,24 +1,48 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
+	dbserverTypes "github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	"github.com/trigg3rX/triggerx-backend/pkg/database"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
+// ApiKeyContextKey is the context key of the API key of the request
+const ApiKeyContextKey = "apiKey"
+
+// lastUsedInterval is how often the last use of a key is written, so busy keys do not write
+// on every request
+const lastUsedInterval = time.Minute
+
+var (
+	ErrApiKeyInactive     = errors.New("API key is inactive")
+	ErrApiKeyExpired      = errors.New("API key has expired")
+	ErrApiKeyIPNotAllowed = errors.New("API key is not allowed from this IP")
+)
+
+// apiKeyLookup finds the key a client presented and records its use
+type apiKeyLookup interface {
+	AuthenticateApiKey(key string) (*types.ApiKey, error)
+	UpdateApiKeyLastUsedIP(key, clientIP string) error
+}
+
 type ApiKeyAuth struct {
 	db          *database.Connection
+	apiKeys     apiKeyLookup
 	logger      logging.Logger
 	rateLimiter *RateLimiter
 }
 
-func NewApiKeyAuth(db *database.Connection, rateLimiter *RateLimiter, logger logging.Logger) *ApiKeyAuth {
+func NewApiKeyAuth(db *database.Connection, apiKeys apiKeyLookup, rateLimiter *RateLimiter, logger logging.Logger) *ApiKeyAuth {
 	return &ApiKeyAuth{
 		db:          db,
+		apiKeys:     apiKeys,
 		logger:      logger,
 		rateLimiter: rateLimiter,
 	}
@@ -26,67 +50,38 @@ func NewApiKeyAuth(db *database.Connection, rateLimiter *RateLimiter, logger log
 
 func (a *ApiKeyAuth) GinMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKeyHeader := c.GetHeader("X-Api-Key")
-		if apiKeyHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
-			c.Abort()
+		apiKey, ok := a.authenticate(c)
+		if !ok {
 			return
 		}
 
-		apiKey, err := a.getApiKey(apiKeyHeader)
-		if err != nil {
-			a.logger.Errorf("Error retrieving API key: %v", err)
-			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or inactive API key"})
-			c.Abort()
-			return
-		}
-
-		if !apiKey.IsActive {
-			c.JSON(http.StatusForbidden, gin.H{"error": "API key is inactive"})
-			c.Abort()
+		if !a.limit(c, apiKey) {
 			return
 		}
 
-		go a.updateLastUsed(apiKeyHeader)
-
-		if a.rateLimiter != nil {
-			if err := a.rateLimiter.ApplyGinRateLimit(c, apiKey); err != nil {
-				a.logger.Warnf("Rate limit applied: %v", err)
-				c.JSON(http.StatusTooManyRequests, gin.H{
-					"error":   "Rate limit exceeded",
-					"message": "You have exceeded the rate limit",
-				})
-				c.Abort()
-				return
-			}
-		}
-
-		c.Set("apiKey", apiKey)
+		c.Set(ApiKeyContextKey, apiKey)
 		c.Next()
 	}
 }
 
-func (a *ApiKeyAuth) KeeperMiddleware() gin.HandlerFunc {
+// OptionalMiddleware authenticates the API key when the request carries one, and lets
+// anonymous requests through
+func (a *ApiKeyAuth) OptionalMiddleware() gin.HandlerFunc {
+	required := a.GinMiddleware()
 	return func(c *gin.Context) {
-		// First check if the request has a valid API key
-		apiKeyHeader := c.GetHeader("X-Api-Key")
-		if apiKeyHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
-			c.Abort()
-			return
-		}
-
-		apiKey, err := a.getApiKey(apiKeyHeader)
-		if err != nil {
-			a.logger.Errorf("Error retrieving API key: %v", err)
-			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or inactive API key"})
-			c.Abort()
+		if c.GetHeader("X-Api-Key") == "" {
+			c.Next()
 			return
 		}
+		required(c)
+	}
+}
 
-		if !apiKey.IsActive {
-			c.JSON(http.StatusForbidden, gin.H{"error": "API key is inactive"})
-			c.Abort()
+func (a *ApiKeyAuth) KeeperMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// First check if the request has a valid API key
+		apiKey, ok := a.authenticate(c)
+		if !ok {
 			return
 		}
 
@@ -105,58 +100,122 @@ func (a *ApiKeyAuth) KeeperMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		go a.updateLastUsed(apiKeyHeader)
-
-		if a.rateLimiter != nil {
-			if err := a.rateLimiter.ApplyGinRateLimit(c, apiKey); err != nil {
-				a.logger.Warnf("Rate limit applied: %v", err)
-				c.JSON(http.StatusTooManyRequests, gin.H{
-					"error":   "Rate limit exceeded",
-					"message": "You have exceeded the rate limit",
-				})
-				c.Abort()
-				return
-			}
+		if !a.limit(c, apiKey) {
+			return
 		}
 
-		c.Set("apiKey", apiKey)
+		c.Set(ApiKeyContextKey, apiKey)
 		c.Next()
 	}
 }
 
-func (a *ApiKeyAuth) getApiKey(key string) (*types.ApiKey, error) {
-	query := `SELECT key, owner, is_active, rate_limit, last_used, created_at 
-			  FROM triggerx.apikeys WHERE key = ? AND is_active = ? ALLOW FILTERING`
+// RequireScope allows requests whose API key, authenticated by GinMiddleware, has scope
+func (a *ApiKeyAuth) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get(ApiKeyContextKey)
+		apiKey, ok := value.(*types.ApiKey)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+			c.Abort()
+			return
+		}
+
+		if !dbserverTypes.ApiKeyHasScope(apiKey, scope) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "API key does not have the required scope",
+				"scope": scope,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
 
-	var apiKey types.ApiKey
+// authenticate validates the X-Api-Key of the request, responding when it is not valid
+func (a *ApiKeyAuth) authenticate(c *gin.Context) (*types.ApiKey, bool) {
+	apiKeyHeader := c.GetHeader("X-Api-Key")
+	if apiKeyHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		c.Abort()
+		return nil, false
+	}
+
+	apiKey, err := a.ValidateApiKey(apiKeyHeader, c.ClientIP())
+	switch {
+	case errors.Is(err, ErrApiKeyInactive), errors.Is(err, ErrApiKeyExpired), errors.Is(err, ErrApiKeyIPNotAllowed):
+		a.logger.Warnf("Rejected API key %s: %v", apiKey.Key, err)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		c.Abort()
+		return nil, false
+	case err != nil:
+		a.logger.Errorf("Error retrieving API key: %v", err)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or inactive API key"})
+		c.Abort()
+		return nil, false
+	}
+	return apiKey, true
+}
 
-	err := a.db.Session().Query(query, key, true).Scan(
-		&apiKey.Key,
-		&apiKey.Owner,
-		&apiKey.IsActive,
-		&apiKey.RateLimit,
-		&apiKey.LastUsed,
-		&apiKey.CreatedAt,
-	)
+// limit records the use of the key and applies its rate limit, responding when exceeded
+func (a *ApiKeyAuth) limit(c *gin.Context, apiKey *types.ApiKey) bool {
+	go a.updateLastUsed(apiKey, c.ClientIP())
+
+	if a.rateLimiter != nil {
+		if err := a.rateLimiter.ApplyGinRateLimit(c, apiKey); err != nil {
+			a.logger.Warnf("Rate limit applied: %v", err)
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Rate limit exceeded",
+				"message": "You have exceeded the rate limit",
+			})
+			c.Abort()
+			return false
+		}
+	}
+	return true
+}
 
+// ValidateApiKey reads the key a client presented and checks it may be used now, from
+// clientIP. The key is returned with the errors of keys that exist.
+func (a *ApiKeyAuth) ValidateApiKey(key, clientIP string) (*types.ApiKey, error) {
+	apiKey, err := a.getApiKey(key)
 	if err != nil {
-		a.logger.Errorf("Failed to retrieve API key for key %s: %v", key, err)
 		return nil, err
 	}
-
-	return &apiKey, nil
+	if !apiKey.IsActive {
+		return apiKey, ErrApiKeyInactive
+	}
+	if dbserverTypes.ApiKeyExpired(apiKey, time.Now()) {
+		return apiKey, ErrApiKeyExpired
+	}
+	if !auth.ApiKeyAllowsIP(apiKey.AllowedIPs, clientIP) {
+		return apiKey, ErrApiKeyIPNotAllowed
+	}
+	return apiKey, nil
 }
 
-func (a *ApiKeyAuth) updateLastUsed(key string) {
-	query := `UPDATE triggerx.apikeys SET last_used = ? WHERE key = ?`
+func (a *ApiKeyAuth) getApiKey(key string) (*types.ApiKey, error) {
+	apiKey, err := a.apiKeys.AuthenticateApiKey(key)
+	if err != nil {
+		a.logger.Errorf("Failed to retrieve API key: %v", err)
+		return nil, err
+	}
+	return apiKey, nil
+}
 
-	if err := a.db.Session().Query(query, time.Now().UTC(), key).Exec(); err != nil {
+// updateLastUsed records the use of a key, at most once per lastUsedInterval unless the IP
+// changed
+func (a *ApiKeyAuth) updateLastUsed(apiKey *types.ApiKey, clientIP string) {
+	if time.Since(apiKey.LastUsed) < lastUsedInterval && apiKey.LastUsedIP == clientIP {
+		return
+	}
+	if err := a.apiKeys.UpdateApiKeyLastUsedIP(apiKey.Key, clientIP); err != nil {
 		a.logger.Errorf("Failed to update last used timestamp: %v", err)
 	}
 }
 
 func (a *ApiKeyAuth) isKeeperApiKey(key string) (bool, error) {
-	query := `SELECT isKeeper FROM triggerx.apikeys WHERE key = ? ALLOW FILTERING`
+	query := `SELECT isKeeper FROM triggerx.apikeys WHERE key = ?`
 
 	var isKeeper bool
 	err := a.db.Session().Query(query, key).Scan(&isKeeper)
@@ -169,12 +228,7 @@ func (a *ApiKeyAuth) isKeeperApiKey(key string) (bool, error) {
 
 // Public wrapper methods for WebSocket authentication
 
-// GetApiKey validates and retrieves API key data (public wrapper for getApiKey)
-func (a *ApiKeyAuth) GetApiKey(key string) (*types.ApiKey, error) {
-	return a.getApiKey(key)
-}
-
-// UpdateLastUsed updates the last used timestamp for an API key (public wrapper for updateLastUsed)
-func (a *ApiKeyAuth) UpdateLastUsed(key string) {
-	a.updateLastUsed(key)
+// UpdateLastUsed records the use of an API key (public wrapper for updateLastUsed)
+func (a *ApiKeyAuth) UpdateLastUsed(apiKey *types.ApiKey, clientIP string) {
+	a.updateLastUsed(apiKey, clientIP)
 }
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	dbserverTypes "github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// fakeApiKeys finds keys by the value presented
+type fakeApiKeys struct {
+	keys map[string]*types.ApiKey
+}
+
+func (f *fakeApiKeys) AuthenticateApiKey(key string) (*types.ApiKey, error) {
+	apiKey, ok := f.keys[key]
+	if !ok {
+		return nil, errors.New("api key not found")
+	}
+	copied := *apiKey
+	return &copied, nil
+}
+
+func (f *fakeApiKeys) UpdateApiKeyLastUsedIP(key, clientIP string) error { return nil }
+
+func TestApiKeyAuth_Scopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys := &fakeApiKeys{keys: map[string]*types.ApiKey{
+		"legacy":   {Key: "legacy", IsActive: true, LastUsed: time.Now()},
+		"reader":   {Key: "reader", IsActive: true, Scopes: []string{dbserverTypes.ScopeJobsRead}, LastUsed: time.Now()},
+		"admin":    {Key: "admin", IsActive: true, Scopes: []string{dbserverTypes.ScopeAdmin}, LastUsed: time.Now()},
+		"inactive": {Key: "inactive", Scopes: []string{dbserverTypes.ScopeAdmin}},
+		"expired":  {Key: "expired", IsActive: true, ExpiresAt: time.Now().Add(-time.Minute)},
+		"rotated":  {Key: "rotated", IsActive: true, ExpiresAt: time.Now().Add(time.Hour), ReplacedBy: "TGRX-00000000"},
+		"office":   {Key: "office", IsActive: true, AllowedIPs: []string{"10.0.0.0/8"}, LastUsed: time.Now(), LastUsedIP: "10.1.2.3"},
+	}}
+	apiKeyAuth := NewApiKeyAuth(nil, keys, nil, logging.NewNoOpLogger())
+
+	router := gin.New()
+	router.Use(apiKeyAuth.GinMiddleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/jobs", apiKeyAuth.RequireScope(dbserverTypes.ScopeJobsRead), ok)
+	router.GET("/tasks", apiKeyAuth.RequireScope(dbserverTypes.ScopeTasksRead), ok)
+	router.GET("/admin", apiKeyAuth.RequireScope(dbserverTypes.ScopeAdmin), ok)
+
+	tests := []struct {
+		name     string
+		key      string
+		path     string
+		ip       string
+		expected int
+	}{
+		{"no key", "", "/jobs", "10.1.2.3", http.StatusUnauthorized},
+		{"unknown key", "unknown", "/jobs", "10.1.2.3", http.StatusForbidden},
+		{"scope granted", "reader", "/jobs", "10.1.2.3", http.StatusOK},
+		{"scope missing", "reader", "/tasks", "10.1.2.3", http.StatusForbidden},
+		{"keys without scopes keep the default scopes", "legacy", "/tasks", "10.1.2.3", http.StatusOK},
+		{"keys without scopes are not admins", "legacy", "/admin", "10.1.2.3", http.StatusForbidden},
+		{"admin has every scope", "admin", "/tasks", "10.1.2.3", http.StatusOK},
+		{"inactive key", "inactive", "/admin", "10.1.2.3", http.StatusForbidden},
+		{"expired key", "expired", "/jobs", "10.1.2.3", http.StatusForbidden},
+		{"rotated key in its grace period", "rotated", "/jobs", "10.1.2.3", http.StatusOK},
+		{"allowed IP", "office", "/jobs", "10.1.2.3", http.StatusOK},
+		{"IP not allowed", "office", "/jobs", "192.0.2.1", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.ip + ":1234"
+			if tt.key != "" {
+				req.Header.Set("X-Api-Key", tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code, w.Body.String())
+		})
+	}
+}
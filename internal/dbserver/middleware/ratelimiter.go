@@ -2,27 +2,61 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/redis"
+	dbserverTypes "github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
-type RateLimitInfo struct {
-	Remaining int   `json:"remaining"`
-	Limit     int   `json:"limit"`
-	Reset     int64 `json:"reset"`
+// ErrRateLimitExceeded is returned when a bucket has too few tokens for a request
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
+// routeCosts are the tokens a request to a route takes, by gin route. Other routes take one.
+var routeCosts = map[string]int{
+	"/api/fees":          5,
+	"/api/code/validate": 10,
+}
+
+// RouteCost returns the tokens a request to a route takes
+func RouteCost(route string) int {
+	if cost, ok := routeCosts[route]; ok {
+		return cost
+	}
+	return 1
+}
+
+// RateLimitResult is the state of a bucket after a request took its tokens
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until a denied request would be allowed
+	RetryAfter time.Duration
 }
 
+// RateLimiter keeps token buckets in Redis, so all dbserver instances share them. When Redis
+// is missing or fails, buckets are kept in memory and only limit this instance.
 type RateLimiter struct {
 	redis  *redis.Client
+	local  *localBuckets
 	logger logging.Logger
+	now    func() time.Time
+	// redisDown is set while requests fall back to memory, so the switch is logged once
+	redisDown atomic.Bool
 }
 
 func NewRateLimiterWithClient(redisClient *redis.Client, logger logging.Logger) (*RateLimiter, error) {
@@ -30,168 +64,224 @@ func NewRateLimiterWithClient(redisClient *redis.Client, logger logging.Logger)
 		return nil, fmt.Errorf("redis client is nil")
 	}
 
+	rl := NewLocalRateLimiter(logger)
+	rl.redis = redisClient
+	return rl, nil
+}
+
+// NewLocalRateLimiter returns a rate limiter keeping its buckets in memory
+func NewLocalRateLimiter(logger logging.Logger) *RateLimiter {
 	return &RateLimiter{
-		redis:  redisClient,
+		local:  newLocalBuckets(10 * time.Minute),
 		logger: logger,
-	}, nil
+		now:    time.Now,
+	}
 }
 
-const rateLimitScript = `
+// tokenBucketScript refills the bucket for the time since it was last used and takes the
+// cost when there are enough tokens. The time is read from Redis so instances with skewed
+// clocks share buckets. It returns whether the request is allowed and the tokens left, as a
+// string since Redis truncates numbers to integers.
+const tokenBucketScript = `
 local key = KEYS[1]
-local limit = tonumber(ARGV[1])
-local window = tonumber(ARGV[2])
-
-local current = redis.call("INCR", key)
-if current == 1 then
-    redis.call("EXPIRE", key, window)
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call("HMGET", key, "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+    tokens = burst
+    ts = now
 end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
 
-local ttl = redis.call("TTL", key)
-
-if current > limit then
-    return {current, 0, ttl}
-else
-    return {current, limit - current, ttl}
+local allowed = 0
+if tokens >= cost then
+    tokens = tokens - cost
+    allowed = 1
 end
-`
 
-func (rl *RateLimiter) ApplyGinRateLimit(c *gin.Context, apiKey *types.ApiKey) error {
-	key := fmt.Sprintf("rate_limit:%s", apiKey.Key)
-	window := 60 // 1 minute window
-	limit := apiKey.RateLimit
+redis.call("HSET", key, "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", key, math.ceil((burst - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`
 
-	ctx := context.Background()
-	result, err := rl.redis.EvalScript(ctx, rateLimitScript, []string{key}, []interface{}{limit, window})
-	if err != nil {
-		rl.logger.Errorf("Failed to evaluate rate limit script: %v", err)
-		return err
+// Take takes cost tokens from the bucket of key
+func (rl *RateLimiter) Take(ctx context.Context, key string, tier dbserverTypes.RateLimitTier, cost int) RateLimitResult {
+	if tier.Burst < 1 {
+		tier.Burst = 1
 	}
-
-	values, ok := result.([]interface{})
-	if !ok || len(values) != 3 {
-		rl.logger.Error("Invalid response from rate limit script")
-		return fmt.Errorf("invalid response from rate limit script")
+	if tier.RatePerMinute < 1 {
+		tier.RatePerMinute = 1
+	}
+	// A request costing more than the bucket holds would never be allowed
+	if cost > tier.Burst {
+		cost = tier.Burst
 	}
 
-	current := values[0].(int64)
-	remaining := values[1].(int64)
-	reset := values[2].(int64)
-
-	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
-	c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
-	c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix()+reset, 10))
-
-	if current > int64(limit) {
-		return fmt.Errorf("rate limit exceeded")
+	if rl.redis != nil {
+		allowed, tokens, err := rl.takeRedis(ctx, key, tier, cost)
+		if err == nil {
+			if rl.redisDown.CompareAndSwap(true, false) {
+				rl.logger.Info("Rate limiting keeps buckets in Redis again")
+			}
+			return newRateLimitResult(tier, cost, allowed, tokens)
+		}
+		if rl.redisDown.CompareAndSwap(false, true) {
+			rl.logger.Warnf("Rate limiting falls back to memory until Redis recovers: %v", err)
+		}
 	}
 
-	return nil
+	allowed, tokens := rl.local.take(key, tier, cost, rl.now())
+	return newRateLimitResult(tier, cost, allowed, tokens)
 }
 
-func (rl *RateLimiter) ApplyRateLimit(r *http.Request, apiKey *types.ApiKey) (*http.Response, error) {
-	ctx := r.Context()
-	rateLimitKey := fmt.Sprintf("rate-limit:%s", apiKey.Key)
-	windowSeconds := 60
-	currentTimestamp := time.Now().UTC().Unix()
-
-	result, err := rl.redis.Eval(ctx, rateLimitScript, []string{rateLimitKey},
-		apiKey.RateLimit, windowSeconds)
+func (rl *RateLimiter) takeRedis(ctx context.Context, key string, tier dbserverTypes.RateLimitTier, cost int) (bool, float64, error) {
+	ratePerMillisecond := float64(tier.RatePerMinute) / float64(time.Minute/time.Millisecond)
+	result, err := rl.redis.EvalScript(ctx, tokenBucketScript, []string{key}, []interface{}{ratePerMillisecond, tier.Burst, cost})
 	if err != nil {
-		rl.logger.Errorf("Rate limiting error: %v", err)
-
-		if true {
-			return nil, nil
-		}
-
-		return &http.Response{
-			StatusCode: http.StatusInternalServerError,
-			Body:       nil,
-		}, fmt.Errorf("rate limiting service unavailable: %w", err)
+		return false, 0, fmt.Errorf("failed to evaluate rate limit script: %w", err)
 	}
 
-	results, ok := result.([]interface{})
-	if !ok || len(results) != 3 {
-		rl.logger.Errorf("Invalid result from Redis: %v", result)
-		return nil, fmt.Errorf("invalid response from rate limiter")
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("invalid response from rate limit script: %v", result)
 	}
-
-	count := int(results[0].(int64))
-	remaining := int(results[1].(int64))
-	ttl := int(results[2].(int64))
-	reset := currentTimestamp + int64(ttl)
-
-	rl.logger.Infof("Rate Limit Debug: API Key: %s, Owner: %s, Rate Limit: %d, Current Count: %d, Remaining: %d, TTL: %d",
-		apiKey.Key, apiKey.Owner, apiKey.RateLimit, count, remaining, ttl)
-
-	r.Header.Set("X-RateLimit-Limit", strconv.Itoa(apiKey.RateLimit))
-	r.Header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
-	r.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
-
-	if count > apiKey.RateLimit {
-		rl.logger.Warnf("Rate limit exceeded: API Key: %s, Owner: %s, Count: %d, Limit: %d",
-			apiKey.Key, apiKey.Owner, count, apiKey.RateLimit)
-
-		resp := &http.Response{
-			StatusCode: http.StatusTooManyRequests,
-			Header:     http.Header{},
-			Body:       nil,
-		}
-
-		resp.Header.Set("X-RateLimit-Limit", strconv.Itoa(apiKey.RateLimit))
-		resp.Header.Set("X-RateLimit-Remaining", "0")
-		resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
-		resp.Header.Set("Retry-After", strconv.Itoa(ttl))
-
-		return resp, fmt.Errorf("rate limit exceeded")
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("invalid response from rate limit script: %v", result)
+	}
+	tokensValue, ok := values[1].(string)
+	if !ok {
+		return false, 0, fmt.Errorf("invalid response from rate limit script: %v", result)
+	}
+	tokens, err := strconv.ParseFloat(tokensValue, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid response from rate limit script: %w", err)
 	}
+	return allowed == 1, tokens, nil
+}
 
-	return nil, nil
+func newRateLimitResult(tier dbserverTypes.RateLimitTier, cost int, allowed bool, tokens float64) RateLimitResult {
+	perSecond := float64(tier.RatePerMinute) / 60
+	result := RateLimitResult{
+		Allowed:   allowed,
+		Limit:     tier.Burst,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     secondsDuration((float64(tier.Burst) - tokens) / perSecond),
+	}
+	if !allowed {
+		result.RetryAfter = secondsDuration((float64(cost) - tokens) / perSecond)
+	}
+	return result
 }
 
-func (rl *RateLimiter) GetRateLimitStatus(ctx context.Context, apiKey *types.ApiKey) (*RateLimitInfo, error) {
-	rateLimitKey := fmt.Sprintf("rate-limit:%s", apiKey.Key)
-	currentTimestamp := time.Now().UTC().Unix()
+// secondsDuration rounds seconds up to whole seconds, as headers carry them
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(math.Max(0, seconds))) * time.Second
+}
 
-	countStr, err := rl.redis.Get(ctx, rateLimitKey)
-	count := 0
-	if err == nil && countStr != "" {
-		count, _ = strconv.Atoi(countStr)
+// setRateLimitHeaders sets the RateLimit headers of the IETF draft, and Retry-After when the
+// request is denied
+func setRateLimitHeaders(c *gin.Context, tier dbserverTypes.RateLimitTier, result RateLimitResult) {
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(int(result.Reset/time.Second)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=60;burst=%d;policy=%q", tier.RatePerMinute, tier.Burst, tier.Name))
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(int(result.RetryAfter/time.Second)))
 	}
+}
 
-	ttl, err := rl.redis.TTL(ctx, rateLimitKey)
-	if err != nil || ttl < 0 {
-		ttl = 60 * time.Second
+// ApplyGinRateLimit takes the cost of the route from the bucket of the API key's tier
+func (rl *RateLimiter) ApplyGinRateLimit(c *gin.Context, apiKey *types.ApiKey) error {
+	tier := dbserverTypes.RateLimitTierOf(apiKey)
+	result := rl.Take(c.Request.Context(), "rate_limit:key:"+apiKey.Key, tier, RouteCost(c.FullPath()))
+	setRateLimitHeaders(c, tier, result)
+	if !result.Allowed {
+		return ErrRateLimitExceeded
 	}
+	return nil
+}
 
-	return &RateLimitInfo{
-		Remaining: int(math.Max(0, float64(apiKey.RateLimit-count))),
-		Limit:     apiKey.RateLimit,
-		Reset:     currentTimestamp + int64(ttl.Seconds()),
-	}, nil
+// IPGinMiddleware limits unauthenticated routes by client IP
+func (rl *RateLimiter) IPGinMiddleware(tier dbserverTypes.RateLimitTier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result := rl.Take(c.Request.Context(), "rate_limit:ip:"+c.ClientIP(), tier, RouteCost(c.FullPath()))
+		setRateLimitHeaders(c, tier, result)
+		if !result.Allowed {
+			rl.logger.Warnf("Rate limit exceeded for %s on %s", c.ClientIP(), c.FullPath())
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Rate limit exceeded",
+				"message": "You have exceeded the rate limit",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
 }
 
 // CheckRateLimitForKey checks rate limit for a given API key without using gin.Context
 func (rl *RateLimiter) CheckRateLimitForKey(apiKey *types.ApiKey) error {
-	key := fmt.Sprintf("rate_limit:%s", apiKey.Key)
-	window := 60 // 1 minute window
-	limit := apiKey.RateLimit
+	result := rl.Take(context.Background(), "rate_limit:key:"+apiKey.Key, dbserverTypes.RateLimitTierOf(apiKey), 1)
+	if !result.Allowed {
+		return ErrRateLimitExceeded
+	}
+	return nil
+}
 
-	ctx := context.Background()
-	result, err := rl.redis.EvalScript(ctx, rateLimitScript, []string{key}, []interface{}{limit, window})
-	if err != nil {
-		rl.logger.Errorf("Failed to evaluate rate limit script: %v", err)
-		return err
+// localBuckets keeps token buckets in memory, dropping buckets left idle
+type localBuckets struct {
+	idleTimeout time.Duration
+	mu          sync.Mutex
+	buckets     map[string]*localBucket
+	lastSweep   time.Time
+}
+
+type localBucket struct {
+	tier     dbserverTypes.RateLimitTier
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newLocalBuckets(idleTimeout time.Duration) *localBuckets {
+	return &localBuckets{
+		idleTimeout: idleTimeout,
+		buckets:     make(map[string]*localBucket),
+		lastSweep:   time.Now(),
 	}
+}
 
-	values, ok := result.([]interface{})
-	if !ok || len(values) != 3 {
-		rl.logger.Error("Invalid response from rate limit script")
-		return fmt.Errorf("invalid response from rate limit script")
+// take takes cost tokens from the bucket of key, returning the tokens left
+func (l *localBuckets) take(key string, tier dbserverTypes.RateLimitTier, cost int, now time.Time) (bool, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.idleTimeout {
+		for key, bucket := range l.buckets {
+			if now.Sub(bucket.lastSeen) > l.idleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
 	}
 
-	current := values[0].(int64)
-	if current > int64(limit) {
-		return fmt.Errorf("rate limit exceeded")
+	// A key moved to another tier starts with a full bucket of it
+	bucket, ok := l.buckets[key]
+	if !ok || bucket.tier != tier {
+		bucket = &localBucket{
+			tier:    tier,
+			limiter: rate.NewLimiter(rate.Limit(float64(tier.RatePerMinute)/60), tier.Burst),
+		}
+		l.buckets[key] = bucket
 	}
-	return nil
+	bucket.lastSeen = now
+	allowed := bucket.limiter.AllowN(now, cost)
+	return allowed, bucket.limiter.TokensAt(now)
 }
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/redis"
+	dbserverTypes "github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func TestRateLimiter_TakeRefills(t *testing.T) {
+	rl := NewLocalRateLimiter(logging.NewNoOpLogger())
+	now := time.Unix(1700000000, 0)
+	rl.now = func() time.Time { return now }
+	tier := dbserverTypes.RateLimitTier{Name: "test", RatePerMinute: 60, Burst: 3}
+
+	// The whole burst is allowed at once, then one token a second
+	for i := 2; i >= 0; i-- {
+		result := rl.Take(context.Background(), "key", tier, 1)
+		require.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+	result := rl.Take(context.Background(), "key", tier, 1)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	now = now.Add(time.Second)
+	assert.True(t, rl.Take(context.Background(), "key", tier, 1).Allowed)
+	assert.False(t, rl.Take(context.Background(), "key", tier, 1).Allowed)
+
+	// Other keys have their own bucket, and costs above the burst take the whole bucket
+	result = rl.Take(context.Background(), "other", tier, 10)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestRateLimitTierOf(t *testing.T) {
+	assert.Equal(t, dbserverTypes.RateLimitTiers["standard"], dbserverTypes.RateLimitTierOf(&types.ApiKey{Tier: "standard", RateLimit: 10}))
+	assert.Equal(t, dbserverTypes.RateLimitTier{Name: "custom", RatePerMinute: 10, Burst: 10}, dbserverTypes.RateLimitTierOf(&types.ApiKey{RateLimit: 10}))
+	assert.Equal(t, 60, dbserverTypes.RateLimitTierOf(&types.ApiKey{Tier: "unknown"}).RatePerMinute)
+}
+
+func TestRateLimiter_IPGinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rl := NewLocalRateLimiter(logging.NewNoOpLogger())
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies(nil))
+	router.Use(rl.IPGinMiddleware(dbserverTypes.RateLimitTier{Name: "ip", RatePerMinute: 60, Burst: 12}))
+	router.GET("/api/code/validate", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(path, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("X-Forwarded-For", "192.0.2.1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Code validation costs 10 of the 12 tokens
+	w := request("/api/code/validate", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "12", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "10", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, `60;w=60;burst=12;policy="ip"`, w.Header().Get("RateLimit-Policy"))
+
+	w = request("/api/code/validate", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "8", w.Header().Get("Retry-After"))
+
+	// Cheaper routes still fit, and other clients are not limited
+	assert.Equal(t, http.StatusOK, request("/api/health", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, request("/api/code/validate", "10.0.0.2").Code)
+
+	// X-Forwarded-For is ignored without trusted proxies, so it gives no bucket of its own
+	assert.Equal(t, http.StatusTooManyRequests, request("/api/code/validate", "10.0.0.2").Code)
+}
+
+func TestRateLimiter_TakeRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	now := time.Unix(1700000000, 0)
+	server.SetTime(now)
+
+	logger := logging.NewNoOpLogger()
+	client := redis.NewClientWithRedis(goredis.NewClient(&goredis.Options{Addr: server.Addr()}), logger)
+	rl, err := NewRateLimiterWithClient(client, logger)
+	require.NoError(t, err)
+	tier := dbserverTypes.RateLimitTier{Name: "test", RatePerMinute: 60, Burst: 3}
+
+	// The script keeps the bucket in Redis, refilling it by the time Redis reports
+	for i := 2; i >= 0; i-- {
+		result := rl.Take(context.Background(), "key", tier, 1)
+		require.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+	result := rl.Take(context.Background(), "key", tier, 1)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Empty(t, rl.local.buckets)
+	assert.Equal(t, "0", server.HGet("key", "tokens"))
+	assert.Equal(t, 4*time.Second, server.TTL("key"))
+
+	server.SetTime(now.Add(1500 * time.Millisecond))
+	result = rl.Take(context.Background(), "key", tier, 1)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, "0.5", server.HGet("key", "tokens"))
+	assert.False(t, rl.Take(context.Background(), "key", tier, 1).Allowed)
+
+	// Buckets are kept in memory while Redis is down
+	server.Close()
+	assert.True(t, rl.Take(context.Background(), "key", tier, 1).Allowed)
+	assert.Contains(t, rl.local.buckets, "key")
+}
+
+func TestRateLimiter_LogsRedisFallbackOnce(t *testing.T) {
+	server := miniredis.RunT(t)
+	logger := new(logging.MockLogger)
+	client := redis.NewClientWithRedis(goredis.NewClient(&goredis.Options{Addr: server.Addr(), MaxRetries: -1}), logger)
+	rl, err := NewRateLimiterWithClient(client, logger)
+	require.NoError(t, err)
+	tier := dbserverTypes.RateLimitTier{Name: "test", RatePerMinute: 60, Burst: 10}
+
+	// The switch to memory and back is logged once, not on every request
+	logger.On("Warnf", "Rate limiting falls back to memory until Redis recovers: %v", mock.Anything).Once()
+	logger.On("Info", "Rate limiting keeps buckets in Redis again", mock.Anything).Once()
+
+	server.Close()
+	for i := 0; i < 3; i++ {
+		assert.True(t, rl.Take(context.Background(), "key", tier, 1).Allowed)
+	}
+	require.NoError(t, server.Restart())
+	for i := 0; i < 3; i++ {
+		assert.True(t, rl.Take(context.Background(), "key", tier, 1).Allowed)
+	}
+	logger.AssertExpectations(t)
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
+)
+
+func TestServiceAuth_GinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer := jwt.NewServiceTokenSigner(privateKey)
+	verifier := jwt.NewServiceTokenVerifier(ServiceAudience, publicKey)
+
+	token := func(audience string, scopes ...string) string {
+		token, err := signer.Sign("taskmonitor", audience, scopes, time.Minute)
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+
+	tests := []struct {
+		name           string
+		allowAnonymous bool
+		authorization  string
+		expected       int
+		service        string
+	}{
+		{"no credentials", false, "", http.StatusUnauthorized, ""},
+		{"internal scope", false, token(ServiceAudience, InternalScope), http.StatusOK, "taskmonitor"},
+		{"missing scope", false, token(ServiceAudience, "/dispatcher.Dispatcher/Submit"), http.StatusUnauthorized, ""},
+		{"wrong audience", false, token("taskdispatcher", InternalScope), http.StatusUnauthorized, ""},
+		{"invalid token", true, "Bearer invalid", http.StatusUnauthorized, ""},
+		{"anonymous allowed", true, "", http.StatusOK, "anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceAuth := NewServiceAuth(verifier, tt.allowAnonymous, logging.NewNoOpLogger())
+			router := gin.New()
+			var identity rpcpkg.CallerIdentity
+			router.POST("/keepers/:id/add-points", serviceAuth.GinMiddleware(), func(c *gin.Context) {
+				identity = c.MustGet(CallerIdentityKey).(rpcpkg.CallerIdentity)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/keepers/1/add-points", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code)
+			assert.Equal(t, tt.service, identity.Service)
+		})
+	}
+}
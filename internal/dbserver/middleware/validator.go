@@ -78,6 +78,20 @@ func (v *Validator) GinMiddleware() gin.HandlerFunc {
 				}
 			}
 
+		case "/api/jobs/manifest/apply":
+			// Manifests are YAML or JSON, their structure is checked by the handler
+			manifest, err := types.ParseJobManifest(body, c.ContentType())
+			if err != nil {
+				validationError = err
+			} else {
+				for _, job := range manifest.Jobs {
+					if err := v.validate.Struct(job); err != nil {
+						validationError = err
+						break
+					}
+				}
+			}
+
 		case "/api/tasks":
 			var taskData types.CreateTaskDataRequest
 			if err := c.ShouldBindJSON(&taskData); err != nil {
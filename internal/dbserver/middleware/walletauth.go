@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// WalletAddressKey is the context key of the address of the caller's session
+const WalletAddressKey = "walletAddress"
+
+// jobLookup finds the job a request mutates
+type jobLookup interface {
+	GetJobByID(jobID *big.Int) (*types.JobData, error)
+}
+
+// userLookup finds the address of the user owning a job
+type userLookup interface {
+	GetUserAddressByID(id int64) (string, error)
+}
+
+// safeOwnerChecker tells whether an address owns a Safe
+type safeOwnerChecker interface {
+	IsOwner(ctx context.Context, chainID, safeAddress, address string) (bool, error)
+}
+
+// WalletAuth authenticates callers by their wallet session token and binds mutations of
+// jobs to the job's owner, or to the owners of the Safe the job runs from
+type WalletAuth struct {
+	sessions *auth.Sessions
+	jobs     jobLookup
+	users    userLookup
+	safes    safeOwnerChecker
+	logger   logging.Logger
+}
+
+func NewWalletAuth(sessions *auth.Sessions, jobs jobLookup, users userLookup, safes safeOwnerChecker, logger logging.Logger) *WalletAuth {
+	return &WalletAuth{
+		sessions: sessions,
+		jobs:     jobs,
+		users:    users,
+		safes:    safes,
+		logger:   logger,
+	}
+}
+
+// GinMiddleware requires a valid session token in the Authorization header
+func (w *WalletAuth) GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token is required"})
+			c.Abort()
+			return
+		}
+
+		claims, err := w.sessions.Verify(token)
+		if err != nil {
+			w.logger.Debugf("Rejected session token: %v", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired session token"})
+			c.Abort()
+			return
+		}
+
+		c.Set(WalletAddressKey, claims.Address)
+		c.Next()
+	}
+}
+
+// OptionalMiddleware authenticates the session token when the request carries one, and
+// lets anonymous requests through
+func (w *WalletAuth) OptionalMiddleware() gin.HandlerFunc {
+	required := w.GinMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		required(c)
+	}
+}
+
+// JobOwnerMiddleware requires the caller of GinMiddleware to own the job whose ID is the
+// route parameter param
+func (w *WalletAuth) JobOwnerMiddleware(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller := c.GetString(WalletAddressKey)
+		if caller == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token is required"})
+			c.Abort()
+			return
+		}
+
+		jobID, ok := new(big.Int).SetString(c.Param(param), 10)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			c.Abort()
+			return
+		}
+
+		job, err := w.jobs.GetJobByID(jobID)
+		if errors.Is(err, gocql.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			w.logger.Errorf("Error retrieving job %s for ownership check: %v", jobID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job"})
+			c.Abort()
+			return
+		}
+
+		allowed, err := w.ownsJob(c.Request.Context(), caller, job)
+		if err != nil {
+			w.logger.Errorf("Error checking ownership of job %s: %v", jobID, err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to check job ownership"})
+			c.Abort()
+			return
+		}
+		if !allowed {
+			w.logger.Warnf("Address %s is not allowed to modify job %s", caller, jobID)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Caller does not own this job"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ownsJob reports whether the caller is the user who created the job, the Safe the job
+// runs from or an owner of that Safe
+func (w *WalletAuth) ownsJob(ctx context.Context, caller string, job *types.JobData) (bool, error) {
+	owner, err := w.users.GetUserAddressByID(job.UserID)
+	if err != nil {
+		return false, err
+	}
+	if strings.EqualFold(owner, caller) {
+		return true, nil
+	}
+
+	if job.SafeAddress == "" {
+		return false, nil
+	}
+	if strings.EqualFold(job.SafeAddress, caller) {
+		return true, nil
+	}
+	if w.safes == nil {
+		return false, nil
+	}
+	return w.safes.IsOwner(ctx, job.CreatedChainID, job.SafeAddress, caller)
+}
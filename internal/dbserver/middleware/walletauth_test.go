@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+const (
+	ownerAddress = "0x00000000000000000000000000000000000000a1"
+	safeAddress  = "0x00000000000000000000000000000000000000cc"
+	safeOwner    = "0x00000000000000000000000000000000000000b1"
+	strangerAddr = "0x00000000000000000000000000000000000000ff"
+)
+
+type fakeJobs map[string]*types.JobData
+
+func (f fakeJobs) GetJobByID(jobID *big.Int) (*types.JobData, error) {
+	job, ok := f[jobID.String()]
+	if !ok {
+		return nil, gocql.ErrNotFound
+	}
+	return job, nil
+}
+
+type fakeUsers map[int64]string
+
+func (f fakeUsers) GetUserAddressByID(id int64) (string, error) {
+	return f[id], nil
+}
+
+type fakeSafes map[string][]string
+
+func (f fakeSafes) IsOwner(ctx context.Context, chainID, safe, address string) (bool, error) {
+	for _, owner := range f[safe] {
+		if owner == address {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestWalletAuth_JobOwnerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sessions, err := auth.NewSessions([]byte("0123456789abcdef0123456789abcdef"), "app.triggerx.network", time.Minute)
+	require.NoError(t, err)
+
+	walletAuth := NewWalletAuth(sessions,
+		fakeJobs{
+			"1": {UserID: 7},
+			"2": {UserID: 7, SafeAddress: safeAddress, CreatedChainID: "84532"},
+		},
+		fakeUsers{7: ownerAddress},
+		fakeSafes{safeAddress: {safeOwner}},
+		logging.NewNoOpLogger())
+
+	router := gin.New()
+	router.PUT("/jobs/:job_id/status/:status", walletAuth.GinMiddleware(), walletAuth.JobOwnerMiddleware("job_id"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	token := func(address string) string {
+		token, _, err := sessions.Issue(address, "84532", time.Time{})
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+
+	tests := []struct {
+		name          string
+		job           string
+		authorization string
+		expected      int
+	}{
+		{"no session", "1", "", http.StatusUnauthorized},
+		{"invalid session", "1", "Bearer invalid", http.StatusUnauthorized},
+		{"owner", "1", token(ownerAddress), http.StatusOK},
+		{"stranger", "1", token(strangerAddr), http.StatusForbidden},
+		{"unknown job", "3", token(ownerAddress), http.StatusNotFound},
+		{"invalid job id", "abc", token(ownerAddress), http.StatusBadRequest},
+		{"safe owner", "2", token(safeOwner), http.StatusOK},
+		{"safe itself", "2", token(safeAddress), http.StatusOK},
+		{"owner of safe job", "2", token(ownerAddress), http.StatusOK},
+		{"stranger on safe job", "2", token(strangerAddr), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/jobs/"+tt.job+"/status/paused", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+// Package migrations embeds the dbserver's CQL migrations, applied in version order by
+// database.Migrator (cmd/dbserver/migrate). Applied migrations are checksummed, add a new
+// migration instead of editing one. Keyspaces created before migrations were tracked hold
+// 001 and 002, they are recorded with `migrate baseline -version 2` before the first up.
+package migrations
+
+import "embed"
+
+// FS holds the migration files, named <version>_<name>.cql
+//
+//go:embed *.cql
+var FS embed.FS
@@ -0,0 +1,91 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+func TestMigrationsLoad(t *testing.T) {
+	migrations, err := database.LoadMigrations(FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "migration versions must be contiguous")
+		for _, statement := range migration.Statements {
+			assert.NotContains(t, statement, "triggerx.", "%03d_%s qualifies a table with the keyspace", migration.Version, migration.Name)
+		}
+	}
+}
+
+// TestMigrationsApply applies every migration to an empty keyspace of a local Scylla, and
+// applies them again to check a second run has nothing to do:
+//
+//	SCYLLA_TEST_HOST=localhost:9042 go test -run TestMigrationsApply ./internal/dbserver/migrations/
+func TestMigrationsApply(t *testing.T) {
+	host := os.Getenv("SCYLLA_TEST_HOST")
+	if host == "" {
+		t.Skip("SCYLLA_TEST_HOST is not set")
+	}
+	ctx := context.Background()
+	keyspace := fmt.Sprintf("migrations_test_%d", time.Now().UnixNano())
+
+	cluster := gocql.NewCluster(host)
+	cluster.Timeout = time.Minute
+	admin, err := cluster.CreateSession()
+	require.NoError(t, err)
+	defer admin.Close()
+
+	require.NoError(t, admin.Query(fmt.Sprintf(
+		`CREATE KEYSPACE %s WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1}`, keyspace)).Exec())
+	t.Cleanup(func() {
+		_ = admin.Query(fmt.Sprintf(`DROP KEYSPACE IF EXISTS %s`, keyspace)).Exec()
+	})
+	require.NoError(t, admin.AwaitSchemaAgreement(ctx))
+
+	cluster.Keyspace = keyspace
+	session, err := cluster.CreateSession()
+	require.NoError(t, err)
+	defer session.Close()
+
+	migrations, err := database.LoadMigrations(FS)
+	require.NoError(t, err)
+	migrator := database.NewMigrator(session, migrations, database.DefaultMigratorConfig(), logging.NewNoOpLogger())
+
+	applied, err := migrator.Up(ctx, false)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied, "%03d_%s", status.Version, status.Name)
+		assert.False(t, status.Modified, "%03d_%s", status.Version, status.Name)
+	}
+
+	applied, err = migrator.Up(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	// The lock is released, a second migrator takes it without waiting
+	config := database.DefaultMigratorConfig()
+	config.LockWait = 0
+	pending, err := database.NewMigrator(session, migrations, config, logging.NewNoOpLogger()).Up(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// An applied migration edited afterwards stops the migrator
+	migrations[0].Checksum = "edited"
+	_, err = database.NewMigrator(session, migrations, database.DefaultMigratorConfig(), logging.NewNoOpLogger()).Up(ctx, true)
+	assert.ErrorIs(t, err, database.ErrChecksumMismatch)
+}
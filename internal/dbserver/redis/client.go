@@ -25,13 +25,7 @@ func NewClient(logger logging.Logger) (*Client, error) {
 		opt.Password = config.GetUpstashRedisRestToken()
 	}
 
-	client := redis.NewClient(opt)
-
-	redisClient := &Client{
-		client: client,
-		logger: logger,
-	}
-
+	redisClient := NewClientWithRedis(redis.NewClient(opt), logger)
 	if err := redisClient.CheckConnection(); err != nil {
 		return nil, err
 	}
@@ -39,6 +33,14 @@ func NewClient(logger logging.Logger) (*Client, error) {
 	return redisClient, nil
 }
 
+// NewClientWithRedis wraps a connected go-redis client
+func NewClientWithRedis(client *redis.Client, logger logging.Logger) *Client {
+	return &Client{
+		client: client,
+		logger: logger,
+	}
+}
+
 func (c *Client) CheckConnection() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -5,22 +5,33 @@ import (
 	"time"
 
 	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository/queries"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	"github.com/trigg3rX/triggerx-backend/pkg/database"
 	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
+var (
+	// ErrApiKeyNotFound is returned for unknown keys and keys not matching their hash
+	ErrApiKeyNotFound = errors.New("api key not found")
+	// ErrApiKeyIDTaken is returned when a new API key has the ID of another key
+	ErrApiKeyIDTaken = errors.New("api key ID taken")
+)
+
 type ApiKeysRepository interface {
 	CreateApiKey(apiKey *commonTypes.ApiKey) error
 	GetApiKeyDataByOwner(owner string) ([]*commonTypes.ApiKey, error) // changed to return slice
 	GetApiKeyDataByKey(key string) (*commonTypes.ApiKey, error)
+	AuthenticateApiKey(key string) (*commonTypes.ApiKey, error)
 	GetApiKeyCounters(key string) (*types.ApiKeyCounters, error)
 	GetApiKeyByOwner(owner string) (key string, err error)
 	GetApiOwnerByApiKey(key string) (owner string, err error)
 	UpdateApiKey(apiKey *types.UpdateApiKeyRequest) error
 	UpdateApiKeyStatus(apiKey *types.UpdateApiKeyStatusRequest) error
 	UpdateApiKeyLastUsed(key string, isSuccess bool) error
+	UpdateApiKeyLastUsedIP(key, clientIP string) error
+	ReplaceApiKey(key, replacedBy string, expiresAt time.Time) error
 	DeleteApiKey(key string) error
 }
 
@@ -34,51 +45,53 @@ func NewApiKeysRepository(db *database.Connection) ApiKeysRepository {
 	}
 }
 
+// CreateApiKey stores a key under its ID, failing with ErrApiKeyIDTaken when the ID is used
 func (r *apiKeysRepository) CreateApiKey(apiKey *commonTypes.ApiKey) error {
-	err := r.db.Session().Query(queries.CreateApiKeyQuery, apiKey.Key, apiKey.Owner, apiKey.IsActive, apiKey.RateLimit, apiKey.LastUsed, apiKey.CreatedAt).Exec()
+	applied, err := r.db.Session().Query(queries.CreateApiKeyQuery,
+		apiKey.Key, apiKey.Owner, apiKey.IsActive, apiKey.RateLimit, apiKey.Tier, apiKey.KeyHash, apiKey.KeySalt,
+		apiKey.Scopes, apiKey.ExpiresAt, apiKey.AllowedIPs, apiKey.LastUsed, apiKey.CreatedAt,
+	).MapScanCAS(map[string]interface{}{})
 	if err != nil {
 		return err
 	}
-	return nil
+	if !applied {
+		return ErrApiKeyIDTaken
+	}
+	return r.db.Session().Query(queries.CreateApiKeyByOwnerQuery, apiKey.Owner, apiKey.Key, apiKey.CreatedAt).Exec()
 }
 
 func (r *apiKeysRepository) GetApiKeyDataByOwner(owner string) ([]*commonTypes.ApiKey, error) {
-	iter := r.db.Session().Query(queries.GetApiKeyDataByOwnerQuery, owner).Iter()
-	var apiKeys []*commonTypes.ApiKey
-	var key, ownerVal string
-	var isActive bool
-	var rateLimit int
-	var successCount, failedCount int64
-	var lastUsed, createdAt time.Time
-	for iter.Scan(&key, &ownerVal, &isActive, &rateLimit, &successCount, &failedCount, &lastUsed, &createdAt) {
-		apiKeys = append(apiKeys, &commonTypes.ApiKey{
-			Key:          key,
-			Owner:        ownerVal,
-			IsActive:     isActive,
-			RateLimit:    rateLimit,
-			SuccessCount: successCount,
-			FailedCount:  failedCount,
-			LastUsed:     lastUsed,
-			CreatedAt:    createdAt,
-		})
-	}
-	if err := iter.Close(); err != nil {
+	keys, err := r.lookupApiKeys(owner)
+	if err != nil {
 		return nil, err
 	}
+
+	var apiKeys []*commonTypes.ApiKey
+	for _, ownerKeys := range chunk(keys) {
+		iter := r.db.Session().Query(queries.GetApiKeyDataByApiKeysQuery, ownerKeys).Iter()
+		for {
+			apiKey := &commonTypes.ApiKey{}
+			if !iter.Scan(apiKeyColumns(apiKey)...) {
+				break
+			}
+			apiKeys = append(apiKeys, apiKey)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+	}
 	if len(apiKeys) == 0 {
 		return nil, errors.New("owner not found")
 	}
 	return apiKeys, nil
 }
 
+// GetApiKeyDataByKey reads a key by its ID
 func (r *apiKeysRepository) GetApiKeyDataByKey(key string) (*commonTypes.ApiKey, error) {
 	apiKey := &commonTypes.ApiKey{}
-	var successCount, failedCount int64
-	err := r.db.Session().Query(queries.GetApiKeyDataByApiKeyQuery, key).Scan(&apiKey.Key, &apiKey.Owner, &apiKey.IsActive, &apiKey.RateLimit, &successCount, &failedCount, &apiKey.LastUsed, &apiKey.CreatedAt)
-	apiKey.SuccessCount = successCount
-	apiKey.FailedCount = failedCount
+	err := r.db.Session().Query(queries.GetApiKeyDataByApiKeyQuery, key).Scan(apiKeyColumns(apiKey)...)
 	if err == gocql.ErrNotFound {
-		return nil, errors.New("api key not found")
+		return nil, ErrApiKeyNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -86,11 +99,37 @@ func (r *apiKeysRepository) GetApiKeyDataByKey(key string) (*commonTypes.ApiKey,
 	return apiKey, nil
 }
 
+// AuthenticateApiKey reads the key a client presented. Hashed keys are found by their ID and
+// checked against their hash, keys stored raw before hashing are found by their value.
+func (r *apiKeysRepository) AuthenticateApiKey(key string) (*commonTypes.ApiKey, error) {
+	if id, ok := auth.ApiKeyID(key); ok {
+		apiKey, err := r.GetApiKeyDataByKey(id)
+		if err == nil && len(apiKey.KeyHash) > 0 {
+			if !auth.VerifyApiKey(key, apiKey.KeySalt, apiKey.KeyHash) {
+				return nil, ErrApiKeyNotFound
+			}
+			return apiKey, nil
+		}
+		if err != nil && !errors.Is(err, ErrApiKeyNotFound) {
+			return nil, err
+		}
+	}
+
+	apiKey, err := r.GetApiKeyDataByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(apiKey.KeyHash) > 0 {
+		return nil, ErrApiKeyNotFound
+	}
+	return apiKey, nil
+}
+
 func (r *apiKeysRepository) GetApiKeyCounters(key string) (*types.ApiKeyCounters, error) {
 	callCount := &types.ApiKeyCounters{}
 	err := r.db.Session().Query(queries.GetApiKeyCallCountQuery, key).Scan(&callCount.SuccessCount, &callCount.FailedCount)
 	if err == gocql.ErrNotFound {
-		return nil, errors.New("api key not found")
+		return nil, ErrApiKeyNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -99,22 +138,21 @@ func (r *apiKeysRepository) GetApiKeyCounters(key string) (*types.ApiKeyCounters
 }
 
 func (r *apiKeysRepository) GetApiKeyByOwner(owner string) (key string, err error) {
-	key = ""
-	err = r.db.Session().Query(queries.GetApiKeyByOwnerQuery, owner).Scan(&key)
-	if err == gocql.ErrNotFound {
-		return "", errors.New("owner not found")
-	}
+	keys, err := r.lookupApiKeys(owner)
 	if err != nil {
 		return "", err
 	}
-	return key, nil
+	if len(keys) == 0 {
+		return "", errors.New("owner not found")
+	}
+	return keys[0], nil
 }
 
 func (r *apiKeysRepository) GetApiOwnerByApiKey(key string) (owner string, err error) {
 	owner = ""
 	err = r.db.Session().Query(queries.GetApiOwnerByApiKeyQuery, key).Scan(&owner)
 	if err == gocql.ErrNotFound {
-		return "", errors.New("api key not found")
+		return "", ErrApiKeyNotFound
 	}
 	if err != nil {
 		return "", err
@@ -122,8 +160,20 @@ func (r *apiKeysRepository) GetApiOwnerByApiKey(key string) (owner string, err e
 	return owner, nil
 }
 
+// UpdateApiKey writes the settings of a key, unset ones are cleared
 func (r *apiKeysRepository) UpdateApiKey(apiKey *types.UpdateApiKeyRequest) error {
-	err := r.db.Session().Query(queries.UpdateApiKeyQuery, apiKey.Key, apiKey.IsActive, apiKey.RateLimit).Exec()
+	var scopes, allowedIPs []string
+	var expiresAt time.Time
+	if apiKey.Scopes != nil {
+		scopes = *apiKey.Scopes
+	}
+	if apiKey.AllowedIPs != nil {
+		allowedIPs = *apiKey.AllowedIPs
+	}
+	if apiKey.ExpiresAt != nil {
+		expiresAt = *apiKey.ExpiresAt
+	}
+	err := r.db.Session().Query(queries.UpdateApiKeyQuery, apiKey.IsActive, apiKey.RateLimit, apiKey.Tier, scopes, expiresAt, allowedIPs, apiKey.Key).Exec()
 	if err != nil {
 		return err
 	}
@@ -153,11 +203,54 @@ func (r *apiKeysRepository) UpdateApiKeyLastUsed(key string, isSuccess bool) err
 	return nil
 }
 
-// DeleteApiKey physically deletes an API key from the apikeys table
+// UpdateApiKeyLastUsedIP records when and from where a key was last used
+func (r *apiKeysRepository) UpdateApiKeyLastUsedIP(key, clientIP string) error {
+	return r.db.Session().Query(queries.UpdateApiKeyLastUsedIPQuery, time.Now().UTC(), clientIP, key).Exec()
+}
+
+// ReplaceApiKey records the key a key was rotated to and ends its grace period at expiresAt
+func (r *apiKeysRepository) ReplaceApiKey(key, replacedBy string, expiresAt time.Time) error {
+	return r.db.Session().Query(queries.UpdateApiKeyReplacementQuery, replacedBy, expiresAt, key).Exec()
+}
+
+// DeleteApiKey physically deletes an API key from the apikeys table and its owner's lookup
 func (r *apiKeysRepository) DeleteApiKey(key string) error {
-	err := r.db.Session().Query(queries.DeleteApiKeyQuery, key).Exec()
+	var owner string
+	err := r.db.Session().Query(queries.GetApiOwnerByApiKeyQuery, key).Scan(&owner)
+	if err == gocql.ErrNotFound {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	return nil
+
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.DeleteApiKeyQuery, key)
+	batch.Query(queries.DeleteApiKeyByOwnerQuery, owner, key)
+	return r.db.Session().ExecuteBatch(batch)
+}
+
+// lookupApiKeys reads the keys of an owner from apikeys_by_owner
+func (r *apiKeysRepository) lookupApiKeys(owner string) ([]string, error) {
+	iter := r.db.Session().Query(queries.GetApiKeysByOwnerQuery, owner).Iter()
+
+	var keys []string
+	var key string
+	for iter.Scan(&key) {
+		keys = append(keys, key)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// apiKeyColumns returns the destinations of the columns the API key queries read
+func apiKeyColumns(apiKey *commonTypes.ApiKey) []interface{} {
+	return []interface{}{
+		&apiKey.Key, &apiKey.Owner, &apiKey.IsActive, &apiKey.RateLimit, &apiKey.Tier, &apiKey.KeyHash, &apiKey.KeySalt,
+		&apiKey.Scopes, &apiKey.ExpiresAt, &apiKey.AllowedIPs, &apiKey.ReplacedBy, &apiKey.SuccessCount, &apiKey.FailedCount,
+		&apiKey.LastUsed, &apiKey.LastUsedIP, &apiKey.CreatedAt,
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/gocql/gocql"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository/queries"
 	"github.com/trigg3rX/triggerx-backend/pkg/database"
 	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
@@ -29,14 +30,18 @@ func NewConditionJobRepository(db *database.Connection) ConditionJobRepository {
 }
 
 func (r *conditionJobRepository) CreateConditionJob(conditionJob *commonTypes.ConditionJobData) error {
-	err := r.db.Session().Query(queries.CreateConditionJobDataQuery,
-		conditionJob.JobID.ToBigInt(), conditionJob.TaskDefinitionID, conditionJob.ExpirationTime, conditionJob.Recurring,
+	jobID := conditionJob.JobID.ToBigInt()
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.CreateConditionJobDataQuery,
+		jobID, conditionJob.TaskDefinitionID, conditionJob.ExpirationTime, conditionJob.Recurring,
 		conditionJob.ConditionType, conditionJob.UpperLimit, conditionJob.LowerLimit,
 		conditionJob.ValueSourceType, conditionJob.ValueSourceUrl, conditionJob.TargetChainID,
 		conditionJob.TargetContractAddress, conditionJob.TargetFunction,
 		conditionJob.ABI, conditionJob.ArgType, conditionJob.Arguments,
 		conditionJob.DynamicArgumentsScriptUrl, conditionJob.IsCompleted, conditionJob.IsActive,
-		conditionJob.SelectedKeyRoute, time.Now(), time.Now()).Exec()
+		conditionJob.SelectedKeyRoute, time.Now(), time.Now())
+	setJobActive(batch, conditionJobType, jobID, conditionJob.IsActive)
+	err := r.db.Session().ExecuteBatch(batch)
 
 	if err != nil {
 		return err
@@ -79,7 +84,10 @@ func (r *conditionJobRepository) CompleteConditionJob(jobID *big.Int) error {
 }
 
 func (r *conditionJobRepository) UpdateConditionJobStatus(jobID *big.Int, isActive bool) error {
-	err := r.db.Session().Query(queries.UpdateConditionJobStatusQuery, isActive, jobID).Exec()
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.UpdateConditionJobStatusQuery, isActive, jobID)
+	setJobActive(batch, conditionJobType, jobID, isActive)
+	err := r.db.Session().ExecuteBatch(batch)
 	if err != nil {
 		return errors.New("failed to update condition job status")
 	}
@@ -88,22 +96,32 @@ func (r *conditionJobRepository) UpdateConditionJobStatus(jobID *big.Int, isActi
 }
 
 func (r *conditionJobRepository) GetActiveConditionJobs() ([]commonTypes.ConditionJobData, error) {
-	var conditionJobs []commonTypes.ConditionJobData
-	iter := r.db.Session().Query(queries.GetActiveConditionJobsQuery).Iter()
-	var conditionJob commonTypes.ConditionJobData
-	var jobIDBigInt *big.Int
-	for iter.Scan(
-		&jobIDBigInt, &conditionJob.ExpirationTime, &conditionJob.Recurring,
-		&conditionJob.ConditionType, &conditionJob.UpperLimit, &conditionJob.LowerLimit,
-		&conditionJob.ValueSourceType, &conditionJob.ValueSourceUrl, &conditionJob.TargetChainID,
-		&conditionJob.TargetContractAddress, &conditionJob.TargetFunction, &conditionJob.ABI,
-		&conditionJob.ArgType, &conditionJob.Arguments, &conditionJob.DynamicArgumentsScriptUrl,
-		&conditionJob.IsCompleted, &conditionJob.IsActive, &conditionJob.SelectedKeyRoute) {
-		conditionJob.JobID = commonTypes.NewBigInt(jobIDBigInt)
-		conditionJobs = append(conditionJobs, conditionJob)
-	}
-	if err := iter.Close(); err != nil {
+	jobIDs, err := lookupJobIDs(r.db.Session(), queries.GetActiveJobIDsByTypeQuery, conditionJobType)
+	if err != nil {
 		return nil, errors.New("failed to fetch active condition jobs")
 	}
+
+	var conditionJobs []commonTypes.ConditionJobData
+	for _, ids := range chunk(jobIDs) {
+		iter := r.db.Session().Query(queries.GetConditionJobsByJobIDsQuery, ids).Iter()
+		var conditionJob commonTypes.ConditionJobData
+		var jobIDBigInt *big.Int
+		for iter.Scan(
+			&jobIDBigInt, &conditionJob.ExpirationTime, &conditionJob.Recurring,
+			&conditionJob.ConditionType, &conditionJob.UpperLimit, &conditionJob.LowerLimit,
+			&conditionJob.ValueSourceType, &conditionJob.ValueSourceUrl, &conditionJob.TargetChainID,
+			&conditionJob.TargetContractAddress, &conditionJob.TargetFunction, &conditionJob.ABI,
+			&conditionJob.ArgType, &conditionJob.Arguments, &conditionJob.DynamicArgumentsScriptUrl,
+			&conditionJob.IsCompleted, &conditionJob.IsActive, &conditionJob.SelectedKeyRoute) {
+			if !conditionJob.IsActive {
+				continue
+			}
+			conditionJob.JobID = commonTypes.NewBigInt(jobIDBigInt)
+			conditionJobs = append(conditionJobs, conditionJob)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, errors.New("failed to fetch active condition jobs")
+		}
+	}
 	return conditionJobs, nil
 }
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/gocql/gocql"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository/queries"
 	"github.com/trigg3rX/triggerx-backend/pkg/database"
 	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
@@ -29,13 +30,17 @@ func NewEventJobRepository(db *database.Connection) EventJobRepository {
 }
 
 func (r *eventJobRepository) CreateEventJob(eventJob *commonTypes.EventJobData) error {
-	err := r.db.Session().Query(queries.CreateEventJobDataQuery,
-		eventJob.JobID.ToBigInt(), eventJob.TaskDefinitionID, eventJob.ExpirationTime, eventJob.Recurring,
+	jobID := eventJob.JobID.ToBigInt()
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.CreateEventJobDataQuery,
+		jobID, eventJob.TaskDefinitionID, eventJob.ExpirationTime, eventJob.Recurring,
 		eventJob.TriggerChainID, eventJob.TriggerContractAddress, eventJob.TriggerEvent,
 		eventJob.EventFilterParaName, eventJob.EventFilterValue,
 		eventJob.TargetChainID, eventJob.TargetContractAddress, eventJob.TargetFunction,
 		eventJob.ABI, eventJob.ArgType, eventJob.Arguments, eventJob.DynamicArgumentsScriptUrl,
-		eventJob.IsCompleted, eventJob.IsActive, time.Now(), time.Now()).Exec()
+		eventJob.IsCompleted, eventJob.IsActive, time.Now(), time.Now())
+	setJobActive(batch, eventJobType, jobID, eventJob.IsActive)
+	err := r.db.Session().ExecuteBatch(batch)
 
 	if err != nil {
 		return err
@@ -75,7 +80,10 @@ func (r *eventJobRepository) CompleteEventJob(jobID *big.Int) error {
 }
 
 func (r *eventJobRepository) UpdateEventJobStatus(jobID *big.Int, isActive bool) error {
-	err := r.db.Session().Query(queries.UpdateEventJobStatusQuery, isActive, jobID).Exec()
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.UpdateEventJobStatusQuery, isActive, jobID)
+	setJobActive(batch, eventJobType, jobID, isActive)
+	err := r.db.Session().ExecuteBatch(batch)
 	if err != nil {
 		return errors.New("failed to update event job status")
 	}
@@ -84,22 +92,32 @@ func (r *eventJobRepository) UpdateEventJobStatus(jobID *big.Int, isActive bool)
 }
 
 func (r *eventJobRepository) GetActiveEventJobs() ([]commonTypes.EventJobData, error) {
-	var eventJobs []commonTypes.EventJobData
-	iter := r.db.Session().Query(queries.GetActiveEventJobsQuery).Iter()
-	var eventJob commonTypes.EventJobData
-	var jobIDBigInt *big.Int
-	for iter.Scan(
-		&jobIDBigInt, &eventJob.ExpirationTime, &eventJob.Recurring,
-		&eventJob.TriggerChainID, &eventJob.TriggerContractAddress, &eventJob.TriggerEvent,
-		&eventJob.EventFilterParaName, &eventJob.EventFilterValue,
-		&eventJob.TargetChainID, &eventJob.TargetContractAddress, &eventJob.TargetFunction,
-		&eventJob.ABI, &eventJob.ArgType, &eventJob.Arguments, &eventJob.DynamicArgumentsScriptUrl,
-		&eventJob.IsCompleted, &eventJob.IsActive) {
-		eventJob.JobID = commonTypes.NewBigInt(jobIDBigInt)
-		eventJobs = append(eventJobs, eventJob)
-	}
-	if err := iter.Close(); err != nil {
+	jobIDs, err := lookupJobIDs(r.db.Session(), queries.GetActiveJobIDsByTypeQuery, eventJobType)
+	if err != nil {
 		return nil, errors.New("failed to fetch active event jobs")
 	}
+
+	var eventJobs []commonTypes.EventJobData
+	for _, ids := range chunk(jobIDs) {
+		iter := r.db.Session().Query(queries.GetEventJobsByJobIDsQuery, ids).Iter()
+		var eventJob commonTypes.EventJobData
+		var jobIDBigInt *big.Int
+		for iter.Scan(
+			&jobIDBigInt, &eventJob.ExpirationTime, &eventJob.Recurring,
+			&eventJob.TriggerChainID, &eventJob.TriggerContractAddress, &eventJob.TriggerEvent,
+			&eventJob.EventFilterParaName, &eventJob.EventFilterValue,
+			&eventJob.TargetChainID, &eventJob.TargetContractAddress, &eventJob.TargetFunction,
+			&eventJob.ABI, &eventJob.ArgType, &eventJob.Arguments, &eventJob.DynamicArgumentsScriptUrl,
+			&eventJob.IsCompleted, &eventJob.IsActive) {
+			if !eventJob.IsActive {
+				continue
+			}
+			eventJob.JobID = commonTypes.NewBigInt(jobIDBigInt)
+			eventJobs = append(eventJobs, eventJob)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, errors.New("failed to fetch active event jobs")
+		}
+	}
 	return eventJobs, nil
 }
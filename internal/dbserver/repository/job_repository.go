@@ -2,9 +2,12 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
+	"strconv"
 	"time"
 
+	"github.com/gocql/gocql"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository/queries"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	"github.com/trigg3rX/triggerx-backend/pkg/database"
@@ -16,13 +19,28 @@ type JobRepository interface {
 	UpdateJobFromUserInDB(jobID *big.Int, job *types.UpdateJobDataFromUserRequest) error
 	UpdateJobLastExecutedAt(jobID *big.Int, taskID int64, jobCostActual float64, lastExecutedAt time.Time) error
 	UpdateJobStatus(jobID *big.Int, status string) error
+	UpdateActiveJobStatus(jobID *big.Int, current, status types.JobStatus) error
 	GetJobByID(jobID *big.Int) (*commonTypes.JobData, error)
 	GetTaskDefinitionIDByJobID(jobID *big.Int) (int, error)
 	GetTaskFeesByJobID(jobID *big.Int) ([]types.TaskFeeResponse, error)
 	GetJobsByUserIDAndChainID(userID int64, createdChainID string) ([]commonTypes.JobData, error)
 	GetJobsBySafeAddress(safeAddress string) ([]commonTypes.JobData, error)
+	ListJobsByUserID(userID int64, opts types.ListOptions) (types.Page[commonTypes.JobData], error)
+	GetJobLifecycle(jobID *big.Int) (types.JobLifecycle, error)
+	TransitionJobStatus(jobID *big.Int, current types.JobLifecycle, status types.JobStatus) error
+	RestoreJobLifecycle(jobID *big.Int, status types.JobStatus, previous types.JobLifecycle) error
+	SchedulePause(jobID *big.Int, current types.JobLifecycle, pauseAt *time.Time) error
+	GetDueScheduledPauses(now time.Time) ([]types.ScheduledPause, error)
+	DeleteScheduledPause(pause types.ScheduledPause) error
 }
 
+// ErrJobStatusChanged is returned when a job's status changed since it was read, the change
+// based on it is not made
+var ErrJobStatusChanged = errors.New("job status changed concurrently")
+
+// ErrJobIDTaken is returned when a job with the ID already exists or is being created by another user
+var ErrJobIDTaken = errors.New("job id is already taken")
+
 type jobRepository struct {
 	db *database.Connection
 }
@@ -41,17 +59,53 @@ func (r *jobRepository) CreateNewJob(job *commonTypes.JobData) (*big.Int, error)
 	// 	return -1, nil
 	// }
 
-	err := r.db.Session().Query(queries.CreateJobDataQuery,
+	createdAt := time.Now()
+	if err := r.claimJobID(job, createdAt); err != nil {
+		return nil, err
+	}
+
+	// The job and its lookup rows are written in one logged batch, so lookups never miss a job
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.CreateJobDataQuery,
 		job.JobID.ToBigInt(), job.JobTitle, job.TaskDefinitionID, job.UserID, job.LinkJobID.ToBigInt(), job.ChainStatus,
-		job.Custom, job.TimeFrame, job.Recurring, job.Status, job.JobCostPrediction, time.Now(), time.Now(), job.Timezone, job.IsImua, job.CreatedChainID, job.SafeAddress).Exec()
+		job.Custom, job.TimeFrame, job.Recurring, job.Status, job.JobCostPrediction, createdAt, createdAt, job.Timezone, job.IsImua, job.CreatedChainID, job.SafeAddress)
+	batch.Query(queries.CreateJobByUserQuery, job.UserID, job.CreatedChainID, createdAt, job.JobID.ToBigInt())
+	if job.SafeAddress != "" {
+		batch.Query(queries.CreateJobBySafeAddressQuery, job.SafeAddress, createdAt, job.JobID.ToBigInt())
+	}
 
-	if err != nil {
+	if err := r.db.Session().ExecuteBatch(batch); err != nil {
+		if _, releaseErr := r.db.Session().Query(queries.ReleaseJobIDQuery, job.JobID.ToBigInt()).ScanCAS(new(string)); releaseErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed to release job id: %w", releaseErr))
+		}
 		return nil, err
 	}
 
 	return job.JobID.Int, nil
 }
 
+// claimJobID claims the job's ID with a stub row, failing with ErrJobIDTaken when a job with
+// the ID exists or another user claimed it. A stub of the same user without a status is left
+// by a create that failed before its job was written, and is claimed again.
+func (r *jobRepository) claimJobID(job *commonTypes.JobData, createdAt time.Time) error {
+	existing := make(map[string]interface{})
+	applied, err := r.db.Session().Query(queries.ClaimJobIDQuery,
+		job.JobID.ToBigInt(), job.UserID, createdAt).MapScanCAS(existing)
+	if err != nil {
+		return fmt.Errorf("failed to claim job id: %w", err)
+	}
+	if applied {
+		return nil
+	}
+
+	userID, _ := existing["user_id"].(int64)
+	status, _ := existing["status"].(string)
+	if userID != job.UserID || status != "" {
+		return ErrJobIDTaken
+	}
+	return nil
+}
+
 func (r *jobRepository) UpdateJobFromUserInDB(jobID *big.Int, job *types.UpdateJobDataFromUserRequest) error {
 	err := r.db.Session().Query(queries.UpdateJobDataFromUserQuery,
 		job.JobTitle, job.TimeFrame, job.Recurring, job.Status, job.JobCostPrediction, time.Now(), jobID).Exec()
@@ -86,6 +140,135 @@ func (r *jobRepository) UpdateJobStatus(jobID *big.Int, status string) error {
 	return nil
 }
 
+// UpdateActiveJobStatus sets the status of a job still in the current status, failing with
+// ErrJobStatusChanged when it was changed since it was read
+func (r *jobRepository) UpdateActiveJobStatus(jobID *big.Int, current, status types.JobStatus) error {
+	var existingStatus string
+	applied, err := r.db.Session().Query(queries.UpdateActiveJobStatusQuery,
+		string(status), time.Now(), jobID, string(current)).ScanCAS(&existingStatus)
+	if err != nil {
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+	if !applied {
+		return ErrJobStatusChanged
+	}
+	return nil
+}
+
+func (r *jobRepository) GetJobLifecycle(jobID *big.Int) (types.JobLifecycle, error) {
+	var lifecycle types.JobLifecycle
+	var status string
+	err := r.db.Session().Query(queries.GetJobLifecycleQuery, jobID).Scan(&status, &lifecycle.PausedAt, &lifecycle.PauseAt)
+	if err != nil {
+		return types.JobLifecycle{}, err
+	}
+	lifecycle.Status = types.JobStatus(status)
+	return lifecycle, nil
+}
+
+// TransitionJobStatus moves a job from the lifecycle it was read with to status, with a
+// lightweight transaction so concurrent changes of the job are not overwritten. It clears any
+// scheduled pause, and records when the job was paused.
+func (r *jobRepository) TransitionJobStatus(jobID *big.Int, current types.JobLifecycle, status types.JobStatus) error {
+	now := time.Now()
+	var pausedAt *time.Time
+	if status == types.JobStatusPaused {
+		pausedAt = &now
+	}
+
+	var existingStatus string
+	applied, err := r.db.Session().Query(queries.TransitionJobStatusQuery,
+		string(status), pausedAt, now, jobID, string(current.Status)).ScanCAS(&existingStatus)
+	if err != nil {
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+	if !applied {
+		return ErrJobStatusChanged
+	}
+
+	// A scheduled pause row left behind is dropped when it falls due
+	if current.PauseAt != nil {
+		_ = r.db.Session().Query(queries.DeleteScheduledPauseQuery, *current.PauseAt, jobID).Exec()
+	}
+	return nil
+}
+
+// RestoreJobLifecycle puts back the lifecycle a job had before TransitionJobStatus moved it to
+// status, including its scheduled pause. The job must still have status.
+func (r *jobRepository) RestoreJobLifecycle(jobID *big.Int, status types.JobStatus, previous types.JobLifecycle) error {
+	// The row is written first so a scheduled pause always has one, a row left by a failed
+	// restore is dropped when it falls due
+	if previous.PauseAt != nil {
+		if err := r.db.Session().Query(queries.CreateScheduledPauseQuery, *previous.PauseAt, jobID).Exec(); err != nil {
+			return fmt.Errorf("failed to restore scheduled pause: %w", err)
+		}
+	}
+
+	var existingStatus string
+	applied, err := r.db.Session().Query(queries.RestoreJobLifecycleQuery,
+		string(previous.Status), previous.PausedAt, previous.PauseAt, time.Now(), jobID, string(status)).ScanCAS(&existingStatus)
+	if err != nil {
+		return fmt.Errorf("failed to restore job status: %w", err)
+	}
+	if !applied {
+		return ErrJobStatusChanged
+	}
+	return nil
+}
+
+// SchedulePause schedules a pause of a job at pauseAt, replacing any scheduled one, or clears
+// it when pauseAt is nil. The job must still have the status it was read with.
+func (r *jobRepository) SchedulePause(jobID *big.Int, current types.JobLifecycle, pauseAt *time.Time) error {
+	// The row is written first so a scheduled pause always has one
+	if pauseAt != nil {
+		if err := r.db.Session().Query(queries.CreateScheduledPauseQuery, *pauseAt, jobID).Exec(); err != nil {
+			return fmt.Errorf("failed to schedule pause: %w", err)
+		}
+	}
+
+	var existingStatus string
+	applied, err := r.db.Session().Query(queries.UpdateJobPauseAtQuery,
+		pauseAt, time.Now(), jobID, string(current.Status)).ScanCAS(&existingStatus)
+	if err != nil {
+		return fmt.Errorf("failed to update job pause time: %w", err)
+	}
+	if !applied {
+		if pauseAt != nil {
+			_ = r.db.Session().Query(queries.DeleteScheduledPauseQuery, *pauseAt, jobID).Exec()
+		}
+		return ErrJobStatusChanged
+	}
+
+	if current.PauseAt != nil && (pauseAt == nil || !current.PauseAt.Equal(*pauseAt)) {
+		_ = r.db.Session().Query(queries.DeleteScheduledPauseQuery, *current.PauseAt, jobID).Exec()
+	}
+	return nil
+}
+
+// GetDueScheduledPauses returns the pauses scheduled at or before now, oldest first
+func (r *jobRepository) GetDueScheduledPauses(now time.Time) ([]types.ScheduledPause, error) {
+	iter := r.db.Session().Query(queries.GetDueScheduledPausesQuery, now).Iter()
+
+	var pauses []types.ScheduledPause
+	var jobID *big.Int
+	var pauseAt time.Time
+	for iter.Scan(&jobID, &pauseAt) {
+		pauses = append(pauses, types.ScheduledPause{JobID: jobID, PauseAt: pauseAt})
+		jobID = nil
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get scheduled pauses: %w", err)
+	}
+	return pauses, nil
+}
+
+func (r *jobRepository) DeleteScheduledPause(pause types.ScheduledPause) error {
+	if err := r.db.Session().Query(queries.DeleteScheduledPauseQuery, pause.PauseAt, pause.JobID).Exec(); err != nil {
+		return fmt.Errorf("failed to delete scheduled pause: %w", err)
+	}
+	return nil
+}
+
 func (r *jobRepository) GetJobByID(jobID *big.Int) (*commonTypes.JobData, error) {
 	var jobData commonTypes.JobData
 	var jobIDBigInt, linkJobIDBigInt *big.Int
@@ -114,82 +297,141 @@ func (r *jobRepository) GetTaskDefinitionIDByJobID(jobID *big.Int) (int, error)
 }
 
 func (r *jobRepository) GetTaskFeesByJobID(jobID *big.Int) ([]types.TaskFeeResponse, error) {
-	session := r.db.Session()
-	iter := session.Query(queries.GetTaskFeesByJobIDQuery, jobID).Iter()
+	taskIDs, err := lookupTaskIDs(r.db.Session(), jobID)
+	if err != nil {
+		return nil, err
+	}
 
 	var results []types.TaskFeeResponse
-	var taskID int64
-	var taskOpxCost float64
-	for iter.Scan(&taskID, &taskOpxCost) {
-		results = append(results, types.TaskFeeResponse{
-			TaskID:      taskID,
-			TaskOpxCost: taskOpxCost,
-		})
-	}
-	if err := iter.Close(); err != nil {
-		return nil, err
+	for _, ids := range chunk(taskIDs) {
+		iter := r.db.Session().Query(queries.GetTaskFeesByTaskIDsQuery, ids).Iter()
+		var taskID int64
+		var taskOpxCost float64
+		for iter.Scan(&taskID, &taskOpxCost) {
+			results = append(results, types.TaskFeeResponse{
+				TaskID:      taskID,
+				TaskOpxCost: taskOpxCost,
+			})
+		}
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
 	}
 	return results, nil
 }
 
 func (r *jobRepository) GetJobsByUserIDAndChainID(userID int64, createdChainID string) ([]commonTypes.JobData, error) {
-	session := r.db.Session()
-	iter := session.Query(queries.GetJobsByUserIDAndChainIDQuery, userID, createdChainID).Iter()
-
-	var jobs []commonTypes.JobData
-	for {
-		var (
-			jobIDBigInt     *big.Int
-			linkJobIDBigInt *big.Int
-			job             commonTypes.JobData
-		)
-		if !iter.Scan(
-			&jobIDBigInt, &job.JobTitle, &job.TaskDefinitionID, &job.UserID,
-			&linkJobIDBigInt, &job.ChainStatus, &job.Custom, &job.TimeFrame,
-			&job.Recurring, &job.Status, &job.JobCostPrediction, &job.JobCostActual,
-			&job.TaskIDs, &job.CreatedAt, &job.UpdatedAt, &job.LastExecutedAt,
-			&job.Timezone, &job.IsImua, &job.CreatedChainID, &job.SafeAddress,
-		) {
-			break
-		}
-		job.JobID = commonTypes.NewBigInt(jobIDBigInt)
-		job.LinkJobID = commonTypes.NewBigInt(linkJobIDBigInt)
-		jobs = append(jobs, job)
+	jobIDs, err := lookupJobIDs(r.db.Session(), queries.GetJobIDsByUserIDAndChainIDQuery, userID, createdChainID)
+	if err != nil {
+		return nil, err
 	}
+	return r.getJobsByIDs(jobIDs)
+}
 
-	if err := iter.Close(); err != nil {
+func (r *jobRepository) GetJobsBySafeAddress(safeAddress string) ([]commonTypes.JobData, error) {
+	jobIDs, err := lookupJobIDs(r.db.Session(), queries.GetJobIDsBySafeAddressQuery, safeAddress)
+	if err != nil {
 		return nil, err
 	}
-	return jobs, nil
+	return r.getJobsByIDs(jobIDs)
 }
 
-func (r *jobRepository) GetJobsBySafeAddress(safeAddress string) ([]commonTypes.JobData, error) {
-	session := r.db.Session()
-	iter := session.Query(queries.GetJobsBySafeAddressQuery, safeAddress).Iter()
+// jobByUser is a row of jobs_by_user
+type jobByUser struct {
+	createdChainID string
+	createdAt      time.Time
+	jobID          *big.Int
+}
 
-	var jobs []commonTypes.JobData
-	for {
-		var (
-			jobIDBigInt     *big.Int
-			linkJobIDBigInt *big.Int
-			job             commonTypes.JobData
-		)
-		if !iter.Scan(
-			&jobIDBigInt, &job.JobTitle, &job.TaskDefinitionID, &job.UserID,
-			&linkJobIDBigInt, &job.ChainStatus, &job.Custom, &job.TimeFrame,
-			&job.Recurring, &job.Status, &job.JobCostPrediction, &job.JobCostActual,
-			&job.TaskIDs, &job.CreatedAt, &job.UpdatedAt, &job.LastExecutedAt,
-			&job.Timezone, &job.IsImua, &job.CreatedChainID, &job.SafeAddress,
-		) {
-			break
+// ListJobsByUserID returns a page of the jobs of a user, filtered by status, task definition,
+// chain and creation time. Jobs are ordered by creation time within each chain.
+func (r *jobRepository) ListJobsByUserID(userID int64, opts types.ListOptions) (types.Page[commonTypes.JobData], error) {
+	scope := listScope("jobs_by_user", strconv.FormatInt(userID, 10), opts)
+	cursor, err := decodeCursor(opts.Cursor, scope)
+	if err != nil {
+		return types.Page[commonTypes.JobData]{}, err
+	}
+
+	// created_at can only be restricted in CQL within a chain, across chains it is filtered
+	// from the lookup rows
+	stmt, values := queries.ListJobsByUserQuery, []interface{}{userID}
+	if opts.ChainID != "" {
+		stmt, values = timeRange(opts, "created_at", queries.ListJobsByUserAndChainQuery, []interface{}{userID, opts.ChainID})
+	}
+	stmt += orderBy(opts, "created_chain_id ASC, created_at DESC, job_id DESC", "created_chain_id DESC, created_at ASC, job_id ASC")
+
+	jobs, next, err := collectPages(
+		func(state []byte, size int) ([]jobByUser, []byte, error) {
+			return readPage(r.db.Session(), stmt, values, state, size, func(iter *gocql.Iter, row *jobByUser) bool {
+				return iter.Scan(&row.createdChainID, &row.createdAt, &row.jobID)
+			})
+		},
+		func(rows []jobByUser) ([]commonTypes.JobData, error) {
+			jobIDs := make([]*big.Int, 0, len(rows))
+			for _, row := range rows {
+				if inRange(opts, row.createdAt) {
+					jobIDs = append(jobIDs, row.jobID)
+				}
+			}
+			jobs, err := r.getJobsByIDs(jobIDs)
+			if err != nil {
+				return nil, err
+			}
+
+			var matching []commonTypes.JobData
+			for _, job := range jobs {
+				if opts.Status != "" && job.Status != opts.Status {
+					continue
+				}
+				if opts.TaskDefinitionID != 0 && job.TaskDefinitionID != opts.TaskDefinitionID {
+					continue
+				}
+				matching = append(matching, job)
+			}
+			return matching, nil
+		},
+		cursor.State, opts.Limit)
+	if err != nil {
+		return types.Page[commonTypes.JobData]{}, fmt.Errorf("failed to list jobs of user %d: %w", userID, err)
+	}
+	return newPage(jobs, scope, "", next), nil
+}
+
+// getJobsByIDs reads the jobs in the order of jobIDs
+func (r *jobRepository) getJobsByIDs(jobIDs []*big.Int) ([]commonTypes.JobData, error) {
+	byID := make(map[string]commonTypes.JobData, len(jobIDs))
+	for _, ids := range chunk(jobIDs) {
+		iter := r.db.Session().Query(queries.GetJobsByJobIDsQuery, ids).Iter()
+		for {
+			var (
+				jobIDBigInt     *big.Int
+				linkJobIDBigInt *big.Int
+				job             commonTypes.JobData
+			)
+			if !iter.Scan(
+				&jobIDBigInt, &job.JobTitle, &job.TaskDefinitionID, &job.UserID,
+				&linkJobIDBigInt, &job.ChainStatus, &job.Custom, &job.TimeFrame,
+				&job.Recurring, &job.Status, &job.JobCostPrediction, &job.JobCostActual,
+				&job.TaskIDs, &job.CreatedAt, &job.UpdatedAt, &job.LastExecutedAt,
+				&job.Timezone, &job.IsImua, &job.CreatedChainID, &job.SafeAddress,
+			) {
+				break
+			}
+			job.JobID = commonTypes.NewBigInt(jobIDBigInt)
+			job.LinkJobID = commonTypes.NewBigInt(linkJobIDBigInt)
+			byID[jobIDBigInt.String()] = job
+		}
+
+		if err := iter.Close(); err != nil {
+			return nil, err
 		}
-		job.JobID = commonTypes.NewBigInt(jobIDBigInt)
-		job.LinkJobID = commonTypes.NewBigInt(linkJobIDBigInt)
-		jobs = append(jobs, job)
 	}
 
-	if err := iter.Close(); err != nil {
-		return nil, err
+	var jobs []commonTypes.JobData
+	for _, jobID := range jobIDs {
+		if job, ok := byID[jobID.String()]; ok {
+			jobs = append(jobs, job)
+		}
 	}
 	return jobs, nil
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 
@@ -51,8 +52,15 @@ func (r *keeperRepository) CreateKeeper(keeperData types.CreateKeeperData) (int6
 		return -1, fmt.Errorf("error getting max keeper ID: %v", err)
 	}
 
+	// The address is claimed first, so concurrent registrations of an address create one keeper
+	if err := claimAddress(r.db.Session(), queries.ClaimKeeperAddressQuery, keeperData.KeeperAddress, maxKeeperID+1); err != nil {
+		return -1, err
+	}
 	err = r.db.Session().Query(queries.CreateNewKeeperQuery, maxKeeperID+1, keeperData.KeeperName, keeperData.KeeperAddress, 1.0, 0.0, true, keeperData.EmailID).Exec()
 	if err != nil {
+		if releaseErr := releaseAddress(r.db.Session(), queries.ReleaseKeeperAddressQuery, keeperData.KeeperAddress, maxKeeperID+1); releaseErr != nil {
+			return -1, errors.Join(err, releaseErr)
+		}
 		return -1, err
 	}
 
@@ -64,9 +72,12 @@ func (r *keeperRepository) GetKeeperAsPerformer() ([]types.GetPerformerData, err
 
 	var performers []types.GetPerformerData
 	var performer types.GetPerformerData
+	var whitelisted bool
 	for iter.Scan(
-		&performer.KeeperID, &performer.KeeperAddress) {
-		performers = append(performers, performer)
+		&performer.KeeperID, &performer.KeeperAddress, &whitelisted) {
+		if whitelisted {
+			performers = append(performers, performer)
+		}
 	}
 
 	if err := iter.Close(); err != nil {
@@ -110,8 +121,7 @@ func (r *keeperRepository) GetKeeperDataByID(id int64) (commonTypes.KeeperData,
 }
 
 func (r *keeperRepository) CheckKeeperExists(address string) (int64, error) {
-	var id int64
-	err := r.db.Session().Query(queries.GetKeeperIDByAddressQuery, address).Scan(&id)
+	id, err := lookupID(r.db.Session(), queries.GetKeeperIDByAddressLookupQuery, address)
 	if err == gocql.ErrNotFound {
 		return -1, nil
 	}
@@ -122,8 +132,7 @@ func (r *keeperRepository) CheckKeeperExists(address string) (int64, error) {
 }
 
 func (r *keeperRepository) UpdateKeeperChatID(address string, chatID int64) error {
-	var id int64
-	err := r.db.Session().Query(queries.GetKeeperIDByAddressQuery, address).Scan(&id)
+	id, err := lookupID(r.db.Session(), queries.GetKeeperIDByAddressLookupQuery, address)
 	if err != nil {
 		return err
 	}
@@ -200,53 +209,20 @@ func (r *keeperRepository) GetKeeperCommunicationInfo(id int64) (types.KeeperCom
 }
 
 func (r *keeperRepository) GetKeeperLeaderboard() ([]types.KeeperLeaderboardEntry, error) {
-	iter := r.db.Session().Query(queries.GetKeeperLeaderboardQuery).Iter()
-
-	var keeperLeaderboard []types.KeeperLeaderboardEntry
-	var keeperEntry types.KeeperLeaderboardEntry
-
-	for iter.Scan(
-		&keeperEntry.KeeperID,
-		&keeperEntry.KeeperAddress,
-		&keeperEntry.KeeperName,
-		&keeperEntry.NoExecutedTasks,
-		&keeperEntry.NoAttestedTasks,
-		&keeperEntry.KeeperPoints,
-		&keeperEntry.OnImua,
-	) {
-		keeperLeaderboard = append(keeperLeaderboard, keeperEntry)
-	}
-
-	if err := iter.Close(); err != nil {
-		return nil, err
-	}
-
-	// Sort leaderboard by UserPoints (desc), TotalJobs (desc), TotalTasks (desc), UserID (asc)
-	sort.Slice(keeperLeaderboard, func(i, j int) bool {
-		// First compare UserPoints
-		if keeperLeaderboard[i].KeeperPoints != keeperLeaderboard[j].KeeperPoints {
-			return keeperLeaderboard[i].KeeperPoints > keeperLeaderboard[j].KeeperPoints
-		}
-		// If UserPoints equal, compare TotalJobs
-		if keeperLeaderboard[i].NoExecutedTasks != keeperLeaderboard[j].NoExecutedTasks {
-			return keeperLeaderboard[i].NoExecutedTasks > keeperLeaderboard[j].NoExecutedTasks
-		}
-		// If TotalJobs equal, compare TotalTasks
-		if keeperLeaderboard[i].NoAttestedTasks != keeperLeaderboard[j].NoAttestedTasks {
-			return keeperLeaderboard[i].NoAttestedTasks > keeperLeaderboard[j].NoAttestedTasks
-		}
-		// If all else equal, sort by UserID ascending
-		return keeperLeaderboard[i].KeeperID < keeperLeaderboard[j].KeeperID
-	})
-
-	return keeperLeaderboard, nil
+	return r.getKeeperLeaderboard(func(types.KeeperLeaderboardEntry) bool { return true })
 }
 
 func (r *keeperRepository) GetKeeperLeaderboardByOnImua(onImua bool) ([]types.KeeperLeaderboardEntry, error) {
-	iter := r.db.Session().Query(queries.GetKeeperLeaderboardByOnImuaQuery, onImua).Iter()
+	return r.getKeeperLeaderboard(func(entry types.KeeperLeaderboardEntry) bool { return entry.OnImua == onImua })
+}
+
+// getKeeperLeaderboard reads the registered and whitelisted keepers that keep accepts, ranked
+func (r *keeperRepository) getKeeperLeaderboard(keep func(types.KeeperLeaderboardEntry) bool) ([]types.KeeperLeaderboardEntry, error) {
+	iter := r.db.Session().Query(queries.GetKeeperLeaderboardQuery).Iter()
 
 	var keeperLeaderboard []types.KeeperLeaderboardEntry
 	var keeperEntry types.KeeperLeaderboardEntry
+	var whitelisted bool
 
 	for iter.Scan(
 		&keeperEntry.KeeperID,
@@ -256,8 +232,11 @@ func (r *keeperRepository) GetKeeperLeaderboardByOnImua(onImua bool) ([]types.Ke
 		&keeperEntry.NoAttestedTasks,
 		&keeperEntry.KeeperPoints,
 		&keeperEntry.OnImua,
+		&whitelisted,
 	) {
-		keeperLeaderboard = append(keeperLeaderboard, keeperEntry)
+		if whitelisted && keep(keeperEntry) {
+			keeperLeaderboard = append(keeperLeaderboard, keeperEntry)
+		}
 	}
 
 	if err := iter.Close(); err != nil {
@@ -287,28 +266,41 @@ func (r *keeperRepository) GetKeeperLeaderboardByOnImua(onImua bool) ([]types.Ke
 
 func (r *keeperRepository) GetKeeperLeaderboardByIdentifierInDB(address string, name string) (types.KeeperLeaderboardEntry, error) {
 	var keeperEntry types.KeeperLeaderboardEntry
-	var query string
-	var args []interface{}
 
 	if address != "" {
-		query = queries.GetKeeperLeaderboardByAddressQuery
-		args = append(args, address)
-	} else {
-		query = queries.GetKeeperLeaderboardByNameQuery
-		args = append(args, name)
+		id, err := lookupID(r.db.Session(), queries.GetKeeperIDByAddressLookupQuery, address)
+		if err != nil {
+			return types.KeeperLeaderboardEntry{}, err
+		}
+		var registered bool
+		err = r.db.Session().Query(queries.GetKeeperLeaderboardByIDQuery, id).Scan(&keeperEntry.KeeperID, &keeperEntry.KeeperAddress, &keeperEntry.KeeperName, &keeperEntry.NoExecutedTasks, &keeperEntry.NoAttestedTasks, &keeperEntry.KeeperPoints, &registered)
+		if err != nil {
+			return types.KeeperLeaderboardEntry{}, err
+		}
+		if !registered {
+			return types.KeeperLeaderboardEntry{}, gocql.ErrNotFound
+		}
+		return keeperEntry, nil
 	}
 
-	err := r.db.Session().Query(query, args...).Scan(&keeperEntry.KeeperID, &keeperEntry.KeeperAddress, &keeperEntry.KeeperName, &keeperEntry.NoExecutedTasks, &keeperEntry.NoAttestedTasks, &keeperEntry.KeeperPoints)
-	if err != nil {
+	// Names are not unique, the first registered keeper of the name is returned
+	iter := r.db.Session().Query(queries.GetKeeperLeaderboardByNameQuery, name).Iter()
+	var registered bool
+	for iter.Scan(&keeperEntry.KeeperID, &keeperEntry.KeeperAddress, &keeperEntry.KeeperName, &keeperEntry.NoExecutedTasks, &keeperEntry.NoAttestedTasks, &keeperEntry.KeeperPoints, &registered) {
+		if registered {
+			_ = iter.Close()
+			return keeperEntry, nil
+		}
+	}
+	if err := iter.Close(); err != nil {
 		return types.KeeperLeaderboardEntry{}, err
 	}
 
-	return keeperEntry, nil
+	return types.KeeperLeaderboardEntry{}, gocql.ErrNotFound
 }
 
 func (r *keeperRepository) CheckKeeperExistsByAddress(address string) (int64, error) {
-	var id int64
-	err := r.db.Session().Query(queries.GetKeeperIDByAddressQuery, address).Scan(&id)
+	id, err := lookupID(r.db.Session(), queries.GetKeeperIDByAddressLookupQuery, address)
 	if err == gocql.ErrNotFound {
 		return 0, nil
 	}
@@ -346,6 +338,9 @@ func (r *keeperRepository) CreateOrUpdateKeeperFromGoogleForm(keeperData types.G
 		return 0, err
 	}
 	currentKeeperID := maxKeeperID + 1
+	if err := claimAddress(r.db.Session(), queries.ClaimKeeperAddressQuery, keeperData.KeeperAddress, currentKeeperID); err != nil {
+		return 0, err
+	}
 	err = r.db.Session().Query(
 		queries.CreateNewKeeperFromGoogleFormQuery,
 		currentKeeperID,
@@ -356,6 +351,9 @@ func (r *keeperRepository) CreateOrUpdateKeeperFromGoogleForm(keeperData types.G
 		keeperData.OnImua,
 	).Exec()
 	if err != nil {
+		if releaseErr := releaseAddress(r.db.Session(), queries.ReleaseKeeperAddressQuery, keeperData.KeeperAddress, currentKeeperID); releaseErr != nil {
+			return 0, errors.Join(err, releaseErr)
+		}
 		return 0, err
 	}
 	return currentKeeperID, nil
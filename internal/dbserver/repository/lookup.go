@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository/queries"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+)
+
+// lookupChunkSize bounds the partitions read by one IN query, so a large lookup does not
+// pile onto a single coordinator
+const lookupChunkSize = 100
+
+// Job types of active_jobs_by_type
+const (
+	timeJobType      = "time"
+	eventJobType     = "event"
+	conditionJobType = "condition"
+)
+
+// timeJobMinuteGrace keeps a time_jobs_by_minute row past its minute, so a poll running late
+// still finds it
+const timeJobMinuteGrace = time.Hour
+
+// maxTTL is the longest TTL Scylla accepts, 20 years
+const maxTTL = 20 * 365 * 24 * time.Hour
+
+// ErrAddressTaken is returned when an address is already claimed by another user or keeper
+var ErrAddressTaken = errors.New("address is already registered")
+
+// chunk splits keys into slices of at most lookupChunkSize
+func chunk[T any](keys []T) [][]T {
+	var chunks [][]T
+	for start := 0; start < len(keys); start += lookupChunkSize {
+		end := min(start+lookupChunkSize, len(keys))
+		chunks = append(chunks, keys[start:end])
+	}
+	return chunks
+}
+
+// claimAddress claims address for id in a lookup table with a lightweight transaction. It
+// fails with ErrAddressTaken when the address is claimed by another id, claiming an address
+// for the id it already belongs to succeeds.
+func claimAddress(session database.Sessioner, claimQuery, address string, id int64) error {
+	var existingAddress string
+	var existingID int64
+	applied, err := session.Query(claimQuery, address, id).ScanCAS(&existingAddress, &existingID)
+	if err != nil {
+		return fmt.Errorf("failed to claim address %s: %w", address, err)
+	}
+	if !applied && existingID != id {
+		return ErrAddressTaken
+	}
+	return nil
+}
+
+// releaseAddress drops the claim of id on address, used when writing the claimed row failed
+func releaseAddress(session database.Sessioner, releaseQuery, address string, id int64) error {
+	if _, err := session.Query(releaseQuery, address, id).ScanCAS(new(int64)); err != nil {
+		return fmt.Errorf("failed to release address %s: %w", address, err)
+	}
+	return nil
+}
+
+// lookupJobIDs reads job IDs from a lookup table, in its clustering order
+func lookupJobIDs(session database.Sessioner, query string, values ...interface{}) ([]*big.Int, error) {
+	iter := session.Query(query, values...).Iter()
+
+	var jobIDs []*big.Int
+	for {
+		var jobID *big.Int
+		if !iter.Scan(&jobID) {
+			break
+		}
+		jobIDs = append(jobIDs, jobID)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return jobIDs, nil
+}
+
+// setJobActive adds the active_jobs_by_type write matching isActive to batch
+func setJobActive(batch *gocql.Batch, jobType string, jobID *big.Int, isActive bool) {
+	if isActive {
+		batch.Query(queries.CreateActiveJobByTypeQuery, jobType, jobID)
+		return
+	}
+	batch.Query(queries.DeleteActiveJobByTypeQuery, jobType, jobID)
+}
+
+// scheduleTimeJob adds the time_jobs_by_minute row of a next execution to batch
+func scheduleTimeJob(batch *gocql.Batch, jobID *big.Int, nextExecution time.Time) {
+	ttl := min(time.Until(nextExecution)+timeJobMinuteGrace, maxTTL)
+	ttl = max(ttl, timeJobMinuteGrace)
+	batch.Query(queries.CreateTimeJobByMinuteQuery, executionMinute(nextExecution), nextExecution, jobID, int(ttl.Seconds()))
+}
+
+// executionMinute is the time_jobs_by_minute partition of an execution time
+func executionMinute(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Minute)
+}
+
+// executionMinutes lists the time_jobs_by_minute partitions from one to another execution
+// time, both included
+func executionMinutes(from, to time.Time) []time.Time {
+	var minutes []time.Time
+	for minute := executionMinute(from); !minute.After(to); minute = minute.Add(time.Minute) {
+		minutes = append(minutes, minute)
+	}
+	return minutes
+}
+
+// lookupID reads the id an address maps to in a lookup table
+func lookupID(session database.Sessioner, lookupQuery, address string) (int64, error) {
+	var id int64
+	if err := session.Query(lookupQuery, address).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
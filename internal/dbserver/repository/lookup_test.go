@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository/queries"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func TestChunk(t *testing.T) {
+	assert.Empty(t, chunk([]int64{}))
+
+	keys := make([]int64, 2*lookupChunkSize+1)
+	chunks := chunk(keys)
+	require.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], lookupChunkSize)
+	assert.Len(t, chunks[1], lookupChunkSize)
+	assert.Len(t, chunks[2], 1)
+}
+
+func TestExecutionMinutes(t *testing.T) {
+	from := time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC)
+	minutes := executionMinutes(from, from.Add(2*time.Minute))
+	require.Len(t, minutes, 3)
+	assert.Equal(t, time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), minutes[0])
+	assert.Equal(t, time.Date(2026, 1, 1, 12, 2, 0, 0, time.UTC), minutes[2])
+
+	assert.Len(t, executionMinutes(from, from), 1)
+}
+
+func TestScheduleTimeJob(t *testing.T) {
+	jobID := big.NewInt(7)
+	nextExecution := time.Now().Add(24 * time.Hour)
+
+	batch := &gocql.Batch{}
+	scheduleTimeJob(batch, jobID, nextExecution)
+	scheduleTimeJob(batch, jobID, time.Now().Add(-time.Hour))
+	require.Len(t, batch.Entries, 2)
+
+	args := batch.Entries[0].Args
+	assert.Equal(t, queries.CreateTimeJobByMinuteQuery, batch.Entries[0].Stmt)
+	assert.Equal(t, executionMinute(nextExecution), args[0])
+	assert.Equal(t, jobID, args[2])
+	assert.InDelta(t, (24*time.Hour + timeJobMinuteGrace).Seconds(), args[3], 5)
+
+	// A past execution is kept for the grace period
+	assert.Equal(t, int(timeJobMinuteGrace.Seconds()), batch.Entries[1].Args[3])
+}
+
+// The benchmarks compare the ALLOW FILTERING queries the lookup tables replaced with the
+// lookups, against a local Scylla with the schema applied (just db-setup):
+//
+//	SCYLLA_BENCH_HOST=localhost:9042 go test -run '^$' -bench Lookup ./internal/dbserver/repository/
+const (
+	benchUsers       = 50
+	benchJobsPerUser = 20
+	benchTasksPerJob = 50
+
+	allowFilteringJobsByUserQuery = `
+		SELECT job_id, job_title, task_definition_id, user_id, link_job_id, chain_status,
+			custom, time_frame, recurring, status, job_cost_prediction, job_cost_actual,
+			task_ids, created_at, updated_at, last_executed_at, timezone, is_imua, created_chain_id, safe_address
+		FROM triggerx.job_data
+		WHERE user_id = ? AND created_chain_id = ? ALLOW FILTERING`
+
+	allowFilteringTasksByJobQuery = `
+		SELECT task_id, task_number, task_opx_cost, execution_timestamp, execution_tx_hash, task_performer_id,
+			task_attester_ids, is_accepted, task_status, task_error, converted_arguments
+		FROM triggerx.task_data
+		WHERE job_id = ? ALLOW FILTERING`
+
+	allowFilteringKeeperByAddressQuery = `
+		SELECT keeper_id FROM triggerx.keeper_data
+		WHERE keeper_address = ? ALLOW FILTERING`
+)
+
+const benchChainID = "84532"
+
+// benchFixture is the data seeded for the benchmarks, removed when they finish
+type benchFixture struct {
+	userID        int64
+	jobID         *big.Int
+	keeperAddress string
+}
+
+func benchConnection(b *testing.B) *database.Connection {
+	host := os.Getenv("SCYLLA_BENCH_HOST")
+	if host == "" {
+		b.Skip("SCYLLA_BENCH_HOST is not set")
+	}
+	conn, err := database.NewConnection(database.NewConfig("", "").WithHosts([]string{host}), logging.NewNoOpLogger())
+	require.NoError(b, err)
+	return conn
+}
+
+// seedBench writes benchUsers users' jobs and tasks, so the filtering queries scan other
+// users' rows as they would in production
+func seedBench(b *testing.B, conn *database.Connection) benchFixture {
+	session := conn.Session()
+	jobs := NewJobRepository(conn)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	var fixture benchFixture
+	var userIDs []int64
+	var jobIDs []*big.Int
+	var taskIDs []int64
+	for u := 0; u < benchUsers; u++ {
+		userID := rng.Int63()
+		userIDs = append(userIDs, userID)
+		for j := 0; j < benchJobsPerUser; j++ {
+			jobID := big.NewInt(rng.Int63())
+			_, err := jobs.CreateNewJob(&commonTypes.JobData{
+				JobID:          commonTypes.NewBigInt(jobID),
+				JobTitle:       "lookup benchmark",
+				UserID:         userID,
+				CreatedChainID: benchChainID,
+			})
+			require.NoError(b, err)
+			jobIDs = append(jobIDs, jobID)
+
+			for t := 0; t < benchTasksPerJob; t++ {
+				taskID := rng.Int63()
+				createdAt := time.Now()
+				batch := session.NewBatch(gocql.LoggedBatch)
+				batch.Query(queries.CreateTaskDataQuery, taskID, jobID, 1, createdAt, false)
+				batch.Query(queries.CreateTaskByJobQuery, jobID, createdAt, taskID)
+				require.NoError(b, session.ExecuteBatch(batch))
+				taskIDs = append(taskIDs, taskID)
+			}
+		}
+		fixture.userID = userID
+		fixture.jobID = jobIDs[len(jobIDs)-1]
+	}
+
+	keeperID := rng.Int63()
+	fixture.keeperAddress = fmt.Sprintf("0xbench%x", keeperID)
+	require.NoError(b, session.Query(`INSERT INTO triggerx.keeper_data (keeper_id, keeper_address) VALUES (?, ?)`, keeperID, fixture.keeperAddress).Exec())
+	require.NoError(b, claimAddress(session, queries.ClaimKeeperAddressQuery, fixture.keeperAddress, keeperID))
+
+	b.Cleanup(func() {
+		for _, ids := range chunk(taskIDs) {
+			_ = session.Query(`DELETE FROM triggerx.task_data WHERE task_id IN ?`, ids).Exec()
+		}
+		for _, ids := range chunk(jobIDs) {
+			_ = session.Query(`DELETE FROM triggerx.job_data WHERE job_id IN ?`, ids).Exec()
+			_ = session.Query(`DELETE FROM triggerx.tasks_by_job WHERE job_id IN ?`, ids).Exec()
+		}
+		_ = session.Query(`DELETE FROM triggerx.jobs_by_user WHERE user_id IN ?`, userIDs).Exec()
+		_ = session.Query(`DELETE FROM triggerx.keeper_data WHERE keeper_id = ?`, keeperID).Exec()
+		_ = session.Query(`DELETE FROM triggerx.keepers_by_address WHERE keeper_address = ?`, fixture.keeperAddress).Exec()
+	})
+	return fixture
+}
+
+// drain reads every row of iter without decoding it
+func drain(b *testing.B, iter *gocql.Iter) {
+	scanner := iter.Scanner()
+	for scanner.Next() {
+	}
+	require.NoError(b, scanner.Err())
+}
+
+func BenchmarkLookup(b *testing.B) {
+	conn := benchConnection(b)
+	fixture := seedBench(b, conn)
+	jobs := NewJobRepository(conn)
+	tasks := NewTaskRepository(conn)
+	keepers := NewKeeperRepository(conn)
+
+	b.Run("JobsByUser/AllowFiltering", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			drain(b, conn.Session().Query(allowFilteringJobsByUserQuery, fixture.userID, benchChainID).Iter())
+		}
+	})
+	b.Run("JobsByUser/Lookup", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := jobs.GetJobsByUserIDAndChainID(fixture.userID, benchChainID)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("TasksByJob/AllowFiltering", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			drain(b, conn.Session().Query(allowFilteringTasksByJobQuery, fixture.jobID).Iter())
+		}
+	})
+	b.Run("TasksByJob/Lookup", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := tasks.GetTasksByJobID(fixture.jobID)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("KeeperByAddress/AllowFiltering", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			drain(b, conn.Session().Query(allowFilteringKeeperByAddressQuery, fixture.keeperAddress).Iter())
+		}
+	})
+	b.Run("KeeperByAddress/Lookup", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := keepers.CheckKeeperExists(fixture.keeperAddress)
+			require.NoError(b, err)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+)
+
+// maxScanFactor bounds the lookup rows a page reads to this many times its limit, so a filter
+// matching few rows returns a short page and a cursor instead of scanning a whole partition
+const maxScanFactor = 10
+
+// ErrInvalidCursor is returned for a cursor that is malformed or was issued for another list
+// or other filters
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// listCursor is the position of a list: the Scylla paging state of its lookup query, and for
+// lists spanning several partitions the partition it is in. Scope ties it to the list and
+// options it was issued for.
+type listCursor struct {
+	Scope  string `json:"q"`
+	Bucket string `json:"b,omitempty"`
+	State  []byte `json:"s,omitempty"`
+}
+
+// listScope identifies a list, the key of its partition and the options that select its
+// rows, apart from the page size and cursor
+func listScope(list, key string, opts types.ListOptions) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s|%s|%s|%s|%d|%s|%d|%d",
+		list, key, opts.Order, opts.Status, opts.TaskDefinitionID, opts.ChainID,
+		opts.From.UnixMilli(), opts.To.UnixMilli()))
+	return hex.EncodeToString(sum[:8])
+}
+
+func encodeCursor(cursor listCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor decodes a cursor of the list with the given scope, the empty cursor is the
+// start of the list
+func decodeCursor(token, scope string) (listCursor, error) {
+	if token == "" {
+		return listCursor{Scope: scope}, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return listCursor{}, ErrInvalidCursor
+	}
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Scope != scope {
+		return listCursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// readPage reads one page of at most size rows of stmt from state, and the paging state of
+// the page after it, nil when the rows ran out
+func readPage[T any](session database.Sessioner, stmt string, values []interface{}, state []byte, size int, scan func(*gocql.Iter, *T) bool) ([]T, []byte, error) {
+	iter := session.Query(stmt, values...).PageSize(size).PageState(state).Iter()
+	next := iter.PageState()
+
+	var rows []T
+	for {
+		var row T
+		if !scan(iter, &row) {
+			break
+		}
+		rows = append(rows, row)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, nil, err
+	}
+	if len(next) == 0 {
+		next = nil
+	}
+	return rows, next, nil
+}
+
+// collectPages fills a page of at most limit items from a lookup query, resolving each page
+// of lookup rows to the items that pass the list's filters. Every lookup page is no larger
+// than the items still wanted and is resolved whole, so the paging state after it resumes the
+// list without skipping rows. It stops when the page is full, the rows ran out, or maxScan
+// lookup rows were read, and returns the paging state to resume from, nil when the rows ran
+// out.
+func collectPages[K, T any](read func(state []byte, size int) ([]K, []byte, error), resolve func([]K) ([]T, error), state []byte, limit int) ([]T, []byte, error) {
+	var items []T
+	scanned := 0
+	for len(items) < limit && scanned < limit*maxScanFactor {
+		keys, next, err := read(state, limit-len(items))
+		if err != nil {
+			return nil, nil, err
+		}
+		scanned += len(keys)
+
+		resolved, err := resolve(keys)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, resolved...)
+
+		state = next
+		if state == nil {
+			break
+		}
+	}
+	return items, state, nil
+}
+
+// newPage returns a page of items continuing at state in bucket, the end of the list when
+// both are empty
+func newPage[T any](items []T, scope, bucket string, state []byte) types.Page[T] {
+	page := types.Page[T]{Items: items}
+	if state != nil || bucket != "" {
+		page.HasMore = true
+		page.NextCursor = encodeCursor(listCursor{Scope: scope, Bucket: bucket, State: state})
+	}
+	return page
+}
+
+// scanID scans a lookup row of a single ID column
+func scanID[T any](iter *gocql.Iter, id *T) bool {
+	return iter.Scan(id)
+}
+
+// orderBy returns the ORDER BY clause listing clustering columns newest first for SortDesc,
+// given as they are in the table's clustering order
+func orderBy(opts types.ListOptions, clustering, reversed string) string {
+	if opts.Order == types.SortAsc {
+		return " ORDER BY " + reversed
+	}
+	return " ORDER BY " + clustering
+}
+
+// inRange reports whether t is within the From and To bounds of opts
+func inRange(opts types.ListOptions, t time.Time) bool {
+	if !opts.From.IsZero() && t.Before(opts.From) {
+		return false
+	}
+	if !opts.To.IsZero() && !t.Before(opts.To) {
+		return false
+	}
+	return true
+}
+
+// timeRange appends the From and To bounds of opts on column to stmt and values
+func timeRange(opts types.ListOptions, column, stmt string, values []interface{}) (string, []interface{}) {
+	if !opts.From.IsZero() {
+		stmt += " AND " + column + " >= ?"
+		values = append(values, opts.From)
+	}
+	if !opts.To.IsZero() {
+		stmt += " AND " + column + " < ?"
+		values = append(values, opts.To)
+	}
+	return stmt, values
+}
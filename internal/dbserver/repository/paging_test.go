@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	opts := types.ListOptions{Order: types.SortDesc, Status: "completed"}
+	scope := listScope("tasks_by_job", "42", opts)
+
+	token := encodeCursor(listCursor{Scope: scope, Bucket: "2025-06-02", State: []byte{1, 2, 3}})
+	cursor, err := decodeCursor(token, scope)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-06-02", cursor.Bucket)
+	assert.Equal(t, []byte{1, 2, 3}, cursor.State)
+
+	// The empty cursor starts the list
+	cursor, err = decodeCursor("", scope)
+	require.NoError(t, err)
+	assert.Nil(t, cursor.State)
+
+	// A cursor is only valid for the list, key and filters it was issued for
+	for _, other := range []string{
+		listScope("tasks_by_job", "43", opts),
+		listScope("tasks_by_job", "42", types.ListOptions{Order: types.SortAsc, Status: "completed"}),
+		listScope("tasks_by_job", "42", types.ListOptions{Order: types.SortDesc, Status: "completed", From: time.Unix(1, 0)}),
+	} {
+		_, err := decodeCursor(token, other)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	}
+
+	_, err = decodeCursor("not a cursor!", scope)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+// fakePages serves rows in pages the way a paged query does, the state being the offset
+func fakePages(rows []int) func(state []byte, size int) ([]int, []byte, error) {
+	return func(state []byte, size int) ([]int, []byte, error) {
+		offset := 0
+		if state != nil {
+			offset = int(state[0])
+		}
+		end := min(offset+size, len(rows))
+		if end == len(rows) {
+			return rows[offset:end], nil, nil
+		}
+		return rows[offset:end], []byte{byte(end)}, nil
+	}
+}
+
+func TestCollectPages(t *testing.T) {
+	rows := make([]int, 30)
+	for i := range rows {
+		rows[i] = i
+	}
+	even := func(keys []int) ([]int, error) {
+		var items []int
+		for _, key := range keys {
+			if key%2 == 0 {
+				items = append(items, key)
+			}
+		}
+		return items, nil
+	}
+
+	// Pages resume where the previous one stopped, without skipping filtered rows
+	var all []int
+	var state []byte
+	for {
+		items, next, err := collectPages(fakePages(rows), even, state, 4)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(items), 4)
+		all = append(all, items...)
+		if next == nil {
+			break
+		}
+		state = next
+	}
+	expected, _ := even(rows)
+	assert.Equal(t, expected, all)
+
+	// A filter matching nothing stops at the scan cap with a state to resume from
+	none := func([]int) ([]int, error) { return nil, nil }
+	items, next, err := collectPages(fakePages(rows), none, nil, 2)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	assert.Equal(t, []byte{2 * maxScanFactor}, next)
+
+	_, _, err = collectPages(fakePages(rows), func([]int) ([]int, error) { return nil, errors.New("boom") }, nil, 2)
+	assert.Error(t, err)
+}
@@ -2,26 +2,38 @@ package queries
 
 // Create Queries
 const (
+	// The key column is the ID of the key, which may only be taken once
 	CreateApiKeyQuery = `
-			INSERT INTO triggerx.apikeys (key, owner, is_active, rate_limit, last_used, created_at)
-			VALUES (?, ?, ?, ?, ?, ?)`
+			INSERT INTO triggerx.apikeys (key, owner, is_active, rate_limit, tier, key_hash, key_salt, scopes, expires_at, allowed_ips, last_used, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`
 )
 
 // Update Queries
 const (
 	UpdateApiKeyQuery = `
-			UPDATE triggerx.apikeys 
-			SET is_active = ?, rate_limit = ? 
+			UPDATE triggerx.apikeys
+			SET is_active = ?, rate_limit = ?, tier = ?, scopes = ?, expires_at = ?, allowed_ips = ?
 			WHERE key = ?`
 
 	UpdateApiKeyStatusQuery = `
-			UPDATE triggerx.apikeys 
-			SET is_active = ? 
+			UPDATE triggerx.apikeys
+			SET is_active = ?
 			WHERE key = ?`
 
 	UpdateApiKeyLastUsedQuery = `
-			UPDATE triggerx.apikeys 
-			SET last_used = ?, success_count = ?, failed_count = ? 
+			UPDATE triggerx.apikeys
+			SET last_used = ?, success_count = ?, failed_count = ?
+			WHERE key = ?`
+
+	UpdateApiKeyLastUsedIPQuery = `
+			UPDATE triggerx.apikeys
+			SET last_used = ?, last_used_ip = ?
+			WHERE key = ?`
+
+	// Ends a rotated key's grace period
+	UpdateApiKeyReplacementQuery = `
+			UPDATE triggerx.apikeys
+			SET replaced_by = ?, expires_at = ?
 			WHERE key = ?`
 
 	// Delete Queries
@@ -31,28 +43,24 @@ const (
 
 // Read Queries
 const (
-	GetApiKeyDataByOwnerQuery = `
-			SELECT key, owner, is_active, rate_limit, success_count, failed_count, last_used, created_at 
-			FROM triggerx.apikeys 
-			WHERE owner = ? ALLOW FILTERING`
+	// The keys come from apikeys_by_owner
+	GetApiKeyDataByApiKeysQuery = `
+			SELECT key, owner, is_active, rate_limit, tier, key_hash, key_salt, scopes, expires_at, allowed_ips, replaced_by, success_count, failed_count, last_used, last_used_ip, created_at
+			FROM triggerx.apikeys
+			WHERE key IN ?`
 
 	GetApiKeyDataByApiKeyQuery = `
-			SELECT key, owner, is_active, rate_limit, success_count, failed_count, last_used, created_at 
-			FROM triggerx.apikeys 
-			WHERE key = ? ALLOW FILTERING`
+			SELECT key, owner, is_active, rate_limit, tier, key_hash, key_salt, scopes, expires_at, allowed_ips, replaced_by, success_count, failed_count, last_used, last_used_ip, created_at
+			FROM triggerx.apikeys
+			WHERE key = ?`
 
 	GetApiKeyCallCountQuery = `
-			SELECT success_count, failed_count 
-			FROM triggerx.apikeys 
-			WHERE key = ? ALLOW FILTERING`
-
-	GetApiKeyByOwnerQuery = `
-			SELECT key
-			FROM triggerx.apikeys 
-			WHERE owner = ? ALLOW FILTERING`
+			SELECT success_count, failed_count
+			FROM triggerx.apikeys
+			WHERE key = ?`
 
 	GetApiOwnerByApiKeyQuery = `
 			SELECT owner
-			FROM triggerx.apikeys 
-			WHERE key = ? ALLOW FILTERING`
+			FROM triggerx.apikeys
+			WHERE key = ?`
 )
@@ -29,7 +29,7 @@ const (
 			expiration_time, script_language, script_hash, next_execution_time,
 			max_execution_time, challenge_period
 		FROM triggerx.custom_jobs
-		WHERE is_active = ? ALLOW FILTERING`
+		WHERE is_active = ?`
 
 	GetActiveCustomJobsQuery = `
 		SELECT job_id, task_definition_id, recurring, custom_script_url, time_interval,
@@ -37,7 +37,7 @@ const (
 			expiration_time, script_language, script_hash, next_execution_time,
 			max_execution_time, challenge_period
 		FROM triggerx.custom_jobs
-		WHERE is_active = ? ALLOW FILTERING`
+		WHERE is_active = ?`
 )
 
 // Update Queries
@@ -92,7 +92,7 @@ const (
 			tx_hash, execution_status, execution_error, verification_status,
 			challenge_deadline, is_challenged, challenge_count, created_at
 		FROM triggerx.custom_script_executions
-		WHERE job_id = ? ALLOW FILTERING`
+		WHERE job_id = ?`
 
 	GetExecutionsByTaskIDQuery = `
 		SELECT execution_id, job_id, task_id, scheduled_time, actual_time, performer_address,
@@ -101,7 +101,7 @@ const (
 			tx_hash, execution_status, execution_error, verification_status,
 			challenge_deadline, is_challenged, challenge_count, created_at
 		FROM triggerx.custom_script_executions
-		WHERE task_id = ? ALLOW FILTERING`
+		WHERE task_id = ?`
 
 	UpdateExecutionTxHashQuery = `
 		UPDATE triggerx.custom_script_executions
@@ -160,7 +160,7 @@ const (
 			challenger_calldata, challenger_signature, resolution_status,
 			resolution_time, validator_count, approve_count, reject_count, created_at
 		FROM triggerx.execution_challenges
-		WHERE execution_id = ? ALLOW FILTERING`
+		WHERE execution_id = ?`
 
 	UpdateChallengeResolutionQuery = `
 		UPDATE triggerx.execution_challenges
@@ -11,6 +11,17 @@ const (
 				created_at, updated_at, timezone, is_imua, created_chain_id, safe_address
 			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	// 17 values to be inserted, so 17 ?s
+
+	// A job ID is claimed with a stub row before the job is written, so two creates of one ID
+	// cannot both write it
+	ClaimJobIDQuery = `
+			INSERT INTO triggerx.job_data (job_id, user_id, created_at)
+			VALUES (?, ?, ?) IF NOT EXISTS`
+
+	// Drops a claim whose job was not written, a written job has a status and is kept
+	ReleaseJobIDQuery = `
+			DELETE FROM triggerx.job_data
+			WHERE job_id = ? IF status = null`
 )
 
 // Write Queries
@@ -31,6 +42,44 @@ const (
 			SET status = ?, updated_at = ?
 			WHERE job_id = ?`
 
+	// Move an active job between active statuses if it is still the status it was read with,
+	// keeping any scheduled pause
+	UpdateActiveJobStatusQuery = `
+			UPDATE triggerx.job_data
+			SET status = ?, updated_at = ?
+			WHERE job_id = ?
+			IF status = ?`
+
+	// Change the status of a job if it is still the status it was read with, clearing any
+	// scheduled pause
+	TransitionJobStatusQuery = `
+			UPDATE triggerx.job_data
+			SET status = ?, paused_at = ?, pause_at = null, updated_at = ?
+			WHERE job_id = ?
+			IF status = ?`
+
+	// Put back the lifecycle a job had before a transition whose side effects failed
+	RestoreJobLifecycleQuery = `
+			UPDATE triggerx.job_data
+			SET status = ?, paused_at = ?, pause_at = ?, updated_at = ?
+			WHERE job_id = ?
+			IF status = ?`
+
+	// Set or clear the scheduled pause of a job if its status is still the one it was read with
+	UpdateJobPauseAtQuery = `
+			UPDATE triggerx.job_data
+			SET pause_at = ?, updated_at = ?
+			WHERE job_id = ?
+			IF status = ?`
+
+	CreateScheduledPauseQuery = `
+			INSERT INTO triggerx.scheduled_job_pauses (bucket, pause_at, job_id)
+			VALUES (0, ?, ?)`
+
+	DeleteScheduledPauseQuery = `
+			DELETE FROM triggerx.scheduled_job_pauses
+			WHERE bucket = 0 AND pause_at = ? AND job_id = ?`
+
 	UpdateTimeJobIntervalQuery = `
 		UPDATE triggerx.time_job_data
 		SET time_interval = ?
@@ -54,23 +103,25 @@ const (
 			SELECT task_ids FROM triggerx.job_data 
 			WHERE job_id = ?`
 
-	// New query to get task_id and fee for all tasks of a job
-	GetTaskFeesByJobIDQuery = `
+	GetJobLifecycleQuery = `
+			SELECT status, paused_at, pause_at FROM triggerx.job_data
+			WHERE job_id = ?`
+
+	// Get the scheduled pauses due at a time, oldest first
+	GetDueScheduledPausesQuery = `
+			SELECT job_id, pause_at FROM triggerx.scheduled_job_pauses
+			WHERE bucket = 0 AND pause_at <= ?`
+
+	// Get task_id and fee of the tasks of a job, the task IDs come from tasks_by_job
+	GetTaskFeesByTaskIDsQuery = `
 			SELECT task_id, task_opx_cost FROM triggerx.task_data
-			WHERE job_id = ? ALLOW FILTERING`
+			WHERE task_id IN ?`
 
-	// New query: get jobs for a user and a specific created_chain_id
-	GetJobsByUserIDAndChainIDQuery = `
+	// Get jobs by ID, the job IDs come from jobs_by_user or jobs_by_safe_address
+	GetJobsByJobIDsQuery = `
 			SELECT job_id, job_title, task_definition_id, user_id, link_job_id, chain_status,
 				custom, time_frame, recurring, status, job_cost_prediction, job_cost_actual,
 				task_ids, created_at, updated_at, last_executed_at, timezone, is_imua, created_chain_id, safe_address
 			FROM triggerx.job_data
-			WHERE user_id = ? AND created_chain_id = ? ALLOW FILTERING`
+			WHERE job_id IN ?`
 )
-
-const GetJobsBySafeAddressQuery = `
-	SELECT job_id, job_title, task_definition_id, user_id, link_job_id, chain_status,
-		custom, time_frame, recurring, status, job_cost_prediction, job_cost_actual,
-		task_ids, created_at, updated_at, last_executed_at, timezone, is_imua, created_chain_id, safe_address
-	FROM triggerx.job_data
-	WHERE safe_address = ? ALLOW FILTERING`
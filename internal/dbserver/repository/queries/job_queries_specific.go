@@ -109,25 +109,25 @@ const (
 			FROM triggerx.condition_job_data
 			WHERE job_id = ?`
 
-	GetTimeJobsByNextExecutionTimestampQuery = `
+	// The job IDs come from time_jobs_by_minute, the repository checks the window and is_active
+	GetTimeJobsDueByJobIDsQuery = `
 			SELECT job_id, last_executed_at, expiration_time, time_interval,
 				schedule_type, cron_expression, specific_schedule, next_execution_timestamp,
 				target_chain_id, target_contract_address, target_function, 
-				abi, arg_type, arguments, dynamic_arguments_script_url
+				abi, arg_type, arguments, dynamic_arguments_script_url, is_active
 			FROM triggerx.time_job_data
-			WHERE next_execution_timestamp >= ? AND next_execution_timestamp <= ? AND is_active = true
-			ALLOW FILTERING`
+			WHERE job_id IN ?`
 
-	GetActiveEventJobsQuery string = `
+	// The job IDs of the Get*JobsByJobIDs queries come from active_jobs_by_type
+	GetEventJobsByJobIDsQuery string = `
 			SELECT job_id, expiration_time, recurring,
 				trigger_chain_id, trigger_contract_address, trigger_event, event_filter_para_name, event_filter_value,
 				target_chain_id, target_contract_address, target_function,
 				abi, arg_type, arguments, dynamic_arguments_script_url,
 				is_completed, is_active
 			FROM triggerx.event_job_data
-			WHERE is_active = true
-			ALLOW FILTERING`
-	GetActiveConditionJobsQuery string = `
+			WHERE job_id IN ?`
+	GetConditionJobsByJobIDsQuery string = `
 			SELECT job_id, expiration_time, recurring,
 				condition_type, upper_limit, lower_limit,
 				value_source_type, value_source_url,
@@ -135,15 +135,12 @@ const (
 				abi, arg_type, arguments, dynamic_arguments_script_url,
 				is_completed, is_active, selected_key_route
 			FROM triggerx.condition_job_data
-			WHERE is_active = true
-			ALLOW FILTERING`
-	// NEW: GetActiveTimeJobsQuery
-	GetActiveTimeJobsQuery string = `
+			WHERE job_id IN ?`
+	GetTimeJobsByJobIDsQuery string = `
 			SELECT job_id, expiration_time, next_execution_timestamp, schedule_type,
 				time_interval, cron_expression, specific_schedule, timezone,
 				target_chain_id, target_contract_address, target_function, abi, arg_type,
 				arguments, dynamic_arguments_script_url, is_completed, is_active
 			FROM triggerx.time_job_data
-			WHERE is_active = true
-			ALLOW FILTERING`
+			WHERE job_id IN ?`
 )
@@ -72,45 +72,29 @@ const (
 		FROM triggerx.keeper_data 
 		WHERE keeper_id = ?`
 
-	GetKeeperIDByAddressQuery = `
-		SELECT keeper_id
-		FROM triggerx.keeper_data 
-		WHERE keeper_address = ? ALLOW FILTERING`
-
-	GetKeeperIDByOperatorIDQuery = `
-		SELECT keeper_id
-		FROM triggerx.keeper_data 
-		WHERE operator_id = ? ALLOW FILTERING`
-
-	GetKeeperIDByNameQuery = `
-		SELECT keeper_id
-		FROM triggerx.keeper_data 
-		WHERE keeper_name = ? ALLOW FILTERING`
-
+	// Reads keeper_data_registered_idx, the repository skips keepers not whitelisted
 	GetKeeperLeaderboardQuery = `
-		SELECT keeper_id, keeper_address, keeper_name, no_executed_tasks, no_attested_tasks, keeper_points, on_imua
+		SELECT keeper_id, keeper_address, keeper_name, no_executed_tasks, no_attested_tasks, keeper_points, on_imua, whitelisted
 		FROM triggerx.keeper_data 
-		WHERE registered = true AND whitelisted = true ALLOW FILTERING`
+		WHERE registered = true`
 
-	GetKeeperLeaderboardByAddressQuery = `
-		SELECT keeper_id, keeper_address, keeper_name, no_executed_tasks, no_attested_tasks, keeper_points 
+	// The keeper ID comes from keepers_by_address
+	GetKeeperLeaderboardByIDQuery = `
+		SELECT keeper_id, keeper_address, keeper_name, no_executed_tasks, no_attested_tasks, keeper_points, registered
 		FROM triggerx.keeper_data 
-		WHERE registered = true AND keeper_address = ? ALLOW FILTERING`
+		WHERE keeper_id = ?`
 
+	// Reads keeper_data_keeper_name_idx, the repository skips keepers not registered
 	GetKeeperLeaderboardByNameQuery = `
-		SELECT keeper_id, keeper_address, keeper_name, no_executed_tasks, no_attested_tasks, keeper_points 
-		FROM triggerx.keeper_data 
-		WHERE registered = true AND keeper_name = ? ALLOW FILTERING`
-
-	GetKeeperLeaderboardByOnImuaQuery = `
-		SELECT keeper_id, keeper_address, keeper_name, no_executed_tasks, no_attested_tasks, keeper_points, on_imua
+		SELECT keeper_id, keeper_address, keeper_name, no_executed_tasks, no_attested_tasks, keeper_points, registered
 		FROM triggerx.keeper_data 
-		WHERE registered = true AND whitelisted = true AND on_imua = ? ALLOW FILTERING`
+		WHERE keeper_name = ?`
 
+	// Reads keeper_data_registered_idx, the repository skips keepers not whitelisted
 	GetKeeperAsPerformersQuery = `
-		SELECT keeper_id, keeper_address 
+		SELECT keeper_id, keeper_address, whitelisted
 		FROM triggerx.keeper_data 
-		WHERE whitelisted = true AND registered = true ALLOW FILTERING`
+		WHERE registered = true`
 
 	GetKeeperTaskCountByIDQuery = `
 		SELECT no_executed_tasks FROM triggerx.keeper_data WHERE keeper_id = ?`
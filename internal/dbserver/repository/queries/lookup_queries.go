@@ -0,0 +1,119 @@
+package queries
+
+// Lookup tables, written along with the base tables so reads never filter a base table
+
+// Create Queries
+const (
+	CreateJobByUserQuery = `
+			INSERT INTO triggerx.jobs_by_user (user_id, created_chain_id, created_at, job_id)
+			VALUES (?, ?, ?, ?)`
+
+	CreateJobBySafeAddressQuery = `
+			INSERT INTO triggerx.jobs_by_safe_address (safe_address, created_at, job_id)
+			VALUES (?, ?, ?)`
+
+	CreateTaskByJobQuery = `
+			INSERT INTO triggerx.tasks_by_job (job_id, created_at, task_id)
+			VALUES (?, ?, ?)`
+
+	CreateTaskByDayQuery = `
+			INSERT INTO triggerx.tasks_by_day (day, created_at, task_id)
+			VALUES (?, ?, ?)`
+
+	// Claims an address for a user, only applied when the address is not taken
+	ClaimUserAddressQuery = `
+			INSERT INTO triggerx.users_by_address (user_address, user_id)
+			VALUES (?, ?) IF NOT EXISTS`
+
+	// Claims an address for a keeper, only applied when the address is not taken
+	ClaimKeeperAddressQuery = `
+			INSERT INTO triggerx.keepers_by_address (keeper_address, keeper_id)
+			VALUES (?, ?) IF NOT EXISTS`
+
+	CreateApiKeyByOwnerQuery = `
+			INSERT INTO triggerx.apikeys_by_owner (owner, key, created_at)
+			VALUES (?, ?, ?)`
+
+	CreateActiveJobByTypeQuery = `
+			INSERT INTO triggerx.active_jobs_by_type (job_type, job_id)
+			VALUES (?, ?)`
+
+	// Rows expire after their minute has passed, readers check the time job row
+	CreateTimeJobByMinuteQuery = `
+			INSERT INTO triggerx.time_jobs_by_minute (minute, next_execution_timestamp, job_id)
+			VALUES (?, ?, ?) USING TTL ?`
+)
+
+// Delete Queries
+const (
+	// Releases a claim whose base row could not be written
+	ReleaseUserAddressQuery = `
+			DELETE FROM triggerx.users_by_address
+			WHERE user_address = ? IF user_id = ?`
+
+	ReleaseKeeperAddressQuery = `
+			DELETE FROM triggerx.keepers_by_address
+			WHERE keeper_address = ? IF keeper_id = ?`
+
+	DeleteApiKeyByOwnerQuery = `
+			DELETE FROM triggerx.apikeys_by_owner
+			WHERE owner = ? AND key = ?`
+
+	DeleteActiveJobByTypeQuery = `
+			DELETE FROM triggerx.active_jobs_by_type
+			WHERE job_type = ? AND job_id = ?`
+)
+
+// Read Queries
+const (
+	GetJobIDsByUserIDAndChainIDQuery = `
+			SELECT job_id FROM triggerx.jobs_by_user
+			WHERE user_id = ? AND created_chain_id = ?`
+
+	GetJobIDsBySafeAddressQuery = `
+			SELECT job_id FROM triggerx.jobs_by_safe_address
+			WHERE safe_address = ?`
+
+	GetTaskIDsByJobQuery = `
+			SELECT task_id FROM triggerx.tasks_by_job
+			WHERE job_id = ?`
+
+	GetUserIDByAddressLookupQuery = `
+			SELECT user_id FROM triggerx.users_by_address
+			WHERE user_address = ?`
+
+	GetKeeperIDByAddressLookupQuery = `
+			SELECT keeper_id FROM triggerx.keepers_by_address
+			WHERE keeper_address = ?`
+
+	GetApiKeysByOwnerQuery = `
+			SELECT key FROM triggerx.apikeys_by_owner
+			WHERE owner = ?`
+
+	GetActiveJobIDsByTypeQuery = `
+			SELECT job_id FROM triggerx.active_jobs_by_type
+			WHERE job_type = ?`
+
+	GetTimeJobIDsByMinutesQuery = `
+			SELECT job_id FROM triggerx.time_jobs_by_minute
+			WHERE minute IN ? AND next_execution_timestamp >= ? AND next_execution_timestamp <= ?`
+)
+
+// List Queries, paged and extended with range and ORDER BY clauses by the repositories
+const (
+	ListJobsByUserQuery = `
+			SELECT created_chain_id, created_at, job_id FROM triggerx.jobs_by_user
+			WHERE user_id = ?`
+
+	ListJobsByUserAndChainQuery = `
+			SELECT created_chain_id, created_at, job_id FROM triggerx.jobs_by_user
+			WHERE user_id = ? AND created_chain_id = ?`
+
+	ListTasksByJobQuery = `
+			SELECT task_id FROM triggerx.tasks_by_job
+			WHERE job_id = ?`
+
+	ListTasksByDayQuery = `
+			SELECT task_id FROM triggerx.tasks_by_day
+			WHERE day = ?`
+)
@@ -55,11 +55,12 @@ const (
         FROM triggerx.task_data
         WHERE task_id = ?`
 
-	GetTasksByJobIDQuery = `
+	// The task IDs come from tasks_by_job
+	GetTasksByTaskIDsQuery = `
 		SELECT task_id, task_number, task_opx_cost, execution_timestamp, execution_tx_hash, task_performer_id, 
 			   task_attester_ids, is_accepted, task_status, task_error, converted_arguments
 		FROM triggerx.task_data
-		WHERE job_id = ? ALLOW FILTERING`
+		WHERE task_id IN ?`
 
 	GetTaskFeeQuery = `
 		SELECT task_opx_cost
@@ -71,10 +72,10 @@ const (
         FROM triggerx.job_data
         WHERE job_id = ?`
 
-	GetRecentTasksQuery = `
+	GetRecentTasksByTaskIDsQuery = `
 		SELECT task_id, task_number, job_id, task_definition_id, created_at,
 		       task_opx_cost, execution_timestamp, execution_tx_hash, task_performer_id,
 		       task_attester_ids, task_status, task_error, is_imua
 		FROM triggerx.task_data
-		LIMIT ?`
+		WHERE task_id IN ?`
 )
@@ -32,11 +32,6 @@ const (
 			SET total_tasks = ?, user_points = ?
 			WHERE user_id = ?`
 
-	UpdateUserEmailByAddressQuery = `
-		UPDATE triggerx.user_data
-		SET email_id = ?
-		WHERE user_address = ? ALLOW FILTERING`
-
 	UpdateUserEmailByIDQuery = `
 		UPDATE triggerx.user_data
 		SET email_id = ?
@@ -45,11 +40,6 @@ const (
 
 // Read Queries
 const (
-	GetUserIDByAddressQuery = `
-			SELECT user_id
-			FROM triggerx.user_data 
-			WHERE user_address = ? ALLOW FILTERING`
-
 	// Get User Data by ID
 	GetUserDataByIDQuery = `
 			SELECT user_id, user_address, 
@@ -59,23 +49,23 @@ const (
 			FROM triggerx.user_data 
 			WHERE user_id = ?`
 
+	// Get User Address by ID for Job Ownership Checks
+	GetUserAddressByIDQuery = `
+			SELECT user_address
+			FROM triggerx.user_data 
+			WHERE user_id = ?`
+
 	// Get User Points by ID for Update after Task Execution
 	GetUserPointsByIDQuery = `
 			SELECT user_points 
 			FROM triggerx.user_data 
 			WHERE user_id = ?`
 
-	// Get User Points by Address for Frontend Display
-	GetUserPointsByAddressQuery = `
-			SELECT user_points
-			FROM triggerx.user_data 
-			WHERE user_address = ? ALLOW FILTERING`
-
-	// Get User Job IDs by Address for Frontend Display
-	GetUserJobIDsByAddressQuery = `
+	// Get User Job IDs by ID for Frontend Display
+	GetUserJobIDsByIDQuery = `
 			SELECT user_id, job_ids
 			FROM triggerx.user_data 
-			WHERE user_address = ? ALLOW FILTERING`
+			WHERE user_id = ?`
 
 	GetUserCountersByIDQuery = `
 			SELECT total_jobs, total_tasks
@@ -87,9 +77,9 @@ const (
 			SELECT user_id, user_address, total_jobs, total_tasks, user_points 
 			FROM triggerx.user_data`
 
-	// Get User Leaderboard by Address for Frontend Serach functionality
-	GetUserLeaderboardByAddressQuery = `
+	// Get User Leaderboard Entry by ID for Frontend Search functionality
+	GetUserLeaderboardByIDQuery = `
 			SELECT user_id, user_address, total_jobs, total_tasks, user_points 
 			FROM triggerx.user_data 
-			WHERE user_address = ? ALLOW FILTERING`
+			WHERE user_id = ?`
 )
@@ -0,0 +1,99 @@
+package queries
+
+// Create Queries
+const (
+	CreateWorkflowQuery = `
+			INSERT INTO triggerx.workflows (workflow_id, user_id, name, definition, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)`
+
+	CreateWorkflowByUserQuery = `
+			INSERT INTO triggerx.workflows_by_user (user_id, created_at, workflow_id)
+			VALUES (?, ?, ?)`
+
+	// Claims a job for a workflow, only applied when the job is in no workflow
+	ClaimWorkflowJobQuery = `
+			INSERT INTO triggerx.workflow_jobs (job_id, workflow_id)
+			VALUES (?, ?) IF NOT EXISTS`
+
+	CreateWorkflowRunQuery = `
+			INSERT INTO triggerx.workflow_runs (workflow_id, run_id, status, nodes, version, started_at, finished_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`
+)
+
+// Read Queries
+const (
+	GetWorkflowQuery = `
+			SELECT workflow_id, user_id, name, definition, active_run_id, created_at, updated_at
+			FROM triggerx.workflows
+			WHERE workflow_id = ?`
+
+	ListWorkflowsByUserQuery = `
+			SELECT workflow_id FROM triggerx.workflows_by_user
+			WHERE user_id = ?`
+
+	GetWorkflowIDByJobQuery = `
+			SELECT workflow_id FROM triggerx.workflow_jobs
+			WHERE job_id = ?`
+
+	GetWorkflowRunQuery = `
+			SELECT workflow_id, run_id, status, nodes, version, started_at, finished_at
+			FROM triggerx.workflow_runs
+			WHERE workflow_id = ? AND run_id = ?`
+
+	ListWorkflowRunsQuery = `
+			SELECT workflow_id, run_id, status, nodes, version, started_at, finished_at
+			FROM triggerx.workflow_runs
+			WHERE workflow_id = ?`
+)
+
+// Update Queries
+const (
+	UpdateWorkflowQuery = `
+			UPDATE triggerx.workflows
+			SET name = ?, definition = ?, updated_at = ?
+			WHERE workflow_id = ?
+			IF active_run_id = null`
+
+	// Claims the workflow for a run, only applied when no run is active
+	StartWorkflowRunQuery = `
+			UPDATE triggerx.workflows
+			SET active_run_id = ?
+			WHERE workflow_id = ?
+			IF active_run_id = null`
+
+	// Releases the workflow after its active run finished
+	FinishWorkflowRunQuery = `
+			UPDATE triggerx.workflows
+			SET active_run_id = null
+			WHERE workflow_id = ?
+			IF active_run_id = ?`
+
+	// Updates a run read with the given version
+	UpdateWorkflowRunQuery = `
+			UPDATE triggerx.workflow_runs
+			SET status = ?, nodes = ?, version = ?, finished_at = ?
+			WHERE workflow_id = ? AND run_id = ?
+			IF version = ?`
+)
+
+// Delete Queries
+const (
+	DeleteWorkflowQuery = `
+			DELETE FROM triggerx.workflows
+			WHERE workflow_id = ?
+			IF active_run_id = null`
+
+	DeleteWorkflowByUserQuery = `
+			DELETE FROM triggerx.workflows_by_user
+			WHERE user_id = ? AND created_at = ? AND workflow_id = ?`
+
+	// Releases a job claimed by the workflow
+	ReleaseWorkflowJobQuery = `
+			DELETE FROM triggerx.workflow_jobs
+			WHERE job_id = ?
+			IF workflow_id = ?`
+
+	DeleteWorkflowRunsQuery = `
+			DELETE FROM triggerx.workflow_runs
+			WHERE workflow_id = ?`
+)
@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/gocql/gocql"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/events"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository/queries"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
@@ -21,13 +22,24 @@ type TaskRepository interface {
 	UpdateTaskNumberAndStatus(taskID int64, taskNumber int64, status string, txHash string) error
 	GetTaskDataByID(taskID int64) (commonTypes.TaskData, error)
 	GetTasksByJobID(jobID *big.Int) ([]types.GetTasksByJobID, error)
+	ListTasksByJobID(jobID *big.Int, opts types.ListOptions) (types.Page[types.GetTasksByJobID], error)
 	AddTaskIDToJob(jobID *big.Int, taskID int64) error
 	UpdateTaskFee(taskID int64, fee float64) error
 	GetTaskFee(taskID int64) (float64, error)
 	GetCreatedChainIDByJobID(jobID *big.Int) (string, error)
-	GetRecentTasks(limit int) ([]types.RecentTaskResponse, error)
+	ListRecentTasks(opts types.ListOptions) (types.Page[types.RecentTaskResponse], error)
 }
 
+const (
+	// recentTasksWindow is how far back recent tasks are listed when no range is given
+	recentTasksWindow = 30 * 24 * time.Hour
+	// maxRecentTasksRange bounds the days a recent tasks list walks
+	maxRecentTasksRange = 366 * 24 * time.Hour
+)
+
+// ErrRangeTooLong is returned when a list's date range spans more days than it walks
+var ErrRangeTooLong = errors.New("date range too long")
+
 type taskRepository struct {
 	db        *database.Connection
 	publisher *events.Publisher
@@ -55,8 +67,12 @@ func (r *taskRepository) CreateTaskDataInDB(task *types.CreateTaskDataRequest) (
 	}
 
 	taskID := maxTaskID + 1
-	err = r.db.Session().Query(queries.CreateTaskDataQuery, taskID, task.JobID, task.TaskDefinitionID, time.Now(), task.IsImua).Exec()
-	if err != nil {
+	createdAt := time.Now()
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.CreateTaskDataQuery, taskID, task.JobID, task.TaskDefinitionID, createdAt, task.IsImua)
+	batch.Query(queries.CreateTaskByJobQuery, task.JobID, createdAt, taskID)
+	batch.Query(queries.CreateTaskByDayQuery, taskDay(createdAt), createdAt, taskID)
+	if err := r.db.Session().ExecuteBatch(batch); err != nil {
 		return -1, errors.New("error creating task data")
 	}
 
@@ -173,34 +189,108 @@ func (r *taskRepository) GetTaskDataByID(taskID int64) (commonTypes.TaskData, er
 	return task, nil
 }
 
+// GetTasksByJobID returns the tasks of a job, newest first
 func (r *taskRepository) GetTasksByJobID(jobID *big.Int) ([]types.GetTasksByJobID, error) {
-	iter := r.db.Session().Query(queries.GetTasksByJobIDQuery, jobID).Iter()
-	var tasks []types.GetTasksByJobID
-	var task types.GetTasksByJobID
-
-	for iter.Scan(
-		&task.TaskID,
-		&task.TaskNumber,
-		&task.TaskOpXCost,
-		&task.ExecutionTimestamp,
-		&task.ExecutionTxHash,
-		&task.TaskPerformerID,
-		&task.TaskAttesterIDs,
-		&task.IsAccepted,
-		&task.TaskStatus,
-		&task.TaskError,
-		&task.ConvertedArguments,
-	) {
-		tasks = append(tasks, task)
+	taskIDs, err := lookupTaskIDs(r.db.Session(), jobID)
+	if err != nil {
+		return []types.GetTasksByJobID{}, errors.New("error getting tasks by job ID: " + err.Error())
 	}
 
-	if err := iter.Close(); err != nil {
+	tasks, err := r.getTasksByIDs(taskIDs)
+	if err != nil {
 		return []types.GetTasksByJobID{}, errors.New("error getting tasks by job ID: " + err.Error())
 	}
+	return tasks, nil
+}
+
+// ListTasksByJobID returns a page of the tasks of a job, filtered by status and creation time
+func (r *taskRepository) ListTasksByJobID(jobID *big.Int, opts types.ListOptions) (types.Page[types.GetTasksByJobID], error) {
+	scope := listScope("tasks_by_job", jobID.String(), opts)
+	cursor, err := decodeCursor(opts.Cursor, scope)
+	if err != nil {
+		return types.Page[types.GetTasksByJobID]{}, err
+	}
+
+	stmt, values := timeRange(opts, "created_at", queries.ListTasksByJobQuery, []interface{}{jobID})
+	stmt += orderBy(opts, "created_at DESC, task_id DESC", "created_at ASC, task_id ASC")
+
+	tasks, next, err := collectPages(
+		func(state []byte, size int) ([]int64, []byte, error) {
+			return readPage(r.db.Session(), stmt, values, state, size, scanID[int64])
+		},
+		func(taskIDs []int64) ([]types.GetTasksByJobID, error) {
+			tasks, err := r.getTasksByIDs(taskIDs)
+			if err != nil || opts.Status == "" {
+				return tasks, err
+			}
+			var matching []types.GetTasksByJobID
+			for _, task := range tasks {
+				if task.TaskStatus == opts.Status {
+					matching = append(matching, task)
+				}
+			}
+			return matching, nil
+		},
+		cursor.State, opts.Limit)
+	if err != nil {
+		return types.Page[types.GetTasksByJobID]{}, fmt.Errorf("error listing tasks by job ID: %w", err)
+	}
+	return newPage(tasks, scope, "", next), nil
+}
+
+// getTasksByIDs reads the tasks in the order of taskIDs
+func (r *taskRepository) getTasksByIDs(taskIDs []int64) ([]types.GetTasksByJobID, error) {
+	byID := make(map[int64]types.GetTasksByJobID, len(taskIDs))
+	for _, ids := range chunk(taskIDs) {
+		iter := r.db.Session().Query(queries.GetTasksByTaskIDsQuery, ids).Iter()
+		var task types.GetTasksByJobID
+
+		for iter.Scan(
+			&task.TaskID,
+			&task.TaskNumber,
+			&task.TaskOpXCost,
+			&task.ExecutionTimestamp,
+			&task.ExecutionTxHash,
+			&task.TaskPerformerID,
+			&task.TaskAttesterIDs,
+			&task.IsAccepted,
+			&task.TaskStatus,
+			&task.TaskError,
+			&task.ConvertedArguments,
+		) {
+			byID[task.TaskID] = task
+		}
+
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+	}
 
+	var tasks []types.GetTasksByJobID
+	for _, taskID := range taskIDs {
+		if task, ok := byID[taskID]; ok {
+			tasks = append(tasks, task)
+		}
+	}
 	return tasks, nil
 }
 
+// lookupTaskIDs reads the IDs of the tasks of a job from tasks_by_job, newest first
+func lookupTaskIDs(session database.Sessioner, jobID *big.Int) ([]int64, error) {
+	iter := session.Query(queries.GetTaskIDsByJobQuery, jobID).Iter()
+
+	var taskIDs []int64
+	var taskID int64
+	for iter.Scan(&taskID) {
+		taskIDs = append(taskIDs, taskID)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return taskIDs, nil
+}
+
 func (r *taskRepository) AddTaskIDToJob(jobID *big.Int, taskID int64) error {
 	var taskIDs []int64
 	iter := r.db.Session().Query(queries.GetTaskIDsByJobIDQuery, jobID).Iter()
@@ -256,55 +346,153 @@ func (r *taskRepository) GetCreatedChainIDByJobID(jobID *big.Int) (string, error
 	return createdChainID, nil
 }
 
-func (r *taskRepository) GetRecentTasks(limit int) ([]types.RecentTaskResponse, error) {
-	iter := r.db.Session().Query(queries.GetRecentTasksQuery, limit).Iter()
-	var tasks []types.RecentTaskResponse
+// ListRecentTasks returns a page of the tasks created between opts.From and opts.To, the last
+// 30 days when no range is given, walking tasks_by_day a day at a time. Tasks are filtered by
+// status, task definition and the chain their job was created on.
+func (r *taskRepository) ListRecentTasks(opts types.ListOptions) (types.Page[types.RecentTaskResponse], error) {
+	to := opts.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := opts.From
+	if from.IsZero() {
+		from = to.Add(-recentTasksWindow)
+	}
+	if to.Sub(from) > maxRecentTasksRange {
+		return types.Page[types.RecentTaskResponse]{}, ErrRangeTooLong
+	}
+
+	scope := listScope("tasks_by_day", "", opts)
+	cursor, err := decodeCursor(opts.Cursor, scope)
+	if err != nil {
+		return types.Page[types.RecentTaskResponse]{}, err
+	}
+
+	first, last, step := taskDay(to), taskDay(from), -1
+	if opts.Order == types.SortAsc {
+		first, last, step = last, first, 1
+	}
+	day := first
+	if cursor.Bucket != "" {
+		if day, err = time.Parse(time.DateOnly, cursor.Bucket); err != nil {
+			return types.Page[types.RecentTaskResponse]{}, ErrInvalidCursor
+		}
+	}
+
+	stmt, values := timeRange(opts, "created_at", queries.ListTasksByDayQuery, []interface{}{nil})
+	stmt += orderBy(opts, "created_at DESC, task_id DESC", "created_at ASC, task_id ASC")
 
+	chainIDs := make(map[string]string)
+	var tasks []types.RecentTaskResponse
+	state := cursor.State
 	for {
-		var task types.RecentTaskResponse
-		var jobIDBigInt *big.Int
+		values[0] = day
+		items, next, err := collectPages(
+			func(state []byte, size int) ([]int64, []byte, error) {
+				return readPage(r.db.Session(), stmt, values, state, size, scanID[int64])
+			},
+			func(taskIDs []int64) ([]types.RecentTaskResponse, error) {
+				return r.getRecentTasksByIDs(taskIDs, opts, chainIDs)
+			},
+			state, opts.Limit-len(tasks))
+		if err != nil {
+			return types.Page[types.RecentTaskResponse]{}, fmt.Errorf("error listing recent tasks: %w", err)
+		}
+		tasks = append(tasks, items...)
+		if next != nil {
+			return newPage(tasks, scope, day.Format(time.DateOnly), next), nil
+		}
 
-		if !iter.Scan(
-			&task.TaskID,
-			&task.TaskNumber,
-			&jobIDBigInt,
-			&task.TaskDefinitionID,
-			&task.CreatedAt,
-			&task.TaskOpXCost,
-			&task.ExecutionTimestamp,
-			&task.ExecutionTxHash,
-			&task.TaskPerformerID,
-			&task.TaskAttesterIDs,
-			&task.TaskStatus,
-			&task.TaskError,
-			&task.IsImua,
-		) {
-			break
+		if (step < 0 && !day.After(last)) || (step > 0 && !day.Before(last)) {
+			return newPage(tasks, scope, "", nil), nil
+		}
+		day = day.AddDate(0, 0, step)
+		state = nil
+		if len(tasks) >= opts.Limit {
+			return newPage(tasks, scope, day.Format(time.DateOnly), nil), nil
 		}
+	}
+}
 
-		// Convert job ID to string
-		if jobIDBigInt != nil {
-			task.JobID = jobIDBigInt.String()
+// getRecentTasksByIDs reads the tasks in the order of taskIDs that pass the filters of opts,
+// with the explorer URL of their transaction. chainIDs caches the chain of each job.
+func (r *taskRepository) getRecentTasksByIDs(taskIDs []int64, opts types.ListOptions, chainIDs map[string]string) ([]types.RecentTaskResponse, error) {
+	byID := make(map[int64]types.RecentTaskResponse, len(taskIDs))
+	for _, ids := range chunk(taskIDs) {
+		iter := r.db.Session().Query(queries.GetRecentTasksByTaskIDsQuery, ids).Iter()
+		for {
+			var task types.RecentTaskResponse
+			var jobIDBigInt *big.Int
+
+			if !iter.Scan(
+				&task.TaskID,
+				&task.TaskNumber,
+				&jobIDBigInt,
+				&task.TaskDefinitionID,
+				&task.CreatedAt,
+				&task.TaskOpXCost,
+				&task.ExecutionTimestamp,
+				&task.ExecutionTxHash,
+				&task.TaskPerformerID,
+				&task.TaskAttesterIDs,
+				&task.TaskStatus,
+				&task.TaskError,
+				&task.IsImua,
+			) {
+				break
+			}
+			if opts.Status != "" && task.TaskStatus != opts.Status {
+				continue
+			}
+			if opts.TaskDefinitionID != 0 && task.TaskDefinitionID != opts.TaskDefinitionID {
+				continue
+			}
 
-			// Get chain ID and generate TxURL if execution tx hash exists
-			if task.ExecutionTxHash != "" {
-				createdChainID, err := r.GetCreatedChainIDByJobID(jobIDBigInt)
-				if err == nil {
-					task.TxURL = getExplorerBaseURL(createdChainID) + task.ExecutionTxHash
+			// Convert job ID to string
+			if jobIDBigInt != nil {
+				task.JobID = jobIDBigInt.String()
+
+				// Get chain ID and generate TxURL if execution tx hash exists
+				if task.ExecutionTxHash != "" || opts.ChainID != "" {
+					createdChainID, ok := chainIDs[task.JobID]
+					if !ok {
+						createdChainID, _ = r.GetCreatedChainIDByJobID(jobIDBigInt)
+						chainIDs[task.JobID] = createdChainID
+					}
+					if opts.ChainID != "" && createdChainID != opts.ChainID {
+						continue
+					}
+					if task.ExecutionTxHash != "" && createdChainID != "" {
+						task.TxURL = getExplorerBaseURL(createdChainID) + task.ExecutionTxHash
+					}
 				}
+			} else if opts.ChainID != "" {
+				continue
 			}
+
+			byID[task.TaskID] = task
 		}
 
-		tasks = append(tasks, task)
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := iter.Close(); err != nil {
-		return []types.RecentTaskResponse{}, errors.New("error getting recent tasks: " + err.Error())
+	var tasks []types.RecentTaskResponse
+	for _, taskID := range taskIDs {
+		if task, ok := byID[taskID]; ok {
+			tasks = append(tasks, task)
+		}
 	}
-
 	return tasks, nil
 }
 
+// taskDay returns the UTC day of t, the partition of tasks_by_day
+func taskDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
 // getExplorerBaseURL is a helper to get explorer URL - mirrors the one in handlers
 func getExplorerBaseURL(chainID string) string {
 	switch chainID {
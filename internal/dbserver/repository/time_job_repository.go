@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/gocql/gocql"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository/queries"
 	"github.com/trigg3rX/triggerx-backend/pkg/database"
 	"github.com/trigg3rX/triggerx-backend/pkg/parser"
@@ -34,12 +35,17 @@ func NewTimeJobRepository(db *database.Connection) TimeJobRepository {
 }
 
 func (r *timeJobRepository) CreateTimeJob(timeJob *commonTypes.TimeJobData) error {
-	err := r.db.Session().Query(queries.CreateTimeJobDataQuery,
-		timeJob.JobID.ToBigInt(), timeJob.TaskDefinitionID, timeJob.ExpirationTime, timeJob.NextExecutionTimestamp,
+	jobID := timeJob.JobID.ToBigInt()
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.CreateTimeJobDataQuery,
+		jobID, timeJob.TaskDefinitionID, timeJob.ExpirationTime, timeJob.NextExecutionTimestamp,
 		timeJob.ScheduleType, timeJob.TimeInterval, timeJob.CronExpression, timeJob.SpecificSchedule,
 		timeJob.Timezone, timeJob.TargetChainID, timeJob.TargetContractAddress, timeJob.TargetFunction,
 		timeJob.ABI, timeJob.ArgType, timeJob.Arguments, timeJob.DynamicArgumentsScriptUrl,
-		timeJob.IsCompleted, timeJob.IsActive, time.Now(), time.Now()).Exec()
+		timeJob.IsCompleted, timeJob.IsActive, time.Now(), time.Now())
+	setJobActive(batch, timeJobType, jobID, timeJob.IsActive)
+	scheduleTimeJob(batch, jobID, timeJob.NextExecutionTimestamp)
+	err := r.db.Session().ExecuteBatch(batch)
 
 	if err != nil {
 		return err
@@ -79,7 +85,10 @@ func (r *timeJobRepository) CompleteTimeJob(jobID *big.Int) error {
 }
 
 func (r *timeJobRepository) UpdateTimeJobStatus(jobID *big.Int, isActive bool) error {
-	err := r.db.Session().Query(queries.UpdateTimeJobStatusQuery, isActive, jobID).Exec()
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.UpdateTimeJobStatusQuery, isActive, jobID)
+	setJobActive(batch, timeJobType, jobID, isActive)
+	err := r.db.Session().ExecuteBatch(batch)
 	if err != nil {
 		return errors.New("failed to update time job status")
 	}
@@ -89,18 +98,14 @@ func (r *timeJobRepository) UpdateTimeJobStatus(jobID *big.Int, isActive bool) e
 
 func (r *timeJobRepository) GetTimeJobsByNextExecutionTimestamp(lookAheadTime time.Time) ([]commonTypes.ScheduleTimeTaskData, error) {
 	currentTime := time.Now()
-	iter := r.db.Session().Query(queries.GetTimeJobsByNextExecutionTimestampQuery, currentTime, lookAheadTime).Iter()
+	due, err := r.getTimeJobsDue(currentTime, lookAheadTime)
+	if err != nil {
+		return nil, err
+	}
 
 	var timeJobs []commonTypes.ScheduleTimeTaskData
-	var timeJob commonTypes.ScheduleTimeTaskData
-	var jobIDBigInt *big.Int
-	for iter.Scan(
-		&jobIDBigInt, &timeJob.LastExecutedAt, &timeJob.ExpirationTime, &timeJob.TimeInterval,
-		&timeJob.ScheduleType, &timeJob.CronExpression, &timeJob.SpecificSchedule, &timeJob.NextExecutionTimestamp,
-		&timeJob.TaskTargetData.TargetChainID, &timeJob.TaskTargetData.TargetContractAddress, &timeJob.TaskTargetData.TargetFunction, &timeJob.TaskTargetData.ABI, &timeJob.TaskTargetData.ArgType,
-		&timeJob.TaskTargetData.Arguments, &timeJob.TaskTargetData.DynamicArgumentsScriptUrl,
-	) {
-		timeJob.TaskTargetData.JobID = commonTypes.NewBigInt(jobIDBigInt)
+	for _, timeJob := range due {
+		jobIDBigInt := timeJob.TaskTargetData.JobID.ToBigInt()
 		if timeJob.TaskTargetData.DynamicArgumentsScriptUrl != "" {
 			timeJob.TaskDefinitionID = 2
 			timeJob.TaskTargetData.TaskDefinitionID = 2
@@ -141,15 +146,63 @@ func (r *timeJobRepository) GetTimeJobsByNextExecutionTimestamp(lookAheadTime ti
 
 		timeJobs = append(timeJobs, timeJob)
 	}
-	if err := iter.Close(); err != nil {
-		return nil, err
+
+	return timeJobs, nil
+}
+
+// getTimeJobsDue reads the active time jobs whose next execution falls between from and to,
+// found through time_jobs_by_minute
+func (r *timeJobRepository) getTimeJobsDue(from, to time.Time) ([]commonTypes.ScheduleTimeTaskData, error) {
+	seen := make(map[string]bool)
+	var jobIDs []*big.Int
+	for _, minutes := range chunk(executionMinutes(from, to)) {
+		ids, err := lookupJobIDs(r.db.Session(), queries.GetTimeJobIDsByMinutesQuery, minutes, from, to)
+		if err != nil {
+			return nil, err
+		}
+		// A job rescheduled within the window has a row for each execution time
+		for _, id := range ids {
+			if !seen[id.String()] {
+				seen[id.String()] = true
+				jobIDs = append(jobIDs, id)
+			}
+		}
 	}
 
+	var timeJobs []commonTypes.ScheduleTimeTaskData
+	for _, ids := range chunk(jobIDs) {
+		iter := r.db.Session().Query(queries.GetTimeJobsDueByJobIDsQuery, ids).Iter()
+		for {
+			var timeJob commonTypes.ScheduleTimeTaskData
+			var jobIDBigInt *big.Int
+			var isActive bool
+			if !iter.Scan(
+				&jobIDBigInt, &timeJob.LastExecutedAt, &timeJob.ExpirationTime, &timeJob.TimeInterval,
+				&timeJob.ScheduleType, &timeJob.CronExpression, &timeJob.SpecificSchedule, &timeJob.NextExecutionTimestamp,
+				&timeJob.TaskTargetData.TargetChainID, &timeJob.TaskTargetData.TargetContractAddress, &timeJob.TaskTargetData.TargetFunction, &timeJob.TaskTargetData.ABI, &timeJob.TaskTargetData.ArgType,
+				&timeJob.TaskTargetData.Arguments, &timeJob.TaskTargetData.DynamicArgumentsScriptUrl, &isActive,
+			) {
+				break
+			}
+			// Rows of paused or rescheduled jobs stay in the lookup until they expire
+			if !isActive || timeJob.NextExecutionTimestamp.Before(from) || timeJob.NextExecutionTimestamp.After(to) {
+				continue
+			}
+			timeJob.TaskTargetData.JobID = commonTypes.NewBigInt(jobIDBigInt)
+			timeJobs = append(timeJobs, timeJob)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+	}
 	return timeJobs, nil
 }
 
 func (r *timeJobRepository) UpdateTimeJobNextExecutionTimestamp(jobID *big.Int, nextExecutionTimestamp time.Time) error {
-	err := r.db.Session().Query(queries.UpdateTimeJobNextExecutionTimestampQuery, nextExecutionTimestamp, jobID).Exec()
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.UpdateTimeJobNextExecutionTimestampQuery, nextExecutionTimestamp, jobID)
+	scheduleTimeJob(batch, jobID, nextExecutionTimestamp)
+	err := r.db.Session().ExecuteBatch(batch)
 	if err != nil {
 		return errors.New("failed to update time job next execution timestamp")
 	}
@@ -166,20 +219,30 @@ func (r *timeJobRepository) UpdateTimeJobInterval(jobID *big.Int, timeInterval i
 }
 
 func (r *timeJobRepository) GetActiveTimeJobs() ([]commonTypes.TimeJobData, error) {
-	var timeJobs []commonTypes.TimeJobData
-	iter := r.db.Session().Query(queries.GetActiveTimeJobsQuery).Iter()
-	var timeJob commonTypes.TimeJobData
-	var jobIDBigInt *big.Int
-	for iter.Scan(
-		&jobIDBigInt, &timeJob.ExpirationTime, &timeJob.NextExecutionTimestamp, &timeJob.ScheduleType,
-		&timeJob.TimeInterval, &timeJob.CronExpression, &timeJob.SpecificSchedule, &timeJob.Timezone,
-		&timeJob.TargetChainID, &timeJob.TargetContractAddress, &timeJob.TargetFunction, &timeJob.ABI, &timeJob.ArgType,
-		&timeJob.Arguments, &timeJob.DynamicArgumentsScriptUrl, &timeJob.IsCompleted, &timeJob.IsActive) {
-		timeJob.JobID = commonTypes.NewBigInt(jobIDBigInt)
-		timeJobs = append(timeJobs, timeJob)
-	}
-	if err := iter.Close(); err != nil {
+	jobIDs, err := lookupJobIDs(r.db.Session(), queries.GetActiveJobIDsByTypeQuery, timeJobType)
+	if err != nil {
 		return nil, err
 	}
+
+	var timeJobs []commonTypes.TimeJobData
+	for _, ids := range chunk(jobIDs) {
+		iter := r.db.Session().Query(queries.GetTimeJobsByJobIDsQuery, ids).Iter()
+		var timeJob commonTypes.TimeJobData
+		var jobIDBigInt *big.Int
+		for iter.Scan(
+			&jobIDBigInt, &timeJob.ExpirationTime, &timeJob.NextExecutionTimestamp, &timeJob.ScheduleType,
+			&timeJob.TimeInterval, &timeJob.CronExpression, &timeJob.SpecificSchedule, &timeJob.Timezone,
+			&timeJob.TargetChainID, &timeJob.TargetContractAddress, &timeJob.TargetFunction, &timeJob.ABI, &timeJob.ArgType,
+			&timeJob.Arguments, &timeJob.DynamicArgumentsScriptUrl, &timeJob.IsCompleted, &timeJob.IsActive) {
+			if !timeJob.IsActive {
+				continue
+			}
+			timeJob.JobID = commonTypes.NewBigInt(jobIDBigInt)
+			timeJobs = append(timeJobs, timeJob)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+	}
 	return timeJobs, nil
 }
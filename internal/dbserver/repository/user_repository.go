@@ -27,6 +27,7 @@ type UserRepository interface {
 	GetUserLeaderboardByAddress(address string) (types.UserLeaderboardEntry, error)
 	UpdateUserEmail(address string, email string) error
 	GetUserIDByAddress(address string) (int64, error)
+	GetUserAddressByID(id int64) (string, error)
 }
 
 type userRepository struct {
@@ -40,8 +41,7 @@ func NewUserRepository(db *database.Connection) UserRepository {
 }
 
 func (r *userRepository) CheckUserExists(address string) (int64, error) {
-	var userID int64
-	err := r.db.Session().Query(queries.GetUserIDByAddressQuery, address).Scan(&userID)
+	userID, err := lookupID(r.db.Session(), queries.GetUserIDByAddressLookupQuery, address)
 	if err == gocql.ErrNotFound {
 		return -1, errors.New("user not found")
 	}
@@ -57,8 +57,15 @@ func (r *userRepository) CreateNewUser(user *types.CreateUserDataRequest) (commo
 	if err != nil {
 		return commonTypes.UserData{}, err
 	}
+	// The address is claimed first, so concurrent registrations of an address create one user
+	if err := claimAddress(r.db.Session(), queries.ClaimUserAddressQuery, user.UserAddress, maxUserID+1); err != nil {
+		return commonTypes.UserData{}, err
+	}
 	err = r.db.Session().Query(queries.CreateUserDataQuery, maxUserID+1, user.UserAddress, user.EtherBalance.ToBigInt(), user.TokenBalance.ToBigInt(), user.UserPoints, 0, 0, time.Now()).Exec()
 	if err != nil {
+		if releaseErr := releaseAddress(r.db.Session(), queries.ReleaseUserAddressQuery, user.UserAddress, maxUserID+1); releaseErr != nil {
+			return commonTypes.UserData{}, errors.Join(err, releaseErr)
+		}
 		return commonTypes.UserData{}, err
 	}
 	return commonTypes.UserData{
@@ -95,8 +102,7 @@ func (r *userRepository) UpdateUserTasksAndPoints(userID int64, tasksCompleted i
 }
 
 func (r *userRepository) UpdateUserEmail(address string, email string) error {
-	var userID int64
-	err := r.db.Session().Query(queries.GetUserIDByAddressQuery, address).Scan(&userID)
+	userID, err := lookupID(r.db.Session(), queries.GetUserIDByAddressLookupQuery, address)
 	if err != nil {
 		return err
 	}
@@ -108,8 +114,7 @@ func (r *userRepository) UpdateUserEmail(address string, email string) error {
 }
 
 func (r *userRepository) GetUserDataByAddress(address string) (int64, commonTypes.UserData, error) {
-	var userID int64
-	err := r.db.Session().Query(queries.GetUserIDByAddressQuery, address).Scan(&userID)
+	userID, err := lookupID(r.db.Session(), queries.GetUserIDByAddressLookupQuery, address)
 	if err == gocql.ErrNotFound {
 		return -1, commonTypes.UserData{}, gocql.ErrNotFound
 	}
@@ -149,8 +154,15 @@ func (r *userRepository) GetUserPointsByID(id int64) (float64, error) {
 }
 
 func (r *userRepository) GetUserPointsByAddress(address string) (float64, error) {
+	userID, err := lookupID(r.db.Session(), queries.GetUserIDByAddressLookupQuery, address)
+	if err == gocql.ErrNotFound {
+		return 0, errors.New("user address not found")
+	}
+	if err != nil {
+		return 0, err
+	}
 	var userPoints float64
-	err := r.db.Session().Query(queries.GetUserPointsByAddressQuery, address).Scan(&userPoints)
+	err = r.db.Session().Query(queries.GetUserPointsByIDQuery, userID).Scan(&userPoints)
 	if err == gocql.ErrNotFound {
 		return 0, errors.New("user address not found")
 	}
@@ -161,9 +173,15 @@ func (r *userRepository) GetUserPointsByAddress(address string) (float64, error)
 }
 
 func (r *userRepository) GetUserJobIDsByAddress(address string) (int64, []*big.Int, error) {
-	var userID int64
+	userID, err := lookupID(r.db.Session(), queries.GetUserIDByAddressLookupQuery, address)
+	if err == gocql.ErrNotFound {
+		return -1, nil, errors.New("user address not found")
+	}
+	if err != nil {
+		return -1, nil, err
+	}
 	var jobIDs []*big.Int
-	err := r.db.Session().Query(queries.GetUserJobIDsByAddressQuery, address).Scan(&userID, &jobIDs)
+	err = r.db.Session().Query(queries.GetUserJobIDsByIDQuery, userID).Scan(&userID, &jobIDs)
 	if err == gocql.ErrNotFound {
 		return -1, nil, errors.New("user address not found")
 	}
@@ -214,8 +232,12 @@ func (r *userRepository) GetUserLeaderboard() ([]types.UserLeaderboardEntry, err
 }
 
 func (r *userRepository) GetUserLeaderboardByAddress(address string) (types.UserLeaderboardEntry, error) {
+	userID, err := lookupID(r.db.Session(), queries.GetUserIDByAddressLookupQuery, address)
+	if err != nil {
+		return types.UserLeaderboardEntry{}, err
+	}
 	var userEntry types.UserLeaderboardEntry
-	err := r.db.Session().Query(queries.GetUserLeaderboardByAddressQuery, address).Scan(&userEntry.UserID, &userEntry.UserAddress, &userEntry.TotalJobs, &userEntry.TotalTasks, &userEntry.UserPoints)
+	err = r.db.Session().Query(queries.GetUserLeaderboardByIDQuery, userID).Scan(&userEntry.UserID, &userEntry.UserAddress, &userEntry.TotalJobs, &userEntry.TotalTasks, &userEntry.UserPoints)
 	if err != nil {
 		return types.UserLeaderboardEntry{}, err
 	}
@@ -224,8 +246,7 @@ func (r *userRepository) GetUserLeaderboardByAddress(address string) (types.User
 }
 
 func (r *userRepository) GetUserIDByAddress(address string) (int64, error) {
-	var userID int64
-	err := r.db.Session().Query(queries.GetUserIDByAddressQuery, address).Scan(&userID)
+	userID, err := lookupID(r.db.Session(), queries.GetUserIDByAddressLookupQuery, address)
 	if err == gocql.ErrNotFound {
 		return -1, errors.New("user address not found")
 	}
@@ -234,3 +255,15 @@ func (r *userRepository) GetUserIDByAddress(address string) (int64, error) {
 	}
 	return userID, nil
 }
+
+func (r *userRepository) GetUserAddressByID(id int64) (string, error) {
+	var userAddress string
+	err := r.db.Session().Query(queries.GetUserAddressByIDQuery, id).Scan(&userAddress)
+	if err == gocql.ErrNotFound {
+		return "", errors.New("user not found")
+	}
+	if err != nil {
+		return "", err
+	}
+	return userAddress, nil
+}
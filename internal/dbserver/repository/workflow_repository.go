@@ -0,0 +1,419 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository/queries"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+)
+
+// WorkflowRepository handles workflows and their runs
+type WorkflowRepository interface {
+	CreateWorkflow(workflow *types.Workflow) error
+	UpdateWorkflow(current types.Workflow, workflow *types.Workflow) error
+	DeleteWorkflow(workflow types.Workflow) error
+	GetWorkflow(workflowID string) (types.Workflow, error)
+	GetWorkflowIDByJobID(jobID *big.Int) (string, error)
+	ListWorkflowsByUserID(userID int64, opts types.ListOptions) (types.Page[types.Workflow], error)
+	StartWorkflowRun(run *types.WorkflowRun) error
+	UpdateWorkflowRun(run *types.WorkflowRun) error
+	GetWorkflowRun(workflowID, runID string) (types.WorkflowRun, error)
+	ListWorkflowRuns(workflowID string, opts types.ListOptions) (types.Page[types.WorkflowRun], error)
+}
+
+var (
+	// ErrJobInWorkflow is returned when a job of a workflow already is in another workflow
+	ErrJobInWorkflow = errors.New("job is already in a workflow")
+	// ErrWorkflowRunning is returned when a workflow is changed or run while a run is active
+	ErrWorkflowRunning = errors.New("workflow has an active run")
+	// ErrWorkflowRunChanged is returned when a run changed since it was read, the update
+	// based on it is not made
+	ErrWorkflowRunChanged = errors.New("workflow run changed concurrently")
+)
+
+type workflowRepository struct {
+	db *database.Connection
+}
+
+// NewWorkflowRepository creates a new workflow repository
+func NewWorkflowRepository(db *database.Connection) WorkflowRepository {
+	return &workflowRepository{
+		db: db,
+	}
+}
+
+// CreateWorkflow claims the jobs of the workflow and writes it with a new ID. None of the jobs
+// is claimed when one is already in a workflow.
+func (r *workflowRepository) CreateWorkflow(workflow *types.Workflow) error {
+	workflowID := gocql.TimeUUID()
+	definition, err := json.Marshal(workflow.Definition)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow definition: %w", err)
+	}
+
+	claimed, err := r.claimJobs(workflowID, workflow.Definition.JobIDs())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.CreateWorkflowQuery, workflowID, workflow.UserID, workflow.Name, string(definition), now, now)
+	batch.Query(queries.CreateWorkflowByUserQuery, workflow.UserID, now, workflowID)
+	if err := r.db.Session().ExecuteBatch(batch); err != nil {
+		r.releaseJobs(workflowID, claimed)
+		return fmt.Errorf("failed to create workflow: %w", err)
+	}
+
+	workflow.WorkflowID = workflowID.String()
+	workflow.CreatedAt = now
+	workflow.UpdatedAt = now
+	return nil
+}
+
+// UpdateWorkflow replaces the name and definition of a workflow without an active run,
+// claiming the jobs it adds and releasing the ones it drops
+func (r *workflowRepository) UpdateWorkflow(current types.Workflow, workflow *types.Workflow) error {
+	workflowID, err := gocql.ParseUUID(current.WorkflowID)
+	if err != nil {
+		return fmt.Errorf("invalid workflow ID: %w", err)
+	}
+	definition, err := json.Marshal(workflow.Definition)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow definition: %w", err)
+	}
+
+	added, dropped := diffJobIDs(current.Definition.JobIDs(), workflow.Definition.JobIDs())
+	claimed, err := r.claimJobs(workflowID, added)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var activeRunID gocql.UUID
+	applied, err := r.db.Session().Query(queries.UpdateWorkflowQuery,
+		workflow.Name, string(definition), now, workflowID).ScanCAS(&activeRunID)
+	if err != nil || !applied {
+		r.releaseJobs(workflowID, claimed)
+		if err != nil {
+			return fmt.Errorf("failed to update workflow: %w", err)
+		}
+		return ErrWorkflowRunning
+	}
+	r.releaseJobs(workflowID, dropped)
+
+	workflow.WorkflowID = current.WorkflowID
+	workflow.UserID = current.UserID
+	workflow.CreatedAt = current.CreatedAt
+	workflow.UpdatedAt = now
+	return nil
+}
+
+// DeleteWorkflow deletes a workflow without an active run with its runs, and releases its jobs
+func (r *workflowRepository) DeleteWorkflow(workflow types.Workflow) error {
+	workflowID, err := gocql.ParseUUID(workflow.WorkflowID)
+	if err != nil {
+		return fmt.Errorf("invalid workflow ID: %w", err)
+	}
+
+	var activeRunID gocql.UUID
+	applied, err := r.db.Session().Query(queries.DeleteWorkflowQuery, workflowID).ScanCAS(&activeRunID)
+	if err != nil {
+		return fmt.Errorf("failed to delete workflow: %w", err)
+	}
+	if !applied {
+		return ErrWorkflowRunning
+	}
+
+	batch := r.db.Session().NewBatch(gocql.LoggedBatch)
+	batch.Query(queries.DeleteWorkflowByUserQuery, workflow.UserID, workflow.CreatedAt, workflowID)
+	batch.Query(queries.DeleteWorkflowRunsQuery, workflowID)
+	if err := r.db.Session().ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to delete workflow runs: %w", err)
+	}
+	r.releaseJobs(workflowID, workflow.Definition.JobIDs())
+	return nil
+}
+
+func (r *workflowRepository) GetWorkflow(workflowID string) (types.Workflow, error) {
+	id, err := gocql.ParseUUID(workflowID)
+	if err != nil {
+		return types.Workflow{}, gocql.ErrNotFound
+	}
+	workflows, err := r.getWorkflowsByIDs([]gocql.UUID{id})
+	if err != nil {
+		return types.Workflow{}, err
+	}
+	if len(workflows) == 0 {
+		return types.Workflow{}, gocql.ErrNotFound
+	}
+	return workflows[0], nil
+}
+
+func (r *workflowRepository) GetWorkflowIDByJobID(jobID *big.Int) (string, error) {
+	var workflowID gocql.UUID
+	if err := r.db.Session().Query(queries.GetWorkflowIDByJobQuery, jobID).Scan(&workflowID); err != nil {
+		return "", err
+	}
+	return workflowID.String(), nil
+}
+
+func (r *workflowRepository) ListWorkflowsByUserID(userID int64, opts types.ListOptions) (types.Page[types.Workflow], error) {
+	scope := listScope("workflows_by_user", strconv.FormatInt(userID, 10), opts)
+	cursor, err := decodeCursor(opts.Cursor, scope)
+	if err != nil {
+		return types.Page[types.Workflow]{}, err
+	}
+
+	stmt, values := timeRange(opts, "created_at", queries.ListWorkflowsByUserQuery, []interface{}{userID})
+	stmt += orderBy(opts, "created_at DESC, workflow_id DESC", "created_at ASC, workflow_id ASC")
+
+	workflows, next, err := collectPages(
+		func(state []byte, size int) ([]gocql.UUID, []byte, error) {
+			return readPage(r.db.Session(), stmt, values, state, size, scanID[gocql.UUID])
+		},
+		r.getWorkflowsByIDs,
+		cursor.State, opts.Limit)
+	if err != nil {
+		return types.Page[types.Workflow]{}, fmt.Errorf("error listing workflows by user ID: %w", err)
+	}
+	return newPage(workflows, scope, "", next), nil
+}
+
+// StartWorkflowRun claims the workflow for the run and writes the run with a new ID, failing
+// with ErrWorkflowRunning when another run is active
+func (r *workflowRepository) StartWorkflowRun(run *types.WorkflowRun) error {
+	workflowID, err := gocql.ParseUUID(run.WorkflowID)
+	if err != nil {
+		return fmt.Errorf("invalid workflow ID: %w", err)
+	}
+	runID := gocql.UUIDFromTime(run.StartedAt)
+	nodes, err := json.Marshal(run.Nodes)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow run: %w", err)
+	}
+
+	var activeRunID gocql.UUID
+	applied, err := r.db.Session().Query(queries.StartWorkflowRunQuery, runID, workflowID).ScanCAS(&activeRunID)
+	if err != nil {
+		return fmt.Errorf("failed to start workflow run: %w", err)
+	}
+	if !applied {
+		return ErrWorkflowRunning
+	}
+
+	if err := r.db.Session().Query(queries.CreateWorkflowRunQuery,
+		workflowID, runID, string(run.Status), string(nodes), run.Version, run.StartedAt, run.FinishedAt).Exec(); err != nil {
+		_, _ = r.db.Session().Query(queries.FinishWorkflowRunQuery, workflowID, runID).ScanCAS(&activeRunID)
+		return fmt.Errorf("failed to create workflow run: %w", err)
+	}
+	run.RunID = runID.String()
+	return nil
+}
+
+// UpdateWorkflowRun writes a run read with run.Version, failing with ErrWorkflowRunChanged
+// when it changed since. A finished run releases its workflow.
+func (r *workflowRepository) UpdateWorkflowRun(run *types.WorkflowRun) error {
+	workflowID, err := gocql.ParseUUID(run.WorkflowID)
+	if err != nil {
+		return fmt.Errorf("invalid workflow ID: %w", err)
+	}
+	runID, err := gocql.ParseUUID(run.RunID)
+	if err != nil {
+		return fmt.Errorf("invalid run ID: %w", err)
+	}
+	nodes, err := json.Marshal(run.Nodes)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow run: %w", err)
+	}
+
+	var existingVersion int
+	applied, err := r.db.Session().Query(queries.UpdateWorkflowRunQuery,
+		string(run.Status), string(nodes), run.Version+1, run.FinishedAt, workflowID, runID, run.Version).ScanCAS(&existingVersion)
+	if err != nil {
+		return fmt.Errorf("failed to update workflow run: %w", err)
+	}
+	if !applied {
+		return ErrWorkflowRunChanged
+	}
+	run.Version++
+
+	if run.IsFinished() {
+		var activeRunID gocql.UUID
+		if _, err := r.db.Session().Query(queries.FinishWorkflowRunQuery, workflowID, runID).ScanCAS(&activeRunID); err != nil {
+			return fmt.Errorf("failed to release workflow: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *workflowRepository) GetWorkflowRun(workflowID, runID string) (types.WorkflowRun, error) {
+	workflowUUID, err := gocql.ParseUUID(workflowID)
+	if err != nil {
+		return types.WorkflowRun{}, gocql.ErrNotFound
+	}
+	runUUID, err := gocql.ParseUUID(runID)
+	if err != nil {
+		return types.WorkflowRun{}, gocql.ErrNotFound
+	}
+
+	iter := r.db.Session().Query(queries.GetWorkflowRunQuery, workflowUUID, runUUID).Iter()
+	var run types.WorkflowRun
+	ok := scanWorkflowRun(iter, &run)
+	if err := iter.Close(); err != nil {
+		return types.WorkflowRun{}, err
+	}
+	if !ok {
+		return types.WorkflowRun{}, gocql.ErrNotFound
+	}
+	return run, nil
+}
+
+// ListWorkflowRuns lists the run history of a workflow, filtered by run status and start time
+func (r *workflowRepository) ListWorkflowRuns(workflowID string, opts types.ListOptions) (types.Page[types.WorkflowRun], error) {
+	id, err := gocql.ParseUUID(workflowID)
+	if err != nil {
+		return types.Page[types.WorkflowRun]{}, gocql.ErrNotFound
+	}
+	scope := listScope("workflow_runs", workflowID, opts)
+	cursor, err := decodeCursor(opts.Cursor, scope)
+	if err != nil {
+		return types.Page[types.WorkflowRun]{}, err
+	}
+
+	stmt := queries.ListWorkflowRunsQuery + orderBy(opts, "run_id DESC", "run_id ASC")
+	runs, next, err := collectPages(
+		func(state []byte, size int) ([]types.WorkflowRun, []byte, error) {
+			return readPage(r.db.Session(), stmt, []interface{}{id}, state, size, scanWorkflowRun)
+		},
+		func(runs []types.WorkflowRun) ([]types.WorkflowRun, error) {
+			var matching []types.WorkflowRun
+			for _, run := range runs {
+				if opts.Status != "" && string(run.Status) != opts.Status {
+					continue
+				}
+				if inRange(opts, run.StartedAt) {
+					matching = append(matching, run)
+				}
+			}
+			return matching, nil
+		},
+		cursor.State, opts.Limit)
+	if err != nil {
+		return types.Page[types.WorkflowRun]{}, fmt.Errorf("error listing workflow runs: %w", err)
+	}
+	return newPage(runs, scope, "", next), nil
+}
+
+// getWorkflowsByIDs reads the workflows in the order of workflowIDs, skipping deleted ones
+func (r *workflowRepository) getWorkflowsByIDs(workflowIDs []gocql.UUID) ([]types.Workflow, error) {
+	workflows := make([]types.Workflow, 0, len(workflowIDs))
+	for _, workflowID := range workflowIDs {
+		var workflow types.Workflow
+		var id, activeRunID gocql.UUID
+		var definition string
+		err := r.db.Session().Query(queries.GetWorkflowQuery, workflowID).Scan(
+			&id, &workflow.UserID, &workflow.Name, &definition, &activeRunID, &workflow.CreatedAt, &workflow.UpdatedAt)
+		if err == gocql.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workflow %s: %w", workflowID, err)
+		}
+		if err := json.Unmarshal([]byte(definition), &workflow.Definition); err != nil {
+			return nil, fmt.Errorf("failed to decode definition of workflow %s: %w", workflowID, err)
+		}
+		workflow.WorkflowID = id.String()
+		if activeRunID != (gocql.UUID{}) {
+			workflow.ActiveRunID = activeRunID.String()
+		}
+		workflows = append(workflows, workflow)
+	}
+	return workflows, nil
+}
+
+// scanWorkflowRun scans a row of workflow_runs
+func scanWorkflowRun(iter *gocql.Iter, run *types.WorkflowRun) bool {
+	var workflowID, runID gocql.UUID
+	var status, nodes string
+	if !iter.Scan(&workflowID, &runID, &status, &nodes, &run.Version, &run.StartedAt, &run.FinishedAt) {
+		return false
+	}
+	run.WorkflowID = workflowID.String()
+	run.RunID = runID.String()
+	run.Status = types.WorkflowRunStatus(status)
+	// A run whose nodes can not be decoded is listed without them
+	_ = json.Unmarshal([]byte(nodes), &run.Nodes)
+	return true
+}
+
+// claimJobs claims jobIDs for a workflow, releasing the ones it claimed when a job is in
+// another workflow. Jobs already in the workflow stay claimed and are not returned.
+func (r *workflowRepository) claimJobs(workflowID gocql.UUID, jobIDs []string) ([]string, error) {
+	var claimed []string
+	for _, jobID := range jobIDs {
+		id, ok := new(big.Int).SetString(jobID, 10)
+		if !ok {
+			r.releaseJobs(workflowID, claimed)
+			return nil, fmt.Errorf("invalid job ID %s", jobID)
+		}
+
+		var existingJobID *big.Int
+		var existingWorkflowID gocql.UUID
+		applied, err := r.db.Session().Query(queries.ClaimWorkflowJobQuery, id, workflowID).ScanCAS(&existingJobID, &existingWorkflowID)
+		if err != nil {
+			r.releaseJobs(workflowID, claimed)
+			return nil, fmt.Errorf("failed to claim job %s: %w", jobID, err)
+		}
+		if !applied {
+			if existingWorkflowID == workflowID {
+				continue
+			}
+			r.releaseJobs(workflowID, claimed)
+			return nil, fmt.Errorf("%w: job %s", ErrJobInWorkflow, jobID)
+		}
+		claimed = append(claimed, jobID)
+	}
+	return claimed, nil
+}
+
+// releaseJobs drops the claims of a workflow on jobIDs, best effort: a claim left behind only
+// keeps the job out of other workflows
+func (r *workflowRepository) releaseJobs(workflowID gocql.UUID, jobIDs []string) {
+	for _, jobID := range jobIDs {
+		id, ok := new(big.Int).SetString(jobID, 10)
+		if !ok {
+			continue
+		}
+		_, _ = r.db.Session().Query(queries.ReleaseWorkflowJobQuery, id, workflowID).ScanCAS(new(gocql.UUID))
+	}
+}
+
+// diffJobIDs returns the job IDs of next that are not in current, and the ones of current
+// that are not in next
+func diffJobIDs(current, next []string) (added, dropped []string) {
+	inCurrent := make(map[string]bool, len(current))
+	for _, jobID := range current {
+		inCurrent[jobID] = true
+	}
+	inNext := make(map[string]bool, len(next))
+	for _, jobID := range next {
+		inNext[jobID] = true
+		if !inCurrent[jobID] {
+			added = append(added, jobID)
+		}
+	}
+	for _, jobID := range current {
+		if !inNext[jobID] {
+			dropped = append(dropped, jobID)
+		}
+	}
+	return added, dropped
+}
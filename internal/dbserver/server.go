@@ -2,11 +2,13 @@ package db
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/config"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/events"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/handlers"
@@ -14,11 +16,13 @@ import (
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/middleware"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/redis"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/repository"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/websocket"
 	"github.com/trigg3rX/triggerx-backend/pkg/database"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor"
 	httpclientpkg "github.com/trigg3rX/triggerx-backend/pkg/http"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -102,6 +106,11 @@ type Server struct {
 	logger             logging.Logger
 	rateLimiter        *middleware.RateLimiter
 	apiKeyAuth         *middleware.ApiKeyAuth
+	walletAuth         *middleware.WalletAuth
+	serviceAuth        *middleware.ServiceAuth
+	authenticator      *auth.Authenticator
+	safeOwners         *auth.SafeOwners
+	chainClients       *auth.ChainClients
 	validator          *middleware.Validator
 	redisClient        *redis.Client
 	notificationConfig handlers.NotificationConfig
@@ -188,18 +197,18 @@ func NewServer(db *database.Connection, logger logging.Logger) *Server {
 		logger.Infof("Redis client initialized successfully")
 	}
 
-	// Initialize rate limiter
-	var rateLimiter *middleware.RateLimiter
+	// Initialize rate limiter, keeping buckets in memory without Redis
+	rateLimiter := middleware.NewLocalRateLimiter(logger)
 	if redisClient != nil {
-		var err error
-		rateLimiter, err = middleware.NewRateLimiterWithClient(redisClient, logger)
+		redisRateLimiter, err := middleware.NewRateLimiterWithClient(redisClient, logger)
 		if err != nil {
 			logger.Errorf("Failed to initialize rate limiter: %v", err)
 		} else {
+			rateLimiter = redisRateLimiter
 			logger.Info("Rate limiter initialized successfully")
 		}
 	} else {
-		logger.Warn("Rate limiter disabled - Redis client not available")
+		logger.Warn("Rate limiter keeps buckets in memory - Redis client not available")
 	}
 
 	s := &Server{
@@ -216,7 +225,9 @@ func NewServer(db *database.Connection, logger logging.Logger) *Server {
 		},
 	}
 
-	s.apiKeyAuth = middleware.NewApiKeyAuth(db, rateLimiter, logger)
+	s.apiKeyAuth = middleware.NewApiKeyAuth(db, repository.NewApiKeysRepository(db), rateLimiter, logger)
+	s.initWalletAuth()
+	s.initServiceAuth()
 
 	// Initialize WebSocket components
 	s.hub = websocket.NewHub(logger)
@@ -268,74 +279,127 @@ func (s *Server) RegisterRoutes(router *gin.Engine, dockerExecutor dockerexecuto
 	}
 
 	// Create handler w/ HTTP client and Redis client
-	handler := handlers.NewHandler(s.db, s.logger, s.notificationConfig, dockerExecutor, s.hub, publisher, httpClient, s.redisClient)
+	handler := handlers.NewHandler(s.db, s.logger, s.notificationConfig, dockerExecutor, s.hub, publisher, httpClient, s.redisClient, s.authenticator, s.safeOwners)
+
+	// Pause jobs whose scheduled pause is due
+	go handler.StartScheduledPauseLoop()
 
 	// Register metrics endpoint at root level without middleware
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// Client IPs are only read from X-Forwarded-For behind the configured proxies, so clients
+	// can not pick the IP they are rate limited and allowlisted by
+	if err := router.SetTrustedProxies(config.GetTrustedProxies()); err != nil {
+		s.logger.Fatalf("Invalid trusted proxies: %v", err)
+	}
+
 	api := router.Group("/api")
+
+	// Routes without an API key or session are limited by client IP
+	ipRatePerMinute, ipBurst := config.GetIPRateLimit()
+	public := api.Group("")
+	public.Use(s.rateLimiter.IPGinMiddleware(types.RateLimitTier{Name: "ip", RatePerMinute: ipRatePerMinute, Burst: ipBurst}))
+
 	// Code validation endpoint (raw source)
-	api.POST("/code/validate", handler.ValidateCodeExecutable)
+	// Executions are scheduled against the caller when it authenticates
+	public.POST("/code/validate", s.walletAuth.OptionalMiddleware(), s.apiKeyAuth.OptionalMiddleware(), handler.ValidateCodeExecutable)
 
-	// Health check route - no authentication required
+	// Health check route - no authentication required, nor rate limited
 	api.GET("/health", handler.HealthCheck)
 
+	// Wallet login issuing session tokens
+	public.GET("/auth/nonce", handler.GetAuthNonce)
+	public.POST("/auth/login", handler.WalletLogin)
+
 	protected := api.Group("")
 	protected.Use(s.apiKeyAuth.GinMiddleware())
 
+	// Scopes API keys need per route, leaderboards only need a valid key
+	jobsRead := s.apiKeyAuth.RequireScope(types.ScopeJobsRead)
+	jobsWrite := s.apiKeyAuth.RequireScope(types.ScopeJobsWrite)
+	tasksRead := s.apiKeyAuth.RequireScope(types.ScopeTasksRead)
+
+	// Internal mutations made by other services, audited
+	internal := api.Group("")
+	internal.Use(s.serviceAuth.GinMiddleware())
+	internal.PUT("/tasks/execution/:id", handler.UpdateTaskExecutionData)
+	internal.POST("/keepers/:id/increment-tasks", handler.IncrementKeeperTaskCount)
+	internal.POST("/keepers/:id/add-points", handler.AddTaskFeeToKeeperPoints)
+	internal.POST("/tasks/:id/completed", handler.TaskCompleted)
+	// The schedulers create the tasks of condition jobs and read the time jobs due, here they
+	// are not limited by IP
+	internal.POST("/tasks", s.validator.GinMiddleware(), handler.CreateTaskData)
+	internal.GET("/jobs/time", handler.GetTimeBasedTasks)
+
+	// Mutations of a user's jobs need a session of the job's owner
+	wallet := api.Group("")
+	wallet.Use(s.walletAuth.GinMiddleware())
+
 	// Public routes
-	protected.GET("/users/:address", handler.GetUserDataByAddress)
-	protected.POST("/users/email", handler.StoreUserEmail)
+	protected.GET("/users/:address", jobsRead, handler.GetUserDataByAddress)
+	protected.POST("/users/email", jobsWrite, handler.StoreUserEmail)
 
 	// Apply validation middleware to routes that need it
-	// api.POST("/jobs", s.validator.GinMiddleware(), handler.CreateJobData)
-	api.POST("/jobs", s.validator.GinMiddleware(), handler.CreateJobData)
-	protected.GET("/jobs/by-apikey", handler.GetJobsByApiKey)
-	api.GET("/jobs/time", handler.GetTimeBasedTasks)
-	api.PUT("/jobs/update/:id", handler.UpdateJobDataFromUser)
-	api.PUT("/jobs/:id/status/:status", handler.UpdateJobStatus)
-	api.PUT("/jobs/:id/lastexecuted", handler.UpdateJobLastExecutedAt)
-	protected.GET("/jobs/user/:user_address", handler.GetJobsByUserAddress)
-	protected.GET("/jobs/user/:user_address/chain/:created_chain_id", handler.GetJobsByUserAddressAndChainID)
-	protected.PUT("/jobs/delete/:id", handler.DeleteJobData)
-	protected.GET("/jobs/user/:user_address/:job_id", handler.GetJobDataByJobIDForUser)
-	api.GET("/jobs/:job_id/task-fees", handler.GetTaskFeesByJobID)
-
-	api.POST("/tasks", s.validator.GinMiddleware(), handler.CreateTaskData)
-	api.GET("/tasks/:id", handler.GetTaskDataByID)
+	wallet.POST("/jobs", s.validator.GinMiddleware(), handler.CreateJobData)
+	protected.GET("/jobs/by-apikey", jobsRead, handler.GetJobsByApiKey)
+	protected.GET("/jobs/manifest/by-apikey", jobsRead, handler.ExportJobManifestByApiKey)
+	wallet.GET("/jobs/manifest", handler.ExportJobManifest)
+	wallet.POST("/jobs/manifest/apply", s.validator.GinMiddleware(), handler.ApplyJobManifest)
+	wallet.PUT("/jobs/update/:id", s.walletAuth.JobOwnerMiddleware("id"), handler.UpdateJobDataFromUser)
+	wallet.PUT("/jobs/:job_id/status/:status", s.walletAuth.JobOwnerMiddleware("job_id"), handler.UpdateJobStatus)
+	wallet.PUT("/jobs/:job_id/pause", s.walletAuth.JobOwnerMiddleware("job_id"), handler.PauseJob)
+	wallet.PUT("/jobs/:job_id/resume", s.walletAuth.JobOwnerMiddleware("job_id"), handler.ResumeJob)
+	wallet.PUT("/jobs/:job_id/pause-at", s.walletAuth.JobOwnerMiddleware("job_id"), handler.SchedulePauseJob)
+	wallet.PUT("/jobs/:job_id/cancel", s.walletAuth.JobOwnerMiddleware("job_id"), handler.CancelJob)
+	wallet.POST("/workflows", handler.CreateWorkflow)
+	wallet.GET("/workflows", handler.GetWorkflows)
+	wallet.GET("/workflows/:workflow_id", handler.GetWorkflow)
+	wallet.PUT("/workflows/:workflow_id", handler.UpdateWorkflow)
+	wallet.DELETE("/workflows/:workflow_id", handler.DeleteWorkflow)
+	wallet.POST("/workflows/:workflow_id/runs", handler.StartWorkflowRun)
+	wallet.GET("/workflows/:workflow_id/runs", handler.GetWorkflowRuns)
+	wallet.GET("/workflows/:workflow_id/runs/:run_id", handler.GetWorkflowRun)
+	wallet.PUT("/workflows/:workflow_id/runs/:run_id/cancel", handler.CancelWorkflowRun)
+	wallet.PUT("/jobs/:job_id/lastexecuted", s.walletAuth.JobOwnerMiddleware("job_id"), handler.UpdateJobLastExecutedAt)
+	protected.GET("/jobs/user/:user_address", jobsRead, handler.GetJobsByUserAddress)
+	protected.GET("/jobs/user/:user_address/chain/:created_chain_id", jobsRead, handler.GetJobsByUserAddressAndChainID)
+	protected.PUT("/jobs/delete/:id", jobsWrite, s.walletAuth.GinMiddleware(), s.walletAuth.JobOwnerMiddleware("id"), handler.DeleteJobData)
+	protected.GET("/jobs/user/:user_address/:job_id", jobsRead, handler.GetJobDataByJobIDForUser)
+	public.GET("/jobs/:job_id/task-fees", handler.GetTaskFeesByJobID)
+
+	public.GET("/tasks/:id", handler.GetTaskDataByID)
 	// api.PUT("/tasks/:id/fee", handler.UpdateTaskFee)
 	// api.PUT("/tasks/:id/attestation", handler.UpdateTaskAttestationData)
-	api.PUT("/tasks/execution/:id", handler.UpdateTaskExecutionData)
-	api.GET("/tasks/job/:job_id", handler.GetTasksByJobID)
-	protected.GET("/tasks/recent", handler.GetRecentTasks)
-	protected.GET("/tasks/user/:user_address", handler.GetTasksByUserAddress)
-	protected.GET("/tasks/by-apikey/:api_key", handler.GetTasksByApiKey)
-	protected.GET("/tasks/safe-address/:safe_address", handler.GetTasksBySafeAddress)
-
-	api.POST("/keepers", s.validator.GinMiddleware(), handler.CreateKeeperData)
-	api.POST("/keepers/form", s.validator.GinMiddleware(), handler.CreateKeeperDataGoogleForm)
-	api.GET("/keepers/performers", handler.GetPerformers)
-	api.GET("/keepers/:id", handler.GetKeeperData)
-	api.POST("/keepers/:id/increment-tasks", handler.IncrementKeeperTaskCount)
-	api.GET("/keepers/:id/task-count", handler.GetKeeperTaskCount)
-	api.POST("/keepers/:id/add-points", handler.AddTaskFeeToKeeperPoints)
-	api.GET("/keepers/:id/points", handler.GetKeeperPoints)
+	public.GET("/tasks/job/:job_id", handler.GetTasksByJobID)
+	protected.GET("/tasks/recent", tasksRead, handler.GetRecentTasks)
+	protected.GET("/tasks/user/:user_address", tasksRead, handler.GetTasksByUserAddress)
+	protected.GET("/tasks/by-apikey/:api_key", tasksRead, handler.GetTasksByApiKey)
+	protected.GET("/tasks/safe-address/:safe_address", tasksRead, handler.GetTasksBySafeAddress)
+
+	public.POST("/keepers", s.validator.GinMiddleware(), handler.CreateKeeperData)
+	public.POST("/keepers/form", s.validator.GinMiddleware(), handler.CreateKeeperDataGoogleForm)
+	public.GET("/keepers/performers", handler.GetPerformers)
+	public.GET("/keepers/:id", handler.GetKeeperData)
+	public.GET("/keepers/:id/task-count", handler.GetKeeperTaskCount)
+	public.GET("/keepers/:id/points", handler.GetKeeperPoints)
 
 	protected.GET("/leaderboard/keepers", handler.GetKeeperLeaderboard)
 	protected.GET("/leaderboard/users", handler.GetUserLeaderboard)
 	protected.GET("/leaderboard/users/search", handler.GetUserLeaderboardByAddress)
-	api.GET("/leaderboard/keepers/search", handler.GetKeeperByIdentifier)
+	public.GET("/leaderboard/keepers/search", handler.GetKeeperByIdentifier)
 
-	api.GET("/fees", handler.GetTaskFees)
+	public.GET("/fees", s.walletAuth.OptionalMiddleware(), s.apiKeyAuth.OptionalMiddleware(), handler.GetTaskFees)
 
-	api.POST("/keepers/update-chat-id", handler.UpdateKeeperChatID)
-	api.GET("/keepers/com-info/:id", handler.GetKeeperCommunicationInfo)
-	api.POST("/claim-fund", handler.ClaimFund)
+	public.POST("/keepers/update-chat-id", handler.UpdateKeeperChatID)
+	public.GET("/keepers/com-info/:id", handler.GetKeeperCommunicationInfo)
+	public.POST("/claim-fund", handler.ClaimFund)
 
 	// Admin routes
 	admin := protected.Group("/admin")
+	admin.Use(s.apiKeyAuth.RequireScope(types.ScopeAdmin))
 	admin.POST("/api-keys", s.validator.GinMiddleware(), handler.CreateApiKey)
 	admin.PUT("/api-keys/:key", handler.UpdateApiKey)
+	admin.POST("/api-keys/:key/rotate", handler.RotateApiKey)
 	admin.DELETE("/api-keys/:key", handler.DeleteApiKey)
 	admin.GET("/api-keys/:owner", handler.GetApiKeysByOwner)
 
@@ -346,12 +410,55 @@ func (s *Server) RegisterRoutes(router *gin.Engine, dockerExecutor dockerexecuto
 
 	// WebSocket routes
 	wsHandler := handlers.NewWebSocketHandler(s.wsConnectionManager, s.logger)
-	api.GET("/ws/tasks", wsHandler.HandleWebSocketConnection)
-	api.GET("/ws/stats", wsHandler.GetWebSocketStats)
-	api.GET("/ws/health", wsHandler.GetWebSocketHealth)
+	public.GET("/ws/tasks", wsHandler.HandleWebSocketConnection)
+	public.GET("/ws/stats", wsHandler.GetWebSocketStats)
+	public.GET("/ws/health", wsHandler.GetWebSocketHealth)
 
-	protected.GET("/users/safe-addresses/:user_address", handler.GetSafeAddressesByUser)
-	protected.GET("/jobs/safe-address/:safe_address", handler.GetJobsBySafeAddress)
+	protected.GET("/users/safe-addresses/:user_address", jobsRead, handler.GetSafeAddressesByUser)
+	protected.GET("/jobs/safe-address/:safe_address", jobsRead, handler.GetJobsBySafeAddress)
+}
+
+// initWalletAuth sets up wallet login and the session checks of job mutations. Login needs
+// Redis for its nonces, sessions are checked without it.
+func (s *Server) initWalletAuth() {
+	secret := []byte(config.GetSessionTokenSecret())
+	if len(secret) == 0 {
+		// Only allowed in dev mode, sessions do not survive a restart
+		s.logger.Warn("SESSION_TOKEN_SECRET not set, using a random session secret")
+		secret = make([]byte, 32)
+		_, _ = rand.Read(secret)
+	}
+	sessions, err := auth.NewSessions(secret, config.GetSIWEDomain(), config.GetSessionTokenTTL())
+	if err != nil {
+		s.logger.Fatalf("Failed to initialize session tokens: %v", err)
+	}
+
+	s.chainClients = auth.NewChainClients(config.GetChainRpcUrl)
+	s.safeOwners = auth.NewSafeOwners(s.chainClients, config.GetSafeOwnersCacheTTL())
+	s.walletAuth = middleware.NewWalletAuth(sessions, repository.NewJobRepository(s.db), repository.NewUserRepository(s.db), s.safeOwners, s.logger)
+
+	if s.redisClient == nil {
+		s.logger.Warn("Wallet login disabled - Redis client not available")
+		return
+	}
+	nonces := auth.NewNonceStore(s.redisClient, config.GetSIWENonceTTL())
+	s.authenticator = auth.NewAuthenticator(config.GetSIWEDomain(), nonces, auth.NewSignatureVerifier(s.chainClients), sessions)
+	s.logger.Info("Wallet login initialized successfully")
+}
+
+// initServiceAuth sets up the authentication of internal calls by service tokens, or by the
+// client certificates of the internal listener
+func (s *Server) initServiceAuth() {
+	var verifier *jwt.ServiceTokenVerifier
+	if keys := config.GetServiceTokenPublicKeys(); len(keys) > 0 {
+		verifier = jwt.NewServiceTokenVerifier(middleware.ServiceAudience, keys...)
+	}
+
+	allowAnonymous := config.IsDevMode() && verifier == nil && !config.GetRPCTLSConfig().Enabled()
+	if allowAnonymous {
+		s.logger.Warn("Internal endpoints accept unauthenticated calls - no service token keys or RPC TLS certificate in dev mode")
+	}
+	s.serviceAuth = middleware.NewServiceAuth(verifier, allowAnonymous, s.logger)
 }
 
 func (s *Server) Start(port string) error {
@@ -365,6 +472,8 @@ func (s *Server) Start(port string) error {
 		}()
 	}
 
+	defer s.chainClients.Close()
+
 	// Graceful shutdown for WebSocket hub
 	defer func() {
 		if s.hub != nil {
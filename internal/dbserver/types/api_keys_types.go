@@ -1,19 +1,141 @@
 package types
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// Scopes of API keys. Admin keys have every scope.
+const (
+	ScopeJobsRead  = "jobs:read"
+	ScopeJobsWrite = "jobs:write"
+	ScopeTasksRead = "tasks:read"
+	ScopeAdmin     = "admin"
+)
+
+// ApiKeyScopes lists the scopes an API key may be given
+var ApiKeyScopes = []string{ScopeJobsRead, ScopeJobsWrite, ScopeTasksRead, ScopeAdmin}
+
+// DefaultApiKeyScopes are the scopes of keys created without any, and of keys made before
+// scopes existed
+var DefaultApiKeyScopes = []string{ScopeJobsRead, ScopeJobsWrite, ScopeTasksRead}
+
+// Rotated keys keep working for a grace period, so clients can move to the new key
+const (
+	DefaultApiKeyGracePeriod = 24 * time.Hour
+	MaxApiKeyGracePeriod     = 30 * 24 * time.Hour
+)
+
+// ErrInvalidApiKeySettings is returned for unknown tiers or scopes and malformed allowlists
+var ErrInvalidApiKeySettings = errors.New("invalid API key settings")
+
+// ApiKeyHasScope reports whether an API key has a scope
+func ApiKeyHasScope(apiKey *commonTypes.ApiKey, scope string) bool {
+	scopes := apiKey.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultApiKeyScopes
+	}
+	return slices.Contains(scopes, scope) || slices.Contains(scopes, ScopeAdmin)
+}
+
+// ApiKeyExpired reports whether an API key has expired at now
+func ApiKeyExpired(apiKey *commonTypes.ApiKey, now time.Time) bool {
+	return !apiKey.ExpiresAt.IsZero() && !now.Before(apiKey.ExpiresAt)
+}
+
+// ValidateApiKeySettings checks the tier, scopes and IP allowlist of an API key
+func ValidateApiKeySettings(tier string, scopes, allowedIPs []string) error {
+	if _, ok := RateLimitTiers[tier]; tier != "" && !ok {
+		return fmt.Errorf("%w: unknown rate limit tier %s", ErrInvalidApiKeySettings, tier)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(ApiKeyScopes, scope) {
+			return fmt.Errorf("%w: unknown scope %s", ErrInvalidApiKeySettings, scope)
+		}
+	}
+	for _, allowed := range allowedIPs {
+		if strings.Contains(allowed, "/") {
+			if _, _, err := net.ParseCIDR(allowed); err != nil {
+				return fmt.Errorf("%w: invalid CIDR %s", ErrInvalidApiKeySettings, allowed)
+			}
+		} else if net.ParseIP(allowed) == nil {
+			return fmt.Errorf("%w: invalid IP %s", ErrInvalidApiKeySettings, allowed)
+		}
+	}
+	return nil
+}
+
+// RateLimitTier is the token bucket of an API key: Burst requests at once, refilled at
+// RatePerMinute. Routes may cost more than one token.
+type RateLimitTier struct {
+	Name          string `json:"name"`
+	RatePerMinute int    `json:"rate_per_minute"`
+	Burst         int    `json:"burst"`
+}
+
+// RateLimitTiers are the tiers an API key may be given
+var RateLimitTiers = map[string]RateLimitTier{
+	"free":       {Name: "free", RatePerMinute: 60, Burst: 20},
+	"standard":   {Name: "standard", RatePerMinute: 300, Burst: 100},
+	"enterprise": {Name: "enterprise", RatePerMinute: 1200, Burst: 400},
+}
+
+// RateLimitTierOf returns the tier of an API key. Keys without one keep their rate_limit,
+// which may be spent at once.
+func RateLimitTierOf(apiKey *commonTypes.ApiKey) RateLimitTier {
+	if tier, ok := RateLimitTiers[apiKey.Tier]; ok {
+		return tier
+	}
+	limit := apiKey.RateLimit
+	if limit <= 0 {
+		limit = 60
+	}
+	return RateLimitTier{Name: "custom", RatePerMinute: limit, Burst: limit}
+}
 
 type CreateApiKeyRequest struct {
-	Owner     string `json:"owner" validate:"required,min=3,max=50"`
-	RateLimit int    `json:"rate_limit" validate:"required,min=1,max=1000"`
+	Owner      string     `json:"owner" validate:"required,min=3,max=50"`
+	RateLimit  int        `json:"rate_limit" validate:"required,min=1,max=1000"`
+	Tier       string     `json:"tier,omitempty" validate:"omitempty,oneof=free standard enterprise"`
+	Scopes     []string   `json:"scopes,omitempty" validate:"omitempty,dive,oneof=jobs:read jobs:write tasks:read admin"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	AllowedIPs []string   `json:"allowed_ips,omitempty" validate:"omitempty,dive,cidr|ip"`
 }
 
+// CreateApiKeyResponse returns a new API key. The key is only ever shown here, it is
+// referred to by its ID afterwards.
 type CreateApiKeyResponse struct {
-	Key       string    `json:"key"`
-	Owner     string    `json:"owner"`
-	IsActive  bool      `json:"is_active"`
-	RateLimit int       `json:"rate_limit"`
-	LastUsed  time.Time `json:"last_used"`
-	CreatedAt time.Time `json:"created_at"`
+	Key        string    `json:"key"`
+	KeyID      string    `json:"key_id"`
+	Owner      string    `json:"owner"`
+	IsActive   bool      `json:"is_active"`
+	RateLimit  int       `json:"rate_limit"`
+	Tier       string    `json:"tier,omitempty"`
+	Scopes     []string  `json:"scopes"`
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`
+	AllowedIPs []string  `json:"allowed_ips,omitempty"`
+	LastUsed   time.Time `json:"last_used"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RotateApiKeyResponse returns the new key of a rotation and when the old one expires
+type RotateApiKeyResponse struct {
+	CreateApiKeyResponse
+	ReplacedKeyID        string    `json:"replaced_key_id"`
+	ReplacedKeyExpiresAt time.Time `json:"replaced_key_expires_at"`
+}
+
+// RotateApiKeyRequest rotates an API key. The old key expires after the grace period, a
+// duration such as "24h".
+type RotateApiKeyRequest struct {
+	GracePeriod string     `json:"grace_period,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
 }
 
 type GetApiKeyCallCount struct {
@@ -23,9 +145,13 @@ type GetApiKeyCallCount struct {
 }
 
 type UpdateApiKeyRequest struct {
-	Key       string `json:"key"`
-	IsActive  *bool  `json:"isActive,omitempty"`
-	RateLimit *int   `json:"rateLimit,omitempty"`
+	Key        string     `json:"key"`
+	IsActive   *bool      `json:"isActive,omitempty"`
+	RateLimit  *int       `json:"rateLimit,omitempty"`
+	Tier       *string    `json:"tier,omitempty"`
+	Scopes     *[]string  `json:"scopes,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	AllowedIPs *[]string  `json:"allowed_ips,omitempty"`
 }
 
 type UpdateApiKeyStatusRequest struct {
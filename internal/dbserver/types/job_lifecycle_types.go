@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// JobLifecycleAction is an operation of the job lifecycle API
+type JobLifecycleAction string
+
+const (
+	JobActionPause         JobLifecycleAction = "pause"
+	JobActionResume        JobLifecycleAction = "resume"
+	JobActionSchedulePause JobLifecycleAction = "schedule-pause-at"
+	JobActionCancel        JobLifecycleAction = "cancel"
+)
+
+// ResumeMode chooses what a resumed time job does about the executions it missed while paused
+type ResumeMode string
+
+const (
+	// ResumeSkip drops the missed executions, the job runs next at its next scheduled time
+	ResumeSkip ResumeMode = "skip"
+	// ResumeCatchUp runs the job once right away for the executions it missed
+	ResumeCatchUp ResumeMode = "catch_up"
+	// ResumeNow runs the job once right away whether or not it missed executions, used by
+	// workflow runs
+	ResumeNow ResumeMode = "now"
+)
+
+// ErrInvalidJobTransition is returned for a lifecycle action a job's status does not allow
+type ErrInvalidJobTransition struct {
+	Status JobStatus
+	Action JobLifecycleAction
+}
+
+func (e *ErrInvalidJobTransition) Error() string {
+	return fmt.Sprintf("cannot %s a job that is %s", e.Action, e.Status)
+}
+
+// IsActive reports whether a job with the status is scheduled to run
+func (s JobStatus) IsActive() bool {
+	return s == JobStatusPending || s == JobStatusInQueue || s == JobStatusRunning
+}
+
+// NextJobStatus returns the status a job is in after the action, scheduling a pause keeps the
+// job's status. Active jobs can be paused, have a pause scheduled and be cancelled, paused
+// jobs can be resumed and cancelled, and cancelled, completed and deleted jobs are final.
+func NextJobStatus(status JobStatus, action JobLifecycleAction) (JobStatus, error) {
+	switch {
+	case action == JobActionPause && status.IsActive():
+		return JobStatusPaused, nil
+	case action == JobActionSchedulePause && status.IsActive():
+		return status, nil
+	case action == JobActionResume && status == JobStatusPaused:
+		return JobStatusPending, nil
+	case action == JobActionCancel && (status.IsActive() || status == JobStatusPaused):
+		return JobStatusCancelled, nil
+	}
+	return "", &ErrInvalidJobTransition{Status: status, Action: action}
+}
+
+// JobLifecycle is the lifecycle state of a job: its status, when it was paused and the time a
+// pause is scheduled at
+type JobLifecycle struct {
+	Status   JobStatus  `json:"status"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+	PauseAt  *time.Time `json:"pause_at,omitempty"`
+}
+
+// ScheduledPause is a pause of a job scheduled at a time
+type ScheduledPause struct {
+	JobID   *big.Int
+	PauseAt time.Time
+}
+
+type ResumeJobRequest struct {
+	Mode ResumeMode `json:"mode" binding:"omitempty,oneof=skip catch_up"`
+}
+
+// SchedulePauseRequest schedules a pause of a job, a nil PauseAt clears the scheduled pause
+type SchedulePauseRequest struct {
+	PauseAt *time.Time `json:"pause_at"`
+}
+
+type JobLifecycleResponse struct {
+	JobID     string             `json:"job_id"`
+	Action    JobLifecycleAction `json:"action"`
+	OldStatus JobStatus          `json:"old_status"`
+	JobLifecycle
+	ResumeMode             ResumeMode `json:"resume_mode,omitempty"`
+	NextExecutionTimestamp *time.Time `json:"next_execution_timestamp,omitempty"`
+}
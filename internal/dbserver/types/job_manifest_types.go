@@ -0,0 +1,399 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// JobManifestVersion is the version of the job manifest format
+const JobManifestVersion = "v1"
+
+// MaxManifestJobs bounds the jobs of a manifest
+const MaxManifestJobs = 500
+
+// ErrInvalidManifest is returned for a job manifest that can not be applied
+var ErrInvalidManifest = errors.New("invalid job manifest")
+
+// JobManifest declares the jobs of a user. Applying it creates the jobs it adds, updates the
+// jobs it changes and deactivates the active jobs it leaves out.
+type JobManifest struct {
+	Version string        `json:"version" yaml:"version"`
+	Jobs    []ManifestJob `json:"jobs" yaml:"jobs"`
+}
+
+// ManifestJob is a job of a manifest, identified by the job ID it was created with on chain.
+// The fields are those of a job creation request, Paused declares a job that must not run.
+type ManifestJob struct {
+	JobID     string `json:"job_id" yaml:"job_id" validate:"required"`
+	LinkJobID string `json:"link_job_id,omitempty" yaml:"link_job_id,omitempty"`
+	Paused    bool   `json:"paused,omitempty" yaml:"paused,omitempty"`
+
+	JobTitle          string  `json:"job_title" yaml:"job_title" validate:"required,min=3,max=100"`
+	TaskDefinitionID  int     `json:"task_definition_id" yaml:"task_definition_id" validate:"required,min=1,max=7"`
+	Custom            bool    `json:"custom,omitempty" yaml:"custom,omitempty"`
+	Language          string  `json:"language,omitempty" yaml:"language,omitempty"`
+	TimeFrame         int64   `json:"time_frame" yaml:"time_frame" validate:"required,min=1"`
+	Recurring         bool    `json:"recurring" yaml:"recurring"`
+	JobCostPrediction float64 `json:"job_cost_prediction" yaml:"job_cost_prediction" validate:"min=0"`
+	Timezone          string  `json:"timezone" yaml:"timezone" validate:"required"`
+	CreatedChainID    string  `json:"created_chain_id" yaml:"created_chain_id" validate:"required,chain_id"`
+	SafeAddress       string  `json:"safe_address,omitempty" yaml:"safe_address,omitempty" validate:"omitempty,ethereum_address"`
+	IsImua            bool    `json:"is_imua,omitempty" yaml:"is_imua,omitempty"`
+
+	// Time and custom job schedule, jobs are created to run on their interval
+	ScheduleType     string `json:"schedule_type,omitempty" yaml:"schedule_type,omitempty" validate:"omitempty,oneof=interval"`
+	TimeInterval     int64  `json:"time_interval,omitempty" yaml:"time_interval,omitempty" validate:"omitempty,min=1"`
+	CronExpression   string `json:"cron_expression,omitempty" yaml:"cron_expression,omitempty"`
+	SpecificSchedule string `json:"specific_schedule,omitempty" yaml:"specific_schedule,omitempty"`
+
+	// Event job trigger
+	TriggerChainID         string `json:"trigger_chain_id,omitempty" yaml:"trigger_chain_id,omitempty" validate:"omitempty,chain_id"`
+	TriggerContractAddress string `json:"trigger_contract_address,omitempty" yaml:"trigger_contract_address,omitempty" validate:"omitempty,ethereum_address"`
+	TriggerEvent           string `json:"trigger_event,omitempty" yaml:"trigger_event,omitempty"`
+	EventFilterParaName    string `json:"event_filter_para_name,omitempty" yaml:"event_filter_para_name,omitempty"`
+	EventFilterValue       string `json:"event_filter_value,omitempty" yaml:"event_filter_value,omitempty"`
+
+	// Condition job condition
+	ConditionType    string  `json:"condition_type,omitempty" yaml:"condition_type,omitempty"`
+	UpperLimit       float64 `json:"upper_limit,omitempty" yaml:"upper_limit,omitempty" validate:"omitempty,gt=0"`
+	LowerLimit       float64 `json:"lower_limit,omitempty" yaml:"lower_limit,omitempty" validate:"omitempty,gt=0"`
+	ValueSourceType  string  `json:"value_source_type,omitempty" yaml:"value_source_type,omitempty"`
+	ValueSourceUrl   string  `json:"value_source_url,omitempty" yaml:"value_source_url,omitempty"`
+	SelectedKeyRoute string  `json:"selected_key_route,omitempty" yaml:"selected_key_route,omitempty"`
+
+	// Target of the job's tasks, custom jobs only have a chain
+	TargetChainID             string   `json:"target_chain_id" yaml:"target_chain_id" validate:"required,chain_id"`
+	TargetContractAddress     string   `json:"target_contract_address,omitempty" yaml:"target_contract_address,omitempty" validate:"omitempty,ethereum_address"`
+	TargetFunction            string   `json:"target_function,omitempty" yaml:"target_function,omitempty"`
+	ABI                       string   `json:"abi,omitempty" yaml:"abi,omitempty"`
+	ArgType                   int      `json:"arg_type,omitempty" yaml:"arg_type,omitempty"`
+	Arguments                 []string `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+	DynamicArgumentsScriptUrl string   `json:"dynamic_arguments_script_url,omitempty" yaml:"dynamic_arguments_script_url,omitempty" validate:"omitempty,ipfs_url"`
+}
+
+// NewManifestJob describes a job in a manifest from its data and that of its type, the custom
+// job data is that of custom script jobs, which job responses do not carry
+func NewManifestJob(response JobResponse, customJob *commonTypes.CustomJobData) ManifestJob {
+	job := response.JobData
+	manifestJob := ManifestJob{
+		JobID:             job.JobID.String(),
+		Paused:            JobStatus(job.Status) == JobStatusPaused,
+		JobTitle:          job.JobTitle,
+		TaskDefinitionID:  job.TaskDefinitionID,
+		Custom:            job.Custom,
+		TimeFrame:         job.TimeFrame,
+		Recurring:         job.Recurring,
+		JobCostPrediction: job.JobCostPrediction,
+		Timezone:          job.Timezone,
+		CreatedChainID:    job.CreatedChainID,
+		SafeAddress:       job.SafeAddress,
+		IsImua:            job.IsImua,
+	}
+	if job.LinkJobID != nil && job.LinkJobID.Int != nil && job.LinkJobID.Sign() > 0 {
+		manifestJob.LinkJobID = job.LinkJobID.String()
+	}
+
+	target := func(chainID, contract, function, abi string, argType int, arguments []string, scriptUrl string) {
+		manifestJob.TargetChainID = chainID
+		manifestJob.TargetContractAddress = contract
+		manifestJob.TargetFunction = function
+		manifestJob.ABI = abi
+		manifestJob.ArgType = argType
+		manifestJob.Arguments = arguments
+		manifestJob.DynamicArgumentsScriptUrl = scriptUrl
+	}
+	switch {
+	case response.TimeJobData != nil:
+		timeJob := response.TimeJobData
+		manifestJob.ScheduleType = timeJob.ScheduleType
+		manifestJob.TimeInterval = timeJob.TimeInterval
+		manifestJob.CronExpression = timeJob.CronExpression
+		manifestJob.SpecificSchedule = timeJob.SpecificSchedule
+		target(timeJob.TargetChainID, timeJob.TargetContractAddress, timeJob.TargetFunction, timeJob.ABI, timeJob.ArgType, timeJob.Arguments, timeJob.DynamicArgumentsScriptUrl)
+	case response.EventJobData != nil:
+		eventJob := response.EventJobData
+		manifestJob.TriggerChainID = eventJob.TriggerChainID
+		manifestJob.TriggerContractAddress = eventJob.TriggerContractAddress
+		manifestJob.TriggerEvent = eventJob.TriggerEvent
+		manifestJob.EventFilterParaName = eventJob.EventFilterParaName
+		manifestJob.EventFilterValue = eventJob.EventFilterValue
+		target(eventJob.TargetChainID, eventJob.TargetContractAddress, eventJob.TargetFunction, eventJob.ABI, eventJob.ArgType, eventJob.Arguments, eventJob.DynamicArgumentsScriptUrl)
+	case response.ConditionJobData != nil:
+		conditionJob := response.ConditionJobData
+		manifestJob.ConditionType = conditionJob.ConditionType
+		manifestJob.UpperLimit = conditionJob.UpperLimit
+		manifestJob.LowerLimit = conditionJob.LowerLimit
+		manifestJob.ValueSourceType = conditionJob.ValueSourceType
+		manifestJob.ValueSourceUrl = conditionJob.ValueSourceUrl
+		manifestJob.SelectedKeyRoute = conditionJob.SelectedKeyRoute
+		target(conditionJob.TargetChainID, conditionJob.TargetContractAddress, conditionJob.TargetFunction, conditionJob.ABI, conditionJob.ArgType, conditionJob.Arguments, conditionJob.DynamicArgumentsScriptUrl)
+	case customJob != nil:
+		manifestJob.Language = customJob.ScriptLanguage
+		manifestJob.TimeInterval = customJob.TimeInterval
+		manifestJob.TargetChainID = customJob.TargetChainID
+		manifestJob.DynamicArgumentsScriptUrl = customJob.CustomScriptUrl
+	}
+	if len(manifestJob.Arguments) == 0 {
+		manifestJob.Arguments = nil
+	}
+	return manifestJob
+}
+
+// CreateJobData is the creation request of the manifest's job for a user
+func (j ManifestJob) CreateJobData(userAddress string) CreateJobData {
+	return CreateJobData{
+		JobID:                     j.JobID,
+		UserAddress:               userAddress,
+		JobTitle:                  j.JobTitle,
+		TaskDefinitionID:          j.TaskDefinitionID,
+		Custom:                    j.Custom,
+		Language:                  j.Language,
+		TimeFrame:                 j.TimeFrame,
+		Recurring:                 j.Recurring,
+		JobCostPrediction:         j.JobCostPrediction,
+		Timezone:                  j.Timezone,
+		CreatedChainID:            j.CreatedChainID,
+		IsSafe:                    j.SafeAddress != "",
+		SafeAddress:               j.SafeAddress,
+		ScheduleType:              j.ScheduleType,
+		TimeInterval:              j.TimeInterval,
+		CronExpression:            j.CronExpression,
+		SpecificSchedule:          j.SpecificSchedule,
+		TriggerChainID:            j.TriggerChainID,
+		TriggerContractAddress:    j.TriggerContractAddress,
+		TriggerEvent:              j.TriggerEvent,
+		EventFilterParaName:       j.EventFilterParaName,
+		EventFilterValue:          j.EventFilterValue,
+		ConditionType:             j.ConditionType,
+		UpperLimit:                j.UpperLimit,
+		LowerLimit:                j.LowerLimit,
+		ValueSourceType:           j.ValueSourceType,
+		ValueSourceUrl:            j.ValueSourceUrl,
+		SelectedKeyRoute:          j.SelectedKeyRoute,
+		TargetChainID:             j.TargetChainID,
+		TargetContractAddress:     j.TargetContractAddress,
+		TargetFunction:            j.TargetFunction,
+		ABI:                       j.ABI,
+		ArgType:                   j.ArgType,
+		Arguments:                 j.Arguments,
+		DynamicArgumentsScriptUrl: j.DynamicArgumentsScriptUrl,
+		IsImua:                    j.IsImua,
+	}
+}
+
+// ParseJobManifest decodes a manifest, as YAML when the content type is a YAML one and as JSON
+// otherwise. Unknown fields are rejected so misspelled ones are not silently dropped.
+func ParseJobManifest(body []byte, contentType string) (JobManifest, error) {
+	var manifest JobManifest
+	if strings.Contains(contentType, "yaml") {
+		decoder := yaml.NewDecoder(bytes.NewReader(body))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&manifest); err != nil {
+			return manifest, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&manifest); err != nil {
+			return manifest, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+		}
+	}
+	manifest.Normalize()
+	return manifest, nil
+}
+
+// Normalize fills the defaults of the manifest's jobs and lowercases their safe addresses, as
+// they are stored
+func (m *JobManifest) Normalize() {
+	for i := range m.Jobs {
+		job := &m.Jobs[i]
+		job.SafeAddress = strings.ToLower(job.SafeAddress)
+		if job.isTimeJob() && job.ScheduleType == "" {
+			job.ScheduleType = "interval"
+		}
+		if len(job.Arguments) == 0 {
+			job.Arguments = nil
+		}
+	}
+}
+
+// Validate checks the manifest's version, that its jobs have the fields of their type, and
+// that its links do not loop. Links to jobs out of the manifest are checked when applying it.
+func (m JobManifest) Validate() error {
+	if m.Version != JobManifestVersion {
+		return fmt.Errorf("%w: unsupported version %q, expected %q", ErrInvalidManifest, m.Version, JobManifestVersion)
+	}
+	if len(m.Jobs) > MaxManifestJobs {
+		return fmt.Errorf("%w: more than %d jobs", ErrInvalidManifest, MaxManifestJobs)
+	}
+
+	links := make(map[string]string, len(m.Jobs))
+	linked := make(map[string]string)
+	for _, job := range m.Jobs {
+		if _, ok := new(big.Int).SetString(job.JobID, 10); !ok {
+			return fmt.Errorf("%w: job ID %q is not a decimal number", ErrInvalidManifest, job.JobID)
+		}
+		if _, ok := links[job.JobID]; ok {
+			return fmt.Errorf("%w: job %s is declared twice", ErrInvalidManifest, job.JobID)
+		}
+		if err := job.validate(); err != nil {
+			return fmt.Errorf("%w: job %s: %v", ErrInvalidManifest, job.JobID, err)
+		}
+		links[job.JobID] = job.LinkJobID
+		if job.LinkJobID == "" {
+			continue
+		}
+		if _, ok := new(big.Int).SetString(job.LinkJobID, 10); !ok {
+			return fmt.Errorf("%w: job %s: link job ID %q is not a decimal number", ErrInvalidManifest, job.JobID, job.LinkJobID)
+		}
+		if other, ok := linked[job.LinkJobID]; ok {
+			return fmt.Errorf("%w: jobs %s and %s both link to job %s", ErrInvalidManifest, other, job.JobID, job.LinkJobID)
+		}
+		linked[job.LinkJobID] = job.JobID
+	}
+
+	// Every job links to at most one job, so following the links finds any loop
+	for _, job := range m.Jobs {
+		seen := map[string]bool{job.JobID: true}
+		for next := links[job.JobID]; next != ""; next = links[next] {
+			if seen[next] {
+				return fmt.Errorf("%w: the links of job %s loop", ErrInvalidManifest, job.JobID)
+			}
+			seen[next] = true
+		}
+	}
+	return nil
+}
+
+func (j ManifestJob) isTimeJob() bool {
+	return j.TaskDefinitionID == 1 || j.TaskDefinitionID == 2
+}
+
+func (j ManifestJob) isCustomJob() bool {
+	return j.TaskDefinitionID == 7
+}
+
+// validate checks the fields a job of its task definition requires
+func (j ManifestJob) validate() error {
+	if j.LinkJobID == j.JobID {
+		return errors.New("a job can not link to itself")
+	}
+
+	switch j.TaskDefinitionID {
+	case 1, 2:
+		if j.TimeInterval <= 0 {
+			return errors.New("time_interval is required for time-based jobs")
+		}
+	case 3, 4:
+		if j.TriggerChainID == "" || j.TriggerContractAddress == "" || j.TriggerEvent == "" {
+			return errors.New("trigger_chain_id, trigger_contract_address and trigger_event are required for event-based jobs")
+		}
+	case 5, 6:
+		if j.ConditionType == "" || j.ValueSourceType == "" || j.ValueSourceUrl == "" {
+			return errors.New("condition_type, value_source_type and value_source_url are required for condition-based jobs")
+		}
+	case 7:
+		if j.TimeInterval <= 0 || j.Language == "" || j.DynamicArgumentsScriptUrl == "" {
+			return errors.New("time_interval, language and dynamic_arguments_script_url are required for custom script jobs")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid task_definition_id %d", j.TaskDefinitionID)
+	}
+
+	if j.TargetContractAddress == "" || j.TargetFunction == "" || j.ABI == "" {
+		return errors.New("target_contract_address, target_function and abi are required")
+	}
+	if (j.TaskDefinitionID == 2 || j.TaskDefinitionID == 4 || j.TaskDefinitionID == 6) && j.DynamicArgumentsScriptUrl == "" {
+		return errors.New("dynamic_arguments_script_url is required for jobs with dynamic arguments")
+	}
+	return nil
+}
+
+// IsMutable reports whether a field of the job, named by its JSON name, can be changed after
+// the job is created. Like the job update API, the title, time frame, recurrence and cost
+// prediction of any job and the interval of time and custom jobs can.
+func (j ManifestJob) IsMutable(field string) bool {
+	switch field {
+	case "job_title", "time_frame", "recurring", "job_cost_prediction":
+		return true
+	case "time_interval":
+		return j.isTimeJob() || j.isCustomJob()
+	}
+	return false
+}
+
+// ChangedFields returns the JSON names of the fields the desired job changes on the current
+// one, leaving out the job ID and whether it is paused
+func ChangedFields(desired, current ManifestJob) []string {
+	var changed []string
+	desiredValue := reflect.ValueOf(desired)
+	currentValue := reflect.ValueOf(current)
+	jobType := desiredValue.Type()
+	for i := 0; i < jobType.NumField(); i++ {
+		name := strings.Split(jobType.Field(i).Tag.Get("json"), ",")[0]
+		if name == "job_id" || name == "paused" {
+			continue
+		}
+		a, b := desiredValue.Field(i), currentValue.Field(i)
+		if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// JobManifestAction is a change applying a manifest makes to a job
+type JobManifestAction string
+
+const (
+	ManifestActionCreate JobManifestAction = "create"
+	ManifestActionUpdate JobManifestAction = "update"
+	// ManifestActionPause pauses an active job the manifest declares paused
+	ManifestActionPause JobManifestAction = "pause"
+	// ManifestActionResume resumes a paused job the manifest declares active
+	ManifestActionResume JobManifestAction = "resume"
+	// ManifestActionDeactivate pauses an active job the manifest leaves out
+	ManifestActionDeactivate JobManifestAction = "deactivate"
+)
+
+// JobManifestChange is a change of a job in a manifest plan, Fields are those an update changes
+type JobManifestChange struct {
+	JobID  string            `json:"job_id"`
+	Action JobManifestAction `json:"action"`
+	Fields []string          `json:"fields,omitempty"`
+}
+
+// JobManifestConflict is a job a manifest can not be applied to as it is
+type JobManifestConflict struct {
+	JobID string `json:"job_id"`
+	Error string `json:"error"`
+}
+
+// JobManifestPlan is what applying a manifest changes, in the order the changes are made. A
+// plan with conflicts is not applied.
+type JobManifestPlan struct {
+	Changes   []JobManifestChange   `json:"changes"`
+	Conflicts []JobManifestConflict `json:"conflicts,omitempty"`
+	Unchanged []string              `json:"unchanged"`
+}
+
+type JobManifestApplyResponse struct {
+	DryRun bool            `json:"dry_run"`
+	Plan   JobManifestPlan `json:"plan"`
+	// Applied counts the changes made, when applying fails the changes after them are not made
+	// and applying the manifest again resumes with them
+	Applied int    `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,165 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func manifestTimeJob(jobID string) ManifestJob {
+	return ManifestJob{
+		JobID:                 jobID,
+		JobTitle:              "Time job",
+		TaskDefinitionID:      1,
+		TimeFrame:             3600,
+		Timezone:              "UTC",
+		CreatedChainID:        "84532",
+		TimeInterval:          60,
+		TargetChainID:         "84532",
+		TargetContractAddress: "0x0000000000000000000000000000000000000001",
+		TargetFunction:        "ping",
+		ABI:                   "[]",
+	}
+}
+
+func TestParseJobManifest(t *testing.T) {
+	yamlManifest := `
+version: v1
+jobs:
+  - job_id: "12"
+    job_title: Time job
+    task_definition_id: 1
+    time_frame: 3600
+    recurring: true
+    job_cost_prediction: 0.5
+    timezone: UTC
+    created_chain_id: "84532"
+    safe_address: "0x00000000000000000000000000000000000000AB"
+    time_interval: 60
+    target_chain_id: "84532"
+    target_contract_address: "0x0000000000000000000000000000000000000001"
+    target_function: ping
+    abi: "[]"
+    arguments: ["1", "2"]
+`
+	manifest, err := ParseJobManifest([]byte(yamlManifest), "application/yaml")
+	require.NoError(t, err)
+	require.NoError(t, manifest.Validate())
+	require.Len(t, manifest.Jobs, 1)
+	job := manifest.Jobs[0]
+	assert.Equal(t, "12", job.JobID)
+	assert.Equal(t, []string{"1", "2"}, job.Arguments)
+	// Defaults are filled and safe addresses lowercased as they are stored
+	assert.Equal(t, "interval", job.ScheduleType)
+	assert.Equal(t, "0x00000000000000000000000000000000000000ab", job.SafeAddress)
+
+	jsonManifest := `{"version": "v1", "jobs": [{"job_id": "12", "job_title": "Time job", "task_definition_id": 1}]}`
+	manifest, err = ParseJobManifest([]byte(jsonManifest), "application/json")
+	require.NoError(t, err)
+	assert.Equal(t, "Time job", manifest.Jobs[0].JobTitle)
+
+	// Misspelled fields are rejected
+	_, err = ParseJobManifest([]byte("version: v1\njobs:\n  - job_id: \"1\"\n    job_tilte: x\n"), "application/yaml")
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+	_, err = ParseJobManifest([]byte(`{"version": "v1", "jobz": []}`), "application/json")
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+}
+
+func TestJobManifestValidate(t *testing.T) {
+	linked := manifestTimeJob("2")
+	linked.LinkJobID = "1"
+	assert.NoError(t, JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{manifestTimeJob("1"), linked}}.Validate())
+
+	noInterval := manifestTimeJob("1")
+	noInterval.TimeInterval = 0
+	event := manifestTimeJob("1")
+	event.TaskDefinitionID = 3
+	dynamic := manifestTimeJob("1")
+	dynamic.TaskDefinitionID = 2
+	custom := ManifestJob{JobID: "1", TaskDefinitionID: 7, TimeInterval: 60, Language: "go"}
+	selfLinked := manifestTimeJob("1")
+	selfLinked.LinkJobID = "1"
+	loopA, loopB := manifestTimeJob("1"), manifestTimeJob("2")
+	loopA.LinkJobID, loopB.LinkJobID = "2", "1"
+	sharedA, sharedB := manifestTimeJob("1"), manifestTimeJob("2")
+	sharedA.LinkJobID, sharedB.LinkJobID = "3", "3"
+
+	tests := []struct {
+		name     string
+		manifest JobManifest
+	}{
+		{"unsupported version", JobManifest{Version: "v2"}},
+		{"invalid job ID", JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{manifestTimeJob("0x1")}}},
+		{"duplicate job", JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{manifestTimeJob("1"), manifestTimeJob("1")}}},
+		{"time job without interval", JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{noInterval}}},
+		{"event job without trigger", JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{event}}},
+		{"dynamic job without script", JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{dynamic}}},
+		{"custom job without script", JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{custom}}},
+		{"self link", JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{selfLinked}}},
+		{"link loop", JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{loopA, loopB}}},
+		{"shared link", JobManifest{Version: JobManifestVersion, Jobs: []ManifestJob{sharedA, sharedB}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.manifest.Validate(), ErrInvalidManifest)
+		})
+	}
+}
+
+func TestManifestJobDiff(t *testing.T) {
+	jobID := big.NewInt(12)
+	response := JobResponse{
+		JobData: commonTypes.JobData{
+			JobID:             commonTypes.NewBigInt(jobID),
+			JobTitle:          "Time job",
+			TaskDefinitionID:  1,
+			TimeFrame:         3600,
+			Status:            string(JobStatusPaused),
+			JobCostPrediction: 0.5,
+			Timezone:          "UTC",
+			CreatedChainID:    "84532",
+		},
+		TimeJobData: &commonTypes.TimeJobData{
+			ScheduleType:          "interval",
+			TimeInterval:          60,
+			TargetChainID:         "84532",
+			TargetContractAddress: "0x0000000000000000000000000000000000000001",
+			TargetFunction:        "ping",
+			ABI:                   "[]",
+			Arguments:             []string{},
+		},
+	}
+	current := NewManifestJob(response, nil)
+	assert.Equal(t, "12", current.JobID)
+	assert.True(t, current.Paused)
+	assert.Empty(t, current.LinkJobID)
+
+	// An exported job is unchanged, whether or not it is paused
+	desired := current
+	desired.Paused = false
+	assert.Empty(t, ChangedFields(desired, current))
+
+	desired.JobTitle = "Renamed job"
+	desired.TimeInterval = 120
+	desired.TargetFunction = "pong"
+	desired.Arguments = []string{"1"}
+	changed := ChangedFields(desired, current)
+	assert.Equal(t, []string{"job_title", "time_interval", "target_function", "arguments"}, changed)
+	assert.True(t, desired.IsMutable("job_title"))
+	assert.True(t, desired.IsMutable("time_interval"))
+	assert.False(t, desired.IsMutable("target_function"))
+	assert.False(t, desired.IsMutable("arguments"))
+
+	event := desired
+	event.TaskDefinitionID = 3
+	assert.False(t, event.IsMutable("time_interval"))
+
+	create := desired.CreateJobData("0xuser")
+	assert.Equal(t, "12", create.JobID)
+	assert.Equal(t, "0xuser", create.UserAddress)
+	assert.False(t, create.IsSafe)
+	assert.Equal(t, int64(120), create.TimeInterval)
+}
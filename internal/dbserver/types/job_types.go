@@ -24,9 +24,13 @@ const (
 type JobStatus string
 
 const (
-	JobStatusPending JobStatus = "pending"
-	JobStatusInQueue JobStatus = "in-queue"
-	JobStatusRunning JobStatus = "running"
+	JobStatusPending   JobStatus = "pending"
+	JobStatusInQueue   JobStatus = "in-queue"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusPaused    JobStatus = "paused"
+	JobStatusCancelled JobStatus = "cancelled"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusDeleted   JobStatus = "deleted"
 )
 
 type CreateJobData struct {
@@ -0,0 +1,48 @@
+package types
+
+import "time"
+
+// Page sizes of the list endpoints
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+
+	// DefaultTaskGroupsLimit is the number of jobs in a page of tasks grouped by job
+	DefaultTaskGroupsLimit = 10
+
+	// DefaultTasksPerJob is the page size of each job's tasks when tasks are listed grouped
+	// by job
+	DefaultTasksPerJob = 20
+)
+
+// SortOrder orders a list by creation time
+type SortOrder string
+
+const (
+	SortDesc SortOrder = "desc"
+	SortAsc  SortOrder = "asc"
+)
+
+// ListOptions selects a page of a list. Each list applies the filters its rows carry, a zero
+// value does not filter.
+type ListOptions struct {
+	Limit  int
+	Cursor string
+	Order  SortOrder
+
+	Status           string
+	TaskDefinitionID int
+	ChainID          string
+	// From and To bound the creation time, From inclusive and To exclusive
+	From time.Time
+	To   time.Time
+}
+
+// Page is a page of a list. NextCursor continues the list with the same options, HasMore is
+// set while the list may have more rows: a page ending exactly at the last row can still set
+// it, the next page is then empty.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	HasMore    bool
+}
@@ -64,6 +64,9 @@ type GetTasksByJobID struct {
 type TasksByJobGroupResponse struct {
 	JobID string                 `json:"job_id"`
 	Tasks []TasksByJobIDResponse `json:"tasks"`
+	// NextCursor continues the job's tasks on /tasks/job/:job_id
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
 }
 
 // RecentTaskResponse represents a task in the recent tasks list for the landing page
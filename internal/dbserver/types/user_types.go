@@ -24,3 +24,8 @@ type UserLeaderboardEntry struct {
 	TotalTasks  int64   `json:"total_tasks"`
 	UserPoints  float64 `json:"user_points"`
 }
+
+type WalletLoginRequest struct {
+	Message   string `json:"message" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
@@ -0,0 +1,469 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// MaxWorkflowNodes bounds the jobs of a workflow
+const MaxWorkflowNodes = 50
+
+// WorkflowEdgeOn is the result of the upstream job's task an edge is taken on
+type WorkflowEdgeOn string
+
+const (
+	WorkflowOnSuccess WorkflowEdgeOn = "success"
+	WorkflowOnFailure WorkflowEdgeOn = "failure"
+	WorkflowOnAlways  WorkflowEdgeOn = "always"
+)
+
+// WorkflowJoin is how a node with several incoming edges waits for them
+type WorkflowJoin string
+
+const (
+	// WorkflowJoinAll runs the node once every incoming edge is taken, and skips it as soon as
+	// one is not
+	WorkflowJoinAll WorkflowJoin = "all"
+	// WorkflowJoinAny runs the node as soon as one incoming edge is taken, and skips it when
+	// none is
+	WorkflowJoinAny WorkflowJoin = "any"
+)
+
+// Operators of output predicates
+const (
+	PredicateEq  = "eq"
+	PredicateNeq = "neq"
+	PredicateGt  = "gt"
+	PredicateGte = "gte"
+	PredicateLt  = "lt"
+	PredicateLte = "lte"
+)
+
+// WorkflowNodeStatus is the status of a node in a workflow run
+type WorkflowNodeStatus string
+
+const (
+	WorkflowNodePending   WorkflowNodeStatus = "pending"
+	WorkflowNodeRunning   WorkflowNodeStatus = "running"
+	WorkflowNodeSucceeded WorkflowNodeStatus = "succeeded"
+	WorkflowNodeFailed    WorkflowNodeStatus = "failed"
+	WorkflowNodeSkipped   WorkflowNodeStatus = "skipped"
+)
+
+// WorkflowRunStatus is the status of a workflow run, failed when a node failed without an
+// edge out of it being taken
+type WorkflowRunStatus string
+
+const (
+	WorkflowRunRunning   WorkflowRunStatus = "running"
+	WorkflowRunSucceeded WorkflowRunStatus = "succeeded"
+	WorkflowRunFailed    WorkflowRunStatus = "failed"
+	WorkflowRunCancelled WorkflowRunStatus = "cancelled"
+)
+
+// ErrInvalidWorkflow is returned for a workflow definition that is not a valid DAG
+var ErrInvalidWorkflow = errors.New("invalid workflow")
+
+// OutputPredicate compares a value of the upstream task's output, indexed as NewTaskOutput
+// lays it out, against Value. Values that parse as decimal numbers are compared as numbers,
+// others only with eq and neq.
+type OutputPredicate struct {
+	Index    int    `json:"index"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// WorkflowNode is a job of a workflow, identified by its job ID
+type WorkflowNode struct {
+	JobID string       `json:"job_id"`
+	Join  WorkflowJoin `json:"join,omitempty"`
+}
+
+// WorkflowEdge runs the To job after the From job's task when the task's result matches
+type WorkflowEdge struct {
+	From      string           `json:"from"`
+	To        string           `json:"to"`
+	On        WorkflowEdgeOn   `json:"on"`
+	Predicate *OutputPredicate `json:"predicate,omitempty"`
+}
+
+// WorkflowDefinition is the DAG of a workflow
+type WorkflowDefinition struct {
+	Nodes []WorkflowNode `json:"nodes"`
+	Edges []WorkflowEdge `json:"edges"`
+}
+
+// Workflow is a user's DAG of jobs
+type Workflow struct {
+	WorkflowID  string             `json:"workflow_id"`
+	UserID      int64              `json:"user_id"`
+	Name        string             `json:"name"`
+	Definition  WorkflowDefinition `json:"definition"`
+	ActiveRunID string             `json:"active_run_id,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// WorkflowRequest creates or replaces a workflow
+type WorkflowRequest struct {
+	Name       string             `json:"name" binding:"required,max=100"`
+	Definition WorkflowDefinition `json:"definition" binding:"required"`
+}
+
+// WorkflowNodeRun is the state of a node in a workflow run
+type WorkflowNodeRun struct {
+	Status     WorkflowNodeStatus `json:"status"`
+	TaskID     int64              `json:"task_id,omitempty"`
+	Output     []interface{}      `json:"output,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	StartedAt  *time.Time         `json:"started_at,omitempty"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+}
+
+// WorkflowRun is a run of a workflow, tying together the tasks of its jobs. Version is
+// incremented on every update, so concurrent updates of a run do not overwrite each other.
+type WorkflowRun struct {
+	WorkflowID string                      `json:"workflow_id"`
+	RunID      string                      `json:"run_id"`
+	Status     WorkflowRunStatus           `json:"status"`
+	Nodes      map[string]*WorkflowNodeRun `json:"nodes"`
+	Version    int                         `json:"-"`
+	StartedAt  time.Time                   `json:"started_at"`
+	FinishedAt *time.Time                  `json:"finished_at,omitempty"`
+}
+
+// WorkflowTaskResult is the result of a task run by a workflow node
+type WorkflowTaskResult struct {
+	TaskID  int64
+	Success bool
+	Output  []interface{}
+	Error   string
+}
+
+// Indexes of the values in a task's output
+const (
+	TaskOutputSuccess        = iota // whether the action transaction succeeded, true or false
+	TaskOutputGasUsed               // gas the action transaction used
+	TaskOutputTxHash                // hash of the action transaction
+	TaskOutputExecutedAtUnix        // when the action was executed, in unix seconds
+)
+
+// NewTaskOutput is the output of a task, the result of the action its keeper performed
+func NewTaskOutput(action commonTypes.PerformerActionData) []interface{} {
+	output := make([]interface{}, TaskOutputExecutedAtUnix+1)
+	output[TaskOutputSuccess] = action.Status
+	output[TaskOutputGasUsed] = action.GasUsed
+	output[TaskOutputTxHash] = action.ActionTxHash
+	output[TaskOutputExecutedAtUnix] = action.ExecutionTimestamp.Unix()
+	return output
+}
+
+// TaskCompletedRequest reports a task whose result was submitted on chain, sent by the task
+// monitor
+type TaskCompletedRequest struct {
+	TaskID      int64         `json:"task_id"`
+	JobID       string        `json:"job_id" binding:"required"`
+	IsAccepted  bool          `json:"is_accepted"`
+	Output      []interface{} `json:"output,omitempty"`
+	CompletedAt time.Time     `json:"completed_at"`
+}
+
+// Validate checks that the definition is a DAG of distinct jobs with valid edges
+func (d WorkflowDefinition) Validate() error {
+	if len(d.Nodes) == 0 {
+		return fmt.Errorf("%w: no nodes", ErrInvalidWorkflow)
+	}
+	if len(d.Nodes) > MaxWorkflowNodes {
+		return fmt.Errorf("%w: more than %d nodes", ErrInvalidWorkflow, MaxWorkflowNodes)
+	}
+
+	nodes := make(map[string]bool, len(d.Nodes))
+	for _, node := range d.Nodes {
+		if _, ok := new(big.Int).SetString(node.JobID, 10); !ok {
+			return fmt.Errorf("%w: invalid job ID %q", ErrInvalidWorkflow, node.JobID)
+		}
+		if nodes[node.JobID] {
+			return fmt.Errorf("%w: job %s is in more than one node", ErrInvalidWorkflow, node.JobID)
+		}
+		if node.Join != "" && node.Join != WorkflowJoinAll && node.Join != WorkflowJoinAny {
+			return fmt.Errorf("%w: invalid join %q of job %s", ErrInvalidWorkflow, node.Join, node.JobID)
+		}
+		nodes[node.JobID] = true
+	}
+
+	edges := make(map[[2]string]bool, len(d.Edges))
+	for _, edge := range d.Edges {
+		if !nodes[edge.From] || !nodes[edge.To] {
+			return fmt.Errorf("%w: edge %s -> %s is not between nodes", ErrInvalidWorkflow, edge.From, edge.To)
+		}
+		if edges[[2]string{edge.From, edge.To}] {
+			return fmt.Errorf("%w: duplicate edge %s -> %s", ErrInvalidWorkflow, edge.From, edge.To)
+		}
+		edges[[2]string{edge.From, edge.To}] = true
+		switch edge.On {
+		case WorkflowOnSuccess, WorkflowOnFailure, WorkflowOnAlways:
+		default:
+			return fmt.Errorf("%w: invalid condition %q of edge %s -> %s", ErrInvalidWorkflow, edge.On, edge.From, edge.To)
+		}
+		if edge.Predicate != nil {
+			switch edge.Predicate.Operator {
+			case PredicateEq, PredicateNeq, PredicateGt, PredicateGte, PredicateLt, PredicateLte:
+			default:
+				return fmt.Errorf("%w: invalid predicate operator %q", ErrInvalidWorkflow, edge.Predicate.Operator)
+			}
+			if edge.Predicate.Index < 0 {
+				return fmt.Errorf("%w: negative predicate index", ErrInvalidWorkflow)
+			}
+		}
+	}
+
+	if len(d.order()) != len(d.Nodes) {
+		return fmt.Errorf("%w: edges form a cycle", ErrInvalidWorkflow)
+	}
+	return nil
+}
+
+// JobIDs returns the job IDs of the nodes
+func (d WorkflowDefinition) JobIDs() []string {
+	jobIDs := make([]string, len(d.Nodes))
+	for i, node := range d.Nodes {
+		jobIDs[i] = node.JobID
+	}
+	return jobIDs
+}
+
+// order returns the nodes in topological order, leaving out the nodes on a cycle
+func (d WorkflowDefinition) order() []WorkflowNode {
+	incoming := make(map[string]int, len(d.Nodes))
+	for _, edge := range d.Edges {
+		incoming[edge.To]++
+	}
+
+	var order []WorkflowNode
+	var ready []WorkflowNode
+	for _, node := range d.Nodes {
+		if incoming[node.JobID] == 0 {
+			ready = append(ready, node)
+		}
+	}
+	byID := make(map[string]WorkflowNode, len(d.Nodes))
+	for _, node := range d.Nodes {
+		byID[node.JobID] = node
+	}
+	for len(ready) > 0 {
+		node := ready[0]
+		ready = ready[1:]
+		order = append(order, node)
+		for _, edge := range d.Edges {
+			if edge.From != node.JobID {
+				continue
+			}
+			incoming[edge.To]--
+			if incoming[edge.To] == 0 {
+				ready = append(ready, byID[edge.To])
+			}
+		}
+	}
+	return order
+}
+
+// NewWorkflowRun returns a run of the definition with its root nodes running, the nodes
+// without incoming edges. The run ID is assigned when the run is stored.
+func NewWorkflowRun(workflowID string, d WorkflowDefinition, now time.Time) *WorkflowRun {
+	run := &WorkflowRun{
+		WorkflowID: workflowID,
+		Status:     WorkflowRunRunning,
+		Nodes:      make(map[string]*WorkflowNodeRun, len(d.Nodes)),
+		StartedAt:  now,
+	}
+	for _, node := range d.Nodes {
+		run.Nodes[node.JobID] = &WorkflowNodeRun{Status: WorkflowNodePending}
+	}
+	run.advance(d, now)
+	return run
+}
+
+// Running returns the job IDs of the running nodes
+func (r *WorkflowRun) Running() []string {
+	var jobIDs []string
+	for jobID, node := range r.Nodes {
+		if node.Status == WorkflowNodeRunning {
+			jobIDs = append(jobIDs, jobID)
+		}
+	}
+	return jobIDs
+}
+
+// Complete records the result of the task of a running node, and returns the job IDs of the
+// nodes it started
+func (r *WorkflowRun) Complete(d WorkflowDefinition, jobID string, result WorkflowTaskResult, now time.Time) []string {
+	node, ok := r.Nodes[jobID]
+	if !ok || node.Status != WorkflowNodeRunning {
+		return nil
+	}
+	node.TaskID = result.TaskID
+	node.Output = result.Output
+	node.Error = result.Error
+	node.FinishedAt = &now
+	node.Status = WorkflowNodeFailed
+	if result.Success {
+		node.Status = WorkflowNodeSucceeded
+	}
+	return r.advance(d, now)
+}
+
+// Cancel skips the nodes that did not finish and ends the run
+func (r *WorkflowRun) Cancel(now time.Time) {
+	for _, node := range r.Nodes {
+		if node.Status == WorkflowNodePending || node.Status == WorkflowNodeRunning {
+			node.Status = WorkflowNodeSkipped
+		}
+	}
+	r.Status = WorkflowRunCancelled
+	r.FinishedAt = &now
+}
+
+// IsFinished reports whether the run ended
+func (r *WorkflowRun) IsFinished() bool {
+	return r.Status != WorkflowRunRunning
+}
+
+// advance starts the pending nodes whose incoming edges allow it and skips the ones they
+// rule out, in topological order so skips propagate, then ends the run when no node is left
+// to finish. It returns the job IDs of the nodes it started.
+func (r *WorkflowRun) advance(d WorkflowDefinition, now time.Time) []string {
+	var started []string
+	for _, node := range d.order() {
+		state := r.Nodes[node.JobID]
+		if state.Status != WorkflowNodePending {
+			continue
+		}
+
+		taken, resolved, total := 0, 0, 0
+		for _, edge := range d.Edges {
+			if edge.To != node.JobID {
+				continue
+			}
+			total++
+			upstream := r.Nodes[edge.From]
+			switch upstream.Status {
+			case WorkflowNodeSucceeded, WorkflowNodeFailed, WorkflowNodeSkipped:
+				resolved++
+				if edge.isTaken(upstream) {
+					taken++
+				}
+			}
+		}
+
+		var start, skip bool
+		if node.Join == WorkflowJoinAny {
+			start = total == 0 || taken > 0
+			skip = !start && resolved == total
+		} else {
+			start = taken == total
+			skip = resolved-taken > 0
+		}
+		switch {
+		case start:
+			state.Status = WorkflowNodeRunning
+			state.StartedAt = &now
+			started = append(started, node.JobID)
+		case skip:
+			state.Status = WorkflowNodeSkipped
+			state.FinishedAt = &now
+		}
+	}
+
+	if !r.IsFinished() {
+		status := WorkflowRunSucceeded
+		for jobID, node := range r.Nodes {
+			switch node.Status {
+			case WorkflowNodePending, WorkflowNodeRunning:
+				return started
+			case WorkflowNodeFailed:
+				if !r.isHandled(d, jobID) {
+					status = WorkflowRunFailed
+				}
+			}
+		}
+		r.Status = status
+		r.FinishedAt = &now
+	}
+	return started
+}
+
+// isHandled reports whether an edge out of a failed node was taken, a failure routed to
+// another job does not fail the run
+func (r *WorkflowRun) isHandled(d WorkflowDefinition, jobID string) bool {
+	for _, edge := range d.Edges {
+		if edge.From == jobID && edge.isTaken(r.Nodes[jobID]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTaken reports whether the edge is taken after its upstream node finished
+func (e WorkflowEdge) isTaken(upstream *WorkflowNodeRun) bool {
+	switch upstream.Status {
+	case WorkflowNodeSucceeded:
+		if e.On == WorkflowOnFailure {
+			return false
+		}
+	case WorkflowNodeFailed:
+		if e.On == WorkflowOnSuccess {
+			return false
+		}
+	default:
+		return false
+	}
+	return e.Predicate == nil || e.Predicate.Matches(upstream.Output)
+}
+
+// Matches reports whether the output value at the predicate's index compares to its value
+func (p OutputPredicate) Matches(output []interface{}) bool {
+	if p.Index < 0 || p.Index >= len(output) {
+		return false
+	}
+	actual := strings.TrimSpace(fmt.Sprint(output[p.Index]))
+
+	actualNumber, actualErr := parseDecimal(actual)
+	expectedNumber, expectedErr := parseDecimal(strings.TrimSpace(p.Value))
+	if actualErr == nil && expectedErr == nil {
+		cmp := actualNumber.Cmp(expectedNumber)
+		switch p.Operator {
+		case PredicateEq:
+			return cmp == 0
+		case PredicateNeq:
+			return cmp != 0
+		case PredicateGt:
+			return cmp > 0
+		case PredicateGte:
+			return cmp >= 0
+		case PredicateLt:
+			return cmp < 0
+		case PredicateLte:
+			return cmp <= 0
+		}
+		return false
+	}
+
+	switch p.Operator {
+	case PredicateEq:
+		return actual == p.Value
+	case PredicateNeq:
+		return actual != p.Value
+	}
+	return false
+}
+
+// parseDecimal parses a decimal number, hex strings such as addresses are not numbers
+func parseDecimal(value string) (*big.Float, error) {
+	number, _, err := new(big.Float).Parse(value, 10)
+	return number, err
+}
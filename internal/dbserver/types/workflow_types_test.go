@@ -0,0 +1,179 @@
+package types
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func TestWorkflowDefinitionValidate(t *testing.T) {
+	valid := WorkflowDefinition{
+		Nodes: []WorkflowNode{{JobID: "1"}, {JobID: "2"}, {JobID: "3", Join: WorkflowJoinAny}},
+		Edges: []WorkflowEdge{
+			{From: "1", To: "2", On: WorkflowOnSuccess},
+			{From: "1", To: "3", On: WorkflowOnFailure},
+			{From: "2", To: "3", On: WorkflowOnAlways, Predicate: &OutputPredicate{Index: 0, Operator: PredicateGt, Value: "10"}},
+		},
+	}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name       string
+		definition WorkflowDefinition
+	}{
+		{"no nodes", WorkflowDefinition{}},
+		{"invalid job ID", WorkflowDefinition{Nodes: []WorkflowNode{{JobID: "x"}}}},
+		{"duplicate node", WorkflowDefinition{Nodes: []WorkflowNode{{JobID: "1"}, {JobID: "1"}}}},
+		{"invalid join", WorkflowDefinition{Nodes: []WorkflowNode{{JobID: "1", Join: "some"}}}},
+		{"unknown node", WorkflowDefinition{
+			Nodes: []WorkflowNode{{JobID: "1"}},
+			Edges: []WorkflowEdge{{From: "1", To: "2", On: WorkflowOnSuccess}},
+		}},
+		{"invalid condition", WorkflowDefinition{
+			Nodes: []WorkflowNode{{JobID: "1"}, {JobID: "2"}},
+			Edges: []WorkflowEdge{{From: "1", To: "2", On: "done"}},
+		}},
+		{"invalid operator", WorkflowDefinition{
+			Nodes: []WorkflowNode{{JobID: "1"}, {JobID: "2"}},
+			Edges: []WorkflowEdge{{From: "1", To: "2", On: WorkflowOnSuccess, Predicate: &OutputPredicate{Operator: "like"}}},
+		}},
+		{"duplicate edge", WorkflowDefinition{
+			Nodes: []WorkflowNode{{JobID: "1"}, {JobID: "2"}},
+			Edges: []WorkflowEdge{{From: "1", To: "2", On: WorkflowOnSuccess}, {From: "1", To: "2", On: WorkflowOnFailure}},
+		}},
+		{"self loop", WorkflowDefinition{
+			Nodes: []WorkflowNode{{JobID: "1"}},
+			Edges: []WorkflowEdge{{From: "1", To: "1", On: WorkflowOnSuccess}},
+		}},
+		{"cycle", WorkflowDefinition{
+			Nodes: []WorkflowNode{{JobID: "1"}, {JobID: "2"}, {JobID: "3"}},
+			Edges: []WorkflowEdge{
+				{From: "1", To: "2", On: WorkflowOnSuccess},
+				{From: "2", To: "3", On: WorkflowOnSuccess},
+				{From: "3", To: "2", On: WorkflowOnSuccess},
+			},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.definition.Validate(), ErrInvalidWorkflow)
+		})
+	}
+}
+
+func sorted(jobIDs []string) []string {
+	sort.Strings(jobIDs)
+	return jobIDs
+}
+
+func TestWorkflowRunFanOutFanIn(t *testing.T) {
+	// 1 fans out to 2 and 3, which fan in to 4
+	definition := WorkflowDefinition{
+		Nodes: []WorkflowNode{{JobID: "1"}, {JobID: "2"}, {JobID: "3"}, {JobID: "4"}},
+		Edges: []WorkflowEdge{
+			{From: "1", To: "2", On: WorkflowOnSuccess},
+			{From: "1", To: "3", On: WorkflowOnSuccess},
+			{From: "2", To: "4", On: WorkflowOnSuccess},
+			{From: "3", To: "4", On: WorkflowOnSuccess},
+		},
+	}
+	now := time.Now()
+	run := NewWorkflowRun("w", definition, now)
+	assert.Equal(t, []string{"1"}, run.Running())
+
+	started := run.Complete(definition, "1", WorkflowTaskResult{TaskID: 10, Success: true}, now)
+	assert.Equal(t, []string{"2", "3"}, sorted(started))
+
+	// The fan-in waits for every upstream job
+	assert.Empty(t, run.Complete(definition, "2", WorkflowTaskResult{TaskID: 11, Success: true}, now))
+	assert.Equal(t, WorkflowNodePending, run.Nodes["4"].Status)
+	assert.Equal(t, []string{"4"}, run.Complete(definition, "3", WorkflowTaskResult{TaskID: 12, Success: true}, now))
+
+	// A result of a node that is not running is ignored
+	assert.Empty(t, run.Complete(definition, "3", WorkflowTaskResult{TaskID: 13, Success: false}, now))
+	assert.Equal(t, int64(12), run.Nodes["3"].TaskID)
+
+	assert.False(t, run.IsFinished())
+	run.Complete(definition, "4", WorkflowTaskResult{TaskID: 14, Success: true}, now)
+	assert.Equal(t, WorkflowRunSucceeded, run.Status)
+	assert.NotNil(t, run.FinishedAt)
+}
+
+func TestWorkflowRunConditions(t *testing.T) {
+	// 1 leads to 2 on success, to the failure handler 3 on failure, and 4 joins either
+	definition := WorkflowDefinition{
+		Nodes: []WorkflowNode{{JobID: "1"}, {JobID: "2"}, {JobID: "3"}, {JobID: "4", Join: WorkflowJoinAny}},
+		Edges: []WorkflowEdge{
+			{From: "1", To: "2", On: WorkflowOnSuccess},
+			{From: "1", To: "3", On: WorkflowOnFailure},
+			{From: "2", To: "4", On: WorkflowOnSuccess},
+			{From: "3", To: "4", On: WorkflowOnSuccess},
+		},
+	}
+	now := time.Now()
+
+	run := NewWorkflowRun("w", definition, now)
+	assert.Equal(t, []string{"3"}, run.Complete(definition, "1", WorkflowTaskResult{Success: false}, now))
+	assert.Equal(t, WorkflowNodeSkipped, run.Nodes["2"].Status)
+	assert.Equal(t, []string{"4"}, run.Complete(definition, "3", WorkflowTaskResult{Success: true}, now))
+	run.Complete(definition, "4", WorkflowTaskResult{Success: true}, now)
+	// The failure was handled by an edge out of the failed job
+	assert.Equal(t, WorkflowRunSucceeded, run.Status)
+
+	// Skips propagate, and an unhandled failure fails the run
+	run = NewWorkflowRun("w", definition, now)
+	run.Complete(definition, "1", WorkflowTaskResult{Success: true}, now)
+	assert.Empty(t, run.Complete(definition, "2", WorkflowTaskResult{Success: false}, now))
+	assert.Equal(t, WorkflowNodeSkipped, run.Nodes["3"].Status)
+	assert.Equal(t, WorkflowNodeSkipped, run.Nodes["4"].Status)
+	assert.Equal(t, WorkflowRunFailed, run.Status)
+
+	// Cancelling skips the nodes that did not finish
+	run = NewWorkflowRun("w", definition, now)
+	run.Cancel(now)
+	assert.Equal(t, WorkflowRunCancelled, run.Status)
+	assert.Equal(t, WorkflowNodeSkipped, run.Nodes["1"].Status)
+	assert.Empty(t, run.Complete(definition, "1", WorkflowTaskResult{Success: true}, now))
+}
+
+func TestOutputPredicate(t *testing.T) {
+	output := []interface{}{float64(42), "0xabc", true, "1000000000000000000000"}
+
+	assert.True(t, OutputPredicate{Index: 0, Operator: PredicateEq, Value: "42"}.Matches(output))
+	assert.True(t, OutputPredicate{Index: 0, Operator: PredicateGt, Value: "41.5"}.Matches(output))
+	assert.False(t, OutputPredicate{Index: 0, Operator: PredicateLt, Value: "42"}.Matches(output))
+	assert.True(t, OutputPredicate{Index: 0, Operator: PredicateLte, Value: "42"}.Matches(output))
+	assert.True(t, OutputPredicate{Index: 3, Operator: PredicateGte, Value: "999999999999999999999"}.Matches(output))
+
+	assert.True(t, OutputPredicate{Index: 1, Operator: PredicateEq, Value: "0xabc"}.Matches(output))
+	assert.True(t, OutputPredicate{Index: 2, Operator: PredicateNeq, Value: "false"}.Matches(output))
+	// Strings are only compared for equality
+	assert.False(t, OutputPredicate{Index: 1, Operator: PredicateGt, Value: "0xaaa"}.Matches(output))
+
+	// A value out of the output does not match
+	assert.False(t, OutputPredicate{Index: 4, Operator: PredicateNeq, Value: "1"}.Matches(output))
+
+	// An edge with a predicate is only taken when it matches
+	edge := WorkflowEdge{From: "1", To: "2", On: WorkflowOnSuccess, Predicate: &OutputPredicate{Index: 0, Operator: PredicateGt, Value: "100"}}
+	assert.False(t, edge.isTaken(&WorkflowNodeRun{Status: WorkflowNodeSucceeded, Output: output}))
+	assert.True(t, edge.isTaken(&WorkflowNodeRun{Status: WorkflowNodeSucceeded, Output: []interface{}{float64(101)}}))
+}
+
+func TestNewTaskOutput(t *testing.T) {
+	executedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	output := NewTaskOutput(commonTypes.PerformerActionData{
+		ActionTxHash:       "0xabc",
+		GasUsed:            "21000",
+		Status:             true,
+		ExecutionTimestamp: executedAt,
+		ConvertedArguments: []interface{}{"1", "2"},
+	})
+
+	assert.Equal(t, []interface{}{true, "21000", "0xabc", executedAt.Unix()}, output)
+	assert.True(t, OutputPredicate{Index: TaskOutputSuccess, Operator: PredicateEq, Value: "true"}.Matches(output))
+	assert.True(t, OutputPredicate{Index: TaskOutputGasUsed, Operator: PredicateLt, Value: "50000"}.Matches(output))
+	assert.False(t, OutputPredicate{Index: TaskOutputTxHash, Operator: PredicateEq, Value: "0xdef"}.Matches(output))
+}
@@ -309,6 +309,25 @@ func (h *Hub) BroadcastTaskFeeUpdated(taskData *TaskEventData) {
 	}
 }
 
+// BroadcastJobLifecycleChanged broadcasts a job lifecycle event to the rooms of the job and
+// its user
+func (h *Hub) BroadcastJobLifecycleChanged(jobData *JobEventData) {
+	jobData.Timestamp = time.Now()
+	rooms := []string{"job:" + jobData.JobID}
+	if jobData.UserID != "" {
+		rooms = append(rooms, "user:"+jobData.UserID)
+	}
+
+	select {
+	case h.broadcast <- &BroadcastMessage{
+		Message: NewJobEventMessage(MessageTypeJobLifecycleChanged, jobData),
+		Rooms:   rooms,
+	}:
+	default:
+		h.logger.Warn("Broadcast channel is full, dropping job lifecycle event")
+	}
+}
+
 // GetStats returns hub statistics
 func (h *Hub) GetStats() map[string]interface{} {
 	h.mu.RLock()
@@ -16,6 +16,9 @@ const (
 	MessageTypeTaskFeeUpdated    MessageType = "TASK_FEE_UPDATED"
 	MessageTypeJobTasksSnapshot  MessageType = "JOB_TASKS_SNAPSHOT"
 
+	// Job-related message types
+	MessageTypeJobLifecycleChanged MessageType = "JOB_LIFECYCLE_CHANGED"
+
 	// System message types
 	MessageTypeSubscribe   MessageType = "SUBSCRIBE"
 	MessageTypeUnsubscribe MessageType = "UNSUBSCRIBE"
@@ -42,6 +45,14 @@ type TaskEventData struct {
 	Timestamp time.Time   `json:"timestamp"`
 }
 
+// JobEventData represents job-related event data
+type JobEventData struct {
+	JobID     string      `json:"job_id"`
+	UserID    string      `json:"user_id,omitempty"`
+	Changes   interface{} `json:"changes,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
 // SubscriptionData represents subscription request data
 type SubscriptionData struct {
 	Room   string `json:"room"`
@@ -102,6 +113,15 @@ func NewTaskEventMessage(msgType MessageType, taskData *TaskEventData) *Message
 	}
 }
 
+// NewJobEventMessage creates a new job event message
+func NewJobEventMessage(msgType MessageType, jobData *JobEventData) *Message {
+	return &Message{
+		Type:      msgType,
+		Data:      jobData,
+		Timestamp: time.Now(),
+	}
+}
+
 // NewErrorMessage creates a new error message
 func NewErrorMessage(code, message string) *Message {
 	return &Message{
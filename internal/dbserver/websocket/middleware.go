@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/middleware"
+	dbserverTypes "github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
@@ -58,7 +60,7 @@ func NewWebSocketAuthMiddleware(apiKeyAuth *middleware.ApiKeyAuth, logger loggin
 }
 
 // AuthenticateWebSocket authenticates WebSocket connection using existing ApiKeyAuth logic
-func (wam *WebSocketAuthMiddleware) AuthenticateWebSocket(c *gin.Context) (string, string, error) {
+func (wam *WebSocketAuthMiddleware) AuthenticateWebSocket(c *gin.Context) (*types.ApiKey, string, error) {
 	// Check for API key in query parameters first (WebSocket specific)
 	apiKey := c.Query("api_key")
 	if apiKey == "" {
@@ -68,37 +70,45 @@ func (wam *WebSocketAuthMiddleware) AuthenticateWebSocket(c *gin.Context) (strin
 
 	if apiKey == "" {
 		wam.logger.Warn("WebSocket connection attempt without API key")
-		return "", "", &WebSocketAuthError{
+		return nil, "", &WebSocketAuthError{
 			Code:    "MISSING_API_KEY",
 			Message: "API key is required for WebSocket connection",
 		}
 	}
 
 	// Use existing ApiKeyAuth logic to validate the API key
-	apiKeyData, err := wam.apiKeyAuth.GetApiKey(apiKey)
+	apiKeyData, err := wam.apiKeyAuth.ValidateApiKey(apiKey, c.ClientIP())
+	if errors.Is(err, middleware.ErrApiKeyInactive) {
+		wam.logger.Warnf("Inactive API key used for WebSocket connection: %s", apiKeyData.Key)
+		return nil, "", &WebSocketAuthError{
+			Code:    "INACTIVE_API_KEY",
+			Message: "API key is inactive",
+		}
+	}
 	if err != nil {
 		wam.logger.Errorf("Invalid API key for WebSocket connection: %v", err)
-		return "", "", &WebSocketAuthError{
+		return nil, "", &WebSocketAuthError{
 			Code:    "INVALID_API_KEY",
 			Message: "Invalid or inactive API key",
 		}
 	}
 
-	if !apiKeyData.IsActive {
-		wam.logger.Warnf("Inactive API key used for WebSocket connection: %s", apiKey)
-		return "", "", &WebSocketAuthError{
-			Code:    "INACTIVE_API_KEY",
-			Message: "API key is inactive",
+	// Connections stream task updates, so keys need the scope of the task routes
+	if !dbserverTypes.ApiKeyHasScope(apiKeyData, dbserverTypes.ScopeTasksRead) {
+		wam.logger.Warnf("API key %s without the %s scope used for WebSocket connection", apiKeyData.Key, dbserverTypes.ScopeTasksRead)
+		return nil, "", &WebSocketAuthError{
+			Code:    "INSUFFICIENT_SCOPE",
+			Message: "API key does not have the required scope",
 		}
 	}
 
 	// Update last used timestamp asynchronously
-	go wam.apiKeyAuth.UpdateLastUsed(apiKey)
+	go wam.apiKeyAuth.UpdateLastUsed(apiKeyData, c.ClientIP())
 
 	// Extract user ID from the API key owner
 	userID := wam.extractUserIDFromApiKey(apiKeyData)
 
-	return apiKey, userID, nil
+	return apiKeyData, userID, nil
 }
 
 // extractUserIDFromApiKey extracts user ID from API key data
@@ -204,24 +214,24 @@ func (wcm *WebSocketConnectionManager) HandleWebSocketConnection(c *gin.Context)
 	clientIP := c.ClientIP()
 
 	// Authenticate the connection first to get API key data
-	apiKey, userID, err := wcm.auth.AuthenticateWebSocket(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
+	apiKeyData, userID, err := wcm.auth.AuthenticateWebSocket(c)
+	var authErr *WebSocketAuthError
+	if errors.As(err, &authErr) && authErr.Code == "INSUFFICIENT_SCOPE" {
+		c.JSON(http.StatusForbidden, gin.H{
 			"error": err.Error(),
-			"code":  "AUTHENTICATION_FAILED",
+			"scope": dbserverTypes.ScopeTasksRead,
 		})
 		return
 	}
-
-	// Get API key data for rate limiting
-	apiKeyData, err := wcm.auth.apiKeyAuth.GetApiKey(apiKey)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid API key",
-			"code":  "INVALID_API_KEY",
+			"error": err.Error(),
+			"code":  "AUTHENTICATION_FAILED",
 		})
 		return
 	}
+	// Clients are known by the ID of their key, never the key itself
+	apiKey := apiKeyData.Key
 
 	// Check rate limit with API key data
 	if !wcm.rateLimit.CheckRateLimit(clientIP, apiKeyData) {
@@ -0,0 +1,88 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// counterCode deploys a contract whose every call increments storage slot 0:
+// PUSH1 1 PUSH1 0 SLOAD ADD PUSH1 0 SSTORE STOP, behind a constructor returning it
+var counterCode = common.FromHex("0x600a600c600039600a6000f3" + "60016000540160005500")
+
+// CounterABI is the ABI jobs on a counter contract are created with, any call increments it
+const CounterABI = `[{"inputs":[],"name":"increment","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+// taskExecutionHubCode deploys the devnet's TaskExecutionHub. Its only function is
+// executeFunction(uint256 jobId, uint256 tgAmount, address target, bytes data), which forwards
+// data and the call value to target and reverts when that call reverts:
+//
+//	selector == 0xfa9b1a80 or revert
+//	CALLDATACOPY(0, data offset, data length)
+//	CALL(gas, target, callvalue, 0, data length, 0, 0) or revert
+//
+// The hub of the TriggerX contracts also charges the job's TG balance and checks the keeper is
+// registered, the bindings in the contracts repository carry no creation code to deploy it with.
+var taskExecutionHubCode = common.FromHex("0x6039600c60003960396000f3" +
+	"60003560e01c63fa9b1a8014601357600080fd5b60643560040180358091602001600037" +
+	"600080916000346044355af1603757600080fd5b00")
+
+// taskExecutionHubABI is the part of the TaskExecutionHub ABI keepers execute actions through
+const taskExecutionHubABI = `[{"inputs":[{"internalType":"uint256","name":"jobId","type":"uint256"},{"internalType":"uint256","name":"tgAmount","type":"uint256"},{"internalType":"address","name":"target","type":"address"},{"internalType":"bytes","name":"data","type":"bytes"}],"name":"executeFunction","outputs":[],"stateMutability":"payable","type":"function"}]`
+
+var taskExecutionHub = mustParseABI(taskExecutionHubABI)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI: %v", err))
+	}
+	return parsed
+}
+
+// deploy deploys code from the deployer account and returns the contract's address
+func (d *Devnet) deploy(ctx context.Context, code []byte) (common.Address, error) {
+	receipt, err := d.transact(ctx, d.deployerKey, common.Address{}, nil, code)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		return common.Address{}, fmt.Errorf("deployment reverted")
+	}
+	return receipt.ContractAddress, nil
+}
+
+// DeployCounter deploys a contract counting the calls it receives and registers it under name
+func (d *Devnet) DeployCounter(ctx context.Context, name string) (common.Address, error) {
+	address, err := d.deploy(ctx, counterCode)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to deploy counter: %w", err)
+	}
+
+	d.mu.Lock()
+	d.contracts[name] = address
+	d.mu.Unlock()
+	return address, nil
+}
+
+// Contract returns the address of the contract registered under name
+func (d *Devnet) Contract(name string) (common.Address, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	address, ok := d.contracts[name]
+	return address, ok
+}
+
+// CounterValue returns how many calls the counter at address received
+func (d *Devnet) CounterValue(ctx context.Context, address common.Address) (uint64, error) {
+	value, err := d.Client().StorageAt(ctx, address, common.Hash{}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read counter: %w", err)
+	}
+	return new(big.Int).SetBytes(value).Uint64(), nil
+}
@@ -0,0 +1,367 @@
+package devnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/trigg3rX/triggerx-backend/internal/localaggregator"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+	"github.com/trigg3rX/triggerx-backend/pkg/database/memory"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/proof"
+)
+
+const (
+	// AttestationChainID is the chain the aggregator stand-in emits AttestationCenter events on,
+	// the chain taskmonitor's testnet listener polls
+	AttestationChainID = 84532
+	// AttestationCenterAddress is the AttestationCenter address on the attestation chain
+	AttestationCenterAddress = "0x710DAb96f318b16F0fC9962D3466C00275414Ff0"
+
+	shutdownTimeout = 10 * time.Second
+)
+
+// started is set by the first New of the process. The services keep their config, database
+// connection and keeper state in package variables, so they boot once per process.
+var started atomic.Bool
+
+// Config holds the devnet settings
+type Config struct {
+	// Keepers is the number of keepers to boot, defaults to 3
+	Keepers int
+	// QuorumPercent is the aggregator stand-in's quorum, defaults to 66
+	QuorumPercent uint64
+}
+
+// Devnet boots the TriggerX services in one process: dbserver, health, taskmonitor,
+// eventmonitor, the condition and time schedulers and the task dispatcher run against an
+// in-memory Scylla, miniredis, a local IPFS directory, the aggregator stand-in with its
+// simulated attestation chain, and a simulated EVM chain the TaskExecutionHub is deployed on.
+// N keepers register with dbserver, check in with health and execute through the hub, see
+// docs/devnet.md for what is stood in.
+type Devnet struct {
+	logger logging.Logger
+
+	backend *simulated.Backend
+	// mineMu serializes transaction submission and block production
+	mineMu sync.Mutex
+
+	Database *memory.Server
+	Redis    *miniredis.Miniredis
+	conn     *database.Connection
+	ipfsDir  string
+
+	AttestationChain *localaggregator.Chain
+	Aggregator       *localaggregator.Aggregator
+	AggregatorURL    string
+	aggregatorServer *http.Server
+
+	// DBServerURL and HealthURL are the APIs of dbserver and health
+	DBServerURL string
+	HealthURL   string
+	services    []service
+
+	// TaskExecutionHub is the hub keepers execute actions through
+	TaskExecutionHub common.Address
+	Keepers          []*Keeper
+
+	managerKey     *ecdsa.PrivateKey
+	managerAddress common.Address
+	deployerKey    *ecdsa.PrivateKey
+	user           *user
+
+	mu        sync.Mutex
+	contracts map[string]common.Address
+	jobs      map[string]*TimeJob
+	nextJobID int64
+}
+
+// New boots a devnet, Close shuts it down. Only one devnet can run per process.
+func New(logger logging.Logger, cfg Config) (*Devnet, error) {
+	if !started.CompareAndSwap(false, true) {
+		return nil, errors.New("a devnet was already started in this process")
+	}
+	if cfg.Keepers == 0 {
+		cfg.Keepers = 3
+	}
+
+	d := &Devnet{
+		logger:    logger,
+		contracts: make(map[string]common.Address),
+		jobs:      make(map[string]*TimeJob),
+		nextJobID: 1,
+	}
+	if err := d.start(cfg); err != nil {
+		d.Close()
+		return nil, err
+	}
+	logger.Info("Devnet started", "keepers", len(d.Keepers), "dbserver", d.DBServerURL, "aggregator", d.AggregatorURL)
+	return d, nil
+}
+
+func (d *Devnet) start(cfg Config) error {
+	ctx := context.Background()
+
+	var err error
+	if d.managerKey, err = crypto.GenerateKey(); err != nil {
+		return fmt.Errorf("failed to generate manager key: %w", err)
+	}
+	d.managerAddress = crypto.PubkeyToAddress(d.managerKey.PublicKey)
+	if d.deployerKey, err = crypto.GenerateKey(); err != nil {
+		return fmt.Errorf("failed to generate deployer key: %w", err)
+	}
+	if d.user, err = newUser(); err != nil {
+		return err
+	}
+
+	keeperKeys := make([]*ecdsa.PrivateKey, cfg.Keepers)
+	alloc := ethtypes.GenesisAlloc{
+		crypto.PubkeyToAddress(d.deployerKey.PublicKey): {Balance: big.NewInt(params.Ether)},
+	}
+	for i := range keeperKeys {
+		if keeperKeys[i], err = crypto.GenerateKey(); err != nil {
+			return fmt.Errorf("failed to generate keeper key: %w", err)
+		}
+		alloc[crypto.PubkeyToAddress(keeperKeys[i].PublicKey)] = ethtypes.Account{Balance: big.NewInt(params.Ether)}
+	}
+	d.backend = simulated.NewBackend(alloc)
+
+	if d.TaskExecutionHub, err = d.deploy(ctx, taskExecutionHubCode); err != nil {
+		return fmt.Errorf("failed to deploy task execution hub: %w", err)
+	}
+
+	if d.ipfsDir, err = os.MkdirTemp("", "triggerx-devnet-ipfs-"); err != nil {
+		return fmt.Errorf("failed to create IPFS directory: %w", err)
+	}
+	if d.Database, err = memory.NewServer(d.logger.With("service", "scylla"), "triggerx"); err != nil {
+		return fmt.Errorf("failed to start database: %w", err)
+	}
+	if d.Redis, err = miniredis.Run(); err != nil {
+		return fmt.Errorf("failed to start redis: %w", err)
+	}
+	if err := d.startAggregator(cfg.QuorumPercent); err != nil {
+		return err
+	}
+
+	ports, err := newServicePorts()
+	if err != nil {
+		return err
+	}
+	defer ports.close()
+	if err := d.setSharedEnv(ports); err != nil {
+		return err
+	}
+
+	if err := d.startService("tempo", func() (func(ctx context.Context) error, error) {
+		return d.startTraceSink(ports)
+	}); err != nil {
+		return err
+	}
+	// Keepers register with dbserver before health boots and loads the registered keepers
+	if err := d.startService("dbserver", func() (func(ctx context.Context) error, error) {
+		return d.startDBServer(ctx, ports)
+	}); err != nil {
+		return err
+	}
+	for i, key := range keeperKeys {
+		keeper, err := newKeeper(d, i+1, key)
+		if err != nil {
+			return fmt.Errorf("failed to start keeper %d: %w", i+1, err)
+		}
+		d.Keepers = append(d.Keepers, keeper)
+		if err := keeper.register(ctx); err != nil {
+			return fmt.Errorf("failed to register keeper %d: %w", i+1, err)
+		}
+	}
+
+	if err := d.startService("health", func() (func(ctx context.Context) error, error) {
+		return d.startHealth(ports)
+	}); err != nil {
+		return err
+	}
+	if err := d.startService("taskmonitor", func() (func(ctx context.Context) error, error) {
+		return d.startTaskMonitor(ctx, ports)
+	}); err != nil {
+		return err
+	}
+	if err := d.startService("eventmonitor", func() (func(ctx context.Context) error, error) {
+		return d.startEventMonitor(ports)
+	}); err != nil {
+		return err
+	}
+	if err := d.startService("condition-scheduler", func() (func(ctx context.Context) error, error) {
+		return d.startConditionScheduler(ctx, ports)
+	}); err != nil {
+		return err
+	}
+
+	// The dispatcher caches the performers health reports for a minute, keepers check in first
+	for i, keeper := range d.Keepers {
+		if err := keeper.start(ctx); err != nil {
+			return fmt.Errorf("failed to check in keeper %d: %w", i+1, err)
+		}
+		if err := d.Aggregator.RegisterKeeper(keeper.registration()); err != nil {
+			return fmt.Errorf("failed to register keeper %d with the aggregator: %w", i+1, err)
+		}
+	}
+
+	if err := d.startService("taskdispatcher", func() (func(ctx context.Context) error, error) {
+		return d.startTaskDispatcher(ctx, ports)
+	}); err != nil {
+		return err
+	}
+	if err := d.startService("time-scheduler", func() (func(ctx context.Context) error, error) {
+		return d.startTimeScheduler(ctx, ports)
+	}); err != nil {
+		return err
+	}
+
+	return d.user.login(ctx, d.DBServerURL, d.ChainID())
+}
+
+func (d *Devnet) startAggregator(quorumPercent uint64) error {
+	chain, err := localaggregator.NewChain(AttestationChainID, AttestationCenterAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create attestation chain: %w", err)
+	}
+	chainServer, err := chain.RPCServer()
+	if err != nil {
+		return fmt.Errorf("failed to create attestation chain RPC server: %w", err)
+	}
+	agg, err := localaggregator.NewAggregator(d.logger, localaggregator.Config{QuorumPercent: quorumPercent}, chain)
+	if err != nil {
+		return fmt.Errorf("failed to create aggregator: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/chain", chainServer)
+	mux.Handle("/", agg)
+	d.aggregatorServer = d.serveHTTP("aggregator", listener, mux)
+
+	d.AttestationChain = chain
+	d.Aggregator = agg
+	d.AggregatorURL = "http://" + listener.Addr().String()
+	return nil
+}
+
+// Close stops every service and the simulated chain
+func (d *Devnet) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	d.stopServices(ctx)
+	if d.Aggregator != nil {
+		d.Aggregator.Wait()
+	}
+	for _, keeper := range d.Keepers {
+		keeper.close(ctx)
+	}
+	if d.aggregatorServer != nil {
+		_ = d.aggregatorServer.Shutdown(ctx)
+	}
+	if d.conn != nil {
+		d.conn.Close()
+	}
+	if d.Redis != nil {
+		d.Redis.Close()
+	}
+	if d.Database != nil {
+		_ = d.Database.Close()
+	}
+	if d.ipfsDir != "" {
+		_ = os.RemoveAll(d.ipfsDir)
+	}
+	if d.backend != nil {
+		_ = d.backend.Close()
+	}
+}
+
+// Client returns a client of the simulated chain
+func (d *Devnet) Client() simulated.Client {
+	return d.backend.Client()
+}
+
+// ChainID is the simulated chain's ID
+func (d *Devnet) ChainID() int64 {
+	return params.AllDevChainProtocolChanges.ChainID.Int64()
+}
+
+// Now is the timestamp of the simulated chain's head
+func (d *Devnet) Now() time.Time {
+	header, err := d.Client().HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		d.logger.Error("Failed to get head header", "error", err)
+		return time.Time{}
+	}
+	return time.Unix(int64(header.Time), 0).UTC()
+}
+
+// receiptBackend exposes the receipt methods of the ethclient behind the simulated client
+func (d *Devnet) receiptBackend() proof.ReceiptBackend {
+	return d.Client().(proof.ReceiptBackend)
+}
+
+// transact sends a transaction, mines it and returns its receipt. A zero to address deploys data.
+func (d *Devnet) transact(ctx context.Context, key *ecdsa.PrivateKey, to common.Address, value *big.Int, data []byte) (*ethtypes.Receipt, error) {
+	d.mineMu.Lock()
+	defer d.mineMu.Unlock()
+
+	client := d.Client()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head header: %w", err)
+	}
+	tip := big.NewInt(params.GWei)
+
+	txData := &ethtypes.DynamicFeeTx{
+		ChainID:   big.NewInt(d.ChainID()),
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip),
+		Gas:       keeperActionGas,
+		Value:     value,
+		Data:      data,
+	}
+	if to != (common.Address{}) {
+		txData.To = &to
+	}
+	tx, err := ethtypes.SignNewTx(key, ethtypes.LatestSignerForChainID(big.NewInt(d.ChainID())), txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	d.backend.Commit()
+
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+	return receipt, nil
+}
@@ -0,0 +1,95 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trigg3rX/triggerx-backend/internal/localaggregator"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// testDevnet is shared by the tests, the services boot once per process
+var testDevnet *Devnet
+
+func TestMain(m *testing.M) {
+	d, err := New(logging.NewNoOpLogger(), Config{Keepers: 3})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start devnet: %v\n", err)
+		os.Exit(1)
+	}
+	testDevnet = d
+
+	code := m.Run()
+	d.Close()
+	os.Exit(code)
+}
+
+func TestNew_RefusesSecondDevnet(t *testing.T) {
+	_, err := New(logging.NewNoOpLogger(), Config{})
+	assert.ErrorContains(t, err, "already started")
+}
+
+func TestTimeJobScenario(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	require.NoError(t, TimeJobScenario().Run(ctx, testDevnet))
+
+	// Attesters are every keeper but the performer, all registered through dbserver
+	tasks, err := testDevnet.Tasks(ctx, "counter")
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	keeperIDs := make(map[int64]bool)
+	for _, keeper := range testDevnet.Keepers {
+		keeperIDs[keeper.KeeperID()] = true
+	}
+	for _, task := range tasks {
+		assert.True(t, task.IsAccepted)
+		assert.NotEmpty(t, task.ExecutionTxHash)
+		assert.True(t, keeperIDs[task.TaskPerformerID], "performer %d is not a devnet keeper", task.TaskPerformerID)
+	}
+
+	logs, err := testDevnet.AttestationChain.EventLogs(localaggregator.EventTaskSubmitted)
+	require.NoError(t, err)
+	assert.Len(t, logs, 2)
+}
+
+func TestScenario_ReportsFailingStep(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	scenario := Scenario{
+		Name: "failing",
+		Steps: []Step{
+			CreateTimeJob("failing", 30*time.Minute, 1),
+			ExpectCounter("failing", 1),
+		},
+	}
+
+	err := scenario.Run(ctx, testDevnet)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "step 2 (expect counter failing at 1)")
+	assert.Contains(t, err.Error(), "counter failing is 0, expected 1")
+}
+
+func TestCreateTimeJob_Validation(t *testing.T) {
+	ctx := context.Background()
+	target, err := testDevnet.DeployCounter(ctx, "validation")
+	require.NoError(t, err)
+
+	_, err = testDevnet.CreateTimeJob(ctx, "validation", target, 0, 1)
+	assert.Error(t, err)
+	_, err = testDevnet.CreateTimeJob(ctx, "validation", target, 30*time.Minute, 0)
+	assert.Error(t, err)
+
+	_, err = testDevnet.CreateTimeJob(ctx, "validation", target, 30*time.Minute, 1)
+	require.NoError(t, err)
+	_, err = testDevnet.CreateTimeJob(ctx, "validation", target, 30*time.Minute, 1)
+	assert.ErrorContains(t, err, "already exists")
+}
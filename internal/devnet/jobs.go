@@ -0,0 +1,231 @@
+package devnet
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/auth"
+	dbserverconfig "github.com/trigg3rX/triggerx-backend/internal/dbserver/config"
+	dbservertypes "github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
+)
+
+const (
+	timeJobTaskDefinitionID = 1
+	// sessionTTL is how long the devnet user's sign-in message is valid
+	sessionTTL = 24 * time.Hour
+)
+
+// errNotFound is returned for requests dbserver answers with 404
+var errNotFound = errors.New("not found")
+
+// user is the devnet's job owner, logged in to dbserver with a signed sign-in message
+type user struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+	token   string
+}
+
+func newUser() (*user, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user key: %w", err)
+	}
+	return &user{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+// login signs in to dbserver the way the app does: fetch a nonce, sign a sign-in message
+// with it and exchange the signature for a session token
+func (u *user) login(ctx context.Context, dbserverURL string, chainID int64) error {
+	var nonce struct {
+		Nonce string `json:"nonce"`
+	}
+	if err := getJSON(ctx, dbserverURL+"/api/auth/nonce", &nonce); err != nil {
+		return fmt.Errorf("failed to get sign-in nonce: %w", err)
+	}
+
+	issuedAt := time.Now().UTC()
+	expiresAt := issuedAt.Add(sessionTTL)
+	message := &auth.Message{
+		Domain:         dbserverconfig.GetSIWEDomain(),
+		Address:        u.address.Hex(),
+		Statement:      "Sign in to the TriggerX devnet",
+		URI:            "https://" + dbserverconfig.GetSIWEDomain(),
+		Version:        "1",
+		ChainID:        strconv.FormatInt(chainID, 10),
+		Nonce:          nonce.Nonce,
+		IssuedAt:       issuedAt,
+		ExpirationTime: &expiresAt,
+	}
+	text := message.String()
+	signature, err := cryptography.SignMessage(text, hex.EncodeToString(crypto.FromECDSA(u.key)))
+	if err != nil {
+		return fmt.Errorf("failed to sign sign-in message: %w", err)
+	}
+
+	var session auth.Session
+	if err := postJSON(ctx, dbserverURL+"/api/auth/login", "", dbservertypes.WalletLoginRequest{
+		Message:   text,
+		Signature: signature,
+	}, &session); err != nil {
+		return fmt.Errorf("failed to sign in: %w", err)
+	}
+	u.token = session.Token
+	return nil
+}
+
+// TimeJob is an interval job on a devnet contract, created through dbserver's job API
+type TimeJob struct {
+	JobID    int64
+	Target   common.Address
+	Interval time.Duration
+	Runs     int
+}
+
+// CreateTimeJob creates a job calling increment on target every interval, starting one
+// interval from now, as the devnet user and registers it under name. The job's time frame
+// ends half an interval after its last run, dbserver completes it then.
+func (d *Devnet) CreateTimeJob(ctx context.Context, name string, target common.Address, interval time.Duration, runs int) (*TimeJob, error) {
+	if interval < 2*time.Second || interval%time.Second != 0 {
+		return nil, fmt.Errorf("interval must be a whole number of seconds, at least 2")
+	}
+	if runs < 1 {
+		return nil, fmt.Errorf("a job runs at least once")
+	}
+
+	d.mu.Lock()
+	if _, exists := d.jobs[name]; exists {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("job %s already exists", name)
+	}
+	job := &TimeJob{
+		JobID:    d.nextJobID,
+		Target:   target,
+		Interval: interval,
+		Runs:     runs,
+	}
+	d.nextJobID++
+	d.jobs[name] = job
+	d.mu.Unlock()
+
+	chainID := strconv.FormatInt(d.ChainID(), 10)
+	address := strings.ToLower(d.user.address.Hex())
+	if err := postJSON(ctx, d.DBServerURL+"/api/jobs", d.user.token, []dbservertypes.CreateJobData{{
+		JobID:                 strconv.FormatInt(job.JobID, 10),
+		UserAddress:           address,
+		EtherBalance:          big.NewInt(0),
+		TokenBalance:          big.NewInt(0),
+		JobTitle:              name,
+		TaskDefinitionID:      timeJobTaskDefinitionID,
+		TimeFrame:             int64((time.Duration(runs)*interval + interval/2) / time.Second),
+		Recurring:             true,
+		JobCostPrediction:     0.1,
+		Timezone:              "UTC",
+		CreatedChainID:        chainID,
+		ScheduleType:          "interval",
+		TimeInterval:          int64(interval / time.Second),
+		TargetChainID:         chainID,
+		TargetContractAddress: target.Hex(),
+		TargetFunction:        "increment",
+		ABI:                   CounterABI,
+		ArgType:               1,
+		Arguments:             []string{},
+	}}, nil); err != nil {
+		d.mu.Lock()
+		delete(d.jobs, name)
+		d.mu.Unlock()
+		return nil, fmt.Errorf("failed to create job %s: %w", name, err)
+	}
+	d.logger.Info("Time job created", "name", name, "job_id", job.JobID, "interval", interval, "runs", runs)
+	return job, nil
+}
+
+// Job returns the job registered under name
+func (d *Devnet) Job(name string) (*TimeJob, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	job, ok := d.jobs[name]
+	return job, ok
+}
+
+// Tasks returns the tasks dbserver recorded for the job registered under name
+func (d *Devnet) Tasks(ctx context.Context, name string) ([]dbservertypes.TasksByJobIDResponse, error) {
+	job, ok := d.Job(name)
+	if !ok {
+		return nil, fmt.Errorf("no job named %s", name)
+	}
+	var page dbservertypes.TasksByJobGroupResponse
+	err := getJSON(ctx, fmt.Sprintf("%s/api/tasks/job/%d", d.DBServerURL, job.JobID), &page)
+	if errors.Is(err, errNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return page.Tasks, nil
+}
+
+// postJSON posts body to url and decodes a 2xx response into out, with token as bearer if set
+func postJSON(ctx context.Context, url, token string, body, out interface{}) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return doJSON(req, out)
+}
+
+// getJSON gets url and decodes a 2xx response into out
+func getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	return doJSON(req, out)
+}
+
+func doJSON(req *http.Request, out interface{}) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", req.Method, req.URL.Path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(resp.Body); err != nil {
+		return fmt.Errorf("failed to read %s response: %w", req.URL.Path, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, errNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s returned %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(body.String()))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body.Bytes(), out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", req.URL.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+package devnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/trigg3rX/triggerx-backend/internal/localaggregator"
+	"github.com/trigg3rX/triggerx-backend/pkg/client/aggregator"
+	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
+	"github.com/trigg3rX/triggerx-backend/pkg/ipfs"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/proof"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+const (
+	keeperActionGas = 200_000
+
+	// keeperVersion is the keeper release the devnet keepers check in as
+	keeperVersion = "1.0.1"
+	// checkInInterval is how often keepers check in with health, as the keeper does
+	checkInInterval = time.Minute
+	// timeJobExecutionLead is how long before a time job's trigger the keeper executes it
+	timeJobExecutionLead = 4 * time.Second
+)
+
+// Keeper is a devnet keeper. It registers with dbserver, checks in with health, and serves the
+// /p2p/message and /task/validate endpoints of the keeper API: as performer it executes the
+// action through the TaskExecutionHub, proves the receipt, uploads the result and submits it to
+// the aggregator, as attester it verifies those results. The real keeper runs actions in docker.
+type Keeper struct {
+	logger  logging.Logger
+	devnet  *Devnet
+	name    string
+	key     *ecdsa.PrivateKey
+	address common.Address
+	client  *aggregator.AggregatorClient
+	ipfs    ipfs.IPFSClient
+	server  *http.Server
+	url     string
+
+	// keeperID is the ID dbserver registered the keeper under, its operator and attester ID
+	keeperID int64
+	// hub is the TaskExecutionHub health hands out on check-in
+	hub common.Address
+
+	stopCheckIn context.CancelFunc
+	checkInDone sync.WaitGroup
+}
+
+func newKeeper(d *Devnet, index int, key *ecdsa.PrivateKey) (*Keeper, error) {
+	k := &Keeper{
+		logger:  d.logger.With("keeper", index),
+		devnet:  d,
+		name:    fmt.Sprintf("devnet-keeper-%d", index),
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+
+	var err error
+	if k.ipfs, err = ipfs.NewLocalClient(ipfs.LocalConfig{Dir: d.ipfsDir}, k.logger); err != nil {
+		return nil, fmt.Errorf("failed to create IPFS client: %w", err)
+	}
+	k.client, err = aggregator.NewAggregatorClient(k.logger, aggregator.AggregatorClientConfig{
+		AggregatorRPCUrl: d.AggregatorURL,
+		SenderPrivateKey: k.privateKeyHex(),
+		SenderAddress:    k.address.Hex(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aggregator client: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		k.client.Close()
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/p2p/message", k.handleMessage)
+	mux.HandleFunc("/task/validate", k.handleValidate)
+	k.url = "http://" + listener.Addr().String()
+	k.server = d.serveHTTP(k.name, listener, mux)
+	return k, nil
+}
+
+// Address is the keeper's address, it executes, signs and checks in with it
+func (k *Keeper) Address() common.Address {
+	return k.address
+}
+
+// KeeperID is the ID dbserver registered the keeper under
+func (k *Keeper) KeeperID() int64 {
+	return k.keeperID
+}
+
+func (k *Keeper) privateKeyHex() string {
+	return hex.EncodeToString(crypto.FromECDSA(k.key))
+}
+
+func (k *Keeper) registration() localaggregator.Keeper {
+	return localaggregator.Keeper{
+		AttesterID: k.keeperID,
+		Address:    k.address.Hex(),
+		URL:        k.url,
+	}
+}
+
+// register creates the keeper through dbserver's keeper API and marks it registered the way
+// the on-chain registration listener does, with its keeper ID as operator ID
+func (k *Keeper) register(ctx context.Context) error {
+	// Health looks keepers up by the address as stored, which it lowercases on check-in
+	address := strings.ToLower(k.address.Hex())
+	var created struct {
+		KeeperID int64 `json:"keeper_id"`
+	}
+	if err := postJSON(ctx, k.devnet.DBServerURL+"/api/keepers", "", map[string]string{
+		"keeper_name":    k.name,
+		"keeper_address": address,
+		"email_id":       k.name + "@devnet.local",
+	}, &created); err != nil {
+		return err
+	}
+	k.keeperID = created.KeeperID
+
+	if err := k.devnet.conn.NewQuery(`
+		UPDATE triggerx.keeper_data SET registered = true, operator_id = ?, consensus_address = ?, version = ?, on_imua = false
+		WHERE keeper_id = ?`,
+		k.keeperID, address, keeperVersion, k.keeperID).Exec(); err != nil {
+		return fmt.Errorf("failed to mark keeper registered: %w", err)
+	}
+	k.logger.Info("Keeper registered", "keeper_id", k.keeperID, "address", address)
+	return nil
+}
+
+// start checks the keeper in with health and keeps checking in every checkInInterval
+func (k *Keeper) start(ctx context.Context) error {
+	hub, err := k.checkIn(ctx)
+	if err != nil {
+		return err
+	}
+	k.hub = hub
+
+	checkInCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	k.stopCheckIn = cancel
+	k.checkInDone.Add(1)
+	go func() {
+		defer k.checkInDone.Done()
+		ticker := time.NewTicker(checkInInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-checkInCtx.Done():
+				return
+			case <-ticker.C:
+				if _, err := k.checkIn(checkInCtx); err != nil {
+					k.logger.Warn("Health check-in failed", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// checkIn sends a signed check-in to health and returns the TaskExecutionHub from its response
+func (k *Keeper) checkIn(ctx context.Context) (common.Address, error) {
+	checkIn := types.KeeperHealthCheckIn{
+		KeeperAddress:    k.address.Hex(),
+		ConsensusPubKey:  hex.EncodeToString(crypto.FromECDSAPub(&k.key.PublicKey)),
+		ConsensusAddress: k.address.Hex(),
+		Version:          keeperVersion,
+		Timestamp:        time.Now().UTC(),
+		PeerID:           k.name,
+	}
+	signature, err := cryptography.SignTypedData(checkIn, cryptography.NewTypedDataDomain(0), k.privateKeyHex())
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to sign check-in: %w", err)
+	}
+	checkIn.Signature = signature
+
+	var response types.KeeperHealthCheckInResponse
+	if err := postJSON(ctx, k.devnet.HealthURL+"/health", "", checkIn, &response); err != nil {
+		return common.Address{}, err
+	}
+	message, err := cryptography.DecryptMessage(k.privateKeyHex(), response.Data)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to decrypt check-in response: %w", err)
+	}
+	// etherscan key, alchemy key, pinata host, pinata JWT, manager, task execution hub
+	fields := strings.Split(message, ":")
+	if len(fields) != 6 || !common.IsHexAddress(fields[5]) {
+		return common.Address{}, fmt.Errorf("unexpected check-in response")
+	}
+	return common.HexToAddress(fields[5]), nil
+}
+
+func (k *Keeper) close(ctx context.Context) {
+	if k.stopCheckIn != nil {
+		k.stopCheckIn()
+		k.checkInDone.Wait()
+	}
+	k.client.Close()
+	_ = k.ipfs.Close()
+	_ = k.server.Shutdown(ctx)
+}
+
+func (k *Keeper) handleMessage(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Data string `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(body.Data, "0x"))
+	if err != nil {
+		http.Error(w, "invalid hex data", http.StatusBadRequest)
+		return
+	}
+	var task types.SendTaskDataToKeeper
+	if err := json.Unmarshal(decoded, &task); err != nil {
+		http.Error(w, "failed to parse task data", http.StatusBadRequest)
+		return
+	}
+
+	if !strings.EqualFold(task.PerformerData.KeeperAddress, k.address.Hex()) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if err := k.perform(r.Context(), &task); err != nil {
+		k.logger.Error("Task execution failed", "task_id", task.TaskID, "error", err)
+		http.Error(w, "task execution failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// perform executes the task's action through the TaskExecutionHub, uploads the signed,
+// receipt-proven result and sends it to the aggregator for attestation
+func (k *Keeper) perform(ctx context.Context, task *types.SendTaskDataToKeeper) error {
+	if err := verifyManagerSignature(task, k.devnet.managerAddress.Hex()); err != nil {
+		return err
+	}
+	if len(task.TriggerData) == 0 {
+		return fmt.Errorf("trigger data is missing")
+	}
+
+	target := task.TargetData[0]
+	if target.TaskDefinitionID == timeJobTaskDefinitionID {
+		// Time jobs are sent ahead of their trigger, the keeper holds them until just before it
+		select {
+		case <-time.After(time.Until(task.TriggerData[0].NextTriggerTimestamp.Add(-timeJobExecutionLead))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	data, err := executionData(target)
+	if err != nil {
+		return err
+	}
+	receipt, err := k.devnet.transact(ctx, k.key, k.hub, nil, data)
+	if err != nil {
+		return fmt.Errorf("failed to execute action: %w", err)
+	}
+
+	ipfsData := types.IPFSData{
+		TaskData: task,
+		ActionData: &types.PerformerActionData{
+			TaskID:             target.TaskID,
+			ActionTxHash:       receipt.TxHash.Hex(),
+			GasUsed:            strconv.FormatUint(receipt.GasUsed, 10),
+			Status:             receipt.Status == ethtypes.ReceiptStatusSuccessful,
+			TotalFee:           new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice),
+			ExecutionTimestamp: k.devnet.Now(),
+		},
+	}
+	proofData, err := proof.GenerateReceiptProof(ctx, k.devnet.receiptBackend(), ipfsData)
+	if err != nil {
+		return fmt.Errorf("failed to generate receipt proof: %w", err)
+	}
+	ipfsData.ProofData = &proofData
+	ipfsData.PerformerSignature = &types.PerformerSignatureData{
+		TaskID:                  target.TaskID,
+		PerformerSigningAddress: k.address.Hex(),
+	}
+
+	signature, err := cryptography.SignStructured(ipfsData, task.SigningChainID(), k.privateKeyHex(), cryptography.SignatureFormatEIP712)
+	if err != nil {
+		return fmt.Errorf("failed to sign IPFS data: %w", err)
+	}
+	ipfsData.PerformerSignature.PerformerSignature = signature
+
+	encoded, err := json.Marshal(ipfsData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal IPFS data: %w", err)
+	}
+	cid, err := k.ipfs.Upload(ctx, fmt.Sprintf("proof_of_task_%d.json", target.TaskID), encoded)
+	if err != nil {
+		return fmt.Errorf("failed to upload IPFS data: %w", err)
+	}
+
+	if _, err := k.client.SendTaskToValidators(ctx, &types.BroadcastDataForValidators{
+		ProofOfTask:      proofData.ProofOfTask,
+		Data:             []byte(cid),
+		TaskDefinitionID: target.TaskDefinitionID,
+		PerformerAddress: k.address.Hex(),
+	}); err != nil {
+		return fmt.Errorf("failed to send task result to aggregator: %w", err)
+	}
+	k.logger.Info("Task performed", "task_id", target.TaskID, "tx_hash", receipt.TxHash.Hex(), "cid", cid)
+	return nil
+}
+
+// executionData encodes the TaskExecutionHub call running the target function of a job with
+// static arguments
+func executionData(target types.TaskTargetData) ([]byte, error) {
+	if target.JobID == nil {
+		return nil, fmt.Errorf("job ID is missing")
+	}
+	targetABI, err := abi.JSON(strings.NewReader(target.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("invalid target ABI: %w", err)
+	}
+	if len(target.Arguments) > 0 {
+		return nil, fmt.Errorf("devnet keepers only call functions without arguments")
+	}
+	calldata, err := targetABI.Pack(target.TargetFunction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", target.TargetFunction, err)
+	}
+	return taskExecutionHub.Pack("executeFunction", target.JobID.Int, big.NewInt(0), common.HexToAddress(target.TargetContractAddress), calldata)
+}
+
+func (k *Keeper) handleValidate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProofOfTask string `json:"proofOfTask"`
+		Data        string `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	response := struct {
+		Data    bool   `json:"data"`
+		Error   bool   `json:"error"`
+		Message string `json:"message,omitempty"`
+	}{Data: true}
+	if err := k.validate(r.Context(), req.ProofOfTask, req.Data); err != nil {
+		k.logger.Warn("Task validation failed", "error", err)
+		response.Data, response.Error, response.Message = false, true, err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// validate checks a performer's result the way the keeper validator does: both signatures,
+// the receipt proof against the simulated chain and the submitted proof of task
+func (k *Keeper) validate(ctx context.Context, proofOfTask string, data string) error {
+	cid, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid task data: %w", err)
+	}
+	ipfsData, err := k.ipfs.Fetch(ctx, string(cid))
+	if err != nil {
+		return err
+	}
+	if err := verifyManagerSignature(ipfsData.TaskData, k.devnet.managerAddress.Hex()); err != nil {
+		return err
+	}
+	if ipfsData.PerformerSignature == nil {
+		return fmt.Errorf("performer signature is missing")
+	}
+
+	signed := ipfsData
+	signed.PerformerSignature = &types.PerformerSignatureData{
+		TaskID:                  ipfsData.PerformerSignature.TaskID,
+		PerformerSigningAddress: ipfsData.PerformerSignature.PerformerSigningAddress,
+	}
+	// Devnet services only sign EIP-712
+	format, err := cryptography.VerifyStructured(signed, ipfsData.TaskData.SigningChainID(), ipfsData.PerformerSignature.PerformerSignature, ipfsData.PerformerSignature.PerformerSigningAddress, false)
+	if err != nil {
+		return fmt.Errorf("failed to verify performer signature: %w", err)
+	}
+	if format == "" {
+		return fmt.Errorf("performer signature verification failed")
+	}
+
+	if err := proof.VerifyReceiptProof(ctx, k.devnet.Client(), ipfsData); err != nil {
+		return fmt.Errorf("invalid receipt proof: %w", err)
+	}
+	if ipfsData.ProofData.ProofOfTask != proofOfTask {
+		return fmt.Errorf("proof of task does not match the uploaded result")
+	}
+	return nil
+}
+
+func verifyManagerSignature(task *types.SendTaskDataToKeeper, managerAddress string) error {
+	if task == nil || len(task.TargetData) == 0 {
+		return fmt.Errorf("task data is missing")
+	}
+	unsigned := *task
+	unsigned.ManagerSignature = ""
+	format, err := cryptography.VerifyStructured(unsigned, task.SigningChainID(), task.ManagerSignature, managerAddress, false)
+	if err != nil {
+		return fmt.Errorf("failed to verify manager signature: %w", err)
+	}
+	if format == "" {
+		return fmt.Errorf("manager signature verification failed")
+	}
+	return nil
+}
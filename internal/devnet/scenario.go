@@ -0,0 +1,209 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/trigg3rX/triggerx-backend/internal/localaggregator"
+)
+
+const (
+	// expectTimeout bounds how long an expectation waits for the services to catch up
+	expectTimeout = 45 * time.Second
+	// expectPollInterval is how often an expectation re-checks
+	expectPollInterval = 500 * time.Millisecond
+)
+
+// Step is one action or assertion of a scenario
+type Step struct {
+	Name string
+	Run  func(ctx context.Context, d *Devnet) error
+}
+
+// Scenario is a script of steps run in order against a devnet
+type Scenario struct {
+	Name  string
+	Steps []Step
+}
+
+// Run runs the steps in order and stops at the first failing one
+func (s Scenario) Run(ctx context.Context, d *Devnet) error {
+	for i, step := range s.Steps {
+		d.logger.Info("Running scenario step", "scenario", s.Name, "step", i+1, "name", step.Name)
+		if err := step.Run(ctx, d); err != nil {
+			return fmt.Errorf("scenario %s, step %d (%s): %w", s.Name, i+1, step.Name, err)
+		}
+	}
+	return nil
+}
+
+// CreateTimeJob deploys a counter contract and creates a time job calling it every interval
+// for runs runs, both registered under name
+func CreateTimeJob(name string, interval time.Duration, runs int) Step {
+	return Step{
+		Name: fmt.Sprintf("create time job %s running %d times every %s", name, runs, interval),
+		Run: func(ctx context.Context, d *Devnet) error {
+			target, err := d.DeployCounter(ctx, name)
+			if err != nil {
+				return err
+			}
+			_, err = d.CreateTimeJob(ctx, name, target, interval, runs)
+			return err
+		},
+	}
+}
+
+// Wait lets the services run for duration. The services keep wall clock time, the devnet
+// can not move it forward.
+func Wait(duration time.Duration) Step {
+	return Step{
+		Name: fmt.Sprintf("wait %s", duration),
+		Run: func(ctx context.Context, d *Devnet) error {
+			select {
+			case <-time.After(duration):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// ExpectCounter asserts the on-chain effect: the counter registered under name reaches want
+// calls, and not more
+func ExpectCounter(name string, want uint64) Step {
+	return Step{
+		Name: fmt.Sprintf("expect counter %s at %d", name, want),
+		Run: func(ctx context.Context, d *Devnet) error {
+			address, ok := d.Contract(name)
+			if !ok {
+				return fmt.Errorf("no contract named %s", name)
+			}
+			return eventually(ctx, func() (bool, error) {
+				got, err := d.CounterValue(ctx, address)
+				if err != nil {
+					return false, err
+				}
+				if got > want {
+					return true, fmt.Errorf("counter %s is %d, expected %d", name, got, want)
+				}
+				if got < want {
+					return false, fmt.Errorf("counter %s is %d, expected %d", name, got, want)
+				}
+				return true, nil
+			})
+		},
+	}
+}
+
+// ExpectTaskEvents asserts the attestation chain reaches want TaskSubmitted or TaskRejected
+// events, and not more
+func ExpectTaskEvents(eventName string, want int) Step {
+	return Step{
+		Name: fmt.Sprintf("expect %d %s events", want, eventName),
+		Run: func(ctx context.Context, d *Devnet) error {
+			return eventually(ctx, func() (bool, error) {
+				got, err := d.TaskEvents(eventName)
+				if err != nil {
+					return false, err
+				}
+				if got != want {
+					return got > want, fmt.Errorf("found %d %s events, expected %d", got, eventName, want)
+				}
+				return true, nil
+			})
+		},
+	}
+}
+
+// ExpectTaskStatus asserts dbserver reaches want tasks with status for the job registered
+// under name, as taskmonitor records them from the attestation chain
+func ExpectTaskStatus(name, status string, want int) Step {
+	return Step{
+		Name: fmt.Sprintf("expect %d %s tasks for job %s", want, status, name),
+		Run: func(ctx context.Context, d *Devnet) error {
+			return eventually(ctx, func() (bool, error) {
+				tasks, err := d.Tasks(ctx, name)
+				if err != nil {
+					return false, err
+				}
+				got := 0
+				for _, task := range tasks {
+					if task.TaskStatus == status {
+						got++
+					}
+				}
+				if got != want {
+					return got > want, fmt.Errorf("job %s has %d %s tasks, expected %d", name, got, status, want)
+				}
+				return true, nil
+			})
+		},
+	}
+}
+
+// eventually runs check until it reports done or expectTimeout passes, returning its last error
+func eventually(ctx context.Context, check func() (bool, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, expectTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(expectPollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := check()
+		if done {
+			return err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// TaskEvents counts the TaskSubmitted or TaskRejected events emitted on the attestation chain
+func (d *Devnet) TaskEvents(eventName string) (int, error) {
+	logs, err := d.AttestationChain.EventLogs(eventName)
+	if err != nil {
+		return 0, err
+	}
+	return len(logs), nil
+}
+
+// TimeJobScenario creates a time job running twice, ten seconds apart, and follows both runs
+// from the time scheduler through the dispatcher, the keepers and the aggregator to taskmonitor
+func TimeJobScenario() Scenario {
+	return Scenario{
+		Name: "time-job",
+		Steps: []Step{
+			CreateTimeJob("counter", 10*time.Second, 2),
+			ExpectCounter("counter", 0),
+			ExpectCounter("counter", 1),
+			ExpectTaskEvents(localaggregator.EventTaskSubmitted, 1),
+			ExpectCounter("counter", 2),
+			ExpectTaskEvents(localaggregator.EventTaskSubmitted, 2),
+			ExpectTaskEvents(localaggregator.EventTaskRejected, 0),
+			ExpectTaskStatus("counter", "completed", 2),
+			Wait(15 * time.Second),
+			ExpectCounter("counter", 2),
+		},
+	}
+}
+
+// Scenarios are the scripts cmd/devnet can run by name
+var Scenarios = map[string]func() Scenario{
+	"time-job": TimeJobScenario,
+}
+
+// ScenarioNames lists the registered scenarios
+func ScenarioNames() []string {
+	names := make([]string, 0, len(Scenarios))
+	for name := range Scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
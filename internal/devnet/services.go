@@ -0,0 +1,548 @@
+package devnet
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+
+	dbserver "github.com/trigg3rX/triggerx-backend/internal/dbserver"
+	dbserverconfig "github.com/trigg3rX/triggerx-backend/internal/dbserver/config"
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/migrations"
+	eventmonitorapi "github.com/trigg3rX/triggerx-backend/internal/eventmonitor/api"
+	eventmonitorconfig "github.com/trigg3rX/triggerx-backend/internal/eventmonitor/config"
+	eventmonitorservice "github.com/trigg3rX/triggerx-backend/internal/eventmonitor/service"
+	"github.com/trigg3rX/triggerx-backend/internal/health"
+	healthclient "github.com/trigg3rX/triggerx-backend/internal/health/client"
+	healthconfig "github.com/trigg3rX/triggerx-backend/internal/health/config"
+	healthkeeper "github.com/trigg3rX/triggerx-backend/internal/health/keeper"
+	conditionapi "github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/api"
+	conditionconfig "github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/config"
+	conditionmetrics "github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/metrics"
+	conditionscheduler "github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/scheduler"
+	timeapi "github.com/trigg3rX/triggerx-backend/internal/schedulers/time/api"
+	timeconfig "github.com/trigg3rX/triggerx-backend/internal/schedulers/time/config"
+	timescheduler "github.com/trigg3rX/triggerx-backend/internal/schedulers/time/scheduler"
+	"github.com/trigg3rX/triggerx-backend/internal/taskdispatcher"
+	taskdispatcherconfig "github.com/trigg3rX/triggerx-backend/internal/taskdispatcher/config"
+	taskdispatchermetrics "github.com/trigg3rX/triggerx-backend/internal/taskdispatcher/metrics"
+	taskdispatcherrpc "github.com/trigg3rX/triggerx-backend/internal/taskdispatcher/rpc"
+	taskdispatchertasks "github.com/trigg3rX/triggerx-backend/internal/taskdispatcher/tasks"
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor"
+	taskmonitorconfig "github.com/trigg3rX/triggerx-backend/internal/taskmonitor/config"
+	taskmonitorrpc "github.com/trigg3rX/triggerx-backend/internal/taskmonitor/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/client/aggregator"
+	dbserverclient "github.com/trigg3rX/triggerx-backend/pkg/client/dbserver"
+	"github.com/trigg3rX/triggerx-backend/pkg/client/redis"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+	"github.com/trigg3rX/triggerx-backend/pkg/ipfs"
+	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+	rpcserver "github.com/trigg3rX/triggerx-backend/pkg/rpc/server"
+)
+
+const (
+	// timeSchedulerPollingInterval and timeSchedulerLookAhead let time jobs run seconds after
+	// they are due instead of the production minutes
+	timeSchedulerPollingInterval = time.Second
+	timeSchedulerLookAhead       = 2 * time.Second
+
+	// devnetAPIKey fills the third party API keys the services refuse to start without, the
+	// devnet never calls those APIs
+	devnetAPIKey = "devnet"
+)
+
+// service is a TriggerX service booted in the devnet process, stopped in reverse boot order
+type service struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// servicePorts are the ports the services listen on, picked before any of them boots since
+// they are configured with each other's URLs
+type servicePorts struct {
+	dbserver           net.Listener
+	health             net.Listener
+	taskDispatcher     net.Listener
+	traces             net.Listener
+	taskMonitor        string
+	timeScheduler      string
+	conditionScheduler string
+	eventMonitor       string
+}
+
+func newServicePorts() (*servicePorts, error) {
+	ports := &servicePorts{}
+	var err error
+	for _, listener := range []*net.Listener{&ports.dbserver, &ports.health, &ports.taskDispatcher, &ports.traces} {
+		if *listener, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+			ports.close()
+			return nil, fmt.Errorf("failed to listen: %w", err)
+		}
+	}
+	for _, port := range []*string{&ports.taskMonitor, &ports.timeScheduler, &ports.conditionScheduler, &ports.eventMonitor} {
+		if *port, err = freePort(); err != nil {
+			ports.close()
+			return nil, err
+		}
+	}
+	return ports, nil
+}
+
+// close releases the listeners no service took over
+func (p *servicePorts) close() {
+	for _, listener := range []net.Listener{p.dbserver, p.health, p.taskDispatcher, p.traces} {
+		if listener != nil {
+			_ = listener.Close()
+		}
+	}
+}
+
+// freePort returns a port nothing listens on, for the services opening their own listener
+func freePort() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to find a free port: %w", err)
+	}
+	defer func() { _ = listener.Close() }()
+	return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port), nil
+}
+
+func listenerPort(listener net.Listener) string {
+	return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func localURL(port string) string {
+	return "http://127.0.0.1:" + port
+}
+
+// setEnv sets the environment a service's config is loaded from. The services read their
+// config from process-wide variables, each one is loaded right after its own are set.
+func setEnv(vars map[string]string) error {
+	for key, value := range vars {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// setSharedEnv sets the environment every service reads, none of them calls out with service
+// tokens or certificates
+func (d *Devnet) setSharedEnv(ports *servicePorts) error {
+	d.DBServerURL = localURL(listenerPort(ports.dbserver))
+	d.HealthURL = localURL(listenerPort(ports.health))
+
+	return setEnv(map[string]string{
+		"DEV_MODE":                  "true",
+		"DATABASE_HOST_ADDRESS":     d.Database.Host(),
+		"DATABASE_HOST_PORT":        d.Database.Port(),
+		"UPSTASH_REDIS_URL":         "redis://" + d.Redis.Addr(),
+		"UPSTASH_REDIS_REST_TOKEN":  "",
+		"ALCHEMY_API_KEY":           devnetAPIKey,
+		"BOT_TOKEN":                 "",
+		"SERVICE_TOKEN_PRIVATE_KEY": "",
+		"SERVICE_TOKEN_PUBLIC_KEYS": "",
+		"RPC_TLS_CERT_FILE":         "",
+		"RPC_TLS_KEY_FILE":          "",
+		"RPC_TLS_CA_FILE":           "",
+		"DBSERVER_RPC_URL":          d.DBServerURL,
+		"AGGREGATOR_RPC_URL":        d.AggregatorURL,
+		"TASK_DISPATCHER_RPC_URL":   "127.0.0.1:" + listenerPort(ports.taskDispatcher),
+		"TEMPO_OTLP_ENDPOINT":       "127.0.0.1:" + listenerPort(ports.traces),
+	})
+}
+
+func (d *Devnet) startService(name string, start func() (func(ctx context.Context) error, error)) error {
+	stop, err := start()
+	if err != nil {
+		return fmt.Errorf("failed to start %s: %w", name, err)
+	}
+	d.services = append(d.services, service{name: name, stop: stop})
+	d.logger.Info("Service started", "service", name)
+	return nil
+}
+
+// stopServices stops the services in reverse boot order
+func (d *Devnet) stopServices(ctx context.Context) {
+	for i := len(d.services) - 1; i >= 0; i-- {
+		if err := d.services[i].stop(ctx); err != nil {
+			d.logger.Warn("Failed to stop service", "service", d.services[i].name, "error", err)
+		}
+	}
+	d.services = nil
+}
+
+func (d *Devnet) serveHTTP(name string, listener net.Listener, handler http.Handler) *http.Server {
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Error("HTTP server stopped", "service", name, "error", err)
+		}
+	}()
+	return srv
+}
+
+// startTraceSink accepts the traces dbserver and the schedulers export, in place of Tempo
+func (d *Devnet) startTraceSink(ports *servicePorts) (func(ctx context.Context) error, error) {
+	srv := d.serveHTTP("tempo", ports.traces, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	ports.traces = nil
+	return srv.Shutdown, nil
+}
+
+func (d *Devnet) startDBServer(ctx context.Context, ports *servicePorts) (func(ctx context.Context) error, error) {
+	if err := setEnv(map[string]string{
+		"DBSERVER_RPC_PORT":           listenerPort(ports.dbserver),
+		"TIME_SCHEDULER_RPC_URL":      localURL(ports.timeScheduler),
+		"CONDITION_SCHEDULER_RPC_URL": localURL(ports.conditionScheduler),
+		"FAUCET_PRIVATE_KEY":          hex.EncodeToString(crypto.FromECDSA(d.deployerKey)),
+		// Scenarios poll dbserver, every client of the devnet shares the loopback address
+		"DBSERVER_IP_RATE_LIMIT_PER_MINUTE": "6000",
+		"DBSERVER_IP_RATE_LIMIT_BURST":      "1000",
+		// Read in seconds here, as a duration by the time scheduler
+		"TIME_SCHEDULER_POLLING_LOOKAHEAD": strconv.Itoa(int(timeSchedulerLookAhead / time.Second)),
+	}); err != nil {
+		return nil, err
+	}
+	if err := dbserverconfig.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	logger := d.logger.With("service", "dbserver")
+
+	// The first connection of the process is the one every service shares
+	conn, err := database.NewConnection(&database.Config{
+		Hosts:       []string{dbserverconfig.GetDatabaseHostAddress() + ":" + dbserverconfig.GetDatabaseHostPort()},
+		Keyspace:    "triggerx",
+		Consistency: gocql.Quorum,
+		Timeout:     10 * time.Second,
+		Retries:     3,
+		ConnectWait: 5 * time.Second,
+		RetryConfig: retry.DefaultRetryConfig(),
+	}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the database: %w", err)
+	}
+	d.conn = conn
+
+	loaded, err := database.LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	if _, err := database.NewMigrator(conn.Session(), loaded, database.DefaultMigratorConfig(), logger).Up(ctx, false); err != nil {
+		return nil, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	// Without a docker executor code validation is unavailable, time jobs do not need it
+	s := dbserver.NewServer(conn, logger)
+	s.RegisterRoutes(s.GetRouter(), nil)
+	srv := d.serveHTTP("dbserver", ports.dbserver, s.GetRouter())
+	ports.dbserver = nil
+
+	return srv.Shutdown, nil
+}
+
+func (d *Devnet) startHealth(ports *servicePorts) (func(ctx context.Context) error, error) {
+	hub := d.TaskExecutionHub.Hex()
+	if err := setEnv(map[string]string{
+		"HEALTH_RPC_PORT":             listenerPort(ports.health),
+		"MANAGER_SIGNING_ADDRESS":     d.managerAddress.Hex(),
+		"TASK_EXECUTION_ADDRESS":      hub,
+		"TEST_TASK_EXECUTION_ADDRESS": hub,
+		"IMUA_TASK_EXECUTION_ADDRESS": hub,
+		"PINATA_HOST":                 devnetAPIKey,
+		"PINATA_JWT":                  devnetAPIKey,
+		"ETHERSCAN_API_KEY":           devnetAPIKey,
+	}); err != nil {
+		return nil, err
+	}
+	if err := healthconfig.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	logger := d.logger.With("service", "health")
+
+	// Devnet keepers have no Telegram chat, the bot is left out
+	healthclient.InitDatabaseManager(logger, d.conn, nil)
+	state := healthkeeper.InitializeStateManager(logger)
+	if err := state.LoadVerifiedKeepers(); err != nil {
+		return nil, fmt.Errorf("failed to load verified keepers: %w", err)
+	}
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(health.LoggerMiddleware(logger))
+	health.RegisterRoutes(router, logger)
+	srv := d.serveHTTP("health", ports.health, router)
+	ports.health = nil
+
+	return func(ctx context.Context) error {
+		if err := state.DumpState(); err != nil {
+			logger.Warn("Failed to dump keeper state", "error", err)
+		}
+		return srv.Shutdown(ctx)
+	}, nil
+}
+
+func (d *Devnet) startTaskMonitor(ctx context.Context, ports *servicePorts) (func(ctx context.Context) error, error) {
+	if err := setEnv(map[string]string{
+		"TASK_MONITOR_RPC_PORT":     ports.taskMonitor,
+		"ATTESTATION_CHAIN_RPC_URL": d.AggregatorURL + "/chain",
+		// The aggregator stand-in attests on the testnet listener's chain, the mainnet
+		// listener watches an address nothing emits from
+		"TEST_ATTESTATION_CENTER_ADDRESS": AttestationCenterAddress,
+		"ATTESTATION_CENTER_ADDRESS":      d.managerAddress.Hex(),
+		"IPFS_STORAGE_BACKENDS":           string(ipfs.BackendLocal),
+		"IPFS_LOCAL_DIR":                  d.ipfsDir,
+		"IPFS_RETENTION_ENABLED":          "false",
+	}); err != nil {
+		return nil, err
+	}
+	if err := taskmonitorconfig.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	logger := d.logger.With("service", "taskmonitor")
+
+	tm, err := taskmonitor.NewTaskManager(logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := tm.Initialize(); err != nil {
+		_ = tm.Close()
+		return nil, err
+	}
+	srv, err := taskmonitorrpc.StartRPCServer(ctx, logger, tm, "127.0.0.1", taskmonitorconfig.GetTaskMonitorRPCPort(), nil)
+	if err != nil {
+		_ = tm.Close()
+		return nil, err
+	}
+	tm.SetRPCServer(srv)
+
+	return func(ctx context.Context) error {
+		return errors.Join(srv.Stop(ctx), tm.Close())
+	}, nil
+}
+
+func (d *Devnet) startEventMonitor(ports *servicePorts) (func(ctx context.Context) error, error) {
+	if err := setEnv(map[string]string{
+		"EVENT_MONITOR_PORT": ports.eventMonitor,
+		"EVENT_MONITOR_HOST": "127.0.0.1",
+	}); err != nil {
+		return nil, err
+	}
+	if err := eventmonitorconfig.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	logger := d.logger.With("service", "eventmonitor")
+
+	svc, err := eventmonitorservice.NewService(logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.Start(); err != nil {
+		return nil, err
+	}
+	srv := eventmonitorapi.NewServer(eventmonitorapi.Config{
+		Port: eventmonitorconfig.GetPort(),
+	}, eventmonitorapi.Dependencies{
+		Logger:          logger,
+		RegistryManager: svc.GetRegistryManager(),
+		Service:         svc,
+	})
+	go func() {
+		if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("HTTP server stopped", "error", err)
+		}
+	}()
+
+	return func(ctx context.Context) error {
+		svc.Stop()
+		return srv.Stop(ctx)
+	}, nil
+}
+
+func (d *Devnet) startConditionScheduler(ctx context.Context, ports *servicePorts) (func(ctx context.Context) error, error) {
+	if err := setEnv(map[string]string{
+		"CONDITION_SCHEDULER_RPC_PORT":  ports.conditionScheduler,
+		"CONDITION_SCHEDULER_GRPC_PORT": "",
+		"EVENT_MONITOR_SERVICE_URL":     localURL(ports.eventMonitor),
+	}); err != nil {
+		return nil, err
+	}
+	if err := conditionconfig.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	conditionmetrics.StartMetricsCollection()
+	logger := d.logger.With("service", "condition-scheduler")
+
+	dbClient, err := dbserverclient.NewDBServerClient(logger, conditionconfig.GetDBServerURL())
+	if err != nil {
+		return nil, err
+	}
+	if err := dbClient.SetCredentials(dbserverclient.NewServiceCredentials("condition-scheduler", conditionconfig.GetServiceTokenKey(), conditionconfig.GetRPCTLSConfig())); err != nil {
+		return nil, err
+	}
+	sched, err := conditionscheduler.NewConditionBasedScheduler(fmt.Sprintf("condition-scheduler-%d", time.Now().Unix()), logger, dbClient)
+	if err != nil {
+		return nil, err
+	}
+	srv := conditionapi.NewServer(conditionapi.Config{
+		Port: conditionconfig.GetSchedulerRPCPort(),
+	}, conditionapi.Dependencies{
+		Logger:    logger,
+		Scheduler: sched,
+	})
+
+	schedCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	go sched.Start(schedCtx)
+	go func() {
+		if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("HTTP server stopped", "error", err)
+		}
+	}()
+
+	return func(ctx context.Context) error {
+		cancel()
+		sched.Stop()
+		dbClient.Close()
+		return srv.Stop(ctx)
+	}, nil
+}
+
+func (d *Devnet) startTaskDispatcher(ctx context.Context, ports *servicePorts) (func(ctx context.Context) error, error) {
+	if err := setEnv(map[string]string{
+		"TASK_DISPATCHER_RPC_PORT":        listenerPort(ports.taskDispatcher),
+		"HEALTH_RPC_URL":                  d.HealthURL,
+		"TEST_AGGREGATOR_RPC_URL":         d.AggregatorURL,
+		"TASK_DISPATCHER_SIGNING_KEY":     hex.EncodeToString(crypto.FromECDSA(d.managerKey)),
+		"TASK_DISPATCHER_SIGNING_ADDRESS": d.managerAddress.Hex(),
+		"SIGNATURE_FORMAT":                "eip712",
+	}); err != nil {
+		return nil, err
+	}
+	if err := taskdispatcherconfig.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	logger := d.logger.With("service", "taskdispatcher")
+
+	taskdispatchermetrics.NewCollector().Start()
+
+	redisClient, err := redis.NewRedisClient(logger, taskdispatcherconfig.GetRedisClientConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create redis client: %w", err)
+	}
+	if err := redisClient.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("redis is not reachable: %w", err)
+	}
+	redisClient.SetMonitoringHooks(taskdispatchermetrics.CreateRedisMonitoringHooks())
+
+	newAggregatorClient := func(url string) (*aggregator.AggregatorClient, error) {
+		return aggregator.NewAggregatorClient(logger, aggregator.AggregatorClientConfig{
+			AggregatorRPCUrl: url,
+			SenderPrivateKey: taskdispatcherconfig.GetTaskDispatcherSigningKey(),
+			SenderAddress:    taskdispatcherconfig.GetTaskDispatcherSigningAddress(),
+		})
+	}
+	aggClient, err := newAggregatorClient(taskdispatcherconfig.GetAggregatorRPCUrl())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aggregator client: %w", err)
+	}
+	testAggClient, err := newAggregatorClient(taskdispatcherconfig.GetTestAggregatorRPCUrl())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test aggregator client: %w", err)
+	}
+
+	taskStreamMgr, err := taskdispatchertasks.NewTaskStreamManager(redisClient, aggClient, testAggClient, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task stream manager: %w", err)
+	}
+	dispatcher, err := taskdispatcher.NewTaskDispatcher(
+		logger,
+		taskStreamMgr,
+		taskdispatcher.NewHealthClient(logger, taskdispatcherconfig.GetHealthRPCUrl()),
+		taskdispatcherconfig.GetTaskDispatcherSigningKey(),
+		taskdispatcherconfig.GetTaskDispatcherSigningAddress(),
+		taskdispatcherconfig.GetSignatureFormat(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task dispatcher: %w", err)
+	}
+
+	// Without service token keys or certificates the dev mode server is unauthenticated
+	srv := rpcserver.NewServer(rpcserver.Config{
+		Name:    "TaskDispatcher",
+		Version: "1.0.0",
+		Address: "127.0.0.1",
+		Port:    taskdispatcherconfig.GetTaskDispatcherRPCPort(),
+		TLS:     taskdispatcherconfig.GetRPCTLSConfig(),
+	}, logger)
+	srv.RegisterHandler("TaskDispatcher", taskdispatcherrpc.NewTaskDispatcherHandler(logger, dispatcher))
+	rpcproto.RegisterTaskDispatcherServiceServer(srv, taskdispatcherrpc.NewTaskDispatcherService(logger, dispatcher))
+	if err := srv.StartWithListener(context.WithoutCancel(ctx), ports.taskDispatcher); err != nil {
+		_ = dispatcher.Close()
+		return nil, fmt.Errorf("failed to start RPC server: %w", err)
+	}
+	ports.taskDispatcher = nil
+
+	return func(ctx context.Context) error {
+		return errors.Join(srv.Stop(ctx), dispatcher.Close())
+	}, nil
+}
+
+func (d *Devnet) startTimeScheduler(ctx context.Context, ports *servicePorts) (func(ctx context.Context) error, error) {
+	if err := setEnv(map[string]string{
+		"TIME_SCHEDULER_RPC_PORT":          ports.timeScheduler,
+		"TIME_SCHEDULER_GRPC_PORT":         "",
+		"TIME_SCHEDULER_POLLING_INTERVAL":  timeSchedulerPollingInterval.String(),
+		"TIME_SCHEDULER_POLLING_LOOKAHEAD": timeSchedulerLookAhead.String(),
+	}); err != nil {
+		return nil, err
+	}
+	if err := timeconfig.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	logger := d.logger.With("service", "time-scheduler")
+
+	dbClient, err := dbserverclient.NewDBServerClient(logger, timeconfig.GetDBServerURL())
+	if err != nil {
+		return nil, err
+	}
+	if err := dbClient.SetCredentials(dbserverclient.NewServiceCredentials("time-scheduler", timeconfig.GetServiceTokenKey(), timeconfig.GetRPCTLSConfig())); err != nil {
+		return nil, err
+	}
+	sched, err := timescheduler.NewTimeBasedScheduler(fmt.Sprintf("time-scheduler-%d", time.Now().Unix()), logger, dbClient)
+	if err != nil {
+		return nil, err
+	}
+	srv := timeapi.NewServer(timeapi.Config{
+		Port: timeconfig.GetSchedulerRPCPort(),
+	}, timeapi.Dependencies{
+		Logger:    logger,
+		Scheduler: sched,
+	})
+
+	schedCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	go sched.Start(schedCtx)
+	go func() {
+		if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("HTTP server stopped", "error", err)
+		}
+	}()
+
+	return func(ctx context.Context) error {
+		cancel()
+		sched.Stop()
+		dbClient.Close()
+		return srv.Stop(ctx)
+	}, nil
+}
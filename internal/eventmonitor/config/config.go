@@ -38,7 +38,12 @@ func Init() error {
 	if err := godotenv.Load(); err != nil {
 		return fmt.Errorf("error loading .env file: %w", err)
 	}
+	return Load()
+}
 
+// Load reads the configuration from the environment only, for processes that set it
+// themselves instead of shipping a .env file.
+func Load() error {
 	cfg = Config{
 		Port:              env.GetEnvString("EVENT_MONITOR_PORT", "9007"),
 		Host:              env.GetEnvString("EVENT_MONITOR_HOST", "0.0.0.0"),
@@ -81,10 +81,19 @@ func (dm *DatabaseManager) UpdateKeeperHealth(keeperHealth commonTypes.KeeperHea
 	var prevLastCheckedIn time.Time
 	var prevUptime int64
 
-	// Fetch previous online status, last_checked_in, and uptime
+	// Fetch keeper_id, previous online status, last_checked_in, and uptime
 	if err := dm.db.Session().Query(`
-		SELECT keeper_id, online, last_checked_in, uptime FROM triggerx.keeper_data WHERE keeper_address = ? ALLOW FILTERING`,
-		keeperHealth.KeeperAddress).Scan(&keeperID, &prevOnline, &prevLastCheckedIn, &prevUptime); err != nil {
+		SELECT keeper_id FROM triggerx.keepers_by_address WHERE keeper_address = ?`,
+		keeperHealth.KeeperAddress).Scan(&keeperID); err != nil {
+		dm.logger.Error("Failed to retrieve keeper_id",
+			"keeper", keeperHealth.KeeperAddress,
+			"error", err,
+		)
+		return err
+	}
+	if err := dm.db.Session().Query(`
+		SELECT online, last_checked_in, uptime FROM triggerx.keeper_data WHERE keeper_id = ?`,
+		keeperID).Scan(&prevOnline, &prevLastCheckedIn, &prevUptime); err != nil {
 		dm.logger.Error("Failed to retrieve keeper_id and previous status",
 			"keeper", keeperHealth.KeeperAddress,
 			"error", err,
@@ -297,21 +306,24 @@ func (dm *DatabaseManager) sendEmailNotification(to, subject, body string) error
 	return nil
 }
 
-// GetVerifiedKeepers retrieves only verified keepers from the database
+// GetVerifiedKeepers retrieves only verified keepers from the database. It reads the registered
+// keepers through keeper_data_registered_idx and skips those not whitelisted.
 func (dm *DatabaseManager) GetVerifiedKeepers() ([]types.KeeperInfo, error) {
 	var keepers []types.KeeperInfo
 
 	iter := dm.db.Session().Query(`
-		SELECT keeper_name, keeper_address, consensus_address, operator_id, version, peer_id, last_checked_in, on_imua
+		SELECT keeper_name, keeper_address, consensus_address, operator_id, version, peer_id, last_checked_in, on_imua, whitelisted
 		FROM triggerx.keeper_data 
-		WHERE registered = true AND whitelisted = true 
-		ALLOW FILTERING`).Iter()
+		WHERE registered = true`).Iter()
 
 	var keeperName, keeperAddress, consensusAddress, operatorID, version, peerID string
 	var lastCheckedIn time.Time
-	var isImua bool
+	var isImua, whitelisted bool
 
-	for iter.Scan(&keeperName, &keeperAddress, &consensusAddress, &operatorID, &version, &peerID, &lastCheckedIn, &isImua) {
+	for iter.Scan(&keeperName, &keeperAddress, &consensusAddress, &operatorID, &version, &peerID, &lastCheckedIn, &isImua, &whitelisted) {
+		if !whitelisted {
+			continue
+		}
 		keepers = append(keepers, types.KeeperInfo{
 			KeeperName:       keeperName,
 			KeeperAddress:    keeperAddress,
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// sampleBucket returns the UTC day partition a check-in is stored in
+func sampleBucket(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// RecordHealthSample stores a check-in in the keeper's history
+func (dm *DatabaseManager) RecordHealthSample(keeperHealth commonTypes.KeeperHealthCheckIn, checkedInAt time.Time) error {
+	keeperAddress := strings.ToLower(keeperHealth.KeeperAddress)
+
+	if err := dm.db.Session().Query(`
+		INSERT INTO triggerx.keeper_health_samples (keeper_address, bucket, checked_in_at, version, peer_id, is_imua)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		keeperAddress, sampleBucket(checkedInAt), checkedInAt.UTC(), keeperHealth.Version, keeperHealth.PeerID, keeperHealth.IsImua).Exec(); err != nil {
+		dm.logger.Error("Failed to record keeper health sample",
+			"error", err,
+			"keeper", keeperAddress,
+		)
+		return err
+	}
+	return nil
+}
+
+// GetHealthSamples returns the check-in times of a keeper in [since, until), oldest first
+func (dm *DatabaseManager) GetHealthSamples(keeperAddress string, since, until time.Time) ([]time.Time, error) {
+	keeperAddress = strings.ToLower(keeperAddress)
+	var samples []time.Time
+
+	for bucket := sampleBucket(since); bucket.Before(until); bucket = bucket.AddDate(0, 0, 1) {
+		iter := dm.db.Session().Query(`
+			SELECT checked_in_at FROM triggerx.keeper_health_samples
+			WHERE keeper_address = ? AND bucket = ? AND checked_in_at >= ? AND checked_in_at < ?`,
+			keeperAddress, bucket, since.UTC(), until.UTC()).Iter()
+
+		var checkedInAt time.Time
+		for iter.Scan(&checkedInAt) {
+			samples = append(samples, checkedInAt)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("error closing iterator: %w", err)
+		}
+	}
+
+	dm.logger.Debug("Retrieved keeper health samples",
+		"keeper", keeperAddress,
+		"count", len(samples),
+	)
+	return samples, nil
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -42,6 +43,12 @@ type Config struct {
 	taskExecutionAddress string
 	testTaskExecutionAddress string
 	imuaTaskExecutionAddress string
+
+	// Uptime percentage keepers are expected to meet over every window
+	uptimeSLATarget float64
+
+	// Whether check-ins signed in the legacy format, over only the keeper address, are accepted
+	acceptLegacySignatures bool
 }
 
 var cfg Config
@@ -50,6 +57,12 @@ func Init() error {
 	if err := godotenv.Load(); err != nil {
 		return fmt.Errorf("error loading .env file: %w", err)
 	}
+	return Load()
+}
+
+// Load reads the configuration from the environment only, for processes that set it
+// themselves instead of shipping a .env file.
+func Load() error {
 	cfg = Config{
 		devMode:                        env.GetEnvBool("DEV_MODE", false),
 		healthRPCPort:                  env.GetEnvString("HEALTH_RPC_PORT", "9003"),
@@ -67,6 +80,12 @@ func Init() error {
 		testTaskExecutionAddress:        env.GetEnvString("TEST_TASK_EXECUTION_ADDRESS", ""),
 		imuaTaskExecutionAddress:       env.GetEnvString("IMUA_TASK_EXECUTION_ADDRESS", ""),
 	}
+	uptimeSLATarget, err := strconv.ParseFloat(env.GetEnvString("UPTIME_SLA_TARGET", "99"), 64)
+	if err != nil {
+		return fmt.Errorf("invalid uptime SLA target: %w", err)
+	}
+	cfg.uptimeSLATarget = uptimeSLATarget
+	cfg.acceptLegacySignatures = env.GetEnvBool("ACCEPT_LEGACY_SIGNATURES", true)
 	if err := validateConfig(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -107,6 +126,9 @@ func validateConfig() error {
 	if !env.IsValidEthAddress(cfg.imuaTaskExecutionAddress) {
 		return fmt.Errorf("invalid Imua task execution address: %s", cfg.imuaTaskExecutionAddress)
 	}
+	if cfg.uptimeSLATarget <= 0 || cfg.uptimeSLATarget > 100 {
+		return fmt.Errorf("invalid uptime SLA target: %v", cfg.uptimeSLATarget)
+	}
 	if !cfg.devMode {
 		if !env.IsValidEmail(cfg.emailUser) {
 			return fmt.Errorf("invalid email user: %s", cfg.emailUser)
@@ -180,3 +202,11 @@ func GetImuaTaskExecutionAddress() string {
 func GetManagerSigningAddress() string {
 	return cfg.managerSigningAddress
 }
+
+func GetUptimeSLATarget() float64 {
+	return cfg.uptimeSLATarget
+}
+
+func GetAcceptLegacySignatures() bool {
+	return cfg.acceptLegacySignatures
+}
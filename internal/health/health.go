@@ -79,6 +79,8 @@ func RegisterRoutes(router *gin.Engine, logger logging.Logger) {
 	router.GET("/status", handler.GetKeeperStatus)
 	router.GET("/operators", handler.GetDetailedKeeperStatus)
 	router.GET("/performers", handler.GetActivePerformers) // New endpoint for taskmanager
+	router.GET("/performers/history", handler.GetPerformerHistory)
+	router.GET("/operators/:address/uptime", handler.GetOperatorUptime)
 	router.GET("/metrics", gin.WrapH(metricsCollector.Handler()))
 }
 
@@ -103,6 +105,9 @@ func (h *Handler) HandleCheckInEvent(c *gin.Context) {
 		return
 	}
 
+	// Signatures cover the check-in as sent, before defaults are filled in
+	signedCheckIn := keeperHealth
+
 	// Handle missing fields with defaults
 	if keeperHealth.PeerID == "" {
 		keeperHealth.PeerID = "no-peer-id"
@@ -121,8 +126,8 @@ func (h *Handler) HandleCheckInEvent(c *gin.Context) {
 	metrics.CheckinsByVersionTotal.WithLabelValues(keeperHealth.Version).Inc()
 
 	// Verify signature for all versions
-	ok, err := cryptography.VerifySignature(keeperHealth.KeeperAddress, keeperHealth.Signature, keeperHealth.ConsensusAddress)
-	if !ok {
+	format, err := verifyCheckInSignature(signedCheckIn, config.GetAcceptLegacySignatures())
+	if format == "" {
 		h.logger.Error("Invalid keeper signature",
 			"keeper", keeperHealth.KeeperAddress,
 			"error", err,
@@ -132,6 +137,9 @@ func (h *Handler) HandleCheckInEvent(c *gin.Context) {
 		})
 		return
 	}
+	if format == cryptography.SignatureFormatJSON {
+		metrics.LegacySignaturesTotal.WithLabelValues(keeperHealth.Version).Inc()
+	}
 
 	// h.logger.Debug("Valid keeper signature verified",
 	// 	"keeper", keeperHealth.KeeperAddress,
@@ -331,3 +339,78 @@ func (h *Handler) GetActivePerformers(c *gin.Context) {
 		"timestamp":  time.Now().UTC().Format(time.RFC3339),
 	})
 }
+
+// GetOperatorUptime returns uptime, SLA compliance and outages of a keeper over the 1h, 24h, 7d and 30d windows
+func (h *Handler) GetOperatorUptime(c *gin.Context) {
+	address := strings.ToLower(c.Param("address"))
+
+	uptime, err := h.stateManager.GetKeeperUptime(address)
+	if err != nil {
+		if errors.Is(err, keeper.ErrKeeperNotVerified) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Keeper not found",
+				"code":  "KEEPER_NOT_VERIFIED",
+			})
+			return
+		}
+
+		h.logger.Error("Failed to compute keeper uptime",
+			"error", err,
+			"keeper", address,
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute keeper uptime"})
+		return
+	}
+
+	c.JSON(http.StatusOK, uptime)
+}
+
+// GetPerformerHistory returns verified keepers ranked by uptime over the window query parameter (default 24h),
+// used by the task dispatcher to select performers
+func (h *Handler) GetPerformerHistory(c *gin.Context) {
+	windowName := c.DefaultQuery("window", "24h")
+	window, ok := keeper.ParseUptimeWindow(windowName)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid window %q, expected one of 1h, 24h, 7d, 30d", windowName)})
+		return
+	}
+
+	history, err := h.stateManager.GetPerformerHistory(window)
+	if err != nil {
+		h.logger.Error("Failed to compute performer history",
+			"error", err,
+			"window", windowName,
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute performer history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"window":     window.Name,
+		"performers": history,
+		"count":      len(history),
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// verifyCheckInSignature accepts an EIP-712 signature of the whole check-in and, while keepers
+// upgrade and acceptLegacy is set, the legacy signature of only the keeper address. It returns
+// the format the signature verified in, empty if it doesn't verify.
+func verifyCheckInSignature(checkIn commonTypes.KeeperHealthCheckIn, acceptLegacy bool) (cryptography.SignatureFormat, error) {
+	ok, err := cryptography.VerifyTypedData(checkIn, cryptography.NewTypedDataDomain(0), checkIn.Signature, checkIn.ConsensusAddress)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return cryptography.SignatureFormatEIP712, nil
+	}
+	if !acceptLegacy {
+		return "", nil
+	}
+
+	ok, err = cryptography.VerifySignature(checkIn.KeeperAddress, checkIn.Signature, checkIn.ConsensusAddress)
+	if err != nil || !ok {
+		return "", err
+	}
+	return cryptography.SignatureFormatJSON, nil
+}
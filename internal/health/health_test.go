@@ -11,12 +11,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/trigg3rX/triggerx-backend/internal/health/keeper"
 	"github.com/trigg3rX/triggerx-backend/internal/health/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
@@ -501,3 +504,37 @@ func TestLoggerMiddleware(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockLogger.AssertExpectations(t)
 }
+
+func TestVerifyCheckInSignature_LegacyGate(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	privateKey := fmt.Sprintf("%x", crypto.FromECDSA(key))
+
+	checkIn := commonTypes.KeeperHealthCheckIn{
+		KeeperAddress:    "0x0a067a261c5f5e8c4c0b9137430b4fe1255eb62e",
+		ConsensusPubKey:  "pubkey",
+		ConsensusAddress: crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		Version:          "1.0.0",
+		Timestamp:        time.Unix(1700000000, 0),
+		PeerID:           "peer",
+	}
+
+	typed := checkIn
+	typed.Signature, err = cryptography.SignTypedData(checkIn, cryptography.NewTypedDataDomain(0), privateKey)
+	require.NoError(t, err)
+	legacy := checkIn
+	legacy.Signature, err = cryptography.SignMessage(checkIn.KeeperAddress, privateKey)
+	require.NoError(t, err)
+
+	format, err := verifyCheckInSignature(typed, false)
+	require.NoError(t, err)
+	assert.Equal(t, cryptography.SignatureFormatEIP712, format)
+
+	format, err = verifyCheckInSignature(legacy, true)
+	require.NoError(t, err)
+	assert.Equal(t, cryptography.SignatureFormatJSON, format)
+
+	format, err = verifyCheckInSignature(legacy, false)
+	require.NoError(t, err)
+	assert.Empty(t, format, "legacy check-ins are rejected once turned off")
+}
@@ -24,7 +24,9 @@ func (sm *StateManager) LoadVerifiedKeepers() error {
 	// Clear existing state
 	sm.keepers = make(map[string]*types.KeeperInfo)
 
-	// Load each keeper's state (initially marked as inactive)
+	// Load each keeper's state, keepers that checked in within the inactivity threshold stay active
+	// so a restart of the health service does not show up as an outage
+	now := time.Now().UTC()
 	for _, keeper := range keepers {
 		state := &types.KeeperInfo{
 			KeeperName:       keeper.KeeperName,
@@ -33,7 +35,7 @@ func (sm *StateManager) LoadVerifiedKeepers() error {
 			OperatorID:       keeper.OperatorID,
 			Version:          keeper.Version,
 			PeerID:           keeper.PeerID,
-			IsActive:         false,
+			IsActive:         now.Sub(keeper.LastCheckedIn) <= inactivityThreshold,
 			LastCheckedIn:    keeper.LastCheckedIn,
 			IsImua:           keeper.IsImua,
 		}
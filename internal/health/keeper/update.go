@@ -47,6 +47,14 @@ func (sm *StateManager) UpdateKeeperHealth(keeperHealth commonTypes.KeeperHealth
 		return fmt.Errorf("failed to update keeper status in database: %w", err)
 	}
 
+	// History is best effort, a missing sample only lowers reported uptime
+	if err := sm.db.RecordHealthSample(keeperHealth, now); err != nil {
+		sm.logger.Warn("Failed to record keeper health sample",
+			"error", err,
+			"keeper", address,
+		)
+	}
+
 	sm.logger.Info("Updated keeper health status",
 		"keeper", address,
 		"version", keeperHealth.Version,
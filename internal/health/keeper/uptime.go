@@ -0,0 +1,176 @@
+package keeper
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trigg3rX/triggerx-backend/internal/health/config"
+	"github.com/trigg3rX/triggerx-backend/internal/health/types"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// UptimeWindow is a trailing window uptime is reported over
+type UptimeWindow struct {
+	Name     string
+	Duration time.Duration
+}
+
+// UptimeWindows are the supported windows, shortest first
+var UptimeWindows = []UptimeWindow{
+	{Name: "1h", Duration: time.Hour},
+	{Name: "24h", Duration: 24 * time.Hour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour},
+}
+
+// ParseUptimeWindow returns the supported window with the given name
+func ParseUptimeWindow(name string) (UptimeWindow, bool) {
+	for _, window := range UptimeWindows {
+		if window.Name == name {
+			return window, true
+		}
+	}
+	return UptimeWindow{}, false
+}
+
+// GetKeeperUptime reports the uptime and outages of a verified keeper over every window
+func (sm *StateManager) GetKeeperUptime(keeperAddress string) (*types.KeeperUptime, error) {
+	keeperAddress = strings.ToLower(keeperAddress)
+
+	sm.mu.RLock()
+	state, exists := sm.keepers[keeperAddress]
+	var report types.KeeperUptime
+	if exists {
+		report = types.KeeperUptime{
+			KeeperAddress: keeperAddress,
+			IsActive:      state.IsActive,
+			LastCheckedIn: state.LastCheckedIn,
+		}
+	}
+	sm.mu.RUnlock()
+
+	if !exists {
+		return nil, ErrKeeperNotVerified
+	}
+
+	now := time.Now().UTC()
+	longest := UptimeWindows[len(UptimeWindows)-1]
+	// A check-in just before the window still covers its start
+	checkIns, err := sm.db.GetHealthSamples(keeperAddress, now.Add(-longest.Duration-inactivityThreshold), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load health samples: %w", err)
+	}
+
+	for _, window := range UptimeWindows {
+		report.Windows = append(report.Windows,
+			computeUptimeWindow(window, checkIns, now, inactivityThreshold, config.GetUptimeSLATarget()))
+	}
+	return &report, nil
+}
+
+// GetPerformerHistory returns the verified keepers with their uptime over the window, best first
+func (sm *StateManager) GetPerformerHistory(window UptimeWindow) ([]commonTypes.PerformerHistory, error) {
+	keepers := sm.GetDetailedKeeperInfo()
+	now := time.Now().UTC()
+	history := make([]commonTypes.PerformerHistory, 0, len(keepers))
+
+	for _, keeper := range keepers {
+		operatorID, err := strconv.ParseInt(keeper.OperatorID, 10, 64)
+		if err != nil {
+			sm.logger.Warn("Skipping keeper with invalid operator_id",
+				"keeper", keeper.KeeperAddress,
+				"operator_id", keeper.OperatorID,
+			)
+			continue
+		}
+
+		checkIns, err := sm.db.GetHealthSamples(keeper.KeeperAddress, now.Add(-window.Duration-inactivityThreshold), now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load health samples for %s: %w", keeper.KeeperAddress, err)
+		}
+		uptime := computeUptimeWindow(window, checkIns, now, inactivityThreshold, config.GetUptimeSLATarget())
+
+		history = append(history, commonTypes.PerformerHistory{
+			PerformerData: commonTypes.PerformerData{
+				OperatorID:    operatorID,
+				KeeperAddress: keeper.KeeperAddress,
+				IsImua:        keeper.IsImua,
+			},
+			IsActive:      keeper.IsActive,
+			LastCheckedIn: keeper.LastCheckedIn,
+			UptimePercent: uptime.UptimePercent,
+			OutageCount:   len(uptime.Outages),
+		})
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		if history[i].UptimePercent != history[j].UptimePercent {
+			return history[i].UptimePercent > history[j].UptimePercent
+		}
+		return history[i].OperatorID < history[j].OperatorID
+	})
+	return history, nil
+}
+
+// computeUptimeWindow treats every check-in as keeping the keeper up for threshold, the same rule
+// the cleanup routine uses to mark keepers inactive. Gaps between covered intervals are outages.
+func computeUptimeWindow(window UptimeWindow, checkIns []time.Time, end time.Time, threshold time.Duration, slaTarget float64) types.UptimeWindow {
+	start := end.Add(-window.Duration)
+	result := types.UptimeWindow{
+		Window:    window.Name,
+		Start:     start,
+		End:       end,
+		SLATarget: slaTarget,
+		Outages:   make([]types.Outage, 0),
+	}
+
+	sorted := append([]time.Time(nil), checkIns...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var up time.Duration
+	covered := start
+	for _, checkIn := range sorted {
+		from, to := checkIn, checkIn.Add(threshold)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if !to.After(from) {
+			continue
+		}
+		if from.After(covered) {
+			result.Outages = append(result.Outages, newOutage(covered, from, false))
+		}
+		if to.After(covered) {
+			if from.After(covered) {
+				up += to.Sub(from)
+			} else {
+				up += to.Sub(covered)
+			}
+			covered = to
+		}
+	}
+	if end.After(covered) {
+		result.Outages = append(result.Outages, newOutage(covered, end, true))
+	}
+
+	result.UptimeSeconds = int64(up.Seconds())
+	result.DowntimeSeconds = int64((window.Duration - up).Seconds())
+	result.UptimePercent = float64(up) / float64(window.Duration) * 100
+	result.MeetsSLA = result.UptimePercent >= slaTarget
+	return result
+}
+
+func newOutage(start, end time.Time, ongoing bool) types.Outage {
+	return types.Outage{
+		Start:           start,
+		End:             end,
+		DurationSeconds: int64(end.Sub(start).Seconds()),
+		Ongoing:         ongoing,
+	}
+}
@@ -0,0 +1,95 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeUptimeWindow(t *testing.T) {
+	end := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := UptimeWindow{Name: "1h", Duration: time.Hour}
+	start := end.Add(-time.Hour)
+
+	everyMinute := func(from, to time.Time) []time.Time {
+		var checkIns []time.Time
+		for t := from; t.Before(to); t = t.Add(time.Minute) {
+			checkIns = append(checkIns, t)
+		}
+		return checkIns
+	}
+
+	t.Run("always up", func(t *testing.T) {
+		// The check-in just before the window covers its start
+		checkIns := everyMinute(start.Add(-30*time.Second), end)
+
+		result := computeUptimeWindow(window, checkIns, end, inactivityThreshold, 99)
+
+		assert.Equal(t, "1h", result.Window)
+		assert.Equal(t, start, result.Start)
+		assert.InDelta(t, 100.0, result.UptimePercent, 0.001)
+		assert.Equal(t, int64(0), result.DowntimeSeconds)
+		assert.Empty(t, result.Outages)
+		assert.True(t, result.MeetsSLA)
+	})
+
+	t.Run("outage in the middle", func(t *testing.T) {
+		checkIns := append(everyMinute(start, start.Add(20*time.Minute)), everyMinute(start.Add(40*time.Minute), end)...)
+
+		result := computeUptimeWindow(window, checkIns, end, inactivityThreshold, 99)
+
+		// Last check-in at 19m covers until 20m10s, the next one arrives at 40m
+		require.Len(t, result.Outages, 1)
+		outage := result.Outages[0]
+		assert.Equal(t, start.Add(20*time.Minute+10*time.Second), outage.Start)
+		assert.Equal(t, start.Add(40*time.Minute), outage.End)
+		assert.Equal(t, int64(19*60+50), outage.DurationSeconds)
+		assert.False(t, outage.Ongoing)
+		assert.Equal(t, int64(19*60+50), result.DowntimeSeconds)
+		assert.Equal(t, int64(40*60+10), result.UptimeSeconds)
+		assert.False(t, result.MeetsSLA)
+	})
+
+	t.Run("ongoing outage", func(t *testing.T) {
+		checkIns := everyMinute(start, start.Add(30*time.Minute))
+
+		result := computeUptimeWindow(window, checkIns, end, inactivityThreshold, 50)
+
+		require.Len(t, result.Outages, 1)
+		assert.Equal(t, start.Add(30*time.Minute+10*time.Second), result.Outages[0].Start)
+		assert.Equal(t, end, result.Outages[0].End)
+		assert.True(t, result.Outages[0].Ongoing)
+		assert.InDelta(t, 50.0+10.0/36, result.UptimePercent, 0.001)
+		assert.True(t, result.MeetsSLA)
+	})
+
+	t.Run("no check-ins", func(t *testing.T) {
+		result := computeUptimeWindow(window, nil, end, inactivityThreshold, 99)
+
+		assert.Equal(t, 0.0, result.UptimePercent)
+		require.Len(t, result.Outages, 1)
+		assert.Equal(t, start, result.Outages[0].Start)
+		assert.True(t, result.Outages[0].Ongoing)
+	})
+
+	t.Run("unsorted and overlapping check-ins", func(t *testing.T) {
+		checkIns := []time.Time{start.Add(10 * time.Second), start, start.Add(5 * time.Second)}
+
+		result := computeUptimeWindow(window, checkIns, end, inactivityThreshold, 99)
+
+		assert.Equal(t, int64(80), result.UptimeSeconds)
+		require.Len(t, result.Outages, 1)
+		assert.Equal(t, start.Add(80*time.Second), result.Outages[0].Start)
+	})
+}
+
+func TestParseUptimeWindow(t *testing.T) {
+	window, ok := ParseUptimeWindow("7d")
+	assert.True(t, ok)
+	assert.Equal(t, 7*24*time.Hour, window.Duration)
+
+	_, ok = ParseUptimeWindow("2d")
+	assert.False(t, ok)
+}
@@ -53,6 +53,14 @@ var (
 		Help:      "Check-ins by keeper version",
 	}, []string{"version"})
 
+	// Check-ins accepted with a legacy signature over only the keeper address
+	LegacySignaturesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triggerx",
+		Subsystem: "health_service",
+		Name:      "legacy_signatures_total",
+		Help:      "Signatures accepted in the legacy format",
+	}, []string{"version"})
+
 	// Keeper status metrics
 	KeepersTotal = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "triggerx",
@@ -90,8 +90,8 @@ func (b *Bot) updateKeeperChatID(keeperAddress string, chatID int64) error {
 
 	var keeperID string
 	if err := b.db.Session().Query(`
-		SELECT keeper_id FROM triggerx.keeper_data 
-		WHERE keeper_address = ? ALLOW FILTERING`, keeperAddress).Consistency(gocql.One).Scan(&keeperID); err != nil {
+		SELECT keeper_id FROM triggerx.keepers_by_address 
+		WHERE keeper_address = ?`, keeperAddress).Consistency(gocql.One).Scan(&keeperID); err != nil {
 		b.logger.Errorf("[UpdateKeeperChatID] Error finding keeper ID for keeper %s: %v", keeperAddress, err)
 		return err
 	}
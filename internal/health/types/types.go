@@ -16,3 +16,32 @@ type KeeperInfo struct {
 	LastCheckedIn    time.Time `json:"last_checked_in"`
 	IsImua           bool      `json:"is_imua"`
 }
+
+// Outage is an interval in which a keeper was not checking in
+type Outage struct {
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	Ongoing         bool      `json:"ongoing"`
+}
+
+// UptimeWindow summarises a keeper's availability over a trailing window
+type UptimeWindow struct {
+	Window          string    `json:"window"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	UptimeSeconds   int64     `json:"uptime_seconds"`
+	DowntimeSeconds int64     `json:"downtime_seconds"`
+	UptimePercent   float64   `json:"uptime_percent"`
+	SLATarget       float64   `json:"sla_target"`
+	MeetsSLA        bool      `json:"meets_sla"`
+	Outages         []Outage  `json:"outages"`
+}
+
+// KeeperUptime is the uptime report of a keeper over every supported window
+type KeeperUptime struct {
+	KeeperAddress string         `json:"keeper_address"`
+	IsActive      bool           `json:"is_active"`
+	LastCheckedIn time.Time      `json:"last_checked_in"`
+	Windows       []UptimeWindow `json:"windows"`
+}
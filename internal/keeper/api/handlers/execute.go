@@ -58,6 +58,8 @@ func (h *TaskHandler) ExecuteTask(c *gin.Context) {
 
 	if !strings.EqualFold(config.GetKeeperAddress(), requestData.PerformerData.KeeperAddress) {
 		h.logger.Infof("I am not the performer: %s", requestData.PerformerData.KeeperAddress)
+		// The job's next run may fall to this keeper, so warm up for it
+		h.executor.SchedulePrewarm(&requestData)
 		c.JSON(http.StatusOK, gin.H{"message": "I am not the performer"})
 		return
 	} else {
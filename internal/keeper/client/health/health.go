@@ -87,16 +87,6 @@ func (c *Client) CheckIn(ctx context.Context) (types.KeeperHealthCheckInResponse
 	consensusPubKey := hex.EncodeToString(publicKeyBytes)
 	consensusAddress := ethcrypto.PubkeyToAddress(privateKey.PublicKey).Hex()
 
-	// Create message to sign
-	msg := []byte(c.config.KeeperAddress)
-	signature, err := cryptography.SignMessage(string(msg), c.config.PrivateKey)
-	if err != nil {
-		return types.KeeperHealthCheckInResponse{
-			Status: false,
-			Data:   err.Error(),
-		}, fmt.Errorf("failed to sign check-in message: %w", err)
-	}
-
 	// Prepare health check payload
 	payload := types.KeeperHealthCheckIn{
 		KeeperAddress:    c.config.KeeperAddress,
@@ -104,11 +94,19 @@ func (c *Client) CheckIn(ctx context.Context) (types.KeeperHealthCheckInResponse
 		ConsensusAddress: consensusAddress,
 		Version:          c.config.Version,
 		Timestamp:        time.Now().UTC(),
-		Signature:        signature,
 		PeerID:           c.config.PeerID,
 		IsImua:           config.IsImua(),
 	}
 
+	signature, err := c.signCheckIn(payload)
+	if err != nil {
+		return types.KeeperHealthCheckInResponse{
+			Status: false,
+			Data:   err.Error(),
+		}, fmt.Errorf("failed to sign check-in message: %w", err)
+	}
+	payload.Signature = signature
+
 	// c.logger.Infof("Payload: %+v", payload)
 
 	// Send health check request
@@ -130,6 +128,14 @@ func (c *Client) CheckIn(ctx context.Context) (types.KeeperHealthCheckInResponse
 	return response, nil
 }
 
+// signCheckIn signs the whole check-in as EIP-712 typed data, or only the keeper address in the legacy format
+func (c *Client) signCheckIn(payload types.KeeperHealthCheckIn) (string, error) {
+	if config.GetSignatureFormat() == cryptography.SignatureFormatEIP712 {
+		return cryptography.SignTypedData(payload, cryptography.NewTypedDataDomain(0), c.config.PrivateKey)
+	}
+	return cryptography.SignMessage(c.config.KeeperAddress, c.config.PrivateKey)
+}
+
 // sendHealthCheck sends the health check request to the health service
 func (c *Client) sendHealthCheck(ctx context.Context, payload types.KeeperHealthCheckIn) (types.KeeperHealthCheckInResponse, error) {
 	payloadBytes, err := json.Marshal(payload)
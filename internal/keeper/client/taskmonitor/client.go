@@ -9,11 +9,14 @@ import (
 	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	"github.com/trigg3rX/triggerx-backend/pkg/rpc/client"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
 // Client represents a client for communicating with the taskmonitor service
 type Client struct {
 	rpcClient *client.Client
+	service   rpcproto.TaskMonitorServiceClient
 	logger    logging.Logger
 }
 
@@ -35,6 +38,7 @@ func NewClient(logger logging.Logger) (*Client, error) {
 
 	return &Client{
 		rpcClient: rpcClient,
+		service:   rpcproto.NewTaskMonitorServiceClient(rpcClient),
 		logger:    logger,
 	}, nil
 }
@@ -58,39 +62,31 @@ func (c *Client) ReportTaskError(ctx context.Context, taskID int64, errorMsg str
 	keeperAddress := config.GetKeeperAddress()
 
 	// Create request data for signing (without signature field)
-	signData := struct {
-		TaskID        int64  `json:"task_id"`
-		KeeperAddress string `json:"keeper_address"`
-		Error         string `json:"error"`
-	}{
+	signData := types.TaskErrorReport{
 		TaskID:        taskID,
 		KeeperAddress: keeperAddress,
 		Error:         errorMsg,
 	}
 
-	// Sign the request data
-	signature, err := cryptography.SignJSONMessage(signData, config.GetPrivateKeyConsensus())
+	// Sign the request data, error reports are not bound to a chain
+	signature, err := cryptography.SignStructured(signData, 0, config.GetPrivateKeyConsensus(), config.GetSignatureFormat())
 	if err != nil {
 		return fmt.Errorf("failed to sign error report: %w", err)
 	}
 
-	// Create request
-	request := ReportTaskErrorRequest{
-		TaskID:        taskID,
+	// Make RPC call
+	response, err := c.service.ReportTaskError(ctx, &rpcproto.ReportTaskErrorRequest{
+		TaskId:        taskID,
 		KeeperAddress: keeperAddress,
 		Error:         errorMsg,
 		Signature:     signature,
-	}
-
-	// Make RPC call
-	var response ReportTaskErrorResponse
-	err = c.rpcClient.Call(ctx, "report-task-error", &request, &response)
+	})
 	if err != nil {
 		return fmt.Errorf("RPC call failed: %w", err)
 	}
 
-	if !response.Success {
-		return fmt.Errorf("taskmonitor reported failure: %s", response.Message)
+	if !response.GetSuccess() {
+		return fmt.Errorf("taskmonitor reported failure: %s", response.GetMessage())
 	}
 
 	c.logger.Info("Task error reported successfully to taskmonitor",
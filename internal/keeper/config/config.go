@@ -10,7 +10,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 
+	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
 	"github.com/trigg3rX/triggerx-backend/pkg/env"
+	"github.com/trigg3rX/triggerx-backend/pkg/ipfs"
 )
 
 const (
@@ -51,6 +53,13 @@ type Config struct {
 	ipfsHost  string
 	pinataJWT string
 
+	// IPFS storage backends, results are replicated to all of them when several are listed
+	ipfsStorageBackends []ipfs.Backend
+	ipfsMinReplicas     int
+	kuboAPIUrl          string
+	ipfsLocalDir        string
+	s3Config            ipfs.S3Config
+
 	// TLS Proof configuration
 	tlsProofHost string
 	tlsProofPort string
@@ -58,6 +67,11 @@ type Config struct {
 	// Manager Signing Address
 	managerSigningAddress string
 
+	// Format the keeper signs results, check-ins and error reports in
+	signatureFormat cryptography.SignatureFormat
+	// Whether legacy JSON signatures of task data and results are still accepted
+	acceptLegacySignatures bool
+
 	// Backend Service URLs
 	aggregatorRPCUrl  string
 	healthRPCUrl      string
@@ -100,6 +114,9 @@ func Init() error {
 		taskMonitorRPCUrl:    env.GetEnvString("TASK_MONITOR_RPC_URL", "https://task.triggerx.network"),
 		tlsProofHost:         "www.google.com",
 		tlsProofPort:         "443",
+		ipfsMinReplicas:      env.GetEnvInt("IPFS_MIN_REPLICAS", 1),
+		kuboAPIUrl:           env.GetEnvString("KUBO_API_URL", "http://127.0.0.1:5001"),
+		ipfsLocalDir:         env.GetEnvString("IPFS_LOCAL_DIR", "data/ipfs"),
 		l1Chain:                  env.GetEnvString("L1_CHAIN", "11155111"),
 		l2Chain:                  env.GetEnvString("L2_CHAIN", "84532"),
 		avsGovernanceAddress:     env.GetEnvString("TEST_AVS_GOVERNANCE_ADDRESS", "0xaaE90bE86cec5E6c34D584917FFfCE7C379fFEE1"),
@@ -110,6 +127,25 @@ func Init() error {
 		// attestationCenterAddress: env.GetEnvString("ATTESTATION_CENTER_ADDRESS", "0x6DFee10D13d5B43AaF97bDA908C1D76d4313aF5f"),
 		othenticBootstrapID:      env.GetEnvString("OTHENTIC_BOOTSTRAP_ID", "12D3KooWBNFG1QjuF3UKAKvqhdXcxh9iBmj88cM5eU2EK5Pa91KB"),
 	}
+	signatureFormat, err := cryptography.ParseSignatureFormat(env.GetEnvString("SIGNATURE_FORMAT", string(cryptography.SignatureFormatJSON)))
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	cfg.signatureFormat = signatureFormat
+	cfg.acceptLegacySignatures = env.GetEnvBool("ACCEPT_LEGACY_SIGNATURES", true)
+	ipfsStorageBackends, err := ipfs.ParseBackends(env.GetEnvString("IPFS_STORAGE_BACKENDS", string(ipfs.BackendPinata)))
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	cfg.ipfsStorageBackends = ipfsStorageBackends
+	cfg.s3Config = ipfs.S3Config{
+		Endpoint:        env.GetEnvString("S3_ENDPOINT", ""),
+		Region:          env.GetEnvString("S3_REGION", "us-east-1"),
+		Bucket:          env.GetEnvString("S3_BUCKET", ""),
+		AccessKeyID:     env.GetEnvString("S3_ACCESS_KEY_ID", ""),
+		SecretAccessKey: env.GetEnvString("S3_SECRET_ACCESS_KEY", ""),
+		KeyPrefix:       env.GetEnvString("S3_KEY_PREFIX", "ipfs/"),
+	}
 	if err := validateConfig(cfg); err != nil {
 		return fmt.Errorf("invalid config: %w", err)
 	}
@@ -255,6 +291,19 @@ func GetPinataJWT() string {
 	return cfg.pinataJWT
 }
 
+// GetIPFSStorageConfig returns the backends results are stored on, with the Pinata
+// credentials received from the health service
+func GetIPFSStorageConfig() ipfs.StorageConfig {
+	return ipfs.StorageConfig{
+		Backends:    cfg.ipfsStorageBackends,
+		MinReplicas: cfg.ipfsMinReplicas,
+		Pinata:      ipfs.NewConfig(cfg.ipfsHost, cfg.pinataJWT),
+		Kubo:        ipfs.KuboConfig{APIURL: cfg.kuboAPIUrl},
+		S3:          cfg.s3Config,
+		Local:       ipfs.LocalConfig{Dir: cfg.ipfsLocalDir},
+	}
+}
+
 // TLS Proof configuration
 func SetTLSProofHost(host string) {
 	cfg.tlsProofHost = host
@@ -281,6 +330,14 @@ func GetManagerSigningAddress() string {
 	return cfg.managerSigningAddress
 }
 
+func GetSignatureFormat() cryptography.SignatureFormat {
+	return cfg.signatureFormat
+}
+
+func GetAcceptLegacySignatures() bool {
+	return cfg.acceptLegacySignatures
+}
+
 func SetTaskExecutionAddress(addr string) {
 	cfg.taskExecutionAddress = addr
 }
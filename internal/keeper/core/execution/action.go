@@ -17,15 +17,21 @@ import (
 	"github.com/trigg3rX/triggerx-backend/internal/keeper/config"
 	"github.com/trigg3rX/triggerx-backend/internal/keeper/metrics"
 	dockertypes "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/parser"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
+// dynamicArgsLead is how long before its trigger a time job with dynamic arguments runs its script
+const dynamicArgsLead = 60 * time.Second
+
 func (e *TaskExecutor) executeAction(targetData *types.TaskTargetData, triggerData *types.TaskTriggerData, nonce uint64, client *ethclient.Client) (types.PerformerActionData, error) {
 	if targetData.TaskDefinitionID != 7 && targetData.TargetContractAddress == "" {
 		e.logger.Errorf("Execution contract address not configured")
 		return types.PerformerActionData{}, fmt.Errorf("execution contract address not configured")
 	}
 
+	e.schedulePrewarm(targetData, scriptRunTimes(triggerData))
+
 	var timeToNextTrigger time.Duration
 	switch targetData.TaskDefinitionID {
 	case 1:
@@ -33,7 +39,7 @@ func (e *TaskExecutor) executeAction(targetData *types.TaskTargetData, triggerDa
 		timeToNextTrigger = timeToNextTrigger - 4*time.Second
 	case 2:
 		timeToNextTrigger = time.Until(triggerData.NextTriggerTimestamp)
-		timeToNextTrigger = timeToNextTrigger - 60*time.Second
+		timeToNextTrigger = timeToNextTrigger - dynamicArgsLead
 		if timeToNextTrigger < 0 {
 			timeToNextTrigger = 0
 		}
@@ -123,7 +129,11 @@ func (e *TaskExecutor) executeAction(targetData *types.TaskTargetData, triggerDa
 
 		// e.logger.Infof("Metadata: %+v", metadata)
 
-		result, execErr = e.validator.GetDockerExecutor().Execute(context.Background(), targetData.DynamicArgumentsScriptUrl, "go", 1, config.GetAlchemyAPIKey(), metadata)
+		language, ok := scriptLanguage(targetData)
+		if !ok {
+			language = dockertypes.LanguageGo
+		}
+		result, execErr = e.validator.GetDockerExecutor().Execute(context.Background(), targetData.DynamicArgumentsScriptUrl, string(language), 1, config.GetAlchemyAPIKey(), metadata)
 		if execErr != nil {
 			return types.PerformerActionData{}, fmt.Errorf("failed to execute script: %v", execErr)
 		}
@@ -234,3 +244,69 @@ skipArgumentProcessing:
 
 	return executionResult, nil
 }
+
+// SchedulePrewarm pre-warms pools for the next runs of the time jobs in a task sent to another
+// performer, so this keeper's containers are ready if one of those runs falls to it
+func (e *TaskExecutor) SchedulePrewarm(task *types.SendTaskDataToKeeper) {
+	for i := range task.TargetData {
+		if i >= len(task.TriggerData) {
+			break
+		}
+		if runTimes := scriptRunTimes(&task.TriggerData[i]); len(runTimes) > 1 {
+			e.schedulePrewarm(&task.TargetData[i], runTimes[1:])
+		}
+	}
+}
+
+// schedulePrewarm lets the pool of the task's script language pre-warm containers for the
+// given script runs. Tasks without a script use no container and are skipped.
+func (e *TaskExecutor) schedulePrewarm(targetData *types.TaskTargetData, runTimes []time.Time) {
+	language, ok := scriptLanguage(targetData)
+	if !ok || len(runTimes) == 0 {
+		return
+	}
+
+	if err := e.validator.GetDockerExecutor().SchedulePrewarm(language, runTimes); err != nil {
+		e.logger.Debugf("Failed to schedule pool pre-warm: %v", err)
+	}
+}
+
+// scriptLanguage returns the language a task's script runs in, or false when the task runs no script
+func scriptLanguage(targetData *types.TaskTargetData) (dockertypes.Language, bool) {
+	switch targetData.TaskDefinitionID {
+	case 7:
+		if targetData.ScriptLanguage == "" {
+			return dockertypes.LanguageTS, true
+		}
+	case 2, 4, 6:
+		if targetData.DynamicArgumentsScriptUrl == "" {
+			return "", false
+		}
+		if targetData.ScriptLanguage == "" {
+			return dockertypes.LanguageGo, true
+		}
+	default:
+		return "", false
+	}
+	return dockertypes.Language(strings.ToLower(targetData.ScriptLanguage)), true
+}
+
+// scriptRunTimes returns when the script of a time task runs for the execution at its next trigger
+// and, if the job does not expire first, for the execution the scheduler will send after it
+func scriptRunTimes(triggerData *types.TaskTriggerData) []time.Time {
+	if triggerData.NextTriggerTimestamp.IsZero() {
+		return nil
+	}
+
+	lead := time.Duration(0)
+	if triggerData.TaskDefinitionID == 2 {
+		lead = dynamicArgsLead
+	}
+	runTimes := []time.Time{triggerData.NextTriggerTimestamp.Add(-lead)}
+
+	next, err := parser.CalculateNextExecutionTime(triggerData.NextTriggerTimestamp, triggerData.TimeScheduleType, triggerData.TimeInterval, triggerData.TimeCronExpression, triggerData.TimeSpecificSchedule)
+	if err == nil && (triggerData.ExpirationTime.IsZero() || !next.After(triggerData.ExpirationTime)) {
+		runTimes = append(runTimes, next.Add(-lead))
+	}
+	return runTimes
+}
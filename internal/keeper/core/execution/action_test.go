@@ -0,0 +1,101 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	dockertypes "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func TestScriptLanguage(t *testing.T) {
+	tests := []struct {
+		name       string
+		targetData types.TaskTargetData
+		expected   dockertypes.Language
+		hasScript  bool
+	}{
+		{
+			name:       "static time job runs no script",
+			targetData: types.TaskTargetData{TaskDefinitionID: 1},
+			hasScript:  false,
+		},
+		{
+			name:       "dynamic job without script url",
+			targetData: types.TaskTargetData{TaskDefinitionID: 2},
+			hasScript:  false,
+		},
+		{
+			name:       "dynamic job defaults to go",
+			targetData: types.TaskTargetData{TaskDefinitionID: 2, DynamicArgumentsScriptUrl: "ipfs://script"},
+			expected:   dockertypes.LanguageGo,
+			hasScript:  true,
+		},
+		{
+			name:       "dynamic job with script language",
+			targetData: types.TaskTargetData{TaskDefinitionID: 4, DynamicArgumentsScriptUrl: "ipfs://script", ScriptLanguage: "PY"},
+			expected:   dockertypes.LanguagePy,
+			hasScript:  true,
+		},
+		{
+			name:       "custom job defaults to typescript",
+			targetData: types.TaskTargetData{TaskDefinitionID: 7},
+			expected:   dockertypes.LanguageTS,
+			hasScript:  true,
+		},
+		{
+			name:       "custom job with script language",
+			targetData: types.TaskTargetData{TaskDefinitionID: 7, ScriptLanguage: "go"},
+			expected:   dockertypes.LanguageGo,
+			hasScript:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, ok := scriptLanguage(&tt.targetData)
+			assert.Equal(t, tt.hasScript, ok)
+			assert.Equal(t, tt.expected, language)
+		})
+	}
+}
+
+func TestScriptRunTimes(t *testing.T) {
+	trigger := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("dynamic job runs its script ahead of each trigger", func(t *testing.T) {
+		runTimes := scriptRunTimes(&types.TaskTriggerData{
+			TaskDefinitionID:     2,
+			NextTriggerTimestamp: trigger,
+			TimeScheduleType:     "interval",
+			TimeInterval:         300,
+		})
+		assert.Equal(t, []time.Time{trigger.Add(-dynamicArgsLead), trigger.Add(300*time.Second - dynamicArgsLead)}, runTimes)
+	})
+
+	t.Run("custom job runs its script at each trigger", func(t *testing.T) {
+		runTimes := scriptRunTimes(&types.TaskTriggerData{
+			TaskDefinitionID:     7,
+			NextTriggerTimestamp: trigger,
+			TimeScheduleType:     "interval",
+			TimeInterval:         60,
+		})
+		assert.Equal(t, []time.Time{trigger, trigger.Add(time.Minute)}, runTimes)
+	})
+
+	t.Run("no following run once the job expires", func(t *testing.T) {
+		runTimes := scriptRunTimes(&types.TaskTriggerData{
+			TaskDefinitionID:     7,
+			NextTriggerTimestamp: trigger,
+			ExpirationTime:       trigger.Add(30 * time.Second),
+			TimeScheduleType:     "interval",
+			TimeInterval:         60,
+		})
+		assert.Equal(t, []time.Time{trigger}, runTimes)
+	})
+
+	t.Run("tasks without a trigger timestamp", func(t *testing.T) {
+		assert.Empty(t, scriptRunTimes(&types.TaskTriggerData{TaskDefinitionID: 4}))
+	})
+}
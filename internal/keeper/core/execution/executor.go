@@ -17,9 +17,20 @@ import (
 	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	"github.com/trigg3rX/triggerx-backend/pkg/proof"
+	"github.com/trigg3rX/triggerx-backend/pkg/retry"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
+// receiptProofRetryConfig retries receipt proofs while the action's block propagates to the RPC
+var receiptProofRetryConfig = &retry.RetryConfig{
+	MaxRetries:      6,
+	InitialDelay:    500 * time.Millisecond,
+	MaxDelay:        5 * time.Second,
+	BackoffFactor:   1.5,
+	JitterFactor:    0.3,
+	LogRetryAttempt: true,
+}
+
 // TaskMonitorClientInterface defines the interface for taskmonitor client operations
 type TaskMonitorClientInterface interface {
 	ReportTaskError(ctx context.Context, taskID int64, errorMsg string) error
@@ -171,13 +182,27 @@ func (e *TaskExecutor) ExecuteTask(ctx context.Context, task *types.SendTaskData
 			ipfsData.PerformerSignature.TaskID = task.TaskID[0]
 			ipfsData.PerformerSignature.PerformerSigningAddress = config.GetConsensusAddress()
 
-			tlsConfig := proof.DefaultTLSProofConfig(config.GetTLSProofHost())
-			tlsConfig.TargetPort = config.GetTLSProofPort()
-			proofData, err := proof.GenerateProofWithTLSConnection(ipfsData, tlsConfig)
-			if err != nil {
-				e.logger.Error("Failed to generate TLS proof, falling back to mock", "task_id", task.TaskID, "trace_id", traceID, "error", err)
+			// Prove the action against chain state, only actions without a transaction fall back to a
+			// TLS proof since attesters reject anything but a receipt proof for a transaction
+			var proofData types.ProofData
+			if actionData.ActionTxHash != "" {
+				proofData, err = retry.Retry(ctx, func() (types.ProofData, error) {
+					return proof.GenerateReceiptProof(ctx, client, ipfsData)
+				}, receiptProofRetryConfig, e.logger)
+				if err != nil {
+					e.logger.Error("Failed to generate receipt proof", "task_id", task.TaskID, "trace_id", traceID, "error", err)
+				} else {
+					e.logger.Info("Receipt proof generated successfully", "task_id", task.TaskID, "trace_id", traceID)
+				}
 			} else {
-				e.logger.Info("TLS proof generated successfully", "task_id", task.TaskID, "trace_id", traceID)
+				tlsConfig := proof.DefaultTLSProofConfig(config.GetTLSProofHost())
+				tlsConfig.TargetPort = config.GetTLSProofPort()
+				proofData, err = proof.GenerateProofWithTLSConnection(ipfsData, tlsConfig)
+				if err != nil {
+					e.logger.Error("Failed to generate TLS proof, falling back to mock", "task_id", task.TaskID, "trace_id", traceID, "error", err)
+				} else {
+					e.logger.Info("TLS proof generated successfully", "task_id", task.TaskID, "trace_id", traceID)
+				}
 			}
 
 			ipfsData.ProofData = &proofData
@@ -194,7 +219,12 @@ func (e *TaskExecutor) ExecuteTask(ctx context.Context, task *types.SendTaskData
 				},
 			}
 
-			performerSignature, err := cryptography.SignJSONMessage(ipfsDataForSigning, config.GetPrivateKeyConsensus())
+			performerSignature, err := cryptography.SignStructured(
+				ipfsDataForSigning,
+				ipfsDataForSigning.TaskData.SigningChainID(),
+				config.GetPrivateKeyConsensus(),
+				config.GetSignatureFormat(),
+			)
 			if err != nil {
 				e.logger.Error("Failed to sign the ipfs data", "task_id", task.TaskID, "trace_id", traceID, "error", err)
 				resultCh <- struct {
@@ -1,16 +1,18 @@
 package validation
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/trigg3rX/triggerx-backend/internal/keeper/config"
 	"github.com/trigg3rX/triggerx-backend/pkg/proof"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
-func (v *TaskValidator) ValidateProof(ipfsData types.IPFSData, traceID string) (bool, error) {
+func (v *TaskValidator) ValidateProof(ctx context.Context, ipfsData types.IPFSData, client *ethclient.Client, traceID string) (bool, error) {
 	proofData := ipfsData.ProofData
 	if proofData == nil {
 		return false, fmt.Errorf("proof data is missing")
@@ -18,6 +20,30 @@ func (v *TaskValidator) ValidateProof(ipfsData types.IPFSData, traceID string) (
 	if proofData.ProofOfTask == "" {
 		return false, fmt.Errorf("proof of task is empty")
 	}
+
+	// Actions that sent a transaction must be proven against its receipt, a TLS proof
+	// says nothing about the transaction and would let performers claim any outcome
+	if ipfsData.ActionData != nil && ipfsData.ActionData.ActionTxHash != "" && proofData.ProofVersion() != types.ProofVersionReceipt {
+		return false, fmt.Errorf("action transaction %s requires a receipt proof, got proof version %d", ipfsData.ActionData.ActionTxHash, proofData.ProofVersion())
+	}
+
+	switch proofData.ProofVersion() {
+	case types.ProofVersionReceipt:
+		// The target chain's RPC is the trusted source of canonical headers
+		if err := proof.VerifyReceiptProof(ctx, client, ipfsData); err != nil {
+			return false, fmt.Errorf("receipt proof validation failed: %w", err)
+		}
+		v.logger.Info("Receipt proof validation passed", "trace_id", traceID, "task_id", ipfsData.TaskData.TaskID)
+		return true, nil
+	case types.ProofVersionTLS:
+		return v.validateTLSProof(ipfsData, traceID)
+	default:
+		return false, fmt.Errorf("unsupported proof version %d", proofData.Version)
+	}
+}
+
+func (v *TaskValidator) validateTLSProof(ipfsData types.IPFSData, traceID string) (bool, error) {
+	proofData := ipfsData.ProofData
 	if proofData.CertificateHash == "" {
 		return false, fmt.Errorf("certificate hash is empty")
 	}
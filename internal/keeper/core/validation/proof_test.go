@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func TestValidateProof_ActionTransactionRequiresReceiptProof(t *testing.T) {
+	v := NewTaskValidator("", "", nil, nil, logging.NewNoOpLogger(), nil)
+
+	for _, version := range []int{0, types.ProofVersionTLS} {
+		ipfsData := types.IPFSData{
+			TaskData:   &types.SendTaskDataToKeeper{TaskID: []int64{1}},
+			ActionData: &types.PerformerActionData{TaskID: 1, ActionTxHash: "0x01"},
+			ProofData: &types.ProofData{
+				TaskID:          1,
+				Version:         version,
+				ProofOfTask:     "proof",
+				CertificateHash: "cert",
+			},
+		}
+
+		isValid, err := v.ValidateProof(context.Background(), ipfsData, nil, "trace")
+		require.Error(t, err)
+		assert.False(t, isValid)
+		assert.Contains(t, err.Error(), "requires a receipt proof")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/trigg3rX/triggerx-backend/internal/keeper/config"
+	"github.com/trigg3rX/triggerx-backend/internal/keeper/metrics"
 	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
@@ -26,22 +27,29 @@ func (v *TaskValidator) ValidateManagerSignature(task *types.SendTaskDataToKeepe
 		SchedulerID:   task.SchedulerID,
 	}
 
-	// Convert the task data to JSON message format (same as signing process)
-	isValid, err := cryptography.VerifySignatureFromJSON(
+	// Accepts EIP-712 signatures and, unless turned off, legacy JSON signatures
+	format, err := cryptography.VerifyStructured(
 		taskDataForVerification,
+		task.SigningChainID(),
 		task.ManagerSignature,
 		config.GetManagerSigningAddress(),
+		config.GetAcceptLegacySignatures(),
 	)
 	if err != nil {
 		logger.Error("Failed to verify manager signature", "error", err)
 		return false, fmt.Errorf("failed to verify manager signature: %w", err)
 	}
 
-	if !isValid {
+	if format == "" {
 		logger.Error("Manager signature verification failed")
 		return false, fmt.Errorf("manager signature verification failed")
 	}
 
+	if format == cryptography.SignatureFormatJSON {
+		metrics.LegacySignaturesTotal.WithLabelValues("task_data").Inc()
+		logger.Warn("Manager signed task data in the legacy JSON format")
+	}
+
 	logger.Info("Manager signature verification successful")
 	return true, nil
 }
@@ -82,22 +90,29 @@ func (v *TaskValidator) ValidatePerformerSignature(ipfsData types.IPFSData, trac
 		},
 	}
 
-	// Convert the task data to JSON message format (same as signing process)
-	isValid, err := cryptography.VerifySignatureFromJSON(
+	// Accepts EIP-712 signatures and, unless turned off, legacy JSON signatures
+	format, err := cryptography.VerifyStructured(
 		ipfsDataForVerification,
+		ipfsData.TaskData.SigningChainID(),
 		ipfsData.PerformerSignature.PerformerSignature,
 		ipfsData.PerformerSignature.PerformerSigningAddress,
+		config.GetAcceptLegacySignatures(),
 	)
 	if err != nil {
 		logger.Error("Failed to verify performer signature", "error", err)
 		return false, fmt.Errorf("failed to verify performer signature: %w", err)
 	}
 
-	if !isValid {
+	if format == "" {
 		logger.Error("Performer signature verification failed")
 		return false, fmt.Errorf("performer signature verification failed")
 	}
 
+	if format == cryptography.SignatureFormatJSON {
+		metrics.LegacySignaturesTotal.WithLabelValues("performer_result").Inc()
+		logger.Warn("Performer signed the result in the legacy JSON format", "performer", ipfsData.PerformerSignature.PerformerSigningAddress)
+	}
+
 	logger.Info("Performer signature verification successful")
 	return true, nil
 }
@@ -87,7 +87,7 @@ func (v *TaskValidator) ValidateTask(ctx context.Context, data string, traceID s
 	v.logger.Info("Action validation passed", "task_id", ipfsData.TaskData.TaskID, "trace_id", traceID)
 
 	// validate the proof data
-	isProofTrue, err := v.ValidateProof(ipfsData, traceID)
+	isProofTrue, err := v.ValidateProof(ctx, ipfsData, client, traceID)
 	if !isProofTrue {
 		v.logger.Error("Proof validation failed", "task_id", ipfsData.TaskData.TaskID, "trace_id", traceID, "error", err)
 		return false, err
@@ -168,6 +168,14 @@ var (
 		Name:      "restarts_total",
 		Help:      "Service restart count",
 	})
+
+	// Signatures accepted in the legacy JSON format, message: task_data, performer_result
+	LegacySignaturesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triggerx",
+		Subsystem: "keeper",
+		Name:      "legacy_signatures_total",
+		Help:      "Signatures accepted in the legacy JSON format",
+	}, []string{"message"})
 )
 
 // StartMetricsCollection starts collecting metrics
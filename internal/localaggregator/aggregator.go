@@ -0,0 +1,312 @@
+package localaggregator
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// Aggregator stands in for the Othentic aggregator and attestation center. It forwards custom
+// messages to registered keepers, collects attestations for submitted tasks and emits the
+// resulting TaskSubmitted or TaskRejected event on the simulated chain.
+type Aggregator struct {
+	logger     logging.Logger
+	config     Config
+	chain      *Chain
+	httpClient *http.Client
+
+	mu         sync.RWMutex
+	keepers    []Keeper
+	taskNumber uint32
+
+	// inFlight tracks forwarded messages and attestation rounds
+	inFlight sync.WaitGroup
+}
+
+// NewAggregator creates an aggregator stand-in emitting events on chain
+func NewAggregator(logger logging.Logger, cfg Config, chain *Chain) (*Aggregator, error) {
+	if logger == nil {
+		return nil, fmt.Errorf("logger cannot be nil")
+	}
+	if chain == nil {
+		return nil, fmt.Errorf("chain cannot be nil")
+	}
+	if err := cfg.setDefaults(); err != nil {
+		return nil, err
+	}
+	return &Aggregator{
+		logger:     logger,
+		config:     cfg,
+		chain:      chain,
+		httpClient: &http.Client{Timeout: cfg.RequestTimeout},
+	}, nil
+}
+
+// RegisterKeeper adds a keeper, replacing any keeper registered with the same address
+func (a *Aggregator) RegisterKeeper(keeper Keeper) error {
+	if !common.IsHexAddress(keeper.Address) {
+		return fmt.Errorf("invalid keeper address: %s", keeper.Address)
+	}
+	if keeper.ConsensusAddress != "" && !common.IsHexAddress(keeper.ConsensusAddress) {
+		return fmt.Errorf("invalid consensus address: %s", keeper.ConsensusAddress)
+	}
+	if keeper.URL == "" {
+		return fmt.Errorf("keeper URL cannot be empty")
+	}
+	keeper.URL = strings.TrimSuffix(keeper.URL, "/")
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i, existing := range a.keepers {
+		if strings.EqualFold(existing.Address, keeper.Address) {
+			a.keepers[i] = keeper
+			return nil
+		}
+	}
+	a.keepers = append(a.keepers, keeper)
+	a.logger.Info("Keeper registered", "address", keeper.Address, "attester_id", keeper.AttesterID, "url", keeper.URL)
+	return nil
+}
+
+// Keepers returns the registered keepers
+func (a *Aggregator) Keepers() []Keeper {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return append([]Keeper(nil), a.keepers...)
+}
+
+// Wait blocks until forwarded messages are answered and attestation rounds have emitted their event
+func (a *Aggregator) Wait() {
+	a.inFlight.Wait()
+}
+
+// SendCustomMessage broadcasts the message to every keeper's /p2p/message, as the Othentic
+// p2p network does. Keepers decide on their own whether they are the performer.
+func (a *Aggregator) SendCustomMessage(data string, taskDefinitionID int) error {
+	if _, err := decodeHex(data); err != nil {
+		return fmt.Errorf("invalid message data: %w", err)
+	}
+
+	keepers := a.Keepers()
+	a.logger.Info("Broadcasting custom message", "task_definition_id", taskDefinitionID, "keepers", len(keepers))
+
+	body, err := json.Marshal(map[string]string{"data": data})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	for _, keeper := range keepers {
+		a.inFlight.Add(1)
+		go func(keeper Keeper) {
+			defer a.inFlight.Done()
+			if err := a.post(keeper.URL+"/p2p/message", body, nil); err != nil {
+				a.logger.Warn("Failed to forward custom message", "keeper", keeper.Address, "error", err)
+			}
+		}(keeper)
+	}
+	return nil
+}
+
+// SendTask accepts a performer's task result and starts an attestation round for it
+func (a *Aggregator) SendTask(proofOfTask, data string, taskDefinitionID int, performerAddress, signature, signatureType string, targetChainID int) error {
+	if signatureType != "ecdsa" {
+		return fmt.Errorf("unsupported signature type: %s", signatureType)
+	}
+	if taskDefinitionID < 0 || taskDefinitionID > 0xffff {
+		return fmt.Errorf("invalid task definition ID: %d", taskDefinitionID)
+	}
+	decoded, err := decodeHex(data)
+	if err != nil {
+		return fmt.Errorf("invalid task data: %w", err)
+	}
+
+	performer, ok := a.keeperBySigner(performerAddress)
+	if !ok {
+		return fmt.Errorf("performer %s is not a registered keeper", performerAddress)
+	}
+	if err := verifyTaskSignature(proofOfTask, decoded, performer, taskDefinitionID, signature); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.taskNumber++
+	task := submittedTask{
+		TaskNumber:       a.taskNumber,
+		ProofOfTask:      proofOfTask,
+		Data:             decoded,
+		TaskDefinitionID: uint16(taskDefinitionID),
+		Performer:        performer,
+		PerformerAddress: performerAddress,
+	}
+	a.mu.Unlock()
+
+	a.logger.Info("Task received", "task_number", task.TaskNumber, "task_definition_id", taskDefinitionID, "performer", performerAddress, "target_chain_id", targetChainID)
+
+	a.inFlight.Add(1)
+	go func() {
+		defer a.inFlight.Done()
+		a.attest(task)
+	}()
+	return nil
+}
+
+// attest asks every keeper but the performer to validate the task and emits the outcome
+func (a *Aggregator) attest(task submittedTask) {
+	var attesters []Keeper
+	for _, keeper := range a.Keepers() {
+		if !strings.EqualFold(keeper.Address, task.Performer.Address) {
+			attesters = append(attesters, keeper)
+		}
+	}
+
+	body, err := json.Marshal(validationRequest{
+		ProofOfTask:      task.ProofOfTask,
+		Data:             hexutil.Encode(task.Data),
+		TaskDefinitionID: task.TaskDefinitionID,
+		Performer:        task.PerformerAddress,
+	})
+	if err != nil {
+		a.logger.Error("Failed to marshal validation request", "task_number", task.TaskNumber, "error", err)
+		return
+	}
+
+	votes := make([]*bool, len(attesters))
+	var wg sync.WaitGroup
+	for i, attester := range attesters {
+		wg.Add(1)
+		go func(i int, attester Keeper) {
+			defer wg.Done()
+			var response validationResponse
+			if err := a.post(attester.URL+"/task/validate", body, &response); err != nil {
+				a.logger.Warn("Attester did not respond", "task_number", task.TaskNumber, "attester", attester.Address, "error", err)
+				return
+			}
+			approved := response.Data && !response.Error
+			votes[i] = &approved
+		}(i, attester)
+	}
+	wg.Wait()
+
+	eventName, attesterIDs := tally(attesters, votes, a.config.QuorumPercent)
+	lg, err := a.chain.EmitTaskEvent(eventName, task.PerformerAddress, task, attesterIDs)
+	if err != nil {
+		a.logger.Error("Failed to emit task event", "task_number", task.TaskNumber, "error", err)
+		return
+	}
+	a.logger.Info("Task attested", "task_number", task.TaskNumber, "event", eventName, "attesters", attesterIDs, "block", lg.BlockNumber)
+}
+
+// tally decides the outcome of an attestation round. Unanswered attestations count towards
+// the total voting power but not towards either side. With no attesters the task is accepted,
+// so a single keeper devnet can make progress.
+func tally(attesters []Keeper, votes []*bool, quorumPercent uint64) (string, []int64) {
+	var total, approvedPower uint64
+	approvers := []int64{}
+	rejecters := []int64{}
+	for i, attester := range attesters {
+		total += attester.power()
+		if votes[i] == nil {
+			continue
+		}
+		if *votes[i] {
+			approvedPower += attester.power()
+			approvers = append(approvers, attester.AttesterID)
+		} else {
+			rejecters = append(rejecters, attester.AttesterID)
+		}
+	}
+	if approvedPower*100 >= total*quorumPercent {
+		return EventTaskSubmitted, approvers
+	}
+	return EventTaskRejected, rejecters
+}
+
+func (a *Aggregator) keeperBySigner(address string) (Keeper, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, keeper := range a.keepers {
+		if strings.EqualFold(keeper.signer(), address) {
+			return keeper, true
+		}
+	}
+	return Keeper{}, false
+}
+
+// verifyTaskSignature checks the sendTask signature, an ECDSA signature by the keeper's
+// consensus key over keccak256(abi.encode(proofOfTask, data, keeperAddress, taskDefinitionId))
+func verifyTaskSignature(proofOfTask string, data []byte, performer Keeper, taskDefinitionID int, signature string) error {
+	arguments := abi.Arguments{
+		{Type: abi.Type{T: abi.StringTy}},
+		{Type: abi.Type{T: abi.BytesTy}},
+		{Type: abi.Type{T: abi.AddressTy}},
+		{Type: abi.Type{T: abi.UintTy}},
+	}
+	packed, err := arguments.Pack(proofOfTask, data, common.HexToAddress(performer.Address), big.NewInt(int64(taskDefinitionID)))
+	if err != nil {
+		return fmt.Errorf("failed to encode task data: %w", err)
+	}
+
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return fmt.Errorf("invalid signature encoding")
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	publicKey, err := crypto.SigToPub(crypto.Keccak256(packed), sig)
+	if err != nil {
+		return fmt.Errorf("failed to recover signer: %w", err)
+	}
+	if recovered := crypto.PubkeyToAddress(*publicKey); !strings.EqualFold(recovered.Hex(), performer.signer()) {
+		return fmt.Errorf("task signed by %s, not performer %s", recovered.Hex(), performer.signer())
+	}
+	return nil
+}
+
+// post sends a JSON body to a keeper endpoint and decodes the JSON response into result
+func (a *Aggregator) post(url string, body []byte, result interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func decodeHex(data string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(data, "0x"))
+}
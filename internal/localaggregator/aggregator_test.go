@@ -0,0 +1,323 @@
+package localaggregator
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/client/aggregator"
+	"github.com/trigg3rX/triggerx-backend/pkg/client/nodeclient"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+const testAttestationCenter = "0x710DAb96f318b16F0fC9962D3466C00275414Ff0"
+
+// fakeKeeper serves the keeper endpoints the stand-in calls
+type fakeKeeper struct {
+	keeper     Keeper
+	privateKey string
+	approve    bool
+
+	mu          sync.Mutex
+	messages    []string
+	validations []validationRequest
+}
+
+func newFakeKeeper(t *testing.T, attesterID int64, approve bool) *fakeKeeper {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	fk := &fakeKeeper{
+		privateKey: hex.EncodeToString(crypto.FromECDSA(key)),
+		approve:    approve,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/p2p/message", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Data string `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		fk.mu.Lock()
+		fk.messages = append(fk.messages, body.Data)
+		fk.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/task/validate", func(w http.ResponseWriter, r *http.Request) {
+		var req validationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		fk.mu.Lock()
+		fk.validations = append(fk.validations, req)
+		fk.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(validationResponse{Data: fk.approve})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	// Keepers sign sendTask with their consensus key but pack their keeper address
+	fk.keeper = Keeper{
+		AttesterID:       attesterID,
+		Address:          common.BigToAddress(big.NewInt(attesterID)).Hex(),
+		ConsensusAddress: crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		URL:              server.URL,
+	}
+	return fk
+}
+
+func (fk *fakeKeeper) client(t *testing.T, url string) *aggregator.AggregatorClient {
+	t.Helper()
+	client, err := aggregator.NewAggregatorClient(logging.NewNoOpLogger(), aggregator.AggregatorClientConfig{
+		AggregatorRPCUrl: url,
+		SenderPrivateKey: fk.privateKey,
+		SenderAddress:    fk.keeper.Address,
+	})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+type testNetwork struct {
+	chain      *Chain
+	aggregator *Aggregator
+	url        string
+	keepers    []*fakeKeeper
+}
+
+func newTestNetwork(t *testing.T, votes ...bool) *testNetwork {
+	t.Helper()
+
+	chain, err := NewChain(84532, testAttestationCenter)
+	require.NoError(t, err)
+	chainServer, err := chain.RPCServer()
+	require.NoError(t, err)
+	t.Cleanup(chainServer.Stop)
+
+	agg, err := NewAggregator(logging.NewNoOpLogger(), Config{}, chain)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle("/chain", chainServer)
+	mux.Handle("/", agg)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	network := &testNetwork{chain: chain, aggregator: agg, url: server.URL}
+	for i, vote := range votes {
+		fk := newFakeKeeper(t, int64(i+1), vote)
+		require.NoError(t, agg.RegisterKeeper(fk.keeper))
+		network.keepers = append(network.keepers, fk)
+	}
+	return network
+}
+
+// taskEvents fetches the AttestationCenter logs the way taskmonitor's listener polls them
+func (n *testNetwork) taskEvents(t *testing.T, eventName string) []map[string]interface{} {
+	t.Helper()
+
+	client, err := nodeclient.NewNodeClient(&nodeclient.Config{BaseURL: n.url + "/chain", Logger: logging.NewNoOpLogger()})
+	require.NoError(t, err)
+	defer client.Close()
+
+	head, err := client.EthBlockNumber(context.Background())
+	require.NoError(t, err)
+
+	ev := n.chain.abi.Events[eventName]
+	from := nodeclient.BlockNumber("0x1")
+	to := nodeclient.BlockNumber(head)
+	logs, err := client.EthGetLogs(context.Background(), nodeclient.EthGetLogsParams{
+		FromBlock: &from,
+		ToBlock:   &to,
+		Address:   testAttestationCenter,
+		Topics:    []interface{}{ev.ID.Hex()},
+	})
+	require.NoError(t, err)
+
+	var events []map[string]interface{}
+	for _, lg := range logs {
+		data, err := hex.DecodeString(lg.Data[2:])
+		require.NoError(t, err)
+		parsed := make(map[string]interface{})
+		require.NoError(t, ev.Inputs.UnpackIntoMap(parsed, data))
+		parsed["operator"] = common.HexToAddress(lg.Topics[1]).Hex()
+		parsed["taskDefinitionId"] = common.HexToHash(lg.Topics[2]).Big().Int64()
+		events = append(events, parsed)
+	}
+	return events
+}
+
+func TestSendCustomMessage_BroadcastsToKeepers(t *testing.T) {
+	network := newTestNetwork(t, true, true)
+
+	task := types.BroadcastDataForPerformer{TaskID: 1, TaskDefinitionID: 1, Data: []byte(`{"task_id":[1]}`)}
+	ok, err := network.keepers[0].client(t, network.url).SendTaskToPerformer(context.Background(), &task)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	network.aggregator.Wait()
+	for _, fk := range network.keepers {
+		assert.Equal(t, []string{"0x" + hex.EncodeToString(task.Data)}, fk.messages)
+	}
+}
+
+func TestSendTask_EmitsQuorumOutcome(t *testing.T) {
+	tests := []struct {
+		name        string
+		votes       []bool
+		event       string
+		attesterIDs []*big.Int
+	}{
+		{
+			name:        "quorum approves",
+			votes:       []bool{true, true, true, false},
+			event:       EventTaskSubmitted,
+			attesterIDs: []*big.Int{big.NewInt(2), big.NewInt(3)},
+		},
+		{
+			name:        "quorum not reached",
+			votes:       []bool{true, true, false, false},
+			event:       EventTaskRejected,
+			attesterIDs: []*big.Int{big.NewInt(3), big.NewInt(4)},
+		},
+		{
+			name:        "single keeper",
+			votes:       []bool{true},
+			event:       EventTaskSubmitted,
+			attesterIDs: []*big.Int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := newTestNetwork(t, tt.votes...)
+			performer := network.keepers[0]
+
+			result := types.BroadcastDataForValidators{
+				ProofOfTask:      "proof",
+				Data:             []byte("QmCID"),
+				TaskDefinitionID: 7,
+			}
+			ok, err := performer.client(t, network.url).SendTaskToValidators(context.Background(), &result)
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			network.aggregator.Wait()
+			assert.Empty(t, performer.validations, "the performer does not attest its own task")
+			for _, fk := range network.keepers[1:] {
+				require.Len(t, fk.validations, 1)
+				assert.Equal(t, "0x"+hex.EncodeToString(result.Data), fk.validations[0].Data)
+				assert.Equal(t, uint16(7), fk.validations[0].TaskDefinitionID)
+			}
+
+			events := network.taskEvents(t, tt.event)
+			require.Len(t, events, 1)
+			assert.Equal(t, uint32(1), events[0]["taskNumber"])
+			assert.Equal(t, "proof", events[0]["proofOfTask"])
+			assert.Equal(t, result.Data, events[0]["data"])
+			assert.Equal(t, int64(7), events[0]["taskDefinitionId"])
+			assert.Equal(t, performer.keeper.ConsensusAddress, events[0]["operator"])
+			assert.Equal(t, tt.attesterIDs, events[0]["attestersIds"])
+		})
+	}
+}
+
+func TestSendTask_RejectsInvalidSubmissions(t *testing.T) {
+	network := newTestNetwork(t, true, true)
+	performer := network.keepers[0].keeper
+
+	client, err := rpc.DialHTTP(network.url)
+	require.NoError(t, err)
+	defer client.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	stranger := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	sig, err := crypto.Sign(crypto.Keccak256([]byte("proof")), key)
+	require.NoError(t, err)
+	signature := "0x" + hex.EncodeToString(sig)
+
+	tests := []struct {
+		name      string
+		performer string
+		data      string
+		errMsg    string
+	}{
+		{name: "unregistered performer", performer: stranger, data: "0x01", errMsg: "is not a registered keeper"},
+		{name: "invalid signature", performer: performer.ConsensusAddress, data: "0x01", errMsg: "not performer"},
+		{name: "invalid data", performer: performer.ConsensusAddress, data: "0xzz", errMsg: "invalid task data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result interface{}
+			err := client.Call(&result, "sendTask", "proof", tt.data, 1, tt.performer, signature, "ecdsa", 8453)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+
+	var result interface{}
+	err = client.Call(&result, "unknownMethod")
+	assert.ErrorContains(t, err, "not found")
+
+	network.aggregator.Wait()
+	assert.Equal(t, uint64(1), network.chain.BlockNumber(), "no event is emitted")
+}
+
+func TestRegisterKeeper_OverRPC(t *testing.T) {
+	network := newTestNetwork(t)
+
+	client, err := rpc.DialHTTP(network.url)
+	require.NoError(t, err)
+	defer client.Close()
+
+	keeper := Keeper{AttesterID: 9, Address: "0x0a067a261c5F5e8C4c0b9137430b4FE1255EB62e", URL: "http://localhost:9011/"}
+	var result bool
+	require.NoError(t, client.Call(&result, "registerKeeper", keeper))
+	assert.True(t, result)
+
+	// Registering the same address again replaces the keeper
+	keeper.URL = "http://localhost:9021"
+	require.NoError(t, client.Call(&result, "registerKeeper", keeper))
+	assert.Equal(t, []Keeper{keeper}, network.aggregator.Keepers())
+
+	err = client.Call(&result, "registerKeeper", Keeper{Address: "not-an-address", URL: "http://localhost"})
+	assert.ErrorContains(t, err, "invalid keeper address")
+}
+
+func TestTally(t *testing.T) {
+	approve, reject := true, false
+	attesters := []Keeper{
+		{AttesterID: 1, VotingPower: 5},
+		{AttesterID: 2},
+		{AttesterID: 3},
+	}
+
+	tests := []struct {
+		votes []*bool
+		event string
+		ids   []int64
+	}{
+		{votes: []*bool{&approve, &reject, &reject}, event: EventTaskSubmitted, ids: []int64{1}},
+		{votes: []*bool{&reject, &approve, &approve}, event: EventTaskRejected, ids: []int64{1}},
+		{votes: []*bool{nil, &approve, &approve}, event: EventTaskRejected, ids: []int64{}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			event, ids := tally(attesters, tt.votes, defaultQuorumPercent)
+			assert.Equal(t, tt.event, event)
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
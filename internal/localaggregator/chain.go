@@ -0,0 +1,270 @@
+package localaggregator
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	contractAttestationCenter "github.com/trigg3rX/triggerx-contracts/bindings/contracts/AttestationCenter"
+)
+
+// Chain is a simulated chain holding the AttestationCenter logs the stand-in emits.
+// Every emitted event is mined in its own block, and the chain serves the eth_chainId,
+// eth_blockNumber and eth_getLogs calls taskmonitor's listener polls with.
+type Chain struct {
+	mu       sync.RWMutex
+	chainID  *big.Int
+	contract common.Address
+	abi      *abi.ABI
+	head     uint64
+	logs     []*ethtypes.Log
+}
+
+// NewChain creates a simulated chain with the AttestationCenter at contractAddress
+func NewChain(chainID int64, contractAddress string) (*Chain, error) {
+	if !common.IsHexAddress(contractAddress) {
+		return nil, fmt.Errorf("invalid attestation center address: %s", contractAddress)
+	}
+	attABI, err := contractAttestationCenter.ContractAttestationCenterMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AttestationCenter ABI: %w", err)
+	}
+	return &Chain{
+		chainID:  big.NewInt(chainID),
+		contract: common.HexToAddress(contractAddress),
+		abi:      attABI,
+		head:     1,
+	}, nil
+}
+
+// BlockNumber returns the current head of the chain
+func (c *Chain) BlockNumber() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.head
+}
+
+// EventLogs returns every TaskSubmitted or TaskRejected log emitted so far
+func (c *Chain) EventLogs(eventName string) ([]*ethtypes.Log, error) {
+	ev, ok := c.abi.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %s", eventName)
+	}
+	return c.FilterLogs(0, c.BlockNumber(), nil, [][]common.Hash{{ev.ID}}), nil
+}
+
+// EmitTaskEvent mines a block with a TaskSubmitted or TaskRejected log for the task
+func (c *Chain) EmitTaskEvent(eventName string, operator string, task submittedTask, attesterIDs []int64) (*ethtypes.Log, error) {
+	ev, ok := c.abi.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found in AttestationCenter ABI", eventName)
+	}
+
+	ids := make([]*big.Int, len(attesterIDs))
+	for i, id := range attesterIDs {
+		ids[i] = big.NewInt(id)
+	}
+	data, err := ev.Inputs.NonIndexed().Pack(task.TaskNumber, task.ProofOfTask, task.Data, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s data: %w", eventName, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.head++
+	lg := &ethtypes.Log{
+		Address: c.contract,
+		Topics: []common.Hash{
+			ev.ID,
+			common.BytesToHash(common.HexToAddress(operator).Bytes()),
+			common.BigToHash(big.NewInt(int64(task.TaskDefinitionID))),
+		},
+		Data:        data,
+		BlockNumber: c.head,
+		TxHash:      crypto.Keccak256Hash(c.chainID.Bytes(), binary.BigEndian.AppendUint32(nil, task.TaskNumber), ev.ID.Bytes()),
+		TxIndex:     0,
+		BlockHash:   crypto.Keccak256Hash(c.chainID.Bytes(), binary.BigEndian.AppendUint64(nil, c.head)),
+		Index:       0,
+	}
+	c.logs = append(c.logs, lg)
+	return lg, nil
+}
+
+// FilterLogs returns the logs in [from, to] matching the addresses and topics, with the
+// semantics of eth_getLogs
+func (c *Chain) FilterLogs(from, to uint64, addresses []common.Address, topics [][]common.Hash) []*ethtypes.Log {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var matched []*ethtypes.Log
+	for _, lg := range c.logs {
+		if lg.BlockNumber < from || lg.BlockNumber > to {
+			continue
+		}
+		if len(addresses) > 0 && !containsAddress(addresses, lg.Address) {
+			continue
+		}
+		if !matchTopics(topics, lg.Topics) {
+			continue
+		}
+		copied := *lg
+		matched = append(matched, &copied)
+	}
+	return matched
+}
+
+// RPCServer returns a JSON-RPC server for the eth namespace methods the listener uses
+func (c *Chain) RPCServer() (*rpc.Server, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &ethAPI{chain: c}); err != nil {
+		return nil, fmt.Errorf("failed to register eth API: %w", err)
+	}
+	return server, nil
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func matchTopics(filter [][]common.Hash, topics []common.Hash) bool {
+	if len(filter) > len(topics) {
+		return false
+	}
+	for i, alternatives := range filter {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range alternatives {
+			if topic == topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ethAPI implements the eth namespace subset of the simulated chain
+type ethAPI struct {
+	chain *Chain
+}
+
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(api.chain.chainID)
+}
+
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.chain.BlockNumber())
+}
+
+func (api *ethAPI) GetLogs(filter logFilter) ([]*ethtypes.Log, error) {
+	head := api.chain.BlockNumber()
+	from, err := resolveBlock(filter.FromBlock, head)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fromBlock: %w", err)
+	}
+	to, err := resolveBlock(filter.ToBlock, head)
+	if err != nil {
+		return nil, fmt.Errorf("invalid toBlock: %w", err)
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: %d > %d", from, to)
+	}
+
+	logs := api.chain.FilterLogs(from, to, filter.Addresses, filter.Topics)
+	if logs == nil {
+		logs = []*ethtypes.Log{}
+	}
+	return logs, nil
+}
+
+// logFilter is the eth_getLogs filter object, address and each topic position may be a
+// single value or a list
+type logFilter struct {
+	FromBlock string
+	ToBlock   string
+	Addresses []common.Address
+	Topics    [][]common.Hash
+}
+
+func (f *logFilter) UnmarshalJSON(input []byte) error {
+	var raw struct {
+		FromBlock string            `json:"fromBlock"`
+		ToBlock   string            `json:"toBlock"`
+		Address   json.RawMessage   `json:"address"`
+		Topics    []json.RawMessage `json:"topics"`
+	}
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+	f.FromBlock = raw.FromBlock
+	f.ToBlock = raw.ToBlock
+
+	addresses, err := decodeOneOrMany(raw.Address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	for _, address := range addresses {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid address: %s", address)
+		}
+		f.Addresses = append(f.Addresses, common.HexToAddress(address))
+	}
+
+	f.Topics = make([][]common.Hash, len(raw.Topics))
+	for i, position := range raw.Topics {
+		topics, err := decodeOneOrMany(position)
+		if err != nil {
+			return fmt.Errorf("invalid topic %d: %w", i, err)
+		}
+		for _, topic := range topics {
+			f.Topics[i] = append(f.Topics[i], common.HexToHash(topic))
+		}
+	}
+	return nil
+}
+
+// decodeOneOrMany decodes null, a string or a list of strings
+func decodeOneOrMany(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var one string
+	if err := json.Unmarshal(raw, &one); err == nil {
+		return []string{one}, nil
+	}
+	var many []string
+	if err := json.Unmarshal(raw, &many); err != nil {
+		return nil, err
+	}
+	return many, nil
+}
+
+func resolveBlock(block string, head uint64) (uint64, error) {
+	switch block {
+	case "", "latest", "pending", "safe", "finalized":
+		return head, nil
+	case "earliest":
+		return 0, nil
+	}
+	return hexutil.DecodeUint64(block)
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/trigg3rX/triggerx-backend/internal/localaggregator"
+	"github.com/trigg3rX/triggerx-backend/pkg/env"
+)
+
+type Config struct {
+	devMode bool
+
+	// Port the aggregator JSON-RPC and the simulated chain are served on
+	rpcPort string
+
+	// Simulated chain the AttestationCenter events are emitted on
+	chainID                  int64
+	attestationCenterAddress string
+
+	// Share of attester voting power needed to accept a task
+	quorumPercent int
+	// Timeout of calls to keeper endpoints
+	requestTimeout time.Duration
+
+	// Keepers known at startup, more can register over JSON-RPC
+	keepers []localaggregator.Keeper
+}
+
+var cfg Config
+
+func Init() error {
+	if err := godotenv.Load(); err != nil {
+		return fmt.Errorf("error loading .env file: %w", err)
+	}
+	cfg = Config{
+		devMode:                  env.GetEnvBool("DEV_MODE", false),
+		rpcPort:                  env.GetEnvString("LOCAL_AGGREGATOR_RPC_PORT", "9001"),
+		chainID:                  int64(env.GetEnvInt("LOCAL_AGGREGATOR_CHAIN_ID", 84532)),
+		attestationCenterAddress: env.GetEnvString("ATTESTATION_CENTER_ADDRESS", ""),
+		quorumPercent:            env.GetEnvInt("LOCAL_AGGREGATOR_QUORUM_PERCENT", 66),
+		requestTimeout:           env.GetEnvDuration("LOCAL_AGGREGATOR_REQUEST_TIMEOUT", 2*time.Minute),
+	}
+	keepers, err := parseKeepers(env.GetEnvString("LOCAL_AGGREGATOR_KEEPERS", ""))
+	if err != nil {
+		return fmt.Errorf("invalid local aggregator keepers: %w", err)
+	}
+	cfg.keepers = keepers
+	if err := validateConfig(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return nil
+}
+
+func validateConfig() error {
+	if !env.IsValidPort(cfg.rpcPort) {
+		return fmt.Errorf("invalid local aggregator RPC port: %s", cfg.rpcPort)
+	}
+	if cfg.chainID <= 0 {
+		return fmt.Errorf("invalid local aggregator chain ID: %d", cfg.chainID)
+	}
+	if !env.IsValidEthAddress(cfg.attestationCenterAddress) {
+		return fmt.Errorf("invalid attestation center address: %s", cfg.attestationCenterAddress)
+	}
+	if cfg.quorumPercent <= 0 || cfg.quorumPercent > 100 {
+		return fmt.Errorf("invalid quorum percent: %d", cfg.quorumPercent)
+	}
+	return nil
+}
+
+// parseKeepers parses comma separated attesterID@address@url entries, optionally followed by
+// @consensusAddress when the keeper signs with a different key
+func parseKeepers(value string) ([]localaggregator.Keeper, error) {
+	var keepers []localaggregator.Keeper
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "@")
+		if len(parts) != 3 && len(parts) != 4 {
+			return nil, fmt.Errorf("expected attesterID@address@url[@consensusAddress], got %s", entry)
+		}
+		attesterID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attester ID %s: %w", parts[0], err)
+		}
+		keeper := localaggregator.Keeper{
+			AttesterID: attesterID,
+			Address:    parts[1],
+			URL:        parts[2],
+		}
+		if len(parts) == 4 {
+			keeper.ConsensusAddress = parts[3]
+		}
+		keepers = append(keepers, keeper)
+	}
+	return keepers, nil
+}
+
+func IsDevMode() bool {
+	return cfg.devMode
+}
+
+func GetRPCPort() string {
+	return cfg.rpcPort
+}
+
+func GetChainID() int64 {
+	return cfg.chainID
+}
+
+func GetAttestationCenterAddress() string {
+	return cfg.attestationCenterAddress
+}
+
+func GetQuorumPercent() uint64 {
+	return uint64(cfg.quorumPercent)
+}
+
+func GetRequestTimeout() time.Duration {
+	return cfg.requestTimeout
+}
+
+func GetKeepers() []localaggregator.Keeper {
+	return cfg.keepers
+}
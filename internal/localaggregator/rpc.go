@@ -0,0 +1,109 @@
+package localaggregator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	rpcParseError     = -32700
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+)
+
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// ServeHTTP serves the aggregator JSON-RPC methods. Their names carry no namespace, so they
+// are dispatched here rather than through go-ethereum's rpc.Server.
+func (a *Aggregator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeRPCResponse(w, rpcResponse{Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+		return
+	}
+
+	result, rpcErr := a.dispatch(req)
+	writeRPCResponse(w, rpcResponse{ID: req.ID, Result: result, Error: rpcErr})
+}
+
+func (a *Aggregator) dispatch(req rpcRequest) (interface{}, *rpcError) {
+	var err error
+	switch req.Method {
+	case "sendCustomMessage":
+		var (
+			data             string
+			taskDefinitionID int
+		)
+		if err := decodeParams(req.Params, &data, &taskDefinitionID); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		err = a.SendCustomMessage(data, taskDefinitionID)
+	case "sendTask":
+		var (
+			proofOfTask, data, performerAddress, signature, signatureType string
+			taskDefinitionID, targetChainID                               int
+		)
+		if err := decodeParams(req.Params, &proofOfTask, &data, &taskDefinitionID, &performerAddress, &signature, &signatureType, &targetChainID); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		err = a.SendTask(proofOfTask, data, taskDefinitionID, performerAddress, signature, signatureType, targetChainID)
+	case "registerKeeper":
+		var keeper Keeper
+		if err := decodeParams(req.Params, &keeper); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		err = a.RegisterKeeper(keeper)
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method %s not found", req.Method)}
+	}
+	if err != nil {
+		return nil, &rpcError{Code: rpcServerError, Message: err.Error()}
+	}
+	return true, nil
+}
+
+// decodeParams decodes positional params into targets, all of them are required
+func decodeParams(params []json.RawMessage, targets ...interface{}) error {
+	if len(params) < len(targets) {
+		return fmt.Errorf("expected %d params, got %d", len(targets), len(params))
+	}
+	for i, target := range targets {
+		if err := json.Unmarshal(params[i], target); err != nil {
+			return fmt.Errorf("invalid param %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func writeRPCResponse(w http.ResponseWriter, resp rpcResponse) {
+	resp.JSONRPC = "2.0"
+	if resp.ID == nil {
+		resp.ID = json.RawMessage("null")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,90 @@
+package localaggregator
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// EventTaskSubmitted and EventTaskRejected are the AttestationCenter events emitted after quorum
+	EventTaskSubmitted = "TaskSubmitted"
+	EventTaskRejected  = "TaskRejected"
+
+	defaultQuorumPercent  = 66
+	defaultRequestTimeout = 2 * time.Minute
+)
+
+// Keeper is an operator the stand-in forwards custom messages to and collects attestations from
+type Keeper struct {
+	// AttesterID is the operator ID reported in the attestersIds of emitted events
+	AttesterID int64 `json:"attester_id"`
+	// Address is the keeper address, the one the keeper packs into its sendTask signature
+	Address string `json:"address"`
+	// ConsensusAddress is the address the keeper signs sendTask with, defaults to Address
+	ConsensusAddress string `json:"consensus_address,omitempty"`
+	// URL is the base URL of the keeper API serving /p2p/message and /task/validate
+	URL string `json:"url"`
+	// VotingPower weighs the keeper's attestation in the quorum, defaults to 1
+	VotingPower uint64 `json:"voting_power,omitempty"`
+}
+
+func (k Keeper) signer() string {
+	if k.ConsensusAddress != "" {
+		return k.ConsensusAddress
+	}
+	return k.Address
+}
+
+func (k Keeper) power() uint64 {
+	if k.VotingPower == 0 {
+		return 1
+	}
+	return k.VotingPower
+}
+
+// Config holds the aggregator stand-in settings
+type Config struct {
+	// QuorumPercent is the share of attester voting power that must approve a task, defaults to 66
+	QuorumPercent uint64
+	// RequestTimeout bounds calls to keeper endpoints, defaults to 2 minutes as keepers execute
+	// tasks before answering /p2p/message
+	RequestTimeout time.Duration
+}
+
+func (c *Config) setDefaults() error {
+	if c.QuorumPercent == 0 {
+		c.QuorumPercent = defaultQuorumPercent
+	}
+	if c.QuorumPercent > 100 {
+		return fmt.Errorf("quorum percent must be at most 100, got %d", c.QuorumPercent)
+	}
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = defaultRequestTimeout
+	}
+	return nil
+}
+
+// submittedTask is a performer's sendTask call awaiting attestation
+type submittedTask struct {
+	TaskNumber       uint32
+	ProofOfTask      string
+	Data             []byte
+	TaskDefinitionID uint16
+	Performer        Keeper
+	PerformerAddress string
+}
+
+// validationRequest is the body keepers expect on /task/validate
+type validationRequest struct {
+	ProofOfTask      string `json:"proofOfTask"`
+	Data             string `json:"data"`
+	TaskDefinitionID uint16 `json:"taskDefinitionId"`
+	Performer        string `json:"performer"`
+}
+
+// validationResponse is the attestation keepers answer /task/validate with
+type validationResponse struct {
+	Data    bool   `json:"data"`
+	Error   bool   `json:"error"`
+	Message string `json:"message,omitempty"`
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -79,7 +80,14 @@ func (h *SchedulerHandler) UnscheduleJob(c *gin.Context) {
 	traceID := getTraceID(c)
 	h.logger.Info("[UnscheduleJob] trace_id=" + traceID + " - Unscheduling job")
 
+	// The job is in the path, or in the body as the DBServer sends it
 	jobIDStr := c.Param("job_id")
+	if jobIDStr == "" {
+		var body types.ScheduleConditionJobData
+		if err := c.ShouldBindJSON(&body); err == nil && body.JobID != nil && body.JobID.Int != nil {
+			jobIDStr = body.JobID.String()
+		}
+	}
 	jobID := new(big.Int)
 	_, ok := jobID.SetString(jobIDStr, 10)
 	if !ok {
@@ -94,8 +102,19 @@ func (h *SchedulerHandler) UnscheduleJob(c *gin.Context) {
 		return
 	}
 
-	// Unschedule the job
-	if err := h.scheduler.UnscheduleJob(jobID); err != nil {
+	// Unschedule the job, a job that is not scheduled is already stopped
+	err := h.scheduler.UnscheduleJob(jobID)
+	if errors.Is(err, scheduler.ErrJobNotScheduled) {
+		h.logger.Info("Condition job is not scheduled", "job_id", jobID)
+		c.JSON(http.StatusOK, gin.H{
+			"status":    "success",
+			"message":   "Condition job is not scheduled",
+			"job_id":    jobID,
+			"timestamp": time.Now().UTC(),
+		})
+		return
+	}
+	if err != nil {
 		h.logger.Error("Failed to unschedule condition job", "job_id", jobID, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":    "error",
@@ -1,12 +1,15 @@
 package config
 
 import (
+	"crypto/ed25519"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/env"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
 )
 
 type Config struct {
@@ -14,6 +17,8 @@ type Config struct {
 
 	// Scheduler RPC Port
 	conditionSchedulerRPCPort string
+	// Scheduler gRPC Port, the typed gRPC API is disabled when empty
+	conditionSchedulerGRPCPort string
 
 	// Database RPC URL
 	dbServerURL string
@@ -21,6 +26,12 @@ type Config struct {
 	aggregatorRPCURL string
 	// Task Dispatcher RPC URL
 	taskDispatcherRPCUrl string
+	// Mutual TLS certificate used to call the task dispatcher
+	rpcTLS mtls.Config
+	// Key signing the service tokens the DBServer and task dispatcher are called with, none when empty
+	serviceTokenKey ed25519.PrivateKey
+	// Keys the service tokens of RPC callers are verified with
+	serviceTokenPublicKeys []ed25519.PublicKey
 
 	// Scheduler ID for consumer groups
 	conditionSchedulerID int
@@ -47,17 +58,41 @@ func Init() error {
 	if err := godotenv.Load(); err != nil {
 		return fmt.Errorf("error loading .env file: %w", err)
 	}
+	return Load()
+}
+
+// Load reads the configuration from the environment only, for processes that set it
+// themselves instead of shipping a .env file.
+func Load() error {
 	cfg = Config{
-		devMode:                   env.GetEnvBool("DEV_MODE", false),
-		conditionSchedulerRPCPort: env.GetEnvString("CONDITION_SCHEDULER_RPC_PORT", "9006"),
-		dbServerURL:               env.GetEnvString("DBSERVER_RPC_URL", "http://localhost:9002"),
-		aggregatorRPCURL:          env.GetEnvString("AGGREGATOR_RPC_URL", "http://localhost:9001"),
-		taskDispatcherRPCUrl:      env.GetEnvString("TASK_DISPATCHER_RPC_URL", "localhost:9003"),
-		conditionSchedulerID:      env.GetEnvInt("CONDITION_SCHEDULER_ID", 5678),
-		maxWorkers:                env.GetEnvInt("CONDITION_SCHEDULER_MAX_WORKERS", 100),
-		alchemyAPIKey:             env.GetEnvString("ALCHEMY_API_KEY", ""),
-		eventMonitorServiceURL:    env.GetEnvString("EVENT_MONITOR_SERVICE_URL", "http://localhost:9009"),
+		devMode:                    env.GetEnvBool("DEV_MODE", false),
+		conditionSchedulerRPCPort:  env.GetEnvString("CONDITION_SCHEDULER_RPC_PORT", "9006"),
+		conditionSchedulerGRPCPort: env.GetEnvString("CONDITION_SCHEDULER_GRPC_PORT", ""),
+		dbServerURL:                env.GetEnvString("DBSERVER_RPC_URL", "http://localhost:9002"),
+		aggregatorRPCURL:           env.GetEnvString("AGGREGATOR_RPC_URL", "http://localhost:9001"),
+		taskDispatcherRPCUrl:       env.GetEnvString("TASK_DISPATCHER_RPC_URL", "localhost:9003"),
+		conditionSchedulerID:       env.GetEnvInt("CONDITION_SCHEDULER_ID", 5678),
+		maxWorkers:                 env.GetEnvInt("CONDITION_SCHEDULER_MAX_WORKERS", 100),
+		alchemyAPIKey:              env.GetEnvString("ALCHEMY_API_KEY", ""),
+		eventMonitorServiceURL:     env.GetEnvString("EVENT_MONITOR_SERVICE_URL", "http://localhost:9009"),
+	}
+	cfg.rpcTLS = mtls.Config{
+		CertFile: env.GetEnvString("RPC_TLS_CERT_FILE", ""),
+		KeyFile:  env.GetEnvString("RPC_TLS_KEY_FILE", ""),
+		CAFile:   env.GetEnvString("RPC_TLS_CA_FILE", ""),
+	}
+	if key := env.GetEnvString("SERVICE_TOKEN_PRIVATE_KEY", ""); key != "" {
+		serviceTokenKey, err := jwt.ParseServiceTokenKey(key)
+		if err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+		cfg.serviceTokenKey = serviceTokenKey
+	}
+	serviceTokenPublicKeys, err := jwt.ParseServiceTokenPublicKeys(env.GetEnvString("SERVICE_TOKEN_PUBLIC_KEYS", ""))
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
 	}
+	cfg.serviceTokenPublicKeys = serviceTokenPublicKeys
 	if err := validateConfig(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -71,6 +106,9 @@ func validateConfig() error {
 	if !env.IsValidPort(cfg.conditionSchedulerRPCPort) {
 		return fmt.Errorf("invalid condition scheduler RPC port: %s", cfg.conditionSchedulerRPCPort)
 	}
+	if cfg.conditionSchedulerGRPCPort != "" && !env.IsValidPort(cfg.conditionSchedulerGRPCPort) {
+		return fmt.Errorf("invalid condition scheduler gRPC port: %s", cfg.conditionSchedulerGRPCPort)
+	}
 	if !env.IsValidURL(cfg.dbServerURL) {
 		return fmt.Errorf("invalid database server URL: %s", cfg.dbServerURL)
 	}
@@ -92,6 +130,11 @@ func GetSchedulerRPCPort() string {
 	return cfg.conditionSchedulerRPCPort
 }
 
+// GetSchedulerGRPCPort returns the scheduler gRPC port, empty when disabled
+func GetSchedulerGRPCPort() string {
+	return cfg.conditionSchedulerGRPCPort
+}
+
 // GetDBServerURL returns the database server URL
 func GetDBServerURL() string {
 	return cfg.dbServerURL
@@ -107,6 +150,23 @@ func GetTaskDispatcherRPCUrl() string {
 	return cfg.taskDispatcherRPCUrl
 }
 
+// GetRPCTLSConfig returns the certificate the task dispatcher is called with, disabled when
+// no certificate file is set
+func GetRPCTLSConfig() mtls.Config {
+	return cfg.rpcTLS
+}
+
+// GetServiceTokenKey returns the key signing the service tokens the DBServer and task
+// dispatcher are called with, nil when unset
+func GetServiceTokenKey() ed25519.PrivateKey {
+	return cfg.serviceTokenKey
+}
+
+// GetServiceTokenPublicKeys returns the keys the service tokens of RPC callers are verified with
+func GetServiceTokenPublicKeys() []ed25519.PublicKey {
+	return cfg.serviceTokenPublicKeys
+}
+
 // GetMaxWorkers returns the maximum number of concurrent workers allowed
 func GetMaxWorkers() int {
 	return cfg.maxWorkers
@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// SchedulerInterface defines the condition scheduler operations exposed over gRPC
+type SchedulerInterface interface {
+	ScheduleJob(jobData *types.ScheduleConditionJobData) error
+	UnscheduleJob(jobID *big.Int) error
+	GetStats() map[string]interface{}
+}
+
+// SchedulerService implements the typed SchedulerService gRPC API for the condition scheduler
+type SchedulerService struct {
+	rpcproto.UnimplementedSchedulerServiceServer
+	logger    logging.Logger
+	scheduler SchedulerInterface
+}
+
+// NewSchedulerService creates the typed gRPC service
+func NewSchedulerService(logger logging.Logger, scheduler SchedulerInterface) *SchedulerService {
+	return &SchedulerService{
+		logger:    logger,
+		scheduler: scheduler,
+	}
+}
+
+// ScheduleJob starts a condition or event worker for the job
+func (s *SchedulerService) ScheduleJob(ctx context.Context, req *rpcproto.ScheduleJobRequest) (*rpcproto.ScheduleJobResponse, error) {
+	jobData, err := req.ToTypes()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+
+	if err := s.scheduler.ScheduleJob(jobData); err != nil {
+		s.logger.Error("Failed to schedule condition job", "job_id", req.GetJobId(), "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to schedule job: %v", err)
+	}
+	return &rpcproto.ScheduleJobResponse{
+		JobId:   req.GetJobId(),
+		Message: "Condition job scheduled successfully",
+	}, nil
+}
+
+// PauseJob stops the worker of the job
+func (s *SchedulerService) PauseJob(ctx context.Context, req *rpcproto.PauseJobRequest) (*rpcproto.PauseJobResponse, error) {
+	jobID, ok := new(big.Int).SetString(req.GetJobId(), 10)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid job ID %q", req.GetJobId())
+	}
+
+	if err := s.scheduler.UnscheduleJob(jobID); err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to pause job: %v", err)
+	}
+	return &rpcproto.PauseJobResponse{
+		JobId:   req.GetJobId(),
+		Message: "Condition job paused successfully",
+	}, nil
+}
+
+// GetStats returns the scheduler's statistics
+func (s *SchedulerService) GetStats(ctx context.Context, req *rpcproto.GetStatsRequest) (*rpcproto.GetStatsResponse, error) {
+	stats, err := rpcproto.NewStats(s.scheduler.GetStats())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode stats: %v", err)
+	}
+	return &rpcproto.GetStatsResponse{
+		Scheduler: "condition",
+		Stats:     stats,
+	}, nil
+}
@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 
+	"google.golang.org/grpc/credentials"
+
 	nodeclient "github.com/trigg3rX/triggerx-backend/pkg/client/nodeclient"
 
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/client/eventmonitor"
@@ -16,25 +18,39 @@ import (
 	"github.com/trigg3rX/triggerx-backend/pkg/client/dbserver"
 	httppkg "github.com/trigg3rX/triggerx-backend/pkg/http"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
 	rpcclient "github.com/trigg3rX/triggerx-backend/pkg/rpc/client"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
+// serviceTokenTTL is the lifetime of the service tokens the task dispatcher is called with
+const serviceTokenTTL = 5 * time.Minute
+
+// dispatcherMethods are the task dispatcher methods the service tokens allow
+var dispatcherMethods = []string{
+	rpcproto.TaskDispatcherService_SubmitTask_FullMethodName,
+	rpcproto.TaskDispatcherService_SubmitBatch_FullMethodName,
+	rpcproto.TaskDispatcherService_StreamSubmit_FullMethodName,
+}
+
 // ConditionBasedScheduler manages individual job workers for condition monitoring and event watching
 type ConditionBasedScheduler struct {
 	ctx                  context.Context
 	cancel               context.CancelFunc
 	logger               logging.Logger
-	conditionWorkers     map[*types.BigInt]*worker.ConditionWorker  // jobID -> condition worker
-	eventWorkers         map[*types.BigInt]*worker.EventWorker      // jobID -> event worker
+	conditionWorkers     map[string]*worker.ConditionWorker         // jobID -> condition worker
+	eventWorkers         map[string]*worker.EventWorker             // jobID -> event worker
 	jobDataStore         map[string]*types.ScheduleConditionJobData // jobID -> job data for trigger notifications
 	workersMutex         sync.RWMutex
 	notificationMutex    sync.Mutex                        // Protect job data during notification processing
 	chainClients         map[string]*nodeclient.NodeClient // chainID -> client
 	HTTPClient           *httppkg.HTTPClient
 	dbClient             *dbserver.DBServerClient
-	taskDispatcherClient *rpcclient.Client    // RPC client for task dispatcher
-	eventMonitorClient   *eventmonitor.Client // Event Monitor Service client
+	taskDispatcherClient *rpcclient.Client                    // RPC client for task dispatcher
+	taskDispatcher       rpcproto.TaskDispatcherServiceClient // Typed task dispatcher API over taskDispatcherClient
+	eventMonitorClient   *eventmonitor.Client                 // Event Monitor Service client
 	metrics              *metrics.Collector
 	maxWorkers           int
 	schedulerID          int
@@ -45,7 +61,11 @@ type ConditionBasedScheduler struct {
 func NewConditionBasedScheduler(managerID string, logger logging.Logger, dbClient *dbserver.DBServerClient) (*ConditionBasedScheduler, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// Initialize RPC client for task dispatcher
+	// Initialize RPC client for task dispatcher, authenticated by a service token when a key is set
+	var tokenCredentials credentials.PerRPCCredentials
+	if key := config.GetServiceTokenKey(); key != nil {
+		tokenCredentials = jwt.NewServiceTokenCredentials(jwt.NewServiceTokenSigner(key), rpcpkg.ServiceConditionScheduler, rpcpkg.ServiceTaskDispatcher, dispatcherMethods, serviceTokenTTL, false)
+	}
 	taskDispatcherClient := rpcclient.NewClient(rpcclient.Config{
 		ServiceName: config.GetTaskDispatcherRPCUrl(),
 		Timeout:     30 * time.Second,
@@ -53,6 +73,8 @@ func NewConditionBasedScheduler(managerID string, logger logging.Logger, dbClien
 		RetryDelay:  time.Second,
 		PoolSize:    10,
 		PoolTimeout: 5 * time.Second,
+		TLS:         config.GetRPCTLSConfig(),
+		Credentials: tokenCredentials,
 	}, logger)
 
 	// Initialize Event Monitor Service client
@@ -69,12 +91,13 @@ func NewConditionBasedScheduler(managerID string, logger logging.Logger, dbClien
 		ctx:                  ctx,
 		cancel:               cancel,
 		logger:               logger,
-		conditionWorkers:     make(map[*types.BigInt]*worker.ConditionWorker),
-		eventWorkers:         make(map[*types.BigInt]*worker.EventWorker),
+		conditionWorkers:     make(map[string]*worker.ConditionWorker),
+		eventWorkers:         make(map[string]*worker.EventWorker),
 		jobDataStore:         make(map[string]*types.ScheduleConditionJobData),
 		chainClients:         make(map[string]*nodeclient.NodeClient),
 		dbClient:             dbClient,
 		taskDispatcherClient: taskDispatcherClient,
+		taskDispatcher:       rpcproto.NewTaskDispatcherServiceClient(taskDispatcherClient),
 		eventMonitorClient:   eventMonitorClient,
 		metrics:              metrics.NewCollector(),
 		maxWorkers:           config.GetMaxWorkers(),
@@ -146,7 +169,7 @@ func (s *ConditionBasedScheduler) Stop() {
 	for jobID, worker := range s.eventWorkers {
 		// If using Event Monitor Service (worker is nil), unregister
 		if worker == nil && s.eventMonitorClient != nil {
-			if err := s.eventMonitorClient.Unregister(jobID.String()); err != nil {
+			if err := s.eventMonitorClient.Unregister(jobID); err != nil {
 				s.logger.Warn("Failed to unregister event job from Event Monitor Service during shutdown",
 					"job_id", jobID,
 					"error", err)
@@ -159,8 +182,8 @@ func (s *ConditionBasedScheduler) Stop() {
 			s.logger.Info("Stopped event worker", "job_id", jobID)
 		}
 	}
-	s.conditionWorkers = make(map[*types.BigInt]*worker.ConditionWorker)
-	s.eventWorkers = make(map[*types.BigInt]*worker.EventWorker)
+	s.conditionWorkers = make(map[string]*worker.ConditionWorker)
+	s.eventWorkers = make(map[string]*worker.EventWorker)
 	s.jobDataStore = make(map[string]*types.ScheduleConditionJobData)
 	s.workersMutex.Unlock()
 
@@ -213,15 +236,14 @@ func (s *ConditionBasedScheduler) cleanupExpiredEventJobs(ctx context.Context) {
 
 			// Find expired event jobs
 			s.workersMutex.RLock()
-			for jobIDBigInt, eventWorker := range s.eventWorkers {
+			for jobIDStr, eventWorker := range s.eventWorkers {
 				// Only check jobs that are using Event Monitor Service (eventWorker is nil)
 				if eventWorker == nil {
-					jobIDStr := jobIDBigInt.String()
 					jobData, exists := s.jobDataStore[jobIDStr]
 					if exists && jobData != nil {
 						// Check if job has expired
 						if jobData.EventWorkerData.ExpirationTime.Before(now) {
-							expiredJobIDs = append(expiredJobIDs, jobIDBigInt.ToBigInt())
+							expiredJobIDs = append(expiredJobIDs, jobData.JobID.ToBigInt())
 						}
 					}
 				}
@@ -11,6 +11,7 @@ import (
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/metrics"
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/condition/scheduler/worker"
 	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
@@ -188,11 +189,11 @@ func (s *ConditionBasedScheduler) submitTaskToTaskManager(request types.Schedule
 		defer rpcCancel()
 
 		// Make RPC call to task dispatcher
-		var response types.TaskManagerAPIResponse
-		err := s.taskDispatcherClient.Call(rpcCtx, "submit-task", &request, &response)
+		resp, err := s.taskDispatcher.SubmitTask(rpcCtx, rpcproto.NewSubmitTaskRequest(&request))
 		if err != nil {
 			return false, fmt.Errorf("RPC call failed: %w", err)
 		}
+		response := resp.ToTypes()
 
 		if !response.Success {
 			return false, fmt.Errorf("task dispatcher processing failed: %s - %s", response.Message, response.Error)
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	// "strconv"
@@ -17,6 +18,9 @@ import (
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
+// ErrJobNotScheduled is returned when unscheduling a job the scheduler has no worker for
+var ErrJobNotScheduled = errors.New("job is not scheduled")
+
 // ScheduleJob creates and starts a new condition worker for monitoring
 func (s *ConditionBasedScheduler) ScheduleJob(jobData *types.ScheduleConditionJobData) error {
 	s.workersMutex.Lock()
@@ -50,7 +54,7 @@ func (s *ConditionBasedScheduler) ScheduleJob(jobData *types.ScheduleConditionJo
 // scheduleConditionJob handles condition-based job scheduling
 func (s *ConditionBasedScheduler) scheduleConditionJob(jobData *types.ScheduleConditionJobData, startTime time.Time) error {
 	// Check if job is already scheduled
-	if _, exists := s.conditionWorkers[jobData.JobID]; exists {
+	if _, exists := s.conditionWorkers[jobData.JobID.String()]; exists {
 		metrics.TrackCriticalError("duplicate_job_schedule")
 		return fmt.Errorf("job %d is already scheduled", jobData.JobID)
 	}
@@ -61,7 +65,7 @@ func (s *ConditionBasedScheduler) scheduleConditionJob(jobData *types.ScheduleCo
 			metrics.TrackCriticalError("websocket_worker_creation_failed")
 			return fmt.Errorf("failed to create websocket worker: %w", err)
 		}
-		s.conditionWorkers[jobData.JobID] = nil // Or: s.websocketWorkers[jobData.JobID] = websocketWorker (if struct field added)
+		s.conditionWorkers[jobData.JobID.String()] = nil // Or: s.websocketWorkers[jobData.JobID] = websocketWorker (if struct field added)
 		s.jobDataStore[jobData.JobID.String()] = jobData
 		go websocketWorker.Start()
 		duration := time.Since(startTime)
@@ -95,7 +99,7 @@ func (s *ConditionBasedScheduler) scheduleConditionJob(jobData *types.ScheduleCo
 	}
 
 	// Store worker and job data separately for Redis integration
-	s.conditionWorkers[jobData.JobID] = conditionWorker
+	s.conditionWorkers[jobData.JobID.String()] = conditionWorker
 	s.jobDataStore[jobData.JobID.String()] = jobData
 
 	// Start worker
@@ -124,7 +128,7 @@ func (s *ConditionBasedScheduler) scheduleConditionJob(jobData *types.ScheduleCo
 // scheduleEventJob handles event-based job scheduling using Event Monitor Service
 func (s *ConditionBasedScheduler) scheduleEventJob(jobData *types.ScheduleConditionJobData, startTime time.Time) error {
 	// Check if job is already scheduled
-	if _, exists := s.eventWorkers[jobData.JobID]; exists {
+	if _, exists := s.eventWorkers[jobData.JobID.String()]; exists {
 		metrics.TrackCriticalError("duplicate_job_schedule")
 		return fmt.Errorf("job %d is already scheduled", jobData.JobID)
 	}
@@ -157,7 +161,7 @@ func (s *ConditionBasedScheduler) scheduleEventJob(jobData *types.ScheduleCondit
 	}
 
 	// Store job data (no local event worker needed)
-	s.eventWorkers[jobData.JobID] = nil // Mark as using Event Monitor Service
+	s.eventWorkers[jobData.JobID.String()] = nil // Mark as using Event Monitor Service
 	s.jobDataStore[jobData.JobID.String()] = jobData
 
 	duration := time.Since(startTime)
@@ -292,7 +296,7 @@ func (s *ConditionBasedScheduler) UnregisterEventJob(jobID *big.Int) error {
 	defer s.workersMutex.Unlock()
 
 	// Check if this is an event job
-	if _, exists := s.eventWorkers[types.NewBigInt(jobID)]; !exists {
+	if _, exists := s.eventWorkers[jobID.String()]; !exists {
 		return fmt.Errorf("job %d is not an event job", jobID)
 	}
 
@@ -305,7 +309,7 @@ func (s *ConditionBasedScheduler) UnregisterEventJob(jobID *big.Int) error {
 	}
 
 	// Remove from event workers map
-	delete(s.eventWorkers, types.NewBigInt(jobID))
+	delete(s.eventWorkers, jobID.String())
 
 	// Clean up job data
 	delete(s.jobDataStore, jobID.String())
@@ -322,11 +326,11 @@ func (s *ConditionBasedScheduler) UnscheduleJob(jobID *big.Int) error {
 	defer s.workersMutex.Unlock()
 
 	// Try condition workers first
-	if conditionWorker, exists := s.conditionWorkers[types.NewBigInt(jobID)]; exists {
+	if conditionWorker, exists := s.conditionWorkers[jobID.String()]; exists {
 		conditionWorker.Stop()
-		delete(s.conditionWorkers, types.NewBigInt(jobID))
+		delete(s.conditionWorkers, jobID.String())
 		delete(s.jobDataStore, jobID.String()) // Clean up job data
-	} else if eventWorker, exists := s.eventWorkers[types.NewBigInt(jobID)]; exists {
+	} else if eventWorker, exists := s.eventWorkers[jobID.String()]; exists {
 		// If event worker exists, unregister from Event Monitor Service
 		if s.eventMonitorClient != nil {
 			if err := s.eventMonitorClient.Unregister(jobID.String()); err != nil {
@@ -342,11 +346,11 @@ func (s *ConditionBasedScheduler) UnscheduleJob(jobID *big.Int) error {
 			eventWorker.Stop()
 		}
 
-		delete(s.eventWorkers, types.NewBigInt(jobID))
+		delete(s.eventWorkers, jobID.String())
 		delete(s.jobDataStore, jobID.String()) // Clean up job data
 	} else {
 		metrics.TrackCriticalError("job_not_found")
-		return fmt.Errorf("job %d is not scheduled", jobID)
+		return fmt.Errorf("job %d: %w", jobID, ErrJobNotScheduled)
 	}
 
 	// Update active workers count
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
 // GetStats returns current scheduler statistics
@@ -143,7 +142,7 @@ func (s *ConditionBasedScheduler) GetConditionWorkerStats(jobID *big.Int) (map[s
 	s.workersMutex.RLock()
 	defer s.workersMutex.RUnlock()
 
-	worker, exists := s.conditionWorkers[types.NewBigInt(jobID)]
+	worker, exists := s.conditionWorkers[jobID.String()]
 	if !exists {
 		return nil, fmt.Errorf("condition worker for job %d not found", jobID)
 	}
@@ -169,7 +168,7 @@ func (s *ConditionBasedScheduler) GetEventWorkerStats(jobID *big.Int) (map[strin
 	s.workersMutex.RLock()
 	defer s.workersMutex.RUnlock()
 
-	worker, exists := s.eventWorkers[types.NewBigInt(jobID)]
+	worker, exists := s.eventWorkers[jobID.String()]
 	if !exists {
 		return nil, fmt.Errorf("event worker for job %d not found", jobID)
 	}
@@ -196,7 +195,7 @@ func (s *ConditionBasedScheduler) GetAllWorkerStats() map[string]interface{} {
 
 	// Get condition worker stats
 	for jobID, worker := range s.conditionWorkers {
-		conditionStats[fmt.Sprintf("job_%d", jobID)] = map[string]interface{}{
+		conditionStats[fmt.Sprintf("job_%s", jobID)] = map[string]interface{}{
 			"job_id":              worker.ConditionWorkerData.JobID,
 			"is_running":          worker.IsRunning(),
 			"condition_type":      worker.ConditionWorkerData.ConditionType,
@@ -208,7 +207,7 @@ func (s *ConditionBasedScheduler) GetAllWorkerStats() map[string]interface{} {
 
 	// Get event worker stats
 	for jobID, worker := range s.eventWorkers {
-		eventStats[fmt.Sprintf("job_%d", jobID)] = map[string]interface{}{
+		eventStats[fmt.Sprintf("job_%s", jobID)] = map[string]interface{}{
 			"job_id":               worker.EventWorkerData.JobID,
 			"is_running":           worker.IsRunning(),
 			"trigger_chain_id":     worker.EventWorkerData.TriggerChainID,
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/time/scheduler"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
 type SchedulerHandler struct {
@@ -44,3 +45,56 @@ func (h *SchedulerHandler) GetStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// PauseJob stops the scheduler from submitting tasks of a job the DBServer paused
+func (h *SchedulerHandler) PauseJob(c *gin.Context) {
+	traceID := getTraceID(c)
+	h.logger.Info("[PauseJob] trace_id=" + traceID + " - Pausing job")
+
+	jobID, ok := h.bindJobID(c)
+	if !ok {
+		return
+	}
+	h.scheduler.PauseJob(jobID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Time job paused",
+		"job_id":    jobID,
+		"timestamp": time.Now().UTC(),
+	})
+}
+
+// ResumeJob lets the scheduler submit tasks of a job the DBServer resumed
+func (h *SchedulerHandler) ResumeJob(c *gin.Context) {
+	traceID := getTraceID(c)
+	h.logger.Info("[ResumeJob] trace_id=" + traceID + " - Resuming job")
+
+	jobID, ok := h.bindJobID(c)
+	if !ok {
+		return
+	}
+	h.scheduler.ResumeJob(jobID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Time job resumed",
+		"job_id":    jobID,
+		"timestamp": time.Now().UTC(),
+	})
+}
+
+// bindJobID reads the job of a lifecycle notice, answering 400 when it has none
+func (h *SchedulerHandler) bindJobID(c *gin.Context) (string, bool) {
+	var notice types.JobLifecycleNotice
+	if err := c.ShouldBindJSON(&notice); err != nil || notice.JobID == nil || notice.JobID.Int == nil {
+		h.logger.Error("Invalid job lifecycle notice", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":    "error",
+			"message":   "Invalid job ID",
+			"timestamp": time.Now().UTC(),
+		})
+		return "", false
+	}
+	return notice.JobID.String(), true
+}
@@ -105,6 +105,8 @@ func (s *Server) setupRoutes(deps Dependencies) {
 	api := s.router.Group("/api/v1")
 	{
 		api.GET("/scheduler/stats", schedulerHandler.GetStats)
+		api.POST("/job/pause", schedulerHandler.PauseJob)
+		api.POST("/job/resume", schedulerHandler.ResumeJob)
 	}
 }
 
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"time"
 
@@ -8,6 +9,8 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/env"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
 )
 
 type Config struct {
@@ -15,6 +18,8 @@ type Config struct {
 
 	// Scheduler RPC Port
 	timeSchedulerRPCPort string
+	// Scheduler gRPC Port, the typed gRPC API is disabled when empty
+	timeSchedulerGRPCPort string
 
 	// Database RPC URL
 	dbServerURL string
@@ -22,6 +27,12 @@ type Config struct {
 	aggregatorRPCUrl string
 	// Task Dispatcher RPC URL (renamed from Redis API URL)
 	taskDispatcherRPCUrl string
+	// Mutual TLS certificate used to call the task dispatcher
+	rpcTLS mtls.Config
+	// Key signing the service tokens the DBServer and task dispatcher are called with, none when empty
+	serviceTokenKey ed25519.PrivateKey
+	// Keys the service tokens of RPC callers are verified with
+	serviceTokenPublicKeys []ed25519.PublicKey
 
 	// Scheduler ID
 	timeSchedulerID int
@@ -41,19 +52,43 @@ func Init() error {
 	if err := godotenv.Load(); err != nil {
 		return fmt.Errorf("error loading .env file: %w", err)
 	}
+	return Load()
+}
+
+// Load reads the configuration from the environment only, for processes that set it
+// themselves instead of shipping a .env file.
+func Load() error {
 	cfg = Config{
-		devMode:              env.GetEnvBool("DEV_MODE", false),
-		timeSchedulerRPCPort: env.GetEnvString("TIME_SCHEDULER_RPC_PORT", "9005"),
-		taskDispatcherRPCUrl: env.GetEnvString("TASK_DISPATCHER_RPC_URL", "localhost:9003"),
-		dbServerURL:          env.GetEnvString("DBSERVER_RPC_URL", "http://localhost:9002"),
-		aggregatorRPCUrl:     env.GetEnvString("AGGREGATOR_RPC_URL", "http://localhost:9001"),
-		pollingInterval:      env.GetEnvDuration("TIME_SCHEDULER_POLLING_INTERVAL", 30*time.Second),
-		pollingLookAhead:     env.GetEnvDuration("TIME_SCHEDULER_POLLING_LOOKAHEAD", 40*time.Minute),
-		taskBatchSize:        env.GetEnvInt("TIME_SCHEDULER_TASK_BATCH_SIZE", 15),
-		performerLockTTL:     env.GetEnvDuration("TIME_SCHEDULER_PERFORMER_LOCK_TTL", 31*time.Second),
-		taskCacheTTL:         env.GetEnvDuration("TIME_SCHEDULER_TASK_CACHE_TTL", 1*time.Minute),
-		duplicateTaskWindow:  env.GetEnvDuration("TIME_SCHEDULER_DUPLICATE_TASK_WINDOW", 1*time.Minute),
+		devMode:               env.GetEnvBool("DEV_MODE", false),
+		timeSchedulerRPCPort:  env.GetEnvString("TIME_SCHEDULER_RPC_PORT", "9005"),
+		timeSchedulerGRPCPort: env.GetEnvString("TIME_SCHEDULER_GRPC_PORT", ""),
+		taskDispatcherRPCUrl:  env.GetEnvString("TASK_DISPATCHER_RPC_URL", "localhost:9003"),
+		dbServerURL:           env.GetEnvString("DBSERVER_RPC_URL", "http://localhost:9002"),
+		aggregatorRPCUrl:      env.GetEnvString("AGGREGATOR_RPC_URL", "http://localhost:9001"),
+		pollingInterval:       env.GetEnvDuration("TIME_SCHEDULER_POLLING_INTERVAL", 30*time.Second),
+		pollingLookAhead:      env.GetEnvDuration("TIME_SCHEDULER_POLLING_LOOKAHEAD", 40*time.Minute),
+		taskBatchSize:         env.GetEnvInt("TIME_SCHEDULER_TASK_BATCH_SIZE", 15),
+		performerLockTTL:      env.GetEnvDuration("TIME_SCHEDULER_PERFORMER_LOCK_TTL", 31*time.Second),
+		taskCacheTTL:          env.GetEnvDuration("TIME_SCHEDULER_TASK_CACHE_TTL", 1*time.Minute),
+		duplicateTaskWindow:   env.GetEnvDuration("TIME_SCHEDULER_DUPLICATE_TASK_WINDOW", 1*time.Minute),
+	}
+	cfg.rpcTLS = mtls.Config{
+		CertFile: env.GetEnvString("RPC_TLS_CERT_FILE", ""),
+		KeyFile:  env.GetEnvString("RPC_TLS_KEY_FILE", ""),
+		CAFile:   env.GetEnvString("RPC_TLS_CA_FILE", ""),
+	}
+	if key := env.GetEnvString("SERVICE_TOKEN_PRIVATE_KEY", ""); key != "" {
+		serviceTokenKey, err := jwt.ParseServiceTokenKey(key)
+		if err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+		cfg.serviceTokenKey = serviceTokenKey
 	}
+	serviceTokenPublicKeys, err := jwt.ParseServiceTokenPublicKeys(env.GetEnvString("SERVICE_TOKEN_PUBLIC_KEYS", ""))
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	cfg.serviceTokenPublicKeys = serviceTokenPublicKeys
 	if err := validateConfig(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -67,6 +102,9 @@ func validateConfig() error {
 	if !env.IsValidPort(cfg.timeSchedulerRPCPort) {
 		return fmt.Errorf("invalid time scheduler RPC port: %s", cfg.timeSchedulerRPCPort)
 	}
+	if cfg.timeSchedulerGRPCPort != "" && !env.IsValidPort(cfg.timeSchedulerGRPCPort) {
+		return fmt.Errorf("invalid time scheduler gRPC port: %s", cfg.timeSchedulerGRPCPort)
+	}
 	if !env.IsValidURL(cfg.dbServerURL) {
 		return fmt.Errorf("invalid database server URL: %s", cfg.dbServerURL)
 	}
@@ -86,6 +124,10 @@ func GetSchedulerRPCPort() string {
 	return cfg.timeSchedulerRPCPort
 }
 
+func GetSchedulerGRPCPort() string {
+	return cfg.timeSchedulerGRPCPort
+}
+
 func GetDBServerURL() string {
 	return cfg.dbServerURL
 }
@@ -98,6 +140,23 @@ func GetTaskDispatcherRPCUrl() string {
 	return cfg.taskDispatcherRPCUrl
 }
 
+// GetRPCTLSConfig returns the certificate the task dispatcher is called with, disabled when
+// no certificate file is set
+func GetRPCTLSConfig() mtls.Config {
+	return cfg.rpcTLS
+}
+
+// GetServiceTokenKey returns the key signing the service tokens the DBServer and task
+// dispatcher are called with, nil when unset
+func GetServiceTokenKey() ed25519.PrivateKey {
+	return cfg.serviceTokenKey
+}
+
+// GetServiceTokenPublicKeys returns the keys the service tokens of RPC callers are verified with
+func GetServiceTokenPublicKeys() []ed25519.PublicKey {
+	return cfg.serviceTokenPublicKeys
+}
+
 func GetSchedulerID() int {
 	return cfg.timeSchedulerID
 }
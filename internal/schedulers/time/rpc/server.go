@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+	rpcserver "github.com/trigg3rX/triggerx-backend/pkg/rpc/server"
+)
+
+// MethodPolicy lists the services allowed to call each method of the scheduler: the DBServer
+// manages jobs, any service may read the stats
+var MethodPolicy = map[string][]string{
+	rpcproto.SchedulerService_ScheduleJob_FullMethodName: {rpcpkg.ServiceDBServer},
+	rpcproto.SchedulerService_PauseJob_FullMethodName:    {rpcpkg.ServiceDBServer},
+	rpcproto.SchedulerService_GetStats_FullMethodName:    {"*"},
+}
+
+// StartRPCServer creates and starts the scheduler's gRPC server with the typed SchedulerService,
+// authenticating callers with auth unless it is nil
+func StartRPCServer(ctx context.Context, logger logging.Logger, scheduler SchedulerInterface, portStr string, tlsConfig mtls.Config, auth *rpcserver.AuthConfig) (*rpcserver.Server, error) {
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q: %w", portStr, err)
+	}
+
+	serverConfig := rpcserver.Config{
+		Name:    "TimeScheduler",
+		Version: "1.0.0",
+		Address: "0.0.0.0",
+		Port:    port,
+		TLS:     tlsConfig,
+		Auth:    auth,
+	}
+
+	srv := rpcserver.NewServer(serverConfig, logger)
+
+	srv.AddInterceptor(rpcserver.LoggingInterceptor(logger))
+	srv.AddStreamInterceptor(rpcserver.LoggingStreamInterceptor(logger))
+	rpcproto.RegisterSchedulerServiceServer(srv, NewSchedulerService(scheduler))
+
+	if err := srv.Start(ctx); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
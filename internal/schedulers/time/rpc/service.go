@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+)
+
+// SchedulerInterface defines the time scheduler operations exposed over gRPC
+type SchedulerInterface interface {
+	PauseJob(jobID string)
+	ResumeJob(jobID string)
+	GetStats() map[string]interface{}
+}
+
+// SchedulerService implements the typed SchedulerService gRPC API for the time scheduler.
+// Time jobs are polled from the database, so ScheduleJob and PauseJob only lift or set the
+// pause mark of a job the DBServer resumed or paused, like the HTTP lifecycle notices.
+type SchedulerService struct {
+	rpcproto.UnimplementedSchedulerServiceServer
+	scheduler SchedulerInterface
+}
+
+// NewSchedulerService creates the typed gRPC service
+func NewSchedulerService(scheduler SchedulerInterface) *SchedulerService {
+	return &SchedulerService{scheduler: scheduler}
+}
+
+// ScheduleJob lets the scheduler submit tasks of the job again
+func (s *SchedulerService) ScheduleJob(ctx context.Context, req *rpcproto.ScheduleJobRequest) (*rpcproto.ScheduleJobResponse, error) {
+	jobID, err := parseJobID(req.GetJobId())
+	if err != nil {
+		return nil, err
+	}
+
+	s.scheduler.ResumeJob(jobID)
+	return &rpcproto.ScheduleJobResponse{
+		JobId:   jobID,
+		Message: "Time job resumed",
+	}, nil
+}
+
+// PauseJob stops the scheduler from submitting tasks of the job
+func (s *SchedulerService) PauseJob(ctx context.Context, req *rpcproto.PauseJobRequest) (*rpcproto.PauseJobResponse, error) {
+	jobID, err := parseJobID(req.GetJobId())
+	if err != nil {
+		return nil, err
+	}
+
+	s.scheduler.PauseJob(jobID)
+	return &rpcproto.PauseJobResponse{
+		JobId:   jobID,
+		Message: "Time job paused",
+	}, nil
+}
+
+// GetStats returns the scheduler's statistics
+func (s *SchedulerService) GetStats(ctx context.Context, req *rpcproto.GetStatsRequest) (*rpcproto.GetStatsResponse, error) {
+	stats, err := rpcproto.NewStats(s.scheduler.GetStats())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode stats: %v", err)
+	}
+	return &rpcproto.GetStatsResponse{
+		Scheduler: "time",
+		Stats:     stats,
+	}, nil
+}
+
+// parseJobID normalizes a decimal job ID the way the scheduler keys paused jobs
+func parseJobID(value string) (string, error) {
+	jobID, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return "", status.Errorf(codes.InvalidArgument, "invalid job ID %q", value)
+	}
+	return jobID.String(), nil
+}
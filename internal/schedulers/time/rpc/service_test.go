@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+)
+
+// fakeScheduler records the jobs paused and resumed
+type fakeScheduler struct {
+	paused map[string]bool
+}
+
+func (f *fakeScheduler) PauseJob(jobID string)  { f.paused[jobID] = true }
+func (f *fakeScheduler) ResumeJob(jobID string) { delete(f.paused, jobID) }
+func (f *fakeScheduler) GetStats() map[string]interface{} {
+	return map[string]interface{}{"paused_jobs": len(f.paused)}
+}
+
+func TestSchedulerService_PauseAndScheduleJob(t *testing.T) {
+	ctx := context.Background()
+	scheduler := &fakeScheduler{paused: make(map[string]bool)}
+	service := NewSchedulerService(scheduler)
+
+	paused, err := service.PauseJob(ctx, &rpcproto.PauseJobRequest{JobId: "0042"})
+	require.NoError(t, err)
+	assert.Equal(t, "42", paused.JobId)
+	assert.True(t, scheduler.paused["42"])
+
+	scheduled, err := service.ScheduleJob(ctx, &rpcproto.ScheduleJobRequest{JobId: "42"})
+	require.NoError(t, err)
+	assert.Equal(t, "42", scheduled.JobId)
+	assert.Empty(t, scheduler.paused)
+
+	_, err = service.PauseJob(ctx, &rpcproto.PauseJobRequest{JobId: "job-42"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
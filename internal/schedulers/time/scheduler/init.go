@@ -2,16 +2,32 @@ package scheduler
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	"google.golang.org/grpc/credentials"
+
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/time/config"
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/time/metrics"
 	"github.com/trigg3rX/triggerx-backend/pkg/client/dbserver"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
 	"github.com/trigg3rX/triggerx-backend/pkg/rpc/client"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
+// serviceTokenTTL is the lifetime of the service tokens the task dispatcher is called with
+const serviceTokenTTL = 5 * time.Minute
+
+// dispatcherMethods are the task dispatcher methods the service tokens allow
+var dispatcherMethods = []string{
+	rpcproto.TaskDispatcherService_SubmitTask_FullMethodName,
+	rpcproto.TaskDispatcherService_SubmitBatch_FullMethodName,
+	rpcproto.TaskDispatcherService_StreamSubmit_FullMethodName,
+}
+
 type TimeBasedScheduler struct {
 	ctx                  context.Context
 	cancel               context.CancelFunc
@@ -19,6 +35,8 @@ type TimeBasedScheduler struct {
 	activeTasks          map[int64]*types.ScheduleTimeTaskData
 	dbClient             *dbserver.DBServerClient
 	taskDispatcherClient *client.Client // RPC client for task dispatcher
+	taskDispatcher       rpcproto.TaskDispatcherServiceClient
+	batchStream          *batchStream // Streams batches to the task dispatcher
 	metrics              *metrics.Collector
 	schedulerID          int
 	pollingInterval      time.Duration
@@ -27,13 +45,21 @@ type TimeBasedScheduler struct {
 	performerLockTTL     time.Duration
 	taskCacheTTL         time.Duration
 	duplicateTaskWindow  time.Duration
+
+	// Jobs the DBServer paused, by job ID, until the time their mark expires
+	pausedJobs   map[string]time.Time
+	pausedJobsMu sync.Mutex
 }
 
 // NewTimeBasedScheduler creates a new instance of TimeBasedScheduler
 func NewTimeBasedScheduler(managerID string, logger logging.Logger, dbClient *dbserver.DBServerClient) (*TimeBasedScheduler, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// Initialize RPC client for task dispatcher
+	// Initialize RPC client for task dispatcher, authenticated by a service token when a key is set
+	var tokenCredentials credentials.PerRPCCredentials
+	if key := config.GetServiceTokenKey(); key != nil {
+		tokenCredentials = jwt.NewServiceTokenCredentials(jwt.NewServiceTokenSigner(key), rpcpkg.ServiceTimeScheduler, rpcpkg.ServiceTaskDispatcher, dispatcherMethods, serviceTokenTTL, false)
+	}
 	taskDispatcherClient := client.NewClient(client.Config{
 		ServiceName: config.GetTaskDispatcherRPCUrl(),
 		Timeout:     30 * time.Second,
@@ -41,6 +67,8 @@ func NewTimeBasedScheduler(managerID string, logger logging.Logger, dbClient *db
 		RetryDelay:  time.Second,
 		PoolSize:    10,
 		PoolTimeout: 5 * time.Second,
+		TLS:         config.GetRPCTLSConfig(),
+		Credentials: tokenCredentials,
 	}, logger)
 
 	scheduler := &TimeBasedScheduler{
@@ -50,6 +78,7 @@ func NewTimeBasedScheduler(managerID string, logger logging.Logger, dbClient *db
 		activeTasks:          make(map[int64]*types.ScheduleTimeTaskData),
 		dbClient:             dbClient,
 		taskDispatcherClient: taskDispatcherClient,
+		taskDispatcher:       rpcproto.NewTaskDispatcherServiceClient(taskDispatcherClient),
 		metrics:              metrics.NewCollector(),
 		schedulerID:          config.GetSchedulerID(),
 		pollingInterval:      config.GetPollingInterval(),
@@ -58,8 +87,11 @@ func NewTimeBasedScheduler(managerID string, logger logging.Logger, dbClient *db
 		performerLockTTL:     config.GetPerformerLockTTL(),
 		taskCacheTTL:         config.GetTaskCacheTTL(),
 		duplicateTaskWindow:  config.GetDuplicateTaskWindow(),
+		pausedJobs:           make(map[string]time.Time),
 	}
 
+	scheduler.batchStream = newBatchStream(ctx, scheduler.taskDispatcher, logger)
+
 	// Start metrics collection
 	scheduler.metrics.Start()
 
@@ -0,0 +1,38 @@
+package scheduler
+
+import "time"
+
+// PauseJob stops the scheduler from submitting tasks of a job the DBServer paused. Polls
+// after the pause no longer return the job, so the mark only has to outlast the polls that
+// were running when the job was paused: it expires after a polling interval and look-ahead.
+func (s *TimeBasedScheduler) PauseJob(jobID string) {
+	s.pausedJobsMu.Lock()
+	defer s.pausedJobsMu.Unlock()
+
+	s.pausedJobs[jobID] = time.Now().Add(s.pollingInterval + s.pollingLookAhead)
+	s.logger.Info("Paused time job", "job_id", jobID)
+}
+
+// ResumeJob lets the scheduler submit tasks of a job the DBServer resumed
+func (s *TimeBasedScheduler) ResumeJob(jobID string) {
+	s.pausedJobsMu.Lock()
+	defer s.pausedJobsMu.Unlock()
+
+	delete(s.pausedJobs, jobID)
+	s.logger.Info("Resumed time job", "job_id", jobID)
+}
+
+// isJobPaused reports whether a job is marked paused, dropping expired marks
+func (s *TimeBasedScheduler) isJobPaused(jobID string) bool {
+	s.pausedJobsMu.Lock()
+	defer s.pausedJobsMu.Unlock()
+
+	now := time.Now()
+	for id, expiresAt := range s.pausedJobs {
+		if now.After(expiresAt) {
+			delete(s.pausedJobs, id)
+		}
+	}
+	_, paused := s.pausedJobs[jobID]
+	return paused
+}
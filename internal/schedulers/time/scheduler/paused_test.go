@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+func TestPausedJobs(t *testing.T) {
+	s := &TimeBasedScheduler{
+		logger:           logging.NewNoOpLogger(),
+		pausedJobs:       make(map[string]time.Time),
+		pollingInterval:  time.Minute,
+		pollingLookAhead: time.Minute,
+	}
+
+	assert.False(t, s.isJobPaused("1"))
+
+	s.PauseJob("1")
+	assert.True(t, s.isJobPaused("1"))
+	assert.False(t, s.isJobPaused("2"))
+
+	s.ResumeJob("1")
+	assert.False(t, s.isJobPaused("1"))
+
+	// The mark expires once every poll running at the pause is done
+	s.pollingInterval, s.pollingLookAhead = 0, 0
+	s.PauseJob("1")
+	time.Sleep(time.Millisecond)
+	assert.False(t, s.isJobPaused("1"))
+	assert.Empty(t, s.pausedJobs)
+}
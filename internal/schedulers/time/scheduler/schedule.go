@@ -2,12 +2,14 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/trigg3rX/triggerx-backend/internal/schedulers/time/metrics"
 	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
@@ -85,6 +87,12 @@ func (s *TimeBasedScheduler) processBatch(tasks []types.ScheduleTimeTaskData) {
 			continue
 		}
 
+		// A poll that started before the job was paused can still return it
+		if s.isJobPaused(task.TaskTargetData.JobID.String()) {
+			s.logger.Infof("Task ID %d belongs to paused job %s, skipping execution", task.TaskID, task.TaskTargetData.JobID)
+			continue
+		}
+
 		// Track task by schedule type
 		metrics.TrackTaskByScheduleType(task.ScheduleType)
 
@@ -101,6 +109,8 @@ func (s *TimeBasedScheduler) processBatch(tasks []types.ScheduleTimeTaskData) {
 			Arguments:                 task.TaskTargetData.Arguments,
 			DynamicArgumentsScriptUrl: task.TaskTargetData.DynamicArgumentsScriptUrl,
 			IsImua:                    task.IsImua,
+			ScriptStorage:             task.TaskTargetData.ScriptStorage,
+			ScriptLanguage:            task.TaskTargetData.ScriptLanguage,
 		}
 		triggerData := types.TaskTriggerData{
 			TaskID:                  task.TaskID,
@@ -190,12 +200,16 @@ func (s *TimeBasedScheduler) submitBatchToTaskDispatcher(request types.Scheduler
 		rpcCtx, rpcCancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer rpcCancel()
 
-		// Make RPC call to task dispatcher
-		var response types.TaskManagerAPIResponse
-		err := s.taskDispatcherClient.Call(rpcCtx, "submit-task", &request, &response)
+		// Stream the batch to task dispatcher, or make a unary call if it can't stream
+		taskRequest := rpcproto.NewSubmitTaskRequest(&request)
+		resp, err := s.batchStream.Submit(rpcCtx, taskRequest)
+		if errors.Is(err, errStreamUnsupported) {
+			resp, err = s.taskDispatcher.SubmitTask(rpcCtx, taskRequest)
+		}
 		if err != nil {
 			return false, fmt.Errorf("RPC call failed: %w", err)
 		}
+		response := resp.ToTypes()
 
 		if !response.Success {
 			return false, fmt.Errorf("task dispatcher processing failed: %s - %s", response.Message, response.Error)
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+)
+
+// errStreamUnsupported is returned once the task dispatcher turned out not to serve
+// StreamSubmit, batches are then submitted with unary calls
+var errStreamUnsupported = errors.New("task dispatcher does not support streamed submits")
+
+type submitStreamClient = grpc.BidiStreamingClient[rpcproto.StreamSubmitRequest, rpcproto.StreamSubmitResponse]
+
+// submitResult is the answer to one streamed batch
+type submitResult struct {
+	response *rpcproto.SubmitTaskResponse
+	err      error
+}
+
+// batchStream submits batches to the task dispatcher over one StreamSubmit stream, opened on
+// first use and reopened by the next submit after it breaks. Batches in flight when the
+// stream breaks fail, the caller's retry submits them again and the dispatcher skips the
+// tasks of them it already dispatched.
+type batchStream struct {
+	ctx    context.Context
+	client rpcproto.TaskDispatcherServiceClient
+	logger logging.Logger
+
+	mu          sync.Mutex
+	stream      submitStreamClient
+	cancel      context.CancelFunc
+	sequence    uint64
+	pending     map[uint64]chan submitResult
+	unsupported bool
+
+	// sendMu serializes sends, which may block on flow control, apart from mu so responses
+	// keep being received meanwhile
+	sendMu sync.Mutex
+}
+
+func newBatchStream(ctx context.Context, client rpcproto.TaskDispatcherServiceClient, logger logging.Logger) *batchStream {
+	return &batchStream{
+		ctx:     ctx,
+		client:  client,
+		logger:  logger,
+		pending: make(map[uint64]chan submitResult),
+	}
+}
+
+// Submit sends a batch on the stream and waits for its response
+func (b *batchStream) Submit(ctx context.Context, req *rpcproto.SubmitTaskRequest) (*rpcproto.SubmitTaskResponse, error) {
+	stream, sequence, result, err := b.register()
+	if err != nil {
+		return nil, err
+	}
+
+	b.sendMu.Lock()
+	err = stream.Send(&rpcproto.StreamSubmitRequest{Sequence: sequence, Request: req})
+	b.sendMu.Unlock()
+	if err != nil {
+		b.reset(stream, err)
+	}
+
+	select {
+	case r := <-result:
+		return r.response, r.err
+	case <-ctx.Done():
+		b.mu.Lock()
+		delete(b.pending, sequence)
+		b.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// register opens the stream if needed and reserves a sequence for a batch
+func (b *batchStream) register() (submitStreamClient, uint64, chan submitResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.unsupported {
+		return nil, 0, nil, errStreamUnsupported
+	}
+	if b.stream == nil {
+		streamCtx, cancel := context.WithCancel(b.ctx)
+		stream, err := b.client.StreamSubmit(streamCtx)
+		if err != nil {
+			cancel()
+			return nil, 0, nil, fmt.Errorf("failed to open submit stream: %w", err)
+		}
+		b.stream = stream
+		b.cancel = cancel
+		go b.receive(stream)
+	}
+
+	b.sequence++
+	result := make(chan submitResult, 1)
+	b.pending[b.sequence] = result
+	return b.stream, b.sequence, result, nil
+}
+
+// receive hands the responses of a stream to the waiting submits until it breaks
+func (b *batchStream) receive(stream submitStreamClient) {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			b.reset(stream, err)
+			return
+		}
+
+		b.mu.Lock()
+		result, ok := b.pending[resp.GetSequence()]
+		delete(b.pending, resp.GetSequence())
+		b.mu.Unlock()
+		if ok {
+			result <- submitResult{response: resp.GetResponse()}
+		}
+	}
+}
+
+// reset drops a broken stream, failing the batches waiting on it
+func (b *batchStream) reset(stream submitStreamClient, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stream != stream {
+		return
+	}
+	b.cancel()
+	b.stream = nil
+
+	if status.Code(err) == codes.Unimplemented {
+		b.logger.Warn("Task dispatcher does not serve StreamSubmit, falling back to unary submits")
+		b.unsupported = true
+		err = errStreamUnsupported
+	} else {
+		err = fmt.Errorf("submit stream broke: %w", err)
+	}
+	for sequence, result := range b.pending {
+		result <- submitResult{err: err}
+		delete(b.pending, sequence)
+	}
+}
@@ -1,13 +1,17 @@
 package config
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	redisClient "github.com/trigg3rX/triggerx-backend/pkg/client/redis"
+	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
 	"github.com/trigg3rX/triggerx-backend/pkg/env"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
 )
 
 type Config struct {
@@ -16,6 +20,15 @@ type Config struct {
 	// Task Dispatcher RPC port
 	taskDispatcherRPCPort int
 
+	// Mutual TLS certificate the RPC server is served with, callers must present one
+	// signed by the same CA
+	rpcTLS mtls.Config
+	// Keys the service tokens of RPC callers are verified with
+	serviceTokenPublicKeys []ed25519.PublicKey
+	// Requests per second and burst every RPC caller is limited to, 0 disables the limit
+	rpcRateLimit      int
+	rpcRateLimitBurst int
+
 	// Health RPC URL
 	healthRPCUrl string
 	// Aggregator RPC URL
@@ -26,6 +39,9 @@ type Config struct {
 	signingKey     string
 	signingAddress string
 
+	// Format task data is signed in, legacy JSON until every keeper verifies EIP-712
+	signatureFormat cryptography.SignatureFormat
+
 	// Redis (Upstash) connection settings
 	upstashURL   string
 	upstashToken string
@@ -60,6 +76,12 @@ func Init() error {
 	if err := godotenv.Load(); err != nil {
 		return fmt.Errorf("error loading .env file: %w", err)
 	}
+	return Load()
+}
+
+// Load reads the configuration from the environment only, for processes that set it
+// themselves instead of shipping a .env file.
+func Load() error {
 	cfg = Config{
 		devMode:               env.GetEnvBool("DEV_MODE", false),
 		taskDispatcherRPCPort: env.GetEnvInt("TASK_DISPATCHER_RPC_PORT", 9003),
@@ -83,7 +105,24 @@ func Init() error {
 		initializationTimeout: env.GetEnvDuration("REDIS_INITIALIZATION_TIMEOUT", 10*time.Second),
 		maxRetryBackoff:       env.GetEnvDuration("REDIS_MAX_RETRY_BACKOFF", 5*time.Minute),
 		ottempoEndpoint:       env.GetEnvString("TEMPO_OTLP_ENDPOINT", "localhost:4318"),
+		rpcRateLimit:          env.GetEnvInt("RPC_RATE_LIMIT_RPS", 0),
+		rpcRateLimitBurst:     env.GetEnvInt("RPC_RATE_LIMIT_BURST", 0),
 	}
+	cfg.rpcTLS = mtls.Config{
+		CertFile: env.GetEnvString("RPC_TLS_CERT_FILE", ""),
+		KeyFile:  env.GetEnvString("RPC_TLS_KEY_FILE", ""),
+		CAFile:   env.GetEnvString("RPC_TLS_CA_FILE", ""),
+	}
+	signatureFormat, err := cryptography.ParseSignatureFormat(env.GetEnvString("SIGNATURE_FORMAT", string(cryptography.SignatureFormatJSON)))
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	cfg.signatureFormat = signatureFormat
+	serviceTokenPublicKeys, err := jwt.ParseServiceTokenPublicKeys(env.GetEnvString("SERVICE_TOKEN_PUBLIC_KEYS", ""))
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	cfg.serviceTokenPublicKeys = serviceTokenPublicKeys
 
 	if !cfg.devMode {
 		gin.SetMode(gin.ReleaseMode)
@@ -103,6 +142,22 @@ func GetTaskDispatcherRPCPort() int {
 	return cfg.taskDispatcherRPCPort
 }
 
+// GetRPCTLSConfig returns the certificate the RPC server is served with, disabled when no
+// certificate file is set
+func GetRPCTLSConfig() mtls.Config {
+	return cfg.rpcTLS
+}
+
+// GetServiceTokenPublicKeys returns the keys the service tokens of RPC callers are verified with
+func GetServiceTokenPublicKeys() []ed25519.PublicKey {
+	return cfg.serviceTokenPublicKeys
+}
+
+// GetRPCRateLimit returns the requests per second and burst every RPC caller is limited to
+func GetRPCRateLimit() (int, int) {
+	return cfg.rpcRateLimit, cfg.rpcRateLimitBurst
+}
+
 func GetAggregatorRPCUrl() string {
 	return cfg.aggregatorRPCUrl
 }
@@ -119,6 +174,10 @@ func GetTaskDispatcherSigningAddress() string {
 	return cfg.signingAddress
 }
 
+func GetSignatureFormat() cryptography.SignatureFormat {
+	return cfg.signatureFormat
+}
+
 func GetUpstashURL() string {
 	return cfg.upstashURL
 }
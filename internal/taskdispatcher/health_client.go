@@ -1,19 +1,30 @@
 package taskdispatcher
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
+// PerformerRefreshTTL is how long performer uptime scores are reused before asking the health service again
+const PerformerRefreshTTL = time.Minute
+
 // HealthClient handles communication with the health service
 type HealthClient struct {
 	client  *http.Client
 	logger  logging.Logger
 	baseURL string
+
+	// Active performers with their uptime, refreshed every PerformerRefreshTTL
+	mu          sync.Mutex
+	performers  []types.PerformerHistory
+	lastRefresh time.Time
 }
 
 // PerformerResponse represents the response from health service
@@ -46,34 +57,23 @@ func (hc *HealthClient) GetPerformerData(isImua bool, isMainnet bool) (types.Per
 		}, nil
 	}
 
-	// Refresh performers if needed
-	// if time.Since(pm.lastRefresh) > PerformerRefreshTTL {
-	// 	pm.logger.Debug("Refreshing performers from health service")
-	// 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	// 	defer cancel()
-	// 	if err := pm.refreshPerformers(ctx); err != nil {
-	// 		pm.logger.Error("Failed to refresh performers", "error", err)
-	// 		// Fall back to cached performers
-	// 	}
-	// }
-
-	var availablePerformers []types.PerformerData
-	// availablePerformers := pm.GetAvailablePerformers()
-	// pm.logger.Debug("Available performers count", "count", len(availablePerformers))
+	// Active performers with their uptime over the last day
+	availablePerformers := hc.getActivePerformers()
+	hc.logger.Debug("Available performers count", "count", len(availablePerformers))
 
 	if len(availablePerformers) == 0 {
 		hc.logger.Warn("No performers available from health service, using fallback")
-		fallbackPerformers := []types.PerformerData{
-			{
+		fallbackPerformers := []types.PerformerHistory{
+			{PerformerData: types.PerformerData{
 				OperatorID:    2,
 				KeeperAddress: "0x0a067a261c5F5e8C4c0b9137430b4FE1255EB62e",
 				IsImua:        false,
-			},
-			{
+			}},
+			{PerformerData: types.PerformerData{
 				OperatorID:    1,
 				KeeperAddress: "0xcacce39134e3b9d5d9220d87fc546c6f0fb9cc37",
 				IsImua:        true,
-			},
+			}},
 		}
 		availablePerformers = fallbackPerformers
 		hc.logger.Info("Using fallback performers", "count", len(availablePerformers))
@@ -88,18 +88,20 @@ func (hc *HealthClient) GetPerformerData(isImua bool, isMainnet bool) (types.Per
 	// 		"is_imua", performer.IsImua)
 	// }
 
-	// Filter by Imua status
-	var filteredPerformer types.PerformerData
+	// Filter by Imua status, then spread tasks over the performers weighted by uptime
+	var candidates []types.PerformerHistory
 	for _, performer := range availablePerformers {
 		if performer.IsImua == isImua {
-			filteredPerformer = performer
+			candidates = append(candidates, performer)
 		}
 	}
 
 	hc.logger.Debug("Filtered performers by Imua status",
 		"is_imua", isImua,
 		"total_available", len(availablePerformers),
-		"filtered_count", 1)
+		"filtered_count", len(candidates))
+
+	filteredPerformer := pickPerformer(candidates)
 
 	if filteredPerformer == (types.PerformerData{}) {
 		hc.logger.Error("No suitable performers available after Imua filtering",
@@ -115,3 +117,85 @@ func (hc *HealthClient) GetPerformerData(isImua bool, isMainnet bool) (types.Per
 
 	return filteredPerformer, nil
 }
+
+// PerformerHistoryResponse represents the performer history response from health service
+type PerformerHistoryResponse struct {
+	Window     string                   `json:"window"`
+	Performers []types.PerformerHistory `json:"performers"`
+	Count      int                      `json:"count"`
+	Timestamp  string                   `json:"timestamp"`
+}
+
+// getActivePerformers returns the active performers, fetching them again once the cached
+// ones are older than PerformerRefreshTTL. If the health service can't be reached the
+// cached performers are kept until it can.
+func (hc *HealthClient) getActivePerformers() []types.PerformerHistory {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	if hc.lastRefresh.IsZero() || time.Since(hc.lastRefresh) > PerformerRefreshTTL {
+		performers, err := hc.fetchActivePerformers()
+		if err != nil {
+			hc.logger.Error("Failed to fetch performer history", "error", err, "cached_count", len(hc.performers))
+		} else {
+			hc.performers = performers
+		}
+		// Failed fetches wait for the next refresh too, so an unavailable health service isn't asked on every dispatch
+		hc.lastRefresh = time.Now()
+	}
+	return hc.performers
+}
+
+// pickPerformer picks a performer at random with a probability proportional to its uptime,
+// so tasks are spread across healthy performers instead of all going to the best one.
+// Performers without uptime, like the fallback ones, are picked uniformly.
+func pickPerformer(performers []types.PerformerHistory) types.PerformerData {
+	if len(performers) == 0 {
+		return types.PerformerData{}
+	}
+
+	var total float64
+	for _, performer := range performers {
+		total += performer.UptimePercent
+	}
+	if total <= 0 {
+		return performers[rand.IntN(len(performers))].PerformerData
+	}
+
+	target := rand.Float64() * total
+	for _, performer := range performers {
+		target -= performer.UptimePercent
+		if target < 0 {
+			return performer.PerformerData
+		}
+	}
+	return performers[len(performers)-1].PerformerData
+}
+
+// fetchActivePerformers returns the currently active performers with their 24h uptime
+func (hc *HealthClient) fetchActivePerformers() ([]types.PerformerHistory, error) {
+	resp, err := hc.client.Get(hc.baseURL + "/performers/history?window=24h")
+	if err != nil {
+		return nil, fmt.Errorf("failed to request performer history: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("health service returned status %d", resp.StatusCode)
+	}
+
+	var history PerformerHistoryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return nil, fmt.Errorf("failed to decode performer history: %w", err)
+	}
+
+	performers := make([]types.PerformerHistory, 0, len(history.Performers))
+	for _, performer := range history.Performers {
+		if performer.IsActive {
+			performers = append(performers, performer)
+		}
+	}
+	return performers, nil
+}
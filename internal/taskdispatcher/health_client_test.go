@@ -0,0 +1,103 @@
+package taskdispatcher
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func TestHealthClient_GetPerformerData_CachesAndSpreads(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "/performers/history", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(PerformerHistoryResponse{
+			Window: "24h",
+			Performers: []types.PerformerHistory{
+				{PerformerData: types.PerformerData{OperatorID: 1, KeeperAddress: "0x1"}, IsActive: true, UptimePercent: 99},
+				{PerformerData: types.PerformerData{OperatorID: 2, KeeperAddress: "0x2"}, IsActive: true, UptimePercent: 60},
+				{PerformerData: types.PerformerData{OperatorID: 3, KeeperAddress: "0x3"}, IsActive: false, UptimePercent: 100},
+				{PerformerData: types.PerformerData{OperatorID: 4, KeeperAddress: "0x4", IsImua: true}, IsActive: true, UptimePercent: 80},
+			},
+		})
+	}))
+	defer server.Close()
+
+	hc := NewHealthClient(logging.NewNoOpLogger(), server.URL)
+
+	picked := make(map[int64]int)
+	for i := 0; i < 200; i++ {
+		performer, err := hc.GetPerformerData(false, false)
+		require.NoError(t, err)
+		picked[performer.OperatorID]++
+	}
+
+	assert.Equal(t, int32(1), requests.Load(), "history is reused within the TTL")
+	assert.NotContains(t, picked, int64(3), "inactive performers are never picked")
+	assert.NotContains(t, picked, int64(4), "imua performers are only picked for imua tasks")
+	assert.Positive(t, picked[1])
+	assert.Positive(t, picked[2], "tasks are spread over healthy performers")
+
+	// Stale scores are fetched again
+	hc.lastRefresh = time.Now().Add(-2 * PerformerRefreshTTL)
+	performer, err := hc.GetPerformerData(true, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), performer.OperatorID)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestHealthClient_GetPerformerData_KeepsCachedOnFailure(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(PerformerHistoryResponse{
+			Performers: []types.PerformerHistory{
+				{PerformerData: types.PerformerData{OperatorID: 7, KeeperAddress: "0x7"}, IsActive: true, UptimePercent: 95},
+			},
+		})
+	}))
+	defer server.Close()
+
+	hc := NewHealthClient(logging.NewNoOpLogger(), server.URL)
+	_, err := hc.GetPerformerData(false, false)
+	require.NoError(t, err)
+
+	fail.Store(true)
+	hc.lastRefresh = time.Now().Add(-2 * PerformerRefreshTTL)
+	performer, err := hc.GetPerformerData(false, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), performer.OperatorID)
+}
+
+func TestPickPerformer(t *testing.T) {
+	assert.Equal(t, types.PerformerData{}, pickPerformer(nil))
+
+	// Performers without uptime are never picked over ones with uptime
+	performers := []types.PerformerHistory{
+		{PerformerData: types.PerformerData{OperatorID: 1}, UptimePercent: 0},
+		{PerformerData: types.PerformerData{OperatorID: 2}, UptimePercent: 50},
+	}
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, int64(2), pickPerformer(performers).OperatorID)
+	}
+
+	// Without any uptime the pick is uniform
+	performers[1].UptimePercent = 0
+	picked := make(map[int64]bool)
+	for i := 0; i < 100; i++ {
+		picked[pickPerformer(performers).OperatorID] = true
+	}
+	assert.Len(t, picked, 2)
+}
@@ -14,35 +14,35 @@ var (
 	// System metrics
 	UptimeSeconds = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "uptime_seconds",
 		Help:      "Time passed since Redis Service started in seconds",
 	})
 
 	MemoryUsageBytes = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "memory_usage_bytes",
 		Help:      "Service memory consumption",
 	})
 
 	CPUUsagePercent = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "cpu_usage_percent",
 		Help:      "CPU utilization percentage",
 	})
 
 	GoroutinesActive = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "goroutines_active",
 		Help:      "Active Go routines",
 	})
 
 	GCDurationSeconds = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "gc_duration_seconds",
 		Help:      "Garbage collection time",
 	})
@@ -50,7 +50,7 @@ var (
 	// Service Health & Availability
 	ServiceStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "service_status",
 		Help:      "Service component health status (component=client/job_stream_manager/task_stream_manager)",
 	}, []string{"component"})
@@ -58,7 +58,7 @@ var (
 	// Single flag to indicate which Redis is being used
 	IsRedisUpstashAvailable = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "is_upstash_available",
 		Help:      "Whether Upstash Redis is available and being used (1=Upstash, 0=Local)",
 	})
@@ -66,28 +66,28 @@ var (
 	// Connection Management
 	ClientConnectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "client_connections_total",
 		Help:      "Redis client connections (status=success/failure)",
 	}, []string{"status"})
 
 	ClientConnectionErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "client_connection_errors_total",
 		Help:      "Redis client connection errors",
 	}, []string{"error_type"})
 
 	PingOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "ping_operations_total",
 		Help:      "Redis ping operations",
 	}, []string{"status"})
 
 	PingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "ping_duration_seconds",
 		Help:      "Redis ping response time",
 		Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1},
@@ -95,7 +95,7 @@ var (
 
 	ConnectionChecksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "connection_checks_total",
 		Help:      "Connection health checks",
 	}, []string{"status"})
@@ -103,42 +103,42 @@ var (
 	// Core Stream Operations
 	TaskStreamLengths = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "task_stream_lengths",
 		Help:      "Current task stream lengths (stream=ready/retry/processing/completed/failed)",
 	}, []string{"stream"})
 
 	JobStreamLengths = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "job_stream_lengths",
 		Help:      "Current job stream lengths (stream=running/completed)",
 	}, []string{"stream"})
 
 	TasksAddedToStreamTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "tasks_added_to_stream_total",
 		Help:      "Tasks added to streams",
 	}, []string{"stream", "status"})
 
 	TasksReadFromStreamTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "tasks_read_from_stream_total",
 		Help:      "Tasks read from streams",
 	}, []string{"stream", "status"})
 
 	JobsAddedToStreamTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "jobs_added_to_stream_total",
 		Help:      "Jobs added to streams",
 	}, []string{"stream", "status"})
 
 	JobsReadFromStreamTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "jobs_read_from_stream_total",
 		Help:      "Jobs read from streams",
 	}, []string{"stream", "status"})
@@ -146,35 +146,35 @@ var (
 	// Task Lifecycle & Performance
 	TaskRetryOperationsTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "task_retry_operations_total",
 		Help:      "Task retry operations",
 	})
 
 	TaskMaxRetriesExceededTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "task_max_retries_exceeded_total",
 		Help:      "Tasks exceeding max retry attempts",
 	})
 
 	TasksMovedToFailedStreamTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "tasks_moved_to_failed_stream_total",
 		Help:      "Tasks permanently failed and moved to failed stream",
 	})
 
 	TaskReadyToProcessingTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "task_ready_to_processing_total",
 		Help:      "Tasks moved from ready to processing stream",
 	})
 
 	TaskProcessingToCompletedTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "task_processing_to_completed_total",
 		Help:      "Tasks moved from processing to completed stream",
 	})
@@ -182,7 +182,7 @@ var (
 	// Updated buckets for longer task execution times (30+ seconds to several minutes)
 	TaskLifecycleTransitionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "task_lifecycle_transition_duration_seconds",
 		Help:      "Task lifecycle transition time",
 		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600}, // Up to 10 minutes
@@ -191,14 +191,14 @@ var (
 	// Redis Client Operation Metrics
 	RedisOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "operations_total",
 		Help:      "Total Redis operations performed",
 	}, []string{"operation", "status"})
 
 	RedisOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "operation_duration_seconds",
 		Help:      "Redis operation duration",
 		Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 2, 5},
@@ -206,21 +206,21 @@ var (
 
 	RedisRetryAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "retry_attempts_total",
 		Help:      "Total Redis retry attempts",
 	}, []string{"operation"})
 
 	RedisConnectionRecoveries = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "connection_recoveries_total",
 		Help:      "Total Redis connection recovery attempts",
 	}, []string{"status"})
 
 	RedisConnectionHealth = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "triggerx",
-		Subsystem: "redis",
+		Subsystem: "task_dispatcher",
 		Name:      "connection_health",
 		Help:      "Redis connection health status",
 	}, []string{"type"})
@@ -7,13 +7,15 @@ import (
 
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	rpcclient "github.com/trigg3rX/triggerx-backend/pkg/rpc/client"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
 // TaskDispatcherClient provides a client for the task dispatcher service
 type TaskDispatcherClient struct {
-	client *rpcclient.Client
-	logger logging.Logger
+	client  *rpcclient.Client
+	service rpcproto.TaskDispatcherServiceClient
+	logger  logging.Logger
 }
 
 // NewTaskDispatcherClient creates a new TaskDispatcherClient
@@ -30,8 +32,9 @@ func NewTaskDispatcherClient(address string, logger logging.Logger) (*TaskDispat
 	client := rpcclient.NewClient(config, logger)
 
 	return &TaskDispatcherClient{
-		client: client,
-		logger: logger,
+		client:  client,
+		service: rpcproto.NewTaskDispatcherServiceClient(client),
+		logger:  logger,
 	}, nil
 }
 
@@ -46,17 +49,17 @@ func (c *TaskDispatcherClient) SubmitTask(ctx context.Context, req *types.Schedu
 		"source", req.Source,
 		"task_count", len(req.SendTaskDataToKeeper.TaskID))
 
-	var response types.TaskManagerAPIResponse
-	err := c.client.Call(ctx, "submit-task", req, &response)
+	resp, err := c.service.SubmitTask(ctx, rpcproto.NewSubmitTaskRequest(req))
 	if err != nil {
 		c.logger.Error("gRPC call failed",
 			"error", err)
 		return nil, fmt.Errorf("gRPC call failed: %w", err)
 	}
+	response := resp.ToTypes()
 
 	c.logger.Debug("Task submission completed",
 		"success", response.Success,
 		"task_count", len(response.TaskID))
 
-	return &response, nil
+	return response, nil
 }
@@ -6,9 +6,23 @@ import (
 	"strconv"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 	rpcserver "github.com/trigg3rX/triggerx-backend/pkg/rpc/server"
 )
 
+// schedulers are the services submitting tasks to the dispatcher
+var schedulers = []string{rpcpkg.ServiceTimeScheduler, rpcpkg.ServiceConditionScheduler}
+
+// MethodPolicy lists the services allowed to call each method of the dispatcher
+var MethodPolicy = map[string][]string{
+	"submit-task": schedulers,
+	rpcproto.TaskDispatcherService_SubmitTask_FullMethodName:   schedulers,
+	rpcproto.TaskDispatcherService_SubmitBatch_FullMethodName:  schedulers,
+	rpcproto.TaskDispatcherService_StreamSubmit_FullMethodName: schedulers,
+	rpcproto.GenericService_GetMethods_FullMethodName:          {"*"},
+}
+
 // StartRPCServer creates and starts the Task Dispatcher gRPC server using the generic approach.
 // It registers the task dispatcher handler with the generic RPC server.
 func StartRPCServer(ctx context.Context, logger logging.Logger, dispatcher TaskDispatcherInterface, addr string, portStr string) (*rpcserver.Server, error) {
@@ -26,10 +40,12 @@ func StartRPCServer(ctx context.Context, logger logging.Logger, dispatcher TaskD
 
 	// Add useful middleware (logging)
 	srv.AddInterceptor(rpcserver.LoggingInterceptor(logger))
+	srv.AddStreamInterceptor(rpcserver.LoggingStreamInterceptor(logger))
 
 	// Create and register the generic RPC handler
 	handler := NewTaskDispatcherHandler(logger, dispatcher)
 	srv.RegisterHandler("TaskDispatcher", handler)
+	rpcproto.RegisterTaskDispatcherServiceServer(srv, NewTaskDispatcherService(logger, dispatcher))
 
 	if err := srv.Start(ctx); err != nil {
 		return nil, err
@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+)
+
+// TaskDispatcherService implements the typed TaskDispatcherService gRPC API
+type TaskDispatcherService struct {
+	rpcproto.UnimplementedTaskDispatcherServiceServer
+	logger     logging.Logger
+	dispatcher TaskDispatcherInterface
+}
+
+// NewTaskDispatcherService creates the typed gRPC service
+func NewTaskDispatcherService(logger logging.Logger, dispatcher TaskDispatcherInterface) *TaskDispatcherService {
+	return &TaskDispatcherService{
+		logger:     logger,
+		dispatcher: dispatcher,
+	}
+}
+
+// SubmitTask submits a batch of tasks for one performer
+func (s *TaskDispatcherService) SubmitTask(ctx context.Context, req *rpcproto.SubmitTaskRequest) (*rpcproto.SubmitTaskResponse, error) {
+	taskReq, err := req.ToTypes()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+
+	resp, err := s.dispatcher.SubmitTaskFromScheduler(ctx, taskReq)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to submit task: %v", err)
+	}
+	return rpcproto.NewSubmitTaskResponse(resp), nil
+}
+
+// SubmitBatch submits several batches, a failed batch is reported in its response
+func (s *TaskDispatcherService) SubmitBatch(ctx context.Context, req *rpcproto.SubmitBatchRequest) (*rpcproto.SubmitBatchResponse, error) {
+	batch := &rpcproto.SubmitBatchResponse{}
+	for _, taskReq := range req.GetRequests() {
+		resp, err := s.SubmitTask(ctx, taskReq)
+		if err != nil {
+			s.logger.Error("Failed to submit task of batch", "source", taskReq.GetSource(), "error", err)
+			resp = failedSubmitResponse(taskReq, err)
+		}
+		if resp.GetSuccess() {
+			batch.Submitted++
+		} else {
+			batch.Failed++
+		}
+		batch.Responses = append(batch.Responses, resp)
+	}
+	return batch, nil
+}
+
+// StreamSubmit submits the batches received on the stream one at a time, answering each with
+// the sequence of its request. A failed batch is reported in its response; a slow dispatcher
+// holds the client back through the stream's flow control.
+func (s *TaskDispatcherService) StreamSubmit(ss grpc.BidiStreamingServer[rpcproto.StreamSubmitRequest, rpcproto.StreamSubmitResponse]) error {
+	for {
+		req, err := ss.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.SubmitTask(ss.Context(), req.GetRequest())
+		if err != nil {
+			s.logger.Error("Failed to submit streamed task", "sequence", req.GetSequence(), "error", err)
+			resp = failedSubmitResponse(req.GetRequest(), err)
+		}
+		if err := ss.Send(&rpcproto.StreamSubmitResponse{Sequence: req.GetSequence(), Response: resp}); err != nil {
+			return err
+		}
+	}
+}
+
+// failedSubmitResponse reports a batch that could not be submitted
+func failedSubmitResponse(req *rpcproto.SubmitTaskRequest, err error) *rpcproto.SubmitTaskResponse {
+	return &rpcproto.SubmitTaskResponse{
+		Success: false,
+		TaskId:  req.GetTaskData().GetTaskId(),
+		Error:   status.Convert(err).Message(),
+	}
+}
@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcclient "github.com/trigg3rX/triggerx-backend/pkg/rpc/client"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+	rpcserver "github.com/trigg3rX/triggerx-backend/pkg/rpc/server"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// startTypedService serves the typed dispatcher API over an in-memory listener and returns
+// a typed client going through the pooled RPC client
+func startTypedService(t *testing.T, dispatcher TaskDispatcherInterface) rpcproto.TaskDispatcherServiceClient {
+	t.Helper()
+	logger := logging.NewNoOpLogger()
+
+	srv := rpcserver.NewServer(rpcserver.Config{Name: "TaskDispatcher"}, logger)
+	rpcproto.RegisterTaskDispatcherServiceServer(srv, NewTaskDispatcherService(logger, dispatcher))
+	listener := bufconn.Listen(1 << 20)
+	require.NoError(t, srv.StartWithListener(context.Background(), listener))
+	t.Cleanup(func() { _ = srv.Stop(context.Background()) })
+
+	client := rpcclient.NewClient(rpcclient.Config{
+		ServiceName: "passthrough:///bufnet",
+		Timeout:     5 * time.Second,
+		MaxRetries:  1,
+		PoolSize:    2,
+		DialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+		},
+	}, logger)
+	t.Cleanup(func() { _ = client.Close() })
+	return rpcproto.NewTaskDispatcherServiceClient(client)
+}
+
+func testTaskRequest(taskID int64) *types.SchedulerTaskRequest {
+	return &types.SchedulerTaskRequest{
+		SendTaskDataToKeeper: types.SendTaskDataToKeeper{
+			TaskID:      []int64{taskID},
+			TargetData:  []types.TaskTargetData{{TaskID: taskID, TargetChainID: "84532"}},
+			TriggerData: []types.TaskTriggerData{{TaskID: taskID}},
+			SchedulerID: 1,
+		},
+		Source: "test_scheduler",
+	}
+}
+
+func TestTaskDispatcherService_SubmitTask(t *testing.T) {
+	mockDispatcher := &MockTaskDispatcherInterface{}
+	mockDispatcher.On("SubmitTaskFromScheduler", mock.Anything, mock.MatchedBy(func(req *types.SchedulerTaskRequest) bool {
+		return req.Source == "test_scheduler" && req.SendTaskDataToKeeper.TaskID[0] == 123
+	})).Return(&types.TaskManagerAPIResponse{Success: true, TaskID: []int64{123}, Message: "ok"}, nil)
+	client := startTypedService(t, mockDispatcher)
+
+	resp, err := client.SubmitTask(context.Background(), rpcproto.NewSubmitTaskRequest(testTaskRequest(123)))
+	require.NoError(t, err)
+	assert.True(t, resp.GetSuccess())
+	assert.Equal(t, []int64{123}, resp.GetTaskId())
+
+	_, err = client.SubmitTask(context.Background(), &rpcproto.SubmitTaskRequest{Source: "test_scheduler"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockDispatcher.AssertExpectations(t)
+}
+
+func TestTaskDispatcherService_SubmitBatch(t *testing.T) {
+	mockDispatcher := &MockTaskDispatcherInterface{}
+	mockDispatcher.On("SubmitTaskFromScheduler", mock.Anything, mock.MatchedBy(func(req *types.SchedulerTaskRequest) bool {
+		return req.SendTaskDataToKeeper.TaskID[0] == 1
+	})).Return(&types.TaskManagerAPIResponse{Success: true, TaskID: []int64{1}}, nil)
+	mockDispatcher.On("SubmitTaskFromScheduler", mock.Anything, mock.MatchedBy(func(req *types.SchedulerTaskRequest) bool {
+		return req.SendTaskDataToKeeper.TaskID[0] == 2
+	})).Return(nil, errors.New("no performer available"))
+	client := startTypedService(t, mockDispatcher)
+
+	resp, err := client.SubmitBatch(context.Background(), &rpcproto.SubmitBatchRequest{
+		Requests: []*rpcproto.SubmitTaskRequest{
+			rpcproto.NewSubmitTaskRequest(testTaskRequest(1)),
+			rpcproto.NewSubmitTaskRequest(testTaskRequest(2)),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), resp.GetSubmitted())
+	assert.Equal(t, int32(1), resp.GetFailed())
+	require.Len(t, resp.GetResponses(), 2)
+	assert.True(t, resp.GetResponses()[0].GetSuccess())
+	assert.False(t, resp.GetResponses()[1].GetSuccess())
+	assert.Contains(t, resp.GetResponses()[1].GetError(), "no performer available")
+}
+
+func TestTaskDispatcherService_StreamSubmit(t *testing.T) {
+	mockDispatcher := &MockTaskDispatcherInterface{}
+	mockDispatcher.On("SubmitTaskFromScheduler", mock.Anything, mock.MatchedBy(func(req *types.SchedulerTaskRequest) bool {
+		return req.SendTaskDataToKeeper.TaskID[0] == 1
+	})).Return(&types.TaskManagerAPIResponse{Success: true, TaskID: []int64{1}}, nil)
+	mockDispatcher.On("SubmitTaskFromScheduler", mock.Anything, mock.MatchedBy(func(req *types.SchedulerTaskRequest) bool {
+		return req.SendTaskDataToKeeper.TaskID[0] == 2
+	})).Return(nil, errors.New("no performer available"))
+	client := startTypedService(t, mockDispatcher)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.StreamSubmit(ctx)
+	require.NoError(t, err)
+
+	for i, taskID := range []int64{1, 2} {
+		require.NoError(t, stream.Send(&rpcproto.StreamSubmitRequest{
+			Sequence: uint64(i + 1),
+			Request:  rpcproto.NewSubmitTaskRequest(testTaskRequest(taskID)),
+		}))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), first.GetSequence())
+	assert.True(t, first.GetResponse().GetSuccess())
+
+	second, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), second.GetSequence())
+	assert.False(t, second.GetResponse().GetSuccess())
+	assert.Contains(t, second.GetResponse().GetError(), "no performer available")
+
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+	mockDispatcher.AssertExpectations(t)
+}
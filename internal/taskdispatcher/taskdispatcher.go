@@ -19,6 +19,7 @@ type TaskDispatcher struct {
 	healthClient      *HealthClient
 	signingKey        string
 	signingAddress    string
+	signatureFormat   cryptography.SignatureFormat
 }
 
 // NewTaskDispatcher constructs a new dispatcher with an initialized aggregator client.
@@ -27,7 +28,8 @@ func NewTaskDispatcher(
 	taskStreamManager *tasks.TaskStreamManager,
 	healthClient *HealthClient,
 	signingKey string,
-	signingAddress string) (*TaskDispatcher, error) {
+	signingAddress string,
+	signatureFormat cryptography.SignatureFormat) (*TaskDispatcher, error) {
 
 	return &TaskDispatcher{
 		logger:            logger,
@@ -35,6 +37,7 @@ func NewTaskDispatcher(
 		healthClient:      healthClient,
 		signingKey:        signingKey,
 		signingAddress:    signingAddress,
+		signatureFormat:   signatureFormat,
 	}, nil
 }
 
@@ -76,7 +79,12 @@ func (d *TaskDispatcher) SubmitTaskFromScheduler(ctx context.Context, req *types
 	req.SendTaskDataToKeeper.PerformerData = performer
 
 	// Sign the task data with improved error handling
-	signature, err := cryptography.SignJSONMessage(req.SendTaskDataToKeeper, d.signingKey)
+	signature, err := cryptography.SignStructured(
+		req.SendTaskDataToKeeper,
+		req.SendTaskDataToKeeper.SigningChainID(),
+		d.signingKey,
+		d.signatureFormat,
+	)
 	if err != nil {
 		d.logger.Error("Failed to sign batch task data",
 			"task_id", req.SendTaskDataToKeeper.TaskID[0],
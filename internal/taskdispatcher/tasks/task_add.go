@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -12,14 +13,35 @@ import (
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
+// AddTaskToDispatchedStream sends a task to its performer and tracks it in the dispatched
+// stream. Schedulers resubmit the batches in flight when their submit stream breaks, so a
+// task is dispatched once: resubmissions within TasksProcessingTTL return false without error.
 func (tsm *TaskStreamManager) AddTaskToDispatchedStream(ctx context.Context, task TaskStreamData) (bool, error) {
+	taskID := task.SendTaskDataToKeeper.TaskID[0]
+
 	// Prepare payload identical to previous implementation
 	jsonData, err := json.Marshal(task.SendTaskDataToKeeper)
 	if err != nil {
-		tsm.logger.Error("Failed to marshal scheduler task data", "task_id", task.SendTaskDataToKeeper.TaskID[0], "error", err)
+		tsm.logger.Error("Failed to marshal scheduler task data", "task_id", taskID, "error", err)
 		return false, fmt.Errorf("failed to marshal task data: %w", err)
 	}
 
+	claimed, err := tsm.claimTaskDispatch(ctx, taskID)
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		metrics.TasksAddedToStreamTotal.WithLabelValues(StreamTaskDispatched, "duplicate").Inc()
+		tsm.logger.Info("Skipping task already dispatched", "task_id", taskID)
+		return false, nil
+	}
+	dispatched := false
+	defer func() {
+		if !dispatched {
+			tsm.releaseTaskDispatch(taskID)
+		}
+	}()
+
 	broadcast := types.BroadcastDataForPerformer{
 		TaskID:           task.SendTaskDataToKeeper.TaskID[0],
 		TaskDefinitionID: task.SendTaskDataToKeeper.TargetData[0].TaskDefinitionID,
@@ -50,9 +72,29 @@ func (tsm *TaskStreamManager) AddTaskToDispatchedStream(ctx context.Context, tas
 	if !success {
 		return false, fmt.Errorf("failed to add task to stream")
 	}
+	dispatched = true
 	return true, nil
 }
 
+// claimTaskDispatch claims the dispatch of a task, false when it was claimed already
+func (tsm *TaskStreamManager) claimTaskDispatch(ctx context.Context, taskID int64) (bool, error) {
+	claimed, err := tsm.client.SetNX(ctx, taskDispatchClaimPrefix+strconv.FormatInt(taskID, 10), time.Now().Unix(), TasksProcessingTTL)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim dispatch of task %d: %w", taskID, err)
+	}
+	return claimed, nil
+}
+
+// releaseTaskDispatch drops the claim of a task that could not be dispatched, so the
+// scheduler's retry dispatches it
+func (tsm *TaskStreamManager) releaseTaskDispatch(taskID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.GetRequestTimeout())
+	defer cancel()
+	if err := tsm.client.Del(ctx, taskDispatchClaimPrefix+strconv.FormatInt(taskID, 10)); err != nil {
+		tsm.logger.Warn("Failed to release dispatch claim", "task_id", taskID, "error", err)
+	}
+}
+
 func (tsm *TaskStreamManager) addTaskToStream(ctx context.Context, stream string, task *TaskStreamData) (bool, error) {
 	start := time.Now()
 	ctx, cancel := context.WithTimeout(ctx, config.GetRequestTimeout())
@@ -22,6 +22,9 @@ const (
 
 	// Retry Configuration
 	MaxRetryAttempts = 3
+
+	// Dispatch claims, a task already claimed is a resubmission and is not sent again
+	taskDispatchClaimPrefix = "task:dispatch_claim:"
 )
 
 // TaskStreamData represents task information for Redis-managed task streams
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/clients/database/queries"
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/types"
+)
+
+// TrackIPFSPin records a pinned CID so the retention policy can unpin it later
+func (dm *DatabaseClient) TrackIPFSPin(pin types.IPFSPin) error {
+	if err := dm.db.NewQuery(queries.InsertIPFSPin,
+		pin.CID,
+		pin.TaskID,
+		pin.JobID,
+		pin.TaskDefinitionID,
+		pin.SizeBytes,
+		pin.PinnedAt).Exec(); err != nil {
+		return fmt.Errorf("failed to track pin %s: %w", pin.CID, err)
+	}
+	return nil
+}
+
+// ListIPFSPins returns up to limit tracked pins in token order, starting after afterCID,
+// or from the first pin when afterCID is empty
+func (dm *DatabaseClient) ListIPFSPins(afterCID string, limit int) ([]types.IPFSPin, error) {
+	query := dm.db.NewQuery(queries.ListIPFSPins, limit)
+	if afterCID != "" {
+		query = dm.db.NewQuery(queries.ListIPFSPinsAfter, afterCID, limit)
+	}
+	iter := query.Iter()
+
+	var pins []types.IPFSPin
+	var pin types.IPFSPin
+	for iter.Scan(&pin.CID, &pin.TaskID, &pin.JobID, &pin.TaskDefinitionID, &pin.SizeBytes, &pin.PinnedAt) {
+		pins = append(pins, pin)
+		pin = types.IPFSPin{}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list pins: %w", err)
+	}
+	return pins, nil
+}
+
+// DeleteIPFSPin stops tracking an unpinned CID
+func (dm *DatabaseClient) DeleteIPFSPin(cid string) error {
+	if err := dm.db.NewQuery(queries.DeleteIPFSPin, cid).Exec(); err != nil {
+		return fmt.Errorf("failed to delete pin %s: %w", cid, err)
+	}
+	return nil
+}
+
+// GetJobRetentionState reads the completion of a job from the table of its task definition
+func (dm *DatabaseClient) GetJobRetentionState(taskDefinitionID int, jobID *big.Int) (types.JobRetentionState, error) {
+	var query string
+	switch taskDefinitionID {
+	case 1, 2:
+		query = queries.GetTimeJobRetentionState
+	case 3, 4:
+		query = queries.GetEventJobRetentionState
+	case 5, 6:
+		query = queries.GetConditionJobRetentionState
+	case 7:
+		query = queries.GetCustomJobRetentionState
+	default:
+		return types.JobRetentionState{}, fmt.Errorf("unsupported task definition ID %d", taskDefinitionID)
+	}
+
+	var state types.JobRetentionState
+	iter := dm.db.NewQuery(query, jobID).Iter()
+	defer func() {
+		if cerr := iter.Close(); cerr != nil {
+			dm.logger.Errorf("Error closing iterator: %v", cerr)
+		}
+	}()
+
+	if !iter.Scan(&state.IsCompleted, &state.UpdatedAt, &state.ExpirationTime) {
+		return types.JobRetentionState{}, fmt.Errorf("job not found for job ID %s", jobID)
+	}
+	return state, nil
+}
+
+// GetExecutionRetentionState returns the verification state of the custom script execution
+// of a task, or nil if the task has no execution recorded
+func (dm *DatabaseClient) GetExecutionRetentionState(taskID int64) (*types.ExecutionRetentionState, error) {
+	var state types.ExecutionRetentionState
+	iter := dm.db.NewQuery(queries.GetExecutionRetentionState, taskID).Iter()
+	found := iter.Scan(&state.VerificationStatus, &state.IsChallenged, &state.ChallengeDeadline)
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get execution of task %d: %w", taskID, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &state, nil
+}
@@ -0,0 +1,43 @@
+package queries
+
+const (
+	InsertIPFSPin = `
+        INSERT INTO triggerx.ipfs_pins (
+            cid, task_id, job_id, task_definition_id, size_bytes, pinned_at
+        ) VALUES (?, ?, ?, ?, ?, ?)`
+	ListIPFSPins = `
+        SELECT cid, task_id, job_id, task_definition_id, size_bytes, pinned_at
+        FROM triggerx.ipfs_pins
+        LIMIT ?`
+	ListIPFSPinsAfter = `
+        SELECT cid, task_id, job_id, task_definition_id, size_bytes, pinned_at
+        FROM triggerx.ipfs_pins
+        WHERE token(cid) > token(?)
+        LIMIT ?`
+	DeleteIPFSPin = `
+        DELETE FROM triggerx.ipfs_pins
+        WHERE cid = ?`
+
+	// Job completion, one query per job table
+	GetTimeJobRetentionState = `
+        SELECT is_completed, updated_at, expiration_time
+        FROM triggerx.time_job_data
+        WHERE job_id = ?`
+	GetEventJobRetentionState = `
+        SELECT is_completed, updated_at, expiration_time
+        FROM triggerx.event_job_data
+        WHERE job_id = ?`
+	GetConditionJobRetentionState = `
+        SELECT is_completed, updated_at, expiration_time
+        FROM triggerx.condition_job_data
+        WHERE job_id = ?`
+	GetCustomJobRetentionState = `
+        SELECT is_completed, updated_at, expiration_time
+        FROM triggerx.custom_jobs
+        WHERE job_id = ?`
+
+	GetExecutionRetentionState = `
+        SELECT verification_status, is_challenged, challenge_deadline
+        FROM triggerx.custom_script_executions
+        WHERE task_id = ?`
+)
@@ -4,9 +4,8 @@ const (
 	// Getters
 	GetKeeperIDByAddress = `
         SELECT keeper_id 
-        FROM triggerx.keeper_data 
-        WHERE keeper_address = ? 
-        ALLOW FILTERING`
+        FROM triggerx.keepers_by_address 
+        WHERE keeper_address = ?`
 	GetTaskCostAndJobId = `
         SELECT task_opx_predicted_cost, job_id 
         FROM triggerx.task_data 
@@ -15,14 +14,14 @@ const (
         SELECT user_id 
         FROM triggerx.job_data 
         WHERE job_id = ?`
+	// Reads keeper_data_operator_id_idx
 	GetAttesterPointsAndNoOfTasks = `
         SELECT keeper_id,
             keeper_points, 
             rewards_booster,
             no_attested_tasks
         FROM triggerx.keeper_data 
-        WHERE operator_id = ?
-        ALLOW FILTERING`
+        WHERE operator_id = ?`
 	GetPerformerPointsAndNoOfTasks = `
         SELECT keeper_points, 
             rewards_booster,
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"time"
 
@@ -8,6 +9,9 @@ import (
 	"github.com/joho/godotenv"
 	redisClient "github.com/trigg3rX/triggerx-backend/pkg/client/redis"
 	"github.com/trigg3rX/triggerx-backend/pkg/env"
+	"github.com/trigg3rX/triggerx-backend/pkg/ipfs"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
 )
 
 type Config struct {
@@ -24,10 +28,22 @@ type Config struct {
 	rpcProvider string
 	rpcAPIKey   string
 
+	// RPC URL overriding the provider for the attestation chain, e.g. the local aggregator's simulated chain
+	attestationChainRPCUrl string
+
 	// ScyllaDB Host and Port
 	databaseHostAddress string
 	databaseHostPort    string
 
+	// DBServer URL task completions are reported to for workflow runs, none when empty
+	dbServerURL string
+	// Client certificate the DBServer is called with, disabled when no certificate file is set
+	rpcTLS mtls.Config
+	// Key signing the service tokens the DBServer is called with, none when empty
+	serviceTokenKey ed25519.PrivateKey
+	// Keys the service tokens of RPC callers are verified with
+	serviceTokenPublicKeys []ed25519.PublicKey
+
 	// Upstash Redis URL and Rest Token
 	upstashRedisUrl       string
 	upstashRedisRestToken string
@@ -39,6 +55,22 @@ type Config struct {
 	pinataJWT  string
 	pinataHost string
 
+	// IPFS storage backends, task data is replicated to all of them when several are listed
+	ipfsStorageBackends []ipfs.Backend
+	ipfsMinReplicas     int
+	kuboAPIUrl          string
+	ipfsLocalDir        string
+	s3Config            ipfs.S3Config
+
+	// IPFS retention, task data is unpinned a period after its job completed
+	ipfsRetentionEnabled   bool
+	ipfsRetentionPeriod    time.Duration
+	ipfsRetentionInterval  time.Duration
+	ipfsRetentionBatchSize int
+
+	// Whether keepers may still sign error reports in the legacy JSON format
+	acceptLegacySignatures bool
+
 	// OpenTelemetry endpoint
 	ottempoEndpoint string
 
@@ -88,6 +120,12 @@ func Init() error {
 	if err := godotenv.Load(); err != nil {
 		return fmt.Errorf("error loading .env file: %w", err)
 	}
+	return Load()
+}
+
+// Load reads the configuration from the environment only, for processes that set it
+// themselves instead of shipping a .env file.
+func Load() error {
 	cfg = Config{
 		devMode:                      env.GetEnvBool("DEV_MODE", false),
 		taskMonitorRPCPort:           env.GetEnvString("TASK_MONITOR_RPC_PORT", "9007"),
@@ -95,12 +133,17 @@ func Init() error {
 		testAttestationCenterAddress: env.GetEnvString("TEST_ATTESTATION_CENTER_ADDRESS", ""),
 		rpcProvider:                  env.GetEnvString("RPC_PROVIDER", ""),
 		rpcAPIKey:                    env.GetEnvString("RPC_API_KEY", ""),
+		attestationChainRPCUrl:       env.GetEnvString("ATTESTATION_CHAIN_RPC_URL", ""),
 		databaseHostAddress:          env.GetEnvString("DATABASE_HOST_ADDRESS", ""),
 		databaseHostPort:             env.GetEnvString("DATABASE_HOST_PORT", ""),
+		dbServerURL:                  env.GetEnvString("DBSERVER_RPC_URL", "http://localhost:9002"),
 		upstashRedisUrl:              env.GetEnvString("UPSTASH_REDIS_URL", ""),
 		upstashRedisRestToken:        env.GetEnvString("UPSTASH_REDIS_REST_TOKEN", ""),
 		pinataJWT:                    env.GetEnvString("PINATA_JWT", ""),
 		pinataHost:                   env.GetEnvString("PINATA_HOST", ""),
+		ipfsMinReplicas:              env.GetEnvInt("IPFS_MIN_REPLICAS", 1),
+		kuboAPIUrl:                   env.GetEnvString("KUBO_API_URL", "http://127.0.0.1:5001"),
+		ipfsLocalDir:                 env.GetEnvString("IPFS_LOCAL_DIR", "data/ipfs"),
 		poolSize:                     env.GetEnvInt("REDIS_POOL_SIZE", 10),
 		minIdleConns:                 env.GetEnvInt("REDIS_MIN_IDLE_CONNS", 2),
 		maxRetries:                   env.GetEnvInt("REDIS_MAX_RETRIES", 3),
@@ -128,6 +171,41 @@ func Init() error {
 		smtpFrom:                     env.GetEnvString("SMTP_FROM", ""),
 		smtpStartTLS:                 env.GetEnvBool("SMTP_STARTTLS", true),
 	}
+	ipfsStorageBackends, err := ipfs.ParseBackends(env.GetEnvString("IPFS_STORAGE_BACKENDS", string(ipfs.BackendPinata)))
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	cfg.ipfsStorageBackends = ipfsStorageBackends
+	cfg.s3Config = ipfs.S3Config{
+		Endpoint:        env.GetEnvString("S3_ENDPOINT", ""),
+		Region:          env.GetEnvString("S3_REGION", "us-east-1"),
+		Bucket:          env.GetEnvString("S3_BUCKET", ""),
+		AccessKeyID:     env.GetEnvString("S3_ACCESS_KEY_ID", ""),
+		SecretAccessKey: env.GetEnvString("S3_SECRET_ACCESS_KEY", ""),
+		KeyPrefix:       env.GetEnvString("S3_KEY_PREFIX", "ipfs/"),
+	}
+	cfg.rpcTLS = mtls.Config{
+		CertFile: env.GetEnvString("RPC_TLS_CERT_FILE", ""),
+		KeyFile:  env.GetEnvString("RPC_TLS_KEY_FILE", ""),
+		CAFile:   env.GetEnvString("RPC_TLS_CA_FILE", ""),
+	}
+	if key := env.GetEnvString("SERVICE_TOKEN_PRIVATE_KEY", ""); key != "" {
+		serviceTokenKey, err := jwt.ParseServiceTokenKey(key)
+		if err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+		cfg.serviceTokenKey = serviceTokenKey
+	}
+	serviceTokenPublicKeys, err := jwt.ParseServiceTokenPublicKeys(env.GetEnvString("SERVICE_TOKEN_PUBLIC_KEYS", ""))
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	cfg.serviceTokenPublicKeys = serviceTokenPublicKeys
+	cfg.acceptLegacySignatures = env.GetEnvBool("ACCEPT_LEGACY_SIGNATURES", true)
+	cfg.ipfsRetentionEnabled = env.GetEnvBool("IPFS_RETENTION_ENABLED", false)
+	cfg.ipfsRetentionPeriod = env.GetEnvDuration("IPFS_RETENTION_PERIOD", 720*time.Hour)
+	cfg.ipfsRetentionInterval = env.GetEnvDuration("IPFS_RETENTION_INTERVAL", time.Hour)
+	cfg.ipfsRetentionBatchSize = env.GetEnvInt("IPFS_RETENTION_BATCH_SIZE", 100)
 
 	if !cfg.devMode {
 		gin.SetMode(gin.ReleaseMode)
@@ -147,6 +225,29 @@ func GetDatabaseHostPort() string {
 	return cfg.databaseHostPort
 }
 
+// GetDBServerURL returns the DBServer URL task completions are reported to, empty when they
+// are not reported
+func GetDBServerURL() string {
+	return cfg.dbServerURL
+}
+
+// GetRPCTLSConfig returns the certificate the DBServer is called with, disabled when no
+// certificate file is set
+func GetRPCTLSConfig() mtls.Config {
+	return cfg.rpcTLS
+}
+
+// GetServiceTokenKey returns the key signing the service tokens the DBServer is called
+// with, nil when unset
+func GetServiceTokenKey() ed25519.PrivateKey {
+	return cfg.serviceTokenKey
+}
+
+// GetServiceTokenPublicKeys returns the keys the service tokens of RPC callers are verified with
+func GetServiceTokenPublicKeys() []ed25519.PublicKey {
+	return cfg.serviceTokenPublicKeys
+}
+
 func SetLastBaseBlockUpdated(blockNumber uint64) {
 	cfg.lastBaseBlockUpdated = blockNumber
 }
@@ -163,6 +264,14 @@ func GetTestAttestationCenterAddress() string {
 	return cfg.testAttestationCenterAddress
 }
 
+// GetAttestationChainRPCUrl returns the RPC URL the event listener polls the AttestationCenter on
+func GetAttestationChainRPCUrl(chainID string) string {
+	if cfg.attestationChainRPCUrl != "" {
+		return cfg.attestationChainRPCUrl
+	}
+	return GetChainRPCUrl(true, chainID)
+}
+
 func GetRPCProvider() string {
 	return cfg.rpcProvider
 }
@@ -179,6 +288,38 @@ func GetPinataJWT() string {
 	return cfg.pinataJWT
 }
 
+// GetIPFSStorageConfig returns the backends task data is fetched from
+func GetIPFSStorageConfig() ipfs.StorageConfig {
+	return ipfs.StorageConfig{
+		Backends:    cfg.ipfsStorageBackends,
+		MinReplicas: cfg.ipfsMinReplicas,
+		Pinata:      ipfs.NewConfig(cfg.pinataHost, cfg.pinataJWT),
+		Kubo:        ipfs.KuboConfig{APIURL: cfg.kuboAPIUrl},
+		S3:          cfg.s3Config,
+		Local:       ipfs.LocalConfig{Dir: cfg.ipfsLocalDir},
+	}
+}
+
+func GetAcceptLegacySignatures() bool {
+	return cfg.acceptLegacySignatures
+}
+
+func IsIPFSRetentionEnabled() bool {
+	return cfg.ipfsRetentionEnabled
+}
+
+func GetIPFSRetentionPeriod() time.Duration {
+	return cfg.ipfsRetentionPeriod
+}
+
+func GetIPFSRetentionInterval() time.Duration {
+	return cfg.ipfsRetentionInterval
+}
+
+func GetIPFSRetentionBatchSize() int {
+	return cfg.ipfsRetentionBatchSize
+}
+
 func GetTaskMonitorRPCPort() string {
 	return cfg.taskMonitorRPCPort
 }
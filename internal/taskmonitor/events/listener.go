@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	dbserverTypes "github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/clients/database"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/clients/notify"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/config"
@@ -77,6 +78,7 @@ type ContractEventListener struct {
 	dbClient          *database.DatabaseClient
 	ipfsClient        ipfs.IPFSClient
 	taskStreamManager *tasks.TaskStreamManager
+	completions       TaskCompletionReporter
 }
 
 // ListenerConfig holds configuration for the event listener
@@ -124,10 +126,18 @@ type TaskEventHandler struct {
 	ipfsClient        ipfs.IPFSClient
 	taskStreamManager *tasks.TaskStreamManager
 	notifier          notify.Notifier
+	// completions receives the completed tasks for workflow runs, nil when they are not reported
+	completions TaskCompletionReporter
+}
+
+// TaskCompletionReporter reports completed tasks to the DBServer, which runs the workflows
+// their jobs are part of
+type TaskCompletionReporter interface {
+	ReportTaskCompleted(ctx context.Context, taskCompleted dbserverTypes.TaskCompletedRequest) error
 }
 
 // NewContractEventListener creates a new contract event listener
-func NewContractEventListener(logger logging.Logger, config *ListenerConfig, dbClient *database.DatabaseClient, ipfsClient ipfs.IPFSClient, taskStreamManager *tasks.TaskStreamManager) *ContractEventListener {
+func NewContractEventListener(logger logging.Logger, config *ListenerConfig, dbClient *database.DatabaseClient, ipfsClient ipfs.IPFSClient, taskStreamManager *tasks.TaskStreamManager, completions TaskCompletionReporter) *ContractEventListener {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &ContractEventListener{
@@ -138,6 +148,7 @@ func NewContractEventListener(logger logging.Logger, config *ListenerConfig, dbC
 		eventChan:         make(chan *ChainEvent, config.EventBufferSize),
 		dbClient:          dbClient,
 		ipfsClient:        ipfsClient,
+		completions:       completions,
 		taskStreamManager: taskStreamManager,
 	}
 }
@@ -215,7 +226,7 @@ func (l *ContractEventListener) startEventProcessors() {
 	processor := &EventProcessor{
 		logger:          l.logger,
 		operatorHandler: &OperatorEventHandler{logger: l.logger},
-		taskHandler:     &TaskEventHandler{logger: l.logger, db: l.dbClient, ipfsClient: l.ipfsClient, taskStreamManager: l.taskStreamManager, notifier: notify.NewCompositeNotifier(l.logger, notify.NewWebhookNotifier(l.logger), notify.NewSMTPNotifier(l.logger))},
+		taskHandler:     &TaskEventHandler{logger: l.logger, db: l.dbClient, ipfsClient: l.ipfsClient, taskStreamManager: l.taskStreamManager, notifier: notify.NewCompositeNotifier(l.logger, notify.NewWebhookNotifier(l.logger), notify.NewSMTPNotifier(l.logger)), completions: l.completions},
 	}
 
 	// Start multiple processing workers
@@ -589,7 +600,7 @@ func GetTestnetConfig() *ListenerConfig {
 			{
 				ChainID: "84532",
 				Name:    "Base Sepolia",
-				RPCURL:  config.GetChainRPCUrl(true, "84532"),
+				RPCURL:  config.GetAttestationChainRPCUrl("84532"),
 				Enabled: true,
 			},
 		},
@@ -616,7 +627,7 @@ func GetMainnetConfig() *ListenerConfig {
 			{
 				ChainID: "8453",
 				Name:    "Base",
-				RPCURL:  config.GetChainRPCUrl(true, "8453"),
+				RPCURL:  config.GetAttestationChainRPCUrl("8453"),
 				Enabled: true,
 			},
 		},
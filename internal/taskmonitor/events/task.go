@@ -8,9 +8,13 @@ import (
 	"strconv"
 	"time"
 
+	dbserverTypes "github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/clients/notify"
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/metrics"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/tasks"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
 // ProcessTaskEvent processes task-related events
@@ -50,6 +54,7 @@ func (h *TaskEventHandler) ProcessTaskEvent(event *ChainEvent) {
 				h.logger.Errorf("Failed to fetch IPFS data: %v", err)
 				return
 			}
+			h.trackIPFSPin(ipfsHash, ipfsData, taskData.TaskDefinitionID)
 
 			taskOpxCostFloat, _ := ipfsData.ActionData.TotalFee.Float64()
 			taskOpxCostFloat = taskOpxCostFloat / 1e18
@@ -94,6 +99,11 @@ func (h *TaskEventHandler) ProcessTaskEvent(event *ChainEvent) {
 				}
 			}
 
+			// Let the workflow run the task's job is part of go on
+			if h.completions != nil {
+				go h.reportTaskCompleted(*taskData, dbserverTypes.NewTaskOutput(*ipfsData.ActionData))
+			}
+
 			// Update keeper points in database
 			if err := h.db.UpdateKeeperPointsInDatabase(*taskData); err != nil {
 				h.logger.Errorf("Failed to update keeper points in database: %v", err)
@@ -131,6 +141,66 @@ func (h *TaskEventHandler) ProcessTaskEvent(event *ChainEvent) {
 	}
 }
 
+// completionReportRetry retries reporting a completed task for several minutes, so a workflow
+// run goes on when the DBServer was briefly unavailable. The DBServer ignores a task reported
+// again once its node finished.
+var completionReportRetry = &retry.RetryConfig{
+	MaxRetries:      8,
+	InitialDelay:    2 * time.Second,
+	MaxDelay:        2 * time.Minute,
+	BackoffFactor:   2.0,
+	JitterFactor:    0.2,
+	LogRetryAttempt: true,
+}
+
+// reportTaskCompleted reports a completed task to the DBServer, tasks of jobs that are not
+// part of a workflow run are ignored there. Failed reports are retried with backoff.
+func (h *TaskEventHandler) reportTaskCompleted(taskData types.TaskSubmissionData, output []interface{}) {
+	completedAt := time.Now()
+	err := retry.RetryFunc(context.Background(), func() error {
+		jobID, err := h.db.GetJobIDByTaskID(taskData.TaskID)
+		if err != nil {
+			return fmt.Errorf("failed to get job ID: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return h.completions.ReportTaskCompleted(ctx, dbserverTypes.TaskCompletedRequest{
+			TaskID:      taskData.TaskID,
+			JobID:       jobID.String(),
+			IsAccepted:  taskData.IsAccepted,
+			Output:      output,
+			CompletedAt: completedAt,
+		})
+	}, completionReportRetry, h.logger)
+	if err != nil {
+		metrics.TaskCompletionReportsTotal.WithLabelValues("failure").Inc()
+		h.logger.Errorf("Failed to report completion of task %d: %v", taskData.TaskID, err)
+		return
+	}
+	metrics.TaskCompletionReportsTotal.WithLabelValues("success").Inc()
+}
+
+// trackIPFSPin records the task data CID for the retention policy
+func (h *TaskEventHandler) trackIPFSPin(cid string, ipfsData commonTypes.IPFSData, taskDefinitionID int) {
+	taskID := ipfsData.ActionData.TaskID
+	jobID, err := h.db.GetJobIDByTaskID(taskID)
+	if err != nil {
+		h.logger.Warnf("Failed to get job ID to track pin %s of task %d: %v", cid, taskID, err)
+		return
+	}
+	if err := h.db.TrackIPFSPin(types.IPFSPin{
+		CID:              cid,
+		TaskID:           taskID,
+		JobID:            jobID,
+		TaskDefinitionID: taskDefinitionID,
+		SizeBytes:        ipfsData.StoredSize,
+		PinnedAt:         time.Now().UTC(),
+	}); err != nil {
+		h.logger.Warnf("Failed to track IPFS pin: %v", err)
+	}
+}
+
 // moveTaskToCompleted moves a task from dispatched to completed stream
 func (h *TaskEventHandler) moveTaskToCompleted(taskID int64) error {
 	h.logger.Info("Moving task to completed stream", "task_id", taskID)
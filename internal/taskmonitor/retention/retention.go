@@ -0,0 +1,261 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/ipfs"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+const customScriptTaskDefinitionID = 7
+
+// Store is where pins and the state deciding their retention are read from,
+// implemented by the taskmonitor database client
+type Store interface {
+	ListIPFSPins(afterCID string, limit int) ([]types.IPFSPin, error)
+	DeleteIPFSPin(cid string) error
+	GetJobRetentionState(taskDefinitionID int, jobID *big.Int) (types.JobRetentionState, error)
+	GetExecutionRetentionState(taskID int64) (*types.ExecutionRetentionState, error)
+}
+
+// Policy decides how long task data stays pinned
+type Policy struct {
+	// RetentionPeriod is how long task data is kept after its job completed
+	RetentionPeriod time.Duration
+	// Interval is the time between two garbage collection runs
+	Interval time.Duration
+	// BatchSize is the number of pins read and unpinned at a time
+	BatchSize int
+}
+
+// DefaultPolicy keeps task data for 30 days after job completion
+func DefaultPolicy() Policy {
+	return Policy{
+		RetentionPeriod: 30 * 24 * time.Hour,
+		Interval:        time.Hour,
+		BatchSize:       100,
+	}
+}
+
+// Decision is the outcome of evaluating a pin against the policy
+type Decision struct {
+	Expired bool
+	Reason  string
+}
+
+// Report summarises a garbage collection run
+type Report struct {
+	StartedAt      time.Time `json:"started_at"`
+	Duration       string    `json:"duration"`
+	Scanned        int       `json:"scanned"`
+	Kept           int       `json:"kept"`
+	Unpinned       int       `json:"unpinned"`
+	Failed         int       `json:"failed"`
+	ReclaimedBytes int64     `json:"reclaimed_bytes"`
+}
+
+// Manager unpins task data the policy no longer requires. Data of a custom script execution
+// that was challenged or slashed is kept forever, as evidence for the dispute.
+type Manager struct {
+	logger     logging.Logger
+	store      Store
+	ipfsClient ipfs.IPFSClient
+	policy     Policy
+	now        func() time.Time
+
+	mu         sync.RWMutex
+	lastReport *Report
+}
+
+// NewManager creates a retention manager, filling unset policy fields with the defaults
+func NewManager(logger logging.Logger, store Store, ipfsClient ipfs.IPFSClient, policy Policy) *Manager {
+	defaults := DefaultPolicy()
+	if policy.RetentionPeriod <= 0 {
+		policy.RetentionPeriod = defaults.RetentionPeriod
+	}
+	if policy.Interval <= 0 {
+		policy.Interval = defaults.Interval
+	}
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = defaults.BatchSize
+	}
+	return &Manager{
+		logger:     logger.With("component", "ipfs_retention"),
+		store:      store,
+		ipfsClient: ipfsClient,
+		policy:     policy,
+		now:        time.Now,
+	}
+}
+
+// Start runs garbage collection every policy interval until ctx is cancelled
+func (m *Manager) Start(ctx context.Context) {
+	m.logger.Info("Starting IPFS retention worker",
+		"retention_period", m.policy.RetentionPeriod,
+		"interval", m.policy.Interval)
+
+	ticker := time.NewTicker(m.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("IPFS retention worker stopping")
+			return
+		case <-ticker.C:
+			if _, err := m.RunOnce(ctx); err != nil {
+				m.logger.Error("IPFS garbage collection failed", "error", err)
+			}
+		}
+	}
+}
+
+// RunOnce walks every tracked pin, unpinning the expired ones a batch at a time
+func (m *Manager) RunOnce(ctx context.Context) (Report, error) {
+	report := Report{StartedAt: m.now()}
+	jobStates := make(map[string]types.JobRetentionState)
+
+	afterCID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return m.finish(report), err
+		}
+
+		pins, err := m.store.ListIPFSPins(afterCID, m.policy.BatchSize)
+		if err != nil {
+			return m.finish(report), fmt.Errorf("failed to list pins: %w", err)
+		}
+
+		var expired []types.IPFSPin
+		for _, pin := range pins {
+			report.Scanned++
+			decision, err := m.evaluate(pin, report.StartedAt, jobStates)
+			if err != nil {
+				m.logger.Warn("Failed to evaluate pin", "cid", pin.CID, "task_id", pin.TaskID, "error", err)
+				report.Failed++
+				metrics.IPFSUnpinFailuresTotal.Inc()
+				continue
+			}
+			if !decision.Expired {
+				report.Kept++
+				continue
+			}
+			expired = append(expired, pin)
+		}
+		m.unpin(ctx, expired, &report)
+
+		if len(pins) < m.policy.BatchSize {
+			break
+		}
+		afterCID = pins[len(pins)-1].CID
+	}
+
+	report = m.finish(report)
+	m.logger.Info("IPFS garbage collection completed",
+		"scanned", report.Scanned,
+		"unpinned", report.Unpinned,
+		"failed", report.Failed,
+		"reclaimed_bytes", report.ReclaimedBytes)
+	return report, nil
+}
+
+// Evaluate decides whether pin has outlived the policy at now
+func (m *Manager) Evaluate(pin types.IPFSPin, now time.Time) (Decision, error) {
+	return m.evaluate(pin, now, make(map[string]types.JobRetentionState))
+}
+
+// LastReport returns the report of the latest run, nil before the first one
+func (m *Manager) LastReport() *Report {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastReport
+}
+
+// evaluate is Evaluate reading job states through a cache shared by one run
+func (m *Manager) evaluate(pin types.IPFSPin, now time.Time, jobStates map[string]types.JobRetentionState) (Decision, error) {
+	if pin.TaskDefinitionID == customScriptTaskDefinitionID {
+		execution, err := m.store.GetExecutionRetentionState(pin.TaskID)
+		if err != nil {
+			return Decision{}, err
+		}
+		if execution != nil {
+			if execution.IsChallenged || execution.VerificationStatus == "challenged" || execution.VerificationStatus == "slashed" {
+				return Decision{Reason: "execution was challenged"}, nil
+			}
+			if now.Before(execution.ChallengeDeadline) {
+				return Decision{Reason: "challenge period is open"}, nil
+			}
+		}
+	}
+
+	if pin.JobID == nil {
+		return Decision{}, fmt.Errorf("pin %s has no job ID", pin.CID)
+	}
+	cacheKey := fmt.Sprintf("%d:%s", pin.TaskDefinitionID, pin.JobID)
+	job, ok := jobStates[cacheKey]
+	if !ok {
+		var err error
+		job, err = m.store.GetJobRetentionState(pin.TaskDefinitionID, pin.JobID)
+		if err != nil {
+			return Decision{}, err
+		}
+		jobStates[cacheKey] = job
+	}
+
+	completedAt, completed := jobCompletedAt(job, pin, now)
+	if !completed {
+		return Decision{Reason: "job is not completed"}, nil
+	}
+	if now.Before(completedAt.Add(m.policy.RetentionPeriod)) {
+		return Decision{Reason: "retention period is not over"}, nil
+	}
+	return Decision{Expired: true, Reason: "retention period is over"}, nil
+}
+
+// jobCompletedAt returns when the job completed: when it was last updated if it is marked
+// completed, else when it expired
+func jobCompletedAt(job types.JobRetentionState, pin types.IPFSPin, now time.Time) (time.Time, bool) {
+	if job.IsCompleted {
+		if job.UpdatedAt.IsZero() {
+			return pin.PinnedAt, true
+		}
+		return job.UpdatedAt, true
+	}
+	if !job.ExpirationTime.IsZero() && job.ExpirationTime.Before(now) {
+		return job.ExpirationTime, true
+	}
+	return time.Time{}, false
+}
+
+// unpin deletes expired pins from storage, then stops tracking them
+func (m *Manager) unpin(ctx context.Context, pins []types.IPFSPin, report *Report) {
+	for _, pin := range pins {
+		if err := m.ipfsClient.Delete(ctx, pin.CID); err != nil {
+			m.logger.Warn("Failed to unpin task data", "cid", pin.CID, "task_id", pin.TaskID, "error", err)
+			report.Failed++
+			metrics.IPFSUnpinFailuresTotal.Inc()
+			continue
+		}
+		if err := m.store.DeleteIPFSPin(pin.CID); err != nil {
+			m.logger.Warn("Failed to stop tracking unpinned task data", "cid", pin.CID, "error", err)
+		}
+		report.Unpinned++
+		report.ReclaimedBytes += pin.SizeBytes
+		metrics.IPFSPinsUnpinnedTotal.Inc()
+		metrics.IPFSReclaimedBytesTotal.Add(float64(pin.SizeBytes))
+	}
+}
+
+func (m *Manager) finish(report Report) Report {
+	report.Duration = m.now().Sub(report.StartedAt).String()
+	m.mu.Lock()
+	m.lastReport = &report
+	m.mu.Unlock()
+	return report
+}
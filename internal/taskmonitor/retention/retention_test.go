@@ -0,0 +1,211 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/ipfs"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+var now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeStore keeps pins and job state in memory, listing pins in CID order
+type fakeStore struct {
+	pins       map[string]types.IPFSPin
+	jobs       map[string]types.JobRetentionState
+	executions map[int64]*types.ExecutionRetentionState
+	jobReads   int
+	listCalls  int
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		pins:       make(map[string]types.IPFSPin),
+		jobs:       make(map[string]types.JobRetentionState),
+		executions: make(map[int64]*types.ExecutionRetentionState),
+	}
+}
+
+func (s *fakeStore) ListIPFSPins(afterCID string, limit int) ([]types.IPFSPin, error) {
+	s.listCalls++
+	cids := make([]string, 0, len(s.pins))
+	for cid := range s.pins {
+		if cid > afterCID {
+			cids = append(cids, cid)
+		}
+	}
+	sort.Strings(cids)
+	if len(cids) > limit {
+		cids = cids[:limit]
+	}
+	pins := make([]types.IPFSPin, 0, len(cids))
+	for _, cid := range cids {
+		pins = append(pins, s.pins[cid])
+	}
+	return pins, nil
+}
+
+func (s *fakeStore) DeleteIPFSPin(cid string) error {
+	delete(s.pins, cid)
+	return nil
+}
+
+func (s *fakeStore) GetJobRetentionState(taskDefinitionID int, jobID *big.Int) (types.JobRetentionState, error) {
+	s.jobReads++
+	state, ok := s.jobs[jobID.String()]
+	if !ok {
+		return types.JobRetentionState{}, fmt.Errorf("job not found for job ID %s", jobID)
+	}
+	return state, nil
+}
+
+func (s *fakeStore) GetExecutionRetentionState(taskID int64) (*types.ExecutionRetentionState, error) {
+	return s.executions[taskID], nil
+}
+
+func (s *fakeStore) addPin(cid string, taskID, jobID int64, taskDefinitionID int, size int64) {
+	s.pins[cid] = types.IPFSPin{
+		CID:              cid,
+		TaskID:           taskID,
+		JobID:            big.NewInt(jobID),
+		TaskDefinitionID: taskDefinitionID,
+		SizeBytes:        size,
+		PinnedAt:         now.Add(-90 * 24 * time.Hour),
+	}
+}
+
+// fakeIPFS records deleted CIDs, failing for the ones in failing
+type fakeIPFS struct {
+	deleted []string
+	failing map[string]bool
+}
+
+func (f *fakeIPFS) Upload(context.Context, string, []byte) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (f *fakeIPFS) Fetch(context.Context, string) (commonTypes.IPFSData, error) {
+	return commonTypes.IPFSData{}, fmt.Errorf("not implemented")
+}
+
+func (f *fakeIPFS) Delete(_ context.Context, cid string) error {
+	if f.failing[cid] {
+		return fmt.Errorf("gateway timeout")
+	}
+	f.deleted = append(f.deleted, cid)
+	return nil
+}
+
+func (f *fakeIPFS) ListFiles(context.Context) ([]ipfs.PinataFile, error) {
+	return nil, nil
+}
+
+func (f *fakeIPFS) Close() error {
+	return nil
+}
+
+func newTestManager(store Store, ipfsClient ipfs.IPFSClient, batchSize int) *Manager {
+	m := NewManager(logging.NewNoOpLogger(), store, ipfsClient, Policy{
+		RetentionPeriod: 30 * 24 * time.Hour,
+		BatchSize:       batchSize,
+	})
+	m.now = func() time.Time { return now }
+	return m
+}
+
+func TestEvaluate(t *testing.T) {
+	store := newFakeStore()
+	store.jobs["1"] = types.JobRetentionState{IsCompleted: true, UpdatedAt: now.Add(-31 * 24 * time.Hour)}
+	store.jobs["2"] = types.JobRetentionState{IsCompleted: true, UpdatedAt: now.Add(-29 * 24 * time.Hour)}
+	store.jobs["3"] = types.JobRetentionState{ExpirationTime: now.Add(24 * time.Hour)}
+	store.jobs["4"] = types.JobRetentionState{ExpirationTime: now.Add(-40 * 24 * time.Hour)}
+	store.jobs["7"] = types.JobRetentionState{IsCompleted: true, UpdatedAt: now.Add(-60 * 24 * time.Hour)}
+	store.executions[71] = &types.ExecutionRetentionState{VerificationStatus: "slashed"}
+	store.executions[72] = &types.ExecutionRetentionState{VerificationStatus: "pending", ChallengeDeadline: now.Add(time.Hour)}
+	store.executions[73] = &types.ExecutionRetentionState{VerificationStatus: "verified", ChallengeDeadline: now.Add(-time.Hour)}
+	store.executions[74] = &types.ExecutionRetentionState{VerificationStatus: "verified", IsChallenged: true}
+	m := newTestManager(store, &fakeIPFS{}, 10)
+
+	tests := []struct {
+		name    string
+		pin     types.IPFSPin
+		expired bool
+		reason  string
+	}{
+		{"completed past retention", types.IPFSPin{TaskID: 10, JobID: big.NewInt(1), TaskDefinitionID: 1}, true, "retention period is over"},
+		{"completed within retention", types.IPFSPin{TaskID: 20, JobID: big.NewInt(2), TaskDefinitionID: 3}, false, "retention period is not over"},
+		{"running job", types.IPFSPin{TaskID: 30, JobID: big.NewInt(3), TaskDefinitionID: 5}, false, "job is not completed"},
+		{"expired job", types.IPFSPin{TaskID: 40, JobID: big.NewInt(4), TaskDefinitionID: 2}, true, "retention period is over"},
+		{"slashed execution", types.IPFSPin{TaskID: 71, JobID: big.NewInt(7), TaskDefinitionID: 7}, false, "execution was challenged"},
+		{"open challenge period", types.IPFSPin{TaskID: 72, JobID: big.NewInt(7), TaskDefinitionID: 7}, false, "challenge period is open"},
+		{"verified execution", types.IPFSPin{TaskID: 73, JobID: big.NewInt(7), TaskDefinitionID: 7}, true, "retention period is over"},
+		{"challenged execution", types.IPFSPin{TaskID: 74, JobID: big.NewInt(7), TaskDefinitionID: 7}, false, "execution was challenged"},
+		{"custom task without execution", types.IPFSPin{TaskID: 75, JobID: big.NewInt(7), TaskDefinitionID: 7}, true, "retention period is over"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := m.Evaluate(tt.pin, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expired, decision.Expired)
+			assert.Equal(t, tt.reason, decision.Reason)
+		})
+	}
+
+	_, err := m.Evaluate(types.IPFSPin{TaskID: 80, JobID: big.NewInt(8), TaskDefinitionID: 1}, now)
+	assert.ErrorContains(t, err, "job not found")
+}
+
+func TestRunOnce_UnpinsExpiredInBatches(t *testing.T) {
+	store := newFakeStore()
+	store.jobs["1"] = types.JobRetentionState{IsCompleted: true, UpdatedAt: now.Add(-31 * 24 * time.Hour)}
+	store.jobs["2"] = types.JobRetentionState{IsCompleted: false}
+	store.addPin("cid-a", 1, 1, 1, 100)
+	store.addPin("cid-b", 2, 1, 1, 200)
+	store.addPin("cid-c", 3, 2, 1, 400)
+	store.addPin("cid-d", 4, 1, 1, 800)
+	store.addPin("cid-e", 5, 1, 1, 1600)
+	ipfsClient := &fakeIPFS{failing: map[string]bool{"cid-d": true}}
+	m := newTestManager(store, ipfsClient, 2)
+
+	report, err := m.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, report.Scanned)
+	assert.Equal(t, 3, report.Unpinned)
+	assert.Equal(t, 1, report.Kept)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, int64(1900), report.ReclaimedBytes)
+	assert.Equal(t, []string{"cid-a", "cid-b", "cid-e"}, ipfsClient.deleted)
+	assert.Equal(t, 3, store.listCalls)
+	assert.Equal(t, 2, store.jobReads, "job state is read once per job")
+
+	// Pins that failed to unpin or are kept stay tracked for the next run
+	assert.Len(t, store.pins, 2)
+	assert.Contains(t, store.pins, "cid-c")
+	assert.Contains(t, store.pins, "cid-d")
+	assert.Equal(t, &report, m.LastReport())
+}
+
+func TestRunOnce_CancelledContext(t *testing.T) {
+	store := newFakeStore()
+	store.addPin("cid-a", 1, 1, 1, 100)
+	m := newTestManager(store, &fakeIPFS{}, 10)
+	assert.Nil(t, m.LastReport())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := m.RunOnce(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, store.pins, 1)
+}
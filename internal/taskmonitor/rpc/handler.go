@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/config"
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/tasks"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/types"
 	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/stream"
+	commonTypes "github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
 // TaskMonitorHandler implements the generic RPC handler interface
@@ -21,6 +26,7 @@ type TaskMonitorHandler struct {
 // TaskMonitorInterface defines the interface for task monitor operations
 type TaskMonitorInterface interface {
 	ReportTaskError(ctx context.Context, req *types.ReportTaskErrorRequest) (*types.ReportTaskErrorResponse, error)
+	TaskEvents() *stream.Broadcaster[tasks.TaskEvent]
 }
 
 // NewTaskMonitorHandler creates a new RPC handler
@@ -51,7 +57,7 @@ func (h *TaskMonitorHandler) Handle(ctx context.Context, method string, request
 		}
 
 		// Validate keeper signature
-		if err := h.validateSignature(req); err != nil {
+		if err := validateSignature(req); err != nil {
 			h.logger.Error("Invalid keeper signature for task error report",
 				"task_id", req.TaskID,
 				"keeper_address", req.KeeperAddress,
@@ -101,27 +107,26 @@ func (h *TaskMonitorHandler) convertMapToRequest(reqMap map[string]interface{})
 }
 
 // validateSignature validates the keeper's signature for the error report
-func (h *TaskMonitorHandler) validateSignature(req *types.ReportTaskErrorRequest) error {
+func validateSignature(req *types.ReportTaskErrorRequest) error {
 	// Create a struct for signing (without signature field)
-	signData := struct {
-		TaskID        int64  `json:"task_id"`
-		KeeperAddress string `json:"keeper_address"`
-		Error         string `json:"error"`
-	}{
+	signData := commonTypes.TaskErrorReport{
 		TaskID:        req.TaskID,
 		KeeperAddress: req.KeeperAddress,
 		Error:         req.Error,
 	}
 
-	// Verify signature using JSON verification (same as other services)
-	isValid, err := cryptography.VerifySignatureFromJSON(signData, req.Signature, req.KeeperAddress)
+	// Accepts EIP-712 signatures and, unless turned off, legacy JSON signatures
+	format, err := cryptography.VerifyStructured(signData, 0, req.Signature, req.KeeperAddress, config.GetAcceptLegacySignatures())
 	if err != nil {
 		return fmt.Errorf("signature verification failed: %w", err)
 	}
 
-	if !isValid {
+	if format == "" {
 		return fmt.Errorf("invalid signature for keeper %s", req.KeeperAddress)
 	}
+	if format == cryptography.SignatureFormatJSON {
+		metrics.LegacySignaturesTotal.WithLabelValues("task_error_report").Inc()
+	}
 
 	return nil
 }
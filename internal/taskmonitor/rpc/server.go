@@ -2,16 +2,35 @@ package rpc
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
 	"strconv"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 	rpcserver "github.com/trigg3rX/triggerx-backend/pkg/rpc/server"
 )
 
+// MethodPolicy lists the services allowed to call each method of the task monitor. Keepers
+// report errors through the public ReportTaskError, their reports carry their signature.
+var MethodPolicy = map[string][]string{
+	"report-task-error": {"*"},
+	rpcproto.TaskMonitorService_WatchTasks_FullMethodName: {"*"},
+	rpcproto.GenericService_GetMethods_FullMethodName:     {"*"},
+}
+
+// NewAuthConfig returns the auth of the task monitor, verifying service tokens with keys
+func NewAuthConfig(keys []ed25519.PublicKey) *rpcserver.AuthConfig {
+	auth := rpcserver.NewServiceAuthConfig(rpcpkg.ServiceTaskMonitor, keys, MethodPolicy)
+	auth.PublicMethods = []string{rpcproto.TaskMonitorService_ReportTaskError_FullMethodName}
+	return auth
+}
+
 // StartRPCServer creates and starts the Task Monitor gRPC server using the generic approach.
-// It registers the task monitor handler with the generic RPC server.
-func StartRPCServer(ctx context.Context, logger logging.Logger, monitor TaskMonitorInterface, addr string, portStr string) (*rpcserver.Server, error) {
+// It registers the task monitor handler with the generic RPC server and authenticates
+// callers with auth unless it is nil.
+func StartRPCServer(ctx context.Context, logger logging.Logger, monitor TaskMonitorInterface, addr string, portStr string, auth *rpcserver.AuthConfig) (*rpcserver.Server, error) {
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid port %q: %w", portStr, err)
@@ -22,15 +41,20 @@ func StartRPCServer(ctx context.Context, logger logging.Logger, monitor TaskMoni
 		Version: "1.0.0",
 		Address: addr,
 		Port:    port,
+		Auth:    auth,
 	}, logger)
 
 	// Add useful middleware (logging)
 	srv.AddInterceptor(rpcserver.LoggingInterceptor(logger))
+	srv.AddStreamInterceptor(rpcserver.LoggingStreamInterceptor(logger))
 
 	// Create and register the generic RPC handler
 	handler := NewTaskMonitorHandler(logger, monitor)
 	srv.RegisterHandler("TaskMonitor", handler)
 
+	// Register the typed service alongside the generic handler
+	rpcproto.RegisterTaskMonitorServiceServer(srv, NewTaskMonitorService(logger, monitor))
+
 	if err := srv.Start(ctx); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/tasks"
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/stream"
+)
+
+// TaskMonitorService implements the typed TaskMonitorService gRPC API
+type TaskMonitorService struct {
+	rpcproto.UnimplementedTaskMonitorServiceServer
+	logger  logging.Logger
+	monitor TaskMonitorInterface
+}
+
+// NewTaskMonitorService creates the typed gRPC service
+func NewTaskMonitorService(logger logging.Logger, monitor TaskMonitorInterface) *TaskMonitorService {
+	return &TaskMonitorService{
+		logger:  logger,
+		monitor: monitor,
+	}
+}
+
+// ReportTaskError records a task error after checking the keeper's signature
+func (s *TaskMonitorService) ReportTaskError(ctx context.Context, req *rpcproto.ReportTaskErrorRequest) (*rpcproto.ReportTaskErrorResponse, error) {
+	report := &types.ReportTaskErrorRequest{
+		TaskID:        req.GetTaskId(),
+		KeeperAddress: req.GetKeeperAddress(),
+		Error:         req.GetError(),
+		Signature:     req.GetSignature(),
+	}
+
+	if err := validateSignature(report); err != nil {
+		s.logger.Error("Invalid keeper signature for task error report",
+			"task_id", report.TaskID,
+			"keeper_address", report.KeeperAddress,
+			"error", err)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid signature: %v", err)
+	}
+
+	resp, err := s.monitor.ReportTaskError(ctx, report)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to report task error: %v", err)
+	}
+	return &rpcproto.ReportTaskErrorResponse{
+		Success: resp.Success,
+		Message: resp.Message,
+	}, nil
+}
+
+// WatchTasks streams task status changes from the requested sequence
+func (s *TaskMonitorService) WatchTasks(req *rpcproto.WatchTasksRequest, ss grpc.ServerStreamingServer[rpcproto.TaskEvent]) error {
+	events := s.monitor.TaskEvents()
+	if events == nil {
+		return status.Error(codes.Unavailable, "task events are not available")
+	}
+
+	jobs := make(map[string]bool, len(req.GetJobIds()))
+	for _, jobID := range req.GetJobIds() {
+		jobs[jobID] = true
+	}
+
+	return stream.Serve(ss, events, req.GetFromSequence(), func(event stream.Event[tasks.TaskEvent]) error {
+		jobID := ""
+		if event.Value.JobID != nil {
+			jobID = event.Value.JobID.String()
+		}
+		if len(jobs) > 0 && !jobs[jobID] {
+			return nil
+		}
+		return ss.Send(&rpcproto.TaskEvent{
+			Sequence:    event.Sequence,
+			TaskId:      event.Value.TaskID,
+			JobId:       jobID,
+			Status:      event.Value.Status,
+			PerformerId: event.Value.PerformerID,
+			Error:       event.Value.Error,
+			OccurredAt:  timestamppb.New(event.Value.OccurredAt),
+		})
+	})
+}
@@ -11,13 +11,16 @@ import (
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/config"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/events"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/metrics"
+	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/retention"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/tasks"
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/client/dbserver"
 	redisClient "github.com/trigg3rX/triggerx-backend/pkg/client/redis"
 	dbClient "github.com/trigg3rX/triggerx-backend/pkg/database"
 	"github.com/trigg3rX/triggerx-backend/pkg/ipfs"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/stream"
 )
 
 const (
@@ -38,6 +41,7 @@ type TaskManager struct {
 	shutdownWg          sync.WaitGroup
 	startTime           time.Time
 	dbClient            *database.DatabaseClient
+	retentionManager    *retention.Manager
 	rpcServer           interface {
 		Stop(ctx context.Context) error
 	}
@@ -84,8 +88,7 @@ func NewTaskManager(logger logging.Logger) (*TaskManager, error) {
 	databaseClient := database.NewDatabaseClient(logger, dbConn)
 
 	// Initialize IPFS client
-	ipfsCfg := ipfs.NewConfig(config.GetPinataHost(), config.GetPinataJWT())
-	ipfsClient, err := ipfs.NewClient(ipfsCfg, logger)
+	ipfsClient, err := ipfs.NewStorage(config.GetIPFSStorageConfig(), logger)
 	if err != nil {
 		cancel()
 		return nil, fmt.Errorf("failed to initialize IPFS client: %w", err)
@@ -103,9 +106,24 @@ func NewTaskManager(logger logging.Logger) (*TaskManager, error) {
 		return nil, fmt.Errorf("failed to create task stream manager: %w", err)
 	}
 
+	// Initialize DBServer client task completions of workflow runs are reported with
+	var completions events.TaskCompletionReporter
+	if config.GetDBServerURL() != "" {
+		dbServerClient, err := dbserver.NewDBServerClient(logger, config.GetDBServerURL())
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to initialize dbserver client: %w", err)
+		}
+		if err := dbServerClient.SetCredentials(dbserver.NewServiceCredentials("task-monitor", config.GetServiceTokenKey(), config.GetRPCTLSConfig())); err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to configure dbserver client credentials: %w", err)
+		}
+		completions = dbServerClient
+	}
+
 	// Initialize event listener
-	eventListener := events.NewContractEventListener(logger, events.GetMainnetConfig(), databaseClient, ipfsClient, taskStreamManager)
-	testEventListener := events.NewContractEventListener(logger, events.GetTestnetConfig(), databaseClient, ipfsClient, taskStreamManager)
+	eventListener := events.NewContractEventListener(logger, events.GetMainnetConfig(), databaseClient, ipfsClient, taskStreamManager, completions)
+	testEventListener := events.NewContractEventListener(logger, events.GetTestnetConfig(), databaseClient, ipfsClient, taskStreamManager, completions)
 
 	tm := &TaskManager{
 		logger:              logger,
@@ -120,6 +138,14 @@ func NewTaskManager(logger logging.Logger) (*TaskManager, error) {
 		dbClient:            databaseClient,
 	}
 
+	if config.IsIPFSRetentionEnabled() {
+		tm.retentionManager = retention.NewManager(logger, databaseClient, ipfsClient, retention.Policy{
+			RetentionPeriod: config.GetIPFSRetentionPeriod(),
+			Interval:        config.GetIPFSRetentionInterval(),
+			BatchSize:       config.GetIPFSRetentionBatchSize(),
+		})
+	}
+
 	logger.Info("TaskManager initialized successfully",
 		"metrics_update_interval", config.GetMetricsUpdateInterval())
 
@@ -160,6 +186,14 @@ func (tm *TaskManager) Initialize() error {
 		tm.taskStreamManager.StartTimeoutWorker(tm.ctx)
 	}()
 
+	if tm.retentionManager != nil {
+		tm.shutdownWg.Add(1)
+		go func() {
+			defer tm.shutdownWg.Done()
+			tm.retentionManager.Start(tm.ctx)
+		}()
+	}
+
 	tm.logger.Info("TaskManager initialization completed successfully")
 	return nil
 }
@@ -196,6 +230,11 @@ func (tm *TaskManager) ReportTaskError(ctx context.Context, req *types.ReportTas
 	}, nil
 }
 
+// TaskEvents returns the broadcaster of task status changes served by WatchTasks
+func (tm *TaskManager) TaskEvents() *stream.Broadcaster[tasks.TaskEvent] {
+	return tm.taskStreamManager.Events()
+}
+
 // SetRPCServer sets the RPC server for graceful shutdown
 func (tm *TaskManager) SetRPCServer(server interface {
 	Stop(ctx context.Context) error
@@ -290,6 +329,11 @@ func (tm *TaskManager) HealthCheck() map[string]interface{} {
 		healthStatus["task_streams"] = tm.taskStreamManager.GetStreamInfo()
 	}
 
+	// Get the latest IPFS garbage collection
+	if tm.retentionManager != nil {
+		healthStatus["ipfs_retention"] = tm.retentionManager.LastReport()
+	}
+
 	return healthStatus
 }
 
@@ -13,6 +13,7 @@ import (
 	"github.com/trigg3rX/triggerx-backend/internal/taskmonitor/metrics"
 	redisClient "github.com/trigg3rX/triggerx-backend/pkg/client/redis"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/stream"
 )
 
 type TaskStreamManager struct {
@@ -25,6 +26,7 @@ type TaskStreamManager struct {
 	startTime      time.Time
 	taskIndex      *TaskIndexManager
 	expirationManager *ExpirationManager
+	events         *stream.Broadcaster[TaskEvent]
 }
 
 func NewTaskStreamManager(redisClient redisClient.RedisClientInterface, dbClient *database.DatabaseClient, logger logging.Logger) (*TaskStreamManager, error) {
@@ -35,6 +37,7 @@ func NewTaskStreamManager(redisClient redisClient.RedisClientInterface, dbClient
 		logger:         logger,
 		consumerGroups: make(map[string]bool),
 		startTime:      time.Now(),
+		events:         stream.NewBroadcaster[TaskEvent](TaskEventHistorySize, TaskEventBufferSize),
 	}
 
 	// Initialize the task index manager
@@ -320,6 +323,8 @@ func (tsm *TaskStreamManager) AddTaskToStream(ctx context.Context, stream string
 func (tsm *TaskStreamManager) Close() error {
 	tsm.logger.Info("Closing TaskStreamManager")
 
+	tsm.events.Close()
+
 	err := tsm.redisClient.Close()
 	if err != nil {
 		tsm.logger.Error("Failed to close Redis client", "error", err)
@@ -330,6 +335,11 @@ func (tsm *TaskStreamManager) Close() error {
 	return nil
 }
 
+// Events returns the broadcaster of task status changes
+func (tsm *TaskStreamManager) Events() *stream.Broadcaster[TaskEvent] {
+	return tsm.events
+}
+
 // startStreamHealthMonitor monitors the health of Redis streams
 func (tsm *TaskStreamManager) StartStreamHealthMonitor(ctx context.Context) {
 	tsm.logger.Info("Starting stream health monitor")
@@ -226,6 +226,7 @@ func (tsm *TaskStreamManager) addTaskToStream(ctx context.Context, stream string
 	}
 
 	metrics.TasksAddedToStreamTotal.WithLabelValues(stream, "success").Inc()
+	tsm.publishTaskEvent(stream, task)
 	tsm.logger.Debug("Task added to stream successfully",
 		"task_id", task.SendTaskDataToKeeper.TaskID[0],
 		"stream", stream,
@@ -236,3 +237,29 @@ func (tsm *TaskStreamManager) addTaskToStream(ctx context.Context, stream string
 
 	return nil
 }
+
+// publishTaskEvent tells the watchers about a task reaching a final or retry stream
+func (tsm *TaskStreamManager) publishTaskEvent(stream string, task *TaskStreamData) {
+	var status string
+	switch stream {
+	case StreamTaskCompleted:
+		status = "completed"
+	case StreamTaskFailed:
+		status = "failed"
+	case StreamTaskRetry:
+		status = "retry"
+	default:
+		return
+	}
+
+	for _, taskID := range task.SendTaskDataToKeeper.TaskID {
+		tsm.events.Publish(TaskEvent{
+			TaskID:      taskID,
+			JobID:       task.JobID,
+			Status:      status,
+			PerformerID: task.SendTaskDataToKeeper.PerformerData.OperatorID,
+			Error:       task.LastError,
+			OccurredAt:  time.Now(),
+		})
+	}
+}
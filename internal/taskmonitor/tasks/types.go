@@ -22,6 +22,10 @@ const (
 
 	// Retry Configuration
 	MaxRetryAttempts = 3
+
+	// Task events retained for watchers resuming a broken stream, and buffered per watcher
+	TaskEventHistorySize = 4096
+	TaskEventBufferSize  = 256
 )
 
 // TaskStreamData represents task information for Redis-managed task streams
@@ -43,6 +47,16 @@ type TaskStreamData struct {
 	LastError    string     `json:"last_error,omitempty"`
 }
 
+// TaskEvent is a change of a task's status, published to the watchers of the task monitor
+type TaskEvent struct {
+	TaskID      int64
+	JobID       *big.Int
+	Status      string // completed, failed, retry
+	PerformerID int64
+	Error       string
+	OccurredAt  time.Time
+}
+
 // TaskStatusUpdate represents status updates from performers
 type TaskStatusUpdate struct {
 	TaskID      int64     `json:"task_id"`
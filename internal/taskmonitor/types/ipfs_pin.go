@@ -0,0 +1,30 @@
+package types
+
+import (
+	"math/big"
+	"time"
+)
+
+// IPFSPin is task data pinned to IPFS, tracked so it can be unpinned once it is no longer needed
+type IPFSPin struct {
+	CID              string
+	TaskID           int64
+	JobID            *big.Int
+	TaskDefinitionID int
+	SizeBytes        int64
+	PinnedAt         time.Time
+}
+
+// JobRetentionState is the part of a job the IPFS retention policy looks at
+type JobRetentionState struct {
+	IsCompleted    bool
+	UpdatedAt      time.Time
+	ExpirationTime time.Time
+}
+
+// ExecutionRetentionState is the verification state of a custom script execution
+type ExecutionRetentionState struct {
+	VerificationStatus string
+	IsChallenged       bool
+	ChallengeDeadline  time.Time
+}
@@ -25,7 +25,7 @@ func (c *DBServerClient) CreateTask(ctx context.Context, createTaskData types.Cr
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.DoWithRetry(ctx, req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return -1, fmt.Errorf("failed to create task: %v", err)
 	}
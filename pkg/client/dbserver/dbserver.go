@@ -2,19 +2,42 @@ package dbserver
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	httppkg "github.com/trigg3rX/triggerx-backend/pkg/http"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
 )
 
+const (
+	// ServiceAudience is the audience of the service tokens the DBServer accepts
+	ServiceAudience = "dbserver"
+	// InternalScope is the scope service tokens need for internal endpoints
+	InternalScope = "internal"
+	// serviceTokenTTL is the lifetime of the service tokens signed by the client
+	serviceTokenTTL = 5 * time.Minute
+)
+
+// Credentials authenticate the client to the internal endpoints of the DBServer
+type Credentials struct {
+	// Token signs the service token presented on every request, nil to present none
+	Token *jwt.ServiceTokenCredentials
+	// TLS is the client certificate presented to the internal mutual TLS listener, used
+	// when the DBServer URL is https
+	TLS mtls.Config
+}
+
 // DBServerClient handles communication with the DBServer service
 type DBServerClient struct {
 	logger      logging.Logger
 	dbserverUrl string
 	httpClient  *httppkg.HTTPClient
+	token       *jwt.ServiceTokenCredentials
 }
 
 // NewDBServerClient creates a new instance of DBServerClient
@@ -38,6 +61,46 @@ func NewDBServerClient(logger logging.Logger, dbserverUrl string) (*DBServerClie
 	}, nil
 }
 
+// NewServiceCredentials returns the credentials of service, signing service tokens with key
+// when it is set and presenting the client certificate of tlsConfig when it is enabled
+func NewServiceCredentials(service string, key ed25519.PrivateKey, tlsConfig mtls.Config) Credentials {
+	credentials := Credentials{TLS: tlsConfig}
+	if key != nil {
+		credentials.Token = jwt.NewServiceTokenCredentials(jwt.NewServiceTokenSigner(key), service, ServiceAudience, []string{InternalScope}, serviceTokenTTL, false)
+	}
+	return credentials
+}
+
+// SetCredentials makes the client authenticate as a service, required by the internal
+// endpoints of the DBServer
+func (c *DBServerClient) SetCredentials(credentials Credentials) error {
+	if credentials.TLS.Enabled() && strings.HasPrefix(c.dbserverUrl, "https://") {
+		tlsConfig, err := mtls.ClientTLSConfig(credentials.TLS)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		transport, ok := c.httpClient.GetClient().Transport.(*http.Transport)
+		if !ok {
+			return fmt.Errorf("unexpected HTTP transport %T", c.httpClient.GetClient().Transport)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	c.token = credentials.Token
+	return nil
+}
+
+// do sends the request with the client's credentials
+func (c *DBServerClient) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.token != nil {
+		headers, err := c.token.GetRequestMetadata(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign service token: %w", err)
+		}
+		req.Header.Set("Authorization", headers["authorization"])
+	}
+	return c.httpClient.DoWithRetry(ctx, req)
+}
+
 // HealthCheck checks if the database server is healthy
 func (c *DBServerClient) HealthCheck() error {
 	url := fmt.Sprintf("%s/api/health", c.dbserverUrl)
@@ -50,7 +113,7 @@ func (c *DBServerClient) HealthCheck() error {
 		return fmt.Errorf("failed to create health check request: %v", err)
 	}
 
-	resp, err := c.httpClient.DoWithRetry(ctx, req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return fmt.Errorf("health check request failed: %v", err)
 	}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
@@ -23,10 +24,18 @@ func (c *DBServerClient) UpdateTaskExecutionData(ctx context.Context, taskExecut
 		return false, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	_, err = c.httpClient.DoWithRetry(ctx, req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return false, fmt.Errorf("failed to update task execution data: %v", err)
 	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	}
 
 	return true, nil
 }
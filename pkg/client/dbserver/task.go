@@ -0,0 +1,41 @@
+package dbserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/types"
+)
+
+// ReportTaskCompleted reports a task whose result was submitted on chain, so the workflow run
+// the task's job runs for can go on
+func (c *DBServerClient) ReportTaskCompleted(ctx context.Context, taskCompleted types.TaskCompletedRequest) error {
+	url := fmt.Sprintf("%s/api/tasks/%d/completed", c.dbserverUrl, taskCompleted.TaskID)
+
+	jsonPayload, err := json.Marshal(taskCompleted)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task completion: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to report task completion: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -19,7 +19,7 @@ func (c *DBServerClient) GetTimeBasedTasks(ctx context.Context) ([]types.Schedul
 		return nil, fmt.Errorf("failed to fetch time-based tasks: %v", err)
 	}
 
-	resp, err := c.httpClient.DoWithRetry(ctx, req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch time-based tasks: %v", err)
 	}
@@ -11,15 +11,19 @@ import (
 )
 
 func SignMessage(message string, privateKey string) (string, error) {
+	messageHash := crypto.Keccak256Hash([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+
+	return signHash(messageHash.Bytes(), privateKey)
+}
+
+// signHash signs a 32 byte digest, returning the signature with an Ethereum style recovery id
+func signHash(hash []byte, privateKey string) (string, error) {
 	privateKeyECDSA, err := crypto.HexToECDSA(privateKey)
 	if err != nil {
 		return "", fmt.Errorf("invalid private key: %w", err)
 	}
 
-	messageHash := crypto.Keccak256Hash([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
-	messageBytes := messageHash.Bytes()
-
-	signature, err := crypto.Sign(messageBytes, privateKeyECDSA)
+	signature, err := crypto.Sign(hash, privateKeyECDSA)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign message: %w", err)
 	}
@@ -55,8 +59,12 @@ func SignJSONMessage(jsonData interface{}, privateKey string) (string, error) {
 
 func VerifySignature(message string, signature string, signerAddress string) (bool, error) {
 	messageHash := crypto.Keccak256Hash([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
-	messageBytes := messageHash.Bytes()
 
+	return verifyHash(messageHash.Bytes(), signature, signerAddress)
+}
+
+// verifyHash checks that the signature over a 32 byte digest was made by signerAddress
+func verifyHash(hash []byte, signature string, signerAddress string) (bool, error) {
 	signatureBytes, err := hexutil.Decode(signature)
 	if err != nil {
 		return false, fmt.Errorf("invalid signature: %w", err)
@@ -70,7 +78,7 @@ func VerifySignature(message string, signature string, signerAddress string) (bo
 		signatureBytes[64] -= 27
 	}
 
-	pubKeyRaw, err := crypto.Ecrecover(messageBytes, signatureBytes)
+	pubKeyRaw, err := crypto.Ecrecover(hash, signatureBytes)
 	if err != nil {
 		return false, fmt.Errorf("failed to recover public key: %w", err)
 	}
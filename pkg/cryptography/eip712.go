@@ -0,0 +1,423 @@
+package cryptography
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-712 domain every TriggerX message is signed under. Bump the version whenever
+// the shape of a signed type changes, so old signatures stop verifying.
+const (
+	EIP712DomainName    = "TriggerX"
+	EIP712DomainVersion = "1"
+)
+
+// SignatureFormat selects how structured payloads are signed
+type SignatureFormat string
+
+const (
+	// SignatureFormatJSON is the legacy personal_sign over the lowercased JSON of the payload
+	SignatureFormatJSON SignatureFormat = "json"
+	// SignatureFormatEIP712 is an EIP-712 typed-data signature
+	SignatureFormatEIP712 SignatureFormat = "eip712"
+)
+
+// ParseSignatureFormat parses a configured signature format, empty meaning the legacy format
+func ParseSignatureFormat(format string) (SignatureFormat, error) {
+	switch SignatureFormat(strings.ToLower(strings.TrimSpace(format))) {
+	case SignatureFormatJSON, "":
+		return SignatureFormatJSON, nil
+	case SignatureFormatEIP712:
+		return SignatureFormatEIP712, nil
+	default:
+		return "", fmt.Errorf("unknown signature format: %s", format)
+	}
+}
+
+// NewTypedDataDomain returns the domain messages for chainID are signed under.
+// A zero chainID leaves the chain out, for messages that are not bound to one.
+func NewTypedDataDomain(chainID int64) apitypes.TypedDataDomain {
+	domain := apitypes.TypedDataDomain{
+		Name:    EIP712DomainName,
+		Version: EIP712DomainVersion,
+	}
+	if chainID != 0 {
+		domain.ChainId = math.NewHexOrDecimal256(chainID)
+	}
+	return domain
+}
+
+// NewTypedData derives the EIP-712 types of the Go struct v and encodes it as the message.
+//
+// Field names come from the json tag, fields tagged `json:"-"` or `eip712:"-"` are left out
+// and embedded structs are flattened. Go types map to EIP-712 types as follows:
+//   - string, bool, sized ints and []byte map to themselves (int and uint are 64 bit)
+//   - *big.Int and structs embedding only a *big.Int map to int256
+//   - time.Time maps to int256 unix seconds
+//   - floats map to their shortest decimal string
+//   - map[string]string maps to a key sorted StringMapEntry[]
+//   - interface values map to the string of their canonical JSON
+//   - nested structs map to struct types named after the Go type, nil pointers encode as zero values
+func NewTypedData(v interface{}, domain apitypes.TypedDataDomain) (apitypes.TypedData, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return apitypes.TypedData{}, fmt.Errorf("cannot sign nil %s", value.Type())
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return apitypes.TypedData{}, fmt.Errorf("typed data must be a struct, got %s", value.Type())
+	}
+
+	builder := &typeBuilder{types: apitypes.Types{}, goTypes: make(map[string]reflect.Type)}
+	primaryType, err := builder.structType(value.Type())
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+	builder.types["EIP712Domain"] = domainFields(domain)
+
+	message, err := encodeStruct(value)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+
+	return apitypes.TypedData{
+		Types:       builder.types,
+		PrimaryType: primaryType,
+		Domain:      domain,
+		Message:     message,
+	}, nil
+}
+
+// HashTypedData returns the EIP-712 digest of v under the domain
+func HashTypedData(v interface{}, domain apitypes.TypedDataDomain) ([]byte, error) {
+	typedData, err := NewTypedData(v, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build typed data: %w", err)
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return hash, nil
+}
+
+// SignTypedData signs the EIP-712 digest of v under the domain
+func SignTypedData(v interface{}, domain apitypes.TypedDataDomain, privateKey string) (string, error) {
+	hash, err := HashTypedData(v, domain)
+	if err != nil {
+		return "", err
+	}
+	return signHash(hash, privateKey)
+}
+
+// VerifyTypedData checks that signature is signerAddress's EIP-712 signature of v under the domain
+func VerifyTypedData(v interface{}, domain apitypes.TypedDataDomain, signature string, signerAddress string) (bool, error) {
+	hash, err := HashTypedData(v, domain)
+	if err != nil {
+		return false, err
+	}
+	return verifyHash(hash, signature, signerAddress)
+}
+
+// SignStructured signs v in the given format, EIP-712 signatures are bound to chainID
+func SignStructured(v interface{}, chainID int64, privateKey string, format SignatureFormat) (string, error) {
+	switch format {
+	case SignatureFormatEIP712:
+		return SignTypedData(v, NewTypedDataDomain(chainID), privateKey)
+	case SignatureFormatJSON, "":
+		return SignJSONMessage(v, privateKey)
+	default:
+		return "", fmt.Errorf("unknown signature format: %s", format)
+	}
+}
+
+// VerifyStructured checks an EIP-712 signature of v bound to chainID and returns the format
+// the signature verified in, empty if it doesn't verify. The lowercased JSON signature of v
+// is only accepted when acceptLegacy is set, while signers move off the legacy format.
+func VerifyStructured(v interface{}, chainID int64, signature string, signerAddress string, acceptLegacy bool) (SignatureFormat, error) {
+	isValid, err := VerifyTypedData(v, NewTypedDataDomain(chainID), signature, signerAddress)
+	if err != nil {
+		return "", err
+	}
+	if isValid {
+		return SignatureFormatEIP712, nil
+	}
+	if !acceptLegacy {
+		return "", nil
+	}
+
+	isValid, err = VerifySignatureFromJSON(v, signature, signerAddress)
+	if err != nil || !isValid {
+		return "", err
+	}
+	return SignatureFormatJSON, nil
+}
+
+var (
+	bigIntType = reflect.TypeOf(big.Int{})
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+const stringMapEntryType = "StringMapEntry"
+
+// typeBuilder collects the struct types reachable from the primary type
+type typeBuilder struct {
+	types   apitypes.Types
+	goTypes map[string]reflect.Type
+}
+
+// structType registers the struct type t and everything it references, returning its name
+func (b *typeBuilder) structType(t reflect.Type) (string, error) {
+	name := t.Name()
+	if name == "" {
+		return "", fmt.Errorf("cannot derive a type name for anonymous struct %s", t)
+	}
+	if existing, ok := b.goTypes[name]; ok {
+		if existing != t {
+			return "", fmt.Errorf("type name %s is used by both %s and %s", name, existing, t)
+		}
+		return name, nil
+	}
+	// Registered before the fields so self references terminate
+	b.goTypes[name] = t
+
+	fields := make([]apitypes.Type, 0, t.NumField())
+	for _, field := range structFields(t) {
+		fieldType, err := b.fieldType(field.typ)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", name, field.name, err)
+		}
+		fields = append(fields, apitypes.Type{Name: field.name, Type: fieldType})
+	}
+	b.types[name] = fields
+	return name, nil
+}
+
+// fieldType returns the EIP-712 type of a field of Go type t
+func (b *typeBuilder) fieldType(t reflect.Type) (string, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == bigIntType || t == timeType || isBigIntWrapper(t) {
+		return "int256", nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string", nil
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.Int, reflect.Int64:
+		return "int64", nil
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return fmt.Sprintf("int%d", t.Bits()), nil
+	case reflect.Uint, reflect.Uint64:
+		return "uint64", nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return fmt.Sprintf("uint%d", t.Bits()), nil
+	case reflect.Float32, reflect.Float64, reflect.Interface:
+		return "string", nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytes", nil
+		}
+		elemType, err := b.fieldType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return elemType + "[]", nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String || t.Elem().Kind() != reflect.String {
+			return "", fmt.Errorf("unsupported map type %s", t)
+		}
+		b.types[stringMapEntryType] = []apitypes.Type{
+			{Name: "key", Type: "string"},
+			{Name: "value", Type: "string"},
+		}
+		return stringMapEntryType + "[]", nil
+	case reflect.Struct:
+		return b.structType(t)
+	default:
+		return "", fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// encodeStruct encodes a struct value as a typed data message
+func encodeStruct(value reflect.Value) (map[string]interface{}, error) {
+	message := make(map[string]interface{})
+	for _, field := range structFields(value.Type()) {
+		encoded, err := encodeValue(value.FieldByIndex(field.index))
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", value.Type().Name(), field.name, err)
+		}
+		message[field.name] = encoded
+	}
+	return message, nil
+}
+
+// encodeValue encodes a value in the form apitypes expects for the type fieldType derived
+func encodeValue(value reflect.Value) (interface{}, error) {
+	if value.Kind() == reflect.Interface {
+		return canonicalJSON(value.Interface())
+	}
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value = reflect.Zero(value.Type().Elem())
+			continue
+		}
+		value = value.Elem()
+	}
+
+	t := value.Type()
+	switch {
+	case t == bigIntType:
+		i := value.Interface().(big.Int)
+		return new(big.Int).Set(&i), nil
+	case isBigIntWrapper(t):
+		i := value.Field(0).Interface().(*big.Int)
+		if i == nil {
+			return new(big.Int), nil
+		}
+		return new(big.Int).Set(i), nil
+	case t == timeType:
+		return big.NewInt(value.Interface().(time.Time).Unix()), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, t.Bits()), nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			encoded := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(encoded), value)
+			return encoded, nil
+		}
+		items := make([]interface{}, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			item, err := encodeValue(value.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case reflect.Map:
+		keys := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		entries := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			entries = append(entries, map[string]interface{}{
+				"key":   key,
+				"value": value.MapIndex(reflect.ValueOf(key).Convert(t.Key())).String(),
+			})
+		}
+		return entries, nil
+	case reflect.Struct:
+		return encodeStruct(value)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// canonicalJSON encodes a dynamically typed value the way it reads back after a JSON round
+// trip, so the signer's original value and the verifier's decoded copy hash the same
+func canonicalJSON(v interface{}) (string, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal value: %w", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return "", fmt.Errorf("failed to unmarshal value: %w", err)
+	}
+	encoded, err = json.Marshal(decoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal value: %w", err)
+	}
+	return string(encoded), nil
+}
+
+type structField struct {
+	name  string
+	index []int
+	typ   reflect.Type
+}
+
+// structFields lists the signed fields of t in declaration order, flattening embedded structs
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("eip712") == "-" {
+			continue
+		}
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonName == "-" {
+			continue
+		}
+		if field.Anonymous && jsonName == "" && field.Type.Kind() == reflect.Struct {
+			for _, embedded := range structFields(field.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+
+		name := jsonName
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, structField{name: name, index: []int{i}, typ: field.Type})
+	}
+	return fields
+}
+
+// isBigIntWrapper reports whether t is a struct whose only field is an embedded *big.Int
+func isBigIntWrapper(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.NumField() == 1 &&
+		t.Field(0).Anonymous && t.Field(0).Type == reflect.PointerTo(bigIntType)
+}
+
+// domainFields lists the EIP712Domain fields the domain sets, in the order the standard defines
+func domainFields(domain apitypes.TypedDataDomain) []apitypes.Type {
+	var fields []apitypes.Type
+	if domain.Name != "" {
+		fields = append(fields, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		fields = append(fields, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		fields = append(fields, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != "" {
+		fields = append(fields, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	if domain.Salt != "" {
+		fields = append(fields, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+	return fields
+}
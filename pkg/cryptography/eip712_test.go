@@ -0,0 +1,218 @@
+package cryptography
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+type testMetadata struct {
+	Timestamp int64  `json:"timestamp"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type testPayload struct {
+	TaskID    int64             `json:"task_id"`
+	Chain     string            `json:"chain"`
+	Active    bool              `json:"active"`
+	JobID     *types.BigInt     `json:"job_id"`
+	Fee       *big.Int          `json:"fee"`
+	Ratio     float64           `json:"ratio"`
+	Data      []byte            `json:"data"`
+	Arguments []interface{}     `json:"arguments"`
+	Storage   map[string]string `json:"storage"`
+	At        time.Time         `json:"at"`
+	Metadata  *testMetadata     `json:"metadata"`
+	Signature string            `json:"signature" eip712:"-"`
+}
+
+func newTestPayload() testPayload {
+	return testPayload{
+		TaskID:    42,
+		Chain:     "84532",
+		Active:    true,
+		JobID:     types.NewBigInt(big.NewInt(7)),
+		Fee:       big.NewInt(1_000_000_000_000),
+		Ratio:     0.1,
+		Data:      []byte{0x01, 0x02},
+		Arguments: []interface{}{"QmCaseSensitiveCID", 3, true},
+		Storage:   map[string]string{"b": "2", "a": "1"},
+		At:        time.Unix(1_700_000_000, 0).UTC(),
+		Metadata:  &testMetadata{Timestamp: 1, Reason: "ok"},
+	}
+}
+
+func testSignerAddress(t *testing.T) string {
+	privateKeyECDSA, err := crypto.HexToECDSA(testPrivateKey)
+	require.NoError(t, err)
+	return crypto.PubkeyToAddress(privateKeyECDSA.PublicKey).Hex()
+}
+
+func TestNewTypedData_DerivesTypes(t *testing.T) {
+	typedData, err := NewTypedData(newTestPayload(), NewTypedDataDomain(84532))
+	require.NoError(t, err)
+
+	assert.Equal(t, "testPayload", typedData.PrimaryType)
+	assert.Equal(t, "testPayload(int64 task_id,string chain,bool active,int256 job_id,int256 fee,string ratio,bytes data,string[] arguments,StringMapEntry[] storage,int256 at,testMetadata metadata)StringMapEntry(string key,string value)testMetadata(int64 timestamp,string reason)",
+		string(typedData.EncodeType("testPayload")))
+	// Dynamic values keep their JSON type, so "3" and 3 hash differently
+	assert.Equal(t, []interface{}{`"QmCaseSensitiveCID"`, "3", "true"}, typedData.Message["arguments"])
+	assert.Equal(t, `[{"key":"a","value":"1"},{"key":"b","value":"2"}]`, mustJSON(t, typedData.Message["storage"]))
+	assert.Equal(t, "0.1", typedData.Message["ratio"])
+	assert.NotContains(t, typedData.Message, "signature")
+	assert.Len(t, typedData.Types["EIP712Domain"], 3)
+}
+
+func TestNewTypedData_RejectsUnsupportedTypes(t *testing.T) {
+	_, err := NewTypedData(struct{ A int }{1}, NewTypedDataDomain(1))
+	assert.ErrorContains(t, err, "anonymous struct")
+
+	type withIntMap struct {
+		Values map[string]int `json:"values"`
+	}
+	_, err = NewTypedData(withIntMap{}, NewTypedDataDomain(1))
+	assert.ErrorContains(t, err, "unsupported map type")
+
+	_, err = NewTypedData("message", NewTypedDataDomain(1))
+	assert.ErrorContains(t, err, "must be a struct")
+}
+
+func TestSignTypedData_RoundTrip(t *testing.T) {
+	payload := newTestPayload()
+	address := testSignerAddress(t)
+
+	signature, err := SignTypedData(payload, NewTypedDataDomain(84532), testPrivateKey)
+	require.NoError(t, err)
+
+	t.Run("verifies after a JSON round trip", func(t *testing.T) {
+		// Verifiers see the payload decoded from JSON, with the signature attached
+		payload.Signature = signature
+		var decoded testPayload
+		require.NoError(t, json.Unmarshal([]byte(mustJSON(t, payload)), &decoded))
+
+		isValid, err := VerifyTypedData(decoded, NewTypedDataDomain(84532), signature, address)
+		require.NoError(t, err)
+		assert.True(t, isValid)
+	})
+
+	t.Run("bound to the chain", func(t *testing.T) {
+		isValid, err := VerifyTypedData(payload, NewTypedDataDomain(1), signature, address)
+		require.NoError(t, err)
+		assert.False(t, isValid)
+	})
+
+	t.Run("case sensitive", func(t *testing.T) {
+		tampered := newTestPayload()
+		tampered.Arguments[0] = strings.ToLower(tampered.Arguments[0].(string))
+
+		isValid, err := VerifyTypedData(tampered, NewTypedDataDomain(84532), signature, address)
+		require.NoError(t, err)
+		assert.False(t, isValid)
+	})
+
+	t.Run("nil pointers hash as zero values", func(t *testing.T) {
+		empty := testPayload{Metadata: &testMetadata{}, JobID: &types.BigInt{}}
+		emptySignature, err := SignTypedData(testPayload{}, NewTypedDataDomain(0), testPrivateKey)
+		require.NoError(t, err)
+
+		isValid, err := VerifyTypedData(empty, NewTypedDataDomain(0), emptySignature, address)
+		require.NoError(t, err)
+		assert.True(t, isValid)
+	})
+}
+
+func TestVerifyStructured_AcceptsBothFormats(t *testing.T) {
+	payload := newTestPayload()
+	address := testSignerAddress(t)
+
+	for _, format := range []SignatureFormat{SignatureFormatEIP712, SignatureFormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			signature, err := SignStructured(payload, 84532, testPrivateKey, format)
+			require.NoError(t, err)
+
+			verified, err := VerifyStructured(payload, 84532, signature, address, true)
+			require.NoError(t, err)
+			assert.Equal(t, format, verified)
+		})
+	}
+
+	t.Run("legacy format turned off", func(t *testing.T) {
+		signature, err := SignStructured(payload, 84532, testPrivateKey, SignatureFormatJSON)
+		require.NoError(t, err)
+
+		verified, err := VerifyStructured(payload, 84532, signature, address, false)
+		require.NoError(t, err)
+		assert.Empty(t, verified)
+
+		signature, err = SignStructured(payload, 84532, testPrivateKey, SignatureFormatEIP712)
+		require.NoError(t, err)
+
+		verified, err = VerifyStructured(payload, 84532, signature, address, false)
+		require.NoError(t, err)
+		assert.Equal(t, SignatureFormatEIP712, verified)
+	})
+
+	t.Run("wrong signer", func(t *testing.T) {
+		signature, err := SignStructured(payload, 84532, testPrivateKey, SignatureFormatEIP712)
+		require.NoError(t, err)
+
+		verified, err := VerifyStructured(payload, 84532, signature, "0x0000000000000000000000000000000000000001", true)
+		require.NoError(t, err)
+		assert.Empty(t, verified)
+	})
+}
+
+func TestParseSignatureFormat(t *testing.T) {
+	format, err := ParseSignatureFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, SignatureFormatJSON, format)
+
+	format, err = ParseSignatureFormat("EIP712")
+	require.NoError(t, err)
+	assert.Equal(t, SignatureFormatEIP712, format)
+
+	_, err = ParseSignatureFormat("eip191")
+	assert.Error(t, err)
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	encoded, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestNewTypedData_SignedMessageTypes(t *testing.T) {
+	task := &types.SendTaskDataToKeeper{
+		TaskID:      []int64{1},
+		TargetData:  []types.TaskTargetData{{JobID: types.NewBigInt(big.NewInt(1)), TargetChainID: "84532", ScriptStorage: map[string]string{"k": "v"}}},
+		TriggerData: []types.TaskTriggerData{{TaskID: 1}},
+	}
+	messages := []interface{}{
+		task,
+		types.BroadcastDataForPerformer{TaskID: 1, Data: []byte("{}")},
+		types.KeeperHealthCheckIn{KeeperAddress: "0x0a067a261c5F5e8C4c0b9137430b4FE1255EB62e", Signature: "0x01"},
+		types.TaskErrorReport{TaskID: 1, Error: "failed"},
+		types.IPFSData{
+			TaskData:           task,
+			ActionData:         &types.PerformerActionData{TotalFee: big.NewInt(1), ConvertedArguments: []interface{}{"1"}},
+			PerformerSignature: &types.PerformerSignatureData{TaskID: 1, PerformerSignature: "0x01"},
+		},
+	}
+
+	for _, message := range messages {
+		typedData, err := NewTypedData(message, NewTypedDataDomain(task.SigningChainID()))
+		require.NoError(t, err, "%T", message)
+
+		_, _, err = apitypes.TypedDataAndHash(typedData)
+		require.NoError(t, err, "%T", message)
+	}
+}
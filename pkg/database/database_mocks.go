@@ -88,6 +88,16 @@ func (m *MockSession) Query(stmt string, values ...interface{}) *gocql.Query {
 	return dummyQuery
 }
 
+// NewBatch returns an empty batch of the given type.
+func (m *MockSession) NewBatch(typ gocql.BatchType) *gocql.Batch {
+	return &gocql.Batch{Type: typ}
+}
+
+// ExecuteBatch accepts every batch.
+func (m *MockSession) ExecuteBatch(batch *gocql.Batch) error {
+	return nil
+}
+
 func (m *MockSession) Close() {
 	// Clean up if needed
 }
@@ -5,5 +5,7 @@ import "github.com/gocql/gocql"
 // Sessioner defines an interface for gocql.Session for easier mocking.
 type Sessioner interface {
 	Query(stmt string, values ...interface{}) *gocql.Query
+	NewBatch(typ gocql.BatchType) *gocql.Batch
+	ExecuteBatch(batch *gocql.Batch) error
 	Close()
 }
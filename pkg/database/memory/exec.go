@@ -0,0 +1,840 @@
+package memory
+
+import (
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// bound is the value of a bind marker, unset markers leave a column as it is
+type bound struct {
+	value []byte
+	unset bool
+}
+
+type resultColumn struct {
+	name string
+	typ  *cqlType
+}
+
+type resultKind int
+
+const (
+	resultVoid resultKind = 1
+	resultRows resultKind = 2
+)
+
+type result struct {
+	kind            resultKind
+	keyspace, table string
+	columns         []resultColumn
+	rows            [][][]byte
+}
+
+var appliedColumn = resultColumn{name: "[applied]", typ: nativeType(gocql.TypeBoolean)}
+
+// statement is a parsed statement resolved against the schema, as a prepared statement is
+type statement struct {
+	parsed          *parsed
+	keyspace, table string
+	t               *table
+	markers         []resultColumn
+	columns         []resultColumn
+	pkIndexes       []int
+}
+
+// resolve types the bind markers and the result columns of p, keyspace is the one of the
+// connection
+func (s *store) resolve(p *parsed, keyspace string) (*statement, error) {
+	st := &statement{parsed: p}
+	var where []relation
+	var conditional bool
+	switch stmt := p.stmt.(type) {
+	case *selectStmt:
+		st.keyspace, st.table, where = stmt.keyspace, stmt.table, stmt.where
+	case *insertStmt:
+		st.keyspace, st.table, conditional = stmt.keyspace, stmt.table, stmt.ifNotExists
+	case *updateStmt:
+		st.keyspace, st.table, where = stmt.keyspace, stmt.table, stmt.where
+		conditional = stmt.ifExists || len(stmt.conditions) > 0
+	case *deleteStmt:
+		st.keyspace, st.table, where = stmt.keyspace, stmt.table, stmt.where
+		conditional = stmt.ifExists || len(stmt.conditions) > 0
+	default:
+		return st, nil
+	}
+	if st.keyspace == "" {
+		st.keyspace = keyspace
+	}
+	if st.keyspace == "" {
+		return nil, invalidf("No keyspace has been specified. USE a keyspace, or explicitly specify keyspace.tablename")
+	}
+	t, err := s.table(st.keyspace, st.table)
+	if err != nil {
+		return nil, err
+	}
+	st.t = t
+
+	for _, m := range p.markers {
+		if m.role == roleInt {
+			st.markers = append(st.markers, resultColumn{name: "[limit]", typ: nativeType(gocql.TypeInt)})
+			continue
+		}
+		c, ok := t.columns[m.column]
+		if !ok {
+			return nil, invalidf("Undefined column name %s", m.column)
+		}
+		switch m.role {
+		case roleInList:
+			st.markers = append(st.markers, resultColumn{name: "in(" + c.name + ")", typ: &cqlType{id: gocql.TypeList, elem: c.typ}})
+		case roleToken:
+			st.markers = append(st.markers, resultColumn{name: "partition key token", typ: c.typ})
+		default:
+			st.markers = append(st.markers, resultColumn{name: c.name, typ: c.typ})
+		}
+	}
+
+	if insert, ok := p.stmt.(*insertStmt); ok {
+		for _, c := range t.partitionKey {
+			for i, name := range insert.columns {
+				if name == c.name && insert.values[i].isMarker() {
+					st.pkIndexes = append(st.pkIndexes, insert.values[i].marker)
+				}
+			}
+		}
+	} else {
+		for _, c := range t.partitionKey {
+			for _, r := range where {
+				if r.column == c.name && !r.token && r.op == "=" && r.value.isMarker() {
+					st.pkIndexes = append(st.pkIndexes, r.value.marker)
+				}
+			}
+		}
+	}
+	if len(st.pkIndexes) != len(t.partitionKey) {
+		st.pkIndexes = nil
+	}
+
+	switch stmt := p.stmt.(type) {
+	case *selectStmt:
+		if st.columns, err = selectColumns(t, stmt); err != nil {
+			return nil, err
+		}
+	default:
+		if conditional {
+			st.columns = []resultColumn{appliedColumn}
+		}
+	}
+	return st, nil
+}
+
+func selectColumns(t *table, stmt *selectStmt) ([]resultColumn, error) {
+	var columns []resultColumn
+	if stmt.star {
+		for _, c := range t.selectAll() {
+			columns = append(columns, resultColumn{name: c.name, typ: c.typ})
+		}
+		return columns, nil
+	}
+	for _, sel := range stmt.selectors {
+		switch sel.fn {
+		case "now":
+			columns = append(columns, resultColumn{name: sel.name, typ: nativeType(gocql.TypeTimeUUID)})
+			continue
+		case "count":
+			columns = append(columns, resultColumn{name: sel.name, typ: nativeType(gocql.TypeBigInt)})
+			if sel.column == "" {
+				continue
+			}
+		}
+		c, ok := t.columns[sel.column]
+		if !ok {
+			return nil, invalidf("Undefined column name %s", sel.column)
+		}
+		if sel.fn != "count" {
+			columns = append(columns, resultColumn{name: sel.name, typ: c.typ})
+		}
+	}
+	return columns, nil
+}
+
+// execution runs a resolved statement with its bound values
+type execution struct {
+	st     *statement
+	values []bound
+	now    time.Time
+}
+
+func (e *execution) eval(t *term, typ *cqlType) ([]byte, bool, error) {
+	if t.isMarker() {
+		v := e.values[t.marker]
+		if v.unset {
+			return nil, true, nil
+		}
+		return v.value, false, nil
+	}
+	v, err := typ.encodeLiteral(t.lit)
+	return v, false, err
+}
+
+// restriction is a relation with its values evaluated
+type restriction struct {
+	c      *column
+	token  bool
+	op     string
+	values [][]byte
+}
+
+func (e *execution) restrictions(relations []relation) ([]restriction, error) {
+	t := e.st.t
+	var out []restriction
+	for _, r := range relations {
+		c, ok := t.columns[r.column]
+		if !ok {
+			return nil, invalidf("Undefined column name %s", r.column)
+		}
+		res := restriction{c: c, token: r.token, op: r.op}
+		switch {
+		case r.token && c.kind != partitionKeyColumn:
+			return nil, invalidf("The token function arguments must be the partition key")
+		case r.op == "IN" && r.value == nil:
+			for _, term := range r.values {
+				v, unset, err := e.eval(term, c.typ)
+				if err != nil {
+					return nil, err
+				}
+				if unset || v == nil {
+					return nil, invalidf("Invalid null value in condition for column %s", c.name)
+				}
+				res.values = append(res.values, v)
+			}
+		case r.op == "IN":
+			v, unset, err := e.eval(r.value, &cqlType{id: gocql.TypeList, elem: c.typ})
+			if err != nil {
+				return nil, err
+			}
+			if unset || v == nil {
+				return nil, invalidf("Invalid null value in condition for column %s", c.name)
+			}
+			if res.values, err = splitCollection(v); err != nil {
+				return nil, invalidf("Invalid list for IN on %s: %v", c.name, err)
+			}
+		default:
+			v, unset, err := e.eval(r.value, c.typ)
+			if err != nil {
+				return nil, err
+			}
+			if unset || v == nil {
+				return nil, invalidf("Invalid null value in condition for column %s", c.name)
+			}
+			res.values = [][]byte{v}
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// keys lists the partition keys the restrictions select, nil when the partition key is not
+// restricted by equality
+func keys(t *table, restrictions []restriction) [][][]byte {
+	combinations := [][][]byte{{}}
+	for _, c := range t.partitionKey {
+		var values [][]byte
+		for _, r := range restrictions {
+			if r.c == c && !r.token && (r.op == "=" || r.op == "IN") {
+				values = r.values
+			}
+		}
+		if values == nil {
+			return nil
+		}
+		var next [][][]byte
+		for _, prefix := range combinations {
+			for _, v := range values {
+				next = append(next, append(append([][]byte{}, prefix...), v))
+			}
+		}
+		combinations = next
+	}
+	seen := map[string]bool{}
+	var unique [][][]byte
+	for _, key := range combinations {
+		if id := partitionID(key); !seen[id] {
+			seen[id] = true
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
+
+// clusteringKey is the clustering of a single row, restricted by equality
+func clusteringKey(t *table, restrictions []restriction) ([][]byte, bool) {
+	key := make([][]byte, len(t.clustering))
+	for i, c := range t.clustering {
+		found := false
+		for _, r := range restrictions {
+			if r.c == c && r.op == "=" {
+				key[i], found = r.values[0], true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return key, true
+}
+
+func (e *execution) matches(p *partition, r *row, restrictions []restriction) bool {
+	for _, res := range restrictions {
+		if res.token {
+			if !compares(res.op, compareInts(p.token, tokenOf([][]byte{res.values[0]}))) {
+				return false
+			}
+			continue
+		}
+		v := r.value(p, res.c, e.now)
+		if v == nil {
+			return false
+		}
+		if res.op == "IN" {
+			found := false
+			for _, want := range res.values {
+				if res.c.typ.compare(v, want) == 0 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+			continue
+		}
+		if !compares(res.op, res.c.typ.compare(v, res.values[0])) {
+			return false
+		}
+	}
+	return true
+}
+
+func compares(op string, cmp int) bool {
+	switch op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func (e *execution) selectRows(stmt *selectStmt) (*result, error) {
+	t := e.st.t
+	restrictions, err := e.restrictions(stmt.where)
+	if err != nil {
+		return nil, err
+	}
+	limit := -1
+	if stmt.limit != nil {
+		v, unset, err := e.eval(stmt.limit, nativeType(gocql.TypeInt))
+		if err != nil {
+			return nil, err
+		}
+		if !unset && v != nil {
+			if limit = int(signedInt(v)); limit <= 0 {
+				return nil, invalidf("LIMIT must be strictly positive")
+			}
+		}
+	}
+
+	reversed, err := orderReversed(t, stmt.orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	var partitions []*partition
+	partitionKeys := keys(t, restrictions)
+	if partitionKeys == nil && len(stmt.orderBy) > 0 {
+		return nil, invalidf("ORDER BY is only supported when the partition key is restricted by an EQ or an IN.")
+	}
+	if partitionKeys != nil {
+		for _, key := range partitionKeys {
+			if p, ok := t.partitions[partitionID(key)]; ok {
+				partitions = append(partitions, p)
+			}
+		}
+	} else {
+		partitions = t.sortedPartitions()
+	}
+
+	type match struct {
+		p *partition
+		r *row
+	}
+	var matched []match
+	for _, p := range partitions {
+		for _, r := range p.rows {
+			if r.live(e.now) && e.matches(p, r, restrictions) {
+				matched = append(matched, match{p, r})
+			}
+		}
+	}
+	if len(stmt.orderBy) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			cmp := t.compareClustering(matched[i].r.clustering, matched[j].r.clustering)
+			if reversed {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+	}
+
+	res := &result{kind: resultRows, keyspace: t.keyspace, table: t.name, columns: e.st.columns}
+	if stmt.star {
+		all := t.selectAll()
+		for _, m := range matched {
+			if limit >= 0 && len(res.rows) == limit {
+				break
+			}
+			values := make([][]byte, len(all))
+			for i, c := range all {
+				values[i] = m.r.value(m.p, c, e.now)
+			}
+			res.rows = append(res.rows, values)
+		}
+		return res, nil
+	}
+
+	aggregate := false
+	for _, sel := range stmt.selectors {
+		if sel.fn == "max" || sel.fn == "min" || sel.fn == "count" {
+			aggregate = true
+		}
+	}
+	if aggregate {
+		values := make([][]byte, len(stmt.selectors))
+		for i, sel := range stmt.selectors {
+			c := t.columns[sel.column]
+			switch sel.fn {
+			case "count":
+				n := 0
+				for _, m := range matched {
+					if c == nil || m.r.value(m.p, c, e.now) != nil {
+						n++
+					}
+				}
+				values[i] = binary.BigEndian.AppendUint64(nil, uint64(n))
+			case "max", "min":
+				for _, m := range matched {
+					v := m.r.value(m.p, c, e.now)
+					if v == nil {
+						continue
+					}
+					cmp := c.typ.compare(v, values[i])
+					if values[i] == nil || sel.fn == "max" && cmp > 0 || sel.fn == "min" && cmp < 0 {
+						values[i] = v
+					}
+				}
+			case "now":
+				values[i] = nowUUID()
+			default:
+				if len(matched) > 0 {
+					values[i] = matched[0].r.value(matched[0].p, c, e.now)
+				}
+			}
+		}
+		res.rows = append(res.rows, values)
+		return res, nil
+	}
+
+	for _, m := range matched {
+		if limit >= 0 && len(res.rows) == limit {
+			break
+		}
+		values := make([][]byte, len(stmt.selectors))
+		for i, sel := range stmt.selectors {
+			if sel.fn == "now" {
+				values[i] = nowUUID()
+				continue
+			}
+			values[i] = m.r.value(m.p, t.columns[sel.column], e.now)
+		}
+		res.rows = append(res.rows, values)
+	}
+	return res, nil
+}
+
+// orderReversed checks an ORDER BY follows the clustering columns in their declared order and
+// reports whether it reverses the table's clustering order
+func orderReversed(t *table, orderBy []ordering) (bool, error) {
+	reversed := false
+	for i, o := range orderBy {
+		c := t.columns[o.column]
+		if c == nil || c.kind != clusteringColumn {
+			return false, invalidf("Order by is currently only supported on the clustered columns of the PRIMARY KEY, got %s", o.column)
+		}
+		if c.position != i {
+			return false, invalidf("Order by currently only supports the ordering of columns following their declared order in the PRIMARY KEY")
+		}
+		columnReversed := o.descending != t.descending[i]
+		if i > 0 && columnReversed != reversed {
+			return false, invalidf("Unsupported order by relation")
+		}
+		reversed = columnReversed
+	}
+	return reversed, nil
+}
+
+func nowUUID() []byte {
+	return gocql.TimeUUID().Bytes()
+}
+
+// expiry is when cells written with the TTL term expire, zero when they do not
+func (e *execution) expiry(ttl *term) (time.Time, error) {
+	seconds := e.st.t.defaultTTL
+	if ttl != nil {
+		v, unset, err := e.eval(ttl, nativeType(gocql.TypeInt))
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !unset && v != nil {
+			seconds = int(signedInt(v))
+		}
+	}
+	if seconds < 0 {
+		return time.Time{}, invalidf("A TTL must be greater or equal to 0, but was %d", seconds)
+	}
+	if seconds == 0 {
+		return time.Time{}, nil
+	}
+	return e.now.Add(time.Duration(seconds) * time.Second), nil
+}
+
+// lookup returns the live row of a primary key, with its partition when that exists
+func (e *execution) lookup(key, clustering [][]byte) (*partition, *row, int) {
+	p, ok := e.st.t.partitions[partitionID(key)]
+	if !ok {
+		return nil, nil, -1
+	}
+	r, at := p.find(e.st.t, clustering)
+	if r != nil && !r.live(e.now) {
+		return p, nil, at
+	}
+	return p, r, at
+}
+
+// write creates the row of a primary key if needed and sets its cells, a nil value removes
+// the cell
+func (e *execution) write(key, clustering [][]byte, marker bool, cells map[string][]byte, expires time.Time) {
+	t := e.st.t
+	id := partitionID(key)
+	p, ok := t.partitions[id]
+	if !ok {
+		p = &partition{key: key, token: tokenOf(key)}
+		t.partitions[id] = p
+	}
+	r, at := p.find(t, clustering)
+	if r == nil {
+		r = &row{clustering: clustering, cells: map[string]cell{}}
+		p.insert(at, r)
+	} else if !r.live(e.now) {
+		r.marker, r.cells = false, map[string]cell{}
+	}
+	if marker {
+		r.marker, r.markerExpires = true, expires
+	}
+	for name, v := range cells {
+		if v == nil {
+			delete(r.cells, name)
+			continue
+		}
+		r.cells[name] = cell{value: v, expires: expires}
+	}
+}
+
+func (e *execution) insert(stmt *insertStmt) (*result, error) {
+	t := e.st.t
+	key := make([][]byte, len(t.partitionKey))
+	clustering := make([][]byte, len(t.clustering))
+	cells := map[string][]byte{}
+	for i, name := range stmt.columns {
+		c, ok := t.columns[name]
+		if !ok {
+			return nil, invalidf("Undefined column name %s", name)
+		}
+		v, unset, err := e.eval(stmt.values[i], c.typ)
+		if err != nil {
+			return nil, err
+		}
+		if c.kind != regularColumn && (unset || v == nil) {
+			return nil, invalidf("Invalid null value in condition for column %s", c.name)
+		}
+		switch c.kind {
+		case partitionKeyColumn:
+			key[c.position] = v
+		case clusteringColumn:
+			clustering[c.position] = v
+		default:
+			if unset {
+				continue
+			}
+			if cells[name], err = c.typ.normalize(v); err != nil {
+				return nil, invalidf("Invalid value for column %s: %v", name, err)
+			}
+		}
+	}
+	for _, c := range append(append([]*column{}, t.partitionKey...), t.clustering...) {
+		if !contains(stmt.columns, c.name) {
+			return nil, invalidf("Some primary key parts are missing: %s", c.name)
+		}
+	}
+	expires, err := e.expiry(stmt.ttl)
+	if err != nil {
+		return nil, err
+	}
+	if stmt.ifNotExists {
+		if p, r, _ := e.lookup(key, clustering); r != nil {
+			res := e.casResult(false)
+			all := t.selectAll()
+			for _, c := range all {
+				res.columns = append(res.columns, resultColumn{name: c.name, typ: c.typ})
+				res.rows[0] = append(res.rows[0], r.value(p, c, e.now))
+			}
+			return res, nil
+		}
+	}
+	e.write(key, clustering, true, cells, expires)
+	if stmt.ifNotExists {
+		return e.casResult(true), nil
+	}
+	return &result{kind: resultVoid}, nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *execution) casResult(applied bool) *result {
+	v := []byte{0}
+	if applied {
+		v = []byte{1}
+	}
+	return &result{
+		kind:     resultRows,
+		keyspace: e.st.t.keyspace,
+		table:    e.st.t.name,
+		columns:  []resultColumn{appliedColumn},
+		rows:     [][][]byte{{v}},
+	}
+}
+
+// primaryKeys lists the rows an UPDATE or a conditional DELETE writes, every primary key
+// column must be restricted by equality
+func (e *execution) primaryKeys(restrictions []restriction) ([][][]byte, [][]byte, error) {
+	t := e.st.t
+	for _, r := range restrictions {
+		if r.c.kind == regularColumn {
+			return nil, nil, invalidf("Non PRIMARY KEY columns found in where clause: %s", r.c.name)
+		}
+		if r.token {
+			return nil, nil, invalidf("The token function cannot be used in WHERE clauses for UPDATE and DELETE statements")
+		}
+	}
+	partitionKeys := keys(t, restrictions)
+	if partitionKeys == nil {
+		return nil, nil, invalidf("Some partition key parts are missing")
+	}
+	clustering, ok := clusteringKey(t, restrictions)
+	if !ok {
+		return nil, nil, invalidf("Some clustering keys are missing")
+	}
+	return partitionKeys, clustering, nil
+}
+
+// check evaluates the IF clause of a statement on a row, it returns the result to send when
+// the condition does not hold
+func (e *execution) check(key, clustering [][]byte, ifExists bool, conditions []relation) (*result, error) {
+	p, r, _ := e.lookup(key, clustering)
+	if ifExists {
+		if r == nil {
+			return e.casResult(false), nil
+		}
+		return nil, nil
+	}
+	t := e.st.t
+	holds := true
+	var columns []*column
+	for _, cond := range conditions {
+		c, ok := t.columns[cond.column]
+		if !ok {
+			return nil, invalidf("Undefined column name %s", cond.column)
+		}
+		if c.kind != regularColumn {
+			return nil, invalidf("PRIMARY KEY column '%s' cannot have IF conditions", c.name)
+		}
+		want, unset, err := e.eval(cond.value, c.typ)
+		if err != nil {
+			return nil, err
+		}
+		if unset {
+			return nil, invalidf("Invalid unset value for column %s", c.name)
+		}
+		var got []byte
+		if r != nil {
+			got = r.value(p, c, e.now)
+		}
+		switch {
+		case got == nil || want == nil:
+			if cond.op == "=" {
+				holds = holds && got == nil && want == nil
+			} else if cond.op == "!=" {
+				holds = holds && (got == nil) != (want == nil)
+			} else {
+				holds = false
+			}
+		default:
+			holds = holds && compares(cond.op, c.typ.compare(got, want))
+		}
+		if !containsColumn(columns, c) {
+			columns = append(columns, c)
+		}
+	}
+	if holds {
+		return nil, nil
+	}
+	res := e.casResult(false)
+	if r != nil {
+		for _, c := range columns {
+			res.columns = append(res.columns, resultColumn{name: c.name, typ: c.typ})
+			res.rows[0] = append(res.rows[0], r.value(p, c, e.now))
+		}
+	}
+	return res, nil
+}
+
+func containsColumn(columns []*column, c *column) bool {
+	for _, other := range columns {
+		if other == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *execution) update(stmt *updateStmt) (*result, error) {
+	t := e.st.t
+	restrictions, err := e.restrictions(stmt.where)
+	if err != nil {
+		return nil, err
+	}
+	partitionKeys, clustering, err := e.primaryKeys(restrictions)
+	if err != nil {
+		return nil, err
+	}
+	conditional := stmt.ifExists || len(stmt.conditions) > 0
+	if conditional && len(partitionKeys) != 1 {
+		return nil, invalidf("IN on the partition key is not supported with conditional updates")
+	}
+	cells := map[string][]byte{}
+	for _, set := range stmt.set {
+		c, ok := t.columns[set.column]
+		if !ok {
+			return nil, invalidf("Undefined column name %s", set.column)
+		}
+		if c.kind != regularColumn {
+			return nil, invalidf("PRIMARY KEY part %s found in SET part", c.name)
+		}
+		v, unset, err := e.eval(set.value, c.typ)
+		if err != nil {
+			return nil, err
+		}
+		if unset {
+			continue
+		}
+		if cells[c.name], err = c.typ.normalize(v); err != nil {
+			return nil, invalidf("Invalid value for column %s: %v", c.name, err)
+		}
+	}
+	expires, err := e.expiry(stmt.ttl)
+	if err != nil {
+		return nil, err
+	}
+	if conditional {
+		failed, err := e.check(partitionKeys[0], clustering, stmt.ifExists, stmt.conditions)
+		if err != nil || failed != nil {
+			return failed, err
+		}
+	}
+	for _, key := range partitionKeys {
+		e.write(key, clustering, false, cells, expires)
+	}
+	if conditional {
+		return e.casResult(true), nil
+	}
+	return &result{kind: resultVoid}, nil
+}
+
+func (e *execution) delete(stmt *deleteStmt) (*result, error) {
+	t := e.st.t
+	restrictions, err := e.restrictions(stmt.where)
+	if err != nil {
+		return nil, err
+	}
+	conditional := stmt.ifExists || len(stmt.conditions) > 0
+	if conditional {
+		partitionKeys, clustering, err := e.primaryKeys(restrictions)
+		if err != nil {
+			return nil, err
+		}
+		if len(partitionKeys) != 1 {
+			return nil, invalidf("IN on the partition key is not supported with conditional deletions")
+		}
+		failed, err := e.check(partitionKeys[0], clustering, stmt.ifExists, stmt.conditions)
+		if err != nil || failed != nil {
+			return failed, err
+		}
+	}
+	partitionKeys := keys(t, restrictions)
+	if partitionKeys == nil {
+		return nil, invalidf("Some partition key parts are missing")
+	}
+	for _, r := range restrictions {
+		if r.c.kind == regularColumn || r.token {
+			return nil, invalidf("Non PRIMARY KEY columns found in where clause: %s", r.c.name)
+		}
+	}
+	for _, key := range partitionKeys {
+		id := partitionID(key)
+		p, ok := t.partitions[id]
+		if !ok {
+			continue
+		}
+		kept := p.rows[:0]
+		for _, r := range p.rows {
+			if !e.matches(p, r, restrictions) {
+				kept = append(kept, r)
+			}
+		}
+		p.rows = kept
+		if len(p.rows) == 0 {
+			delete(t.partitions, id)
+		}
+	}
+	if conditional {
+		return e.casResult(true), nil
+	}
+	return &result{kind: resultVoid}, nil
+}
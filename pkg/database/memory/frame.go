@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Opcodes of the native protocol
+const (
+	opError     byte = 0x00
+	opStartup   byte = 0x01
+	opReady     byte = 0x02
+	opOptions   byte = 0x05
+	opSupported byte = 0x06
+	opQuery     byte = 0x07
+	opResult    byte = 0x08
+	opPrepare   byte = 0x09
+	opExecute   byte = 0x0A
+	opRegister  byte = 0x0B
+	opBatch     byte = 0x0D
+)
+
+// Error codes of the native protocol
+const (
+	errServer        = 0x0000
+	errProtocol      = 0x000A
+	errSyntax        = 0x2000
+	errInvalid       = 0x2200
+	errAlreadyExists = 0x2400
+	errUnprepared    = 0x2500
+)
+
+// Result kinds beyond void and rows
+const (
+	resultSetKeyspace = 3
+	resultPrepared    = 4
+)
+
+// Flags of query parameters and result metadata
+const (
+	flagValues         = 0x01
+	flagPageSize       = 0x04
+	flagPagingState    = 0x08
+	flagSerial         = 0x10
+	flagTimestamp      = 0x20
+	flagNamedValues    = 0x40
+	flagGlobalSpec     = 0x0001
+	maxFrameBodyLength = 256 << 20
+)
+
+type frame struct {
+	version byte
+	stream  int16
+	opcode  byte
+	body    []byte
+}
+
+func readFrame(r io.Reader) (*frame, error) {
+	header := make([]byte, 9)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(header[5:])
+	if length > maxFrameBodyLength {
+		return nil, fmt.Errorf("frame body of %d bytes", length)
+	}
+	f := &frame{
+		version: header[0] & 0x7F,
+		stream:  int16(binary.BigEndian.Uint16(header[2:])),
+		opcode:  header[4],
+		body:    make([]byte, length),
+	}
+	if _, err := io.ReadFull(r, f.body); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// reader decodes the body of a request, the first decoding error sticks
+type reader struct {
+	buf []byte
+	err error
+}
+
+var errShortBody = errors.New("frame body too short")
+
+func (r *reader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.buf) < n {
+		r.err = errShortBody
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *reader) byte() byte {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) short() uint16 {
+	if b := r.take(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) int() int32 {
+	if b := r.take(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *reader) string() string {
+	return string(r.take(int(r.short())))
+}
+
+func (r *reader) longString() string {
+	return string(r.take(int(r.int())))
+}
+
+func (r *reader) shortBytes() []byte {
+	return r.take(int(r.short()))
+}
+
+func (r *reader) bytes() []byte {
+	return r.take(int(r.int()))
+}
+
+// value reads a bound [value], -1 is null and -2 is unset
+func (r *reader) value() bound {
+	n := r.int()
+	switch {
+	case n == -1:
+		return bound{}
+	case n == -2:
+		return bound{unset: true}
+	case n < 0:
+		r.err = fmt.Errorf("invalid value length %d", n)
+		return bound{}
+	}
+	v := r.take(int(n))
+	return bound{value: append([]byte{}, v...)}
+}
+
+// queryParameters reads the parameters of QUERY and EXECUTE, paging and consistency do not
+// apply to a single in-memory node so only the values are kept
+func (r *reader) queryParameters() []bound {
+	r.short()
+	flags := r.byte()
+	var values []bound
+	if flags&flagValues != 0 {
+		n := int(r.short())
+		for i := 0; i < n && r.err == nil; i++ {
+			if flags&flagNamedValues != 0 {
+				r.string()
+			}
+			values = append(values, r.value())
+		}
+	}
+	if flags&flagPageSize != 0 {
+		r.int()
+	}
+	if flags&flagPagingState != 0 {
+		r.bytes()
+	}
+	if flags&flagSerial != 0 {
+		r.short()
+	}
+	if flags&flagTimestamp != 0 {
+		r.take(8)
+	}
+	return values
+}
+
+// writer encodes the body of a response
+type writer struct {
+	buf []byte
+}
+
+func (w *writer) byte(b byte) {
+	w.buf = append(w.buf, b)
+}
+
+func (w *writer) short(n uint16) {
+	w.buf = binary.BigEndian.AppendUint16(w.buf, n)
+}
+
+func (w *writer) int(n int32) {
+	w.buf = binary.BigEndian.AppendUint32(w.buf, uint32(n))
+}
+
+func (w *writer) string(s string) {
+	w.short(uint16(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *writer) shortBytes(b []byte) {
+	w.short(uint16(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+// bytes writes a [bytes], nil is written as null
+func (w *writer) bytes(b []byte) {
+	if b == nil {
+		w.int(-1)
+		return
+	}
+	w.int(int32(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *writer) frame(version byte, stream int16, opcode byte) []byte {
+	out := make([]byte, 9, 9+len(w.buf))
+	out[0] = version | 0x80
+	binary.BigEndian.PutUint16(out[2:], uint16(stream))
+	out[4] = opcode
+	binary.BigEndian.PutUint32(out[5:], uint32(len(w.buf)))
+	return append(out, w.buf...)
+}
+
+// metadata writes the column specifications of a rows result or of the bind markers of a
+// prepared statement, pkIndexes is nil for result metadata
+func (w *writer) metadata(keyspace, table string, columns []resultColumn, pkIndexes []int, prepared bool) {
+	w.int(flagGlobalSpec)
+	w.int(int32(len(columns)))
+	if prepared {
+		w.int(int32(len(pkIndexes)))
+		for _, i := range pkIndexes {
+			w.short(uint16(i))
+		}
+	}
+	w.string(keyspace)
+	w.string(table)
+	for _, c := range columns {
+		w.string(c.name)
+		c.typ.encodeOption(w)
+	}
+}
+
+func (w *writer) rows(res *result) {
+	w.int(int32(resultRows))
+	w.metadata(res.keyspace, res.table, res.columns, nil, false)
+	w.int(int32(len(res.rows)))
+	for _, row := range res.rows {
+		for _, v := range row {
+			w.bytes(v)
+		}
+	}
+}
@@ -0,0 +1,1002 @@
+package memory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokQuotedIdent
+	tokString
+	tokNumber
+	tokNull
+	tokMarker
+	tokSymbol
+	tokLBrace
+	tokLBracket
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// syntaxError is reported to the client with the SyntaxError code
+type syntaxError struct {
+	msg string
+}
+
+func (e *syntaxError) Error() string {
+	return e.msg
+}
+
+// invalidError is reported to the client with the Invalid code
+type invalidError struct {
+	msg string
+}
+
+func (e *invalidError) Error() string {
+	return e.msg
+}
+
+func invalidf(format string, args ...interface{}) error {
+	return &invalidError{msg: fmt.Sprintf(format, args...)}
+}
+
+func tokenize(stmt string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(stmt); {
+		c := stmt[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(stmt[i:], "--"), c == '/' && strings.HasPrefix(stmt[i:], "//"):
+			for i < len(stmt) && stmt[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(stmt[i:], "/*"):
+			end := strings.Index(stmt[i+2:], "*/")
+			if end < 0 {
+				return nil, &syntaxError{msg: "unterminated comment"}
+			}
+			i += end + 4
+		case isIdentStart(c):
+			j := i
+			for j < len(stmt) && isIdentPart(stmt[j]) {
+				j++
+			}
+			word := stmt[i:j]
+			if strings.EqualFold(word, "null") {
+				tokens = append(tokens, token{kind: tokNull, text: "null"})
+			} else {
+				tokens = append(tokens, token{kind: tokIdent, text: word})
+			}
+			i = j
+		case c >= '0' && c <= '9', c == '-' && i+1 < len(stmt) && stmt[i+1] >= '0' && stmt[i+1] <= '9':
+			j := i + 1
+			for j < len(stmt) && (stmt[j] >= '0' && stmt[j] <= '9' || stmt[j] == '.' || stmt[j] == 'e' || stmt[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: stmt[i:j]})
+			i = j
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			j := i + 1
+			for {
+				if j >= len(stmt) {
+					return nil, &syntaxError{msg: "unterminated quoted string"}
+				}
+				if stmt[j] == c {
+					if j+1 < len(stmt) && stmt[j+1] == c {
+						b.WriteByte(c)
+						j += 2
+						continue
+					}
+					break
+				}
+				b.WriteByte(stmt[j])
+				j++
+			}
+			kind := tokString
+			if c == '"' {
+				kind = tokQuotedIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: b.String()})
+			i = j + 1
+		case c == '?':
+			tokens = append(tokens, token{kind: tokMarker, text: "?"})
+			i++
+		case c == '<' || c == '>' || c == '!':
+			if i+1 < len(stmt) && stmt[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokSymbol, text: stmt[i : i+2]})
+				i += 2
+			} else {
+				tokens = append(tokens, token{kind: tokSymbol, text: stmt[i : i+1]})
+				i++
+			}
+		case strings.IndexByte("(),;.=*{}[]:+", c) >= 0:
+			tokens = append(tokens, token{kind: tokSymbol, text: stmt[i : i+1]})
+			i++
+		default:
+			return nil, &syntaxError{msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	return append(tokens, token{kind: tokEOF}), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+// termRole is what a term is bound to, it decides the type of a bind marker
+type termRole int
+
+const (
+	roleValue termRole = iota
+	roleInList
+	roleToken
+	roleInt
+)
+
+// term is a bind marker or a constant, column is the column it is compared with or written to
+type term struct {
+	marker int
+	lit    literal
+	column string
+	role   termRole
+	fn     string
+}
+
+func (t *term) isMarker() bool {
+	return t.marker >= 0
+}
+
+type relation struct {
+	column string
+	token  bool
+	op     string
+	value  *term
+	values []*term
+}
+
+type assignment struct {
+	column string
+	value  *term
+}
+
+type columnDef struct {
+	name string
+	typ  *cqlType
+}
+
+type selector struct {
+	fn     string
+	column string
+	name   string
+}
+
+type useStmt struct {
+	keyspace string
+}
+
+type createKeyspaceStmt struct {
+	keyspace    string
+	ifNotExists bool
+}
+
+type createTableStmt struct {
+	keyspace, table string
+	ifNotExists     bool
+	columns         []columnDef
+	partitionKey    []string
+	clustering      []string
+	descending      map[string]bool
+	defaultTTL      int
+}
+
+type createIndexStmt struct {
+	keyspace, table string
+	ifNotExists     bool
+	column          string
+}
+
+type alterTableStmt struct {
+	keyspace, table string
+	add             []columnDef
+}
+
+type dropTableStmt struct {
+	keyspace, table string
+	ifExists        bool
+}
+
+type truncateStmt struct {
+	keyspace, table string
+}
+
+type selectStmt struct {
+	keyspace, table string
+	star            bool
+	selectors       []selector
+	where           []relation
+	orderBy         []ordering
+	limit           *term
+}
+
+// ordering is a column of an ORDER BY clause
+type ordering struct {
+	column     string
+	descending bool
+}
+
+type insertStmt struct {
+	keyspace, table string
+	columns         []string
+	values          []*term
+	ifNotExists     bool
+	ttl             *term
+}
+
+type updateStmt struct {
+	keyspace, table string
+	ttl             *term
+	set             []assignment
+	where           []relation
+	conditions      []relation
+	ifExists        bool
+}
+
+type deleteStmt struct {
+	keyspace, table string
+	where           []relation
+	conditions      []relation
+	ifExists        bool
+}
+
+// parsed is a statement with its bind markers in order
+type parsed struct {
+	stmt    interface{}
+	markers []*term
+}
+
+type parser struct {
+	tokens  []token
+	pos     int
+	markers []*term
+}
+
+func parse(stmt string) (*parsed, error) {
+	tokens, err := tokenize(stmt)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	s, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	p.accept(";")
+	if p.peek().kind != tokEOF {
+		return nil, p.errorf("unexpected %q at end of statement", p.peek().text)
+	}
+	return &parsed{stmt: s, markers: p.markers}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &syntaxError{msg: "line 1: " + fmt.Sprintf(format, args...)}
+}
+
+// isKeyword reports whether the next token is the keyword or symbol word
+func (p *parser) isKeyword(word string) bool {
+	t := p.peek()
+	return (t.kind == tokIdent || t.kind == tokSymbol) && strings.EqualFold(t.text, word)
+}
+
+func (p *parser) accept(word string) bool {
+	if p.isKeyword(word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(words ...string) error {
+	for _, word := range words {
+		if !p.accept(word) {
+			return p.errorf("expected %s, found %q", word, p.peek().text)
+		}
+	}
+	return nil
+}
+
+func (p *parser) identifier() (string, error) {
+	t := p.next()
+	switch t.kind {
+	case tokIdent:
+		return strings.ToLower(t.text), nil
+	case tokQuotedIdent:
+		return t.text, nil
+	}
+	return "", p.errorf("expected identifier, found %q", t.text)
+}
+
+// tableName reads [keyspace.]table, keyspace is empty when not given
+func (p *parser) tableName() (string, string, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return "", "", err
+	}
+	if p.accept(".") {
+		table, err := p.identifier()
+		return name, table, err
+	}
+	return "", name, nil
+}
+
+func (p *parser) ifNotExists() (bool, error) {
+	if !p.accept("IF") {
+		return false, nil
+	}
+	return true, p.expect("NOT", "EXISTS")
+}
+
+func (p *parser) statement() (interface{}, error) {
+	switch {
+	case p.accept("USE"):
+		ks, err := p.identifier()
+		return &useStmt{keyspace: ks}, err
+	case p.accept("SELECT"):
+		return p.selectStatement()
+	case p.accept("INSERT"):
+		return p.insertStatement()
+	case p.accept("UPDATE"):
+		return p.updateStatement()
+	case p.accept("DELETE"):
+		return p.deleteStatement()
+	case p.accept("CREATE"):
+		switch {
+		case p.accept("TABLE"), p.accept("COLUMNFAMILY"):
+			return p.createTable()
+		case p.accept("INDEX"):
+			return p.createIndex()
+		case p.accept("KEYSPACE"):
+			return p.createKeyspace()
+		}
+		return nil, p.errorf("unsupported CREATE %q", p.peek().text)
+	case p.accept("ALTER"):
+		if err := p.expect("TABLE"); err != nil {
+			return nil, err
+		}
+		return p.alterTable()
+	case p.accept("DROP"):
+		if err := p.expect("TABLE"); err != nil {
+			return nil, err
+		}
+		s := &dropTableStmt{}
+		if p.accept("IF") {
+			if err := p.expect("EXISTS"); err != nil {
+				return nil, err
+			}
+			s.ifExists = true
+		}
+		var err error
+		s.keyspace, s.table, err = p.tableName()
+		return s, err
+	case p.accept("TRUNCATE"):
+		p.accept("TABLE")
+		s := &truncateStmt{}
+		var err error
+		s.keyspace, s.table, err = p.tableName()
+		return s, err
+	}
+	return nil, p.errorf("unsupported statement starting with %q", p.peek().text)
+}
+
+func (p *parser) createKeyspace() (interface{}, error) {
+	s := &createKeyspaceStmt{}
+	var err error
+	if s.ifNotExists, err = p.ifNotExists(); err != nil {
+		return nil, err
+	}
+	if s.keyspace, err = p.identifier(); err != nil {
+		return nil, err
+	}
+	// Replication and durable writes do not apply to a single in-memory node
+	for p.peek().kind != tokEOF && !p.isKeyword(";") {
+		p.next()
+	}
+	return s, nil
+}
+
+func (p *parser) cqlType() (*cqlType, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "list", "set":
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		elem, err := p.cqlType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+		t := &cqlType{id: gocql.TypeList, elem: elem}
+		if name == "set" {
+			t.id = gocql.TypeSet
+		}
+		return t, nil
+	case "frozen":
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		t, err := p.cqlType()
+		if err != nil {
+			return nil, err
+		}
+		return t, p.expect(">")
+	}
+	id, ok := nativeTypes[name]
+	if !ok {
+		return nil, invalidf("unsupported type %s", name)
+	}
+	return nativeType(id), nil
+}
+
+func (p *parser) identifierList() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.accept(",") {
+			break
+		}
+	}
+	return names, p.expect(")")
+}
+
+func (p *parser) createTable() (interface{}, error) {
+	s := &createTableStmt{descending: map[string]bool{}}
+	var err error
+	if s.ifNotExists, err = p.ifNotExists(); err != nil {
+		return nil, err
+	}
+	if s.keyspace, s.table, err = p.tableName(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	for {
+		if p.accept("PRIMARY") {
+			if err := p.expect("KEY", "("); err != nil {
+				return nil, err
+			}
+			if p.isKeyword("(") {
+				if s.partitionKey, err = p.identifierList(); err != nil {
+					return nil, err
+				}
+			} else {
+				name, err := p.identifier()
+				if err != nil {
+					return nil, err
+				}
+				s.partitionKey = []string{name}
+			}
+			for p.accept(",") {
+				name, err := p.identifier()
+				if err != nil {
+					return nil, err
+				}
+				s.clustering = append(s.clustering, name)
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		} else {
+			name, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			typ, err := p.cqlType()
+			if err != nil {
+				return nil, err
+			}
+			s.columns = append(s.columns, columnDef{name: name, typ: typ})
+			if p.accept("PRIMARY") {
+				if err := p.expect("KEY"); err != nil {
+					return nil, err
+				}
+				s.partitionKey = []string{name}
+			}
+		}
+		if !p.accept(",") {
+			break
+		}
+		if p.isKeyword(")") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if p.accept("WITH") {
+		for {
+			switch {
+			case p.accept("CLUSTERING"):
+				if err := p.expect("ORDER", "BY", "("); err != nil {
+					return nil, err
+				}
+				for {
+					name, err := p.identifier()
+					if err != nil {
+						return nil, err
+					}
+					if p.accept("DESC") {
+						s.descending[name] = true
+					} else {
+						p.accept("ASC")
+					}
+					if !p.accept(",") {
+						break
+					}
+				}
+				if err := p.expect(")"); err != nil {
+					return nil, err
+				}
+			default:
+				option, err := p.identifier()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect("="); err != nil {
+					return nil, err
+				}
+				value, err := p.literal()
+				if err != nil {
+					return nil, err
+				}
+				if option == "default_time_to_live" {
+					if s.defaultTTL, err = strconv.Atoi(value.text); err != nil {
+						return nil, invalidf("invalid default_time_to_live %s", value.text)
+					}
+				}
+			}
+			if !p.accept("AND") {
+				break
+			}
+		}
+	}
+	if len(s.partitionKey) == 0 {
+		return nil, invalidf("no PRIMARY KEY specified for table %s", s.table)
+	}
+	return s, nil
+}
+
+func (p *parser) createIndex() (interface{}, error) {
+	s := &createIndexStmt{}
+	var err error
+	if s.ifNotExists, err = p.ifNotExists(); err != nil {
+		return nil, err
+	}
+	if !p.isKeyword("ON") {
+		if _, err := p.identifier(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("ON"); err != nil {
+		return nil, err
+	}
+	if s.keyspace, s.table, err = p.tableName(); err != nil {
+		return nil, err
+	}
+	columns, err := p.identifierList()
+	if err != nil {
+		return nil, err
+	}
+	s.column = columns[0]
+	return s, nil
+}
+
+func (p *parser) alterTable() (interface{}, error) {
+	s := &alterTableStmt{}
+	var err error
+	if s.keyspace, s.table, err = p.tableName(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("ADD"); err != nil {
+		return nil, err
+	}
+	grouped := p.accept("(")
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		typ, err := p.cqlType()
+		if err != nil {
+			return nil, err
+		}
+		s.add = append(s.add, columnDef{name: name, typ: typ})
+		if !grouped || !p.accept(",") {
+			break
+		}
+	}
+	if grouped {
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// literal reads a constant: a string, number, boolean, null or a set or list of constants
+func (p *parser) literal() (literal, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString, tokNumber, tokNull:
+		return literal{kind: t.kind, text: t.text}, nil
+	case tokIdent:
+		if strings.EqualFold(t.text, "true") || strings.EqualFold(t.text, "false") {
+			return literal{kind: tokIdent, text: t.text}, nil
+		}
+	case tokSymbol:
+		if t.text == "{" || t.text == "[" {
+			l := literal{kind: tokLBrace, text: t.text}
+			closing := "}"
+			if t.text == "[" {
+				l.kind, closing = tokLBracket, "]"
+			}
+			if p.accept(closing) {
+				return l, nil
+			}
+			for {
+				elem, err := p.literal()
+				if err != nil {
+					return l, err
+				}
+				l.elems = append(l.elems, elem)
+				if !p.accept(",") {
+					break
+				}
+			}
+			return l, p.expect(closing)
+		}
+	}
+	return literal{}, p.errorf("expected a constant, found %q", t.text)
+}
+
+// term reads a bind marker or a constant
+func (p *parser) term(column string, role termRole) (*term, error) {
+	if p.peek().kind == tokMarker {
+		p.next()
+		t := &term{marker: len(p.markers), column: column, role: role}
+		p.markers = append(p.markers, t)
+		return t, nil
+	}
+	lit, err := p.literal()
+	if err != nil {
+		return nil, err
+	}
+	return &term{marker: -1, lit: lit, column: column, role: role}, nil
+}
+
+func (p *parser) selectStatement() (interface{}, error) {
+	s := &selectStmt{}
+	if p.accept("*") {
+		s.star = true
+	} else {
+		for {
+			sel, err := p.selector()
+			if err != nil {
+				return nil, err
+			}
+			s.selectors = append(s.selectors, sel)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	var err error
+	if s.keyspace, s.table, err = p.tableName(); err != nil {
+		return nil, err
+	}
+	if p.accept("WHERE") {
+		if s.where, err = p.relations(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			o := ordering{}
+			if o.column, err = p.identifier(); err != nil {
+				return nil, err
+			}
+			if p.accept("DESC") {
+				o.descending = true
+			} else {
+				p.accept("ASC")
+			}
+			s.orderBy = append(s.orderBy, o)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("LIMIT") {
+		if s.limit, err = p.term("", roleInt); err != nil {
+			return nil, err
+		}
+	}
+	p.accept("ALLOW")
+	p.accept("FILTERING")
+	return s, nil
+}
+
+func (p *parser) selector() (selector, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return selector{}, err
+	}
+	if !p.accept("(") {
+		return selector{column: name, name: name}, nil
+	}
+	sel := selector{fn: name}
+	switch name {
+	case "now":
+	case "count":
+		if !p.accept("*") {
+			if sel.column, err = p.identifier(); err != nil {
+				return sel, err
+			}
+		}
+	case "max", "min":
+		if sel.column, err = p.identifier(); err != nil {
+			return sel, err
+		}
+	default:
+		return sel, invalidf("unknown function %s", name)
+	}
+	if err := p.expect(")"); err != nil {
+		return sel, err
+	}
+	sel.name = "system." + name + "(" + sel.column + ")"
+	if name == "count" {
+		sel.name = "count"
+	}
+	return sel, nil
+}
+
+func (p *parser) relations() ([]relation, error) {
+	var relations []relation
+	for {
+		r := relation{}
+		if p.accept("TOKEN") {
+			columns, err := p.identifierList()
+			if err != nil {
+				return nil, err
+			}
+			r.column, r.token = columns[0], true
+		} else {
+			var err error
+			if r.column, err = p.identifier(); err != nil {
+				return nil, err
+			}
+		}
+		op := p.next()
+		switch {
+		case op.kind == tokSymbol && (op.text == "=" || op.text == "<" || op.text == ">" || op.text == "<=" || op.text == ">=" || op.text == "!="):
+			r.op = op.text
+		case op.kind == tokIdent && strings.EqualFold(op.text, "IN"):
+			r.op = "IN"
+		default:
+			return nil, p.errorf("expected an operator after %s, found %q", r.column, op.text)
+		}
+		switch {
+		case r.token:
+			if err := p.expect("TOKEN", "("); err != nil {
+				return nil, err
+			}
+			value, err := p.term(r.column, roleToken)
+			if err != nil {
+				return nil, err
+			}
+			r.value = value
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		case r.op == "IN" && p.accept("("):
+			for !p.accept(")") {
+				value, err := p.term(r.column, roleValue)
+				if err != nil {
+					return nil, err
+				}
+				r.values = append(r.values, value)
+				p.accept(",")
+			}
+		case r.op == "IN":
+			value, err := p.term(r.column, roleInList)
+			if err != nil {
+				return nil, err
+			}
+			r.value = value
+		default:
+			value, err := p.term(r.column, roleValue)
+			if err != nil {
+				return nil, err
+			}
+			r.value = value
+		}
+		relations = append(relations, r)
+		if !p.accept("AND") {
+			return relations, nil
+		}
+	}
+}
+
+// usingTTL reads USING TTL n, the only write option the repositories use
+func (p *parser) usingTTL() (*term, error) {
+	if !p.accept("USING") {
+		return nil, nil
+	}
+	if err := p.expect("TTL"); err != nil {
+		return nil, err
+	}
+	return p.term("", roleInt)
+}
+
+func (p *parser) insertStatement() (interface{}, error) {
+	if err := p.expect("INTO"); err != nil {
+		return nil, err
+	}
+	s := &insertStmt{}
+	var err error
+	if s.keyspace, s.table, err = p.tableName(); err != nil {
+		return nil, err
+	}
+	if s.columns, err = p.identifierList(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("VALUES", "("); err != nil {
+		return nil, err
+	}
+	for i := 0; ; i++ {
+		column := ""
+		if i < len(s.columns) {
+			column = s.columns[i]
+		}
+		value, err := p.term(column, roleValue)
+		if err != nil {
+			return nil, err
+		}
+		s.values = append(s.values, value)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(s.values) != len(s.columns) {
+		return nil, invalidf("unmatched column names/values")
+	}
+	if s.ifNotExists, err = p.ifNotExists(); err != nil {
+		return nil, err
+	}
+	if s.ttl, err = p.usingTTL(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (p *parser) conditions() ([]relation, bool, error) {
+	if !p.accept("IF") {
+		return nil, false, nil
+	}
+	if p.accept("EXISTS") {
+		return nil, true, nil
+	}
+	conditions, err := p.relations()
+	return conditions, false, err
+}
+
+func (p *parser) updateStatement() (interface{}, error) {
+	s := &updateStmt{}
+	var err error
+	if s.keyspace, s.table, err = p.tableName(); err != nil {
+		return nil, err
+	}
+	if s.ttl, err = p.usingTTL(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("SET"); err != nil {
+		return nil, err
+	}
+	for {
+		column, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		value, err := p.term(column, roleValue)
+		if err != nil {
+			return nil, err
+		}
+		s.set = append(s.set, assignment{column: column, value: value})
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect("WHERE"); err != nil {
+		return nil, err
+	}
+	if s.where, err = p.relations(); err != nil {
+		return nil, err
+	}
+	if s.conditions, s.ifExists, err = p.conditions(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (p *parser) deleteStatement() (interface{}, error) {
+	if err := p.expect("FROM"); err != nil {
+		return nil, p.errorf("only whole rows can be deleted")
+	}
+	s := &deleteStmt{}
+	var err error
+	if s.keyspace, s.table, err = p.tableName(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("WHERE"); err != nil {
+		return nil, err
+	}
+	if s.where, err = p.relations(); err != nil {
+		return nil, err
+	}
+	if s.conditions, s.ifExists, err = p.conditions(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
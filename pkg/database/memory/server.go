@@ -0,0 +1,409 @@
+// Package memory is an in-memory stand-in for ScyllaDB. It serves the CQL native protocol on a
+// local port, so a gocql session from database.NewConnection talks to it as it talks to a
+// Scylla node and the repositories run unchanged. It keeps a single node's semantics: tables,
+// clustering order, TTLs, lightweight transactions and logged batches. Paging, consistency
+// and secondary indexes do not apply, every restriction is served by a scan.
+package memory
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// releaseVersion is reported in system.local, below 4.0 so gocql reads system.peers
+const releaseVersion = "3.0.8"
+
+// Server serves an in-memory keyspace over the CQL native protocol
+type Server struct {
+	logger    logging.Logger
+	listener  net.Listener
+	store     *store
+	keyspaces []string
+
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	prepared map[string]*parsed
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server on a free local port with the given keyspaces created
+func NewServer(logger logging.Logger, keyspaces ...string) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	s := &Server{
+		logger:    logger,
+		listener:  listener,
+		store:     newStore(),
+		keyspaces: keyspaces,
+		conns:     make(map[net.Conn]struct{}),
+		prepared:  make(map[string]*parsed),
+	}
+	if err := s.initKeyspaces(); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Host is the address the server listens on
+func (s *Server) Host() string {
+	return s.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port is the port the server listens on
+func (s *Server) Port() string {
+	return strconv.Itoa(s.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// Config returns a connection config for the server and keyspace
+func (s *Server) Config(keyspace string) *database.Config {
+	cfg := database.NewConfig(s.Host(), s.Port()).WithKeyspace(keyspace).WithTimeout(5 * time.Second)
+	cfg.ConnectWait = 5 * time.Second
+	return cfg
+}
+
+// Reset drops every table, the keyspaces are created again empty
+func (s *Server) Reset() error {
+	s.store.mu.Lock()
+	s.store.keyspaces = map[string]map[string]*table{}
+	s.store.mu.Unlock()
+	return s.initKeyspaces()
+}
+
+// Close stops the server and closes its connections
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// initKeyspaces creates the system tables gocql reads when it connects and the user keyspaces
+func (s *Server) initKeyspaces() error {
+	statements := []string{
+		"CREATE KEYSPACE system",
+		`CREATE TABLE system.local (key text PRIMARY KEY, bootstrapped text, broadcast_address inet,
+			cluster_name text, cql_version text, data_center text, host_id uuid, listen_address inet,
+			native_port int, partitioner text, rack text, release_version text, rpc_address inet,
+			schema_version uuid, tokens set<text>)`,
+		`CREATE TABLE system.peers (peer inet PRIMARY KEY, data_center text, host_id uuid, preferred_ip inet,
+			rack text, release_version text, rpc_address inet, schema_version uuid, tokens set<text>)`,
+	}
+	for _, keyspace := range s.keyspaces {
+		statements = append(statements, "CREATE KEYSPACE IF NOT EXISTS "+strconv.Quote(keyspace))
+	}
+	for _, stmt := range statements {
+		if err := s.exec(stmt); err != nil {
+			return fmt.Errorf("failed to create system schema: %w", err)
+		}
+	}
+
+	hostID, err := gocql.RandomUUID()
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(s.Host())
+	return s.exec(
+		`INSERT INTO system.local (key, bootstrapped, broadcast_address, cluster_name, cql_version, data_center,
+			host_id, listen_address, native_port, partitioner, rack, release_version, rpc_address,
+			schema_version, tokens) VALUES ('local', 'COMPLETED', ?, 'triggerx-memory', '3.4.4', 'datacenter1',
+			?, ?, ?, 'org.apache.cassandra.dht.Murmur3Partitioner', 'rack1', ?, ?, ?, {'0'})`,
+		ip, hostID, ip, s.listener.Addr().(*net.TCPAddr).Port, releaseVersion, ip, hostID)
+}
+
+// exec runs a statement on the store with Go values, marshaled with the types of its markers
+func (s *Server) exec(stmt string, values ...interface{}) error {
+	p, err := parse(stmt)
+	if err != nil {
+		return err
+	}
+	var bounds []bound
+	if len(values) > 0 {
+		st, err := s.store.prepare(p, "")
+		if err != nil {
+			return err
+		}
+		for i, v := range values {
+			data, err := gocql.Marshal(st.markers[i].typ.typeInfo(), v)
+			if err != nil {
+				return err
+			}
+			bounds = append(bounds, bound{value: data})
+		}
+	}
+	_, err = s.store.execute(p, "", bounds)
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			_ = conn.Close()
+		}()
+	}
+}
+
+// handle answers the requests of a connection in order, keyspace is set by USE
+func (s *Server) handle(conn net.Conn) {
+	keyspace := ""
+	for {
+		f, err := readFrame(conn)
+		if err != nil {
+			return
+		}
+		var out []byte
+		if f.version != protoVersion {
+			w := &writer{}
+			w.int(errProtocol)
+			w.string(fmt.Sprintf("Invalid or unsupported protocol version (%d); supported versions are (%d/v%d)", f.version, protoVersion, protoVersion))
+			out = w.frame(f.version, f.stream, opError)
+		} else {
+			out = s.respond(f, &keyspace)
+		}
+		if _, err := conn.Write(out); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) respond(f *frame, keyspace *string) []byte {
+	r := &reader{buf: f.body}
+	w := &writer{}
+	opcode := opResult
+	var err error
+	switch f.opcode {
+	case opStartup, opRegister:
+		opcode = opReady
+	case opOptions:
+		opcode = opSupported
+		w.short(2)
+		w.string("CQL_VERSION")
+		w.short(1)
+		w.string("3.4.4")
+		w.string("COMPRESSION")
+		w.short(0)
+	case opQuery:
+		query := r.longString()
+		values := r.queryParameters()
+		if r.err != nil {
+			err = r.err
+			break
+		}
+		err = s.query(w, query, values, keyspace)
+	case opPrepare:
+		query := r.longString()
+		if r.err != nil {
+			err = r.err
+			break
+		}
+		err = s.prepare(w, query, *keyspace)
+	case opExecute:
+		id := r.shortBytes()
+		values := r.queryParameters()
+		if r.err != nil {
+			err = r.err
+			break
+		}
+		s.mu.Lock()
+		p, ok := s.prepared[string(id)]
+		s.mu.Unlock()
+		if !ok {
+			err = &unpreparedError{id: append([]byte{}, id...)}
+			break
+		}
+		err = s.result(w, p, values, *keyspace)
+	case opBatch:
+		err = s.batch(r, *keyspace)
+		if err == nil {
+			w.int(int32(resultVoid))
+		}
+	default:
+		err = &protocolError{msg: fmt.Sprintf("unsupported opcode 0x%02x", f.opcode)}
+	}
+	if err != nil {
+		return errorFrame(err).frame(f.version, f.stream, opError)
+	}
+	return w.frame(f.version, f.stream, opcode)
+}
+
+func (s *Server) query(w *writer, query string, values []bound, keyspace *string) error {
+	p, err := parse(query)
+	if err != nil {
+		return err
+	}
+	if use, ok := p.stmt.(*useStmt); ok {
+		if !s.store.hasKeyspace(use.keyspace) {
+			return invalidf("Keyspace '%s' does not exist", use.keyspace)
+		}
+		*keyspace = use.keyspace
+		w.int(resultSetKeyspace)
+		w.string(use.keyspace)
+		return nil
+	}
+	return s.result(w, p, values, *keyspace)
+}
+
+func (s *Server) result(w *writer, p *parsed, values []bound, keyspace string) error {
+	res, err := s.store.execute(p, keyspace, values)
+	if err != nil {
+		return err
+	}
+	if res.kind == resultRows {
+		w.rows(res)
+		return nil
+	}
+	w.int(int32(resultVoid))
+	return nil
+}
+
+func (s *Server) prepare(w *writer, query, keyspace string) error {
+	p, err := parse(query)
+	if err != nil {
+		return err
+	}
+	st, err := s.store.prepare(p, keyspace)
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum([]byte(keyspace + "\x00" + query))
+	s.mu.Lock()
+	s.prepared[string(sum[:])] = p
+	s.mu.Unlock()
+
+	w.int(resultPrepared)
+	w.shortBytes(sum[:])
+	w.metadata(st.keyspace, st.table, st.markers, st.pkIndexes, true)
+	w.metadata(st.keyspace, st.table, st.columns, nil, false)
+	return nil
+}
+
+// batch reads and runs a BATCH request, its statements are queries or prepared statement IDs
+func (s *Server) batch(r *reader, keyspace string) error {
+	r.byte()
+	n := int(r.short())
+	entries := make([]batchEntry, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		var p *parsed
+		switch kind := r.byte(); kind {
+		case 0:
+			var err error
+			if p, err = parse(r.longString()); err != nil {
+				return err
+			}
+		case 1:
+			id := r.shortBytes()
+			s.mu.Lock()
+			prepared, ok := s.prepared[string(id)]
+			s.mu.Unlock()
+			if !ok {
+				return &unpreparedError{id: append([]byte{}, id...)}
+			}
+			p = prepared
+		default:
+			return &protocolError{msg: fmt.Sprintf("invalid batch statement kind %d", kind)}
+		}
+		count := int(r.short())
+		values := make([]bound, 0, count)
+		for j := 0; j < count; j++ {
+			values = append(values, r.value())
+		}
+		entries = append(entries, batchEntry{parsed: p, values: values})
+	}
+	if r.err != nil {
+		return &protocolError{msg: r.err.Error()}
+	}
+	return s.store.executeBatch(entries, keyspace)
+}
+
+type protocolError struct {
+	msg string
+}
+
+func (e *protocolError) Error() string {
+	return e.msg
+}
+
+type unpreparedError struct {
+	id []byte
+}
+
+func (e *unpreparedError) Error() string {
+	return fmt.Sprintf("Prepared query with ID %x not found", e.id)
+}
+
+// errorFrame encodes err as an ERROR response body
+func errorFrame(err error) *writer {
+	w := &writer{}
+	var (
+		syntax     *syntaxError
+		invalid    *invalidError
+		exists     *alreadyExistsError
+		unprepared *unpreparedError
+		protocol   *protocolError
+	)
+	switch {
+	case errors.As(err, &syntax):
+		w.int(errSyntax)
+		w.string(err.Error())
+	case errors.As(err, &invalid):
+		w.int(errInvalid)
+		w.string(err.Error())
+	case errors.As(err, &exists):
+		w.int(errAlreadyExists)
+		w.string(err.Error())
+		w.string(exists.keyspace)
+		w.string(exists.table)
+	case errors.As(err, &unprepared):
+		w.int(errUnprepared)
+		w.string(err.Error())
+		w.shortBytes(unprepared.id)
+	case errors.As(err, &protocol), errors.Is(err, errShortBody):
+		w.int(errProtocol)
+		w.string(err.Error())
+	default:
+		w.int(errServer)
+		w.string(err.Error())
+	}
+	return w
+}
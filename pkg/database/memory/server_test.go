@@ -0,0 +1,249 @@
+package memory
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trigg3rX/triggerx-backend/internal/dbserver/migrations"
+	"github.com/trigg3rX/triggerx-backend/pkg/database"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+func newSession(t *testing.T) (*Server, *gocql.Session) {
+	t.Helper()
+	server, err := NewServer(logging.NewNoOpLogger(), "triggerx")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+
+	cfg := server.Config("triggerx")
+	cluster := gocql.NewCluster(cfg.Hosts...)
+	cluster.Keyspace = cfg.Keyspace
+	cluster.Timeout = cfg.Timeout
+	cluster.ConnectTimeout = cfg.ConnectWait
+	session, err := cluster.CreateSession()
+	require.NoError(t, err)
+	t.Cleanup(session.Close)
+	return server, session
+}
+
+func TestMigrationsApply(t *testing.T) {
+	_, session := newSession(t)
+
+	loaded, err := database.LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	migrator := database.NewMigrator(session, loaded, database.DefaultMigratorConfig(), logging.NewNoOpLogger())
+
+	applied, err := migrator.Up(context.Background(), false)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(loaded))
+
+	pending, err := migrator.Pending(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestReadWrite(t *testing.T) {
+	_, session := newSession(t)
+	require.NoError(t, session.Query(`CREATE TABLE jobs (
+		job_id varint PRIMARY KEY, title text, task_ids set<bigint>, args list<text>, cost double,
+		active boolean, created_at timestamp)`).Exec())
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, session.Query(`INSERT INTO triggerx.jobs (job_id, title, task_ids, args, cost, active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, big.NewInt(7), "job", []int64{3, 1, 3}, []string{"b", "a"}, 1.5, true, createdAt).Exec())
+
+	var (
+		title   string
+		taskIDs []int64
+		args    []string
+		cost    float64
+		active  bool
+		created time.Time
+	)
+	require.NoError(t, session.Query(`SELECT title, task_ids, args, cost, active, created_at FROM jobs WHERE job_id = ?`, big.NewInt(7)).
+		Scan(&title, &taskIDs, &args, &cost, &active, &created))
+	assert.Equal(t, "job", title)
+	assert.Equal(t, []int64{1, 3}, taskIDs)
+	assert.Equal(t, []string{"b", "a"}, args)
+	assert.Equal(t, 1.5, cost)
+	assert.True(t, active)
+	assert.True(t, createdAt.Equal(created))
+
+	require.NoError(t, session.Query(`UPDATE jobs SET title = null, active = false WHERE job_id = ?`, big.NewInt(7)).Exec())
+	require.NoError(t, session.Query(`SELECT title, active FROM jobs WHERE job_id = ?`, big.NewInt(7)).Scan(&title, &active))
+	assert.Empty(t, title)
+	assert.False(t, active)
+
+	var maxID *big.Int
+	require.NoError(t, session.Query(`INSERT INTO jobs (job_id, title) VALUES (?, ?)`, big.NewInt(12), "other").Exec())
+	require.NoError(t, session.Query(`SELECT MAX(job_id) FROM jobs`).Scan(&maxID))
+	assert.Equal(t, int64(12), maxID.Int64())
+
+	var ids []int64
+	iter := session.Query(`SELECT job_id FROM jobs WHERE job_id IN ?`, []*big.Int{big.NewInt(12), big.NewInt(7), big.NewInt(99)}).Iter()
+	var id int64
+	for iter.Scan(&id) {
+		ids = append(ids, id)
+	}
+	require.NoError(t, iter.Close())
+	assert.Equal(t, []int64{12, 7}, ids)
+
+	require.NoError(t, session.Query(`DELETE FROM jobs WHERE job_id = ?`, big.NewInt(7)).Exec())
+	assert.ErrorIs(t, session.Query(`SELECT title FROM jobs WHERE job_id = ?`, big.NewInt(7)).Scan(&title), gocql.ErrNotFound)
+}
+
+func TestClusteringOrderAndRanges(t *testing.T) {
+	_, session := newSession(t)
+	require.NoError(t, session.Query(`CREATE TABLE tasks_by_job (job_id bigint, created_at timestamp, task_id bigint,
+		PRIMARY KEY ((job_id), created_at, task_id)) WITH CLUSTERING ORDER BY (created_at DESC, task_id DESC)`).Exec())
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	batch := session.NewBatch(gocql.LoggedBatch)
+	for i := int64(1); i <= 4; i++ {
+		batch.Query(`INSERT INTO tasks_by_job (job_id, created_at, task_id) VALUES (?, ?, ?)`, 1, base.Add(time.Duration(i)*time.Minute), i)
+	}
+	batch.Query(`INSERT INTO tasks_by_job (job_id, created_at, task_id) VALUES (?, ?, ?)`, 2, base, 9)
+	require.NoError(t, session.ExecuteBatch(batch))
+
+	scan := func(stmt string, values ...interface{}) []int64 {
+		var ids []int64
+		iter := session.Query(stmt, values...).Iter()
+		var id int64
+		for iter.Scan(&id) {
+			ids = append(ids, id)
+		}
+		require.NoError(t, iter.Close())
+		return ids
+	}
+	assert.Equal(t, []int64{4, 3, 2, 1}, scan(`SELECT task_id FROM tasks_by_job WHERE job_id = ?`, 1))
+	assert.Equal(t, []int64{3, 2}, scan(`SELECT task_id FROM tasks_by_job WHERE job_id = ? AND created_at >= ? AND created_at < ?`,
+		1, base.Add(2*time.Minute), base.Add(4*time.Minute)))
+	assert.Equal(t, []int64{4, 3}, scan(`SELECT task_id FROM tasks_by_job WHERE job_id = ? LIMIT ?`, 1, 2))
+	assert.Len(t, scan(`SELECT task_id FROM tasks_by_job`), 5)
+
+	assert.Equal(t, []int64{4, 3, 2, 1}, scan(`SELECT task_id FROM tasks_by_job WHERE job_id = ? ORDER BY created_at DESC, task_id DESC`, 1))
+	assert.Equal(t, []int64{9, 1, 2}, scan(`SELECT task_id FROM tasks_by_job WHERE job_id IN (?, ?) ORDER BY created_at ASC, task_id ASC LIMIT ?`, 1, 2, 3))
+	assert.Error(t, session.Query(`SELECT task_id FROM tasks_by_job ORDER BY created_at ASC`).Exec())
+	assert.Error(t, session.Query(`SELECT task_id FROM tasks_by_job WHERE job_id = ? ORDER BY created_at ASC, task_id DESC`, 1).Exec())
+}
+
+func TestTokenPaging(t *testing.T) {
+	_, session := newSession(t)
+	require.NoError(t, session.Query(`CREATE TABLE pins (cid text PRIMARY KEY, size_bytes bigint)`).Exec())
+	for _, cid := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, session.Query(`INSERT INTO pins (cid, size_bytes) VALUES (?, ?)`, cid, 1).Exec())
+	}
+
+	seen := map[string]bool{}
+	last := ""
+	for {
+		stmt, values := `SELECT cid FROM pins LIMIT ?`, []interface{}{2}
+		if last != "" {
+			stmt, values = `SELECT cid FROM pins WHERE token(cid) > token(?) LIMIT ?`, []interface{}{last, 2}
+		}
+		iter := session.Query(stmt, values...).Iter()
+		var cid string
+		n := 0
+		for iter.Scan(&cid) {
+			assert.False(t, seen[cid], "cid %s read twice", cid)
+			seen[cid], last = true, cid
+			n++
+		}
+		require.NoError(t, iter.Close())
+		if n < 2 {
+			break
+		}
+	}
+	assert.Len(t, seen, 5)
+}
+
+func TestLightweightTransactions(t *testing.T) {
+	_, session := newSession(t)
+	require.NoError(t, session.Query(`CREATE TABLE users_by_address (user_address text PRIMARY KEY, user_id bigint)`).Exec())
+	require.NoError(t, session.Query(`CREATE TABLE job_data (job_id varint PRIMARY KEY, user_id bigint, status text)`).Exec())
+
+	var existingAddress string
+	var existingID int64
+	applied, err := session.Query(`INSERT INTO users_by_address (user_address, user_id) VALUES (?, ?) IF NOT EXISTS`, "0xa", 1).
+		ScanCAS(&existingAddress, &existingID)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	applied, err = session.Query(`INSERT INTO users_by_address (user_address, user_id) VALUES (?, ?) IF NOT EXISTS`, "0xa", 2).
+		ScanCAS(&existingAddress, &existingID)
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, "0xa", existingAddress)
+	assert.Equal(t, int64(1), existingID)
+
+	applied, err = session.Query(`DELETE FROM users_by_address WHERE user_address = ? IF user_id = ?`, "0xa", 2).ScanCAS(&existingID)
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, int64(1), existingID)
+
+	var status string
+	require.NoError(t, session.Query(`INSERT INTO job_data (job_id, user_id, status) VALUES (?, ?, ?)`, big.NewInt(1), 1, "running").Exec())
+	applied, err = session.Query(`UPDATE job_data SET status = ? WHERE job_id = ? IF status = ?`, "paused", big.NewInt(1), "pending").ScanCAS(&status)
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, "running", status)
+
+	applied, err = session.Query(`UPDATE job_data SET status = ? WHERE job_id = ? IF status = ?`, "paused", big.NewInt(1), "running").ScanCAS(&status)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	existing := map[string]interface{}{}
+	applied, err = session.Query(`INSERT INTO job_data (job_id, user_id) VALUES (?, ?) IF NOT EXISTS`, big.NewInt(1), 2).MapScanCAS(existing)
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, "paused", existing["status"])
+}
+
+func TestTTL(t *testing.T) {
+	server, session := newSession(t)
+	var now atomic.Int64
+	now.Store(time.Now().UnixNano())
+	server.store.mu.Lock()
+	server.store.now = func() time.Time { return time.Unix(0, now.Load()) }
+	server.store.mu.Unlock()
+	require.NoError(t, session.Query(`CREATE TABLE locks (name text PRIMARY KEY, owner text)`).Exec())
+	require.NoError(t, session.Query(`INSERT INTO locks (name, owner) VALUES (?, ?) USING TTL ?`, "migrate", "a", 60).Exec())
+
+	var owner string
+	require.NoError(t, session.Query(`SELECT owner FROM locks WHERE name = ?`, "migrate").Scan(&owner))
+	assert.Equal(t, "a", owner)
+
+	now.Add(int64(61 * time.Second))
+	assert.ErrorIs(t, session.Query(`SELECT owner FROM locks WHERE name = ?`, "migrate").Scan(&owner), gocql.ErrNotFound)
+}
+
+func TestErrors(t *testing.T) {
+	server, session := newSession(t)
+	require.NoError(t, session.Query(`CREATE TABLE t (id int PRIMARY KEY, v text)`).Exec())
+
+	err := session.Query(`CREATE TABLE t (id int PRIMARY KEY)`).Exec()
+	var exists *gocql.RequestErrAlreadyExists
+	assert.ErrorAs(t, err, &exists)
+
+	var v string
+	err = session.Query(`SELECT missing FROM t WHERE id = ?`, 1).Scan(&v)
+	var invalid gocql.RequestError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, gocql.ErrCodeInvalid, invalid.Code())
+
+	err = session.Query(`UPDATE t SET v = ?, WHERE id = ?`, "a", 1).Exec()
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, gocql.ErrCodeSyntax, invalid.Code())
+
+	require.NoError(t, server.Reset())
+	err = session.Query(`SELECT v FROM t WHERE id = ?`, 1).Scan(&v)
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, gocql.ErrCodeInvalid, invalid.Code())
+}
@@ -0,0 +1,416 @@
+package memory
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+type columnKind int
+
+const (
+	partitionKeyColumn columnKind = iota
+	clusteringColumn
+	regularColumn
+)
+
+type column struct {
+	name     string
+	typ      *cqlType
+	kind     columnKind
+	position int
+}
+
+type table struct {
+	keyspace, name string
+	columns        map[string]*column
+	partitionKey   []*column
+	clustering     []*column
+	descending     []bool
+	regular        []*column
+	indexes        map[string]bool
+	defaultTTL     int
+	partitions     map[string]*partition
+}
+
+// selectAll lists the columns of SELECT *: the partition key, the clustering columns, then
+// the other columns by name
+func (t *table) selectAll() []*column {
+	all := append([]*column{}, t.partitionKey...)
+	all = append(all, t.clustering...)
+	return append(all, t.regular...)
+}
+
+func (t *table) addRegular(name string, typ *cqlType) {
+	c := &column{name: name, typ: typ, kind: regularColumn}
+	t.columns[name] = c
+	t.regular = append(t.regular, c)
+	sort.Slice(t.regular, func(i, j int) bool { return t.regular[i].name < t.regular[j].name })
+}
+
+// compareClustering orders rows of a partition by the clustering order of the table
+func (t *table) compareClustering(a, b [][]byte) int {
+	for i, c := range t.clustering {
+		if cmp := c.typ.compare(a[i], b[i]); cmp != 0 {
+			if t.descending[i] {
+				return -cmp
+			}
+			return cmp
+		}
+	}
+	return 0
+}
+
+type partition struct {
+	key   [][]byte
+	token int64
+	rows  []*row
+}
+
+type cell struct {
+	value   []byte
+	expires time.Time
+}
+
+func (c cell) live(now time.Time) bool {
+	return c.expires.IsZero() || now.Before(c.expires)
+}
+
+// row is a CQL row, it exists while its INSERT marker or one of its cells is live
+type row struct {
+	clustering    [][]byte
+	marker        bool
+	markerExpires time.Time
+	cells         map[string]cell
+}
+
+func (r *row) live(now time.Time) bool {
+	if r.marker && (r.markerExpires.IsZero() || now.Before(r.markerExpires)) {
+		return true
+	}
+	for _, c := range r.cells {
+		if c.live(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// value is the value of a column in the row of p, nil when null or expired
+func (r *row) value(p *partition, c *column, now time.Time) []byte {
+	switch c.kind {
+	case partitionKeyColumn:
+		return p.key[c.position]
+	case clusteringColumn:
+		return r.clustering[c.position]
+	}
+	if v, ok := r.cells[c.name]; ok && v.live(now) {
+		return v.value
+	}
+	return nil
+}
+
+// find returns the row of p with the clustering values and where it is or would be inserted
+func (p *partition) find(t *table, clustering [][]byte) (*row, int) {
+	i := sort.Search(len(p.rows), func(i int) bool {
+		return t.compareClustering(p.rows[i].clustering, clustering) >= 0
+	})
+	if i < len(p.rows) && t.compareClustering(p.rows[i].clustering, clustering) == 0 {
+		return p.rows[i], i
+	}
+	return nil, i
+}
+
+func (p *partition) insert(at int, r *row) {
+	p.rows = append(p.rows, nil)
+	copy(p.rows[at+1:], p.rows[at:])
+	p.rows[at] = r
+}
+
+// partitionID is the map key of a partition key
+func partitionID(key [][]byte) string {
+	var b []byte
+	for _, v := range key {
+		b = binary.BigEndian.AppendUint32(b, uint32(len(v)))
+		b = append(b, v...)
+	}
+	return string(b)
+}
+
+// tokenOf orders partitions in scans and token() relations
+func tokenOf(key [][]byte) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(partitionID(key)))
+	return int64(h.Sum64())
+}
+
+// store holds the keyspaces of a server, a single lock serializes every statement
+type store struct {
+	mu        sync.Mutex
+	keyspaces map[string]map[string]*table
+	now       func() time.Time
+}
+
+func newStore() *store {
+	return &store{
+		keyspaces: map[string]map[string]*table{},
+		now:       time.Now,
+	}
+}
+
+func (s *store) table(keyspace, name string) (*table, error) {
+	tables, ok := s.keyspaces[keyspace]
+	if !ok {
+		return nil, invalidf("Keyspace %s does not exist", keyspace)
+	}
+	t, ok := tables[name]
+	if !ok {
+		return nil, invalidf("unconfigured table %s", name)
+	}
+	return t, nil
+}
+
+func (s *store) createTable(keyspace string, stmt *createTableStmt) error {
+	tables, ok := s.keyspaces[keyspace]
+	if !ok {
+		return invalidf("Keyspace %s does not exist", keyspace)
+	}
+	if _, ok := tables[stmt.table]; ok {
+		if stmt.ifNotExists {
+			return nil
+		}
+		return &alreadyExistsError{keyspace: keyspace, table: stmt.table}
+	}
+	t := &table{
+		keyspace:   keyspace,
+		name:       stmt.table,
+		columns:    map[string]*column{},
+		indexes:    map[string]bool{},
+		defaultTTL: stmt.defaultTTL,
+		partitions: map[string]*partition{},
+	}
+	types := map[string]*cqlType{}
+	for _, def := range stmt.columns {
+		if _, ok := types[def.name]; ok {
+			return invalidf("Multiple definition of identifier %s", def.name)
+		}
+		types[def.name] = def.typ
+	}
+	for i, name := range stmt.partitionKey {
+		typ, ok := types[name]
+		if !ok {
+			return invalidf("Unknown definition %s referenced in PRIMARY KEY", name)
+		}
+		c := &column{name: name, typ: typ, kind: partitionKeyColumn, position: i}
+		t.columns[name] = c
+		t.partitionKey = append(t.partitionKey, c)
+	}
+	for i, name := range stmt.clustering {
+		typ, ok := types[name]
+		if !ok {
+			return invalidf("Unknown definition %s referenced in PRIMARY KEY", name)
+		}
+		c := &column{name: name, typ: typ, kind: clusteringColumn, position: i}
+		t.columns[name] = c
+		t.clustering = append(t.clustering, c)
+		t.descending = append(t.descending, stmt.descending[name])
+	}
+	for name := range stmt.descending {
+		if c, ok := t.columns[name]; !ok || c.kind != clusteringColumn {
+			return invalidf("Only clustering key columns can be defined in CLUSTERING ORDER directive: %s", name)
+		}
+	}
+	for _, def := range stmt.columns {
+		if _, ok := t.columns[def.name]; !ok {
+			t.addRegular(def.name, def.typ)
+		}
+	}
+	tables[stmt.table] = t
+	return nil
+}
+
+func (s *store) createIndex(keyspace string, stmt *createIndexStmt) error {
+	t, err := s.table(keyspace, stmt.table)
+	if err != nil {
+		return err
+	}
+	c, ok := t.columns[stmt.column]
+	if !ok {
+		return invalidf("No column definition found for column %s", stmt.column)
+	}
+	if c.kind == partitionKeyColumn && len(t.partitionKey) == 1 {
+		return invalidf("Cannot create secondary index on the only partition key column %s", c.name)
+	}
+	if t.indexes[c.name] && !stmt.ifNotExists {
+		return invalidf("Index on %s already exists", c.name)
+	}
+	// Restrictions on any column are served by scanning, the index only records the column
+	t.indexes[c.name] = true
+	return nil
+}
+
+func (s *store) alterTable(keyspace string, stmt *alterTableStmt) error {
+	t, err := s.table(keyspace, stmt.table)
+	if err != nil {
+		return err
+	}
+	for _, def := range stmt.add {
+		if _, ok := t.columns[def.name]; ok {
+			return invalidf("Invalid column name %s because it conflicts with an existing column", def.name)
+		}
+	}
+	for _, def := range stmt.add {
+		t.addRegular(def.name, def.typ)
+	}
+	return nil
+}
+
+// sortedPartitions lists the partitions of t in token order
+func (t *table) sortedPartitions() []*partition {
+	partitions := make([]*partition, 0, len(t.partitions))
+	for _, p := range t.partitions {
+		partitions = append(partitions, p)
+	}
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].token != partitions[j].token {
+			return partitions[i].token < partitions[j].token
+		}
+		return partitionID(partitions[i].key) < partitionID(partitions[j].key)
+	})
+	return partitions
+}
+
+// alreadyExistsError is reported to the client with the AlreadyExists code
+type alreadyExistsError struct {
+	keyspace, table string
+}
+
+func (e *alreadyExistsError) Error() string {
+	return "Cannot add already existing table \"" + e.table + "\" to keyspace \"" + e.keyspace + "\""
+}
+
+// prepare resolves a statement as a PREPARE request does
+func (s *store) prepare(p *parsed, keyspace string) (*statement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resolve(p, keyspace)
+}
+
+// execute runs a statement, keyspace is the one of the connection
+func (s *store) execute(p *parsed, keyspace string, values []bound) (*result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.executeLocked(p, keyspace, values)
+}
+
+// batchEntry is a statement of a BATCH request
+type batchEntry struct {
+	parsed *parsed
+	values []bound
+}
+
+// executeBatch runs the statements of a batch together, conditional statements are not
+// supported in batches
+func (s *store) executeBatch(entries []batchEntry, keyspace string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range entries {
+		switch stmt := entry.parsed.stmt.(type) {
+		case *insertStmt:
+			if stmt.ifNotExists {
+				return invalidf("Conditional statements are not supported in batches")
+			}
+		case *updateStmt:
+			if stmt.ifExists || len(stmt.conditions) > 0 {
+				return invalidf("Conditional statements are not supported in batches")
+			}
+		case *deleteStmt:
+			if stmt.ifExists || len(stmt.conditions) > 0 {
+				return invalidf("Conditional statements are not supported in batches")
+			}
+		default:
+			return invalidf("Only INSERT, UPDATE and DELETE statements are allowed in batches")
+		}
+	}
+	for _, entry := range entries {
+		if _, err := s.executeLocked(entry.parsed, keyspace, entry.values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *store) executeLocked(p *parsed, keyspace string, values []bound) (*result, error) {
+	void := &result{kind: resultVoid}
+	qualify := func(ks string) string {
+		if ks == "" {
+			return keyspace
+		}
+		return ks
+	}
+	switch stmt := p.stmt.(type) {
+	case *createKeyspaceStmt:
+		if _, ok := s.keyspaces[stmt.keyspace]; ok {
+			if stmt.ifNotExists {
+				return void, nil
+			}
+			return nil, &alreadyExistsError{keyspace: stmt.keyspace}
+		}
+		s.keyspaces[stmt.keyspace] = map[string]*table{}
+		return void, nil
+	case *createTableStmt:
+		return void, s.createTable(qualify(stmt.keyspace), stmt)
+	case *createIndexStmt:
+		return void, s.createIndex(qualify(stmt.keyspace), stmt)
+	case *alterTableStmt:
+		return void, s.alterTable(qualify(stmt.keyspace), stmt)
+	case *dropTableStmt:
+		ks := qualify(stmt.keyspace)
+		if _, err := s.table(ks, stmt.table); err != nil {
+			if stmt.ifExists {
+				return void, nil
+			}
+			return nil, err
+		}
+		delete(s.keyspaces[ks], stmt.table)
+		return void, nil
+	case *truncateStmt:
+		t, err := s.table(qualify(stmt.keyspace), stmt.table)
+		if err != nil {
+			return nil, err
+		}
+		t.partitions = map[string]*partition{}
+		return void, nil
+	case *useStmt:
+		return nil, invalidf("USE is not allowed in this context")
+	}
+
+	st, err := s.resolve(p, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(st.markers) {
+		return nil, invalidf("There were %d markers(?) in CQL but %d bound variables", len(st.markers), len(values))
+	}
+	e := &execution{st: st, values: values, now: s.now()}
+	switch stmt := p.stmt.(type) {
+	case *selectStmt:
+		return e.selectRows(stmt)
+	case *insertStmt:
+		return e.insert(stmt)
+	case *updateStmt:
+		return e.update(stmt)
+	case *deleteStmt:
+		return e.delete(stmt)
+	}
+	return nil, invalidf("unsupported statement")
+}
+
+func (s *store) hasKeyspace(keyspace string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.keyspaces[keyspace]
+	return ok
+}
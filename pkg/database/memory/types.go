@@ -0,0 +1,331 @@
+package memory
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// protoVersion is the native protocol version the server speaks
+const protoVersion = 4
+
+// cqlType is a column type, elem is set for list and set
+type cqlType struct {
+	id   gocql.Type
+	elem *cqlType
+}
+
+var nativeTypes = map[string]gocql.Type{
+	"ascii":     gocql.TypeAscii,
+	"bigint":    gocql.TypeBigInt,
+	"blob":      gocql.TypeBlob,
+	"boolean":   gocql.TypeBoolean,
+	"counter":   gocql.TypeCounter,
+	"date":      gocql.TypeDate,
+	"double":    gocql.TypeDouble,
+	"float":     gocql.TypeFloat,
+	"inet":      gocql.TypeInet,
+	"int":       gocql.TypeInt,
+	"smallint":  gocql.TypeSmallInt,
+	"text":      gocql.TypeVarchar,
+	"timestamp": gocql.TypeTimestamp,
+	"timeuuid":  gocql.TypeTimeUUID,
+	"tinyint":   gocql.TypeTinyInt,
+	"uuid":      gocql.TypeUUID,
+	"varchar":   gocql.TypeVarchar,
+	"varint":    gocql.TypeVarint,
+}
+
+func nativeType(id gocql.Type) *cqlType {
+	return &cqlType{id: id}
+}
+
+func (t *cqlType) isCollection() bool {
+	return t.id == gocql.TypeList || t.id == gocql.TypeSet
+}
+
+// typeInfo is the gocql type of t, used to marshal literals and to read collections
+func (t *cqlType) typeInfo() gocql.TypeInfo {
+	if t.isCollection() {
+		return gocql.CollectionType{
+			NativeType: gocql.NewNativeType(protoVersion, t.id, ""),
+			Elem:       t.elem.typeInfo(),
+		}
+	}
+	return gocql.NewNativeType(protoVersion, t.id, "")
+}
+
+func (t *cqlType) String() string {
+	switch t.id {
+	case gocql.TypeList:
+		return "list<" + t.elem.String() + ">"
+	case gocql.TypeSet:
+		return "set<" + t.elem.String() + ">"
+	case gocql.TypeVarchar:
+		return "text"
+	}
+	return t.id.String()
+}
+
+// encodeOption writes t as a protocol [option]
+func (t *cqlType) encodeOption(w *writer) {
+	w.short(uint16(t.id))
+	if t.isCollection() {
+		t.elem.encodeOption(w)
+	}
+}
+
+// splitCollection returns the serialized elements of a list or set value
+func splitCollection(v []byte) ([][]byte, error) {
+	if len(v) < 4 {
+		return nil, fmt.Errorf("collection of %d bytes", len(v))
+	}
+	n := int(int32(binary.BigEndian.Uint32(v)))
+	v = v[4:]
+	elems := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		if len(v) < 4 {
+			return nil, fmt.Errorf("collection truncated at element %d", i)
+		}
+		size := int(int32(binary.BigEndian.Uint32(v)))
+		v = v[4:]
+		if size < 0 {
+			elems = append(elems, nil)
+			continue
+		}
+		if len(v) < size {
+			return nil, fmt.Errorf("collection truncated at element %d", i)
+		}
+		elems = append(elems, v[:size])
+		v = v[size:]
+	}
+	return elems, nil
+}
+
+func joinCollection(elems [][]byte) []byte {
+	var b bytes.Buffer
+	_ = binary.Write(&b, binary.BigEndian, int32(len(elems)))
+	for _, e := range elems {
+		_ = binary.Write(&b, binary.BigEndian, int32(len(e)))
+		b.Write(e)
+	}
+	return b.Bytes()
+}
+
+// normalize brings a written value to its stored form: sets are sorted without duplicates
+// and empty collections are null, as Scylla stores them
+func (t *cqlType) normalize(v []byte) ([]byte, error) {
+	if v == nil || !t.isCollection() {
+		return v, nil
+	}
+	elems, err := splitCollection(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(elems) == 0 {
+		return nil, nil
+	}
+	if t.id == gocql.TypeSet {
+		sort.SliceStable(elems, func(i, j int) bool { return t.elem.compare(elems[i], elems[j]) < 0 })
+		unique := elems[:1]
+		for _, e := range elems[1:] {
+			if t.elem.compare(unique[len(unique)-1], e) != 0 {
+				unique = append(unique, e)
+			}
+		}
+		elems = unique
+	}
+	return joinCollection(elems), nil
+}
+
+// compare orders two serialized values of t, null sorts first
+func (t *cqlType) compare(a, b []byte) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	switch t.id {
+	case gocql.TypeBigInt, gocql.TypeCounter, gocql.TypeTimestamp, gocql.TypeInt,
+		gocql.TypeSmallInt, gocql.TypeTinyInt:
+		return compareInts(signedInt(a), signedInt(b))
+	case gocql.TypeVarint:
+		return varint(a).Cmp(varint(b))
+	case gocql.TypeDate:
+		return compareInts(int64(binary.BigEndian.Uint32(pad(a, 4))), int64(binary.BigEndian.Uint32(pad(b, 4))))
+	case gocql.TypeDouble:
+		return compareFloats(math.Float64frombits(binary.BigEndian.Uint64(pad(a, 8))), math.Float64frombits(binary.BigEndian.Uint64(pad(b, 8))))
+	case gocql.TypeFloat:
+		return compareFloats(float64(math.Float32frombits(binary.BigEndian.Uint32(pad(a, 4)))), float64(math.Float32frombits(binary.BigEndian.Uint32(pad(b, 4)))))
+	case gocql.TypeTimeUUID:
+		ua, errA := gocql.UUIDFromBytes(a)
+		ub, errB := gocql.UUIDFromBytes(b)
+		if errA == nil && errB == nil {
+			if c := compareInts(ua.Timestamp(), ub.Timestamp()); c != 0 {
+				return c
+			}
+		}
+		return bytes.Compare(a, b)
+	case gocql.TypeList, gocql.TypeSet:
+		ea, errA := splitCollection(a)
+		eb, errB := splitCollection(b)
+		if errA != nil || errB != nil {
+			return bytes.Compare(a, b)
+		}
+		for i := 0; i < len(ea) && i < len(eb); i++ {
+			if c := t.elem.compare(ea[i], eb[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInts(int64(len(ea)), int64(len(eb)))
+	}
+	return bytes.Compare(a, b)
+}
+
+func pad(v []byte, n int) []byte {
+	if len(v) >= n {
+		return v[:n]
+	}
+	return append(make([]byte, n-len(v)), v...)
+}
+
+func signedInt(v []byte) int64 {
+	var n int64
+	for i, c := range v {
+		if i == 0 {
+			n = int64(int8(c))
+			continue
+		}
+		n = n<<8 | int64(c)
+	}
+	return n
+}
+
+func varint(v []byte) *big.Int {
+	n := new(big.Int).SetBytes(v)
+	if len(v) > 0 && v[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(v)*8)))
+	}
+	return n
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// literal is a constant written in a statement
+type literal struct {
+	kind  tokenKind
+	text  string
+	elems []literal
+}
+
+// encodeLiteral serializes a constant as a value of t
+func (t *cqlType) encodeLiteral(l literal) ([]byte, error) {
+	if l.kind == tokNull {
+		return nil, nil
+	}
+	var value interface{}
+	switch l.kind {
+	case tokString:
+		switch t.id {
+		case gocql.TypeUUID, gocql.TypeTimeUUID:
+			u, err := gocql.ParseUUID(l.text)
+			if err != nil {
+				return nil, err
+			}
+			value = u
+		case gocql.TypeTimestamp:
+			ts, err := time.Parse(time.RFC3339Nano, l.text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timestamp literal '%s'", l.text)
+			}
+			value = ts
+		case gocql.TypeDate:
+			d, err := time.Parse("2006-01-02", l.text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date literal '%s'", l.text)
+			}
+			value = d
+		default:
+			value = l.text
+		}
+	case tokNumber:
+		switch t.id {
+		case gocql.TypeDouble, gocql.TypeFloat:
+			f, err := strconv.ParseFloat(l.text, 64)
+			if err != nil {
+				return nil, err
+			}
+			value = f
+		case gocql.TypeVarint:
+			n, ok := new(big.Int).SetString(l.text, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid integer literal %s", l.text)
+			}
+			value = n
+		default:
+			n, err := strconv.ParseInt(l.text, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			value = n
+		}
+	case tokIdent:
+		switch strings.ToLower(l.text) {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		default:
+			return nil, fmt.Errorf("invalid constant %s", l.text)
+		}
+	case tokLBrace, tokLBracket:
+		if !t.isCollection() {
+			return nil, fmt.Errorf("invalid collection literal for %s", t)
+		}
+		elems := make([][]byte, 0, len(l.elems))
+		for _, e := range l.elems {
+			v, err := t.elem.encodeLiteral(e)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, v)
+		}
+		return t.normalize(joinCollection(elems))
+	default:
+		return nil, fmt.Errorf("invalid constant %s", l.text)
+	}
+	v, err := gocql.Marshal(t.typeInfo(), value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constant %s for type %s: %v", l.text, t, err)
+	}
+	return v, nil
+}
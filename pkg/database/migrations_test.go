@@ -0,0 +1,102 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	testCases := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "Single Statement Without Semicolon",
+			script: "CREATE TABLE t (id int PRIMARY KEY)",
+			want:   []string{"CREATE TABLE t (id int PRIMARY KEY)"},
+		},
+		{
+			name:   "Multiple Statements",
+			script: "ALTER TABLE t ADD a int;\nALTER TABLE t ADD b int;\n",
+			want:   []string{"ALTER TABLE t ADD a int", "ALTER TABLE t ADD b int"},
+		},
+		{
+			name:   "Line Comments",
+			script: "-- header; not a statement\nCREATE INDEX ON t (a); // trailing; comment\n",
+			want:   []string{"CREATE INDEX ON t (a)"},
+		},
+		{
+			name:   "Block Comment",
+			script: "/* drop; nothing */ CREATE INDEX ON t (a);",
+			want:   []string{"CREATE INDEX ON t (a)"},
+		},
+		{
+			name:   "Semicolon In String Literal",
+			script: "INSERT INTO t (id, v) VALUES (1, 'a;b');INSERT INTO t (id, v) VALUES (2, 'it''s');",
+			want:   []string{"INSERT INTO t (id, v) VALUES (1, 'a;b')", "INSERT INTO t (id, v) VALUES (2, 'it''s')"},
+		},
+		{
+			name:   "Only Comments",
+			script: "-- nothing to do\n",
+			want:   nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SplitStatements(tc.script)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSplitStatementsUnterminated(t *testing.T) {
+	_, err := SplitStatements("CREATE TABLE t (id int PRIMARY KEY) /* no end")
+	assert.Error(t, err)
+
+	_, err = SplitStatements("INSERT INTO t (v) VALUES ('no end)")
+	assert.Error(t, err)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_later.cql":   {Data: []byte("ALTER TABLE t ADD b int;")},
+		"002_second.cql":  {Data: []byte("ALTER TABLE t ADD a int;")},
+		"001_initial.cql": {Data: []byte("CREATE TABLE t (id int PRIMARY KEY);\nCREATE INDEX ON t (id);")},
+		"README.md":       {Data: []byte("not a migration")},
+	}
+
+	migrations, err := LoadMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+
+	assert.Equal(t, []int{1, 2, 10}, []int{migrations[0].Version, migrations[1].Version, migrations[2].Version})
+	assert.Equal(t, "initial", migrations[0].Name)
+	assert.Len(t, migrations[0].Statements, 2)
+	assert.Len(t, migrations[0].Checksum, 64)
+	assert.NotEqual(t, migrations[1].Checksum, migrations[2].Checksum)
+
+	// The checksum covers the whole file, comments included
+	fsys["002_second.cql"] = &fstest.MapFile{Data: []byte("-- edited\nALTER TABLE t ADD a int;")}
+	edited, err := LoadMigrations(fsys)
+	require.NoError(t, err)
+	assert.NotEqual(t, migrations[1].Checksum, edited[1].Checksum)
+}
+
+func TestLoadMigrationsInvalid(t *testing.T) {
+	_, err := LoadMigrations(fstest.MapFS{
+		"001_a.cql":  {Data: []byte("CREATE TABLE a (id int PRIMARY KEY);")},
+		"0001_b.cql": {Data: []byte("CREATE TABLE b (id int PRIMARY KEY);")},
+	})
+	assert.ErrorContains(t, err, "share version 1")
+
+	_, err = LoadMigrations(fstest.MapFS{
+		"001_empty.cql": {Data: []byte("-- nothing yet\n")},
+	})
+	assert.ErrorContains(t, err, "no statements")
+}
@@ -0,0 +1,344 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+const (
+	createSchemaMigrationsTable = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version int PRIMARY KEY,
+			name text,
+			checksum text,
+			applied_at timestamp,
+			execution_ms bigint
+		)`
+
+	createSchemaMigrationsLockTable = `
+		CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+			name text PRIMARY KEY,
+			owner text,
+			acquired_at timestamp
+		)`
+
+	selectAppliedMigrations = `SELECT version, name, checksum, applied_at FROM schema_migrations`
+
+	insertAppliedMigration = `
+		INSERT INTO schema_migrations (version, name, checksum, applied_at, execution_ms)
+		VALUES (?, ?, ?, ?, ?)`
+
+	acquireMigrationLock = `
+		INSERT INTO schema_migrations_lock (name, owner, acquired_at)
+		VALUES (?, ?, ?) IF NOT EXISTS USING TTL ?`
+
+	refreshMigrationLock = `
+		UPDATE schema_migrations_lock USING TTL ?
+		SET acquired_at = ?
+		WHERE name = ? IF owner = ?`
+
+	releaseMigrationLock = `
+		DELETE FROM schema_migrations_lock
+		WHERE name = ? IF owner = ?`
+
+	// migrationLockName is the row of the lock table every migrator contends for
+	migrationLockName = "migrate"
+)
+
+var (
+	// ErrChecksumMismatch is returned when an applied migration was edited afterwards
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	// ErrMigrationLocked is returned when another instance holds the migration lock for longer
+	// than the migrator waits
+	ErrMigrationLocked = errors.New("migrations are locked by another instance")
+)
+
+// schemaAgreementAwaiter is implemented by gocql sessions, migrators of other sessions do not
+// wait for schema agreement
+type schemaAgreementAwaiter interface {
+	AwaitSchemaAgreement(ctx context.Context) error
+}
+
+// MigrationStatus is a migration and whether it is applied
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when the migration's checksum differs from the applied one
+	Modified bool
+}
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// MigratorConfig tunes the migration lock
+type MigratorConfig struct {
+	// LockTTL expires the lock of an instance that died while migrating
+	LockTTL time.Duration
+	// LockWait is how long to wait for another instance's lock before giving up
+	LockWait time.Duration
+	// LockPollInterval is how often a waiting instance retries the lock
+	LockPollInterval time.Duration
+}
+
+// DefaultMigratorConfig returns the lock settings used when none are given
+func DefaultMigratorConfig() MigratorConfig {
+	return MigratorConfig{
+		LockTTL:          5 * time.Minute,
+		LockWait:         10 * time.Minute,
+		LockPollInterval: 2 * time.Second,
+	}
+}
+
+// Migrator applies migrations in version order to the keyspace of its session, recording
+// every applied migration with its checksum in schema_migrations. A lightweight transaction
+// on schema_migrations_lock ensures a single instance migrates at a time.
+type Migrator struct {
+	session    Sessioner
+	migrations []Migration
+	config     MigratorConfig
+	owner      string
+	logger     logging.Logger
+}
+
+// NewMigrator creates a migrator of the given migrations, as loaded by LoadMigrations
+func NewMigrator(session Sessioner, migrations []Migration, config MigratorConfig, logger logging.Logger) *Migrator {
+	owner := make([]byte, 8)
+	_, _ = rand.Read(owner)
+	return &Migrator{
+		session:    session,
+		migrations: migrations,
+		config:     config,
+		owner:      hex.EncodeToString(owner),
+		logger:     logger,
+	}
+}
+
+// Status returns every migration and whether it is applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.appliedAt
+			status.Modified = row.checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that are not applied yet, failing if an applied migration
+// was modified
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, status := range statuses {
+		if status.Modified {
+			return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, status.Version, status.Name)
+		}
+		if !status.Applied {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies the pending migrations and returns them. A dry run returns them without
+// applying anything.
+func (m *Migrator) Up(ctx context.Context, dryRun bool) ([]Migration, error) {
+	if dryRun {
+		return m.Pending(ctx)
+	}
+
+	if err := m.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer m.unlock()
+
+	// Read the pending migrations under the lock, another instance may have applied some
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, migration := range pending {
+		if err := m.apply(ctx, migration); err != nil {
+			return pending[:i], err
+		}
+		if err := m.refreshLock(ctx); err != nil {
+			return pending[:i+1], err
+		}
+	}
+	return pending, nil
+}
+
+// Baseline records the migrations up to version as applied without running them, for
+// keyspaces created before migrations were tracked
+func (m *Migrator) Baseline(ctx context.Context, version int) ([]Migration, error) {
+	if err := m.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer m.unlock()
+
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var baselined []Migration
+	for _, migration := range pending {
+		if migration.Version > version {
+			break
+		}
+		if err := m.record(ctx, migration, 0); err != nil {
+			return baselined, err
+		}
+		baselined = append(baselined, migration)
+	}
+	return baselined, nil
+}
+
+// apply runs the statements of a migration, waiting for schema agreement after each, and
+// records it. A failed migration is not recorded, its statements should be safe to re-run.
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	m.logger.Info("Applying migration", "version", migration.Version, "name", migration.Name, "statements", len(migration.Statements))
+	start := time.Now()
+
+	for i, statement := range migration.Statements {
+		if err := m.session.Query(statement).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("migration %d_%s failed at statement %d: %w", migration.Version, migration.Name, i+1, err)
+		}
+		if err := m.awaitSchemaAgreement(ctx); err != nil {
+			return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return m.record(ctx, migration, time.Since(start))
+}
+
+func (m *Migrator) record(ctx context.Context, migration Migration, duration time.Duration) error {
+	err := m.session.Query(insertAppliedMigration,
+		migration.Version, migration.Name, migration.Checksum, time.Now(), duration.Milliseconds()).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	iter := m.session.Query(selectAppliedMigrations).WithContext(ctx).Iter()
+
+	applied := make(map[int]appliedMigration)
+	var version int
+	var row appliedMigration
+	for iter.Scan(&version, &row.name, &row.checksum, &row.appliedAt) {
+		applied[version] = row
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+func (m *Migrator) ensureTables(ctx context.Context) error {
+	for _, statement := range []string{createSchemaMigrationsTable, createSchemaMigrationsLockTable} {
+		if err := m.session.Query(statement).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("failed to create migration tables: %w", err)
+		}
+		if err := m.awaitSchemaAgreement(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) awaitSchemaAgreement(ctx context.Context) error {
+	awaiter, ok := m.session.(schemaAgreementAwaiter)
+	if !ok {
+		return nil
+	}
+	if err := awaiter.AwaitSchemaAgreement(ctx); err != nil {
+		return fmt.Errorf("schema agreement not reached: %w", err)
+	}
+	return nil
+}
+
+// lock takes the migration lock, waiting up to LockWait for another instance to release it
+func (m *Migrator) lock(ctx context.Context) error {
+	if err := m.ensureTables(ctx); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(m.config.LockWait)
+	for {
+		var name, owner string
+		var acquiredAt time.Time
+		applied, err := m.session.Query(acquireMigrationLock,
+			migrationLockName, m.owner, time.Now(), int(m.config.LockTTL.Seconds())).WithContext(ctx).ScanCAS(&name, &acquiredAt, &owner)
+		if err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if applied {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: held by %s since %s", ErrMigrationLocked, owner, acquiredAt.Format(time.RFC3339))
+		}
+		m.logger.Info("Waiting for migration lock", "owner", owner, "acquired_at", acquiredAt)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.config.LockPollInterval):
+		}
+	}
+}
+
+// refreshLock extends the lock's TTL between migrations, failing if the lock was lost
+func (m *Migrator) refreshLock(ctx context.Context) error {
+	var owner string
+	applied, err := m.session.Query(refreshMigrationLock,
+		int(m.config.LockTTL.Seconds()), time.Now(), migrationLockName, m.owner).WithContext(ctx).ScanCAS(&owner)
+	if err != nil {
+		return fmt.Errorf("failed to refresh migration lock: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("%w: lock expired and was taken by %q", ErrMigrationLocked, owner)
+	}
+	return nil
+}
+
+// unlock releases the lock, a lock that cannot be released expires after LockTTL
+func (m *Migrator) unlock() {
+	var owner string
+	if _, err := m.session.Query(releaseMigrationLock, migrationLockName, m.owner).ScanCAS(&owner); err != nil {
+		m.logger.Warn("Failed to release migration lock, it expires on its own", "error", err)
+	}
+}
+
+var _ schemaAgreementAwaiter = (*gocql.Session)(nil)
@@ -0,0 +1,109 @@
+// Package analysis statically inspects user scripts before they are accepted for execution.
+// Go sources are checked with go/ast, JavaScript, TypeScript and Python with the small
+// tokenizers in this package, so no interpreter is needed to reject obviously unsafe code.
+package analysis
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule identifiers reported in findings
+const (
+	RuleSyntax          = "syntax-error"
+	RuleForbiddenImport = "forbidden-import"
+	RuleProcessExec     = "process-exec"
+	RuleRawSocket       = "raw-socket"
+	RuleInfiniteLoop    = "infinite-loop"
+	RuleFileWrite       = "fs-write-outside-workdir"
+	RuleMissingOutput   = "missing-output"
+)
+
+// WorkDir is the only directory scripts may write to inside the container
+const WorkDir = "/code"
+
+// Finding is a single problem found in a script, lines and columns are 1-based
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column,omitempty"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("line %d: %s (%s)", f.Line, f.Message, f.Rule)
+}
+
+// Analyze runs every rule supported for the language and returns the findings ordered by position.
+// Languages without an analyzer return no findings.
+func Analyze(code string, language types.Language) []Finding {
+	var findings []Finding
+	switch language {
+	case types.LanguageGo:
+		findings = analyzeGo(code)
+	case types.LanguagePy, types.LanguagePyPinned:
+		findings = analyzePython(code)
+	case types.LanguageJS, types.LanguageNode, types.LanguageTS, types.LanguageDeno:
+		findings = analyzeJavaScript(code)
+	default:
+		return nil
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	return findings
+}
+
+// HasErrors reports whether any finding must block the script
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// isOutsideWorkDir reports whether a literal path resolves outside WorkDir.
+// Relative paths are resolved against WorkDir, the scripts' working directory.
+func isOutsideWorkDir(p string) bool {
+	if p == "" {
+		return false
+	}
+	if !path.IsAbs(p) {
+		p = path.Join(WorkDir, p)
+	}
+	cleaned := path.Clean(p)
+	return cleaned != WorkDir && !strings.HasPrefix(cleaned, WorkDir+"/")
+}
+
+func newFinding(rule string, severity Severity, line, column int, format string, args ...interface{}) Finding {
+	return Finding{
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+		Line:     line,
+		Column:   column,
+	}
+}
+
+func missingOutputFinding() Finding {
+	return newFinding(RuleMissingOutput, SeverityError, 1, 0,
+		"script never writes to stdout, the result must be printed as JSON")
+}
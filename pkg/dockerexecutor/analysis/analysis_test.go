@@ -0,0 +1,384 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+)
+
+type expectedFinding struct {
+	rule     string
+	severity Severity
+	line     int
+}
+
+func assertFindings(t *testing.T, expected []expectedFinding, findings []Finding) {
+	t.Helper()
+
+	require.Len(t, findings, len(expected), "findings: %v", findings)
+	for i, want := range expected {
+		assert.Equal(t, want.rule, findings[i].Rule, "finding %d: %v", i, findings[i])
+		assert.Equal(t, want.severity, findings[i].Severity, "finding %d: %v", i, findings[i])
+		assert.Equal(t, want.line, findings[i].Line, "finding %d: %v", i, findings[i])
+	}
+}
+
+func TestAnalyze_Go(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected []expectedFinding
+	}{
+		{
+			name: "clean script",
+			code: `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	_ = os.WriteFile("/code/out.json", nil, 0o644)
+	_ = os.WriteFile("cache/out.json", nil, 0o644)
+	fmt.Println("{}")
+}`,
+		},
+		{
+			name: "forbidden imports",
+			code: `package main
+
+import (
+	"fmt"
+	"os/exec"
+	sys "syscall"
+)
+
+func main() {
+	_ = exec.Command("ls").Run()
+	_ = sys.Getpid()
+	fmt.Println("{}")
+}`,
+			expected: []expectedFinding{
+				{RuleProcessExec, SeverityError, 5},
+				{RuleForbiddenImport, SeverityError, 6},
+			},
+		},
+		{
+			name: "raw socket and writes outside workdir",
+			code: `package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+func main() {
+	_, _ = net.ListenPacket("udp", ":0")
+	_ = os.WriteFile("/etc/passwd", nil, 0o644)
+	_ = os.Rename("/code/a", "../b")
+	fmt.Println("{}")
+}`,
+			expected: []expectedFinding{
+				{RuleRawSocket, SeverityError, 10},
+				{RuleFileWrite, SeverityError, 11},
+				{RuleFileWrite, SeverityError, 12},
+			},
+		},
+		{
+			name: "endless loop",
+			code: `package main
+
+import "fmt"
+
+func main() {
+	for {
+		fmt.Println("{}")
+	}
+	for true {
+		go func() { return }()
+	}
+	for {
+		break
+	}
+}`,
+			expected: []expectedFinding{
+				{RuleInfiniteLoop, SeverityWarning, 6},
+				{RuleInfiniteLoop, SeverityWarning, 9},
+			},
+		},
+		{
+			name: "missing output",
+			code: `package main
+
+func main() {}`,
+			expected: []expectedFinding{
+				{RuleMissingOutput, SeverityError, 1},
+			},
+		},
+		{
+			name: "output through os.Stdout",
+			code: `package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+func main() {
+	_ = json.NewEncoder(os.Stdout).Encode(map[string]int{})
+}`,
+		},
+		{
+			name: "syntax error",
+			code: `package main
+
+func main() {
+	fmt.Println("{}"
+}`,
+			expected: []expectedFinding{
+				{RuleSyntax, SeverityError, 4},
+				{RuleSyntax, SeverityError, 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFindings(t, tt.expected, Analyze(tt.code, types.LanguageGo))
+		})
+	}
+}
+
+func TestAnalyze_JavaScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		language types.Language
+		code     string
+		expected []expectedFinding
+	}{
+		{
+			name:     "clean script",
+			language: types.LanguageJS,
+			code: `const fs = require('fs');
+// require('child_process') in a comment is ignored
+const note = "require('child_process')";
+const re = /\/etc\/[a-z]+/g;
+fs.writeFileSync('/code/result.json', JSON.stringify({ note }));
+console.log(JSON.stringify({ ok: re.test(note) }));`,
+		},
+		{
+			name:     "forbidden modules",
+			language: types.LanguageNode,
+			code: `const { exec } = require("child_process");
+import net from 'node:net';
+const dgram = await import("dgram");
+console.log("{}");`,
+			expected: []expectedFinding{
+				{RuleProcessExec, SeverityError, 1},
+				{RuleRawSocket, SeverityError, 2},
+				{RuleRawSocket, SeverityError, 3},
+			},
+		},
+		{
+			name:     "typescript writes outside workdir",
+			language: types.LanguageTS,
+			code: `import * as fs from "fs";
+const path: string = "/code/ok.txt";
+fs.writeFileSync(path, "x");
+fs.appendFileSync("/var/log/x", "x");
+fs.renameSync("/code/a", ` + "`/tmp/b`" + `);
+fs.writeFileSync(` + "`/tmp/${path}`" + `, "x");
+process.stdout.write("{}");`,
+			expected: []expectedFinding{
+				{RuleFileWrite, SeverityError, 4},
+				{RuleFileWrite, SeverityError, 5},
+			},
+		},
+		{
+			name:     "deno apis",
+			language: types.LanguageDeno,
+			code: `const cmd = new Deno.Command("ls");
+const listener = Deno.listen({ port: 80 });
+await Deno.writeTextFile("/etc/hosts", "x");
+console.log("{}");`,
+			expected: []expectedFinding{
+				{RuleProcessExec, SeverityError, 1},
+				{RuleRawSocket, SeverityError, 2},
+				{RuleFileWrite, SeverityError, 3},
+			},
+		},
+		{
+			name:     "endless loops",
+			language: types.LanguageJS,
+			code: `while (true) { console.log("{}"); }
+for (;;) { if (done()) break; }
+do { tick(); } while (1);
+while (true) process.exit(0);`,
+			expected: []expectedFinding{
+				{RuleInfiniteLoop, SeverityWarning, 1},
+				{RuleInfiniteLoop, SeverityWarning, 3},
+			},
+		},
+		{
+			name:     "missing output",
+			language: types.LanguageJS,
+			code:     `const x = 1;`,
+			expected: []expectedFinding{
+				{RuleMissingOutput, SeverityError, 1},
+			},
+		},
+		{
+			name:     "unterminated string",
+			language: types.LanguageJS,
+			code:     "console.log('{}');\nconst s = 'oops;\n",
+			expected: []expectedFinding{
+				{RuleSyntax, SeverityError, 2},
+			},
+		},
+		{
+			name:     "unbalanced brackets",
+			language: types.LanguageTS,
+			code:     "function main() {\n  console.log('{}');\n",
+			expected: []expectedFinding{
+				{RuleSyntax, SeverityError, 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFindings(t, tt.expected, Analyze(tt.code, tt.language))
+		})
+	}
+}
+
+func TestAnalyze_Python(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected []expectedFinding
+	}{
+		{
+			name: "clean script",
+			code: `import json
+# import subprocess
+doc = """
+import socket
+"""
+with open("/code/result.json", "w") as f:
+    f.write("{}")
+with open("/etc/hosts") as f:
+    hosts = f.read()
+print(json.dumps({"ok": True}))
+`,
+		},
+		{
+			name: "forbidden imports",
+			code: `import os, subprocess as sp
+from socket import socket
+import ctypes.util
+mod = __import__("pty")
+print("{}")
+`,
+			expected: []expectedFinding{
+				{RuleProcessExec, SeverityError, 1},
+				{RuleRawSocket, SeverityError, 2},
+				{RuleForbiddenImport, SeverityError, 3},
+				{RuleProcessExec, SeverityError, 4},
+			},
+		},
+		{
+			name: "process execution through os",
+			code: `import os
+from os import system as run
+os.system("ls")
+run("ls")
+print("{}")
+`,
+			expected: []expectedFinding{
+				{RuleProcessExec, SeverityError, 3},
+				{RuleProcessExec, SeverityError, 4},
+			},
+		},
+		{
+			name: "writes outside workdir",
+			code: `import shutil
+from pathlib import Path
+open("/tmp/out", mode="a").write("x")
+open("relative.txt", "w").write("x")
+shutil.copy("/code/a", "../b")
+Path("/etc/x").write_text("x")
+print(open("/etc/hosts").read())
+`,
+			expected: []expectedFinding{
+				{RuleFileWrite, SeverityError, 3},
+				{RuleFileWrite, SeverityError, 5},
+				{RuleFileWrite, SeverityError, 6},
+			},
+		},
+		{
+			name: "endless loops",
+			code: `import sys
+while True:
+    print("{}")
+while 1:
+    if sys.argv:
+        sys.exit(0)
+while True: break
+`,
+			expected: []expectedFinding{
+				{RuleInfiniteLoop, SeverityWarning, 2},
+			},
+		},
+		{
+			name: "output through sys.stdout",
+			code: `import json, sys
+json.dump({}, sys.stdout)
+`,
+		},
+		{
+			name: "missing output",
+			code: "x = 1\n",
+			expected: []expectedFinding{
+				{RuleMissingOutput, SeverityError, 1},
+			},
+		},
+		{
+			name: "unterminated triple-quoted string",
+			code: "print('{}')\ndoc = '''\nnever closed\n",
+			expected: []expectedFinding{
+				{RuleSyntax, SeverityError, 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFindings(t, tt.expected, Analyze(tt.code, types.LanguagePy))
+		})
+	}
+}
+
+func TestAnalyze_UnsupportedLanguage(t *testing.T) {
+	assert.Nil(t, Analyze(`fn main() {}`, types.LanguageRust))
+}
+
+func TestHasErrors(t *testing.T) {
+	assert.False(t, HasErrors(nil))
+	assert.False(t, HasErrors([]Finding{{Severity: SeverityWarning}}))
+	assert.True(t, HasErrors([]Finding{{Severity: SeverityWarning}, {Severity: SeverityError}}))
+}
+
+func TestIsOutsideWorkDir(t *testing.T) {
+	assert.False(t, isOutsideWorkDir("/code"))
+	assert.False(t, isOutsideWorkDir("/code/a/b.json"))
+	assert.False(t, isOutsideWorkDir("out.json"))
+	assert.True(t, isOutsideWorkDir("/codex/out.json"))
+	assert.True(t, isOutsideWorkDir("/code/../etc/passwd"))
+	assert.True(t, isOutsideWorkDir("../out.json"))
+	assert.True(t, isOutsideWorkDir("/tmp/out.json"))
+}
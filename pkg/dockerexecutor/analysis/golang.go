@@ -0,0 +1,221 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// goForbiddenImports maps import paths to the rule they violate
+var goForbiddenImports = map[string]string{
+	"os/exec":               RuleProcessExec,
+	"syscall":               RuleForbiddenImport,
+	"unsafe":                RuleForbiddenImport,
+	"plugin":                RuleForbiddenImport,
+	"runtime/cgo":           RuleForbiddenImport,
+	"C":                     RuleForbiddenImport,
+	"golang.org/x/sys/unix": RuleForbiddenImport,
+}
+
+// goRawSocketFuncs are the net functions that open sockets below the HTTP/TCP client level
+var goRawSocketFuncs = map[string]bool{
+	"ListenIP":       true,
+	"DialIP":         true,
+	"ListenPacket":   true,
+	"ListenUnix":     true,
+	"DialUnix":       true,
+	"ListenUnixgram": true,
+}
+
+// goFileWriteFuncs are os functions whose first argument is a path that gets created or modified
+var goFileWriteFuncs = map[string]bool{
+	"WriteFile": true,
+	"Create":    true,
+	"OpenFile":  true,
+	"Mkdir":     true,
+	"MkdirAll":  true,
+	"Remove":    true,
+	"RemoveAll": true,
+	"Rename":    true,
+	"Symlink":   true,
+	"Link":      true,
+	"Chmod":     true,
+	"Chown":     true,
+	"Truncate":  true,
+}
+
+func analyzeGo(code string) []Finding {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "code.go", code, parser.SkipObjectResolution)
+	if err != nil {
+		return goSyntaxFindings(err)
+	}
+
+	var findings []Finding
+
+	// Import paths keyed by the local package name
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		pos := fset.Position(spec.Pos())
+		if rule, forbidden := goForbiddenImports[importPath]; forbidden {
+			findings = append(findings, newFinding(rule, SeverityError, pos.Line, pos.Column,
+				"import of %q is not allowed", importPath))
+		}
+
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = importPath
+	}
+
+	writesOutput := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if goPackageCall(node, imports) == "os.Stdout" {
+				writesOutput = true
+			}
+		case *ast.CallExpr:
+			findings = append(findings, goCallFindings(fset, node, imports, &writesOutput)...)
+		case *ast.ForStmt:
+			if goIsEndlessCondition(node.Cond) && !goHasLoopExit(node.Body, imports) {
+				pos := fset.Position(node.Pos())
+				findings = append(findings, newFinding(RuleInfiniteLoop, SeverityWarning, pos.Line, pos.Column,
+					"loop has no condition and no break, return or exit"))
+			}
+		}
+		return true
+	})
+
+	if !writesOutput {
+		findings = append(findings, missingOutputFinding())
+	}
+	return findings
+}
+
+func goSyntaxFindings(err error) []Finding {
+	if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+		findings := make([]Finding, 0, len(list))
+		for _, e := range list {
+			findings = append(findings, newFinding(RuleSyntax, SeverityError, e.Pos.Line, e.Pos.Column, "%s", e.Msg))
+		}
+		return findings
+	}
+	return []Finding{newFinding(RuleSyntax, SeverityError, 1, 0, "%v", err)}
+}
+
+func goCallFindings(fset *token.FileSet, call *ast.CallExpr, imports map[string]string, writesOutput *bool) []Finding {
+	var findings []Finding
+	pos := fset.Position(call.Pos())
+
+	if ident, ok := call.Fun.(*ast.Ident); ok && (ident.Name == "print" || ident.Name == "println") {
+		*writesOutput = true
+		return nil
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	qualified := goPackageCall(sel, imports)
+	pkg, fn, _ := strings.Cut(qualified, ".")
+
+	switch {
+	case pkg == "fmt" && strings.HasPrefix(fn, "Print"):
+		*writesOutput = true
+	case pkg == "net" && goRawSocketFuncs[fn]:
+		findings = append(findings, newFinding(RuleRawSocket, SeverityError, pos.Line, pos.Column,
+			"%s opens a raw or local socket", qualified))
+	case (pkg == "os" && goFileWriteFuncs[fn]) || qualified == "io/ioutil.WriteFile":
+		// Rename, Symlink and Link take two paths, everything else one
+		paths := call.Args
+		if limit := goPathArgs(fn); len(paths) > limit {
+			paths = paths[:limit]
+		}
+		for _, arg := range paths {
+			if p, ok := goStringLiteral(arg); ok && isOutsideWorkDir(p) {
+				findings = append(findings, newFinding(RuleFileWrite, SeverityError, pos.Line, pos.Column,
+					"%s writes to %q outside %s", qualified, p, WorkDir))
+			}
+		}
+	}
+	return findings
+}
+
+// goPackageCall returns "importpath.Name" for selectors on an imported package and "" otherwise
+func goPackageCall(sel *ast.SelectorExpr, imports map[string]string) string {
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	importPath, imported := imports[ident.Name]
+	if !imported {
+		return ""
+	}
+	return importPath + "." + sel.Sel.Name
+}
+
+func goPathArgs(fn string) int {
+	switch fn {
+	case "Rename", "Symlink", "Link":
+		return 2
+	default:
+		return 1
+	}
+}
+
+func goStringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+func goIsEndlessCondition(cond ast.Expr) bool {
+	if cond == nil {
+		return true
+	}
+	ident, ok := cond.(*ast.Ident)
+	return ok && ident.Name == "true"
+}
+
+// goHasLoopExit reports whether a loop body contains a statement that can leave it. Function
+// literals are skipped since a return inside them does not leave the loop.
+func goHasLoopExit(body *ast.BlockStmt, imports map[string]string) bool {
+	exits := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if exits {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			exits = true
+		case *ast.BranchStmt:
+			if node.Tok == token.BREAK || node.Tok == token.GOTO {
+				exits = true
+			}
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "panic" {
+				exits = true
+			}
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
+				switch qualified := goPackageCall(sel, imports); {
+				case qualified == "os.Exit", strings.HasPrefix(qualified, "log.Fatal"), strings.HasPrefix(qualified, "log.Panic"):
+					exits = true
+				}
+			}
+		}
+		return true
+	})
+	return exits
+}
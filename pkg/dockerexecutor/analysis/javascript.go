@@ -0,0 +1,333 @@
+package analysis
+
+import (
+	"strings"
+)
+
+// jsForbiddenModules maps node builtin modules to the rule they violate
+var jsForbiddenModules = map[string]string{
+	"child_process": RuleProcessExec,
+	"net":           RuleRawSocket,
+	"dgram":         RuleRawSocket,
+}
+
+// jsDenoAPIs maps members of the Deno namespace to the rule they violate
+var jsDenoAPIs = map[string]string{
+	"run":            RuleProcessExec,
+	"Command":        RuleProcessExec,
+	"listen":         RuleRawSocket,
+	"listenTls":      RuleRawSocket,
+	"listenDatagram": RuleRawSocket,
+	"connect":        RuleRawSocket,
+	"connectTls":     RuleRawSocket,
+}
+
+// jsFileWriteFuncs are fs and Deno functions that create or modify the paths they are given,
+// mapped to how many leading arguments are paths
+var jsFileWriteFuncs = map[string]int{
+	"writeFile":         1,
+	"writeFileSync":     1,
+	"writeTextFile":     1,
+	"writeTextFileSync": 1,
+	"appendFile":        1,
+	"appendFileSync":    1,
+	"createWriteStream": 1,
+	"mkdir":             1,
+	"mkdirSync":         1,
+	"rm":                1,
+	"rmSync":            1,
+	"rmdir":             1,
+	"rmdirSync":         1,
+	"remove":            1,
+	"removeSync":        1,
+	"unlink":            1,
+	"unlinkSync":        1,
+	"truncate":          1,
+	"truncateSync":      1,
+	"chmod":             1,
+	"chmodSync":         1,
+	"rename":            2,
+	"renameSync":        2,
+	"copyFile":          2,
+	"copyFileSync":      2,
+	"symlink":           2,
+	"symlinkSync":       2,
+}
+
+// jsRegexPrecedingKeywords are keywords after which a "/" starts a regular expression
+var jsRegexPrecedingKeywords = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true, "in": true, "of": true,
+	"new": true, "delete": true, "void": true, "throw": true, "instanceof": true, "yield": true, "await": true,
+}
+
+func analyzeJavaScript(code string) []Finding {
+	stream, findings := lexJavaScript(code)
+	findings = append(findings, stream.matchBrackets()...)
+	if len(findings) > 0 {
+		// Positions are unreliable past a syntax error, report only those
+		return findings
+	}
+
+	writesOutput := false
+	for i, tok := range stream.tokens {
+		switch {
+		case tok.is("require") && stream.at(i+1).is("(") && stream.at(i+2).kind == tokenString && !stream.at(i-1).is("."):
+			findings = append(findings, jsModuleFindings(stream.at(i+2))...)
+		case tok.is("import") && stream.at(i+1).is("(") && stream.at(i+2).kind == tokenString:
+			findings = append(findings, jsModuleFindings(stream.at(i+2))...)
+		case (tok.is("import") || tok.is("from")) && stream.at(i+1).kind == tokenString:
+			findings = append(findings, jsModuleFindings(stream.at(i+1))...)
+		case tok.is("Deno") && stream.at(i+1).is("."):
+			member := stream.at(i + 2)
+			if rule, forbidden := jsDenoAPIs[member.text]; forbidden && member.kind == tokenIdent {
+				findings = append(findings, newFinding(rule, SeverityError, tok.line, tok.col,
+					"Deno.%s is not allowed", member.text))
+			}
+			if member.is("stdout") {
+				writesOutput = true
+			}
+		case tok.is("console") && stream.at(i+1).is(".") && stream.at(i+2).kind == tokenIdent:
+			writesOutput = true
+		case tok.is("process") && stream.at(i+1).is(".") && stream.at(i+2).is("stdout"):
+			writesOutput = true
+		case tok.kind == tokenIdent && jsFileWriteFuncs[tok.text] > 0 && stream.at(i+1).is("("):
+			findings = append(findings, jsFileWriteFindings(stream, i)...)
+		case tok.is("while") || tok.is("for"):
+			if finding, endless := jsEndlessLoop(stream, i); endless {
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	if !writesOutput {
+		findings = append(findings, missingOutputFinding())
+	}
+	return findings
+}
+
+func jsModuleFindings(specifier lexToken) []Finding {
+	module := specifier.value
+	module = strings.TrimPrefix(module, "node:")
+	module = strings.TrimPrefix(module, "npm:")
+	module, _, _ = strings.Cut(module, "/")
+
+	if rule, forbidden := jsForbiddenModules[module]; forbidden {
+		return []Finding{newFinding(rule, SeverityError, specifier.line, specifier.col,
+			"import of %q is not allowed", specifier.value)}
+	}
+	return nil
+}
+
+func jsFileWriteFindings(stream *tokenStream, i int) []Finding {
+	var findings []Finding
+	fn := stream.at(i)
+	args := stream.callArgs(i + 1)
+	for n, arg := range args {
+		if n >= jsFileWriteFuncs[fn.text] {
+			break
+		}
+		if len(arg) == 1 && arg[0].kind == tokenString && isOutsideWorkDir(arg[0].value) {
+			findings = append(findings, newFinding(RuleFileWrite, SeverityError, fn.line, fn.col,
+				"%s writes to %q outside %s", fn.text, arg[0].value, WorkDir))
+		}
+	}
+	return findings
+}
+
+// jsEndlessLoop detects while (true), while (1) and for (;;) loops, including the do-while form,
+// whose body has no break, return, throw or exit
+func jsEndlessLoop(stream *tokenStream, i int) (Finding, bool) {
+	tok := stream.at(i)
+	if !stream.at(i + 1).is("(") {
+		return Finding{}, false
+	}
+	closeParen := stream.matches[i+1]
+
+	switch {
+	case tok.is("while") && closeParen == i+3 && (stream.at(i+2).is("true") || stream.at(i+2).text == "1"):
+	case tok.is("for") && closeParen == i+4 && stream.at(i+2).is(";") && stream.at(i+3).is(";"):
+	default:
+		return Finding{}, false
+	}
+
+	var body []lexToken
+	if prev := stream.at(i - 1); tok.is("while") && prev.is("}") {
+		// Trailing condition of a do { ... } while (true)
+		if open, ok := stream.matches[i-1]; ok && stream.at(open-1).is("do") {
+			body = stream.tokens[open : i-1]
+		}
+	}
+	if body == nil {
+		start := closeParen + 1
+		end := start
+		if stream.at(start).is("{") {
+			end = stream.matches[start]
+		} else {
+			for end < len(stream.tokens) && !stream.at(end).is(";") {
+				end++
+			}
+		}
+		if end > len(stream.tokens) {
+			end = len(stream.tokens)
+		}
+		body = stream.tokens[start:end]
+	}
+
+	for j, t := range body {
+		if t.is("break") || t.is("return") || t.is("throw") {
+			return Finding{}, false
+		}
+		if (t.is("process") || t.is("Deno")) && j+2 < len(body) && body[j+1].is(".") && body[j+2].is("exit") {
+			return Finding{}, false
+		}
+	}
+	return newFinding(RuleInfiniteLoop, SeverityWarning, tok.line, tok.col,
+		"loop has no condition and no break, return, throw or exit"), true
+}
+
+// lexJavaScript tokenizes JavaScript and TypeScript. Type annotations need no special handling
+// since the rules only look at identifiers, strings and brackets.
+func lexJavaScript(code string) (*tokenStream, []Finding) {
+	s := newCursor(code)
+	stream := &tokenStream{}
+
+	for !s.done() {
+		c := s.peek(0)
+		line, col := s.line, s.col
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			s.advance()
+		case s.hasPrefix("//"):
+			s.readWhile(func(b byte) bool { return b != '\n' })
+		case s.hasPrefix("/*"):
+			end := strings.Index(s.src[s.pos+2:], "*/")
+			if end < 0 {
+				s.syntaxError(line, col, "unterminated comment")
+				s.readWhile(func(byte) bool { return true })
+				continue
+			}
+			for n := end + 4; n > 0; n-- {
+				s.advance()
+			}
+		case isIdentStart(c):
+			stream.tokens = append(stream.tokens, lexToken{kind: tokenIdent, text: s.readWhile(isIdentPart), line: line, col: col})
+		case isDigit(c):
+			stream.tokens = append(stream.tokens, lexToken{kind: tokenNumber, text: s.readWhile(isNumberPart), line: line, col: col})
+		case c == '\'' || c == '"':
+			value, ok := s.readQuoted(c, false)
+			if !ok {
+				s.syntaxError(line, col, "unterminated string")
+			}
+			stream.tokens = append(stream.tokens, lexToken{kind: tokenString, text: string(c), value: value, line: line, col: col})
+		case c == '`':
+			value, substitutions, ok := s.readTemplate()
+			if !ok {
+				s.syntaxError(line, col, "unterminated template literal")
+			}
+			tok := lexToken{kind: tokenString, text: "`", value: value, line: line, col: col}
+			if substitutions {
+				// Only literal templates have a known value
+				tok = lexToken{kind: tokenPunct, text: "`", line: line, col: col}
+			}
+			stream.tokens = append(stream.tokens, tok)
+		case c == '/' && jsRegexAllowed(stream):
+			s.readRegex()
+			stream.tokens = append(stream.tokens, lexToken{kind: tokenPunct, text: "/regex/", line: line, col: col})
+		default:
+			stream.tokens = append(stream.tokens, lexToken{kind: tokenPunct, text: string(s.advance()), line: line, col: col})
+		}
+	}
+	return stream, s.findings
+}
+
+func jsRegexAllowed(stream *tokenStream) bool {
+	if len(stream.tokens) == 0 {
+		return true
+	}
+	prev := stream.tokens[len(stream.tokens)-1]
+	switch prev.kind {
+	case tokenIdent:
+		return jsRegexPrecedingKeywords[prev.text]
+	case tokenNumber, tokenString:
+		return false
+	default:
+		return prev.text != ")" && prev.text != "]" && prev.text != "}" && prev.text != "`"
+	}
+}
+
+// readQuoted reads a quoted string starting at the opening quote and returns its content
+func (s *cursor) readQuoted(quote byte, multiline bool) (string, bool) {
+	s.advance()
+	var value strings.Builder
+	for !s.done() {
+		c := s.advance()
+		switch {
+		case c == quote:
+			return value.String(), true
+		case c == '\\' && !s.done():
+			value.WriteByte(s.advance())
+		case c == '\n' && !multiline:
+			return value.String(), false
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return value.String(), false
+}
+
+// readTemplate reads a template literal, skipping over ${...} substitutions
+func (s *cursor) readTemplate() (string, bool, bool) {
+	s.advance()
+	var value strings.Builder
+	substitutions := false
+	for !s.done() {
+		c := s.advance()
+		switch {
+		case c == '`':
+			return value.String(), substitutions, true
+		case c == '\\' && !s.done():
+			value.WriteByte(s.advance())
+		case c == '$' && s.peek(0) == '{':
+			substitutions = true
+			depth := 0
+			for !s.done() {
+				b := s.advance()
+				if b == '{' {
+					depth++
+				} else if b == '}' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return value.String(), substitutions, false
+}
+
+func (s *cursor) readRegex() {
+	s.advance()
+	inClass := false
+	for !s.done() {
+		c := s.peek(0)
+		if c == '\n' {
+			return
+		}
+		s.advance()
+		switch {
+		case c == '\\' && !s.done():
+			s.advance()
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			s.readWhile(isIdentPart)
+			return
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package analysis
+
+import "strings"
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenNumber
+	tokenString // value holds the literal's content
+	tokenPunct
+)
+
+type lexToken struct {
+	kind  tokenKind
+	text  string
+	value string
+	line  int
+	col   int
+}
+
+func (t lexToken) is(text string) bool {
+	return (t.kind == tokenPunct || t.kind == tokenIdent) && t.text == text
+}
+
+// tokenStream is a lexed source with the position of each bracket's partner
+type tokenStream struct {
+	tokens  []lexToken
+	matches map[int]int
+}
+
+func (s *tokenStream) at(i int) lexToken {
+	if i < 0 || i >= len(s.tokens) {
+		return lexToken{kind: tokenPunct}
+	}
+	return s.tokens[i]
+}
+
+// matchBrackets pairs (), [] and {} tokens and reports unbalanced ones as syntax errors
+func (s *tokenStream) matchBrackets() []Finding {
+	var findings []Finding
+	s.matches = make(map[int]int)
+	closers := map[string]string{")": "(", "]": "[", "}": "{"}
+	var stack []int
+
+	for i, tok := range s.tokens {
+		if tok.kind != tokenPunct {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			stack = append(stack, i)
+		case ")", "]", "}":
+			if len(stack) == 0 || s.tokens[stack[len(stack)-1]].text != closers[tok.text] {
+				findings = append(findings, newFinding(RuleSyntax, SeverityError, tok.line, tok.col,
+					"unexpected %q", tok.text))
+				continue
+			}
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			s.matches[open] = i
+			s.matches[i] = open
+		}
+	}
+	for _, open := range stack {
+		tok := s.tokens[open]
+		findings = append(findings, newFinding(RuleSyntax, SeverityError, tok.line, tok.col, "unclosed %q", tok.text))
+	}
+	return findings
+}
+
+// callArgs splits the arguments of the call whose "(" is at index open into top-level argument token lists
+func (s *tokenStream) callArgs(open int) [][]lexToken {
+	closeIdx, ok := s.matches[open]
+	if !ok {
+		return nil
+	}
+
+	var args [][]lexToken
+	var current []lexToken
+	depth := 0
+	for i := open + 1; i < closeIdx; i++ {
+		tok := s.tokens[i]
+		if tok.kind == tokenPunct {
+			switch tok.text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			case ",":
+				if depth == 0 {
+					args = append(args, current)
+					current = nil
+					continue
+				}
+			}
+		}
+		current = append(current, tok)
+	}
+	if len(current) > 0 {
+		args = append(args, current)
+	}
+	return args
+}
+
+// cursor is the position shared by the JavaScript and Python lexers
+type cursor struct {
+	src      string
+	pos      int
+	line     int
+	col      int
+	findings []Finding
+}
+
+func newCursor(src string) *cursor {
+	return &cursor{src: src, line: 1, col: 1}
+}
+
+func (s *cursor) done() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *cursor) peek(offset int) byte {
+	if s.pos+offset < len(s.src) {
+		return s.src[s.pos+offset]
+	}
+	return 0
+}
+
+func (s *cursor) advance() byte {
+	c := s.src[s.pos]
+	s.pos++
+	if c == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+	return c
+}
+
+func (s *cursor) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(s.src[s.pos:], prefix)
+}
+
+func (s *cursor) readWhile(accept func(byte) bool) string {
+	start := s.pos
+	for !s.done() && accept(s.peek(0)) {
+		s.advance()
+	}
+	return s.src[start:s.pos]
+}
+
+func (s *cursor) syntaxError(line, col int, message string) {
+	s.findings = append(s.findings, newFinding(RuleSyntax, SeverityError, line, col, "%s", message))
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNumberPart(c byte) bool {
+	return isIdentPart(c) || c == '.'
+}
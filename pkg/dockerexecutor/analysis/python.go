@@ -0,0 +1,446 @@
+package analysis
+
+import (
+	"strings"
+)
+
+// pyForbiddenModules maps top-level Python modules to the rule they violate
+var pyForbiddenModules = map[string]string{
+	"subprocess":   RuleProcessExec,
+	"pty":          RuleProcessExec,
+	"pexpect":      RuleProcessExec,
+	"sh":           RuleProcessExec,
+	"socket":       RuleRawSocket,
+	"socketserver": RuleRawSocket,
+	"ctypes":       RuleForbiddenImport,
+	"cffi":         RuleForbiddenImport,
+}
+
+// pyProcessFuncs are os functions that start or replace processes
+var pyProcessFuncs = map[string]bool{
+	"system": true, "popen": true, "fork": true, "forkpty": true,
+	"execl": true, "execle": true, "execlp": true, "execlpe": true,
+	"execv": true, "execve": true, "execvp": true, "execvpe": true,
+	"spawnl": true, "spawnle": true, "spawnlp": true, "spawnlpe": true,
+	"spawnv": true, "spawnve": true, "spawnvp": true, "spawnvpe": true,
+	"posix_spawn": true, "posix_spawnp": true,
+}
+
+// pyFileWriteFuncs maps os and shutil functions to the positions of the path arguments they write to
+var pyFileWriteFuncs = map[string][]int{
+	"os.remove":       {0},
+	"os.unlink":       {0},
+	"os.rmdir":        {0},
+	"os.removedirs":   {0},
+	"os.mkdir":        {0},
+	"os.makedirs":     {0},
+	"os.chmod":        {0},
+	"os.chown":        {0},
+	"os.truncate":     {0},
+	"os.rename":       {0, 1},
+	"os.renames":      {0, 1},
+	"os.replace":      {0, 1},
+	"os.symlink":      {1},
+	"os.link":         {1},
+	"shutil.rmtree":   {0},
+	"shutil.copy":     {1},
+	"shutil.copy2":    {1},
+	"shutil.copyfile": {1},
+	"shutil.copytree": {1},
+	"shutil.move":     {0, 1},
+}
+
+// pyPathWriteMethods are pathlib.Path methods that create or modify the path
+var pyPathWriteMethods = map[string]bool{
+	"write_text": true, "write_bytes": true, "touch": true, "mkdir": true,
+	"unlink": true, "rmdir": true, "rename": true, "replace": true, "chmod": true,
+	"symlink_to": true, "hardlink_to": true, "open": true,
+}
+
+// pyLine is a logical line, the half-open token range [start, end) of the stream
+type pyLine struct {
+	indent int
+	start  int
+	end    int
+}
+
+func analyzePython(code string) []Finding {
+	stream, lines, findings := lexPython(code)
+	findings = append(findings, stream.matchBrackets()...)
+	if len(findings) > 0 {
+		// Positions are unreliable past a syntax error, report only those
+		return findings
+	}
+
+	// Fully qualified names keyed by the local name they are bound to
+	names := make(map[string]string)
+	writesOutput := false
+
+	for n, line := range lines {
+		first := stream.at(line.start)
+		switch {
+		case first.is("import"):
+			findings = append(findings, pyImportFindings(stream, line, names)...)
+			continue
+		case first.is("from") && stream.at(line.start+1).kind == tokenIdent:
+			findings = append(findings, pyFromImportFindings(stream, line, names)...)
+			continue
+		case first.is("while"):
+			if finding, endless := pyEndlessLoop(stream, lines, n, names); endless {
+				findings = append(findings, finding)
+			}
+		}
+
+		for i := line.start; i < line.end; i++ {
+			tok := stream.at(i)
+			if tok.kind != tokenIdent || stream.at(i-1).is(".") {
+				continue
+			}
+			qualified, next := pyQualifiedName(stream, i, line.end, names)
+			if qualified == "sys.stdout" || strings.HasPrefix(qualified, "sys.stdout.") || qualified == "print" {
+				writesOutput = true
+			}
+			if !stream.at(next).is("(") {
+				continue
+			}
+			findings = append(findings, pyCallFindings(stream, tok, qualified, next)...)
+		}
+	}
+
+	if !writesOutput {
+		findings = append(findings, missingOutputFinding())
+	}
+	return findings
+}
+
+// pyQualifiedName resolves a dotted name starting at token i through the import bindings and
+// returns it together with the index of the token following it
+func pyQualifiedName(stream *tokenStream, i, end int, names map[string]string) (string, int) {
+	parts := []string{stream.at(i).text}
+	if bound, ok := names[parts[0]]; ok {
+		parts[0] = bound
+	}
+	j := i + 1
+	for j+1 < end && stream.at(j).is(".") && stream.at(j+1).kind == tokenIdent {
+		parts = append(parts, stream.at(j+1).text)
+		j += 2
+	}
+	return strings.Join(parts, "."), j
+}
+
+func pyImportFindings(stream *tokenStream, line pyLine, names map[string]string) []Finding {
+	var findings []Finding
+	for _, clause := range pySplitClauses(stream, line.start+1, line.end) {
+		if len(clause) == 0 {
+			continue
+		}
+		module, alias := pyParseClause(clause)
+		findings = append(findings, pyModuleFindings(module, clause[0])...)
+		if alias != "" {
+			names[alias] = module
+		} else {
+			top, _, _ := strings.Cut(module, ".")
+			names[top] = top
+		}
+	}
+	return findings
+}
+
+func pyFromImportFindings(stream *tokenStream, line pyLine, names map[string]string) []Finding {
+	importIdx := -1
+	for i := line.start + 1; i < line.end; i++ {
+		if stream.at(i).is("import") {
+			importIdx = i
+			break
+		}
+	}
+	if importIdx < 0 {
+		return nil
+	}
+
+	var module strings.Builder
+	for i := line.start + 1; i < importIdx; i++ {
+		module.WriteString(stream.at(i).text)
+	}
+	findings := pyModuleFindings(module.String(), stream.at(line.start+1))
+
+	for _, clause := range pySplitClauses(stream, importIdx+1, line.end) {
+		if len(clause) == 0 {
+			continue
+		}
+		name, alias := pyParseClause(clause)
+		if alias == "" {
+			alias = name
+		}
+		names[alias] = module.String() + "." + name
+	}
+	return findings
+}
+
+// pySplitClauses splits the comma separated part of an import statement, dropping parentheses
+func pySplitClauses(stream *tokenStream, start, end int) [][]lexToken {
+	var clauses [][]lexToken
+	var current []lexToken
+	for i := start; i < end; i++ {
+		tok := stream.at(i)
+		switch {
+		case tok.is("(") || tok.is(")"):
+		case tok.is(","):
+			clauses = append(clauses, current)
+			current = nil
+		default:
+			current = append(current, tok)
+		}
+	}
+	return append(clauses, current)
+}
+
+// pyParseClause returns the dotted name and the "as" alias of one import clause
+func pyParseClause(clause []lexToken) (string, string) {
+	var name strings.Builder
+	for i, tok := range clause {
+		if tok.is("as") && i+1 < len(clause) {
+			return name.String(), clause[i+1].text
+		}
+		name.WriteString(tok.text)
+	}
+	return name.String(), ""
+}
+
+func pyModuleFindings(module string, at lexToken) []Finding {
+	top, _, _ := strings.Cut(module, ".")
+	if rule, forbidden := pyForbiddenModules[top]; forbidden {
+		return []Finding{newFinding(rule, SeverityError, at.line, at.col, "import of %q is not allowed", module)}
+	}
+	return nil
+}
+
+func pyCallFindings(stream *tokenStream, at lexToken, qualified string, open int) []Finding {
+	args := stream.callArgs(open)
+	pkg, fn, _ := strings.Cut(qualified, ".")
+
+	switch {
+	case qualified == "__import__" || qualified == "importlib.import_module":
+		if module, ok := pyLiteralArg(args, 0); ok {
+			return pyModuleFindings(module, at)
+		}
+	case pkg == "os" && pyProcessFuncs[fn]:
+		return []Finding{newFinding(RuleProcessExec, SeverityError, at.line, at.col, "%s is not allowed", qualified)}
+	case qualified == "open" || qualified == "io.open":
+		mode, ok := pyLiteralArg(args, 1)
+		if kw, found := pyKeywordArg(args, "mode"); found {
+			mode, ok = kw, true
+		}
+		if ok && strings.ContainsAny(mode, "wax+") {
+			return pyPathFindings(args, []int{0}, qualified, at)
+		}
+	case pyFileWriteFuncs[qualified] != nil:
+		return pyPathFindings(args, pyFileWriteFuncs[qualified], qualified, at)
+	case qualified == "Path" || qualified == "pathlib.Path":
+		// Path("/etc/x").write_text(...)
+		closeParen := stream.matches[open]
+		if stream.at(closeParen+1).is(".") && pyPathWriteMethods[stream.at(closeParen+2).text] {
+			return pyPathFindings(args, []int{0}, qualified+"."+stream.at(closeParen+2).text, at)
+		}
+	}
+	return nil
+}
+
+func pyPathFindings(args [][]lexToken, positions []int, qualified string, at lexToken) []Finding {
+	var findings []Finding
+	for _, position := range positions {
+		if p, ok := pyLiteralArg(args, position); ok && isOutsideWorkDir(p) {
+			findings = append(findings, newFinding(RuleFileWrite, SeverityError, at.line, at.col,
+				"%s writes to %q outside %s", qualified, p, WorkDir))
+		}
+	}
+	return findings
+}
+
+func pyLiteralArg(args [][]lexToken, position int) (string, bool) {
+	if position >= len(args) || len(args[position]) != 1 || args[position][0].kind != tokenString {
+		return "", false
+	}
+	return args[position][0].value, true
+}
+
+func pyKeywordArg(args [][]lexToken, name string) (string, bool) {
+	for _, arg := range args {
+		if len(arg) == 3 && arg[0].is(name) && arg[1].is("=") && arg[2].kind == tokenString {
+			return arg[2].value, true
+		}
+	}
+	return "", false
+}
+
+// pyEndlessLoop detects while True and while 1 loops whose body, the inline statement or the
+// following more indented lines, has no break, return, raise or exit
+func pyEndlessLoop(stream *tokenStream, lines []pyLine, n int, names map[string]string) (Finding, bool) {
+	line := lines[n]
+	cond := stream.at(line.start + 1)
+	if !(cond.is("True") || cond.text == "1") || !stream.at(line.start+2).is(":") {
+		return Finding{}, false
+	}
+
+	bodies := []pyLine{{start: line.start + 3, end: line.end}}
+	for _, next := range lines[n+1:] {
+		if next.indent <= line.indent {
+			break
+		}
+		bodies = append(bodies, next)
+	}
+
+	for _, body := range bodies {
+		for i := body.start; i < body.end; i++ {
+			tok := stream.at(i)
+			if tok.is("break") || tok.is("return") || tok.is("raise") {
+				return Finding{}, false
+			}
+			if tok.kind != tokenIdent || stream.at(i-1).is(".") {
+				continue
+			}
+			switch qualified, _ := pyQualifiedName(stream, i, body.end, names); qualified {
+			case "exit", "quit", "sys.exit", "os._exit":
+				return Finding{}, false
+			}
+		}
+	}
+	first := stream.at(line.start)
+	return newFinding(RuleInfiniteLoop, SeverityWarning, first.line, first.col,
+		"loop has no condition and no break, return, raise or exit"), true
+}
+
+// lexPython tokenizes Python into logical lines. Newlines inside brackets and after a backslash
+// continue the current line, indentation is measured on the first physical line.
+func lexPython(code string) (*tokenStream, []pyLine, []Finding) {
+	s := newCursor(code)
+	stream := &tokenStream{}
+	var lines []pyLine
+
+	depth := 0
+	atLineStart := true
+	current := pyLine{}
+
+	endLine := func() {
+		if len(stream.tokens) > current.start {
+			current.end = len(stream.tokens)
+			lines = append(lines, current)
+		}
+		atLineStart = true
+	}
+
+	for !s.done() {
+		if atLineStart {
+			indent := s.readWhile(func(b byte) bool { return b == ' ' || b == '\t' })
+			current = pyLine{indent: len(strings.ReplaceAll(indent, "\t", "        ")), start: len(stream.tokens)}
+			atLineStart = false
+			continue
+		}
+
+		c := s.peek(0)
+		line, col := s.line, s.col
+
+		switch {
+		case c == '\n':
+			s.advance()
+			if depth == 0 {
+				endLine()
+			}
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			s.advance()
+		case c == '\\' && (s.peek(1) == '\n' || (s.peek(1) == '\r' && s.peek(2) == '\n')):
+			s.readWhile(func(b byte) bool { return b == '\\' || b == '\r' })
+			s.advance()
+		case c == '#':
+			s.readWhile(func(b byte) bool { return b != '\n' })
+		case isIdentStart(c):
+			word := s.readWhile(isIdentPart)
+			if quote := s.peek(0); (quote == '\'' || quote == '"') && pyIsStringPrefix(word) {
+				stream.tokens = append(stream.tokens, s.readPyString(word, line, col))
+				continue
+			}
+			stream.tokens = append(stream.tokens, lexToken{kind: tokenIdent, text: word, line: line, col: col})
+		case isDigit(c):
+			stream.tokens = append(stream.tokens, lexToken{kind: tokenNumber, text: s.readWhile(isNumberPart), line: line, col: col})
+		case c == '\'' || c == '"':
+			stream.tokens = append(stream.tokens, s.readPyString("", line, col))
+		default:
+			text := string(s.advance())
+			switch text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				if depth > 0 {
+					depth--
+				}
+			}
+			stream.tokens = append(stream.tokens, lexToken{kind: tokenPunct, text: text, line: line, col: col})
+		}
+	}
+	endLine()
+	return stream, lines, s.findings
+}
+
+func pyIsStringPrefix(word string) bool {
+	if len(word) > 2 {
+		return false
+	}
+	for _, r := range strings.ToLower(word) {
+		if r != 'r' && r != 'b' && r != 'f' && r != 'u' {
+			return false
+		}
+	}
+	return true
+}
+
+// readPyString reads a single or triple quoted string. F-strings have no known value and are
+// returned as punctuation so they never match a literal path.
+func (s *cursor) readPyString(prefix string, line, col int) lexToken {
+	quote := s.peek(0)
+	triple := strings.Repeat(string(quote), 3)
+	raw := strings.ContainsAny(prefix, "rR")
+
+	var value string
+	var ok bool
+	if s.hasPrefix(triple) {
+		s.advance()
+		s.advance()
+		s.advance()
+		value, ok = s.readUntil(triple, raw)
+		if !ok {
+			s.syntaxError(line, col, "unterminated triple-quoted string")
+		}
+	} else {
+		value, ok = s.readQuoted(quote, false)
+		if !ok {
+			s.syntaxError(line, col, "unterminated string")
+		}
+	}
+
+	if strings.ContainsAny(prefix, "fF") {
+		return lexToken{kind: tokenPunct, text: "f-string", line: line, col: col}
+	}
+	return lexToken{kind: tokenString, text: prefix + string(quote), value: value, line: line, col: col}
+}
+
+// readUntil reads up to and including the terminator, raw strings keep their backslashes
+func (s *cursor) readUntil(terminator string, raw bool) (string, bool) {
+	var value strings.Builder
+	for !s.done() {
+		if s.hasPrefix(terminator) {
+			for range terminator {
+				s.advance()
+			}
+			return value.String(), true
+		}
+		c := s.advance()
+		if c == '\\' && !s.done() {
+			if raw {
+				value.WriteByte(c)
+			}
+			c = s.advance()
+		}
+		value.WriteByte(c)
+	}
+	return value.String(), false
+}
@@ -36,13 +36,37 @@ type ExecutionFeeConfig struct {
 
 	// Ethereum to USD conversion rate (should be updated periodically)
 	// EthToUSDRate float64 `yaml:"eth_to_usd_rate"` // Current ETH/USD rate
+
+	// Per-language adjustments to the off-chain fee, keyed by language
+	GasProfiles map[string]GasProfile `yaml:"gas_profiles"`
+}
+
+// GasProfile adjusts the off-chain fee for runtimes that cost more to run than the baseline
+type GasProfile struct {
+	OffChainFeeMultiplier float64 `yaml:"offchain_fee_multiplier"` // Applied to the task type's off-chain fee, 0 means 1
+	BuildFeeUSD           float64 `yaml:"build_fee_usd"`           // Flat fee covering compilation or dependency resolution
 }
 
 type BasePoolConfig struct {
-	MaxContainers       int           `yaml:"max_containers"`
-	MinContainers       int           `yaml:"min_containers"`
-	MaxWaitTime         time.Duration `yaml:"max_wait_time"`
-	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
+	MaxContainers       int               `yaml:"max_containers"`
+	MinContainers       int               `yaml:"min_containers"`
+	MaxWaitTime         time.Duration     `yaml:"max_wait_time"`
+	HealthCheckInterval time.Duration     `yaml:"health_check_interval"`
+	Autoscaling         AutoscalingConfig `yaml:"autoscaling"`
+}
+
+// AutoscalingConfig controls how a language pool grows and shrinks between
+// MinContainers and MaxContainers
+type AutoscalingConfig struct {
+	Enabled            bool          `yaml:"enabled"`
+	EvaluationInterval time.Duration `yaml:"evaluation_interval"`  // How often scaling decisions are made
+	ScaleUpQueueDepth  int           `yaml:"scale_up_queue_depth"` // Waiting requests that trigger a scale up
+	ScaleUpWaitP95     time.Duration `yaml:"scale_up_wait_p95"`    // p95 wait time that triggers a scale up
+	ScaleUpStep        int           `yaml:"scale_up_step"`        // Containers added per scale up
+	ScaleUpCooldown    time.Duration `yaml:"scale_up_cooldown"`    // Minimum time between two scale ups
+	IdleTTL            time.Duration `yaml:"idle_ttl"`             // Idle time after which a ready container is reaped
+	WaitTimeWindow     time.Duration `yaml:"wait_time_window"`     // Window over which wait percentiles are computed
+	PrewarmLeadTime    time.Duration `yaml:"prewarm_lead_time"`    // How early to pre-warm before a scheduled peak
 }
 
 type LanguageConfig struct {
@@ -52,6 +76,10 @@ type LanguageConfig struct {
 	RunCommand  string         `yaml:"run_command"`
 	Extensions  []string       `yaml:"extensions"`
 	Environment []string       `yaml:"environment"`
+	CacheMounts []string       `yaml:"cache_mounts"` // host:container:ro binds shared by all containers of the pool
+	// Host directory of binaries compiled ahead of execution by one-shot build containers,
+	// keyed by the sha256 of their source. Never mounted into pool containers.
+	BuildCacheDir string `yaml:"build_cache_dir"`
 }
 
 // LanguagePoolConfig is the configuration for a language pool
@@ -76,6 +104,36 @@ type ValidationConfig struct {
 	AllowedExtensions []string `yaml:"allowed_extensions"`
 	MaxComplexity     float64  `yaml:"max_complexity"`
 	TimeoutSeconds    int      `yaml:"timeout_seconds"`
+
+	// Per-language overrides of MaxComplexity, keyed by language
+	LanguageMaxComplexity map[string]float64 `yaml:"language_max_complexity"`
+
+	// Reject scripts with static analysis errors, warnings are reported without rejecting
+	EnableStaticAnalysis bool `yaml:"enable_static_analysis"`
+
+	Rust         RustValidationConfig         `yaml:"rust"`
+	PinnedPython PinnedPythonValidationConfig `yaml:"pinned_python"`
+	Deno         DenoValidationConfig         `yaml:"deno"`
+}
+
+// RustValidationConfig restricts what rust sources may use
+type RustValidationConfig struct {
+	ForbidUnsafe  bool     `yaml:"forbid_unsafe"`
+	AllowedCrates []string `yaml:"allowed_crates"` // Crates besides std, core and alloc available in the image
+}
+
+// PinnedPythonValidationConfig restricts the inline requirements block of pinned python sources
+type PinnedPythonValidationConfig struct {
+	RequireHashes   bool     `yaml:"require_hashes"`
+	AllowedPackages []string `yaml:"allowed_packages"` // Empty allows any package present in the wheel mirror
+	MaxRequirements int      `yaml:"max_requirements"`
+}
+
+// DenoValidationConfig is the allowlist of permissions and remote module hosts for deno sources
+type DenoValidationConfig struct {
+	AllowedPermissions []string `yaml:"allowed_permissions"` // e.g. net, env, read
+	AllowedNetHosts    []string `yaml:"allowed_net_hosts"`
+	AllowedImportHosts []string `yaml:"allowed_import_hosts"`
 }
 
 // MonitoringConfig is the configuration for execution monitoring
@@ -94,6 +152,25 @@ type MonitoringConfig struct {
 	} `yaml:"health_score_thresholds"`
 }
 
+// QuotaConfig limits how much of the executor a single tenant can consume and how
+// executions from different tenants share it under contention
+type QuotaConfig struct {
+	Enabled                 bool                         `yaml:"enabled"`
+	Window                  time.Duration                `yaml:"window"`                    // Sliding window for cpu and memory budgets
+	MaxConcurrentExecutions int                          `yaml:"max_concurrent_executions"` // Executions running at once across all tenants
+	DefaultTenant           TenantQuotaConfig            `yaml:"default_tenant"`
+	Tenants                 map[string]TenantQuotaConfig `yaml:"tenants"` // Overrides keyed by wallet address or API key ID
+}
+
+// TenantQuotaConfig is the budget and fair-share weight of a single tenant
+type TenantQuotaConfig struct {
+	Weight                   int     `yaml:"weight"`                       // Relative share of execution slots under contention
+	MaxConcurrent            int     `yaml:"max_concurrent"`               // Executions running at once
+	MaxQueued                int     `yaml:"max_queued"`                   // Executions waiting for a slot before new ones are rejected
+	CPUSecondsPerWindow      float64 `yaml:"cpu_seconds_per_window"`       // 0 disables the cpu budget
+	MemoryMBSecondsPerWindow float64 `yaml:"memory_mb_seconds_per_window"` // 0 disables the memory budget
+}
+
 type ManagerConfig struct {
 	AutoCleanup bool `yaml:"auto_cleanup"`
 }
@@ -106,4 +183,5 @@ type CodeExecutorConfig struct {
 	Cache      FileCacheConfig               `yaml:"cache"`
 	Validation ValidationConfig              `yaml:"validation"`
 	Monitoring MonitoringConfig              `yaml:"monitoring"`
+	Quotas     QuotaConfig                   `yaml:"quotas"`
 }
@@ -14,5 +14,6 @@ type ConfigProviderInterface interface {
 	GetValidationConfig() ValidationConfig
 	GetMonitoringConfig() MonitoringConfig
 	GetManagerConfig() ManagerConfig
+	GetQuotaConfig() QuotaConfig
 	GetSupportedLanguages() []types.Language
 }
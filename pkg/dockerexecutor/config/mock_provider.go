@@ -139,6 +139,20 @@ func (mr *MockConfigProviderInterfaceMockRecorder) GetMonitoringConfig() *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonitoringConfig", reflect.TypeOf((*MockConfigProviderInterface)(nil).GetMonitoringConfig))
 }
 
+// GetQuotaConfig mocks base method.
+func (m *MockConfigProviderInterface) GetQuotaConfig() QuotaConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaConfig")
+	ret0, _ := ret[0].(QuotaConfig)
+	return ret0
+}
+
+// GetQuotaConfig indicates an expected call of GetQuotaConfig.
+func (mr *MockConfigProviderInterfaceMockRecorder) GetQuotaConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaConfig", reflect.TypeOf((*MockConfigProviderInterface)(nil).GetQuotaConfig))
+}
+
 // GetSupportedLanguages mocks base method.
 func (m *MockConfigProviderInterface) GetSupportedLanguages() []types.Language {
 	m.ctrl.T.Helper()
@@ -70,6 +70,16 @@ func (cp *ConfigProvider) GetMonitoringConfig() MonitoringConfig {
 	return cp.cfg.Monitoring
 }
 
+// DisableQuotas turns per-tenant quotas off, for executors that only run trusted work
+func (cp *ConfigProvider) DisableQuotas() {
+	cp.cfg.Quotas.Enabled = false
+}
+
+// GetQuotaConfig returns the per-tenant quota configuration
+func (cp *ConfigProvider) GetQuotaConfig() QuotaConfig {
+	return cp.cfg.Quotas
+}
+
 // GetManagerConfig returns the manager configuration
 func (cp *ConfigProvider) GetManagerConfig() ManagerConfig {
 	return cp.cfg.Manager
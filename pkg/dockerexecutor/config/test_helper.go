@@ -97,6 +97,7 @@ func NewDefaultMockConfigProvider(t gomock.TestReporter) *MockConfigProviderInte
 	mock.EXPECT().GetFeesConfig().Return(defaultConfig.Fees).AnyTimes()
 	mock.EXPECT().GetCacheConfig().Return(defaultConfig.Cache).AnyTimes()
 	mock.EXPECT().GetValidationConfig().Return(defaultConfig.Validation).AnyTimes()
+	mock.EXPECT().GetQuotaConfig().Return(defaultConfig.Quotas).AnyTimes()
 	mock.EXPECT().GetSupportedLanguages().Return([]types.Language{types.LanguageGo, types.LanguagePy, types.LanguageJS}).AnyTimes()
 
 	// Set up language-specific config expectations
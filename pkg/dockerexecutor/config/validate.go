@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/docker/go-units"
@@ -27,11 +28,19 @@ func (c *CodeExecutorConfig) Validate() error {
 	if err := c.Monitoring.Validate(); err != nil {
 		errors = append(errors, fmt.Sprintf("monitoring config error: %v", err))
 	}
+	if err := c.Quotas.Validate(); err != nil {
+		errors = append(errors, fmt.Sprintf("quotas config error: %v", err))
+	}
 
 	for langKey, langPoolCfg := range c.Languages {
 		if err := langPoolCfg.Validate(); err != nil {
 			errors = append(errors, fmt.Sprintf("language config '%s' error: %v", langKey, err))
 		}
+		if langPoolCfg.LanguageConfig.Language == types.LanguageDeno {
+			if err := c.Validation.Deno.validatePermissionFlags(langPoolCfg.LanguageConfig.Environment); err != nil {
+				errors = append(errors, fmt.Sprintf("language config '%s' error: %v", langKey, err))
+			}
+		}
 	}
 
 	if len(errors) > 0 {
@@ -98,6 +107,17 @@ func (c *ExecutionFeeConfig) Validate() error {
 	if c.DynamicComplexityFactor < 0 {
 		errors = append(errors, "dynamic_complexity_factor cannot be negative")
 	}
+	for lang, profile := range c.GasProfiles {
+		if !isValidLanguage(types.Language(lang)) {
+			errors = append(errors, fmt.Sprintf("gas profile for unsupported language: %s", lang))
+		}
+		if profile.OffChainFeeMultiplier < 0 {
+			errors = append(errors, fmt.Sprintf("gas profile '%s' offchain_fee_multiplier cannot be negative", lang))
+		}
+		if profile.BuildFeeUSD < 0 {
+			errors = append(errors, fmt.Sprintf("gas profile '%s' build_fee_usd cannot be negative", lang))
+		}
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf("%s", strings.Join(errors, "; "))
@@ -124,6 +144,51 @@ func (c *BasePoolConfig) Validate() error {
 	if c.HealthCheckInterval <= 0 {
 		errors = append(errors, "health_check_interval must be positive")
 	}
+	if err := c.Autoscaling.Validate(); err != nil {
+		errors = append(errors, fmt.Sprintf("autoscaling: %v", err))
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// Validate checks the AutoscalingConfig fields. A disabled config is always valid.
+func (c *AutoscalingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	var errors []string
+
+	if c.EvaluationInterval <= 0 {
+		errors = append(errors, "evaluation_interval must be positive")
+	}
+	if c.ScaleUpQueueDepth <= 0 && c.ScaleUpWaitP95 <= 0 {
+		errors = append(errors, "at least one of scale_up_queue_depth or scale_up_wait_p95 must be positive")
+	}
+	if c.ScaleUpQueueDepth < 0 {
+		errors = append(errors, "scale_up_queue_depth cannot be negative")
+	}
+	if c.ScaleUpWaitP95 < 0 {
+		errors = append(errors, "scale_up_wait_p95 cannot be negative")
+	}
+	if c.ScaleUpStep <= 0 {
+		errors = append(errors, "scale_up_step must be positive")
+	}
+	if c.ScaleUpCooldown < 0 {
+		errors = append(errors, "scale_up_cooldown cannot be negative")
+	}
+	if c.IdleTTL <= 0 {
+		errors = append(errors, "idle_ttl must be positive")
+	}
+	if c.WaitTimeWindow <= 0 {
+		errors = append(errors, "wait_time_window must be positive")
+	}
+	if c.PrewarmLeadTime < 0 {
+		errors = append(errors, "prewarm_lead_time cannot be negative")
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf("%s", strings.Join(errors, "; "))
@@ -147,6 +212,23 @@ func (c *LanguageConfig) Validate() error {
 	if len(c.Extensions) == 0 {
 		errors = append(errors, "at least one file extension must be specified")
 	}
+	for _, mount := range c.CacheMounts {
+		parts := strings.Split(mount, ":")
+		if len(parts) < 2 || len(parts) > 3 || !filepath.IsAbs(parts[0]) || !filepath.IsAbs(parts[1]) {
+			errors = append(errors, fmt.Sprintf("cache mount must be host:container:ro with absolute paths: %s", mount))
+		} else if len(parts) != 3 || parts[2] != "ro" {
+			// Every tenant's code runs in the pool, a writable mount would let one tamper with another's
+			errors = append(errors, fmt.Sprintf("cache mount must be read-only (:ro): %s", mount))
+		}
+	}
+	if c.BuildCacheDir != "" {
+		if c.Language != types.LanguageRust {
+			errors = append(errors, fmt.Sprintf("build_cache_dir is not supported for %s", c.Language))
+		}
+		if !filepath.IsAbs(c.BuildCacheDir) {
+			errors = append(errors, fmt.Sprintf("build_cache_dir must be an absolute path: %s", c.BuildCacheDir))
+		}
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf("%s", strings.Join(errors, "; "))
@@ -208,6 +290,122 @@ func (c *ValidationConfig) Validate() error {
 	if c.TimeoutSeconds <= 0 {
 		errors = append(errors, "timeout_seconds must be positive")
 	}
+	for lang, maxComplexity := range c.LanguageMaxComplexity {
+		if !isValidLanguage(types.Language(lang)) {
+			errors = append(errors, fmt.Sprintf("language_max_complexity for unsupported language: %s", lang))
+		}
+		if maxComplexity < 0 {
+			errors = append(errors, fmt.Sprintf("language_max_complexity for %s cannot be negative", lang))
+		}
+	}
+	if err := c.Rust.Validate(); err != nil {
+		errors = append(errors, fmt.Sprintf("rust: %v", err))
+	}
+	if err := c.PinnedPython.Validate(); err != nil {
+		errors = append(errors, fmt.Sprintf("pinned_python: %v", err))
+	}
+	if err := c.Deno.Validate(); err != nil {
+		errors = append(errors, fmt.Sprintf("deno: %v", err))
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Validate checks the RustValidationConfig fields.
+func (c *RustValidationConfig) Validate() error {
+	var errors []string
+
+	for _, crate := range c.AllowedCrates {
+		if !identifierRegex.MatchString(crate) {
+			errors = append(errors, fmt.Sprintf("invalid crate name: %s", crate))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// Validate checks the PinnedPythonValidationConfig fields.
+func (c *PinnedPythonValidationConfig) Validate() error {
+	var errors []string
+
+	if c.MaxRequirements < 0 {
+		errors = append(errors, "max_requirements cannot be negative")
+	}
+	for _, pkg := range c.AllowedPackages {
+		if strings.TrimSpace(pkg) == "" || strings.ContainsAny(pkg, " =<>;@") {
+			errors = append(errors, fmt.Sprintf("invalid package name: %q", pkg))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// denoPermissions are the deno --allow-* permissions that may be granted to scripts
+var denoPermissions = []string{"read", "write", "net", "env", "sys", "run", "ffi", "import"}
+
+// Validate checks the DenoValidationConfig fields.
+func (c *DenoValidationConfig) Validate() error {
+	var errors []string
+
+	for _, perm := range c.AllowedPermissions {
+		if !slices.Contains(denoPermissions, perm) {
+			errors = append(errors, fmt.Sprintf("unknown deno permission: %s", perm))
+		}
+	}
+	for _, host := range append(append([]string{}, c.AllowedNetHosts...), c.AllowedImportHosts...) {
+		if host == "" || strings.ContainsAny(host, "/ ,") {
+			errors = append(errors, fmt.Sprintf("invalid host: %q", host))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// validatePermissionFlags checks that the DENO_PERMISSIONS granted to a deno pool stay within the allowlist
+func (c *DenoValidationConfig) validatePermissionFlags(environment []string) error {
+	var flags string
+	for _, env := range environment {
+		if value, found := strings.CutPrefix(env, "DENO_PERMISSIONS="); found {
+			flags = value
+		}
+	}
+
+	var errors []string
+	for _, flag := range strings.Fields(flags) {
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(flag, "--allow-"), "=")
+		if !strings.HasPrefix(flag, "--allow-") || !slices.Contains(c.AllowedPermissions, name) {
+			errors = append(errors, fmt.Sprintf("deno permission not allowed: %s", flag))
+			continue
+		}
+		if name != "net" {
+			continue
+		}
+		if !hasValue {
+			if len(c.AllowedNetHosts) > 0 {
+				errors = append(errors, "--allow-net must list hosts from allowed_net_hosts")
+			}
+			continue
+		}
+		for _, host := range strings.Split(value, ",") {
+			if len(c.AllowedNetHosts) > 0 && !slices.Contains(c.AllowedNetHosts, host) {
+				errors = append(errors, fmt.Sprintf("deno net host not allowed: %s", host))
+			}
+		}
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf("%s", strings.Join(errors, "; "))
@@ -240,6 +438,9 @@ func isValidLanguage(lang types.Language) bool {
 		types.LanguageJS,
 		types.LanguageTS,
 		types.LanguageNode,
+		types.LanguageRust,
+		types.LanguagePyPinned,
+		types.LanguageDeno,
 	}
 	for _, supported := range supportedLanguages {
 		if lang == supported {
@@ -292,3 +493,62 @@ func (c *MonitoringConfig) Validate() error {
 	}
 	return nil
 }
+
+// Validate checks the QuotaConfig fields. Disabled quotas are not validated.
+func (c *QuotaConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	var errors []string
+
+	if c.Window <= 0 {
+		errors = append(errors, "window must be positive")
+	}
+	if c.MaxConcurrentExecutions <= 0 {
+		errors = append(errors, "max_concurrent_executions must be positive")
+	}
+	if err := c.DefaultTenant.Validate(); err != nil {
+		errors = append(errors, fmt.Sprintf("default_tenant: %v", err))
+	}
+	for tenant, tenantCfg := range c.Tenants {
+		if tenant == "" {
+			errors = append(errors, "tenant key cannot be empty")
+			continue
+		}
+		if err := tenantCfg.Validate(); err != nil {
+			errors = append(errors, fmt.Sprintf("tenant '%s': %v", tenant, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// Validate checks the TenantQuotaConfig fields.
+func (c *TenantQuotaConfig) Validate() error {
+	var errors []string
+
+	if c.Weight <= 0 {
+		errors = append(errors, "weight must be positive")
+	}
+	if c.MaxConcurrent <= 0 {
+		errors = append(errors, "max_concurrent must be positive")
+	}
+	if c.MaxQueued < 0 {
+		errors = append(errors, "max_queued cannot be negative")
+	}
+	if c.CPUSecondsPerWindow < 0 {
+		errors = append(errors, "cpu_seconds_per_window cannot be negative")
+	}
+	if c.MemoryMBSecondsPerWindow < 0 {
+		errors = append(errors, "memory_mb_seconds_per_window cannot be negative")
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
@@ -296,6 +296,24 @@ func TestExecutionFeeConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "static_complexity_factor cannot be negative",
 		},
+		{
+			name: "NegativeGasProfileMultiplier_ShouldFail",
+			config: ExecutionFeeConfig{
+				PricePerTG:  0.0001,
+				GasProfiles: map[string]GasProfile{"rust": {OffChainFeeMultiplier: -1}},
+			},
+			wantErr: true,
+			errMsg:  "gas profile 'rust' offchain_fee_multiplier cannot be negative",
+		},
+		{
+			name: "GasProfileUnsupportedLanguage_ShouldFail",
+			config: ExecutionFeeConfig{
+				PricePerTG:  0.0001,
+				GasProfiles: map[string]GasProfile{"cobol": {BuildFeeUSD: 0.001}},
+			},
+			wantErr: true,
+			errMsg:  "gas profile for unsupported language: cobol",
+		},
 	}
 
 	for _, tt := range tests {
@@ -404,6 +422,172 @@ func TestBasePoolConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestAutoscalingConfig_Validate(t *testing.T) {
+	validConfig := AutoscalingConfig{
+		Enabled:            true,
+		EvaluationInterval: 5 * time.Second,
+		ScaleUpQueueDepth:  2,
+		ScaleUpWaitP95:     500 * time.Millisecond,
+		ScaleUpStep:        1,
+		ScaleUpCooldown:    10 * time.Second,
+		IdleTTL:            5 * time.Minute,
+		WaitTimeWindow:     time.Minute,
+		PrewarmLeadTime:    30 * time.Second,
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(c *AutoscalingConfig)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "ValidConfig_ShouldPass",
+			modify:  func(c *AutoscalingConfig) {},
+			wantErr: false,
+		},
+		{
+			name:    "DisabledEmptyConfig_ShouldPass",
+			modify:  func(c *AutoscalingConfig) { *c = AutoscalingConfig{} },
+			wantErr: false,
+		},
+		{
+			name:    "ZeroEvaluationInterval_ShouldFail",
+			modify:  func(c *AutoscalingConfig) { c.EvaluationInterval = 0 },
+			wantErr: true,
+			errMsg:  "evaluation_interval must be positive",
+		},
+		{
+			name: "NoScaleUpTrigger_ShouldFail",
+			modify: func(c *AutoscalingConfig) {
+				c.ScaleUpQueueDepth = 0
+				c.ScaleUpWaitP95 = 0
+			},
+			wantErr: true,
+			errMsg:  "at least one of scale_up_queue_depth or scale_up_wait_p95 must be positive",
+		},
+		{
+			name:    "ZeroScaleUpStep_ShouldFail",
+			modify:  func(c *AutoscalingConfig) { c.ScaleUpStep = 0 },
+			wantErr: true,
+			errMsg:  "scale_up_step must be positive",
+		},
+		{
+			name:    "ZeroIdleTTL_ShouldFail",
+			modify:  func(c *AutoscalingConfig) { c.IdleTTL = 0 },
+			wantErr: true,
+			errMsg:  "idle_ttl must be positive",
+		},
+		{
+			name:    "NegativePrewarmLeadTime_ShouldFail",
+			modify:  func(c *AutoscalingConfig) { c.PrewarmLeadTime = -time.Second },
+			wantErr: true,
+			errMsg:  "prewarm_lead_time cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig
+			tt.modify(&config)
+			err := config.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestQuotaConfig_Validate(t *testing.T) {
+	validTenant := TenantQuotaConfig{
+		Weight:                   1,
+		MaxConcurrent:            2,
+		MaxQueued:                10,
+		CPUSecondsPerWindow:      600,
+		MemoryMBSecondsPerWindow: 153600,
+	}
+	validConfig := QuotaConfig{
+		Enabled:                 true,
+		Window:                  time.Hour,
+		MaxConcurrentExecutions: 10,
+		DefaultTenant:           validTenant,
+		Tenants:                 map[string]TenantQuotaConfig{"0xabc": validTenant},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(c *QuotaConfig)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "ValidConfig_ShouldPass",
+			modify:  func(c *QuotaConfig) {},
+			wantErr: false,
+		},
+		{
+			name:    "DisabledEmptyConfig_ShouldPass",
+			modify:  func(c *QuotaConfig) { *c = QuotaConfig{} },
+			wantErr: false,
+		},
+		{
+			name:    "ZeroWindow_ShouldFail",
+			modify:  func(c *QuotaConfig) { c.Window = 0 },
+			wantErr: true,
+			errMsg:  "window must be positive",
+		},
+		{
+			name:    "ZeroMaxConcurrentExecutions_ShouldFail",
+			modify:  func(c *QuotaConfig) { c.MaxConcurrentExecutions = 0 },
+			wantErr: true,
+			errMsg:  "max_concurrent_executions must be positive",
+		},
+		{
+			name:    "ZeroDefaultWeight_ShouldFail",
+			modify:  func(c *QuotaConfig) { c.DefaultTenant.Weight = 0 },
+			wantErr: true,
+			errMsg:  "default_tenant: weight must be positive",
+		},
+		{
+			name: "InvalidTenantOverride_ShouldFail",
+			modify: func(c *QuotaConfig) {
+				c.Tenants = map[string]TenantQuotaConfig{"0xabc": {Weight: 1, MaxConcurrent: 0}}
+			},
+			wantErr: true,
+			errMsg:  "tenant '0xabc': max_concurrent must be positive",
+		},
+		{
+			name: "NegativeCPUBudget_ShouldFail",
+			modify: func(c *QuotaConfig) {
+				c.DefaultTenant.CPUSecondsPerWindow = -1
+			},
+			wantErr: true,
+			errMsg:  "cpu_seconds_per_window cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig
+			tt.modify(&config)
+			err := config.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestLanguageConfig_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -465,6 +649,76 @@ func TestLanguageConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "at least one file extension must be specified",
 		},
+		{
+			name: "ValidCacheMounts_ShouldPass",
+			config: LanguageConfig{
+				Language:    types.LanguageRust,
+				ImageName:   "rust:1.83-alpine",
+				RunCommand:  "rustc -O code.rs && ./code",
+				Extensions:  []string{".rs"},
+				CacheMounts: []string{"/srv/wheels:/wheels:ro"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "WritableCacheMount_ShouldFail",
+			config: LanguageConfig{
+				Language:    types.LanguageRust,
+				ImageName:   "rust:1.83-alpine",
+				RunCommand:  "rustc -O code.rs && ./code",
+				Extensions:  []string{".rs"},
+				CacheMounts: []string{"/var/cache/rust:/cache/rust:rw"},
+			},
+			wantErr: true,
+			errMsg:  "cache mount must be read-only (:ro)",
+		},
+		{
+			name: "ValidBuildCacheDir_ShouldPass",
+			config: LanguageConfig{
+				Language:      types.LanguageRust,
+				ImageName:     "rust:1.83-alpine",
+				RunCommand:    "rustc -O code.rs && ./code",
+				Extensions:    []string{".rs"},
+				BuildCacheDir: "/var/lib/triggerx/rust-build-cache",
+			},
+			wantErr: false,
+		},
+		{
+			name: "BuildCacheDirForInterpretedLanguage_ShouldFail",
+			config: LanguageConfig{
+				Language:      types.LanguagePy,
+				ImageName:     "python:3.12-alpine",
+				RunCommand:    "python -u code.py",
+				Extensions:    []string{".py"},
+				BuildCacheDir: "/var/lib/triggerx/py-build-cache",
+			},
+			wantErr: true,
+			errMsg:  "build_cache_dir is not supported for py",
+		},
+		{
+			name: "RelativeCacheMount_ShouldFail",
+			config: LanguageConfig{
+				Language:    types.LanguageRust,
+				ImageName:   "rust:1.83-alpine",
+				RunCommand:  "rustc -O code.rs && ./code",
+				Extensions:  []string{".rs"},
+				CacheMounts: []string{"cache:/cache/rust"},
+			},
+			wantErr: true,
+			errMsg:  "cache mount must be host:container:ro with absolute paths",
+		},
+		{
+			name: "InvalidCacheMountMode_ShouldFail",
+			config: LanguageConfig{
+				Language:    types.LanguagePyPinned,
+				ImageName:   "python:3.12-alpine",
+				RunCommand:  "python -u -B code.py",
+				Extensions:  []string{".py"},
+				CacheMounts: []string{"/srv/wheels:/wheels:z"},
+			},
+			wantErr: true,
+			errMsg:  "cache mount must be read-only (:ro)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -743,6 +997,54 @@ func TestValidationConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "timeout_seconds must be positive",
 		},
+		{
+			name: "LanguageMaxComplexityUnsupportedLanguage_ShouldFail",
+			config: ValidationConfig{
+				MaxFileSize:           1 * 1024 * 1024,
+				AllowedExtensions:     []string{".rs"},
+				MaxComplexity:         10,
+				TimeoutSeconds:        30,
+				LanguageMaxComplexity: map[string]float64{"cobol": 10},
+			},
+			wantErr: true,
+			errMsg:  "language_max_complexity for unsupported language: cobol",
+		},
+		{
+			name: "InvalidRustCrate_ShouldFail",
+			config: ValidationConfig{
+				MaxFileSize:       1 * 1024 * 1024,
+				AllowedExtensions: []string{".rs"},
+				MaxComplexity:     10,
+				TimeoutSeconds:    30,
+				Rust:              RustValidationConfig{AllowedCrates: []string{"serde-json"}},
+			},
+			wantErr: true,
+			errMsg:  "rust: invalid crate name: serde-json",
+		},
+		{
+			name: "NegativeMaxRequirements_ShouldFail",
+			config: ValidationConfig{
+				MaxFileSize:       1 * 1024 * 1024,
+				AllowedExtensions: []string{".py"},
+				MaxComplexity:     10,
+				TimeoutSeconds:    30,
+				PinnedPython:      PinnedPythonValidationConfig{MaxRequirements: -1},
+			},
+			wantErr: true,
+			errMsg:  "pinned_python: max_requirements cannot be negative",
+		},
+		{
+			name: "UnknownDenoPermission_ShouldFail",
+			config: ValidationConfig{
+				MaxFileSize:       1 * 1024 * 1024,
+				AllowedExtensions: []string{".ts"},
+				MaxComplexity:     10,
+				TimeoutSeconds:    30,
+				Deno:              DenoValidationConfig{AllowedPermissions: []string{"net", "all"}},
+			},
+			wantErr: true,
+			errMsg:  "deno: unknown deno permission: all",
+		},
 	}
 
 	for _, tt := range tests {
@@ -819,6 +1121,9 @@ func TestIsValidLanguage(t *testing.T) {
 		{"ValidJS_ShouldPass", types.LanguageJS, true},
 		{"ValidTS_ShouldPass", types.LanguageTS, true},
 		{"ValidNode_ShouldPass", types.LanguageNode, true},
+		{"ValidRust_ShouldPass", types.LanguageRust, true},
+		{"ValidPyPinned_ShouldPass", types.LanguagePyPinned, true},
+		{"ValidDeno_ShouldPass", types.LanguageDeno, true},
 		{"InvalidLanguage_ShouldFail", "invalid", false},
 		{"EmptyLanguage_ShouldFail", "", false},
 	}
@@ -830,3 +1135,66 @@ func TestIsValidLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestDenoValidationConfig_ValidatePermissionFlags(t *testing.T) {
+	cfg := DenoValidationConfig{
+		AllowedPermissions: []string{"net", "env"},
+		AllowedNetHosts:    []string{"api.coingecko.com", "eth-mainnet.g.alchemy.com"},
+	}
+
+	tests := []struct {
+		name        string
+		environment []string
+		wantErr     bool
+		errMsg      string
+	}{
+		{
+			name:        "NoPermissions_ShouldPass",
+			environment: []string{"DENO_NO_UPDATE_CHECK=1"},
+			wantErr:     false,
+		},
+		{
+			name:        "AllowedFlags_ShouldPass",
+			environment: []string{"DENO_PERMISSIONS=--allow-net=api.coingecko.com --allow-env=ALCHEMY_API_KEY"},
+			wantErr:     false,
+		},
+		{
+			name:        "PermissionNotAllowed_ShouldFail",
+			environment: []string{"DENO_PERMISSIONS=--allow-env --allow-run"},
+			wantErr:     true,
+			errMsg:      "deno permission not allowed: --allow-run",
+		},
+		{
+			name:        "AllowAll_ShouldFail",
+			environment: []string{"DENO_PERMISSIONS=-A"},
+			wantErr:     true,
+			errMsg:      "deno permission not allowed: -A",
+		},
+		{
+			name:        "UnrestrictedNet_ShouldFail",
+			environment: []string{"DENO_PERMISSIONS=--allow-net"},
+			wantErr:     true,
+			errMsg:      "--allow-net must list hosts from allowed_net_hosts",
+		},
+		{
+			name:        "NetHostNotAllowed_ShouldFail",
+			environment: []string{"DENO_PERMISSIONS=--allow-net=api.coingecko.com,evil.example"},
+			wantErr:     true,
+			errMsg:      "deno net host not allowed: evil.example",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cfg.validatePermissionFlags(tt.environment)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
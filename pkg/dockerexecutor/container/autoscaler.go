@@ -0,0 +1,448 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/metrics"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+)
+
+const (
+	// Used when autoscaling is disabled so pool stats still report wait percentiles
+	defaultWaitTimeWindow = time.Minute
+	// Upper bound on retained wait samples per pool
+	maxWaitSamples = 1024
+)
+
+type waitSample struct {
+	at       time.Time
+	duration time.Duration
+}
+
+// waitTimeTracker keeps recent container acquisition wait times for percentile calculation
+type waitTimeTracker struct {
+	mutex   sync.Mutex
+	window  time.Duration
+	samples []waitSample
+}
+
+func newWaitTimeTracker(window time.Duration) *waitTimeTracker {
+	if window <= 0 {
+		window = defaultWaitTimeWindow
+	}
+	return &waitTimeTracker{
+		window:  window,
+		samples: make([]waitSample, 0, 64),
+	}
+}
+
+func (w *waitTimeTracker) record(now time.Time, d time.Duration) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.samples = append(w.samples, waitSample{at: now, duration: d})
+	if len(w.samples) > maxWaitSamples {
+		w.samples = w.samples[len(w.samples)-maxWaitSamples:]
+	}
+}
+
+// snapshot returns the p50, p95, p99, mean and max wait over the tracking window
+func (w *waitTimeTracker) snapshot(now time.Time) (p50, p95, p99, mean, maxWait time.Duration) {
+	w.mutex.Lock()
+	cutoff := now.Add(-w.window)
+	firstValid := 0
+	for firstValid < len(w.samples) && w.samples[firstValid].at.Before(cutoff) {
+		firstValid++
+	}
+	w.samples = w.samples[firstValid:]
+
+	durations := make([]time.Duration, len(w.samples))
+	for i, s := range w.samples {
+		durations[i] = s.duration
+	}
+	w.mutex.Unlock()
+
+	if len(durations) == 0 {
+		return 0, 0, 0, 0, 0
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+
+	return percentile(durations, 0.50), percentile(durations, 0.95), percentile(durations, 0.99),
+		total / time.Duration(len(durations)), durations[len(durations)-1]
+}
+
+// percentile returns the nearest-rank percentile of an ascending slice
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// autoscalerState holds the mutable autoscaling bookkeeping of a pool
+type autoscalerState struct {
+	mutex           sync.Mutex
+	queueDepth      int64 // accessed atomically
+	target          int
+	floor           int // containers kept warm regardless of idleness
+	lastScaleUp     time.Time
+	scaleUpCount    int64
+	scaleDownCount  int64
+	lastDecision    *types.ScalingDecision
+	prewarmSchedule []time.Time
+}
+
+// enterQueue marks a caller as waiting for a container
+func (p *containerPool) enterQueue() {
+	depth := atomic.AddInt64(&p.autoscaler.queueDepth, 1)
+	metrics.PoolQueueDepth.WithLabelValues(string(p.language)).Set(float64(depth))
+}
+
+// leaveQueue marks a waiting caller as served or abandoned
+func (p *containerPool) leaveQueue() {
+	depth := atomic.AddInt64(&p.autoscaler.queueDepth, -1)
+	metrics.PoolQueueDepth.WithLabelValues(string(p.language)).Set(float64(depth))
+}
+
+func (p *containerPool) recordWait(d time.Duration) {
+	p.waitTimes.record(time.Now(), d)
+	metrics.PoolWaitDurationSeconds.WithLabelValues(string(p.language)).Observe(d.Seconds())
+}
+
+// schedulePrewarm merges upcoming execution timestamps into the pool's pre-warm schedule.
+// Past timestamps are dropped.
+func (p *containerPool) schedulePrewarm(timestamps []time.Time) {
+	now := time.Now()
+
+	p.autoscaler.mutex.Lock()
+	defer p.autoscaler.mutex.Unlock()
+
+	upcoming := make([]time.Time, 0, len(p.autoscaler.prewarmSchedule)+len(timestamps))
+	for _, ts := range append(p.autoscaler.prewarmSchedule, timestamps...) {
+		if !ts.Before(now) {
+			upcoming = append(upcoming, ts)
+		}
+	}
+	sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].Before(upcoming[j]) })
+	p.autoscaler.prewarmSchedule = upcoming
+
+	p.logger.Debugf("Pre-warm schedule for %s pool has %d upcoming executions", p.language, len(upcoming))
+}
+
+// prewarmDemand returns the largest number of scheduled executions expected to overlap
+// within one evaluation interval, considering only peaks starting inside the lead time.
+// Must be called with autoscaler.mutex held.
+func (p *containerPool) prewarmDemand(now time.Time) int {
+	cfg := p.config.BasePoolConfig.Autoscaling
+
+	// Drop timestamps that are already in the past
+	firstUpcoming := 0
+	for firstUpcoming < len(p.autoscaler.prewarmSchedule) && p.autoscaler.prewarmSchedule[firstUpcoming].Before(now) {
+		firstUpcoming++
+	}
+	p.autoscaler.prewarmSchedule = p.autoscaler.prewarmSchedule[firstUpcoming:]
+
+	schedule := p.autoscaler.prewarmSchedule
+	horizon := now.Add(cfg.PrewarmLeadTime)
+	burstWindow := cfg.EvaluationInterval
+
+	peak := 0
+	end := 0
+	for start := 0; start < len(schedule) && !schedule[start].After(horizon); start++ {
+		if end < start {
+			end = start
+		}
+		for end < len(schedule) && schedule[end].Sub(schedule[start]) < burstWindow {
+			end++
+		}
+		if count := end - start; count > peak {
+			peak = count
+		}
+	}
+	return peak
+}
+
+// evaluateScaling decides the container count the pool should converge to.
+// It does not create or remove containers itself.
+func (p *containerPool) evaluateScaling(now time.Time) *types.ScalingDecision {
+	cfg := p.config.BasePoolConfig.Autoscaling
+	minContainers := p.config.BasePoolConfig.MinContainers
+	maxContainers := p.config.BasePoolConfig.MaxContainers
+
+	p.mutex.RLock()
+	current := len(p.containers)
+	p.mutex.RUnlock()
+
+	queueDepth := int(atomic.LoadInt64(&p.autoscaler.queueDepth))
+	_, waitP95, _, _, _ := p.waitTimes.snapshot(now)
+
+	p.autoscaler.mutex.Lock()
+	defer p.autoscaler.mutex.Unlock()
+
+	decision := &types.ScalingDecision{
+		Action:     types.ScalingActionNone,
+		From:       current,
+		To:         current,
+		QueueDepth: queueDepth,
+		WaitP95:    waitP95,
+		Timestamp:  now,
+	}
+
+	target := current
+	if target < minContainers {
+		target = minContainers
+		decision.Action = types.ScalingActionFloor
+		decision.Reason = fmt.Sprintf("below min_containers (%d)", minContainers)
+	}
+
+	queuePressure := cfg.ScaleUpQueueDepth > 0 && queueDepth >= cfg.ScaleUpQueueDepth
+	waitPressure := cfg.ScaleUpWaitP95 > 0 && waitP95 >= cfg.ScaleUpWaitP95
+	cooledDown := now.Sub(p.autoscaler.lastScaleUp) >= cfg.ScaleUpCooldown
+	// Only scale-ups caused by load count towards scale_up_count and the cooldown
+	loadDriven := (queuePressure || waitPressure) && cooledDown && current < maxContainers
+	if loadDriven {
+		step := cfg.ScaleUpStep
+		if queueDepth > step {
+			step = queueDepth
+		}
+		if current+step > target {
+			target = current + step
+		}
+		decision.Action = types.ScalingActionScaleUp
+		if queuePressure {
+			decision.Reason = fmt.Sprintf("queue depth %d >= %d", queueDepth, cfg.ScaleUpQueueDepth)
+		} else {
+			decision.Reason = fmt.Sprintf("p95 wait %s >= %s", waitP95, cfg.ScaleUpWaitP95)
+		}
+	}
+
+	demand := p.prewarmDemand(now)
+	floor := minContainers
+	if demand > floor {
+		floor = demand
+	}
+	if floor > maxContainers {
+		floor = maxContainers
+	}
+	p.autoscaler.floor = floor
+
+	if demand > target {
+		target = demand
+		decision.Action = types.ScalingActionPrewarm
+		decision.Reason = fmt.Sprintf("%d executions scheduled within %s", demand, cfg.PrewarmLeadTime)
+	}
+
+	if target > maxContainers {
+		target = maxContainers
+	}
+	if target <= current && decision.Action != types.ScalingActionNone {
+		decision.Action = types.ScalingActionNone
+		decision.Reason = ""
+		loadDriven = false
+	}
+
+	decision.To = target
+	p.autoscaler.target = target
+	if loadDriven {
+		p.autoscaler.lastScaleUp = now
+		p.autoscaler.scaleUpCount++
+	}
+	if decision.Action != types.ScalingActionNone {
+		p.autoscaler.lastDecision = decision
+		metrics.PoolScalingDecisionsTotal.WithLabelValues(string(p.language), string(decision.Action)).Inc()
+	}
+	metrics.PoolTargetContainers.WithLabelValues(string(p.language)).Set(float64(target))
+
+	return decision
+}
+
+// scaleUp creates containers until the pool reaches the target or runs out of creation tokens
+func (p *containerPool) scaleUp(ctx context.Context, count int) int {
+	var wg sync.WaitGroup
+	var created int64
+
+acquire:
+	for i := 0; i < count; i++ {
+		select {
+		case <-p.creationSemaphore:
+		default:
+			// Pool is already at max_containers
+			break acquire
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.createPreparedContainer(ctx); err != nil {
+				p.creationSemaphore <- struct{}{}
+				p.logger.Warnf("Autoscaler failed to create container for %s pool: %v", p.language, err)
+				return
+			}
+			atomic.AddInt64(&created, 1)
+		}()
+	}
+
+	wg.Wait()
+	return int(created)
+}
+
+// reapIdleContainers removes ready containers idle for longer than the idle TTL,
+// never shrinking the pool below floor
+func (p *containerPool) reapIdleContainers(ctx context.Context, now time.Time, floor int) int {
+	idleTTL := p.config.BasePoolConfig.Autoscaling.IdleTTL
+
+	// Drain the ready queue so idle containers cannot be handed out while being removed
+	drained := make([]*types.PooledContainer, 0, len(p.readyQueue))
+drain:
+	for {
+		select {
+		case container := <-p.readyQueue:
+			drained = append(drained, container)
+		default:
+			break drain
+		}
+	}
+
+	p.mutex.Lock()
+	reaped := make([]string, 0)
+	for _, container := range drained {
+		idle := container.Status == types.ContainerStatusReady && now.Sub(container.LastUsed) >= idleTTL
+		if idle && len(p.containers) > floor {
+			delete(p.containers, container.ID)
+			p.stats.DestroyedCount++
+			reaped = append(reaped, container.ID)
+
+			select {
+			case p.creationSemaphore <- struct{}{}:
+			default:
+				p.logger.Warnf("Creation semaphore is full when trying to release token for reaped container %s", container.ID)
+			}
+			continue
+		}
+
+		select {
+		case p.readyQueue <- container:
+		default:
+			p.logger.Debugf("Ready queue full, container %s will be available on next request", container.ID)
+		}
+	}
+	p.updateStats()
+	p.mutex.Unlock()
+
+	for _, id := range reaped {
+		if err := p.manager.CleanupContainer(ctx, id); err != nil {
+			p.logger.Warnf("Failed to cleanup idle container %s: %v", id, err)
+		}
+	}
+
+	if len(reaped) > 0 {
+		metrics.PoolIdleReapedTotal.WithLabelValues(string(p.language)).Add(float64(len(reaped)))
+	}
+	return len(reaped)
+}
+
+// runAutoscaler performs one evaluate-and-apply cycle
+func (p *containerPool) runAutoscaler(ctx context.Context) {
+	now := time.Now()
+	decision := p.evaluateScaling(now)
+
+	if decision.To > decision.From {
+		created := p.scaleUp(ctx, decision.To-decision.From)
+		p.logger.Infof("Autoscaler %s for %s pool: %d -> %d (created %d), reason: %s",
+			decision.Action, p.language, decision.From, decision.To, created, decision.Reason)
+		return
+	}
+
+	p.autoscaler.mutex.Lock()
+	floor := p.autoscaler.floor
+	p.autoscaler.mutex.Unlock()
+
+	reaped := p.reapIdleContainers(ctx, now, floor)
+	if reaped == 0 {
+		return
+	}
+
+	p.mutex.RLock()
+	remaining := len(p.containers)
+	p.mutex.RUnlock()
+
+	p.autoscaler.mutex.Lock()
+	p.autoscaler.scaleDownCount++
+	p.autoscaler.lastDecision = &types.ScalingDecision{
+		Action:     types.ScalingActionScaleDown,
+		From:       remaining + reaped,
+		To:         remaining,
+		Reason:     fmt.Sprintf("%d containers idle for more than %s", reaped, p.config.BasePoolConfig.Autoscaling.IdleTTL),
+		QueueDepth: decision.QueueDepth,
+		WaitP95:    decision.WaitP95,
+		Timestamp:  now,
+	}
+	p.autoscaler.mutex.Unlock()
+
+	metrics.PoolScalingDecisionsTotal.WithLabelValues(string(p.language), string(types.ScalingActionScaleDown)).Inc()
+	p.logger.Infof("Autoscaler scaled down %s pool: %d -> %d", p.language, remaining+reaped, remaining)
+}
+
+func (p *containerPool) startAutoscalerRoutine() {
+	cfg := p.config.BasePoolConfig.Autoscaling
+	if !cfg.Enabled {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.EvaluationInterval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				p.runAutoscaler(context.Background())
+			case <-p.stopAutoscaler:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// fillScalingStats adds autoscaling state to a stats snapshot
+func (p *containerPool) fillScalingStats(stats *types.PoolStats) {
+	now := time.Now()
+	p50, p95, p99, mean, maxWait := p.waitTimes.snapshot(now)
+	stats.WaitTimeP50 = p50
+	stats.WaitTimeP95 = p95
+	stats.WaitTimeP99 = p99
+	stats.AverageWaitTime = mean
+	stats.MaxWaitTime = maxWait
+	stats.QueueDepth = int(atomic.LoadInt64(&p.autoscaler.queueDepth))
+	stats.MinContainers = p.config.BasePoolConfig.MinContainers
+	stats.MaxContainers = p.config.BasePoolConfig.MaxContainers
+
+	p.autoscaler.mutex.Lock()
+	defer p.autoscaler.mutex.Unlock()
+
+	stats.TargetContainers = p.autoscaler.target
+	stats.ScaleUpCount = p.autoscaler.scaleUpCount
+	stats.ScaleDownCount = p.autoscaler.scaleDownCount
+	if len(p.autoscaler.prewarmSchedule) > 0 {
+		stats.NextPrewarmAt = p.autoscaler.prewarmSchedule[0].Add(-p.config.BasePoolConfig.Autoscaling.PrewarmLeadTime)
+	}
+	if p.autoscaler.lastDecision != nil {
+		decision := *p.autoscaler.lastDecision
+		stats.LastScaleDecision = &decision
+	}
+}
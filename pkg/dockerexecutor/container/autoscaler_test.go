@@ -0,0 +1,252 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/client/docker"
+	"github.com/trigg3rX/triggerx-backend/pkg/client/docker/mocks"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// fakePoolManager records cleanups without going through the config provider
+type fakePoolManager struct {
+	dockerClient docker.DockerClientAPI
+	mutex        sync.Mutex
+	cleaned      []string
+}
+
+func (f *fakePoolManager) PullImage(ctx context.Context, imageName string) error { return nil }
+
+func (f *fakePoolManager) CleanupContainer(ctx context.Context, containerID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.cleaned = append(f.cleaned, containerID)
+	return nil
+}
+
+func (f *fakePoolManager) GetDockerClient() docker.DockerClientAPI { return f.dockerClient }
+
+func setupAutoscalerTest(t *testing.T) (*containerPool, *fakePoolManager) {
+	manager := &fakePoolManager{dockerClient: mocks.NewMockDockerClient()}
+	poolConfig := config.LanguagePoolConfig{
+		BasePoolConfig: config.BasePoolConfig{
+			MaxContainers:       5,
+			MinContainers:       1,
+			MaxWaitTime:         time.Second,
+			HealthCheckInterval: time.Hour,
+			Autoscaling: config.AutoscalingConfig{
+				Enabled:            true,
+				EvaluationInterval: time.Hour, // evaluations are driven by the tests
+				ScaleUpQueueDepth:  2,
+				ScaleUpWaitP95:     500 * time.Millisecond,
+				ScaleUpStep:        1,
+				ScaleUpCooldown:    10 * time.Second,
+				IdleTTL:            time.Minute,
+				WaitTimeWindow:     time.Minute,
+				PrewarmLeadTime:    time.Minute,
+			},
+		},
+		LanguageConfig: config.LanguageConfig{
+			Language:  types.LanguageGo,
+			ImageName: "golang:1.24-alpine",
+		},
+	}
+
+	pool := newContainerPool(poolConfig, manager, logging.NewNoOpLogger())
+	t.Cleanup(func() {
+		close(pool.stopHealth)
+		close(pool.stopAutoscaler)
+	})
+	return pool, manager
+}
+
+func addReadyContainer(pool *containerPool, id string, lastUsed time.Time) {
+	pooledContainer := &types.PooledContainer{
+		ID:       id,
+		Status:   types.ContainerStatusReady,
+		LastUsed: lastUsed,
+		Language: types.LanguageGo,
+	}
+	pool.containers[id] = pooledContainer
+	<-pool.creationSemaphore
+	pool.readyQueue <- pooledContainer
+}
+
+func TestWaitTimeTracker_Percentiles(t *testing.T) {
+	tracker := newWaitTimeTracker(time.Minute)
+	now := time.Now()
+
+	for i := 1; i <= 100; i++ {
+		tracker.record(now, time.Duration(i)*time.Millisecond)
+	}
+
+	p50, p95, p99, mean, maxWait := tracker.snapshot(now)
+	assert.Equal(t, 50*time.Millisecond, p50)
+	assert.Equal(t, 95*time.Millisecond, p95)
+	assert.Equal(t, 99*time.Millisecond, p99)
+	assert.Equal(t, 50500*time.Microsecond, mean)
+	assert.Equal(t, 100*time.Millisecond, maxWait)
+}
+
+func TestWaitTimeTracker_DropsSamplesOutsideWindow(t *testing.T) {
+	tracker := newWaitTimeTracker(time.Minute)
+	now := time.Now()
+
+	tracker.record(now.Add(-2*time.Minute), 10*time.Second)
+	tracker.record(now, 10*time.Millisecond)
+
+	_, p95, _, _, maxWait := tracker.snapshot(now)
+	assert.Equal(t, 10*time.Millisecond, p95)
+	assert.Equal(t, 10*time.Millisecond, maxWait)
+}
+
+func TestAutoscaler_EvaluateScaling_QueueDepthScalesUp(t *testing.T) {
+	pool, _ := setupAutoscalerTest(t)
+	addReadyContainer(pool, "c1", time.Now())
+	pool.autoscaler.queueDepth = 3
+
+	decision := pool.evaluateScaling(time.Now())
+
+	assert.Equal(t, types.ScalingActionScaleUp, decision.Action)
+	assert.Equal(t, 1, decision.From)
+	assert.Equal(t, 4, decision.To)
+	assert.Contains(t, decision.Reason, "queue depth")
+	assert.Equal(t, int64(1), pool.autoscaler.scaleUpCount)
+}
+
+func TestAutoscaler_EvaluateScaling_WaitTimeScalesUp(t *testing.T) {
+	pool, _ := setupAutoscalerTest(t)
+	addReadyContainer(pool, "c1", time.Now())
+	pool.waitTimes.record(time.Now(), time.Second)
+
+	decision := pool.evaluateScaling(time.Now())
+
+	assert.Equal(t, types.ScalingActionScaleUp, decision.Action)
+	assert.Equal(t, 2, decision.To)
+	assert.Contains(t, decision.Reason, "p95 wait")
+}
+
+func TestAutoscaler_EvaluateScaling_RespectsCooldownAndMax(t *testing.T) {
+	pool, _ := setupAutoscalerTest(t)
+	addReadyContainer(pool, "c1", time.Now())
+	pool.autoscaler.queueDepth = 10
+
+	now := time.Now()
+	first := pool.evaluateScaling(now)
+	assert.Equal(t, 5, first.To, "target must be capped at max_containers")
+
+	second := pool.evaluateScaling(now.Add(time.Second))
+	assert.Equal(t, types.ScalingActionNone, second.Action, "scale up must wait for the cooldown")
+}
+
+func TestAutoscaler_EvaluateScaling_NoPressure(t *testing.T) {
+	pool, _ := setupAutoscalerTest(t)
+	addReadyContainer(pool, "c1", time.Now())
+
+	decision := pool.evaluateScaling(time.Now())
+
+	assert.Equal(t, types.ScalingActionNone, decision.Action)
+	assert.Equal(t, 1, decision.To)
+}
+
+func TestAutoscaler_PrewarmDemand_UsesScheduledPeak(t *testing.T) {
+	pool, _ := setupAutoscalerTest(t)
+	pool.config.BasePoolConfig.Autoscaling.EvaluationInterval = 5 * time.Second
+	addReadyContainer(pool, "c1", time.Now())
+
+	now := time.Now()
+	peak := now.Add(30 * time.Second)
+	pool.schedulePrewarm([]time.Time{
+		now.Add(-time.Minute), // already past, ignored
+		now.Add(10 * time.Second),
+		peak, peak.Add(time.Second), peak.Add(2 * time.Second),
+		now.Add(10 * time.Minute), // beyond the lead time
+	})
+
+	decision := pool.evaluateScaling(now)
+
+	assert.Equal(t, types.ScalingActionPrewarm, decision.Action)
+	assert.Equal(t, 3, decision.To)
+	assert.Equal(t, 3, pool.autoscaler.floor)
+	assert.Zero(t, pool.autoscaler.scaleUpCount, "pre-warming is not a load-driven scale up")
+}
+
+func TestAutoscaler_EvaluateScaling_MinFloorIsNotAScaleUp(t *testing.T) {
+	pool, _ := setupAutoscalerTest(t)
+	pool.config.BasePoolConfig.MinContainers = 2
+
+	decision := pool.evaluateScaling(time.Now())
+
+	assert.Equal(t, types.ScalingActionFloor, decision.Action)
+	assert.Equal(t, 2, decision.To)
+	assert.Zero(t, pool.autoscaler.scaleUpCount)
+}
+
+func TestAutoscaler_ReapIdleContainers(t *testing.T) {
+	pool, manager := setupAutoscalerTest(t)
+	now := time.Now()
+	addReadyContainer(pool, "idle-1", now.Add(-5*time.Minute))
+	addReadyContainer(pool, "idle-2", now.Add(-5*time.Minute))
+	addReadyContainer(pool, "fresh", now)
+
+	reaped := pool.reapIdleContainers(context.Background(), now, 1)
+
+	assert.Equal(t, 2, reaped)
+	assert.Len(t, pool.containers, 1)
+	assert.Contains(t, pool.containers, "fresh")
+	assert.ElementsMatch(t, []string{"idle-1", "idle-2"}, manager.cleaned)
+	assert.Equal(t, 1, len(pool.readyQueue))
+	assert.Equal(t, 4, len(pool.creationSemaphore), "reaped containers must release creation tokens")
+}
+
+func TestAutoscaler_ReapIdleContainers_KeepsFloor(t *testing.T) {
+	pool, manager := setupAutoscalerTest(t)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		addReadyContainer(pool, fmt.Sprintf("idle-%d", i), now.Add(-5*time.Minute))
+	}
+
+	reaped := pool.reapIdleContainers(context.Background(), now, 2)
+
+	assert.Equal(t, 1, reaped)
+	assert.Len(t, pool.containers, 2)
+	assert.Len(t, manager.cleaned, 1)
+}
+
+func TestAutoscaler_RunAutoscaler_RecordsScaleDown(t *testing.T) {
+	pool, _ := setupAutoscalerTest(t)
+	now := time.Now()
+	addReadyContainer(pool, "idle-1", now.Add(-5*time.Minute))
+	addReadyContainer(pool, "idle-2", now.Add(-5*time.Minute))
+
+	pool.runAutoscaler(context.Background())
+
+	stats := pool.getStats()
+	require.NotNil(t, stats.LastScaleDecision)
+	assert.Equal(t, types.ScalingActionScaleDown, stats.LastScaleDecision.Action)
+	assert.Equal(t, 2, stats.LastScaleDecision.From)
+	assert.Equal(t, 1, stats.LastScaleDecision.To)
+	assert.Equal(t, int64(1), stats.ScaleDownCount)
+	assert.Equal(t, 1, stats.MinContainers)
+	assert.Equal(t, 5, stats.MaxContainers)
+}
+
+func TestAutoscaler_GetContainer_TracksWaitTime(t *testing.T) {
+	pool, _ := setupAutoscalerTest(t)
+	addReadyContainer(pool, "c1", time.Now())
+
+	_, err := pool.getContainer(context.Background())
+	require.NoError(t, err)
+
+	stats := pool.getStats()
+	assert.Equal(t, 0, stats.QueueDepth)
+	assert.Len(t, pool.waitTimes.samples, 1)
+}
@@ -0,0 +1,142 @@
+package container
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/scripts"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+)
+
+// errBuildFailed marks compilation errors in the user's code, as opposed to failures to run a build
+var errBuildFailed = errors.New("build failed")
+
+// copyBuiltBinaryToContainer copies the compiled binary of the file into the container as
+// /code/code, for languages with a build cache. It does nothing for other languages.
+func (m *containerManager) copyBuiltBinaryToContainer(ctx context.Context, containerID string, filePath string, language types.Language) error {
+	poolConfig, exists := m.config.GetLanguagePoolConfig(language)
+	if !exists || poolConfig.LanguageConfig.BuildCacheDir == "" || scripts.GetBuildScript(language) == "" {
+		return nil
+	}
+
+	source, err := m.fileSystem.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	binary, err := m.buildBinary(ctx, poolConfig, language, source)
+	if err != nil {
+		return err
+	}
+	return m.copyContentToContainer(ctx, containerID, "code", 0755, binary)
+}
+
+// buildBinary returns the binary of source from the build cache, compiling it on a miss.
+// Binaries are keyed by the sha256 of the source computed here, and only the executor
+// writes the cache: pool containers, which run every tenant's code, never see it.
+func (m *containerManager) buildBinary(ctx context.Context, poolConfig config.LanguagePoolConfig, language types.Language, source []byte) ([]byte, error) {
+	cacheDir := poolConfig.LanguageConfig.BuildCacheDir
+	sum := sha256.Sum256(source)
+	hash := hex.EncodeToString(sum[:])
+	binaryPath := filepath.Join(cacheDir, hash)
+
+	if binary, err := m.fileSystem.ReadFile(binaryPath); err == nil {
+		m.logger.Debugf("Using cached %s binary %s", language, hash)
+		return binary, nil
+	}
+
+	// The build container only sees a scratch directory, which the binary is moved out of
+	scratchDir := filepath.Join(cacheDir, fmt.Sprintf(".build-%s-%d", hash[:16], time.Now().UnixNano()))
+	if err := m.fileSystem.MkdirAll(scratchDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create build directory: %w", err)
+	}
+	defer func() {
+		if err := m.fileSystem.RemoveAll(scratchDir); err != nil {
+			m.logger.Warnf("Failed to remove build directory %s: %v", scratchDir, err)
+		}
+	}()
+	if err := m.fileSystem.WriteFile(filepath.Join(scratchDir, "code.rs"), source, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write build source: %w", err)
+	}
+
+	exitCode, err := m.runBuildContainer(ctx, poolConfig, language, scratchDir)
+	if err != nil {
+		return nil, err
+	}
+	if exitCode != 0 {
+		buildLog, _ := m.fileSystem.ReadFile(filepath.Join(scratchDir, "build.log"))
+		return nil, fmt.Errorf("%w: %s", errBuildFailed, strings.TrimSpace(string(buildLog)))
+	}
+
+	builtPath := filepath.Join(scratchDir, "code")
+	binary, err := m.fileSystem.ReadFile(builtPath)
+	if err != nil {
+		return nil, fmt.Errorf("build produced no binary: %w", err)
+	}
+	// Concurrent builds of the same source publish the same binary, the rename keeps it whole
+	if err := m.fileSystem.Rename(builtPath, binaryPath); err != nil {
+		return nil, fmt.Errorf("failed to publish binary: %w", err)
+	}
+
+	m.logger.Infof("Built %s binary %s", language, hash)
+	return binary, nil
+}
+
+// runBuildContainer compiles the source in scratchDir in a one-shot container without
+// network access or privileges, returning the exit code of the build script
+func (m *containerManager) runBuildContainer(ctx context.Context, poolConfig config.LanguagePoolConfig, language types.Language, scratchDir string) (int64, error) {
+	if timeout := time.Duration(poolConfig.DockerConfig.TimeoutSeconds) * time.Second; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	envVars := make([]string, 0, len(poolConfig.DockerConfig.Environment)+len(poolConfig.LanguageConfig.Environment))
+	envVars = append(envVars, poolConfig.DockerConfig.Environment...)
+	envVars = append(envVars, poolConfig.LanguageConfig.Environment...)
+
+	containerConfig := &container.Config{
+		Image:      poolConfig.LanguageConfig.ImageName,
+		Cmd:        []string{"sh", "-c", scripts.GetBuildScript(language)},
+		WorkingDir: "/build",
+		Env:        envVars,
+	}
+	hostConfig := &container.HostConfig{
+		Binds:       []string{fmt.Sprintf("%s:/build:rw", scratchDir)},
+		NetworkMode: "none",
+		SecurityOpt: []string{"no-new-privileges"},
+		Resources: container.Resources{
+			Memory:   int64(types.MemoryLimitBytes(poolConfig.DockerConfig.MemoryLimit)),
+			NanoCPUs: int64(poolConfig.DockerConfig.CPULimit * 1e9),
+		},
+	}
+
+	resp, err := m.dockerClient.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create build container: %w", err)
+	}
+	defer func() {
+		if err := m.dockerClient.ContainerRemove(context.Background(), resp.ID, container.RemoveOptions{Force: true}); err != nil {
+			m.logger.Warnf("Failed to remove build container %s: %v", resp.ID, err)
+		}
+	}()
+
+	if err := m.dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return 0, fmt.Errorf("failed to start build container: %w", err)
+	}
+
+	statusCh, errCh := m.dockerClient.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	select {
+	case status := <-statusCh:
+		return status.StatusCode, nil
+	case err := <-errCh:
+		return 0, fmt.Errorf("failed to wait for build container: %w", err)
+	}
+}
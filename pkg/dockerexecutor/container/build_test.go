@@ -0,0 +1,74 @@
+package container
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/client/docker/mocks"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	fs "github.com/trigg3rX/triggerx-backend/pkg/filesystem"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+func setupBuildTest(t *testing.T) (*containerManager, *mocks.MockDockerClient, *fs.MockFileSystem, config.LanguagePoolConfig) {
+	mockDockerClient := mocks.NewMockDockerClient()
+	mockFileSystem := fs.NewMockFileSystem()
+	manager, err := NewContainerManager(mockDockerClient, mockFileSystem, config.NewDefaultMockConfigProvider(t), &logging.NoOpLogger{})
+	require.NoError(t, err)
+
+	poolConfig := config.LanguagePoolConfig{
+		DockerConfig: config.DockerContainerConfig{
+			TimeoutSeconds: 30,
+			MemoryLimit:    "512m",
+			CPULimit:       1.0,
+			NetworkMode:    "bridge",
+		},
+		LanguageConfig: config.LanguageConfig{
+			Language:      types.LanguageRust,
+			ImageName:     "rust:1.83-alpine",
+			BuildCacheDir: "/var/lib/triggerx/rust-build-cache",
+		},
+	}
+	return manager, mockDockerClient, mockFileSystem, poolConfig
+}
+
+func TestBuildBinary_CacheHit(t *testing.T) {
+	manager, mockDockerClient, mockFileSystem, poolConfig := setupBuildTest(t)
+	source := []byte("fn main() {}")
+	sum := sha256.Sum256(source)
+	binaryPath := filepath.Join(poolConfig.LanguageConfig.BuildCacheDir, hex.EncodeToString(sum[:]))
+	require.NoError(t, mockFileSystem.WriteFile(binaryPath, []byte("binary"), 0755))
+
+	binary, err := manager.buildBinary(context.Background(), poolConfig, types.LanguageRust, source)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte("binary"), binary)
+	assert.Empty(t, mockDockerClient.ContainerCreateCalls)
+}
+
+func TestBuildBinary_CacheMissRunsIsolatedBuild(t *testing.T) {
+	manager, mockDockerClient, _, poolConfig := setupBuildTest(t)
+
+	// The mock build exits cleanly without producing a binary
+	_, err := manager.buildBinary(context.Background(), poolConfig, types.LanguageRust, []byte("fn main() {}"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "build produced no binary")
+	require.Len(t, mockDockerClient.ContainerCreateCalls, 1)
+
+	call := mockDockerClient.ContainerCreateCalls[0]
+	assert.Equal(t, poolConfig.LanguageConfig.ImageName, call.Config.Image)
+	assert.Equal(t, container.NetworkMode("none"), call.HostConfig.NetworkMode)
+	assert.False(t, call.HostConfig.Privileged)
+	require.Len(t, call.HostConfig.Binds, 1)
+	assert.Contains(t, call.HostConfig.Binds[0], ".build-")
+	assert.Contains(t, call.HostConfig.Binds[0], ":/build:rw")
+	assert.NotContains(t, call.HostConfig.Binds[0], poolConfig.LanguageConfig.BuildCacheDir+":")
+}
@@ -5,6 +5,7 @@ package container
 
 import (
 	"context"
+	"time"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/client/docker"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
@@ -19,6 +20,7 @@ type poolAPI interface {
 	getStats() *types.PoolStats
 	getHealthCheckStats() (total, toCheck, inError int)
 	markContainerAsFailed(containerID string, err error)
+	schedulePrewarm(timestamps []time.Time)
 	close(ctx context.Context) error
 }
 
@@ -39,6 +41,7 @@ type ContainerManagerAPI interface {
 	CleanupContainer(ctx context.Context, containerID string) error
 	KillExecProcess(ctx context.Context, execID string) error
 	MarkContainerAsFailed(containerID string, language types.Language, err error)
+	SchedulePrewarm(language types.Language, timestamps []time.Time) error
 	Close(ctx context.Context) error
 }
 
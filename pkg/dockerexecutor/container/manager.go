@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -267,6 +268,25 @@ func (m *containerManager) MarkContainerAsFailed(containerID string, language ty
 	pool.markContainerAsFailed(containerID, err)
 }
 
+// SchedulePrewarm hands the autoscaler of a language pool the upcoming execution timestamps
+// (e.g. time-job next execution timestamps) so it can pre-warm containers before peaks
+func (m *containerManager) SchedulePrewarm(language types.Language, timestamps []time.Time) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if !m.initialized {
+		return fmt.Errorf("docker manager not initialized")
+	}
+
+	pool, exists := m.pools[language]
+	if !exists {
+		return fmt.Errorf("no pool available for language: %s", language)
+	}
+
+	pool.schedulePrewarm(timestamps)
+	return nil
+}
+
 // ExecuteInContainerWithLanguage executes code in a container using language-specific setup
 func (m *containerManager) ExecuteInContainer(ctx context.Context, containerID string, filePath string, language types.Language) (*types.ExecutionResult, string, error) {
 	m.logger.Infof("Executing file %s in container %s with language %s", filePath, containerID, language)
@@ -407,6 +427,24 @@ func (m *containerManager) executeCodeWithFileCopy(ctx context.Context, containe
 		return nil, "", fmt.Errorf("failed to copy file to container: %w", err)
 	}
 
+	// Languages with a build cache run the binary a build container compiled for them
+	if err := m.copyBuiltBinaryToContainer(ctx, containerID, filePath, language); err != nil {
+		if !errors.Is(err, errBuildFailed) {
+			m.returnExecutionResult(result)
+			return nil, "", fmt.Errorf("failed to build code: %w", err)
+		}
+		// Compilation errors are the user's, report them like a failed execution
+		result.Success = false
+		result.Error = err
+		result.Output = err.Error()
+		go func() {
+			if err := m.runCleanupScript(context.Background(), containerID, language); err != nil {
+				m.logger.Warnf("Failed to run cleanup script for container %s: %v", containerID, err)
+			}
+		}()
+		return result, "", nil
+	}
+
 	// Step 1: Run setup script (warming up caches, etc.)
 	if err := m.runSetupScript(ctx, containerID, language); err != nil {
 		// Return the result to pool since we're not using it
@@ -705,12 +743,21 @@ func (m *containerManager) copyFileToContainerOptimized(ctx context.Context, con
 		targetFile = "code.py"
 	case types.LanguageJS, types.LanguageNode:
 		targetFile = "code.js"
-	case types.LanguageTS:
+	case types.LanguageTS, types.LanguageDeno:
 		targetFile = "code.ts"
+	case types.LanguageRust:
+		targetFile = "code.rs"
+	case types.LanguagePyPinned:
+		targetFile = "code.py"
 	default:
 		targetFile = "code.go"
 	}
 
+	return m.copyContentToContainer(ctx, containerID, targetFile, 0644, content)
+}
+
+// copyContentToContainer writes content to /code/name in the container
+func (m *containerManager) copyContentToContainer(ctx context.Context, containerID string, name string, mode int64, content []byte) error {
 	// Create a tar archive in memory using pooled buffer
 	buf := m.getBytesBuffer()
 	defer m.returnBytesBuffer(buf)
@@ -718,8 +765,8 @@ func (m *containerManager) copyFileToContainerOptimized(ctx context.Context, con
 
 	// Create tar header
 	header := &tar.Header{
-		Name: targetFile,
-		Mode: 0644,
+		Name: name,
+		Mode: mode,
 		Size: int64(len(content)),
 	}
 
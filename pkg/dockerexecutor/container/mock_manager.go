@@ -12,6 +12,7 @@ package container
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	types "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
 	gomock "go.uber.org/mock/gomock"
@@ -114,6 +115,18 @@ func (mr *MockpoolAPIMockRecorder) initialize(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "initialize", reflect.TypeOf((*MockpoolAPI)(nil).initialize), ctx)
 }
 
+// schedulePrewarm mocks base method.
+func (m *MockpoolAPI) schedulePrewarm(timestamps []time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "schedulePrewarm", timestamps)
+}
+
+// schedulePrewarm indicates an expected call of schedulePrewarm.
+func (mr *MockpoolAPIMockRecorder) schedulePrewarm(timestamps any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "schedulePrewarm", reflect.TypeOf((*MockpoolAPI)(nil).schedulePrewarm), timestamps)
+}
+
 // returnContainer mocks base method.
 func (m *MockpoolAPI) returnContainer(container *types.PooledContainer) error {
 	m.ctrl.T.Helper()
@@ -351,3 +364,17 @@ func (mr *MockContainerManagerAPIMockRecorder) ReturnContainer(container any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnContainer", reflect.TypeOf((*MockContainerManagerAPI)(nil).ReturnContainer), container)
 }
+
+// SchedulePrewarm mocks base method.
+func (m *MockContainerManagerAPI) SchedulePrewarm(language types.Language, timestamps []time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePrewarm", language, timestamps)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SchedulePrewarm indicates an expected call of SchedulePrewarm.
+func (mr *MockContainerManagerAPIMockRecorder) SchedulePrewarm(language, timestamps any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePrewarm", reflect.TypeOf((*MockContainerManagerAPI)(nil).SchedulePrewarm), language, timestamps)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/trigg3rX/triggerx-backend/pkg/client/docker"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/metrics"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/scripts"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
@@ -37,6 +38,9 @@ type containerPool struct {
 	readyQueue        chan *types.PooledContainer // Channel for ready containers
 	stopHealth        chan struct{}               // Channel to stop health check routine
 	creationSemaphore chan struct{}               // Semaphore to control container creation
+	stopAutoscaler    chan struct{}               // Channel to stop autoscaler routine
+	autoscaler        autoscalerState
+	waitTimes         *waitTimeTracker
 }
 
 func newContainerPool(cfg config.LanguagePoolConfig, manager ContainerManager, logger logging.Logger) *containerPool {
@@ -50,6 +54,8 @@ func newContainerPool(cfg config.LanguagePoolConfig, manager ContainerManager, l
 		readyQueue:        make(chan *types.PooledContainer, cfg.BasePoolConfig.MaxContainers), // Buffer for ready containers
 		stopHealth:        make(chan struct{}),
 		creationSemaphore: make(chan struct{}, cfg.BasePoolConfig.MaxContainers),
+		stopAutoscaler:    make(chan struct{}),
+		waitTimes:         newWaitTimeTracker(cfg.BasePoolConfig.Autoscaling.WaitTimeWindow),
 		stats: &types.PoolStats{
 			Language:          cfg.LanguageConfig.Language,
 			TotalContainers:   0,
@@ -74,6 +80,9 @@ func newContainerPool(cfg config.LanguagePoolConfig, manager ContainerManager, l
 	// Start health check routine
 	pool.startHealthCheckRoutine()
 
+	// Start autoscaler routine (no-op when autoscaling is disabled)
+	pool.startAutoscalerRoutine()
+
 	return pool
 }
 
@@ -119,7 +128,19 @@ func (p *containerPool) initialize(ctx context.Context) error {
 	return nil
 }
 
+// getContainer acquires a container while tracking queue depth and wait time for the autoscaler
 func (p *containerPool) getContainer(ctx context.Context) (*types.PooledContainer, error) {
+	startTime := time.Now()
+	p.enterQueue()
+	container, err := p.acquireContainer(ctx)
+	p.leaveQueue()
+	if err == nil {
+		p.recordWait(time.Since(startTime))
+	}
+	return container, err
+}
+
+func (p *containerPool) acquireContainer(ctx context.Context) (*types.PooledContainer, error) {
 	timer := time.NewTimer(p.config.BasePoolConfig.MaxWaitTime)
 	defer timer.Stop()
 
@@ -206,6 +227,7 @@ func (p *containerPool) returnContainer(container *types.PooledContainer) error
 		p.logger.Debugf("Container %s reset successfully, marking as ready", container.ID)
 		pooledContainer.Status = types.ContainerStatusReady
 		pooledContainer.Error = nil // Clear any previous errors
+		pooledContainer.LastUsed = time.Now() // Idle time for the autoscaler counts from here
 		p.updateStats()
 
 		// Add container to ready queue for immediate availability
@@ -313,11 +335,15 @@ func (p *containerPool) createContainer(ctx context.Context, codePath string) (s
 		Env:        envVars,
 	}
 
+	// Cache mounts are shared by every container of the pool, e.g. compiled binaries or a wheel mirror
+	binds := []string{
+		fmt.Sprintf("%s:/code:rw", hostMountPath),
+		"/var/run/dockerexecutor.sock:/var/run/dockerexecutor.sock",
+	}
+	binds = append(binds, p.config.LanguageConfig.CacheMounts...)
+
 	hostConfig := &container.HostConfig{
-		Binds: []string{
-			fmt.Sprintf("%s:/code:rw", hostMountPath),
-			"/var/run/dockerexecutor.sock:/var/run/dockerexecutor.sock",
-		},
+		Binds: binds,
 		Resources: container.Resources{
 			Memory:   int64(types.MemoryLimitBytes(p.config.DockerConfig.MemoryLimit)),
 			NanoCPUs: int64(p.config.DockerConfig.CPULimit * 1e9),
@@ -474,6 +500,12 @@ func (p *containerPool) verifyContainerReady(ctx context.Context, containerID st
 		testCmd = "cd /code && node --version"
 	case types.LanguageTS:
 		testCmd = "cd /code && tsc --version"
+	case types.LanguageRust:
+		testCmd = "cd /code && rustc --version"
+	case types.LanguagePyPinned:
+		testCmd = "cd /code && python --version && pip --version"
+	case types.LanguageDeno:
+		testCmd = "cd /code && deno --version"
 	default:
 		testCmd = "cd /code && echo 'ready'"
 	}
@@ -743,14 +775,21 @@ func (p *containerPool) updateStats() {
 		p.stats.UtilizationRate = float64(busyCount) / float64(p.stats.TotalContainers)
 	}
 	p.statsMutex.Unlock()
+
+	lang := string(p.language)
+	metrics.PoolContainers.WithLabelValues(lang, "ready").Set(float64(readyCount))
+	metrics.PoolContainers.WithLabelValues(lang, "busy").Set(float64(busyCount))
+	metrics.PoolContainers.WithLabelValues(lang, "error").Set(float64(errorCount))
 }
 
 func (p *containerPool) getStats() *types.PoolStats {
 	p.statsMutex.RLock()
-	defer p.statsMutex.RUnlock()
 
 	// Create a copy to avoid race conditions
 	stats := *p.stats
+	p.statsMutex.RUnlock()
+
+	p.fillScalingStats(&stats)
 	return &stats
 }
 
@@ -758,8 +797,9 @@ func (p *containerPool) close(ctx context.Context) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	// Stop health check routine
+	// Stop health check and autoscaler routines
 	close(p.stopHealth)
+	close(p.stopAutoscaler)
 
 	// Cleanup all containers
 	for id := range p.containers {
@@ -23,6 +23,7 @@ type CodeExecutor interface {
 	InitializeLanguagePools(ctx context.Context, languages []types.Language) error
 	GetSupportedLanguages() []types.Language
 	IsLanguageSupported(language types.Language) bool
+	SchedulePrewarm(language types.Language, timestamps []time.Time) error
 	GetActiveExecutions() []*types.ExecutionContext
 	GetAlerts(severity string, limit int) []execution.Alert
 	ClearAlerts()
@@ -78,6 +79,16 @@ func NewDockerExecutorFromFile(configFilePath string, logger logging.Logger) (*D
 		return nil, fmt.Errorf("failed to create config provider: %w", err)
 	}
 
+	return NewDockerExecutorFromConfig(configProvider, logger)
+}
+
+// NewDockerExecutorFromConfig creates a new Docker manager with the default executor for a
+// loaded configuration
+func NewDockerExecutorFromConfig(configProvider *config.ConfigProvider, logger logging.Logger) (*DockerExecutor, error) {
+	if logger == nil {
+		return nil, fmt.Errorf("logger cannot be nil")
+	}
+
 	// Create default implementations
 	httpClient, err := httppkg.NewHTTPClient(httppkg.DefaultHTTPRetryConfig(), logger)
 	if err != nil {
@@ -296,6 +307,22 @@ func (de *DockerExecutor) IsLanguageSupported(language types.Language) bool {
 	return de.executor.IsLanguageSupported(language)
 }
 
+// SchedulePrewarm registers upcoming execution timestamps for a language so its pool
+// can pre-warm containers ahead of predictable peaks
+func (de *DockerExecutor) SchedulePrewarm(language types.Language, timestamps []time.Time) error {
+	de.mutex.RLock()
+	defer de.mutex.RUnlock()
+
+	if !de.initialized {
+		return fmt.Errorf("docker manager not initialized")
+	}
+	if de.closed {
+		return fmt.Errorf("docker manager is closed")
+	}
+
+	return de.executor.SchedulePrewarm(language, timestamps)
+}
+
 // GetActiveExecutions returns all currently active executions
 func (de *DockerExecutor) GetActiveExecutions() []*types.ExecutionContext {
 	de.mutex.RLock()
@@ -2,6 +2,7 @@ package execution
 
 import (
 	"context"
+	"time"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/container"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/file"
@@ -65,6 +66,11 @@ func (a *ContainerManagerAdapter) IsLanguageSupported(language types.Language) b
 	return a.manager.IsLanguageSupported(language)
 }
 
+// SchedulePrewarm implements execution.ContainerManager.SchedulePrewarm
+func (a *ContainerManagerAdapter) SchedulePrewarm(language types.Language, timestamps []time.Time) error {
+	return a.manager.SchedulePrewarm(language, timestamps)
+}
+
 // Close implements execution.ContainerManager.Close
 func (a *ContainerManagerAdapter) Close(ctx context.Context) error {
 	return a.manager.Close(ctx)
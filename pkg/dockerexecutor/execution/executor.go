@@ -138,6 +138,11 @@ func (e *codeExecutor) IsLanguageSupported(language types.Language) bool {
 	return e.pipeline.containerMgr.IsLanguageSupported(language)
 }
 
+// SchedulePrewarm forwards upcoming execution timestamps to the language pool autoscaler
+func (e *codeExecutor) SchedulePrewarm(language types.Language, timestamps []time.Time) error {
+	return e.pipeline.containerMgr.SchedulePrewarm(language, timestamps)
+}
+
 func (e *codeExecutor) GetActiveExecutions() []*types.ExecutionContext {
 	return e.monitor.getActiveExecutions()
 }
@@ -0,0 +1,108 @@
+//go:build integration
+// +build integration
+
+package execution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	httppkg "github.com/trigg3rX/triggerx-backend/pkg/http"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// These tests run real containers for the rust, pinned python and deno pools defined in
+// config/docker-executor.yaml. Images must already be present locally, nothing is pulled:
+//
+//	go test -tags integration -run TestLanguageRuntimes ./pkg/dockerexecutor/execution/
+
+const integrationConfigPath = "../../../config/docker-executor.yaml"
+
+func newIntegrationExecutor(t *testing.T, language types.Language) *codeExecutor {
+	t.Helper()
+
+	cfg, err := config.NewConfigProvider(integrationConfigPath)
+	require.NoError(t, err)
+
+	poolCfg, ok := cfg.GetLanguagePoolConfig(language)
+	require.True(t, ok, "no pool configured for %s", language)
+	if err := exec.Command("docker", "image", "inspect", poolCfg.LanguageConfig.ImageName).Run(); err != nil {
+		t.Skipf("image %s is not available locally", poolCfg.LanguageConfig.ImageName)
+	}
+
+	logger := logging.NewNoOpLogger()
+	httpClient, err := httppkg.NewHTTPClient(httppkg.DefaultHTTPRetryConfig(), logger)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	executor, err := NewCodeExecutor(ctx, cfg, httpClient, logger)
+	require.NoError(t, err)
+	require.NoError(t, executor.InitializeLanguagePools(ctx, []types.Language{language}))
+	t.Cleanup(func() {
+		_ = executor.Close(context.Background())
+	})
+
+	return executor
+}
+
+func runSource(t *testing.T, executor *codeExecutor, code string, language types.Language) *types.ExecutionResult {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	result, err := executor.ExecuteSource(ctx, code, string(language), "")
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	return result
+}
+
+func TestLanguageRuntimes_Rust_CompilesOncePerContentHash(t *testing.T) {
+	executor := newIntegrationExecutor(t, types.LanguageRust)
+	code := `fn main() { println!("{}", (1..=10).sum::<u32>()); }`
+
+	first := runSource(t, executor, code, types.LanguageRust)
+	require.True(t, first.Success, "first run failed: %v", first.Error)
+	assert.Contains(t, first.Output, "55")
+
+	// The binary was published to the build cache under the hash of its source
+	poolCfg, _ := executor.config.GetLanguagePoolConfig(types.LanguageRust)
+	sum := sha256.Sum256([]byte(code))
+	assert.FileExists(t, filepath.Join(poolCfg.LanguageConfig.BuildCacheDir, hex.EncodeToString(sum[:])))
+
+	// Same source again runs the cached binary
+	second := runSource(t, executor, code, types.LanguageRust)
+	require.True(t, second.Success, "cached run failed: %v", second.Error)
+	assert.Contains(t, second.Output, "55")
+}
+
+func TestLanguageRuntimes_PinnedPython_RunsWithEmptyLockfile(t *testing.T) {
+	executor := newIntegrationExecutor(t, types.LanguagePyPinned)
+	code := "# /// requirements\n# ///\nimport json\nprint(json.dumps({'ok': True}))\n"
+
+	result := runSource(t, executor, code, types.LanguagePyPinned)
+	require.True(t, result.Success, "run failed: %v", result.Error)
+	assert.Contains(t, result.Output, `{"ok": true}`)
+}
+
+func TestLanguageRuntimes_Deno_DeniesPermissionsOutsideAllowlist(t *testing.T) {
+	executor := newIntegrationExecutor(t, types.LanguageDeno)
+
+	allowed := runSource(t, executor, `console.log(typeof Deno.env.get("ALCHEMY_API_KEY"));`, types.LanguageDeno)
+	require.True(t, allowed.Success, "run failed: %v", allowed.Error)
+	assert.NotContains(t, allowed.Output, "Requires env access")
+
+	denied := runSource(t, executor, `console.log(Deno.env.get("HOME"));`, types.LanguageDeno)
+	assert.Contains(t, denied.Output, "Requires env access")
+}
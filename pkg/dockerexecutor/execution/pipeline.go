@@ -27,6 +27,7 @@ type ContainerManager interface {
 	InitializeLanguagePools(ctx context.Context, languages []types.Language) error
 	GetSupportedLanguages() []types.Language
 	IsLanguageSupported(language types.Language) bool
+	SchedulePrewarm(language types.Language, timestamps []time.Time) error
 	Close(ctx context.Context) error
 }
 
@@ -39,6 +40,7 @@ type FileManager interface {
 type executionPipeline struct {
 	fileManager        FileManager
 	containerMgr       ContainerManager
+	scheduler          *tenantScheduler
 	config             config.ConfigProviderInterface
 	logger             logging.Logger
 	mutex              sync.RWMutex
@@ -53,6 +55,7 @@ func newExecutionPipeline(cfg config.ConfigProviderInterface, fileMgr FileManage
 	return &executionPipeline{
 		fileManager:      fileMgr,
 		containerMgr:     containerMgr,
+		scheduler:        newTenantScheduler(cfg.GetQuotaConfig(), logger),
 		config:           cfg,
 		logger:           logger,
 		activeExecutions: make(map[string]*types.ExecutionContext),
@@ -174,6 +177,12 @@ func (ep *executionPipeline) executeSource(ctx context.Context, code string, lan
 		ext = ".js"
 	case types.LanguageTS:
 		ext = ".ts"
+	case types.LanguageRust:
+		ext = ".rs"
+	case types.LanguagePyPinned:
+		ext = ".pinned.py"
+	case types.LanguageDeno:
+		ext = ".deno.ts"
 	default:
 		ext = ".go"
 	}
@@ -287,6 +296,14 @@ func (ep *executionPipeline) executeStages(ctx context.Context, execCtx *types.E
 	language := types.GetLanguageFromFile(filePath)
 	ep.logger.Debugf("Detected language: %s for file: %s", language, filePath)
 
+	// Wait for the tenant's fair share of execution slots, rejecting it if over quota
+	release, err := ep.scheduler.acquire(ctx, execCtx.Metadata[types.MetadataKeyTenantID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire execution slot: %w", err)
+	}
+	var usage types.DockerResourceStats
+	defer func() { release(usage) }()
+
 	container, err := ep.containerMgr.GetContainer(ctx, language)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get container: %w", err)
@@ -312,6 +329,8 @@ func (ep *executionPipeline) executeStages(ctx context.Context, execCtx *types.E
 		return nil, fmt.Errorf("failed to execute code: %w", err)
 	}
 
+	usage = result.Stats
+
 	// Store exec ID and container ID for potential cancellation
 	execCtx.State.ExecID = execID
 	execCtx.State.ContainerID = container.ID
@@ -408,6 +427,7 @@ func (ep *executionPipeline) calculateFees(execCtx *types.ExecutionContext, alch
 		ep.logger.Warnf("Unknown task_definition_id: %d, using legacy fee calculation", taskDefinitionID)
 		return ep.calculateLegacyFees(execCtx, feesConfig), big.NewInt(0)
 	}
+	offChainFeeUSD = ep.applyGasProfile(offChainFeeUSD, execCtx.FileLanguage, feesConfig)
 
 	// Fetch ETH to USD conversion rate from CoinGecko API
 	resp, err := http.Get("https://api.coingecko.com/api/v3/simple/price?ids=ethereum&vs_currencies=usd")
@@ -574,6 +594,23 @@ func (ep *executionPipeline) calculateFees(execCtx *types.ExecutionContext, alch
 	return totalFeeWei, currentTotalFeeWei
 }
 
+// applyGasProfile adjusts the off-chain fee for runtimes with their own cost profile,
+// e.g. rust pays for compilation and pinned python for resolving its lockfile
+func (ep *executionPipeline) applyGasProfile(offChainFeeUSD float64, language string, feesConfig config.ExecutionFeeConfig) float64 {
+	profile, ok := feesConfig.GasProfiles[strings.ToLower(language)]
+	if !ok {
+		return offChainFeeUSD
+	}
+
+	multiplier := profile.OffChainFeeMultiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	adjusted := offChainFeeUSD*multiplier + profile.BuildFeeUSD
+	ep.logger.Debugf("Applied %s gas profile: offchain_fee_usd=%.6f -> %.6f", language, offChainFeeUSD, adjusted)
+	return adjusted
+}
+
 // calculateLegacyFees is the old fee calculation method for backward compatibility
 func (ep *executionPipeline) calculateLegacyFees(execCtx *types.ExecutionContext, feesConfig config.ExecutionFeeConfig) *big.Int {
 	var staticComplexity, dynamicComplexity float64
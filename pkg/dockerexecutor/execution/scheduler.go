@@ -0,0 +1,300 @@
+package execution
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/metrics"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// releaseFunc hands an execution slot back to the scheduler along with the resources it consumed
+type releaseFunc func(stats types.DockerResourceStats)
+
+// usageSample is the resource consumption of one finished execution
+type usageSample struct {
+	at              time.Time
+	cpuSeconds      float64
+	memoryMBSeconds float64
+}
+
+type tenantState struct {
+	quota      config.TenantQuotaConfig
+	running    int
+	queued     int
+	lastFinish float64 // Virtual finish tag of the tenant's latest request
+	usage      []usageSample
+}
+
+type pendingExecution struct {
+	tenantID  string
+	startTag  float64
+	finishTag float64
+	seq       uint64
+	ready     chan struct{}
+	granted   bool
+}
+
+// tenantScheduler admits executions using weighted fair queuing across tenants and
+// enforces per-tenant concurrency, cpu and memory budgets over a sliding window
+type tenantScheduler struct {
+	config      config.QuotaConfig
+	logger      logging.Logger
+	mutex       sync.Mutex
+	running     int
+	virtualTime float64
+	seq         uint64
+	tenants     map[string]*tenantState
+	waiting     []*pendingExecution
+	now         func() time.Time
+}
+
+func newTenantScheduler(cfg config.QuotaConfig, logger logging.Logger) *tenantScheduler {
+	// Tenant keys are addresses or API key IDs, match them case-insensitively
+	tenants := make(map[string]config.TenantQuotaConfig, len(cfg.Tenants))
+	for tenant, quota := range cfg.Tenants {
+		tenants[strings.ToLower(tenant)] = quota
+	}
+	cfg.Tenants = tenants
+
+	return &tenantScheduler{
+		config:  cfg,
+		logger:  logger,
+		tenants: make(map[string]*tenantState),
+		now:     time.Now,
+	}
+}
+
+// acquire blocks until the tenant is granted an execution slot, the context is done, or
+// the tenant is over quota, in which case a *types.QuotaExceededError is returned
+func (s *tenantScheduler) acquire(ctx context.Context, tenantID string) (releaseFunc, error) {
+	if !s.config.Enabled {
+		return func(types.DockerResourceStats) {}, nil
+	}
+
+	tenantID = normalizeTenantID(tenantID)
+
+	s.mutex.Lock()
+	state := s.tenantLocked(tenantID)
+	if err := s.checkBudgetsLocked(tenantID, state); err != nil {
+		s.cleanupTenantLocked(tenantID, state)
+		s.mutex.Unlock()
+		metrics.QuotaRejectionsTotal.WithLabelValues(string(err.Resource)).Inc()
+		return nil, err
+	}
+
+	startTag := s.virtualTime
+	if state.lastFinish > startTag {
+		startTag = state.lastFinish
+	}
+	s.seq++
+	pending := &pendingExecution{
+		tenantID:  tenantID,
+		startTag:  startTag,
+		finishTag: startTag + 1/float64(state.quota.Weight),
+		seq:       s.seq,
+		ready:     make(chan struct{}),
+	}
+	state.lastFinish = pending.finishTag
+	state.queued++
+	s.waiting = append(s.waiting, pending)
+	s.dispatchLocked()
+	s.mutex.Unlock()
+
+	select {
+	case <-pending.ready:
+		return s.releaser(tenantID), nil
+	case <-ctx.Done():
+		s.mutex.Lock()
+		if pending.granted {
+			// Granted while the context was being cancelled, hand the slot straight back
+			s.releaseLocked(tenantID, nil)
+		} else {
+			s.removeWaitingLocked(pending)
+			state.queued--
+			s.cleanupTenantLocked(tenantID, state)
+		}
+		s.updateGaugesLocked()
+		s.mutex.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (s *tenantScheduler) releaser(tenantID string) releaseFunc {
+	var once sync.Once
+	return func(stats types.DockerResourceStats) {
+		once.Do(func() {
+			seconds := stats.ExecutionTime.Seconds()
+			sample := usageSample{
+				at:              s.now(),
+				cpuSeconds:      stats.CPUPercentage / 100 * seconds,
+				memoryMBSeconds: float64(stats.MemoryUsage) / (1024 * 1024) * seconds,
+			}
+
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			s.releaseLocked(tenantID, &sample)
+		})
+	}
+}
+
+// releaseLocked frees a slot held by tenantID and dispatches waiting executions.
+// Must be called with s.mutex held.
+func (s *tenantScheduler) releaseLocked(tenantID string, sample *usageSample) {
+	state := s.tenantLocked(tenantID)
+	s.running--
+	state.running--
+	if sample != nil && (sample.cpuSeconds > 0 || sample.memoryMBSeconds > 0) {
+		state.usage = append(state.usage, *sample)
+	}
+	s.dispatchLocked()
+	s.cleanupTenantLocked(tenantID, state)
+}
+
+// dispatchLocked grants free slots to waiting executions in virtual finish order,
+// skipping tenants already at their concurrency limit. Must be called with s.mutex held.
+func (s *tenantScheduler) dispatchLocked() {
+	for s.running < s.config.MaxConcurrentExecutions {
+		next := -1
+		for i, pending := range s.waiting {
+			state := s.tenants[pending.tenantID]
+			if state.running >= state.quota.MaxConcurrent {
+				continue
+			}
+			if next == -1 || pending.finishTag < s.waiting[next].finishTag ||
+				(pending.finishTag == s.waiting[next].finishTag && pending.seq < s.waiting[next].seq) {
+				next = i
+			}
+		}
+		if next == -1 {
+			break
+		}
+
+		pending := s.waiting[next]
+		s.waiting = append(s.waiting[:next], s.waiting[next+1:]...)
+
+		state := s.tenants[pending.tenantID]
+		state.queued--
+		state.running++
+		s.running++
+		if pending.startTag > s.virtualTime {
+			s.virtualTime = pending.startTag
+		}
+		pending.granted = true
+		close(pending.ready)
+	}
+
+	// Reset virtual time once the system drains so tags do not grow unbounded
+	if s.running == 0 && len(s.waiting) == 0 {
+		s.virtualTime = 0
+		now := s.now()
+		for tenantID, state := range s.tenants {
+			state.lastFinish = 0
+			s.pruneUsageLocked(state, now)
+			s.cleanupTenantLocked(tenantID, state)
+		}
+	}
+	s.updateGaugesLocked()
+}
+
+// checkBudgetsLocked rejects the request if the tenant has spent its cpu or memory budget
+// for the current window or has no room left to queue. Must be called with s.mutex held.
+func (s *tenantScheduler) checkBudgetsLocked(tenantID string, state *tenantState) *types.QuotaExceededError {
+	now := s.now()
+	s.pruneUsageLocked(state, now)
+
+	var cpuSeconds, memoryMBSeconds float64
+	for _, sample := range state.usage {
+		cpuSeconds += sample.cpuSeconds
+		memoryMBSeconds += sample.memoryMBSeconds
+	}
+
+	retryAfter := time.Duration(0)
+	if len(state.usage) > 0 {
+		retryAfter = state.usage[0].at.Add(s.config.Window).Sub(now)
+	}
+
+	if limit := state.quota.CPUSecondsPerWindow; limit > 0 && cpuSeconds >= limit {
+		return &types.QuotaExceededError{
+			TenantID:   tenantID,
+			Resource:   types.QuotaResourceCPUSeconds,
+			Limit:      limit,
+			Used:       cpuSeconds,
+			RetryAfter: retryAfter,
+		}
+	}
+	if limit := state.quota.MemoryMBSecondsPerWindow; limit > 0 && memoryMBSeconds >= limit {
+		return &types.QuotaExceededError{
+			TenantID:   tenantID,
+			Resource:   types.QuotaResourceMemoryMBSeconds,
+			Limit:      limit,
+			Used:       memoryMBSeconds,
+			RetryAfter: retryAfter,
+		}
+	}
+	if state.running >= state.quota.MaxConcurrent && state.queued >= state.quota.MaxQueued {
+		return &types.QuotaExceededError{
+			TenantID:   tenantID,
+			Resource:   types.QuotaResourceConcurrency,
+			Limit:      float64(state.quota.MaxConcurrent + state.quota.MaxQueued),
+			Used:       float64(state.running + state.queued),
+			RetryAfter: time.Second,
+		}
+	}
+	return nil
+}
+
+func (s *tenantScheduler) pruneUsageLocked(state *tenantState, now time.Time) {
+	cutoff := now.Add(-s.config.Window)
+	keep := 0
+	for keep < len(state.usage) && state.usage[keep].at.Before(cutoff) {
+		keep++
+	}
+	state.usage = state.usage[keep:]
+}
+
+func (s *tenantScheduler) tenantLocked(tenantID string) *tenantState {
+	state, exists := s.tenants[tenantID]
+	if !exists {
+		quota, hasOverride := s.config.Tenants[tenantID]
+		if !hasOverride {
+			quota = s.config.DefaultTenant
+		}
+		state = &tenantState{quota: quota}
+		s.tenants[tenantID] = state
+	}
+	return state
+}
+
+// cleanupTenantLocked forgets tenants with nothing running, queued or inside the window
+func (s *tenantScheduler) cleanupTenantLocked(tenantID string, state *tenantState) {
+	if state.running == 0 && state.queued == 0 && len(state.usage) == 0 && state.lastFinish <= s.virtualTime {
+		delete(s.tenants, tenantID)
+	}
+}
+
+func (s *tenantScheduler) removeWaitingLocked(pending *pendingExecution) {
+	for i, waiting := range s.waiting {
+		if waiting == pending {
+			s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *tenantScheduler) updateGaugesLocked() {
+	metrics.FairQueueDepth.Set(float64(len(s.waiting)))
+	metrics.FairQueueRunning.Set(float64(s.running))
+}
+
+func normalizeTenantID(tenantID string) string {
+	tenantID = strings.ToLower(strings.TrimSpace(tenantID))
+	if tenantID == "" {
+		return types.DefaultTenantID
+	}
+	return tenantID
+}
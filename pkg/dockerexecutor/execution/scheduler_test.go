@@ -0,0 +1,210 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+func newTestQuotaConfig() config.QuotaConfig {
+	return config.QuotaConfig{
+		Enabled:                 true,
+		Window:                  time.Minute,
+		MaxConcurrentExecutions: 1,
+		DefaultTenant: config.TenantQuotaConfig{
+			Weight:        1,
+			MaxConcurrent: 1,
+			MaxQueued:     10,
+		},
+	}
+}
+
+func mustAcquire(t *testing.T, s *tenantScheduler, tenantID string) releaseFunc {
+	t.Helper()
+	release, err := s.acquire(context.Background(), tenantID)
+	require.NoError(t, err)
+	return release
+}
+
+// acquireAsync queues an acquire and reports the tenant on granted once it holds a slot
+func acquireAsync(s *tenantScheduler, tenantID string, granted chan<- string, releases chan<- releaseFunc) {
+	go func() {
+		release, err := s.acquire(context.Background(), tenantID)
+		if err != nil {
+			return
+		}
+		releases <- release
+		granted <- tenantID
+	}()
+}
+
+func waitForQueued(t *testing.T, s *tenantScheduler, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return len(s.waiting) == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestTenantScheduler_Disabled_AlwaysAdmits(t *testing.T) {
+	cfg := newTestQuotaConfig()
+	cfg.Enabled = false
+	s := newTenantScheduler(cfg, logging.NewNoOpLogger())
+
+	for i := 0; i < 5; i++ {
+		mustAcquire(t, s, "alice")
+	}
+	assert.Equal(t, 0, s.running)
+}
+
+func TestTenantScheduler_ConcurrencyLimit_RejectsWhenQueueFull(t *testing.T) {
+	cfg := newTestQuotaConfig()
+	cfg.DefaultTenant.MaxQueued = 0
+	s := newTenantScheduler(cfg, logging.NewNoOpLogger())
+
+	release := mustAcquire(t, s, "alice")
+
+	_, err := s.acquire(context.Background(), "alice")
+	quotaErr, ok := types.AsQuotaExceeded(err)
+	require.True(t, ok)
+	assert.Equal(t, types.QuotaResourceConcurrency, quotaErr.Resource)
+	assert.Equal(t, "alice", quotaErr.TenantID)
+
+	release(types.DockerResourceStats{})
+	mustAcquire(t, s, "alice")
+}
+
+func TestTenantScheduler_CPUBudget_RejectsOverQuota(t *testing.T) {
+	cfg := newTestQuotaConfig()
+	cfg.DefaultTenant.CPUSecondsPerWindow = 1
+	s := newTenantScheduler(cfg, logging.NewNoOpLogger())
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	release := mustAcquire(t, s, "alice")
+	release(types.DockerResourceStats{CPUPercentage: 100, ExecutionTime: 2 * time.Second})
+
+	_, err := s.acquire(context.Background(), "ALICE")
+	quotaErr, ok := types.AsQuotaExceeded(err)
+	require.True(t, ok, "tenant ids must be matched case-insensitively")
+	assert.Equal(t, types.QuotaResourceCPUSeconds, quotaErr.Resource)
+	assert.Equal(t, 2.0, quotaErr.Used)
+	assert.Equal(t, time.Minute, quotaErr.RetryAfter)
+
+	// Other tenants are unaffected
+	mustAcquire(t, s, "bob")
+}
+
+func TestTenantScheduler_MemoryBudget_ExpiresWithWindow(t *testing.T) {
+	cfg := newTestQuotaConfig()
+	cfg.DefaultTenant.MemoryMBSecondsPerWindow = 100
+	s := newTenantScheduler(cfg, logging.NewNoOpLogger())
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	release := mustAcquire(t, s, "alice")
+	release(types.DockerResourceStats{MemoryUsage: 256 * 1024 * 1024, ExecutionTime: time.Second})
+
+	_, err := s.acquire(context.Background(), "alice")
+	quotaErr, ok := types.AsQuotaExceeded(err)
+	require.True(t, ok)
+	assert.Equal(t, types.QuotaResourceMemoryMBSeconds, quotaErr.Resource)
+
+	now = now.Add(2 * time.Minute)
+	mustAcquire(t, s, "alice")
+}
+
+func TestTenantScheduler_WeightedFairQueuing(t *testing.T) {
+	cfg := newTestQuotaConfig()
+	cfg.DefaultTenant.MaxConcurrent = 10
+	cfg.Tenants = map[string]config.TenantQuotaConfig{
+		"Heavy": {Weight: 1, MaxConcurrent: 10, MaxQueued: 10},
+		"light": {Weight: 2, MaxConcurrent: 10, MaxQueued: 10},
+	}
+	s := newTenantScheduler(cfg, logging.NewNoOpLogger())
+
+	blocker := mustAcquire(t, s, "other")
+
+	granted := make(chan string, 10)
+	releases := make(chan releaseFunc, 10)
+	// The heavy tenant floods the queue before the light tenant arrives
+	for i := 0; i < 4; i++ {
+		acquireAsync(s, "heavy", granted, releases)
+		waitForQueued(t, s, i+1)
+	}
+	for i := 0; i < 2; i++ {
+		acquireAsync(s, "light", granted, releases)
+		waitForQueued(t, s, 5+i)
+	}
+
+	blocker(types.DockerResourceStats{})
+
+	var order []string
+	for i := 0; i < 6; i++ {
+		order = append(order, <-granted)
+		(<-releases)(types.DockerResourceStats{})
+	}
+
+	// Finish tags: heavy 1,2,3,4 and light 0.5,1 so light is served first despite arriving last
+	assert.Equal(t, []string{"light", "heavy", "light", "heavy", "heavy", "heavy"}, order)
+}
+
+func TestTenantScheduler_TenantConcurrencyDoesNotBlockOthers(t *testing.T) {
+	cfg := newTestQuotaConfig()
+	cfg.MaxConcurrentExecutions = 2
+	s := newTenantScheduler(cfg, logging.NewNoOpLogger())
+
+	mustAcquire(t, s, "alice")
+
+	granted := make(chan string, 2)
+	releases := make(chan releaseFunc, 2)
+	acquireAsync(s, "alice", granted, releases)
+	waitForQueued(t, s, 1)
+
+	// bob gets the free slot even though alice queued first, alice is at her limit
+	mustAcquire(t, s, "bob")
+
+	select {
+	case tenant := <-granted:
+		t.Fatalf("unexpected grant for %s", tenant)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestTenantScheduler_ContextCancelled_LeavesQueue(t *testing.T) {
+	s := newTenantScheduler(newTestQuotaConfig(), logging.NewNoOpLogger())
+	release := mustAcquire(t, s, "alice")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := s.acquire(ctx, "bob")
+		errCh <- err
+	}()
+	waitForQueued(t, s, 1)
+
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+
+	release(types.DockerResourceStats{})
+	assert.Equal(t, 0, s.running)
+	assert.Empty(t, s.waiting)
+	assert.Empty(t, s.tenants)
+}
+
+func TestTenantScheduler_EmptyTenantUsesDefault(t *testing.T) {
+	s := newTenantScheduler(newTestQuotaConfig(), logging.NewNoOpLogger())
+
+	mustAcquire(t, s, "  ")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	assert.Contains(t, s.tenants, types.DefaultTenantID)
+}
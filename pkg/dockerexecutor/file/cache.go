@@ -192,6 +192,12 @@ func getFileExtension(fileLanguage string) string {
 		return "py"
 	case "js":
 		return "js"
+	case "rust":
+		return "rs"
+	case "pypinned":
+		return "pinned.py"
+	case "deno":
+		return "deno.ts"
 	default:
 		return "go"
 	}
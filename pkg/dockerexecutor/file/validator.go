@@ -3,8 +3,11 @@ package file
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/analysis"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/types"
 	fs "github.com/trigg3rX/triggerx-backend/pkg/filesystem"
@@ -45,18 +48,193 @@ func (v *codeValidator) validateFile(filePath string) (*types.ValidationResult,
 	}
 
 	// Calculate and validate complexity
+	language := types.GetLanguageFromFile(filePath)
 	complexity := v.calculateComplexity(filePath)
 	result.Complexity = complexity
 
-	if complexity > v.config.MaxComplexity {
+	maxComplexity := v.config.MaxComplexity
+	if limit, ok := v.config.LanguageMaxComplexity[string(language)]; ok {
+		maxComplexity = limit
+	}
+	if complexity > maxComplexity {
 		result.IsValid = false
 		result.Errors = append(result.Errors,
-			fmt.Sprintf("file complexity %.2f exceeds limit %.2f", complexity, v.config.MaxComplexity))
+			fmt.Sprintf("file complexity %.2f exceeds limit %.2f", complexity, maxComplexity))
+	}
+
+	// Apply the rules of runtimes that restrict dependencies or capabilities
+	if err := v.validateLanguageRules(filePath, language, result); err != nil {
+		return nil, err
+	}
+
+	if v.config.EnableStaticAnalysis {
+		if err := v.validateStaticAnalysis(filePath, language, result); err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
 }
 
+// validateStaticAnalysis rejects scripts with analysis errors and reports warnings
+func (v *codeValidator) validateStaticAnalysis(filePath string, language types.Language, result *types.ValidationResult) error {
+	content, err := v.fs.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	for _, finding := range analysis.Analyze(string(content), language) {
+		if finding.Severity == analysis.SeverityError {
+			result.IsValid = false
+			result.Errors = append(result.Errors, finding.String())
+		} else {
+			result.Warnings = append(result.Warnings, finding.String())
+		}
+	}
+	return nil
+}
+
+func (v *codeValidator) validateLanguageRules(filePath string, language types.Language, result *types.ValidationResult) error {
+	switch language {
+	case types.LanguageRust, types.LanguagePyPinned, types.LanguageDeno:
+	default:
+		return nil
+	}
+
+	content, err := v.fs.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var errors []string
+	switch language {
+	case types.LanguageRust:
+		errors = v.validateRustSource(string(content))
+	case types.LanguagePyPinned:
+		errors = v.validatePinnedRequirements(string(content))
+	case types.LanguageDeno:
+		errors = v.validateDenoImports(string(content))
+	}
+
+	if len(errors) > 0 {
+		result.IsValid = false
+		result.Errors = append(result.Errors, errors...)
+	}
+	return nil
+}
+
+var (
+	rustUnsafeRegex      = regexp.MustCompile(`\bunsafe\b`)
+	rustExternCrateRegex = regexp.MustCompile(`(?m)^\s*extern\s+crate\s+([A-Za-z_][A-Za-z0-9_]*)`)
+	denoRemoteImport     = regexp.MustCompile(`(?:from\s+|import\s*\(?\s*)["'](https?://[^"']+)["']`)
+)
+
+// rustBuiltinCrates are always available to rustc without extra libraries
+var rustBuiltinCrates = []string{"std", "core", "alloc"}
+
+func (v *codeValidator) validateRustSource(content string) []string {
+	var errors []string
+
+	if v.config.Rust.ForbidUnsafe && rustUnsafeRegex.MatchString(content) {
+		errors = append(errors, "unsafe code is not allowed")
+	}
+	for _, match := range rustExternCrateRegex.FindAllStringSubmatch(content, -1) {
+		crate := match[1]
+		if !slices.Contains(rustBuiltinCrates, crate) && !slices.Contains(v.config.Rust.AllowedCrates, crate) {
+			errors = append(errors, fmt.Sprintf("crate not allowed: %s", crate))
+		}
+	}
+
+	return errors
+}
+
+// validatePinnedRequirements checks the inline requirements block, which must pin every
+// package to an exact version so the build resolves the same wheels every time
+func (v *codeValidator) validatePinnedRequirements(content string) []string {
+	var errors []string
+
+	requirements := parsePinnedRequirements(content)
+	if max := v.config.PinnedPython.MaxRequirements; max > 0 && len(requirements) > max {
+		errors = append(errors, fmt.Sprintf("%d requirements exceeds limit %d", len(requirements), max))
+	}
+
+	for _, requirement := range requirements {
+		spec, options, _ := strings.Cut(requirement, " ")
+		name, version, pinned := strings.Cut(spec, "==")
+		if !pinned || name == "" || version == "" || strings.ContainsAny(spec, "<>!~@;") {
+			errors = append(errors, fmt.Sprintf("requirement must be pinned with ==: %s", requirement))
+			continue
+		}
+		if v.config.PinnedPython.RequireHashes && !strings.Contains(options, "--hash=sha256:") {
+			errors = append(errors, fmt.Sprintf("requirement is missing a sha256 hash: %s", name))
+		}
+		for _, option := range strings.Fields(options) {
+			if !strings.HasPrefix(option, "--hash=") {
+				errors = append(errors, fmt.Sprintf("requirement option not allowed: %s", option))
+			}
+		}
+		if !v.isPackageAllowed(name) {
+			errors = append(errors, fmt.Sprintf("package not allowed: %s", name))
+		}
+	}
+
+	return errors
+}
+
+func (v *codeValidator) isPackageAllowed(name string) bool {
+	if len(v.config.PinnedPython.AllowedPackages) == 0 {
+		return true
+	}
+	for _, allowed := range v.config.PinnedPython.AllowedPackages {
+		if normalizePackageName(allowed) == normalizePackageName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *codeValidator) validateDenoImports(content string) []string {
+	var errors []string
+
+	for _, match := range denoRemoteImport.FindAllStringSubmatch(content, -1) {
+		host := match[1][strings.Index(match[1], "://")+3:]
+		if i := strings.IndexAny(host, "/?#"); i >= 0 {
+			host = host[:i]
+		}
+		if !slices.Contains(v.config.Deno.AllowedImportHosts, host) {
+			errors = append(errors, fmt.Sprintf("remote import host not allowed: %s", host))
+		}
+	}
+
+	return errors
+}
+
+// parsePinnedRequirements extracts the requirement lines between "# /// requirements" and "# ///",
+// the same block the pinned python setup script turns into requirements.txt
+func parsePinnedRequirements(content string) []string {
+	var requirements []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case !inBlock && line == "# /// requirements":
+			inBlock = true
+		case inBlock && line == "# ///":
+			return requirements
+		case inBlock:
+			requirement := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+			if requirement != "" && !strings.HasPrefix(requirement, "#") {
+				requirements = append(requirements, strings.Join(strings.Fields(requirement), " "))
+			}
+		}
+	}
+	return requirements
+}
+
+func normalizePackageName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+}
+
 // validateFileContent is intentionally removed.
 // Security is handled by container sandboxing and seccomp profiles, not naive pattern matching.
 // Pattern matching can be trivially bypassed and provides a false sense of security.
@@ -117,7 +295,29 @@ func (v *codeValidator) calculateComplexity(filePath string) float64 {
 		return 0.0
 	}
 
-	return v.calculateContentComplexity(content)
+	complexity := v.calculateContentComplexity(content)
+	if types.GetLanguageFromFile(filePath) == types.LanguageRust {
+		complexity += calculateRustComplexity(content)
+	}
+	return complexity
+}
+
+// calculateRustComplexity scores the rust constructs calculateContentComplexity doesn't know,
+// with the same weights, so they don't change the complexity of other languages
+func calculateRustComplexity(content []byte) float64 {
+	contentStr := string(content)
+
+	functionCount := float64(
+		strings.Count(contentStr, "fn ") +
+			strings.Count(contentStr, "impl "))
+	importCount := float64(
+		strings.Count(contentStr, "use ") +
+			strings.Count(contentStr, "extern crate "))
+	controlFlowCount := float64(
+		strings.Count(contentStr, "match ") +
+			strings.Count(contentStr, "loop {"))
+
+	return functionCount*0.8 + importCount*0.3 + controlFlowCount*0.4
 }
 
 func (v *codeValidator) calculateContentComplexity(content []byte) float64 {
@@ -450,6 +450,186 @@ func main() {
 	assert.Greater(t, simpleComplexity, 0.0)
 }
 
+func newLanguageRulesValidator() *codeValidator {
+	cfg := config.ValidationConfig{
+		MaxFileSize:       4096,
+		AllowedExtensions: []string{".rs", ".py", ".ts"},
+		MaxComplexity:     100,
+		TimeoutSeconds:    30,
+		Rust: config.RustValidationConfig{
+			ForbidUnsafe:  true,
+			AllowedCrates: []string{"serde_json"},
+		},
+		PinnedPython: config.PinnedPythonValidationConfig{
+			RequireHashes:   true,
+			AllowedPackages: []string{"requests", "eth_abi"},
+			MaxRequirements: 2,
+		},
+		Deno: config.DenoValidationConfig{
+			AllowedImportHosts: []string{"jsr.io"},
+		},
+	}
+	return newCodeValidator(cfg, logging.NewNoOpLogger(), &fs.OSFileSystem{})
+}
+
+func writeTempSource(t *testing.T, name string, content string) string {
+	t.Helper()
+	filePath := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+	return filePath
+}
+
+func TestCodeValidator_ValidateFile_RustRules(t *testing.T) {
+	validator := newLanguageRulesValidator()
+
+	testCases := []struct {
+		name           string
+		content        string
+		expectedErrors []string
+	}{
+		{
+			name: "safe code with allowed crate",
+			content: `extern crate serde_json;
+use std::collections::HashMap;
+fn main() { let m: HashMap<u8, u8> = HashMap::new(); println!("{}", m.len()); }`,
+		},
+		{
+			name:           "unsafe block",
+			content:        "fn main() { let x = 1; unsafe { println!(\"{}\", x); } }",
+			expectedErrors: []string{"unsafe code is not allowed"},
+		},
+		{
+			name:           "crate not in allowlist",
+			content:        "extern crate libc;\nfn main() {}",
+			expectedErrors: []string{"crate not allowed: libc"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := validator.validateFile(writeTempSource(t, "code.rs", tc.content))
+
+			require.NoError(t, err)
+			assert.Equal(t, len(tc.expectedErrors) == 0, result.IsValid)
+			for _, expected := range tc.expectedErrors {
+				assert.Contains(t, result.Errors, expected)
+			}
+		})
+	}
+}
+
+func TestCodeValidator_ValidateFile_PinnedPythonRules(t *testing.T) {
+	validator := newLanguageRulesValidator()
+	hash := "--hash=sha256:" + strings.Repeat("a", 64)
+
+	testCases := []struct {
+		name           string
+		requirements   []string
+		expectedErrors []string
+	}{
+		{
+			name:         "pinned and hashed",
+			requirements: []string{"requests==2.31.0 " + hash, "Eth-ABI==5.0.1 " + hash},
+		},
+		{
+			name:           "version range",
+			requirements:   []string{"requests>=2.0 " + hash},
+			expectedErrors: []string{"requirement must be pinned with ==: requests>=2.0 " + hash},
+		},
+		{
+			name:           "missing hash",
+			requirements:   []string{"requests==2.31.0"},
+			expectedErrors: []string{"requirement is missing a sha256 hash: requests"},
+		},
+		{
+			name:           "index override",
+			requirements:   []string{"requests==2.31.0 " + hash + " --index-url=https://evil.example"},
+			expectedErrors: []string{"requirement option not allowed: --index-url=https://evil.example"},
+		},
+		{
+			name:           "package not allowed",
+			requirements:   []string{"numpy==1.26.4 " + hash},
+			expectedErrors: []string{"package not allowed: numpy"},
+		},
+		{
+			name:           "too many requirements",
+			requirements:   []string{"requests==2.31.0 " + hash, "eth-abi==5.0.1 " + hash, "requests==2.31.0 " + hash},
+			expectedErrors: []string{"3 requirements exceeds limit 2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content := "import requests\n# /// requirements\n# " + strings.Join(tc.requirements, "\n# ") + "\n# ///\nprint('ok')\n"
+
+			result, err := validator.validateFile(writeTempSource(t, "code.pinned.py", content))
+
+			require.NoError(t, err)
+			assert.Equal(t, len(tc.expectedErrors) == 0, result.IsValid, result.Errors)
+			for _, expected := range tc.expectedErrors {
+				assert.Contains(t, result.Errors, expected)
+			}
+		})
+	}
+}
+
+func TestCodeValidator_ValidateFile_DenoImportHosts(t *testing.T) {
+	validator := newLanguageRulesValidator()
+	content := `import { assert } from "https://jsr.io/@std/assert/1.0.0/mod.ts";
+import { evil } from 'https://evil.example/mod.ts';
+const lazy = await import("http://cdn.example:8080/x.js");
+console.log(assert, evil, lazy);`
+
+	result, err := validator.validateFile(writeTempSource(t, "code.deno.ts", content))
+
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, []string{
+		"remote import host not allowed: evil.example",
+		"remote import host not allowed: cdn.example:8080",
+	}, result.Errors)
+}
+
+func TestCodeValidator_ValidateFile_LanguageMaxComplexity(t *testing.T) {
+	validator := newLanguageRulesValidator()
+	validator.config.MaxComplexity = 0.1
+	validator.config.LanguageMaxComplexity = map[string]float64{"rust": 100}
+
+	rustResult, err := validator.validateFile(writeTempSource(t, "code.rs", "fn main() { println!(\"hi\"); }"))
+	require.NoError(t, err)
+	assert.True(t, rustResult.IsValid, rustResult.Errors)
+
+	tsResult, err := validator.validateFile(writeTempSource(t, "code.ts", "function main() { console.log('hi'); }"))
+	require.NoError(t, err)
+	assert.False(t, tsResult.IsValid)
+}
+
+func TestParsePinnedRequirements(t *testing.T) {
+	content := "# /// requirements\r\n#   requests==2.31.0   --hash=sha256:abc\r\n# a comment\n#\n# # another comment\n# ///\n# idna==3.7\n"
+
+	assert.Equal(t, []string{"requests==2.31.0 --hash=sha256:abc", "a comment"}, parsePinnedRequirements(content))
+	assert.Empty(t, parsePinnedRequirements("print('no block')"))
+}
+
+func TestCodeValidator_ValidateFile_StaticAnalysis(t *testing.T) {
+	validator := newLanguageRulesValidator()
+	validator.config.AllowedExtensions = append(validator.config.AllowedExtensions, ".js")
+	content := "const { execSync } = require('child_process');\nwhile (true) { console.log(execSync('ls')); }\n"
+	filePath := writeTempSource(t, "code.js", content)
+
+	// Analysis is opt-in
+	result, err := validator.validateFile(filePath)
+	require.NoError(t, err)
+	assert.True(t, result.IsValid, result.Errors)
+
+	validator.config.EnableStaticAnalysis = true
+	result, err = validator.validateFile(filePath)
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, []string{`line 1: import of "child_process" is not allowed (process-exec)`}, result.Errors)
+	assert.Contains(t, result.Warnings, "line 2: loop has no condition and no break, return, throw or exit (infinite-loop)")
+}
+
 // Benchmark tests
 func BenchmarkCodeValidator_ValidateFile_SimpleFile(b *testing.B) {
 	// Arrange
@@ -4,6 +4,7 @@ package dockerexecutor
 
 import (
 	"context"
+	"time"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
 	"github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/execution"
@@ -22,6 +23,7 @@ type DockerExecutorAPI interface {
 	GetLanguageStats(language types.Language) (*types.PoolStats, bool)
 	GetSupportedLanguages() []types.Language
 	IsLanguageSupported(language types.Language) bool
+	SchedulePrewarm(language types.Language, timestamps []time.Time) error
 	GetActiveExecutions() []*types.ExecutionContext
 	CancelExecution(executionID string) error
 	GetAlerts(severity string, limit int) []execution.Alert
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Current number of containers per language pool, state: ready, busy, error
+	PoolContainers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "triggerx",
+		Subsystem: "dockerexecutor",
+		Name:      "pool_containers",
+		Help:      "Current number of containers in a language pool by state",
+	}, []string{"language", "state"})
+
+	// Container count the autoscaler is converging the pool to
+	PoolTargetContainers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "triggerx",
+		Subsystem: "dockerexecutor",
+		Name:      "pool_target_containers",
+		Help:      "Target number of containers chosen by the autoscaler",
+	}, []string{"language"})
+
+	// Requests currently waiting for a container
+	PoolQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "triggerx",
+		Subsystem: "dockerexecutor",
+		Name:      "pool_queue_depth",
+		Help:      "Number of requests waiting for a container",
+	}, []string{"language"})
+
+	// Time spent waiting for a container
+	PoolWaitDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "triggerx",
+		Subsystem: "dockerexecutor",
+		Name:      "pool_wait_duration_seconds",
+		Help:      "Time spent waiting to acquire a container from a language pool",
+		Buckets:   []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"language"})
+
+	// Scaling decisions taken, action: scale_up, scale_down, prewarm, floor
+	PoolScalingDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triggerx",
+		Subsystem: "dockerexecutor",
+		Name:      "pool_scaling_decisions_total",
+		Help:      "Total autoscaler decisions by language and action",
+	}, []string{"language", "action"})
+
+	// Idle containers removed after exceeding the idle TTL
+	PoolIdleReapedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triggerx",
+		Subsystem: "dockerexecutor",
+		Name:      "pool_idle_reaped_total",
+		Help:      "Total idle containers reaped from a language pool",
+	}, []string{"language"})
+
+	// Executions waiting in the fair-share queue for an execution slot
+	FairQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "triggerx",
+		Subsystem: "dockerexecutor",
+		Name:      "fair_queue_depth",
+		Help:      "Number of executions waiting in the fair-share queue",
+	})
+
+	// Executions holding a slot granted by the fair-share scheduler
+	FairQueueRunning = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "triggerx",
+		Subsystem: "dockerexecutor",
+		Name:      "fair_queue_running",
+		Help:      "Number of executions holding a fair-share execution slot",
+	})
+
+	// Executions rejected for exceeding a tenant quota, resource: concurrency, cpu_seconds, memory_mb_seconds
+	QuotaRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triggerx",
+		Subsystem: "dockerexecutor",
+		Name:      "quota_rejections_total",
+		Help:      "Total executions rejected for exceeding a tenant quota",
+	}, []string{"resource"})
+)
@@ -7,6 +7,7 @@ package dockerexecutor
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	config "github.com/trigg3rX/triggerx-backend/pkg/dockerexecutor/config"
@@ -258,6 +259,20 @@ func (mr *MockDockerExecutorAPIMockRecorder) Initialize(ctx interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Initialize", reflect.TypeOf((*MockDockerExecutorAPI)(nil).Initialize), ctx)
 }
 
+// SchedulePrewarm mocks base method.
+func (m *MockDockerExecutorAPI) SchedulePrewarm(language types.Language, timestamps []time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePrewarm", language, timestamps)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SchedulePrewarm indicates an expected call of SchedulePrewarm.
+func (mr *MockDockerExecutorAPIMockRecorder) SchedulePrewarm(language, timestamps interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePrewarm", reflect.TypeOf((*MockDockerExecutorAPI)(nil).SchedulePrewarm), language, timestamps)
+}
+
 // IsLanguageSupported mocks base method.
 func (m *MockDockerExecutorAPI) IsLanguageSupported(language types.Language) bool {
 	m.ctrl.T.Helper()
@@ -13,6 +13,12 @@ func GetInitializationScript(language types.Language) string {
 		return javascriptInitializationScript
 	case types.LanguageTS:
 		return typescriptInitializationScript
+	case types.LanguageRust:
+		return rustInitializationScript
+	case types.LanguagePyPinned:
+		return pinnedPythonInitializationScript
+	case types.LanguageDeno:
+		return denoInitializationScript
 	default:
 		return ""
 	}
@@ -29,6 +35,12 @@ func GetSetupScript(language types.Language) string {
 		return javascriptSetupScript
 	case types.LanguageTS:
 		return typescriptSetupScript
+	case types.LanguageRust:
+		return rustSetupScript
+	case types.LanguagePyPinned:
+		return pinnedPythonSetupScript
+	case types.LanguageDeno:
+		return denoSetupScript
 	default:
 		return ""
 	}
@@ -45,6 +57,23 @@ func GetExecutionScript(language types.Language) string {
 		return javascriptExecutionScript
 	case types.LanguageTS:
 		return typescriptExecutionScript
+	case types.LanguageRust:
+		return rustExecutionScript
+	case types.LanguagePyPinned:
+		return pinnedPythonExecutionScript
+	case types.LanguageDeno:
+		return denoExecutionScript
+	default:
+		return ""
+	}
+}
+
+// GetBuildScript returns the script a one-shot build container runs to compile code.rs in
+// /build into /build/code, or "" for languages that are not built ahead of execution.
+func GetBuildScript(language types.Language) string {
+	switch language {
+	case types.LanguageRust:
+		return rustBuildScript
 	default:
 		return ""
 	}
@@ -62,6 +91,12 @@ func GetCleanupScript(language types.Language) string {
 		return javascriptCleanupScript
 	case types.LanguageTS:
 		return typescriptCleanupScript
+	case types.LanguageRust:
+		return rustCleanupScript
+	case types.LanguagePyPinned:
+		return pinnedPythonCleanupScript
+	case types.LanguageDeno:
+		return denoCleanupScript
 	default:
 		return ""
 	}
@@ -107,6 +142,30 @@ echo 'console.log("init");' > code.ts
 echo "TypeScript container initialized successfully"
 `
 
+const rustInitializationScript = `#!/bin/sh
+set -e
+mkdir -p /code
+cd /code
+echo 'fn main() { println!("init"); }' > code.rs
+echo "Rust container initialized successfully"
+`
+
+const pinnedPythonInitializationScript = `#!/bin/sh
+set -e
+mkdir -p /code
+cd /code
+echo 'print("init")' > code.py
+echo "Pinned Python container initialized successfully"
+`
+
+const denoInitializationScript = `#!/bin/sh
+set -e
+mkdir -p /code
+cd /code
+echo 'console.log("init");' > code.ts
+echo "Deno container initialized successfully"
+`
+
 // --- SETUP SCRIPTS (Warming up, dependency installation) ---
 
 const goSetupScript = `#!/bin/sh
@@ -191,6 +250,50 @@ if [ -f package.json ]; then
 fi
 `
 
+const rustSetupScript = `#!/bin/sh
+set -e
+cd /code
+# One-time warm-up of the compiler and standard library.
+if [ ! -f /code/.warm ]; then
+    echo 'fn main(){}' > warm.rs
+    rustc -O -o /tmp/warm warm.rs
+    rm warm.rs /tmp/warm
+    touch /code/.warm
+fi
+`
+
+const pinnedPythonSetupScript = `#!/bin/sh
+set -e
+cd /code
+# One-time warm-up of Python bytecode cache.
+if [ ! -f /code/.warm ]; then
+    echo 'import json, os, sys, time, datetime' > warm.py
+    python -m py_compile warm.py
+    rm -rf warm.py __pycache__
+    touch /code/.warm
+fi
+# Extract the inline lockfile between "# /// requirements" and "# ///" into requirements.txt.
+sed -n '/^# \/\/\/ requirements$/,/^# \/\/\/$/p' code.py | sed '1d;$d' | sed 's/^# \{0,1\}//' > requirements.txt
+# Resolve only from the local wheel mirror, never from the public index.
+if [ -s requirements.txt ]; then
+    pip install --no-index --find-links "${WHEEL_MIRROR:-/wheels}" --target /code/.pydeps -r requirements.txt
+fi
+`
+
+const denoSetupScript = `#!/bin/sh
+set -e
+cd /code
+# One-time warm-up of the Deno runtime.
+if [ ! -f /code/.warm ]; then
+    echo 'const a: string = "warm";' > warm.ts
+    deno check warm.ts
+    rm warm.ts
+    touch /code/.warm
+fi
+# Prefetch remote modules, failures surface when the code runs.
+deno cache --quiet code.ts || echo "Warning: some modules could not be cached"
+`
+
 // --- EXECUTION SCRIPTS (Running the code) ---
 
 const goExecutionScript = `#!/bin/sh
@@ -254,6 +357,48 @@ NODE_OPTIONS="--no-warnings --max-old-space-size=${V8_MEMORY_LIMIT}" node code.j
 echo "done" > execution_complete.flag
 `
 
+const rustExecutionScript = `#!/bin/sh
+set -e
+cd /code
+# With a build cache the executor copies in the binary compiled by a build container,
+# otherwise the source is compiled here for this execution only.
+{
+    if [ ! -x ./code ]; then
+        rustc --edition 2021 -O -L "${RUST_CRATE_DIR:-/opt/rust-crates}" -o ./code code.rs
+    fi
+    ./code
+} > result.json 2>&1
+# Create a completion marker file
+echo "done" > execution_complete.flag
+`
+
+const pinnedPythonExecutionScript = `#!/bin/sh
+set -e
+cd /code
+# Execute with the pinned dependencies ahead of anything in the image
+PYTHONPATH=/code/.pydeps python -u -B code.py > result.json 2>&1
+# Create a completion marker file
+echo "done" > execution_complete.flag
+`
+
+const denoExecutionScript = `#!/bin/sh
+set -e
+cd /code
+V8_MEMORY_LIMIT=${V8_MEMORY_LIMIT:-256}
+# Only the permissions in DENO_PERMISSIONS are granted, anything else is denied without prompting.
+deno run --no-prompt ${DENO_PERMISSIONS} --v8-flags=--max-old-space-size=${V8_MEMORY_LIMIT} code.ts > result.json 2>&1
+# Create a completion marker file
+echo "done" > execution_complete.flag
+`
+
+// --- BUILD SCRIPTS (Compiling in one-shot build containers) ---
+
+const rustBuildScript = `#!/bin/sh
+set -e
+cd /build
+rustc --edition 2021 -O -L "${RUST_CRATE_DIR:-/opt/rust-crates}" -o code code.rs > build.log 2>&1
+`
+
 // --- CLEANUP SCRIPTS (Resetting the container state) ---
 
 const goCleanupScript = `#!/bin/sh
@@ -293,4 +438,28 @@ rm -f package.json
 rm -f package-lock.json
 rm -f tsconfig.json
 rm -rf node_modules dist
-`
\ No newline at end of file
+`
+
+const rustCleanupScript = `#!/bin/sh
+cd /code
+rm -f code.rs
+rm -f code
+rm -f result.json
+rm -f execution_complete.flag
+`
+
+const pinnedPythonCleanupScript = `#!/bin/sh
+cd /code
+rm -f code.py
+rm -f result.json
+rm -f execution_complete.flag
+rm -f requirements.txt
+rm -rf .pydeps __pycache__
+`
+
+const denoCleanupScript = `#!/bin/sh
+cd /code
+rm -f code.ts
+rm -f result.json
+rm -f execution_complete.flag
+`
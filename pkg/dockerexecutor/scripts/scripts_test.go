@@ -46,6 +46,24 @@ func TestGetInitializationScript(t *testing.T) {
 			expectedSubstring: "npm install -g typescript",
 			notExpected:       "code.js",
 		},
+		{
+			name:              "Rust Language",
+			language:          types.LanguageRust,
+			expectedSubstring: "println!(\"init\")",
+			notExpected:       "code.go",
+		},
+		{
+			name:              "Pinned Python Language",
+			language:          types.LanguagePyPinned,
+			expectedSubstring: "print(\"init\")",
+			notExpected:       "code.go",
+		},
+		{
+			name:              "Deno Language",
+			language:          types.LanguageDeno,
+			expectedSubstring: "console.log(\"init\");",
+			notExpected:       "npm install",
+		},
 		{
 			name:              "Unknown Language",
 			language:          "unknown",
@@ -109,6 +127,21 @@ func TestGetSetupScript(t *testing.T) {
 			language:          types.LanguageTS,
 			expectedSubstring: "npm install",
 		},
+		{
+			name:              "Rust Language",
+			language:          types.LanguageRust,
+			expectedSubstring: "rustc -O",
+		},
+		{
+			name:              "Pinned Python Language",
+			language:          types.LanguagePyPinned,
+			expectedSubstring: "pip install --no-index --find-links \"${WHEEL_MIRROR:-/wheels}\"",
+		},
+		{
+			name:              "Deno Language",
+			language:          types.LanguageDeno,
+			expectedSubstring: "deno cache",
+		},
 		{
 			name:              "Unknown Language",
 			language:          "unknown",
@@ -170,6 +203,21 @@ func TestGetExecutionScript(t *testing.T) {
 			language:          types.LanguageTS,
 			expectedSubstring: "tsc code.ts",
 		},
+		{
+			name:              "Rust Language",
+			language:          types.LanguageRust,
+			expectedSubstring: "./code\n} > result.json",
+		},
+		{
+			name:              "Pinned Python Language",
+			language:          types.LanguagePyPinned,
+			expectedSubstring: "PYTHONPATH=/code/.pydeps python -u -B code.py",
+		},
+		{
+			name:              "Deno Language",
+			language:          types.LanguageDeno,
+			expectedSubstring: "deno run --no-prompt ${DENO_PERMISSIONS}",
+		},
 		{
 			name:              "Unknown Language",
 			language:          "unknown",
@@ -240,6 +288,24 @@ func TestGetCleanupScript(t *testing.T) {
 			expectedSubstring: "rm -f code.ts",
 			notExpected:       "rm -f code.py",
 		},
+		{
+			name:              "Rust Language",
+			language:          types.LanguageRust,
+			expectedSubstring: "rm -f code.rs",
+			notExpected:       "RUST_BUILD_CACHE",
+		},
+		{
+			name:              "Pinned Python Language",
+			language:          types.LanguagePyPinned,
+			expectedSubstring: "rm -rf .pydeps",
+			notExpected:       "rm -f code.go",
+		},
+		{
+			name:              "Deno Language",
+			language:          types.LanguageDeno,
+			expectedSubstring: "rm -f code.ts",
+			notExpected:       "node_modules",
+		},
 		{
 			name:              "Unknown Language",
 			language:          "unknown",
@@ -344,7 +410,10 @@ func TestScriptConsistency(t *testing.T) {
 
 // TestScriptSafety tests that scripts contain proper error handling and safety measures.
 func TestScriptSafety(t *testing.T) {
-	languages := []types.Language{types.LanguageGo, types.LanguagePy, types.LanguageJS, types.LanguageNode, types.LanguageTS}
+	languages := []types.Language{
+		types.LanguageGo, types.LanguagePy, types.LanguageJS, types.LanguageNode, types.LanguageTS,
+		types.LanguageRust, types.LanguagePyPinned, types.LanguageDeno,
+	}
 
 	for _, lang := range languages {
 		t.Run(string(lang), func(t *testing.T) {
@@ -390,7 +459,10 @@ func TestMemoryLimits(t *testing.T) {
 
 // TestWarmupMechanism tests that warmup mechanisms are properly implemented in setup scripts.
 func TestWarmupMechanism(t *testing.T) {
-	languages := []types.Language{types.LanguageGo, types.LanguagePy, types.LanguageJS, types.LanguageNode, types.LanguageTS}
+	languages := []types.Language{
+		types.LanguageGo, types.LanguagePy, types.LanguageJS, types.LanguageNode, types.LanguageTS,
+		types.LanguageRust, types.LanguagePyPinned, types.LanguageDeno,
+	}
 
 	for _, lang := range languages {
 		t.Run(string(lang), func(t *testing.T) {
@@ -405,7 +477,10 @@ func TestWarmupMechanism(t *testing.T) {
 
 // TestResultFileHandling tests that all execution scripts handle result files properly.
 func TestResultFileHandling(t *testing.T) {
-	languages := []types.Language{types.LanguageGo, types.LanguagePy, types.LanguageJS, types.LanguageNode, types.LanguageTS}
+	languages := []types.Language{
+		types.LanguageGo, types.LanguagePy, types.LanguageJS, types.LanguageNode, types.LanguageTS,
+		types.LanguageRust, types.LanguagePyPinned, types.LanguageDeno,
+	}
 
 	for _, lang := range languages {
 		t.Run(string(lang), func(t *testing.T) {
@@ -421,3 +496,30 @@ func TestResultFileHandling(t *testing.T) {
 		})
 	}
 }
+
+// TestRustBuild tests that pool containers only run a provided binary or compile for themselves,
+// while the shared build cache is written by build containers alone.
+func TestRustBuild(t *testing.T) {
+	script := GetExecutionScript(types.LanguageRust)
+	assert.Contains(t, script, "if [ ! -x ./code ]; then")
+	assert.NotContains(t, script, "RUST_BUILD_CACHE")
+	assert.NotContains(t, GetInitializationScript(types.LanguageRust), "RUST_BUILD_CACHE")
+
+	// A binary must not outlive the execution it was copied in for
+	assert.Contains(t, GetCleanupScript(types.LanguageRust), "rm -f code\n")
+
+	buildScript := GetBuildScript(types.LanguageRust)
+	assert.Contains(t, buildScript, "cd /build")
+	assert.Contains(t, buildScript, "-o code code.rs > build.log 2>&1")
+	assert.Empty(t, GetBuildScript(types.LanguageGo))
+}
+
+// TestPinnedPythonDependencies tests that pinned dependencies come only from the local wheel mirror.
+func TestPinnedPythonDependencies(t *testing.T) {
+	setupScript := GetSetupScript(types.LanguagePyPinned)
+
+	assert.Contains(t, setupScript, "--no-index")
+	assert.Contains(t, setupScript, "--target /code/.pydeps")
+	assert.Contains(t, setupScript, "# \\/\\/\\/ requirements")
+	assert.NotContains(t, setupScript, "pypi.org")
+}
@@ -14,6 +14,10 @@ const (
 	LanguageJS   Language = "js"
 	LanguageTS   Language = "ts"
 	LanguageNode Language = "node"
+	LanguageRust Language = "rust"
+	// LanguagePyPinned is python whose dependencies are pinned in an inline requirements block
+	LanguagePyPinned Language = "pypinned"
+	LanguageDeno     Language = "deno"
 )
 
 type ContainerStatus string
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MetadataKeyTenantID is the execution metadata key holding the tenant (wallet address or API key ID)
+// an execution is scheduled and billed against
+const MetadataKeyTenantID = "tenant_id"
+
+// DefaultTenantID is used for executions submitted without a tenant
+const DefaultTenantID = "default"
+
+type QuotaResource string
+
+const (
+	QuotaResourceConcurrency     QuotaResource = "concurrency"
+	QuotaResourceCPUSeconds      QuotaResource = "cpu_seconds"
+	QuotaResourceMemoryMBSeconds QuotaResource = "memory_mb_seconds"
+)
+
+// QuotaExceededError is returned when a tenant has exhausted one of its execution budgets
+type QuotaExceededError struct {
+	TenantID   string        `json:"tenant_id"`
+	Resource   QuotaResource `json:"resource"`
+	Limit      float64       `json:"limit"`
+	Used       float64       `json:"used"`
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("tenant %s exceeded %s quota (used %.2f of %.2f), retry after %s",
+		e.TenantID, e.Resource, e.Used, e.Limit, e.RetryAfter.Round(time.Second))
+}
+
+// AsQuotaExceeded unwraps err into a QuotaExceededError if it carries one
+func AsQuotaExceeded(err error) (*QuotaExceededError, bool) {
+	var quotaErr *QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		return quotaErr, true
+	}
+	return nil, false
+}
@@ -26,6 +26,40 @@ type PoolStats struct {
 	CreatedCount      int64         `json:"created_count"`
 	DestroyedCount    int64         `json:"destroyed_count"`
 	LastCleanup       time.Time     `json:"last_cleanup"`
+
+	// Autoscaling state
+	MinContainers     int              `json:"min_containers"`
+	MaxContainers     int              `json:"max_containers"`
+	TargetContainers  int              `json:"target_containers"`
+	QueueDepth        int              `json:"queue_depth"`
+	WaitTimeP50       time.Duration    `json:"wait_time_p50"`
+	WaitTimeP95       time.Duration    `json:"wait_time_p95"`
+	WaitTimeP99       time.Duration    `json:"wait_time_p99"`
+	ScaleUpCount      int64            `json:"scale_up_count"`
+	ScaleDownCount    int64            `json:"scale_down_count"`
+	NextPrewarmAt     time.Time        `json:"next_prewarm_at,omitempty"`
+	LastScaleDecision *ScalingDecision `json:"last_scale_decision,omitempty"`
+}
+
+type ScalingAction string
+
+const (
+	ScalingActionNone      ScalingAction = "none"
+	ScalingActionScaleUp   ScalingAction = "scale_up"
+	ScalingActionScaleDown ScalingAction = "scale_down"
+	ScalingActionPrewarm   ScalingAction = "prewarm"
+	ScalingActionFloor     ScalingAction = "floor" // restoring min_containers
+)
+
+// ScalingDecision records a single autoscaler evaluation of a language pool
+type ScalingDecision struct {
+	Action     ScalingAction `json:"action"`
+	From       int           `json:"from"`
+	To         int           `json:"to"`
+	Reason     string        `json:"reason"`
+	QueueDepth int           `json:"queue_depth"`
+	WaitP95    time.Duration `json:"wait_p95"`
+	Timestamp  time.Time     `json:"timestamp"`
 }
 
 type PerformanceMetrics struct {
@@ -18,6 +18,10 @@ func TestGetLanguageFromFile(t *testing.T) {
 		{name: "js file", filePath: "test.js", expected: LanguageJS},
 		{name: "ts file", filePath: "test.ts", expected: LanguageTS},
 		{name: "node file", filePath: "test.mjs", expected: LanguageNode},
+		{name: "rust file", filePath: "test.rs", expected: LanguageRust},
+		{name: "pinned python file", filePath: "/cache/test.pinned.py", expected: LanguagePyPinned},
+		{name: "deno ts file", filePath: "test.deno.ts", expected: LanguageDeno},
+		{name: "deno js file", filePath: "test.DENO.js", expected: LanguageDeno},
 	}
 
 	for _, test := range tests {
@@ -39,6 +43,9 @@ func TestGetLanguageFromExtension(t *testing.T) {
 		{name: "js extension", extension: "js", expected: LanguageJS},
 		{name: "ts extension", extension: "ts", expected: LanguageTS},
 		{name: "node extension", extension: "mjs", expected: LanguageNode},
+		{name: "rust extension", extension: "rs", expected: LanguageRust},
+		{name: "pinned python extension", extension: ".pinned.py", expected: LanguagePyPinned},
+		{name: "deno extension", extension: "deno.ts", expected: LanguageDeno},
 		{name: "unknown extension", extension: ".unknown", expected: LanguageGo},
 	}
 
@@ -34,6 +34,15 @@ func GetLanguageFromExtension(extension string) Language {
 
 // GetLanguageFromFile returns the language based on file extension
 func GetLanguageFromFile(filePath string) Language {
+	// Runtimes sharing an extension with another language use a compound suffix
+	lowerPath := strings.ToLower(filePath)
+	switch {
+	case strings.HasSuffix(lowerPath, ".pinned.py"):
+		return LanguagePyPinned
+	case strings.HasSuffix(lowerPath, ".deno.ts"), strings.HasSuffix(lowerPath, ".deno.js"):
+		return LanguageDeno
+	}
+
 	ext := strings.ToLower(filepath.Ext(filePath))
 	switch ext {
 	case ".go":
@@ -46,6 +55,8 @@ func GetLanguageFromFile(filePath string) Language {
 		return LanguageTS
 	case ".mjs", ".cjs":
 		return LanguageNode
+	case ".rs":
+		return LanguageRust
 	default:
 		return LanguageGo // Default to Go
 	}
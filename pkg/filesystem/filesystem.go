@@ -13,6 +13,7 @@ type FileSystemAPI interface {
 	Stat(name string) (os.FileInfo, error)
 	Remove(name string) error
 	RemoveAll(path string) error
+	Rename(oldpath, newpath string) error
 	Abs(path string) (string, error)
 	ReadDir(dirname string) ([]os.DirEntry, error)
 }
@@ -44,6 +45,10 @@ func (fs *OSFileSystem) RemoveAll(path string) error {
 	return os.RemoveAll(path)
 }
 
+func (fs *OSFileSystem) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
 func (fs *OSFileSystem) Abs(path string) (string, error) {
 	return filepath.Abs(path)
 }
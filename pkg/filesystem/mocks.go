@@ -184,6 +184,19 @@ func (fs *MockFileSystem) RemoveAll(path string) error {
 	return nil
 }
 
+func (fs *MockFileSystem) Rename(oldpath, newpath string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	content, exists := fs.files[oldpath]
+	if !exists {
+		return fmt.Errorf("rename %s %s: %w", oldpath, newpath, os.ErrNotExist)
+	}
+	fs.files[newpath] = content
+	delete(fs.files, oldpath)
+	return nil
+}
+
 // isPathUnder checks if childPath is under parentPath
 func (fs *MockFileSystem) isPathUnder(childPath, parentPath string) bool {
 	childPath = filepath.Clean(childPath)
@@ -408,3 +421,7 @@ func (fs *FailingMockFS) Abs(path string) (string, error) {
 func (fs *FailingMockFS) ReadDir(dirname string) ([]os.DirEntry, error) {
 	return nil, os.ErrPermission
 }
+
+func (fs *FailingMockFS) Rename(oldpath, newpath string) error {
+	return os.ErrPermission
+}
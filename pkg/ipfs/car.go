@@ -0,0 +1,246 @@
+package ipfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	unixfsTypeRaw  = 0
+	unixfsTypeFile = 2
+
+	// maxDAGDepth bounds the UnixFS tree walked from a root, balanced DAGs of any realistic size stay far below it
+	maxDAGDepth = 32
+)
+
+// blockKey identifies a block by the codec and sha2-256 digest of its CID
+type blockKey struct {
+	codec  uint64
+	digest [sha256.Size]byte
+}
+
+func (id contentID) key() blockKey {
+	key := blockKey{codec: id.codec}
+	copy(key.digest[:], id.digest)
+	return key
+}
+
+// verifyCAR checks that content is the UnixFS file whose DAG is rooted at cid, using the blocks of
+// a CARv1 as served by a trustless gateway for ?format=car. Every block of the CAR must hash to its
+// CID, and the file reassembled from the blocks reachable from the root must equal content.
+func verifyCAR(cid string, car []byte, content []byte) error {
+	root, err := parseCID(cid)
+	if err != nil {
+		return err
+	}
+	blocks, err := readCARBlocks(car)
+	if err != nil {
+		return fmt.Errorf("invalid CAR for %s: %w", cid, err)
+	}
+
+	file, err := readUnixFSFile(blocks, root, len(content), 0)
+	if err != nil {
+		return fmt.Errorf("cannot verify CID %s: %w", cid, err)
+	}
+	if !bytes.Equal(file, content) {
+		return fmt.Errorf("content does not match CID %s", cid)
+	}
+	return nil
+}
+
+// readCARBlocks reads the sections of a CARv1 and verifies each block against its CID.
+// The header is skipped, the walk starts from the requested CID rather than from its roots.
+func readCARBlocks(car []byte) (map[blockKey][]byte, error) {
+	reader := bytes.NewReader(car)
+	headerLength, err := binary.ReadUvarint(reader)
+	if err != nil || headerLength > uint64(reader.Len()) {
+		return nil, fmt.Errorf("truncated header")
+	}
+	if _, err := reader.Seek(int64(headerLength), io.SeekCurrent); err != nil {
+		return nil, err
+	}
+
+	blocks := make(map[blockKey][]byte)
+	for reader.Len() > 0 {
+		sectionLength, err := binary.ReadUvarint(reader)
+		if err != nil || sectionLength > uint64(reader.Len()) {
+			return nil, fmt.Errorf("truncated section")
+		}
+		section := make([]byte, sectionLength)
+		if _, err := io.ReadFull(reader, section); err != nil {
+			return nil, err
+		}
+
+		id, cidLength, err := readBinaryCID(section)
+		if err != nil {
+			return nil, err
+		}
+		block := section[cidLength:]
+		digest := sha256.Sum256(block)
+		if !bytes.Equal(digest[:], id.digest) {
+			return nil, fmt.Errorf("block does not match its CID")
+		}
+		blocks[id.key()] = block
+	}
+	return blocks, nil
+}
+
+// readBinaryCID decodes the binary CID at the start of buf and returns it with its length.
+// A CIDv0 is a bare sha2-256 multihash, a CIDv1 is prefixed with its version and codec.
+func readBinaryCID(buf []byte) (contentID, int, error) {
+	if len(buf) >= 2 && buf[0] == hashSHA256 && buf[1] == sha256.Size {
+		if len(buf) < 2+sha256.Size {
+			return contentID{}, 0, fmt.Errorf("truncated CID")
+		}
+		return contentID{codec: codecDagPB, digest: buf[2 : 2+sha256.Size]}, 2 + sha256.Size, nil
+	}
+
+	reader := bytes.NewReader(buf)
+	version, err := binary.ReadUvarint(reader)
+	if err != nil || version != 1 {
+		return contentID{}, 0, fmt.Errorf("unsupported CID version")
+	}
+	codec, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return contentID{}, 0, fmt.Errorf("missing CID codec")
+	}
+	code, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return contentID{}, 0, fmt.Errorf("missing multihash code")
+	}
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return contentID{}, 0, fmt.Errorf("missing multihash length")
+	}
+	if code != hashSHA256 || length != sha256.Size {
+		return contentID{}, 0, fmt.Errorf("unsupported multihash 0x%x of %d bytes", code, length)
+	}
+	if uint64(reader.Len()) < length {
+		return contentID{}, 0, fmt.Errorf("truncated CID")
+	}
+	start := len(buf) - reader.Len()
+	return contentID{codec: codec, digest: buf[start : start+sha256.Size]}, start + sha256.Size, nil
+}
+
+// readUnixFSFile reassembles the UnixFS file rooted at id from blocks: a raw block is file data, a
+// dag-pb node holds its own data followed by that of its links in order. limit caps the size of the
+// file, so a DAG linking the same block many times cannot blow up.
+func readUnixFSFile(blocks map[blockKey][]byte, id contentID, limit, depth int) ([]byte, error) {
+	if depth > maxDAGDepth {
+		return nil, fmt.Errorf("DAG deeper than %d levels", maxDAGDepth)
+	}
+	block, ok := blocks[id.key()]
+	if !ok {
+		return nil, fmt.Errorf("CAR is missing block %x", id.digest)
+	}
+
+	switch id.codec {
+	case codecRaw:
+		if len(block) > limit {
+			return nil, fmt.Errorf("file exceeds %d bytes", limit)
+		}
+		return block, nil
+	case codecDagPB:
+	default:
+		return nil, fmt.Errorf("unsupported codec 0x%x in DAG", id.codec)
+	}
+
+	var links []contentID
+	var data []byte
+	err := readProtoFields(block, func(field uint64, value []byte) error {
+		switch field {
+		case 1:
+			data = value
+		case 2:
+			return readProtoFields(value, func(field uint64, value []byte) error {
+				if field != 1 {
+					return nil
+				}
+				link, length, err := readBinaryCID(value)
+				if err != nil {
+					return err
+				}
+				if length != len(value) {
+					return fmt.Errorf("trailing bytes after link CID")
+				}
+				links = append(links, link)
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid dag-pb node: %w", err)
+	}
+
+	unixfsType := uint64(unixfsTypeRaw)
+	var file []byte
+	err = readProtoFields(data, func(field uint64, value []byte) error {
+		switch field {
+		case 1:
+			typ, n := binary.Uvarint(value)
+			if n <= 0 {
+				return fmt.Errorf("invalid type")
+			}
+			unixfsType = typ
+		case 2:
+			file = append(file, value...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid UnixFS data: %w", err)
+	}
+	if unixfsType != unixfsTypeFile && unixfsType != unixfsTypeRaw {
+		return nil, fmt.Errorf("UnixFS node of type %d is not a file", unixfsType)
+	}
+	if len(file) > limit {
+		return nil, fmt.Errorf("file exceeds %d bytes", limit)
+	}
+
+	for _, link := range links {
+		child, err := readUnixFSFile(blocks, link, limit-len(file), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		file = append(file, child...)
+	}
+	return file, nil
+}
+
+// readProtoFields calls fn with each field of a protobuf message. Varint fields are passed as
+// their encoded bytes, length-delimited fields as their payload; other wire types are rejected.
+func readProtoFields(message []byte, fn func(field uint64, value []byte) error) error {
+	for len(message) > 0 {
+		tag, n := binary.Uvarint(message)
+		if n <= 0 {
+			return fmt.Errorf("invalid field tag")
+		}
+		message = message[n:]
+
+		var value []byte
+		switch tag & 0x7 {
+		case 0:
+			_, n = binary.Uvarint(message)
+			if n <= 0 {
+				return fmt.Errorf("invalid varint field")
+			}
+			value, message = message[:n], message[n:]
+		case 2:
+			length, n := binary.Uvarint(message)
+			if n <= 0 || length > uint64(len(message)-n) {
+				return fmt.Errorf("truncated length-delimited field")
+			}
+			value, message = message[n:n+int(length)], message[n+int(length):]
+		default:
+			return fmt.Errorf("unsupported wire type %d", tag&0x7)
+		}
+		if err := fn(tag>>3, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// chunkedFile is content laid out as `ipfs add --cid-version 1` does: raw leaves of the
+// default chunk size under a dag-pb UnixFS file root
+type chunkedFile struct {
+	cid    string
+	blocks [][]byte // binary CID followed by the block, root first
+}
+
+func appendProtoBytes(buf []byte, field uint64, value []byte) []byte {
+	buf = binary.AppendUvarint(buf, field<<3|2)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+func appendProtoVarint(buf []byte, field, value uint64) []byte {
+	buf = binary.AppendUvarint(buf, field<<3)
+	return binary.AppendUvarint(buf, value)
+}
+
+func binaryCID(codec uint64, block []byte) []byte {
+	digest := sha256.Sum256(block)
+	return append([]byte{0x01, byte(codec), hashSHA256, 0x20}, digest[:]...)
+}
+
+func newChunkedFile(content []byte) chunkedFile {
+	var file chunkedFile
+	var links, unixfs []byte
+	unixfs = appendProtoVarint(unixfs, 1, unixfsTypeFile)
+	unixfs = appendProtoVarint(unixfs, 3, uint64(len(content)))
+	for start := 0; start < len(content); start += unixfsChunkSize {
+		leaf := content[start:min(start+unixfsChunkSize, len(content))]
+		leafCID := binaryCID(codecRaw, leaf)
+		file.blocks = append(file.blocks, append(append([]byte{}, leafCID...), leaf...))
+
+		var link []byte
+		link = appendProtoBytes(link, 1, leafCID)
+		link = appendProtoBytes(link, 2, nil)
+		link = appendProtoVarint(link, 3, uint64(len(leaf)))
+		links = appendProtoBytes(links, 2, link)
+		unixfs = appendProtoVarint(unixfs, 4, uint64(len(leaf)))
+	}
+
+	root := appendProtoBytes(links, 1, unixfs)
+	rootCID := binaryCID(codecDagPB, root)
+	file.cid = "b" + lowerBase32.EncodeToString(rootCID)
+	file.blocks = append([][]byte{append(append([]byte{}, rootCID...), root...)}, file.blocks...)
+	return file
+}
+
+// car encodes the file's blocks as a CARv1 with a dag-cbor header {roots: [cid], version: 1}
+func (f chunkedFile) car() []byte {
+	root := append([]byte{0x00}, f.blocks[0][:36]...)
+	header := append([]byte{0xa2, 0x65}, "roots"...)
+	header = append(header, 0x81, 0xd8, 0x2a, 0x58, byte(len(root)))
+	header = append(header, root...)
+	header = append(append(header, 0x67), "version"...)
+	header = append(header, 0x01)
+
+	car := binary.AppendUvarint(nil, uint64(len(header)))
+	car = append(car, header...)
+	for _, block := range f.blocks {
+		car = binary.AppendUvarint(car, uint64(len(block)))
+		car = append(car, block...)
+	}
+	return car
+}
+
+func TestVerifyCAR(t *testing.T) {
+	content := make([]byte, 2*unixfsChunkSize)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	file := newChunkedFile(content)
+	require.Len(t, file.blocks, 3)
+	require.ErrorIs(t, VerifyCID(file.cid, content), ErrUnverifiableCID)
+
+	assert.NoError(t, verifyCAR(file.cid, file.car(), content))
+
+	tampered := bytes.Clone(content)
+	tampered[unixfsChunkSize+1] = 'X'
+	assert.ErrorContains(t, verifyCAR(file.cid, file.car(), tampered), "content does not match CID")
+	assert.ErrorContains(t, verifyCAR(file.cid, file.car(), content[:len(content)-1]), "file exceeds")
+
+	t.Run("block not matching its CID", func(t *testing.T) {
+		forged := newChunkedFile(content)
+		forged.blocks[1] = bytes.Clone(forged.blocks[1])
+		forged.blocks[1][40] ^= 0xff
+		assert.ErrorContains(t, verifyCAR(file.cid, forged.car(), content), "block does not match its CID")
+	})
+
+	t.Run("missing block", func(t *testing.T) {
+		partial := chunkedFile{cid: file.cid, blocks: file.blocks[:2]}
+		assert.ErrorContains(t, verifyCAR(file.cid, partial.car(), content), "CAR is missing block")
+	})
+
+	t.Run("DAG of other content", func(t *testing.T) {
+		other := newChunkedFile(tampered)
+		assert.ErrorContains(t, verifyCAR(file.cid, other.car(), tampered), "CAR is missing block")
+	})
+}
+
+func TestFetch_ChunkedContent_VerifiedAgainstDAG(t *testing.T) {
+	padded, err := json.Marshal(map[string]string{"padding": strings.Repeat("a", unixfsChunkSize)})
+	require.NoError(t, err)
+	file := newChunkedFile(padded)
+	contentURL := "https://gateway.pinata.cloud/ipfs/" + file.cid
+	carURL := contentURL + "?format=car&dag-scope=all"
+
+	respond := func(body []byte) *http.Response {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}
+	}
+
+	t.Run("genuine content", func(t *testing.T) {
+		client, mockHTTP, _ := createTestClient()
+		mockHTTP.On("Get", mock.Anything, contentURL).Return(respond(padded), nil)
+		mockHTTP.On("Get", mock.Anything, carURL).Return(respond(file.car()), nil)
+
+		result, err := client.Fetch(context.Background(), file.cid)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(padded)), result.StoredSize)
+		mockHTTP.AssertExpectations(t)
+	})
+
+	t.Run("padded tampered content is rejected", func(t *testing.T) {
+		tampered, err := json.Marshal(map[string]interface{}{
+			"task_data": map[string]interface{}{"task_id": []int64{124}},
+			"padding":   strings.Repeat("a", unixfsChunkSize),
+		})
+		require.NoError(t, err)
+
+		client, mockHTTP, _ := createTestClient()
+		mockHTTP.On("Get", mock.Anything, contentURL).Return(respond(tampered), nil)
+		mockHTTP.On("Get", mock.Anything, carURL).Return(respond(file.car()), nil)
+
+		_, err = client.Fetch(context.Background(), file.cid)
+		assert.ErrorContains(t, err, "content does not match CID")
+		mockHTTP.AssertExpectations(t)
+	})
+
+	t.Run("gateway without trustless responses", func(t *testing.T) {
+		client, mockHTTP, _ := createTestClient()
+		mockHTTP.On("Get", mock.Anything, contentURL).Return(respond(padded), nil)
+		mockHTTP.On("Get", mock.Anything, carURL).Return(&http.Response{StatusCode: http.StatusNotAcceptable, Body: io.NopCloser(strings.NewReader(""))}, nil)
+
+		_, err := client.Fetch(context.Background(), file.cid)
+		assert.ErrorContains(t, err, "failed to fetch DAG")
+		mockHTTP.AssertExpectations(t)
+	})
+}
+
+func TestReplicatedClient_RejectsUnverifiableReplicaCID(t *testing.T) {
+	padded, err := json.Marshal(map[string]string{"padding": strings.Repeat("a", unixfsChunkSize)})
+	require.NoError(t, err)
+
+	a, b := newFakeReplica(), newFakeReplica()
+	b.cid = newChunkedFile(padded).cid
+	client, err := NewReplicatedClient([]Replica{{"a", a}, {"b", b}}, 2, logging.NewNoOpLogger())
+	require.NoError(t, err)
+
+	_, err = client.Upload(context.Background(), "task.json", padded)
+	assert.ErrorIs(t, err, ErrUnverifiableCID)
+}
@@ -0,0 +1,218 @@
+package ipfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+const (
+	codecRaw   = 0x55
+	codecDagPB = 0x70
+	hashSHA256 = 0x12
+
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	// unixfsChunkSize is the default chunk size of `ipfs add` and Pinata, content up to it is a single block
+	unixfsChunkSize = 256 * 1024
+)
+
+// ErrUnverifiableCID is returned by VerifyCID for a dag-pb CID of content spanning several
+// blocks, which cannot be recomputed from the content alone and is checked against its DAG instead
+var ErrUnverifiableCID = errors.New("CID cannot be verified without its DAG")
+
+// CIDv1 prefix of a single raw block: version 1, raw codec 0x55, sha2-256 multihash 0x12 of 32 bytes
+var rawCIDPrefix = []byte{0x01, codecRaw, hashSHA256, 0x20}
+
+var lowerBase32 = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// ComputeCID returns the base32 CIDv1 of data stored as a single raw block with a sha2-256
+// multihash. Every backend stores content under this CID, so it is identical across backends
+// and can be computed before uploading.
+func ComputeCID(data []byte) string {
+	digest := sha256.Sum256(data)
+	cid := append(append([]byte{}, rawCIDPrefix...), digest[:]...)
+	return "b" + lowerBase32.EncodeToString(cid)
+}
+
+// contentID is the part of a CID content is verified against
+type contentID struct {
+	codec  uint64
+	digest []byte
+}
+
+// parseCID decodes a CIDv0 or a CIDv1 in base32, base58btc or base16 with a sha2-256 multihash
+func parseCID(cid string) (contentID, error) {
+	if cid == "" {
+		return contentID{}, fmt.Errorf("CID cannot be empty")
+	}
+
+	if len(cid) == 46 && strings.HasPrefix(cid, "Qm") {
+		multihash, err := decodeBase58(cid)
+		if err != nil {
+			return contentID{}, fmt.Errorf("invalid CID %s: %w", cid, err)
+		}
+		digest, err := parseMultihash(multihash)
+		if err != nil {
+			return contentID{}, fmt.Errorf("invalid CID %s: %w", cid, err)
+		}
+		return contentID{codec: codecDagPB, digest: digest}, nil
+	}
+
+	var raw []byte
+	var err error
+	switch cid[0] {
+	case 'b':
+		raw, err = lowerBase32.DecodeString(cid[1:])
+	case 'B':
+		raw, err = lowerBase32.DecodeString(strings.ToLower(cid[1:]))
+	case 'z':
+		raw, err = decodeBase58(cid[1:])
+	case 'f':
+		raw, err = hex.DecodeString(cid[1:])
+	default:
+		return contentID{}, fmt.Errorf("invalid CID %s: unsupported multibase", cid)
+	}
+	if err != nil {
+		return contentID{}, fmt.Errorf("invalid CID %s: %w", cid, err)
+	}
+
+	reader := bytes.NewReader(raw)
+	version, err := binary.ReadUvarint(reader)
+	if err != nil || version != 1 {
+		return contentID{}, fmt.Errorf("invalid CID %s: unsupported version", cid)
+	}
+	codec, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return contentID{}, fmt.Errorf("invalid CID %s: missing codec", cid)
+	}
+	digest, err := parseMultihash(raw[len(raw)-reader.Len():])
+	if err != nil {
+		return contentID{}, fmt.Errorf("invalid CID %s: %w", cid, err)
+	}
+	return contentID{codec: codec, digest: digest}, nil
+}
+
+func parseMultihash(multihash []byte) ([]byte, error) {
+	reader := bytes.NewReader(multihash)
+	code, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("missing multihash code")
+	}
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("missing multihash length")
+	}
+	if code != hashSHA256 || length != sha256.Size {
+		return nil, fmt.Errorf("unsupported multihash 0x%x of %d bytes", code, length)
+	}
+	if uint64(reader.Len()) != length {
+		return nil, fmt.Errorf("multihash digest is %d bytes, expected %d", reader.Len(), length)
+	}
+	return multihash[len(multihash)-reader.Len():], nil
+}
+
+func decodeBase58(s string) ([]byte, error) {
+	value := new(big.Int)
+	radix := big.NewInt(58)
+	for _, r := range s {
+		index := strings.IndexRune(base58Alphabet, r)
+		if index < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", r)
+		}
+		value.Mul(value, radix)
+		value.Add(value, big.NewInt(int64(index)))
+	}
+	decoded := value.Bytes()
+	leadingZeros := 0
+	for leadingZeros < len(s) && s[leadingZeros] == '1' {
+		leadingZeros++
+	}
+	return append(make([]byte, leadingZeros), decoded...), nil
+}
+
+// VerifyCID checks that content hashes to cid. Raw CIDs, those of every backend but Pinata,
+// are verified whatever the size. dag-pb CIDs are verified as a single-block UnixFS file,
+// which content up to the 256KiB default chunk size is stored as; larger content spans
+// several blocks whose layout the CID does not record, so a mismatch on it is reported as
+// ErrUnverifiableCID until the blocks are checked with verifyCAR.
+func VerifyCID(cid string, content []byte) error {
+	id, err := parseCID(cid)
+	if err != nil {
+		return err
+	}
+
+	var block []byte
+	switch id.codec {
+	case codecRaw:
+		block = content
+	case codecDagPB:
+		block = unixfsFileBlock(content)
+	default:
+		return fmt.Errorf("cannot verify CID %s: unsupported codec 0x%x", cid, id.codec)
+	}
+
+	digest := sha256.Sum256(block)
+	if !bytes.Equal(digest[:], id.digest) {
+		if id.codec == codecDagPB && len(content) > unixfsChunkSize {
+			return fmt.Errorf("%w: %s holds %d bytes", ErrUnverifiableCID, cid, len(content))
+		}
+		return fmt.Errorf("content does not match CID %s", cid)
+	}
+	return nil
+}
+
+// unixfsFileBlock encodes content as the dag-pb node of a single-block UnixFS file:
+// PBNode{Data: unixfs.Data{Type: File, Data: content, filesize: len(content)}}
+func unixfsFileBlock(content []byte) []byte {
+	unixfs := []byte{0x08, 0x02}
+	if len(content) > 0 {
+		unixfs = append(unixfs, 0x12)
+		unixfs = binary.AppendUvarint(unixfs, uint64(len(content)))
+		unixfs = append(unixfs, content...)
+	}
+	unixfs = append(unixfs, 0x18)
+	unixfs = binary.AppendUvarint(unixfs, uint64(len(content)))
+
+	node := []byte{0x0a}
+	node = binary.AppendUvarint(node, uint64(len(unixfs)))
+	return append(node, unixfs...)
+}
+
+// decodeIPFSData verifies the content fetched for cid and unmarshals the task result it holds.
+// Content that cannot be verified without its DAG is rejected.
+func decodeIPFSData(cid string, data []byte) (types.IPFSData, error) {
+	if err := VerifyCID(cid, data); err != nil {
+		return types.IPFSData{}, err
+	}
+	return unmarshalIPFSData(data)
+}
+
+// unmarshalIPFSData unmarshals verified content into the task result it holds
+func unmarshalIPFSData(data []byte) (types.IPFSData, error) {
+	var ipfsData types.IPFSData
+	if err := json.Unmarshal(data, &ipfsData); err != nil {
+		return types.IPFSData{}, fmt.Errorf("failed to unmarshal IPFS data: %w", err)
+	}
+	ipfsData.StoredSize = int64(len(data))
+	return ipfsData, nil
+}
+
+func validateUpload(filename string, data []byte) error {
+	if filename == "" {
+		return fmt.Errorf("filename cannot be empty")
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("data cannot be empty")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -15,25 +16,26 @@ import (
 	"github.com/trigg3rX/triggerx-backend/pkg/types"
 )
 
-// Client interface defines the methods for IPFS operations
+// IPFSClient is the storage interface task data is stored through. It is implemented by the
+// Pinata client and by the Kubo, S3, local filesystem and replicating backends, see NewStorage.
 type IPFSClient interface {
 	// Upload uploads data to IPFS and returns the CID
 	Upload(ctx context.Context, filename string, data []byte) (string, error)
 
-	// Fetch retrieves content from IPFS by CID
+	// Fetch retrieves content from IPFS by CID, failing if the content does not hash to the CID
 	Fetch(ctx context.Context, cid string) (types.IPFSData, error)
 
 	// Delete deletes a file from IPFS by CID
 	Delete(ctx context.Context, cid string) error
 
-	// ListFiles lists all files from Pinata v3 API
+	// ListFiles lists all stored files
 	ListFiles(ctx context.Context) ([]PinataFile, error)
 
 	// Close closes the client and cleans up resources
 	Close() error
 }
 
-// Pinata v3 API structures
+// Pinata v3 API structures, PinataFile is also the file listing of the other backends
 type PinataFile struct {
 	ID        string            `json:"id"`
 	Name      string            `json:"name"`
@@ -163,10 +165,10 @@ func (c *ipfsClient) Upload(ctx context.Context, filename string, data []byte) (
 	return cid, nil
 }
 
-// Fetch retrieves content from IPFS by CID
+// Fetch retrieves content from IPFS by CID and verifies it hashes to the CID
 func (c *ipfsClient) Fetch(ctx context.Context, cid string) (types.IPFSData, error) {
-	if cid == "" {
-		return types.IPFSData{}, fmt.Errorf("CID cannot be empty")
+	if _, err := parseCID(cid); err != nil {
+		return types.IPFSData{}, err
 	}
 
 	ipfsURL := "https://" + c.config.PinataHost + "/ipfs/" + cid
@@ -190,12 +192,39 @@ func (c *ipfsClient) Fetch(ctx context.Context, cid string) (types.IPFSData, err
 		return types.IPFSData{}, fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	var ipfsData types.IPFSData
-	if err := json.Unmarshal(body, &ipfsData); err != nil {
-		return types.IPFSData{}, fmt.Errorf("failed to unmarshal IPFS data: %v", err)
+	if err := VerifyCID(cid, body); err != nil {
+		if !errors.Is(err, ErrUnverifiableCID) {
+			return types.IPFSData{}, err
+		}
+		if err := c.verifyDAG(ctx, cid, body); err != nil {
+			return types.IPFSData{}, err
+		}
 	}
+	return unmarshalIPFSData(body)
+}
 
-	return ipfsData, nil
+// verifyDAG checks content Pinata chunked against the blocks of its DAG, fetched as a CAR
+// from the gateway's trustless endpoint
+func (c *ipfsClient) verifyDAG(ctx context.Context, cid string, content []byte) error {
+	resp, err := c.httpClient.Get(ctx, "https://"+c.config.PinataHost+"/ipfs/"+cid+"?format=car&dag-scope=all")
+	if err != nil {
+		return fmt.Errorf("failed to fetch DAG of %s: %v", cid, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch DAG of %s: status code %d", cid, resp.StatusCode)
+	}
+
+	car, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read DAG of %s: %v", cid, err)
+	}
+	return verifyCAR(cid, car, content)
 }
 
 // Delete file by ID using Pinata v3 API
@@ -417,21 +417,41 @@ func TestFetch_ValidCID_ReturnsIPFSData(t *testing.T) {
 		},
 	}
 	responseBody, _ := json.Marshal(ipfsData)
+	cid := ComputeCID(responseBody)
 
 	mockResponse := &http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(bytes.NewReader(responseBody)),
 	}
 
-	mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/QmTestCID").Return(mockResponse, nil)
+	mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/"+cid).Return(mockResponse, nil)
 
-	result, err := client.Fetch(context.Background(), "QmTestCID")
+	result, err := client.Fetch(context.Background(), cid)
 
 	assert.NoError(t, err)
 	assert.Equal(t, ipfsData.TaskData.TaskID, result.TaskData.TaskID)
 	mockHTTP.AssertExpectations(t)
 }
 
+func TestFetch_TamperedContent_ReturnsError(t *testing.T) {
+	client, mockHTTP, _ := createTestClient()
+
+	cid := ComputeCID([]byte(`{"task_data": {"task_id": [123]}}`))
+	mockResponse := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"task_data": {"task_id": [124]}}`)),
+	}
+
+	mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/"+cid).Return(mockResponse, nil)
+
+	result, err := client.Fetch(context.Background(), cid)
+
+	assert.Error(t, err)
+	assert.Equal(t, types.IPFSData{}, result)
+	assert.Contains(t, err.Error(), "content does not match CID")
+	mockHTTP.AssertExpectations(t)
+}
+
 func TestFetch_EmptyCID_ReturnsError(t *testing.T) {
 	client, _, _ := createTestClient()
 
@@ -445,9 +465,10 @@ func TestFetch_EmptyCID_ReturnsError(t *testing.T) {
 func TestFetch_HTTPError_ReturnsError(t *testing.T) {
 	client, mockHTTP, _ := createTestClient()
 
-	mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/QmTestCID").Return(nil, fmt.Errorf("network error"))
+	cid := ComputeCID([]byte("hello world"))
+	mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/"+cid).Return(nil, fmt.Errorf("network error"))
 
-	result, err := client.Fetch(context.Background(), "QmTestCID")
+	result, err := client.Fetch(context.Background(), cid)
 
 	assert.Error(t, err)
 	assert.Equal(t, types.IPFSData{}, result)
@@ -462,10 +483,11 @@ func TestFetch_NonOKStatus_ReturnsError(t *testing.T) {
 		StatusCode: http.StatusNotFound,
 		Body:       io.NopCloser(strings.NewReader("not found")),
 	}
+	cid := ComputeCID([]byte("hello world"))
 
-	mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/QmTestCID").Return(mockResponse, nil)
+	mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/"+cid).Return(mockResponse, nil)
 
-	result, err := client.Fetch(context.Background(), "QmTestCID")
+	result, err := client.Fetch(context.Background(), cid)
 
 	assert.Error(t, err)
 	assert.Equal(t, types.IPFSData{}, result)
@@ -481,10 +503,11 @@ func TestFetch_InvalidJSONResponse_ReturnsError(t *testing.T) {
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(strings.NewReader(responseBody)),
 	}
+	cid := ComputeCID([]byte(responseBody))
 
-	mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/QmTestCID").Return(mockResponse, nil)
+	mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/"+cid).Return(mockResponse, nil)
 
-	result, err := client.Fetch(context.Background(), "QmTestCID")
+	result, err := client.Fetch(context.Background(), cid)
 
 	assert.Error(t, err)
 	assert.Equal(t, types.IPFSData{}, result)
@@ -544,15 +567,16 @@ func BenchmarkFetch_SmallData(b *testing.B) {
 			},
 		}
 		responseBody, _ := json.Marshal(ipfsData)
+		cid := ComputeCID(responseBody)
 
 		mockResponse := &http.Response{
 			StatusCode: http.StatusOK,
 			Body:       io.NopCloser(bytes.NewReader(responseBody)),
 		}
 
-		mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/QmBenchmarkCID").Return(mockResponse, nil)
+		mockHTTP.On("Get", mock.Anything, "https://gateway.pinata.cloud/ipfs/"+cid).Return(mockResponse, nil)
 
-		_, err := client.Fetch(context.Background(), "QmBenchmarkCID")
+		_, err := client.Fetch(context.Background(), cid)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -629,17 +653,26 @@ func TestFetch_EdgeCases(t *testing.T) {
 		{
 			name:        "very long CID",
 			cid:         "Qm" + strings.Repeat("a", 100),
-			expectError: false,
+			expectError: true,
+			errorMsg:    "invalid CID",
 		},
 		{
 			name:        "CID with special characters",
 			cid:         "Qm@#$%^&*()",
-			expectError: false,
+			expectError: true,
+			errorMsg:    "invalid CID",
 		},
 		{
 			name:        "CID with spaces",
 			cid:         "Qm Test CID",
-			expectError: false,
+			expectError: true,
+			errorMsg:    "invalid CID",
+		},
+		{
+			name:        "unsupported multibase",
+			cid:         "mAVUSIA",
+			expectError: true,
+			errorMsg:    "unsupported multibase",
 		},
 	}
 
@@ -0,0 +1,196 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	httppkg "github.com/trigg3rX/triggerx-backend/pkg/http"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// KuboConfig configures the Kubo backend
+type KuboConfig struct {
+	// APIURL is the node's RPC API, e.g. http://127.0.0.1:5001
+	APIURL string
+}
+
+func (c KuboConfig) Validate() error {
+	if strings.TrimSpace(c.APIURL) == "" {
+		return fmt.Errorf("kubo API URL is required")
+	}
+	return nil
+}
+
+// kuboClient stores content as pinned raw blocks on a Kubo node through its RPC API.
+// Raw blocks keep the CID equal to ComputeCID whatever the content size, which UnixFS
+// chunking in /api/v0/add would not.
+type kuboClient struct {
+	config     KuboConfig
+	logger     logging.Logger
+	httpClient httppkg.HTTPClientInterface
+}
+
+// NewKuboClient creates a client of the Kubo node at config.APIURL
+func NewKuboClient(config KuboConfig, logger logging.Logger) (IPFSClient, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := httppkg.NewHTTPClient(httppkg.DefaultHTTPRetryConfig(), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kuboClient{
+		config:     KuboConfig{APIURL: strings.TrimSuffix(config.APIURL, "/")},
+		logger:     logger,
+		httpClient: httpClient,
+	}, nil
+}
+
+// Upload puts data as a pinned raw block and returns its CID
+func (c *kuboClient) Upload(ctx context.Context, filename string, data []byte) (string, error) {
+	if err := validateUpload(filename, data); err != nil {
+		return "", err
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("failed to write data to form: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to close writer: %w", err)
+	}
+
+	query := url.Values{
+		"cid-codec":       {"raw"},
+		"mhtype":          {"sha2-256"},
+		"pin":             {"true"},
+		"allow-big-block": {"true"},
+	}
+	respBody, err := c.call(ctx, "block/put", query, writer.FormDataContentType(), body)
+	if err != nil {
+		return "", err
+	}
+
+	var putResponse struct {
+		Key  string `json:"Key"`
+		Size int64  `json:"Size"`
+	}
+	if err := json.Unmarshal(respBody, &putResponse); err != nil {
+		return "", fmt.Errorf("failed to unmarshal block/put response: %w", err)
+	}
+	cid := ComputeCID(data)
+	if putResponse.Key != cid {
+		return "", fmt.Errorf("kubo returned CID %s, expected %s", putResponse.Key, cid)
+	}
+
+	c.logger.Info("Successfully uploaded to Kubo", "filename", filename, "cid", cid)
+	return cid, nil
+}
+
+// Fetch retrieves and decodes the block stored under cid
+func (c *kuboClient) Fetch(ctx context.Context, cid string) (types.IPFSData, error) {
+	if _, err := parseCID(cid); err != nil {
+		return types.IPFSData{}, err
+	}
+	data, err := c.call(ctx, "block/get", url.Values{"arg": {cid}}, "", nil)
+	if err != nil {
+		return types.IPFSData{}, fmt.Errorf("failed to fetch IPFS content: %w", err)
+	}
+	return decodeIPFSData(cid, data)
+}
+
+// Delete unpins cid, the node's garbage collector reclaims the block
+func (c *kuboClient) Delete(ctx context.Context, cid string) error {
+	if cid == "" {
+		return fmt.Errorf("CID cannot be empty")
+	}
+	if _, err := c.call(ctx, "pin/rm", url.Values{"arg": {cid}}, "", nil); err != nil {
+		return fmt.Errorf("failed to unpin %s: %w", cid, err)
+	}
+	c.logger.Infof("Successfully unpinned %s from Kubo", cid)
+	return nil
+}
+
+// ListFiles lists the node's recursive pins. Kubo keeps no file names for raw blocks,
+// so the name of every file is its CID.
+func (c *kuboClient) ListFiles(ctx context.Context) ([]PinataFile, error) {
+	respBody, err := c.call(ctx, "pin/ls", url.Values{"type": {"recursive"}}, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pins: %w", err)
+	}
+
+	var lsResponse struct {
+		Keys map[string]struct {
+			Type string `json:"Type"`
+		} `json:"Keys"`
+	}
+	if err := json.Unmarshal(respBody, &lsResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode pin/ls response: %w", err)
+	}
+
+	files := make([]PinataFile, 0, len(lsResponse.Keys))
+	for cid := range lsResponse.Keys {
+		files = append(files, PinataFile{ID: cid, Name: cid, CID: cid})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].CID < files[j].CID
+	})
+	return files, nil
+}
+
+func (c *kuboClient) Close() error {
+	c.httpClient.Close()
+	return nil
+}
+
+// call POSTs to an RPC API command, as Kubo requires for every command, and returns the body
+func (c *kuboClient) call(ctx context.Context, command string, query url.Values, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.APIURL+"/api/v0/"+command+"?"+query.Encode(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.DoWithRetry(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.Debugf("failed to close response body: %v", err)
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var kuboErr struct {
+			Message string `json:"Message"`
+		}
+		if json.Unmarshal(respBody, &kuboErr) == nil && kuboErr.Message != "" {
+			return nil, fmt.Errorf("kubo %s failed: status %d: %s", command, resp.StatusCode, kuboErr.Message)
+		}
+		return nil, fmt.Errorf("kubo %s failed: status %d", command, resp.StatusCode)
+	}
+	return respBody, nil
+}
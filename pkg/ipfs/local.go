@@ -0,0 +1,169 @@
+package ipfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+const localMetadataSuffix = ".meta.json"
+
+// LocalConfig configures the local filesystem backend
+type LocalConfig struct {
+	// Dir is the directory content is stored in, one file per CID
+	Dir string
+}
+
+func (c LocalConfig) Validate() error {
+	if strings.TrimSpace(c.Dir) == "" {
+		return fmt.Errorf("local storage directory is required")
+	}
+	return nil
+}
+
+// localClient stores content on the local filesystem under its CID, next to a metadata file
+// holding the uploaded file name
+type localClient struct {
+	config LocalConfig
+	logger logging.Logger
+}
+
+// NewLocalClient creates a content-addressed store in config.Dir, creating the directory if needed
+func NewLocalClient(config LocalConfig, logger logging.Logger) (IPFSClient, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage directory: %w", err)
+	}
+	return &localClient{
+		config: config,
+		logger: logger,
+	}, nil
+}
+
+// Upload writes data under its CID, rewriting it is a no-op apart from the metadata
+func (c *localClient) Upload(_ context.Context, filename string, data []byte) (string, error) {
+	if err := validateUpload(filename, data); err != nil {
+		return "", err
+	}
+
+	cid := ComputeCID(data)
+	if err := c.writeFile(cid, data); err != nil {
+		return "", fmt.Errorf("failed to write content: %w", err)
+	}
+	metadata, err := json.Marshal(PinataFile{
+		ID:        cid,
+		Name:      filename,
+		CID:       cid,
+		Size:      int64(len(data)),
+		MimeType:  "application/json",
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	if err := c.writeFile(cid+localMetadataSuffix, metadata); err != nil {
+		return "", fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	c.logger.Info("Successfully stored content locally", "filename", filename, "cid", cid)
+	return cid, nil
+}
+
+// Fetch reads and decodes the content stored under cid
+func (c *localClient) Fetch(_ context.Context, cid string) (types.IPFSData, error) {
+	path, err := c.path(cid)
+	if err != nil {
+		return types.IPFSData{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return types.IPFSData{}, fmt.Errorf("failed to read content %s: %w", cid, err)
+	}
+	return decodeIPFSData(cid, data)
+}
+
+// Delete removes the content stored under cid and its metadata
+func (c *localClient) Delete(_ context.Context, cid string) error {
+	path, err := c.path(cid)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete content %s: %w", cid, err)
+	}
+	if err := os.Remove(path + localMetadataSuffix); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete metadata of %s: %w", cid, err)
+	}
+	return nil
+}
+
+// ListFiles lists the stored content, oldest first
+func (c *localClient) ListFiles(_ context.Context) ([]PinataFile, error) {
+	entries, err := os.ReadDir(c.config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local storage directory: %w", err)
+	}
+
+	var files []PinataFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), localMetadataSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.config.Dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read metadata %s: %w", entry.Name(), err)
+		}
+		var file PinataFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata %s: %w", entry.Name(), err)
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].CreatedAt.Before(files[j].CreatedAt)
+	})
+	return files, nil
+}
+
+func (c *localClient) Close() error {
+	return nil
+}
+
+// path returns where cid is stored, rejecting anything that is not a plain file name
+func (c *localClient) path(cid string) (string, error) {
+	if cid == "" {
+		return "", fmt.Errorf("CID cannot be empty")
+	}
+	if cid != filepath.Base(cid) || strings.HasPrefix(cid, ".") {
+		return "", fmt.Errorf("invalid CID: %s", cid)
+	}
+	return filepath.Join(c.config.Dir, cid), nil
+}
+
+// writeFile writes name atomically, through a temporary file renamed into place
+func (c *localClient) writeFile(name string, data []byte) error {
+	tmp, err := os.CreateTemp(c.config.Dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.config.Dir, name))
+}
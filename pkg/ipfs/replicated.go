@@ -0,0 +1,231 @@
+package ipfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// Replica is a named backend of a replicating client
+type Replica struct {
+	Name   string
+	Client IPFSClient
+}
+
+// dagVerifier is implemented by backends which chunk content themselves, so a CID they store it
+// under can be checked against the blocks of its DAG
+type dagVerifier interface {
+	verifyDAG(ctx context.Context, cid string, content []byte) error
+}
+
+// replicatedClient writes content to every replica and reads it from the fastest one
+type replicatedClient struct {
+	replicas    []Replica
+	minReplicas int
+	logger      logging.Logger
+
+	mu sync.RWMutex
+	// replicaCIDs maps a locally computed CID to the CID each replica stored it under,
+	// for replicas which chunk content themselves instead of storing it as a raw block
+	replicaCIDs map[string]map[string]string
+}
+
+// NewReplicatedClient creates a client replicating to replicas. An upload succeeds once
+// minReplicas of them stored the content, under the CID computed locally or under a CID
+// of their own for the same content, as Pinata does. Replica CIDs are recorded by the
+// client, content uploaded by another process is addressed by its local CID everywhere.
+func NewReplicatedClient(replicas []Replica, minReplicas int, logger logging.Logger) (IPFSClient, error) {
+	if len(replicas) == 0 {
+		return nil, fmt.Errorf("at least one replica is required")
+	}
+	if minReplicas <= 0 {
+		minReplicas = 1
+	}
+	if minReplicas > len(replicas) {
+		return nil, fmt.Errorf("min replicas %d exceeds the %d configured replicas", minReplicas, len(replicas))
+	}
+	return &replicatedClient{
+		replicas:    replicas,
+		minReplicas: minReplicas,
+		logger:      logger,
+		replicaCIDs: make(map[string]map[string]string),
+	}, nil
+}
+
+// Upload writes data to every replica concurrently
+func (c *replicatedClient) Upload(ctx context.Context, filename string, data []byte) (string, error) {
+	if err := validateUpload(filename, data); err != nil {
+		return "", err
+	}
+
+	cid := ComputeCID(data)
+	errs := make([]error, len(c.replicas))
+	replicaCIDs := make([]string, len(c.replicas))
+	var wg sync.WaitGroup
+	for i, replica := range c.replicas {
+		wg.Add(1)
+		go func(i int, replica Replica) {
+			defer wg.Done()
+			replicaCID, err := replica.Client.Upload(ctx, filename, data)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", replica.Name, err)
+				return
+			}
+			if replicaCID != cid {
+				err := VerifyCID(replicaCID, data)
+				if verifier, ok := replica.Client.(dagVerifier); ok && errors.Is(err, ErrUnverifiableCID) {
+					err = verifier.verifyDAG(ctx, replicaCID, data)
+				}
+				if err != nil {
+					errs[i] = fmt.Errorf("%s: stored content under CID %s, expected %s: %w", replica.Name, replicaCID, cid, err)
+					return
+				}
+			}
+			replicaCIDs[i] = replicaCID
+		}(i, replica)
+	}
+	wg.Wait()
+
+	stored := 0
+	aliases := make(map[string]string)
+	for i, err := range errs {
+		if err != nil {
+			c.logger.Warn("Failed to replicate content", "filename", filename, "cid", cid, "error", err)
+			continue
+		}
+		stored++
+		if replicaCIDs[i] != cid {
+			aliases[c.replicas[i].Name] = replicaCIDs[i]
+		}
+	}
+	if stored < c.minReplicas {
+		return "", fmt.Errorf("content stored on %d of %d required replicas: %w", stored, c.minReplicas, errors.Join(errs...))
+	}
+	if len(aliases) > 0 {
+		c.mu.Lock()
+		c.replicaCIDs[cid] = aliases
+		c.mu.Unlock()
+	}
+	return cid, nil
+}
+
+// replicaCID returns the CID replica stored the content of cid under
+func (c *replicatedClient) replicaCID(replica, cid string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if alias, ok := c.replicaCIDs[cid][replica]; ok {
+		return alias
+	}
+	return cid
+}
+
+// Fetch reads cid from every replica concurrently and returns the first successful result
+func (c *replicatedClient) Fetch(ctx context.Context, cid string) (types.IPFSData, error) {
+	if cid == "" {
+		return types.IPFSData{}, fmt.Errorf("CID cannot be empty")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		data types.IPFSData
+		err  error
+	}
+	results := make(chan result, len(c.replicas))
+	for _, replica := range c.replicas {
+		go func(replica Replica) {
+			data, err := replica.Client.Fetch(ctx, c.replicaCID(replica.Name, cid))
+			if err != nil {
+				err = fmt.Errorf("%s: %w", replica.Name, err)
+			}
+			results <- result{data: data, err: err}
+		}(replica)
+	}
+
+	var errs []error
+	for range c.replicas {
+		r := <-results
+		if r.err == nil {
+			return r.data, nil
+		}
+		errs = append(errs, r.err)
+	}
+	return types.IPFSData{}, fmt.Errorf("failed to fetch %s from every replica: %w", cid, errors.Join(errs...))
+}
+
+// Delete removes cid from every replica, it fails only if no replica could delete it
+func (c *replicatedClient) Delete(ctx context.Context, cid string) error {
+	if cid == "" {
+		return fmt.Errorf("CID cannot be empty")
+	}
+
+	var errs []error
+	for _, replica := range c.replicas {
+		if err := replica.Client.Delete(ctx, c.replicaCID(replica.Name, cid)); err != nil {
+			c.logger.Warn("Failed to delete content from replica", "replica", replica.Name, "cid", cid, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", replica.Name, err))
+		}
+	}
+	if len(errs) == len(c.replicas) {
+		return fmt.Errorf("failed to delete %s from every replica: %w", cid, errors.Join(errs...))
+	}
+	c.mu.Lock()
+	delete(c.replicaCIDs, cid)
+	c.mu.Unlock()
+	return nil
+}
+
+// ListFiles merges the replicas' listings by CID, earlier replicas taking precedence.
+// Files a replica stored under its own CID are listed under the local one.
+func (c *replicatedClient) ListFiles(ctx context.Context) ([]PinataFile, error) {
+	c.mu.RLock()
+	localCIDs := make(map[string]string)
+	for cid, aliases := range c.replicaCIDs {
+		for _, alias := range aliases {
+			localCIDs[alias] = cid
+		}
+	}
+	c.mu.RUnlock()
+
+	var files []PinataFile
+	seen := make(map[string]bool)
+	var errs []error
+	for _, replica := range c.replicas {
+		replicaFiles, err := replica.Client.ListFiles(ctx)
+		if err != nil {
+			c.logger.Warn("Failed to list replica", "replica", replica.Name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", replica.Name, err))
+			continue
+		}
+		for _, file := range replicaFiles {
+			if cid, ok := localCIDs[file.CID]; ok {
+				file.CID = cid
+			}
+			if seen[file.CID] {
+				continue
+			}
+			seen[file.CID] = true
+			files = append(files, file)
+		}
+	}
+	if len(errs) == len(c.replicas) {
+		return nil, fmt.Errorf("failed to list every replica: %w", errors.Join(errs...))
+	}
+	return files, nil
+}
+
+// Close closes every replica
+func (c *replicatedClient) Close() error {
+	var errs []error
+	for _, replica := range c.replicas {
+		if err := replica.Client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", replica.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
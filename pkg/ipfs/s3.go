@@ -0,0 +1,311 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	httppkg "github.com/trigg3rX/triggerx-backend/pkg/http"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+const (
+	s3DefaultRegion    = "us-east-1"
+	s3DefaultKeyPrefix = "ipfs/"
+	s3FilenameHeader   = "X-Amz-Meta-Filename"
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+	s3TimeFormat       = "20060102T150405Z"
+)
+
+// S3Config configures the S3-compatible object store backend
+type S3Config struct {
+	// Endpoint is the store's base URL, e.g. https://s3.us-east-1.amazonaws.com or http://127.0.0.1:9000
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// KeyPrefix is prepended to the CID to form the object key, defaults to "ipfs/"
+	KeyPrefix string
+}
+
+func (c S3Config) Validate() error {
+	if strings.TrimSpace(c.Endpoint) == "" {
+		return fmt.Errorf("S3 endpoint is required")
+	}
+	if strings.TrimSpace(c.Bucket) == "" {
+		return fmt.Errorf("S3 bucket is required")
+	}
+	if strings.TrimSpace(c.AccessKeyID) == "" || strings.TrimSpace(c.SecretAccessKey) == "" {
+		return fmt.Errorf("S3 access key ID and secret access key are required")
+	}
+	return nil
+}
+
+// s3Client stores content as objects keyed by CID in an S3-compatible bucket, addressed
+// path-style and signed with AWS Signature Version 4
+type s3Client struct {
+	config     S3Config
+	logger     logging.Logger
+	httpClient httppkg.HTTPClientInterface
+	now        func() time.Time
+}
+
+// NewS3Client creates a client of the bucket in config
+func NewS3Client(config S3Config, logger logging.Logger) (IPFSClient, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Region == "" {
+		config.Region = s3DefaultRegion
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = s3DefaultKeyPrefix
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+
+	httpClient, err := httppkg.NewHTTPClient(httppkg.DefaultHTTPRetryConfig(), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Client{
+		config:     config,
+		logger:     logger,
+		httpClient: httpClient,
+		now:        time.Now,
+	}, nil
+}
+
+// Upload puts data under its CID, keeping the file name as object metadata
+func (c *s3Client) Upload(ctx context.Context, filename string, data []byte) (string, error) {
+	if err := validateUpload(filename, data); err != nil {
+		return "", err
+	}
+
+	cid := ComputeCID(data)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(s3FilenameHeader, filename)
+	if _, err := c.do(ctx, http.MethodPut, c.objectPath(cid), nil, header, data, http.StatusOK); err != nil {
+		return "", fmt.Errorf("failed to upload object: %w", err)
+	}
+
+	c.logger.Info("Successfully uploaded to S3", "filename", filename, "cid", cid)
+	return cid, nil
+}
+
+// Fetch retrieves and decodes the object stored under cid
+func (c *s3Client) Fetch(ctx context.Context, cid string) (types.IPFSData, error) {
+	if _, err := parseCID(cid); err != nil {
+		return types.IPFSData{}, err
+	}
+	data, err := c.do(ctx, http.MethodGet, c.objectPath(cid), nil, nil, nil, http.StatusOK)
+	if err != nil {
+		return types.IPFSData{}, fmt.Errorf("failed to fetch object: %w", err)
+	}
+	return decodeIPFSData(cid, data)
+}
+
+// Delete removes the object stored under cid
+func (c *s3Client) Delete(ctx context.Context, cid string) error {
+	if cid == "" {
+		return fmt.Errorf("CID cannot be empty")
+	}
+	if _, err := c.do(ctx, http.MethodDelete, c.objectPath(cid), nil, nil, nil, http.StatusNoContent, http.StatusOK); err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", cid, err)
+	}
+	c.logger.Infof("Successfully deleted %s from S3", cid)
+	return nil
+}
+
+// ListFiles lists the objects under the key prefix. Listing does not return object metadata,
+// so the name of every file is its CID.
+func (c *s3Client) ListFiles(ctx context.Context) ([]PinataFile, error) {
+	var files []PinataFile
+	continuationToken := ""
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"prefix":    {c.config.KeyPrefix},
+		}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+		body, err := c.do(ctx, http.MethodGet, "/"+c.config.Bucket, query, nil, nil, http.StatusOK)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		var listResult struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(body, &listResult); err != nil {
+			return nil, fmt.Errorf("failed to decode list response: %w", err)
+		}
+		for _, object := range listResult.Contents {
+			cid := strings.TrimPrefix(object.Key, c.config.KeyPrefix)
+			files = append(files, PinataFile{
+				ID:        cid,
+				Name:      cid,
+				CID:       cid,
+				Size:      object.Size,
+				CreatedAt: object.LastModified,
+			})
+		}
+
+		if !listResult.IsTruncated || listResult.NextContinuationToken == "" {
+			break
+		}
+		continuationToken = listResult.NextContinuationToken
+	}
+	return files, nil
+}
+
+func (c *s3Client) Close() error {
+	c.httpClient.Close()
+	return nil
+}
+
+func (c *s3Client) objectPath(cid string) string {
+	return "/" + c.config.Bucket + "/" + c.config.KeyPrefix + cid
+}
+
+// do sends a signed request and returns the body of a response with one of the expected statuses
+func (c *s3Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body []byte, expectedStatus ...int) ([]byte, error) {
+	target := c.config.Endpoint + path
+	if len(query) > 0 {
+		target += "?" + s3CanonicalQuery(query)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	signS3Request(req, body, c.config, c.now())
+
+	resp, err := c.httpClient.DoWithRetry(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.Debugf("failed to close response body: %v", err)
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	for _, status := range expectedStatus {
+		if resp.StatusCode == status {
+			return respBody, nil
+		}
+	}
+	return nil, fmt.Errorf("status %d, body: %s", resp.StatusCode, string(respBody))
+}
+
+// signS3Request adds the AWS Signature Version 4 Authorization header, signing the host and
+// every header already set on the request
+func signS3Request(req *http.Request, body []byte, config S3Config, now time.Time) {
+	payloadHash := sha256.Sum256(body)
+	amzDate := now.UTC().Format(s3TimeFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	date := amzDate[:8]
+	scope := date + "/" + config.Region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3SigningAlgorithm, amzDate, scope, hex.EncodeToString(canonicalHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+config.SecretAccessKey), date)
+	key = hmacSHA256(key, config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		s3SigningAlgorithm, config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// s3CanonicalQuery encodes a query sorted by key with RFC 3986 escaping, as SigV4 requires
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, s3Escape(key)+"="+s3Escape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func s3Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
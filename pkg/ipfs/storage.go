@@ -0,0 +1,105 @@
+package ipfs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// Backend names a storage backend
+type Backend string
+
+const (
+	BackendPinata Backend = "pinata"
+	BackendKubo   Backend = "kubo"
+	BackendS3     Backend = "s3"
+	BackendLocal  Backend = "local"
+)
+
+// ParseBackends parses a comma separated list of backends, e.g. "pinata,local"
+func ParseBackends(value string) ([]Backend, error) {
+	var backends []Backend
+	seen := make(map[Backend]bool)
+	for _, name := range strings.Split(value, ",") {
+		backend := Backend(strings.ToLower(strings.TrimSpace(name)))
+		if backend == "" {
+			continue
+		}
+		switch backend {
+		case BackendPinata, BackendKubo, BackendS3, BackendLocal:
+		default:
+			return nil, fmt.Errorf("unknown storage backend: %s", name)
+		}
+		if seen[backend] {
+			return nil, fmt.Errorf("storage backend %s is listed twice", backend)
+		}
+		seen[backend] = true
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
+
+// StorageConfig selects and configures the backends a service stores task data on
+type StorageConfig struct {
+	// Backends to store on, defaults to Pinata. With several the client replicates to all of them.
+	Backends []Backend
+	// MinReplicas is how many backends an upload must reach when replicating, defaults to 1
+	MinReplicas int
+
+	Pinata *Config
+	Kubo   KuboConfig
+	S3     S3Config
+	Local  LocalConfig
+}
+
+// NewStorage creates the client for the configured backends
+func NewStorage(config StorageConfig, logger logging.Logger) (IPFSClient, error) {
+	backends := config.Backends
+	if len(backends) == 0 {
+		backends = []Backend{BackendPinata}
+	}
+
+	replicas := make([]Replica, 0, len(backends))
+	closeAll := func() {
+		for _, replica := range replicas {
+			_ = replica.Client.Close()
+		}
+	}
+	for _, backend := range backends {
+		client, err := newBackend(backend, config, logger)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to create %s storage: %w", backend, err)
+		}
+		replicas = append(replicas, Replica{Name: string(backend), Client: client})
+	}
+
+	if len(replicas) == 1 {
+		return replicas[0].Client, nil
+	}
+	client, err := NewReplicatedClient(replicas, config.MinReplicas, logger)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return client, nil
+}
+
+func newBackend(backend Backend, config StorageConfig, logger logging.Logger) (IPFSClient, error) {
+	switch backend {
+	case BackendPinata:
+		if config.Pinata == nil {
+			return nil, fmt.Errorf("pinata configuration is missing")
+		}
+		return NewClient(config.Pinata, logger)
+	case BackendKubo:
+		return NewKuboClient(config.Kubo, logger)
+	case BackendS3:
+		return NewS3Client(config.S3, logger)
+	case BackendLocal:
+		return NewLocalClient(config.Local, logger)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", backend)
+	}
+}
@@ -0,0 +1,428 @@
+package ipfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func testIPFSData(t *testing.T, taskID int64) []byte {
+	t.Helper()
+	data, err := json.Marshal(types.IPFSData{
+		PerformerSignature: &types.PerformerSignatureData{TaskID: taskID},
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// dagPBCID returns the CIDv1 Pinata stores single-block content under
+func dagPBCID(data []byte) string {
+	digest := sha256.Sum256(unixfsFileBlock(data))
+	cid := append([]byte{0x01, codecDagPB, hashSHA256, 0x20}, digest[:]...)
+	return "b" + lowerBase32.EncodeToString(cid)
+}
+
+func TestComputeCID_MatchesKubo(t *testing.T) {
+	// ipfs block put --cid-codec raw of the empty block and of "hello world"
+	assert.Equal(t, "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku", ComputeCID(nil))
+	assert.Equal(t, "bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e", ComputeCID([]byte("hello world")))
+}
+
+func TestVerifyCID(t *testing.T) {
+	// CIDv0 is the dag-pb UnixFS file node of `ipfs add`, CIDv1 raw is the block itself
+	assert.NoError(t, VerifyCID("Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", []byte("hello world")))
+	assert.NoError(t, VerifyCID("QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o", []byte("hello world\n")))
+	assert.NoError(t, VerifyCID("bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e", []byte("hello world")))
+	assert.NoError(t, VerifyCID(strings.ToUpper(ComputeCID([]byte("hello world"))), []byte("hello world")))
+
+	assert.ErrorContains(t, VerifyCID(ComputeCID([]byte("hello world")), []byte("hello world!")), "content does not match CID")
+	assert.ErrorContains(t, VerifyCID("Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", []byte("hello world!")), "content does not match CID")
+	assert.ErrorContains(t, VerifyCID("", nil), "CID cannot be empty")
+	assert.ErrorContains(t, VerifyCID("QmTestCID", nil), "invalid CID")
+	// Chunked dag-pb content cannot be checked against its root alone
+	large := []byte(strings.Repeat("a", unixfsChunkSize+1))
+	assert.NoError(t, VerifyCID(dagPBCID(large), large))
+	assert.ErrorIs(t, VerifyCID("Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", large), ErrUnverifiableCID)
+	assert.ErrorContains(t, VerifyCID(ComputeCID([]byte("hello world")), large), "content does not match CID")
+	// dag-cbor is parsed but not verified
+	assert.ErrorContains(t, VerifyCID("bafyreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e", nil), "unsupported codec")
+}
+
+func TestDecodeIPFSData(t *testing.T) {
+	data := testIPFSData(t, 3)
+
+	decoded, err := decodeIPFSData(ComputeCID(data), data)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), decoded.PerformerSignature.TaskID)
+	assert.Equal(t, int64(len(data)), decoded.StoredSize)
+
+	_, err = decodeIPFSData(ComputeCID([]byte("other")), data)
+	assert.ErrorContains(t, err, "content does not match CID")
+
+	// Content padded past the chunk size is not trusted without its DAG
+	padded, err := json.Marshal(map[string]string{"padding": strings.Repeat("a", unixfsChunkSize)})
+	require.NoError(t, err)
+	_, err = decodeIPFSData("Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", padded)
+	assert.ErrorIs(t, err, ErrUnverifiableCID)
+}
+
+func TestParseBackends(t *testing.T) {
+	backends, err := ParseBackends(" Pinata, local ,,s3")
+	require.NoError(t, err)
+	assert.Equal(t, []Backend{BackendPinata, BackendLocal, BackendS3}, backends)
+
+	_, err = ParseBackends("pinata,ftp")
+	assert.ErrorContains(t, err, "unknown storage backend: ftp")
+
+	_, err = ParseBackends("local,local")
+	assert.ErrorContains(t, err, "listed twice")
+}
+
+func TestNewStorage(t *testing.T) {
+	logger := logging.NewNoOpLogger()
+
+	t.Run("defaults to pinata", func(t *testing.T) {
+		client, err := NewStorage(StorageConfig{Pinata: createTestConfig()}, logger)
+		require.NoError(t, err)
+		assert.IsType(t, &ipfsClient{}, client)
+	})
+
+	t.Run("single backend is not wrapped", func(t *testing.T) {
+		client, err := NewStorage(StorageConfig{
+			Backends: []Backend{BackendLocal},
+			Local:    LocalConfig{Dir: t.TempDir()},
+		}, logger)
+		require.NoError(t, err)
+		assert.IsType(t, &localClient{}, client)
+	})
+
+	t.Run("several backends replicate", func(t *testing.T) {
+		client, err := NewStorage(StorageConfig{
+			Backends:    []Backend{BackendLocal, BackendKubo},
+			MinReplicas: 2,
+			Local:       LocalConfig{Dir: t.TempDir()},
+			Kubo:        KuboConfig{APIURL: "http://127.0.0.1:5001"},
+		}, logger)
+		require.NoError(t, err)
+		assert.IsType(t, &replicatedClient{}, client)
+	})
+
+	t.Run("invalid backend configuration", func(t *testing.T) {
+		_, err := NewStorage(StorageConfig{Backends: []Backend{BackendS3}}, logger)
+		assert.ErrorContains(t, err, "failed to create s3 storage: S3 endpoint is required")
+
+		_, err = NewStorage(StorageConfig{Backends: []Backend{BackendPinata}}, logger)
+		assert.ErrorContains(t, err, "pinata configuration is missing")
+	})
+
+	t.Run("min replicas above backends", func(t *testing.T) {
+		_, err := NewStorage(StorageConfig{
+			Backends:    []Backend{BackendLocal},
+			MinReplicas: 2,
+			Local:       LocalConfig{Dir: t.TempDir()},
+		}, logger)
+		require.NoError(t, err, "a single backend ignores min replicas")
+
+		_, err = NewReplicatedClient([]Replica{{Name: "local"}}, 2, logger)
+		assert.ErrorContains(t, err, "exceeds")
+	})
+}
+
+func TestLocalClient_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewLocalClient(LocalConfig{Dir: t.TempDir()}, logging.NewNoOpLogger())
+	require.NoError(t, err)
+
+	first := testIPFSData(t, 1)
+	cid, err := client.Upload(ctx, "proof_of_task_1.json", first)
+	require.NoError(t, err)
+	assert.Equal(t, ComputeCID(first), cid)
+
+	fetched, err := client.Fetch(ctx, cid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), fetched.PerformerSignature.TaskID)
+
+	time.Sleep(time.Millisecond)
+	second, err := client.Upload(ctx, "proof_of_task_2.json", testIPFSData(t, 2))
+	require.NoError(t, err)
+
+	files, err := client.ListFiles(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "proof_of_task_1.json", files[0].Name)
+	assert.Equal(t, cid, files[0].CID)
+	assert.Equal(t, int64(len(first)), files[0].Size)
+	assert.Equal(t, second, files[1].CID)
+
+	require.NoError(t, client.Delete(ctx, cid))
+	_, err = client.Fetch(ctx, cid)
+	assert.Error(t, err)
+	assert.Error(t, client.Delete(ctx, cid))
+
+	files, err = client.ListFiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestLocalClient_RejectsInvalidInput(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewLocalClient(LocalConfig{Dir: t.TempDir()}, logging.NewNoOpLogger())
+	require.NoError(t, err)
+
+	_, err = client.Upload(ctx, "", []byte("{}"))
+	assert.ErrorContains(t, err, "filename cannot be empty")
+	_, err = client.Upload(ctx, "empty.json", nil)
+	assert.ErrorContains(t, err, "data cannot be empty")
+
+	for _, cid := range []string{"", "../etc/passwd", ".meta.json", "a/b"} {
+		_, err = client.Fetch(ctx, cid)
+		assert.Error(t, err, cid)
+	}
+
+	_, err = NewLocalClient(LocalConfig{}, logging.NewNoOpLogger())
+	assert.ErrorContains(t, err, "directory is required")
+}
+
+// fakeReplica is an in-memory backend whose operations can be made to fail or stall
+type fakeReplica struct {
+	blobs     map[string][]byte
+	cid       string
+	err       error
+	delay     time.Duration
+	closed    bool
+	fetchDone chan struct{}
+}
+
+func newFakeReplica() *fakeReplica {
+	return &fakeReplica{blobs: make(map[string][]byte)}
+}
+
+func (f *fakeReplica) Upload(_ context.Context, _ string, data []byte) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	cid := ComputeCID(data)
+	if f.cid != "" {
+		cid = f.cid
+	}
+	f.blobs[cid] = data
+	return cid, nil
+}
+
+func (f *fakeReplica) Fetch(ctx context.Context, cid string) (types.IPFSData, error) {
+	if f.fetchDone != nil {
+		defer close(f.fetchDone)
+	}
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return types.IPFSData{}, ctx.Err()
+	}
+	if f.err != nil {
+		return types.IPFSData{}, f.err
+	}
+	data, ok := f.blobs[cid]
+	if !ok {
+		return types.IPFSData{}, fmt.Errorf("not found")
+	}
+	return decodeIPFSData(cid, data)
+}
+
+func (f *fakeReplica) Delete(_ context.Context, cid string) error {
+	if f.err != nil {
+		return f.err
+	}
+	delete(f.blobs, cid)
+	return nil
+}
+
+func (f *fakeReplica) ListFiles(_ context.Context) ([]PinataFile, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var files []PinataFile
+	for cid := range f.blobs {
+		files = append(files, PinataFile{CID: cid})
+	}
+	return files, nil
+}
+
+func (f *fakeReplica) Close() error {
+	f.closed = true
+	return f.err
+}
+
+func TestReplicatedClient_Upload(t *testing.T) {
+	ctx := context.Background()
+	data := testIPFSData(t, 7)
+
+	t.Run("writes to every replica", func(t *testing.T) {
+		a, b := newFakeReplica(), newFakeReplica()
+		client, err := NewReplicatedClient([]Replica{{"a", a}, {"b", b}}, 2, logging.NewNoOpLogger())
+		require.NoError(t, err)
+
+		cid, err := client.Upload(ctx, "task.json", data)
+		require.NoError(t, err)
+		assert.Equal(t, ComputeCID(data), cid)
+		assert.Contains(t, a.blobs, cid)
+		assert.Contains(t, b.blobs, cid)
+	})
+
+	t.Run("tolerates failures down to min replicas", func(t *testing.T) {
+		a, b := newFakeReplica(), newFakeReplica()
+		b.err = fmt.Errorf("unavailable")
+		client, err := NewReplicatedClient([]Replica{{"a", a}, {"b", b}}, 1, logging.NewNoOpLogger())
+		require.NoError(t, err)
+
+		_, err = client.Upload(ctx, "task.json", data)
+		assert.NoError(t, err)
+	})
+
+	t.Run("fails below min replicas", func(t *testing.T) {
+		a, b := newFakeReplica(), newFakeReplica()
+		b.err = fmt.Errorf("unavailable")
+		client, err := NewReplicatedClient([]Replica{{"a", a}, {"b", b}}, 2, logging.NewNoOpLogger())
+		require.NoError(t, err)
+
+		_, err = client.Upload(ctx, "task.json", data)
+		assert.ErrorContains(t, err, "content stored on 1 of 2 required replicas")
+		assert.ErrorContains(t, err, "b: unavailable")
+	})
+
+	t.Run("a replica storing content under its own CID counts", func(t *testing.T) {
+		a, pinata := newFakeReplica(), newFakeReplica()
+		pinata.cid = dagPBCID(data)
+		client, err := NewReplicatedClient([]Replica{{"a", a}, {"pinata", pinata}}, 2, logging.NewNoOpLogger())
+		require.NoError(t, err)
+
+		cid, err := client.Upload(ctx, "task.json", data)
+		require.NoError(t, err)
+		assert.Equal(t, ComputeCID(data), cid)
+
+		// The replica is addressed by the CID it returned
+		a.err = fmt.Errorf("unavailable")
+		fetched, err := client.Fetch(ctx, cid)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), fetched.PerformerSignature.TaskID)
+
+		files, err := client.ListFiles(ctx)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, cid, files[0].CID)
+
+		require.NoError(t, client.Delete(ctx, cid))
+		assert.Empty(t, pinata.blobs)
+	})
+
+	t.Run("a replica storing other content does not count", func(t *testing.T) {
+		a, b := newFakeReplica(), newFakeReplica()
+		b.cid = dagPBCID([]byte("other"))
+		client, err := NewReplicatedClient([]Replica{{"a", a}, {"b", b}}, 2, logging.NewNoOpLogger())
+		require.NoError(t, err)
+
+		_, err = client.Upload(ctx, "task.json", data)
+		assert.ErrorContains(t, err, "stored content under CID "+b.cid)
+		assert.ErrorContains(t, err, "content does not match CID")
+	})
+}
+
+func TestReplicatedClient_FetchReadsFastest(t *testing.T) {
+	ctx := context.Background()
+	data := testIPFSData(t, 9)
+	cid := ComputeCID(data)
+
+	slow, fast := newFakeReplica(), newFakeReplica()
+	slow.blobs[cid], fast.blobs[cid] = data, data
+	slow.delay = time.Minute
+	slow.fetchDone = make(chan struct{})
+
+	client, err := NewReplicatedClient([]Replica{{"slow", slow}, {"fast", fast}}, 1, logging.NewNoOpLogger())
+	require.NoError(t, err)
+
+	start := time.Now()
+	fetched, err := client.Fetch(ctx, cid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), fetched.PerformerSignature.TaskID)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	// The slow read is cancelled once the fast one returned
+	select {
+	case <-slow.fetchDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow fetch was not cancelled")
+	}
+}
+
+func TestReplicatedClient_FetchFallsBack(t *testing.T) {
+	ctx := context.Background()
+	data := testIPFSData(t, 3)
+	cid := ComputeCID(data)
+
+	broken, healthy := newFakeReplica(), newFakeReplica()
+	broken.err = fmt.Errorf("gateway timeout")
+	healthy.blobs[cid] = data
+	client, err := NewReplicatedClient([]Replica{{"broken", broken}, {"healthy", healthy}}, 1, logging.NewNoOpLogger())
+	require.NoError(t, err)
+
+	_, err = client.Fetch(ctx, cid)
+	require.NoError(t, err)
+
+	healthy.err = fmt.Errorf("not found")
+	_, err = client.Fetch(ctx, cid)
+	assert.ErrorContains(t, err, "failed to fetch "+cid+" from every replica")
+	assert.ErrorContains(t, err, "broken: gateway timeout")
+}
+
+func TestReplicatedClient_FetchSkipsCorruptReplica(t *testing.T) {
+	data := testIPFSData(t, 4)
+	cid := ComputeCID(data)
+
+	corrupt, healthy := newFakeReplica(), newFakeReplica()
+	corrupt.blobs[cid] = testIPFSData(t, 5)
+	healthy.blobs[cid] = data
+	healthy.delay = 50 * time.Millisecond
+	client, err := NewReplicatedClient([]Replica{{"corrupt", corrupt}, {"healthy", healthy}}, 1, logging.NewNoOpLogger())
+	require.NoError(t, err)
+
+	fetched, err := client.Fetch(context.Background(), cid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), fetched.PerformerSignature.TaskID)
+}
+
+func TestReplicatedClient_DeleteListClose(t *testing.T) {
+	ctx := context.Background()
+	a, b := newFakeReplica(), newFakeReplica()
+	client, err := NewReplicatedClient([]Replica{{"a", a}, {"b", b}}, 2, logging.NewNoOpLogger())
+	require.NoError(t, err)
+
+	first, err := client.Upload(ctx, "1.json", testIPFSData(t, 1))
+	require.NoError(t, err)
+	_, err = b.Upload(ctx, "2.json", testIPFSData(t, 2))
+	require.NoError(t, err)
+
+	files, err := client.ListFiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "listings are merged by CID")
+
+	b.err = fmt.Errorf("unavailable")
+	assert.NoError(t, client.Delete(ctx, first), "deleting from one replica is enough")
+	assert.NotContains(t, a.blobs, first)
+
+	a.err = fmt.Errorf("unavailable")
+	assert.Error(t, client.Delete(ctx, first))
+	_, err = client.ListFiles(ctx)
+	assert.Error(t, err)
+
+	assert.Error(t, client.Close())
+	assert.True(t, a.closed)
+	assert.True(t, b.closed)
+}
@@ -142,6 +142,7 @@ func GenerateProof(ipfsData types.IPFSData, connState *tls.ConnectionState) (typ
 
 	// Create enhanced proof with additional TLS information
 	proofData := types.ProofData{
+		Version:              types.ProofVersionTLS,
 		TaskID:               ipfsData.TaskData.TaskID[0],
 		ProofOfTask:          proofHashStr,
 		CertificateHash:      certHashStr,
@@ -0,0 +1,266 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/cryptography"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// ReceiptBackend is the chain access needed to build receipt proofs, *ethclient.Client satisfies it
+type ReceiptBackend interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethtypes.Receipt, error)
+	BlockByHash(ctx context.Context, hash common.Hash) (*ethtypes.Block, error)
+	BlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*ethtypes.Receipt, error)
+}
+
+// HeaderSource supplies the canonical headers a verifier trusts, *ethclient.Client satisfies it
+type HeaderSource interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error)
+}
+
+// GenerateReceiptProof proves the performer's action by including the block header, the action
+// transaction and its receipt, with Merkle-Patricia proofs of both against the header's roots.
+// The proof of task binds the performer's action data to that receipt.
+func GenerateReceiptProof(ctx context.Context, backend ReceiptBackend, ipfsData types.IPFSData) (types.ProofData, error) {
+	if err := checkProofInputs(ipfsData); err != nil {
+		return types.ProofData{}, err
+	}
+	txHash := common.HexToHash(ipfsData.ActionData.ActionTxHash)
+
+	receipt, err := backend.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return types.ProofData{}, fmt.Errorf("failed to get action receipt: %w", err)
+	}
+	block, err := backend.BlockByHash(ctx, receipt.BlockHash)
+	if err != nil {
+		return types.ProofData{}, fmt.Errorf("failed to get block %s: %w", receipt.BlockHash.Hex(), err)
+	}
+	receipts, err := backend.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(receipt.BlockHash, false))
+	if err != nil {
+		return types.ProofData{}, fmt.Errorf("failed to get block receipts: %w", err)
+	}
+
+	header := block.Header()
+	txIndex := receipt.TransactionIndex
+	if int(txIndex) >= len(block.Transactions()) || block.Transactions()[txIndex].Hash() != txHash {
+		return types.ProofData{}, fmt.Errorf("transaction %s is not at index %d of block %s", txHash.Hex(), txIndex, block.Hash().Hex())
+	}
+
+	txValue, txProof, err := proveListItem(ethtypes.Transactions(block.Transactions()), int(txIndex), header.TxHash)
+	if err != nil {
+		return types.ProofData{}, fmt.Errorf("failed to prove transaction: %w", err)
+	}
+	receiptValue, receiptProof, err := proveListItem(ethtypes.Receipts(receipts), int(txIndex), header.ReceiptHash)
+	if err != nil {
+		return types.ProofData{}, fmt.Errorf("failed to prove receipt: %w", err)
+	}
+
+	encodedHeader, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return types.ProofData{}, fmt.Errorf("failed to encode block header: %w", err)
+	}
+
+	proofOfTask, err := ReceiptProofOfTask(ipfsData.ActionData, ipfsData.TaskData.SigningChainID(), block.Hash(), txHash, receiptValue)
+	if err != nil {
+		return types.ProofData{}, err
+	}
+
+	return types.ProofData{
+		Version:     types.ProofVersionReceipt,
+		TaskID:      ipfsData.TaskData.TaskID[0],
+		ProofOfTask: proofOfTask,
+		ReceiptProof: &types.ReceiptProof{
+			ChainID:          ipfsData.TaskData.TargetData[0].TargetChainID,
+			BlockNumber:      header.Number.Uint64(),
+			BlockHash:        block.Hash().Hex(),
+			Header:           hexutil.Encode(encodedHeader),
+			TransactionIndex: uint64(txIndex),
+			Transaction:      hexutil.Encode(txValue),
+			TransactionProof: encodeProofNodes(txProof),
+			Receipt:          hexutil.Encode(receiptValue),
+			ReceiptProof:     encodeProofNodes(receiptProof),
+		},
+	}, nil
+}
+
+// VerifyReceiptProof checks a receipt proof against the canonical header from headers and
+// that it proves the action transaction and status the performer reported
+func VerifyReceiptProof(ctx context.Context, headers HeaderSource, ipfsData types.IPFSData) error {
+	if err := checkProofInputs(ipfsData); err != nil {
+		return err
+	}
+	if ipfsData.ProofData == nil || ipfsData.ProofData.ReceiptProof == nil {
+		return errors.New("receipt proof is missing")
+	}
+	receiptProof := ipfsData.ProofData.ReceiptProof
+
+	if receiptProof.ChainID != ipfsData.TaskData.TargetData[0].TargetChainID {
+		return fmt.Errorf("proof is for chain %s, task targets chain %s", receiptProof.ChainID, ipfsData.TaskData.TargetData[0].TargetChainID)
+	}
+
+	encodedHeader, err := hexutil.Decode(receiptProof.Header)
+	if err != nil {
+		return fmt.Errorf("invalid block header encoding: %w", err)
+	}
+	var header ethtypes.Header
+	if err := rlp.DecodeBytes(encodedHeader, &header); err != nil {
+		return fmt.Errorf("failed to decode block header: %w", err)
+	}
+	if header.Hash() != common.HexToHash(receiptProof.BlockHash) {
+		return fmt.Errorf("block header hashes to %s, proof claims %s", header.Hash().Hex(), receiptProof.BlockHash)
+	}
+	if header.Number == nil || header.Number.Uint64() != receiptProof.BlockNumber {
+		return fmt.Errorf("block header is not block %d", receiptProof.BlockNumber)
+	}
+
+	trusted, err := headers.HeaderByNumber(ctx, new(big.Int).SetUint64(receiptProof.BlockNumber))
+	if err != nil {
+		return fmt.Errorf("failed to get trusted header %d: %w", receiptProof.BlockNumber, err)
+	}
+	if trusted.Hash() != header.Hash() {
+		return fmt.Errorf("block %s is not canonical, trusted header is %s", header.Hash().Hex(), trusted.Hash().Hex())
+	}
+
+	txValue, err := verifyListItem(header.TxHash, receiptProof.TransactionIndex, receiptProof.Transaction, receiptProof.TransactionProof)
+	if err != nil {
+		return fmt.Errorf("transaction proof: %w", err)
+	}
+	receiptValue, err := verifyListItem(header.ReceiptHash, receiptProof.TransactionIndex, receiptProof.Receipt, receiptProof.ReceiptProof)
+	if err != nil {
+		return fmt.Errorf("receipt proof: %w", err)
+	}
+
+	var tx ethtypes.Transaction
+	if err := tx.UnmarshalBinary(txValue); err != nil {
+		return fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	txHash := common.HexToHash(ipfsData.ActionData.ActionTxHash)
+	if tx.Hash() != txHash {
+		return fmt.Errorf("proven transaction %s is not the action transaction %s", tx.Hash().Hex(), txHash.Hex())
+	}
+
+	var receipt ethtypes.Receipt
+	if err := receipt.UnmarshalBinary(receiptValue); err != nil {
+		return fmt.Errorf("failed to decode receipt: %w", err)
+	}
+	if (receipt.Status == ethtypes.ReceiptStatusSuccessful) != ipfsData.ActionData.Status {
+		return fmt.Errorf("receipt status %d does not match reported status %v", receipt.Status, ipfsData.ActionData.Status)
+	}
+
+	proofOfTask, err := ReceiptProofOfTask(ipfsData.ActionData, ipfsData.TaskData.SigningChainID(), header.Hash(), txHash, receiptValue)
+	if err != nil {
+		return err
+	}
+	if proofOfTask != ipfsData.ProofData.ProofOfTask {
+		return fmt.Errorf("proof of task mismatch: expected %s, got %s", proofOfTask, ipfsData.ProofData.ProofOfTask)
+	}
+	return nil
+}
+
+// ReceiptProofOfTask is keccak256(actionDataHash || blockHash || txHash || keccak256(receipt)) as hex,
+// where actionDataHash is the EIP-712 hash of the performer's action data on the target chain
+func ReceiptProofOfTask(actionData *types.PerformerActionData, chainID int64, blockHash common.Hash, txHash common.Hash, receipt []byte) (string, error) {
+	actionDataHash, err := cryptography.HashTypedData(actionData, cryptography.NewTypedDataDomain(chainID))
+	if err != nil {
+		return "", fmt.Errorf("failed to hash action data: %w", err)
+	}
+	proofHash := crypto.Keccak256(actionDataHash, blockHash.Bytes(), txHash.Bytes(), crypto.Keccak256(receipt))
+	return strings.TrimPrefix(hexutil.Encode(proofHash), "0x"), nil
+}
+
+func checkProofInputs(ipfsData types.IPFSData) error {
+	if ipfsData.TaskData == nil || len(ipfsData.TaskData.TaskID) == 0 || len(ipfsData.TaskData.TargetData) == 0 {
+		return errors.New("task data is missing")
+	}
+	if ipfsData.ActionData == nil || ipfsData.ActionData.ActionTxHash == "" {
+		return errors.New("action transaction hash is missing")
+	}
+	return nil
+}
+
+// proveListItem rebuilds the trie of a block's transactions or receipts, checks it against the
+// header root and returns the encoded item at index with its proof
+func proveListItem(list ethtypes.DerivableList, index int, root common.Hash) ([]byte, trienode.ProofList, error) {
+	listTrie := trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	var (
+		buf   bytes.Buffer
+		value []byte
+	)
+	for i := 0; i < list.Len(); i++ {
+		buf.Reset()
+		list.EncodeIndex(i, &buf)
+		encoded := common.CopyBytes(buf.Bytes())
+		if err := listTrie.Update(listKey(uint64(i)), encoded); err != nil {
+			return nil, nil, fmt.Errorf("failed to build trie: %w", err)
+		}
+		if i == index {
+			value = encoded
+		}
+	}
+	if listTrie.Hash() != root {
+		return nil, nil, fmt.Errorf("rebuilt trie root %s does not match header root %s", listTrie.Hash().Hex(), root.Hex())
+	}
+
+	var proof trienode.ProofList
+	if err := listTrie.Prove(listKey(uint64(index)), &proof); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate proof: %w", err)
+	}
+	return value, proof, nil
+}
+
+// verifyListItem checks that the hex encoded value is the item at index under root
+func verifyListItem(root common.Hash, index uint64, value string, nodes []string) ([]byte, error) {
+	encoded, err := hexutil.Decode(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value encoding: %w", err)
+	}
+
+	proof := make(trienode.ProofList, 0, len(nodes))
+	for i, node := range nodes {
+		decoded, err := hexutil.Decode(node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encoding of node %d: %w", i, err)
+		}
+		proof = append(proof, decoded)
+	}
+
+	proven, err := trie.VerifyProof(root, listKey(index), proof.Set())
+	if err != nil {
+		return nil, fmt.Errorf("invalid proof: %w", err)
+	}
+	if !bytes.Equal(proven, encoded) {
+		return nil, errors.New("proven value does not match")
+	}
+	return encoded, nil
+}
+
+// listKey is the trie key of the item at index, its RLP encoding
+func listKey(index uint64) []byte {
+	key, _ := rlp.EncodeToBytes(index)
+	return key
+}
+
+func encodeProofNodes(proof trienode.ProofList) []string {
+	nodes := make([]string, len(proof))
+	for i, node := range proof {
+		nodes[i] = hexutil.Encode(node)
+	}
+	return nodes
+}
@@ -0,0 +1,241 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// fakeChain serves a single block as both the prover's backend and the verifier's header source
+type fakeChain struct {
+	block    *ethtypes.Block
+	receipts []*ethtypes.Receipt
+	headers  map[uint64]*ethtypes.Header
+}
+
+func (c *fakeChain) TransactionReceipt(_ context.Context, txHash common.Hash) (*ethtypes.Receipt, error) {
+	for _, receipt := range c.receipts {
+		if receipt.TxHash == txHash {
+			return receipt, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (c *fakeChain) BlockByHash(_ context.Context, hash common.Hash) (*ethtypes.Block, error) {
+	if hash != c.block.Hash() {
+		return nil, errors.New("not found")
+	}
+	return c.block, nil
+}
+
+func (c *fakeChain) BlockReceipts(_ context.Context, _ rpc.BlockNumberOrHash) ([]*ethtypes.Receipt, error) {
+	return c.receipts, nil
+}
+
+func (c *fakeChain) HeaderByNumber(_ context.Context, number *big.Int) (*ethtypes.Header, error) {
+	header, ok := c.headers[number.Uint64()]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return header, nil
+}
+
+func newFakeChain(t *testing.T, txCount int) *fakeChain {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := ethtypes.LatestSignerForChainID(big.NewInt(84532))
+	to := common.HexToAddress("0x2469e89386947535A350EEBccC5F2754fd35F474")
+
+	var (
+		txs      []*ethtypes.Transaction
+		receipts []*ethtypes.Receipt
+	)
+	for i := 0; i < txCount; i++ {
+		tx, err := ethtypes.SignNewTx(key, signer, &ethtypes.DynamicFeeTx{
+			ChainID:   big.NewInt(84532),
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(2),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(int64(i)),
+		})
+		require.NoError(t, err)
+		txs = append(txs, tx)
+
+		status := ethtypes.ReceiptStatusSuccessful
+		if i%3 == 2 {
+			status = ethtypes.ReceiptStatusFailed
+		}
+		receipts = append(receipts, &ethtypes.Receipt{
+			Type:              ethtypes.DynamicFeeTxType,
+			Status:            status,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Logs:              []*ethtypes.Log{},
+			TxHash:            tx.Hash(),
+			TransactionIndex:  uint(i),
+		})
+	}
+
+	header := &ethtypes.Header{
+		Number:   big.NewInt(1000),
+		GasLimit: 30_000_000,
+		Time:     1_700_000_000,
+		BaseFee:  big.NewInt(1),
+	}
+	block := ethtypes.NewBlock(header, &ethtypes.Body{Transactions: txs}, receipts, trie.NewStackTrie(nil))
+	for _, receipt := range receipts {
+		receipt.BlockHash = block.Hash()
+		receipt.BlockNumber = block.Number()
+	}
+
+	return &fakeChain{
+		block:    block,
+		receipts: receipts,
+		headers:  map[uint64]*ethtypes.Header{1000: block.Header()},
+	}
+}
+
+func newReceiptIPFSData(chain *fakeChain, txIndex int) types.IPFSData {
+	return types.IPFSData{
+		TaskData: &types.SendTaskDataToKeeper{
+			TaskID:     []int64{7},
+			TargetData: []types.TaskTargetData{{TaskID: 7, TargetChainID: "84532"}},
+		},
+		ActionData: &types.PerformerActionData{
+			TaskID:             7,
+			ActionTxHash:       chain.block.Transactions()[txIndex].Hash().Hex(),
+			GasUsed:            "21000",
+			Status:             chain.receipts[txIndex].Status == ethtypes.ReceiptStatusSuccessful,
+			TotalFee:           big.NewInt(100),
+			ExecutionTimestamp: time.Unix(1_700_000_000, 0).UTC(),
+		},
+	}
+}
+
+func TestReceiptProof_RoundTrip(t *testing.T) {
+	chain := newFakeChain(t, 20)
+
+	for _, txIndex := range []int{0, 2, 19} {
+		ipfsData := newReceiptIPFSData(chain, txIndex)
+
+		proofData, err := GenerateReceiptProof(context.Background(), chain, ipfsData)
+		require.NoError(t, err)
+
+		assert.Equal(t, types.ProofVersionReceipt, proofData.ProofVersion())
+		assert.Equal(t, int64(7), proofData.TaskID)
+		assert.Len(t, proofData.ProofOfTask, 64)
+		require.NotNil(t, proofData.ReceiptProof)
+		assert.Equal(t, uint64(txIndex), proofData.ReceiptProof.TransactionIndex)
+		assert.Equal(t, chain.block.Hash().Hex(), proofData.ReceiptProof.BlockHash)
+
+		ipfsData.ProofData = &proofData
+		assert.NoError(t, VerifyReceiptProof(context.Background(), chain, ipfsData), "tx %d", txIndex)
+	}
+}
+
+func TestVerifyReceiptProof_RejectsTampering(t *testing.T) {
+	chain := newFakeChain(t, 5)
+
+	newProven := func(t *testing.T) types.IPFSData {
+		ipfsData := newReceiptIPFSData(chain, 1)
+		proofData, err := GenerateReceiptProof(context.Background(), chain, ipfsData)
+		require.NoError(t, err)
+		ipfsData.ProofData = &proofData
+		return ipfsData
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(ipfsData *types.IPFSData)
+		headers HeaderSource
+		errMsg  string
+	}{
+		{
+			name: "reported status differs from receipt",
+			tamper: func(ipfsData *types.IPFSData) {
+				ipfsData.ActionData.Status = false
+			},
+			errMsg: "does not match reported status",
+		},
+		{
+			name: "proof of another transaction",
+			tamper: func(ipfsData *types.IPFSData) {
+				ipfsData.ActionData.ActionTxHash = chain.block.Transactions()[0].Hash().Hex()
+			},
+			errMsg: "is not the action transaction",
+		},
+		{
+			name: "action data changed after proving",
+			tamper: func(ipfsData *types.IPFSData) {
+				ipfsData.ActionData.TotalFee = big.NewInt(1)
+			},
+			errMsg: "proof of task mismatch",
+		},
+		{
+			name: "forged receipt",
+			tamper: func(ipfsData *types.IPFSData) {
+				ipfsData.ProofData.ReceiptProof.Receipt = ipfsData.ProofData.ReceiptProof.Receipt[:len(ipfsData.ProofData.ReceiptProof.Receipt)-2] + "00"
+			},
+			errMsg: "receipt proof",
+		},
+		{
+			name: "missing proof nodes",
+			tamper: func(ipfsData *types.IPFSData) {
+				ipfsData.ProofData.ReceiptProof.TransactionProof = ipfsData.ProofData.ReceiptProof.TransactionProof[:1]
+			},
+			errMsg: "transaction proof",
+		},
+		{
+			name: "other chain",
+			tamper: func(ipfsData *types.IPFSData) {
+				ipfsData.TaskData.TargetData[0].TargetChainID = "1"
+			},
+			errMsg: "proof is for chain",
+		},
+		{
+			name:    "block not canonical",
+			headers: &fakeChain{headers: map[uint64]*ethtypes.Header{1000: {Number: big.NewInt(1000)}}},
+			errMsg:  "is not canonical",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ipfsData := newProven(t)
+			if tt.tamper != nil {
+				tt.tamper(&ipfsData)
+			}
+			headers := tt.headers
+			if headers == nil {
+				headers = chain
+			}
+
+			err := VerifyReceiptProof(context.Background(), headers, ipfsData)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestGenerateReceiptProof_MissingAction(t *testing.T) {
+	chain := newFakeChain(t, 1)
+	ipfsData := newReceiptIPFSData(chain, 0)
+	ipfsData.ActionData.ActionTxHash = ""
+
+	_, err := GenerateReceiptProof(context.Background(), chain, ipfsData)
+	assert.ErrorContains(t, err, "action transaction hash is missing")
+}
@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceToken_SignAndVerify(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer := NewServiceTokenSigner(private)
+	verifier := NewServiceTokenVerifier("task-dispatcher", public)
+
+	token, err := signer.Sign("time-scheduler", "task-dispatcher", []string{"SubmitTask"}, time.Minute)
+	require.NoError(t, err)
+
+	claims, err := verifier.Verify("Bearer " + token)
+	require.NoError(t, err)
+	assert.Equal(t, "time-scheduler", claims.Service)
+	assert.True(t, claims.AllowsMethod("SubmitTask"))
+	assert.False(t, claims.AllowsMethod("CancelTask"))
+
+	expired, err := signer.Sign("time-scheduler", "task-dispatcher", []string{"*"}, -time.Minute)
+	require.NoError(t, err)
+	_, err = verifier.Verify(expired)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+
+	_, err = signer.Sign("", "task-dispatcher", nil, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestServiceToken_RejectsOtherIssuersAndAlgorithms(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	verifier := NewServiceTokenVerifier("task-dispatcher", public)
+
+	claims := &ServiceClaims{
+		Service: "time-scheduler",
+		Methods: []string{"*"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "someone-else",
+			Audience:  []string{"task-dispatcher"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(private)
+	require.NoError(t, err)
+	_, err = verifier.Verify(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+
+	claims.Issuer = ServiceTokenIssuer
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = verifier.Verify(hmacToken)
+	assert.Error(t, err)
+}
+
+func TestParseServiceTokenKeys(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	key, err := ParseServiceTokenKey(hex.EncodeToString(private.Seed()))
+	require.NoError(t, err)
+	assert.Equal(t, private, key)
+	key, err = ParseServiceTokenKey("0x" + hex.EncodeToString(private))
+	require.NoError(t, err)
+	assert.Equal(t, private, key)
+	_, err = ParseServiceTokenKey("abcd")
+	assert.Error(t, err)
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys, err := ParseServiceTokenPublicKeys(hex.EncodeToString(public) + ", " + hex.EncodeToString(other) + ",")
+	require.NoError(t, err)
+	assert.Equal(t, []ed25519.PublicKey{public, other}, keys)
+	_, err = ParseServiceTokenPublicKeys("zz")
+	assert.Error(t, err)
+}
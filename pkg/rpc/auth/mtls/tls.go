@@ -0,0 +1,138 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Config locates a service's certificate, its key and the CA certificate peers are verified
+// against. Files take precedence over the in-memory PEM fields.
+type Config struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+
+	CertPEM []byte
+	KeyPEM  []byte
+	CAPEM   []byte
+
+	// ServerName overrides the name a client verifies the server certificate against,
+	// defaulting to the host dialled
+	ServerName string
+}
+
+// Enabled reports whether a certificate is configured
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || len(c.CertPEM) > 0
+}
+
+// ServerTLSConfig returns a TLS configuration requiring and verifying client certificates
+func ServerTLSConfig(cfg Config) (*tls.Config, error) {
+	cert, pool, err := cfg.load()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientTLSConfig returns a TLS configuration presenting the client certificate and
+// verifying the server against the CA
+func ClientTLSConfig(cfg Config) (*tls.Config, error) {
+	cert, pool, err := cfg.load()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ServerName:   cfg.ServerName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ServerCredentials returns gRPC transport credentials for a server
+func ServerCredentials(cfg Config) (credentials.TransportCredentials, error) {
+	tlsConfig, err := ServerTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// ClientCredentials returns gRPC transport credentials for a client
+func ClientCredentials(cfg Config) (credentials.TransportCredentials, error) {
+	tlsConfig, err := ClientTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// PeerService returns the service name of the caller's verified client certificate
+func PeerService(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", false
+	}
+	return ConnectionService(&tlsInfo.State)
+}
+
+// ConnectionService returns the service name of the verified client certificate of a TLS
+// connection, such as an HTTP request's
+func ConnectionService(state *tls.ConnectionState) (string, bool) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	name := state.VerifiedChains[0][0].Subject.CommonName
+	return name, name != ""
+}
+
+func (c Config) load() (tls.Certificate, *x509.CertPool, error) {
+	certPEM, err := readPEM(c.CertFile, c.CertPEM)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+	keyPEM, err := readPEM(c.KeyFile, c.KeyPEM)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	caPEM, err := readPEM(c.CAFile, c.CAPEM)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to load key pair: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return tls.Certificate{}, nil, fmt.Errorf("no CA certificate found")
+	}
+	return cert, pool, nil
+}
+
+func readPEM(file string, data []byte) ([]byte, error) {
+	if file != "" {
+		return os.ReadFile(file)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("not configured")
+	}
+	return data, nil
+}
@@ -0,0 +1,394 @@
+package client
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+// Balancing policies picking the endpoint of a call
+const (
+	// RoundRobin takes the endpoints in turn
+	RoundRobin = "round_robin"
+	// LeastRequest takes the endpoint with the fewest calls in flight
+	LeastRequest = "least_request"
+	// PowerOfTwo takes the less loaded of two random endpoints
+	PowerOfTwo = "power_of_two"
+)
+
+// errNoEndpoint is returned when every endpoint is excluded by its circuit breaker
+var errNoEndpoint = errors.New("no endpoint available")
+
+// OutlierConfig configures the ejection of endpoints whose error rate or latency stands
+// out, evaluated over intervals of calls
+type OutlierConfig struct {
+	// Interval between evaluations, defaults to 10 seconds
+	Interval time.Duration
+	// MinRequests an endpoint must serve in an interval to be evaluated, defaults to 10
+	MinRequests int
+	// MaxErrorRate ejects endpoints failing a larger share of calls, defaults to 0.5
+	MaxErrorRate float64
+	// LatencyFactor ejects endpoints slower than this multiple of the median endpoint
+	// latency, defaults to 3. Needs at least three evaluated endpoints.
+	LatencyFactor float64
+	// EjectionTime is how long an endpoint is ejected, multiplied by the number of
+	// intervals in a row it was ejected for, defaults to 30 seconds
+	EjectionTime time.Duration
+	// MaxEjectedPercent bounds the share of endpoints ejected at once, defaults to 50
+	MaxEjectedPercent int
+}
+
+func (c OutlierConfig) withDefaults() OutlierConfig {
+	if c.Interval <= 0 {
+		c.Interval = 10 * time.Second
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = 10
+	}
+	if c.MaxErrorRate <= 0 {
+		c.MaxErrorRate = 0.5
+	}
+	if c.LatencyFactor <= 0 {
+		c.LatencyFactor = 3
+	}
+	if c.EjectionTime <= 0 {
+		c.EjectionTime = 30 * time.Second
+	}
+	if c.MaxEjectedPercent <= 0 {
+		c.MaxEjectedPercent = 50
+	}
+	return c
+}
+
+// maxEjectionMultiplier caps how much the ejection time grows for a repeat outlier
+const maxEjectionMultiplier = 10
+
+// latencyWeight is the weight of a new sample in an endpoint's latency average
+const latencyWeight = 0.2
+
+// EndpointStats describes an endpoint of the service
+type EndpointStats struct {
+	Address  string
+	Breaker  string
+	Ejected  bool
+	InFlight int
+	Requests int64
+	Errors   int64
+	// Latency is the moving average latency of successful calls
+	Latency time.Duration
+}
+
+// endpoint is an instance of the service with its own connection pool
+type endpoint struct {
+	address string
+	pool    *ConnectionPool
+	breaker *circuitBreaker
+
+	inFlight      int
+	totalRequests int64
+	totalErrors   int64
+	latency       time.Duration
+	ejectedUntil  time.Time
+	ejections     int
+
+	// Counters of the current outlier interval
+	requests   int
+	errors     int
+	successes  int
+	latencySum time.Duration
+}
+
+// lease is a call let through to an endpoint
+type lease struct {
+	endpoint   *endpoint
+	generation uint64
+}
+
+// balancer spreads calls over the endpoints of a service
+type balancer struct {
+	policy  string
+	breaker CircuitBreakerConfig
+	outlier OutlierConfig
+	newPool func() *ConnectionPool
+	logger  logging.Logger
+
+	mu          sync.Mutex
+	endpoints   []*endpoint
+	next        uint64
+	evaluatedAt time.Time
+}
+
+func newBalancer(policy string, breaker CircuitBreakerConfig, outlier OutlierConfig, newPool func() *ConnectionPool, logger logging.Logger) *balancer {
+	switch policy {
+	case RoundRobin, LeastRequest, PowerOfTwo:
+	default:
+		policy = RoundRobin
+	}
+	return &balancer{
+		policy:  policy,
+		breaker: breaker.withDefaults(),
+		outlier: outlier.withDefaults(),
+		newPool: newPool,
+		logger:  logger,
+	}
+}
+
+// update sets the endpoint addresses, keeping the state and connections of the endpoints
+// that remain so a discovery update does not reconnect to every instance
+func (b *balancer) update(addresses []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := make(map[string]*endpoint, len(b.endpoints))
+	for _, ep := range b.endpoints {
+		current[ep.address] = ep
+	}
+
+	endpoints := make([]*endpoint, 0, len(addresses))
+	for _, address := range addresses {
+		if ep, ok := current[address]; ok {
+			endpoints = append(endpoints, ep)
+			delete(current, address)
+			continue
+		}
+		endpoints = append(endpoints, &endpoint{
+			address: address,
+			pool:    b.newPool(),
+			breaker: newCircuitBreaker(b.breaker),
+		})
+		b.logger.Debug("Added endpoint", "address", address)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].address < endpoints[j].address })
+	b.endpoints = endpoints
+
+	// Calls in flight on removed endpoints close their connection when they finish
+	for _, ep := range current {
+		if err := ep.pool.Close(); err != nil {
+			b.logger.Errorf("Failed to close connection pool of %s: %v", ep.address, err)
+		}
+		b.logger.Debug("Removed endpoint", "address", ep.address)
+	}
+}
+
+// size returns the number of endpoints
+func (b *balancer) size() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.endpoints)
+}
+
+// pick leases an endpoint for a call, preferring endpoints not excluded and not ejected
+// but falling back to them rather than failing while their breaker lets calls through
+func (b *balancer) pick(excluded func(address string) bool) (*lease, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.evaluate(now)
+
+	filters := []func(ep *endpoint) bool{
+		func(ep *endpoint) bool { return !excluded(ep.address) && !ep.ejected(now) },
+		func(ep *endpoint) bool { return !ep.ejected(now) },
+		func(ep *endpoint) bool { return true },
+	}
+	for _, filter := range filters {
+		var candidates []*endpoint
+		for _, ep := range b.endpoints {
+			if filter(ep) && ep.breaker.available(now) {
+				candidates = append(candidates, ep)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		ep := b.choose(candidates)
+		_, generation := ep.breaker.acquire(now)
+		ep.inFlight++
+		return &lease{endpoint: ep, generation: generation}, nil
+	}
+	return nil, errNoEndpoint
+}
+
+// choose applies the balancing policy to the candidate endpoints
+func (b *balancer) choose(candidates []*endpoint) *endpoint {
+	offset := int(b.next % uint64(len(candidates)))
+	b.next++
+
+	switch b.policy {
+	case LeastRequest:
+		best := candidates[offset]
+		for i := 1; i < len(candidates); i++ {
+			ep := candidates[(offset+i)%len(candidates)]
+			if ep.inFlight < best.inFlight {
+				best = ep
+			}
+		}
+		return best
+	case PowerOfTwo:
+		if len(candidates) == 1 {
+			return candidates[0]
+		}
+		i := rand.IntN(len(candidates))
+		j := rand.IntN(len(candidates) - 1)
+		if j >= i {
+			j++
+		}
+		first, second := candidates[i], candidates[j]
+		if second.inFlight < first.inFlight || (second.inFlight == first.inFlight && second.latency < first.latency) {
+			return second
+		}
+		return first
+	default:
+		return candidates[offset]
+	}
+}
+
+// release records the outcome of a leased call, latency is ignored when negative
+func (b *balancer) release(l *lease, latency time.Duration, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ep := l.endpoint
+	ep.inFlight--
+	ep.breaker.record(l.generation, failed, time.Now())
+
+	ep.requests++
+	ep.totalRequests++
+	if failed {
+		ep.errors++
+		ep.totalErrors++
+		return
+	}
+	if latency < 0 {
+		return
+	}
+	ep.successes++
+	ep.latencySum += latency
+	if ep.latency == 0 {
+		ep.latency = latency
+	} else {
+		ep.latency += time.Duration(latencyWeight * float64(latency-ep.latency))
+	}
+}
+
+// evaluate ejects the outliers of the interval that ended, if one did
+func (b *balancer) evaluate(now time.Time) {
+	if now.Sub(b.evaluatedAt) < b.outlier.Interval {
+		return
+	}
+	b.evaluatedAt = now
+
+	var latencies []time.Duration
+	for _, ep := range b.endpoints {
+		if ep.requests >= b.outlier.MinRequests && ep.successes > 0 {
+			latencies = append(latencies, ep.latencySum/time.Duration(ep.successes))
+		}
+	}
+	var median time.Duration
+	if len(latencies) >= 3 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		median = latencies[len(latencies)/2]
+	}
+
+	ejected := 0
+	for _, ep := range b.endpoints {
+		if ep.ejected(now) {
+			ejected++
+		}
+	}
+	maxEjected := len(b.endpoints) * b.outlier.MaxEjectedPercent / 100
+
+	for _, ep := range b.endpoints {
+		requests, failures, successes, latencySum := ep.requests, ep.errors, ep.successes, ep.latencySum
+		ep.requests, ep.errors, ep.successes, ep.latencySum = 0, 0, 0, 0
+		if requests < b.outlier.MinRequests || ep.ejected(now) {
+			continue
+		}
+
+		errorRate := float64(failures) / float64(requests)
+		slow := median > 0 && successes > 0 &&
+			float64(latencySum/time.Duration(successes)) > b.outlier.LatencyFactor*float64(median)
+		if errorRate <= b.outlier.MaxErrorRate && !slow {
+			ep.ejections = 0
+			continue
+		}
+		if ejected >= maxEjected {
+			continue
+		}
+
+		ep.ejections = min(ep.ejections+1, maxEjectionMultiplier)
+		ep.ejectedUntil = now.Add(b.outlier.EjectionTime * time.Duration(ep.ejections))
+		ejected++
+		b.logger.Warn("Ejected outlier endpoint",
+			"address", ep.address,
+			"error_rate", errorRate,
+			"slow", slow,
+			"until", ep.ejectedUntil)
+	}
+}
+
+// stats describes the endpoints
+func (b *balancer) stats() []EndpointStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]EndpointStats, 0, len(b.endpoints))
+	for _, ep := range b.endpoints {
+		stats = append(stats, EndpointStats{
+			Address:  ep.address,
+			Breaker:  ep.breaker.state,
+			Ejected:  ep.ejected(now),
+			InFlight: ep.inFlight,
+			Requests: ep.totalRequests,
+			Errors:   ep.totalErrors,
+			Latency:  ep.latency,
+		})
+	}
+	return stats
+}
+
+// close closes the connection pools of every endpoint
+func (b *balancer) close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var errs []error
+	for _, ep := range b.endpoints {
+		if err := ep.pool.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	b.endpoints = nil
+	return errors.Join(errs...)
+}
+
+func (ep *endpoint) ejected(now time.Time) bool {
+	return now.Before(ep.ejectedUntil)
+}
+
+// isEndpointFailure reports whether a call failed because of the endpoint rather than the
+// request, counting against its breaker and outlier statistics
+func isEndpointFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch s.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal:
+		return true
+	default:
+		return false
+	}
+}
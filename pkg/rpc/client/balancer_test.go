@@ -0,0 +1,192 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+)
+
+func newTestBalancer(policy string, outlier OutlierConfig, addresses ...string) *balancer {
+	logger := logging.NewNoOpLogger()
+	b := newBalancer(policy, CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Hour}, outlier, func() *ConnectionPool {
+		return NewConnectionPool(1, time.Second, logger)
+	}, logger)
+	b.update(addresses)
+	return b
+}
+
+func noneExcluded(string) bool { return false }
+
+func pickAddress(t *testing.T, b *balancer) (*lease, string) {
+	t.Helper()
+	l, err := b.pick(noneExcluded)
+	require.NoError(t, err)
+	return l, l.endpoint.address
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}.withDefaults())
+
+	_, closed := breaker.acquire(now)
+	breaker.record(closed, true, now)
+	assert.Equal(t, BreakerClosed, breaker.state)
+	breaker.record(closed, true, now)
+	assert.Equal(t, BreakerOpen, breaker.state)
+	assert.False(t, breaker.available(now))
+
+	// A call let through before the breaker opened does not count any more
+	breaker.record(closed, false, now)
+	assert.Equal(t, BreakerOpen, breaker.state)
+
+	// After the timeout a single probe is let through
+	later := now.Add(time.Minute)
+	allowed, generation := breaker.acquire(later)
+	require.True(t, allowed)
+	assert.Equal(t, BreakerHalfOpen, breaker.state)
+	allowed, _ = breaker.acquire(later)
+	assert.False(t, allowed)
+
+	breaker.record(generation, true, later)
+	assert.Equal(t, BreakerOpen, breaker.state)
+
+	allowed, generation = breaker.acquire(later.Add(time.Minute))
+	require.True(t, allowed)
+	breaker.record(generation, false, later.Add(time.Minute))
+	assert.Equal(t, BreakerClosed, breaker.state)
+}
+
+func TestBalancer_RoundRobin(t *testing.T) {
+	b := newTestBalancer(RoundRobin, OutlierConfig{}, "c", "a", "b")
+
+	var picked []string
+	for i := 0; i < 6; i++ {
+		l, address := pickAddress(t, b)
+		b.release(l, time.Millisecond, false)
+		picked = append(picked, address)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, picked)
+}
+
+func TestBalancer_LeastRequest(t *testing.T) {
+	b := newTestBalancer(LeastRequest, OutlierConfig{}, "a", "b", "c")
+
+	held := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		_, address := pickAddress(t, b)
+		held[address] = true
+	}
+	// Every endpoint has one call in flight before any gets a second
+	assert.Len(t, held, 3)
+}
+
+func TestBalancer_PowerOfTwo(t *testing.T) {
+	b := newTestBalancer(PowerOfTwo, OutlierConfig{}, "a", "b")
+
+	busy, busyAddress := pickAddress(t, b)
+	for i := 0; i < 10; i++ {
+		l, address := pickAddress(t, b)
+		assert.NotEqual(t, busyAddress, address)
+		b.release(l, time.Millisecond, false)
+	}
+	b.release(busy, time.Millisecond, false)
+}
+
+func TestBalancer_BreakerSkipsEndpoint(t *testing.T) {
+	b := newTestBalancer(RoundRobin, OutlierConfig{}, "a", "b")
+
+	for i := 0; i < 4; i++ {
+		l, address := pickAddress(t, b)
+		b.release(l, -1, address == "a")
+	}
+	for i := 0; i < 4; i++ {
+		l, address := pickAddress(t, b)
+		assert.Equal(t, "b", address)
+		b.release(l, time.Millisecond, false)
+	}
+
+	// Once every breaker is open there is nothing left to pick
+	for i := 0; i < 2; i++ {
+		l, _ := pickAddress(t, b)
+		b.release(l, -1, true)
+	}
+	_, err := b.pick(noneExcluded)
+	assert.ErrorIs(t, err, errNoEndpoint)
+}
+
+func TestBalancer_PrefersEndpointsNotTried(t *testing.T) {
+	b := newTestBalancer(RoundRobin, OutlierConfig{}, "a", "b")
+
+	for i := 0; i < 3; i++ {
+		l, err := b.pick(func(address string) bool { return address == "a" })
+		require.NoError(t, err)
+		assert.Equal(t, "b", l.endpoint.address)
+		b.release(l, time.Millisecond, false)
+	}
+
+	// Excluding every endpoint falls back to them
+	l, err := b.pick(func(string) bool { return true })
+	require.NoError(t, err)
+	b.release(l, time.Millisecond, false)
+}
+
+func TestBalancer_EjectsOutliers(t *testing.T) {
+	b := newTestBalancer(RoundRobin, OutlierConfig{Interval: time.Hour, MinRequests: 4, EjectionTime: time.Hour}, "a", "b", "c", "d")
+	b.breaker.FailureThreshold = 100
+	for _, ep := range b.endpoints {
+		ep.breaker = newCircuitBreaker(b.breaker)
+	}
+
+	latencies := map[string]time.Duration{"a": time.Millisecond, "b": time.Millisecond, "c": 50 * time.Millisecond, "d": time.Millisecond}
+	for i := 0; i < 16; i++ {
+		l, address := pickAddress(t, b)
+		b.release(l, latencies[address], address == "d")
+	}
+
+	b.mu.Lock()
+	b.evaluatedAt = time.Time{}
+	b.mu.Unlock()
+	l, _ := pickAddress(t, b)
+	b.release(l, time.Millisecond, false)
+
+	ejected := map[string]bool{}
+	for _, stats := range b.stats() {
+		ejected[stats.Address] = stats.Ejected
+	}
+	// The slow and the failing endpoint are ejected, at most half of them
+	assert.Equal(t, map[string]bool{"a": false, "b": false, "c": true, "d": true}, ejected)
+
+	for i := 0; i < 4; i++ {
+		l, address := pickAddress(t, b)
+		assert.Contains(t, []string{"a", "b"}, address)
+		b.release(l, time.Millisecond, false)
+	}
+}
+
+func TestBalancer_UpdateKeepsEndpoints(t *testing.T) {
+	b := newTestBalancer(RoundRobin, OutlierConfig{}, "a", "b")
+	kept := b.endpoints[1]
+	removed := b.endpoints[0]
+
+	b.update([]string{"b", "c"})
+	require.Len(t, b.endpoints, 2)
+	assert.Same(t, kept, b.endpoints[0])
+	assert.Equal(t, "c", b.endpoints[1].address)
+
+	_, err := removed.pool.GetConnection(t.Context(), "a")
+	assert.Error(t, err)
+}
+
+func TestIsEndpointFailure(t *testing.T) {
+	assert.False(t, isEndpointFailure(nil))
+	assert.True(t, isEndpointFailure(status.Error(codes.Unavailable, "down")))
+	assert.True(t, isEndpointFailure(status.Error(codes.DeadlineExceeded, "slow")))
+	assert.False(t, isEndpointFailure(status.Error(codes.InvalidArgument, "bad request")))
+	assert.False(t, isEndpointFailure(status.Error(codes.Canceled, "hedge lost")))
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"time"
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// CircuitBreakerConfig configures the circuit breaker of each endpoint
+type CircuitBreakerConfig struct {
+	// FailureThreshold consecutive failures open the breaker, defaults to 5
+	FailureThreshold int
+	// OpenTimeout is how long an open breaker rejects calls before probing, defaults to 30 seconds
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of calls let through at once to probe a half-open
+	// endpoint, defaults to 1
+	HalfOpenProbes int
+}
+
+func (c CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = 5
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = 30 * time.Second
+	}
+	if c.HalfOpenProbes <= 0 {
+		c.HalfOpenProbes = 1
+	}
+	return c
+}
+
+// circuitBreaker stops calls to an endpoint after consecutive failures, letting probes
+// through once the open timeout passed to find out whether it recovered. It is guarded
+// by the balancer's mutex.
+type circuitBreaker struct {
+	config    CircuitBreakerConfig
+	state     string
+	failures  int
+	openUntil time.Time
+	probes    int
+	// generation changes with every state change
+	generation uint64
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{config: config, state: BreakerClosed}
+}
+
+// available reports whether a call would be let through
+func (b *circuitBreaker) available(now time.Time) bool {
+	switch b.state {
+	case BreakerOpen:
+		return !now.Before(b.openUntil)
+	case BreakerHalfOpen:
+		return b.probes < b.config.HalfOpenProbes
+	default:
+		return true
+	}
+}
+
+// acquire lets a call through, moving an open breaker past its timeout to half-open. The
+// returned generation identifies the breaker state the call was let through in.
+func (b *circuitBreaker) acquire(now time.Time) (bool, uint64) {
+	if !b.available(now) {
+		return false, 0
+	}
+	if b.state == BreakerOpen {
+		b.transition(BreakerHalfOpen)
+	}
+	if b.state == BreakerHalfOpen {
+		b.probes++
+	}
+	return true, b.generation
+}
+
+// record updates the breaker with the outcome of a call it let through. Outcomes of calls
+// let through before the breaker last changed state are ignored.
+func (b *circuitBreaker) record(generation uint64, failed bool, now time.Time) {
+	if generation != b.generation {
+		return
+	}
+	switch b.state {
+	case BreakerHalfOpen:
+		b.probes--
+		if failed {
+			b.open(now)
+		} else {
+			b.transition(BreakerClosed)
+		}
+	case BreakerClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.open(now)
+		}
+	}
+}
+
+func (b *circuitBreaker) open(now time.Time) {
+	b.transition(BreakerOpen)
+	b.openUntil = now.Add(b.config.OpenTimeout)
+}
+
+func (b *circuitBreaker) transition(state string) {
+	b.state = state
+	b.generation++
+	b.failures = 0
+	b.probes = 0
+}
@@ -3,24 +3,43 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/metrics"
 	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 )
 
-// Client represents a gRPC client
+// Client represents a gRPC client, balancing calls over the instances of the service
 type Client struct {
 	config   Config
 	logger   logging.Logger
-	registry rpcpkg.ServiceRegistry
-	pool     *ConnectionPool
+	balancer *balancer
+	// configErr is returned by every call when the TLS configuration cannot be loaded
+	configErr error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Discovery state, see discovery.go
+	discoveryMu sync.Mutex
+	registry    rpcpkg.ServiceRegistry
+	instances   map[string]rpcpkg.ServiceInfo
+	refreshedAt time.Time
 }
 
 // Config holds client configuration
@@ -31,6 +50,31 @@ type Config struct {
 	RetryDelay  time.Duration
 	PoolSize    int
 	PoolTimeout time.Duration
+
+	// TLS dials over mutual TLS when a certificate is configured
+	TLS mtls.Config
+	// Credentials are attached to every call, e.g. a service token
+	Credentials credentials.PerRPCCredentials
+	// DialOptions are applied after the options above
+	DialOptions []grpc.DialOption
+	// KeepaliveInterval pings the server while streams are open so a dead connection is
+	// noticed, defaults to 30 seconds
+	KeepaliveInterval time.Duration
+
+	// Balancer is the policy spreading calls over the instances of the service, one of
+	// RoundRobin, LeastRequest and PowerOfTwo, defaults to RoundRobin
+	Balancer string
+	// CircuitBreaker configures the breaker of each instance
+	CircuitBreaker CircuitBreakerConfig
+	// Outlier configures the ejection of instances with a high error rate or latency
+	Outlier OutlierConfig
+	// Hedging sends idempotent calls to a second instance when the first is slow
+	Hedging *HedgingConfig
+	// RefreshInterval is how often the instances are listed from the registry, on top of
+	// the updates it delivers, defaults to 15 seconds
+	RefreshInterval time.Duration
+	// Metrics records calls per instance, under the service name "<service>@<address>"
+	Metrics metrics.Collector
 }
 
 // NewClient creates a new gRPC client
@@ -50,21 +94,188 @@ func NewClient(config Config, logger logging.Logger) *Client {
 	if config.PoolTimeout == 0 {
 		config.PoolTimeout = 5 * time.Second
 	}
+	if config.KeepaliveInterval == 0 {
+		config.KeepaliveInterval = 30 * time.Second
+	}
+	if config.RefreshInterval == 0 {
+		config.RefreshInterval = 15 * time.Second
+	}
 
-	return &Client{
-		config: config,
-		logger: logger,
-		pool:   NewConnectionPool(config.PoolSize, config.PoolTimeout, logger),
+	options := []grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    config.KeepaliveInterval,
+			Timeout: config.KeepaliveInterval / 2,
+		}),
 	}
-}
+	var configErr error
+	if config.TLS.Enabled() {
+		creds, err := mtls.ClientCredentials(config.TLS)
+		if err != nil {
+			configErr = fmt.Errorf("failed to load TLS configuration: %w", err)
+		} else {
+			options = append(options, grpc.WithTransportCredentials(creds))
+		}
+	}
+	if config.Credentials != nil {
+		options = append(options, grpc.WithPerRPCCredentials(config.Credentials))
+	}
+	options = append(options, config.DialOptions...)
 
-// SetRegistry sets the service registry for discovery
-func (c *Client) SetRegistry(registry rpcpkg.ServiceRegistry) {
-	c.registry = registry
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Client{
+		config:    config,
+		logger:    logger,
+		configErr: configErr,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	c.balancer = newBalancer(config.Balancer, config.CircuitBreaker, config.Outlier, func() *ConnectionPool {
+		return NewConnectionPool(config.PoolSize, config.PoolTimeout, logger, options...)
+	}, logger)
+	// Without a registry the service name is dialled directly
+	c.balancer.update([]string{config.ServiceName})
+	return c
 }
 
 // Call makes a gRPC call to the specified service and method
 func (c *Client) Call(ctx context.Context, method string, request interface{}, response interface{}) error {
+	return c.withConnection(ctx, method, response, func(ctx context.Context, conn *grpc.ClientConn, response interface{}) error {
+		return c.makeGRPCCall(ctx, conn, method, request, response)
+	})
+}
+
+// Invoke makes a unary call of a generated service, implementing grpc.ClientConnInterface so
+// generated clients get the same discovery, pooling and retries as Call
+func (c *Client) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	return c.withConnection(ctx, method, reply, func(ctx context.Context, conn *grpc.ClientConn, reply interface{}) error {
+		callCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+		return conn.Invoke(callCtx, method, args, reply, opts...)
+	})
+}
+
+// NewStream opens a stream of a generated service on a pooled connection to the service,
+// which goes back to the pool when the stream finishes. Opening is retried like a call;
+// resuming a broken stream is left to the caller, see the stream package.
+func (c *Client) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+
+	var stream grpc.ClientStream
+	tried := newTriedEndpoints()
+	err := RetryWithBackoff(ctx, func() error {
+		l, conn, err := c.connect(ctx, tried)
+		if err != nil {
+			return err
+		}
+		clientStream, err := conn.NewStream(ctx, desc, method, opts...)
+		if err != nil {
+			c.release(l, conn, method, -1, err)
+			return err
+		}
+		// Streams last too long for their latency to compare with calls
+		stream = newPooledStream(ctx, clientStream, desc, func(err error) {
+			c.release(l, conn, method, -1, err)
+		})
+		return nil
+	}, c.retryConfig(), c.logger)
+	return stream, err
+}
+
+// pooledStream returns its connection to the pool once the stream finishes
+type pooledStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	release       func(err error)
+	done          chan struct{}
+	once          sync.Once
+}
+
+func newPooledStream(ctx context.Context, stream grpc.ClientStream, desc *grpc.StreamDesc, release func(err error)) *pooledStream {
+	s := &pooledStream{
+		ClientStream:  stream,
+		serverStreams: desc.ServerStreams,
+		release:       release,
+		done:          make(chan struct{}),
+	}
+	// A canceled stream may never be received from again
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.finish(nil)
+		case <-s.done:
+		}
+	}()
+	return s
+}
+
+// RecvMsg receives a message, releasing the connection when the stream ends
+func (s *pooledStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.finish(err)
+	} else if !s.serverStreams {
+		s.finish(nil)
+	}
+	return err
+}
+
+// finish releases the connection with the error that ended the stream
+func (s *pooledStream) finish(err error) {
+	s.once.Do(func() {
+		close(s.done)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+		s.release(err)
+	})
+}
+
+// callFunc makes a call on a connection, decoding the response into reply
+type callFunc func(ctx context.Context, conn *grpc.ClientConn, reply interface{}) error
+
+// withConnection runs call on a pooled connection to an instance of the service, retrying
+// with backoff on another instance when there is one. Methods configured for hedging are
+// sent to a second instance when the first does not answer in time.
+func (c *Client) withConnection(ctx context.Context, method string, reply interface{}, call callFunc) error {
+	if c.configErr != nil {
+		return c.configErr
+	}
+
+	tried := newTriedEndpoints()
+	hedged := c.hedged(method, reply)
+	err := RetryWithBackoff(ctx, func() error {
+		if hedged {
+			return c.hedge(ctx, method, reply, tried, call)
+		}
+		return c.attempt(ctx, method, reply, tried, call)
+	}, c.retryConfig(), c.logger)
+
+	return err
+}
+
+// attempt makes a call once on an instance picked by the balancer
+func (c *Client) attempt(ctx context.Context, method string, reply interface{}, tried *triedEndpoints, call callFunc) error {
+	l, conn, err := c.connect(ctx, tried)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	callErr := call(ctx, conn, reply)
+	latency := time.Since(start)
+	if callErr != nil {
+		// Failed calls end early or time out, neither tells the instance's latency
+		latency = -1
+	}
+	c.release(l, conn, method, latency, callErr)
+
+	return callErr
+}
+
+// retryConfig is the backoff of calls and of opening streams
+func (c *Client) retryConfig() *RetryConfig {
 	retryCfg := DefaultRetryConfig()
 	retryCfg.MaxRetries = c.config.MaxRetries
 	if retryCfg.MaxRetries <= 0 {
@@ -77,54 +288,54 @@ func (c *Client) Call(ctx context.Context, method string, request interface{}, r
 	retryCfg.MaxDelay = 5 * time.Second
 	retryCfg.BackoffFactor = 2.0
 	retryCfg.JitterFactor = 0.2
+	return retryCfg
+}
 
-	err := RetryWithBackoff(ctx, func() error {
-		// Get service info from registry or use direct connection
-		var serviceInfo *rpcpkg.ServiceInfo
-		var err error
-
-		if c.registry != nil {
-			serviceInfo, err = c.getServiceFromRegistry(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to get service from registry: %w", err)
-			}
-		} else {
-			// Use direct connection if no registry
-			serviceInfo = &rpcpkg.ServiceInfo{
-				Address: c.config.ServiceName, // Assume it's a direct address
-			}
-		}
-
-		// Get connection from pool
-		conn, err := c.pool.GetConnection(ctx, serviceInfo.Address)
-		if err != nil {
-			return fmt.Errorf("failed to get connection: %w", err)
-		}
-
-		// Make gRPC call
-		callErr := c.makeGRPCCall(ctx, conn, method, request, response)
-		// Return connection to pool (mark failed if callErr != nil)
-		c.pool.ReturnConnection(conn, callErr != nil)
+// connect leases an instance of the service, preferring one not tried yet by the call,
+// and gets a pooled connection to it
+func (c *Client) connect(ctx context.Context, tried *triedEndpoints) (*lease, *grpc.ClientConn, error) {
+	if err := c.discover(ctx); err != nil {
+		return nil, nil, err
+	}
 
-		return callErr
-	}, retryCfg, c.logger)
+	l, err := c.balancer.pick(tried.contains)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Unavailable, "%s: %v", c.config.ServiceName, err)
+	}
+	tried.add(l.endpoint.address)
 
-	return err
+	// Get connection from pool
+	conn, err := l.endpoint.pool.GetConnection(ctx, l.endpoint.address)
+	if err != nil {
+		c.balancer.release(l, -1, true)
+		return nil, nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	return l, conn, nil
 }
 
-// getServiceFromRegistry gets service info from registry
-func (c *Client) getServiceFromRegistry(ctx context.Context) (*rpcpkg.ServiceInfo, error) {
-	serviceInfo, err := c.registry.GetService(ctx, c.config.ServiceName)
+// release returns the connection of a call to its pool, closing it if the call failed
+// because of the instance, and records the outcome. latency is ignored when negative.
+func (c *Client) release(l *lease, conn *grpc.ClientConn, method string, latency time.Duration, err error) {
+	failed := isEndpointFailure(err)
+	l.endpoint.pool.ReturnConnection(conn, failed)
+	c.balancer.release(l, latency, failed)
+
+	if c.config.Metrics == nil {
+		return
+	}
+	service := c.config.ServiceName + "@" + l.endpoint.address
+	c.config.Metrics.IncRequestsTotal(service, method)
 	if err != nil {
-		return nil, err
+		c.config.Metrics.IncErrorsTotal(service, method)
 	}
-
-	// Check if service is healthy
-	if serviceInfo.Health.Status != "healthy" {
-		return nil, fmt.Errorf("service %s is not healthy: %s", c.config.ServiceName, serviceInfo.Health.Status)
+	if latency >= 0 {
+		c.config.Metrics.ObserveRequestDuration(service, method, latency)
 	}
+}
 
-	return serviceInfo, nil
+// Endpoints describes the instances of the service the client balances over
+func (c *Client) Endpoints() []EndpointStats {
+	return c.balancer.stats()
 }
 
 // makeGRPCCall makes the actual gRPC call
@@ -196,9 +407,10 @@ func (c *Client) makeGRPCCall(ctx context.Context, conn *grpc.ClientConn, method
 	return nil
 }
 
-// Close closes the client and connection pool
+// Close closes the client and the connection pools of every instance
 func (c *Client) Close() error {
-	return c.pool.Close()
+	c.cancel()
+	return c.balancer.close()
 }
 
 // HealthCheck performs a health check on the service
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+)
+
+// testInstance is an instance of the generic service answering with its name
+type testInstance struct {
+	rpcproto.UnimplementedGenericServiceServer
+	name     string
+	calls    atomic.Int32
+	failing  atomic.Bool
+	delay    atomic.Int64
+	listener *bufconn.Listener
+}
+
+func (s *testInstance) Call(ctx context.Context, req *rpcproto.RPCRequest) (*rpcproto.RPCResponse, error) {
+	s.calls.Add(1)
+	if s.failing.Load() {
+		return nil, status.Error(codes.Unavailable, "instance is draining")
+	}
+	select {
+	case <-time.After(time.Duration(s.delay.Load())):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &rpcproto.RPCResponse{Result: jsonAny(fmt.Sprintf("%q", s.name))}, nil
+}
+
+func jsonAny(value string) *anypb.Any {
+	return &anypb.Any{TypeUrl: "application/json", Value: []byte(value)}
+}
+
+// testCluster runs instances of a service behind an in-memory registry
+type testCluster struct {
+	instances map[string]*testInstance
+	registry  *testRegistry
+}
+
+func newTestCluster(t *testing.T, names ...string) *testCluster {
+	t.Helper()
+	cluster := &testCluster{instances: make(map[string]*testInstance), registry: newTestRegistry()}
+	for _, name := range names {
+		instance := &testInstance{name: name, listener: bufconn.Listen(1 << 20)}
+		srv := grpc.NewServer()
+		rpcproto.RegisterGenericServiceServer(srv, instance)
+		go func() { _ = srv.Serve(instance.listener) }()
+		t.Cleanup(srv.Stop)
+
+		cluster.instances[name] = instance
+		cluster.registry.register(rpcpkg.ServiceInfo{
+			Name:       "taskdispatcher",
+			InstanceID: name,
+			Address:    "passthrough:///" + name,
+			Health:     rpcpkg.HealthStatus{Status: "healthy"},
+		})
+	}
+	return cluster
+}
+
+func (c *testCluster) client(t *testing.T, config Config) *Client {
+	t.Helper()
+	config.ServiceName = "taskdispatcher"
+	config.MaxRetries = 2
+	config.RetryDelay = time.Millisecond
+	config.Timeout = 5 * time.Second
+	config.DialOptions = []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			instance, ok := c.instances[strings.TrimPrefix(address, "passthrough:///")]
+			if !ok {
+				return nil, fmt.Errorf("unknown instance %s", address)
+			}
+			return instance.listener.DialContext(ctx)
+		}),
+	}
+	client := NewClient(config, logging.NewNoOpLogger())
+	client.SetRegistry(c.registry)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func call(t *testing.T, client *Client) (string, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var name string
+	err := client.Call(ctx, "GetStatus", nil, &name)
+	return name, err
+}
+
+func TestClient_BalancesOverInstances(t *testing.T) {
+	cluster := newTestCluster(t, "dispatcher-1", "dispatcher-2", "dispatcher-3")
+	client := cluster.client(t, Config{Balancer: RoundRobin})
+
+	answered := map[string]int{}
+	for i := 0; i < 6; i++ {
+		name, err := call(t, client)
+		require.NoError(t, err)
+		answered[name]++
+	}
+	assert.Equal(t, map[string]int{"dispatcher-1": 2, "dispatcher-2": 2, "dispatcher-3": 2}, answered)
+	assert.Len(t, client.Endpoints(), 3)
+}
+
+func TestClient_BreaksCircuitOfFailingInstance(t *testing.T) {
+	cluster := newTestCluster(t, "dispatcher-1", "dispatcher-2")
+	cluster.instances["dispatcher-1"].failing.Store(true)
+	client := cluster.client(t, Config{CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Hour}})
+
+	// Calls failing on the sick instance are retried on the other one
+	for i := 0; i < 10; i++ {
+		name, err := call(t, client)
+		require.NoError(t, err)
+		assert.Equal(t, "dispatcher-2", name)
+	}
+	assert.Equal(t, int32(2), cluster.instances["dispatcher-1"].calls.Load())
+
+	for _, endpoint := range client.Endpoints() {
+		if endpoint.Address == "passthrough:///dispatcher-1" {
+			assert.Equal(t, BreakerOpen, endpoint.Breaker)
+			assert.Equal(t, int64(2), endpoint.Errors)
+		}
+	}
+}
+
+func TestClient_HedgesSlowInstance(t *testing.T) {
+	cluster := newTestCluster(t, "dispatcher-1", "dispatcher-2")
+	cluster.instances["dispatcher-1"].delay.Store(int64(time.Minute))
+	client := cluster.client(t, Config{
+		Balancer: RoundRobin,
+		Hedging:  &HedgingConfig{Methods: []string{"GetStatus"}, Delay: 20 * time.Millisecond},
+	})
+
+	start := time.Now()
+	name, err := call(t, client)
+	require.NoError(t, err)
+	assert.Equal(t, "dispatcher-2", name)
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Equal(t, int32(1), cluster.instances["dispatcher-1"].calls.Load())
+}
+
+func TestClient_FollowsRegistryUpdates(t *testing.T) {
+	cluster := newTestCluster(t, "dispatcher-1", "dispatcher-2")
+	client := cluster.client(t, Config{})
+
+	_, err := call(t, client)
+	require.NoError(t, err)
+	require.Len(t, client.Endpoints(), 2)
+	require.Eventually(t, cluster.registry.watched, 5*time.Second, 10*time.Millisecond)
+
+	cluster.registry.deregister("taskdispatcher", "dispatcher-1")
+	require.Eventually(t, func() bool { return len(client.Endpoints()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	for i := 0; i < 4; i++ {
+		name, err := call(t, client)
+		require.NoError(t, err)
+		assert.Equal(t, "dispatcher-2", name)
+	}
+}
+
+// testRegistry is an in-memory instance registry
+type testRegistry struct {
+	mu        sync.Mutex
+	instances map[string]rpcpkg.ServiceInfo
+	watchers  []chan rpcpkg.ServiceInfo
+}
+
+func newTestRegistry() *testRegistry {
+	return &testRegistry{instances: make(map[string]rpcpkg.ServiceInfo)}
+}
+
+func (r *testRegistry) watched() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.watchers) > 0
+}
+
+func (r *testRegistry) register(info rpcpkg.ServiceInfo) {
+	_ = r.Register(context.Background(), info)
+}
+
+func (r *testRegistry) deregister(name, instanceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.instances, instanceID)
+	for _, ch := range r.watchers {
+		ch <- rpcpkg.ServiceInfo{Name: name, InstanceID: instanceID}
+	}
+}
+
+func (r *testRegistry) Register(ctx context.Context, info rpcpkg.ServiceInfo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instances[info.InstanceID] = info
+	for _, ch := range r.watchers {
+		ch <- info
+	}
+	return nil
+}
+
+func (r *testRegistry) Deregister(ctx context.Context, name string) error {
+	r.deregister(name, "")
+	return nil
+}
+
+func (r *testRegistry) GetService(ctx context.Context, name string) (*rpcpkg.ServiceInfo, error) {
+	return nil, fmt.Errorf("service %s runs several instances", name)
+}
+
+func (r *testRegistry) ListServices(ctx context.Context) ([]rpcpkg.ServiceInfo, error) {
+	return r.ListInstances(ctx, "")
+}
+
+func (r *testRegistry) ListInstances(ctx context.Context, name string) ([]rpcpkg.ServiceInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	instances := make([]rpcpkg.ServiceInfo, 0, len(r.instances))
+	for _, info := range r.instances {
+		instances = append(instances, info)
+	}
+	return instances, nil
+}
+
+func (r *testRegistry) Watch(ctx context.Context, name string) (<-chan rpcpkg.ServiceInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch := make(chan rpcpkg.ServiceInfo, 16)
+	r.watchers = append(r.watchers, ch)
+	return ch, nil
+}
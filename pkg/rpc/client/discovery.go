@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+)
+
+// SetRegistry sets the service registry for discovery. The client balances over every
+// healthy instance the registry lists, following the updates it delivers.
+func (c *Client) SetRegistry(registry rpcpkg.ServiceRegistry) {
+	c.discoveryMu.Lock()
+	c.registry = registry
+	c.instances = nil
+	c.refreshedAt = time.Time{}
+	c.discoveryMu.Unlock()
+
+	go c.watchRegistry(registry)
+}
+
+// discover lists the instances from the registry when they are due for a refresh
+func (c *Client) discover(ctx context.Context) error {
+	c.discoveryMu.Lock()
+	defer c.discoveryMu.Unlock()
+
+	if c.registry == nil || time.Since(c.refreshedAt) < c.config.RefreshInterval {
+		return nil
+	}
+
+	instances, err := c.listInstances(ctx)
+	if err != nil {
+		if len(c.instances) == 0 {
+			return fmt.Errorf("failed to get service from registry: %w", err)
+		}
+		// Keep balancing over the instances known until the registry is back
+		c.logger.Warn("Failed to refresh service instances", "service", c.config.ServiceName, "error", err)
+		c.refreshedAt = time.Now()
+		return nil
+	}
+
+	c.instances = make(map[string]rpcpkg.ServiceInfo, len(instances))
+	for _, info := range instances {
+		c.instances[info.InstanceID] = info
+	}
+	c.refreshedAt = time.Now()
+	return c.applyInstances()
+}
+
+// listInstances lists the instances of the service, a registry without instances has at
+// most one
+func (c *Client) listInstances(ctx context.Context) ([]rpcpkg.ServiceInfo, error) {
+	if registry, ok := c.registry.(rpcpkg.InstanceRegistry); ok {
+		instances, err := registry.ListInstances(ctx, c.config.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+		if len(instances) == 0 {
+			return nil, fmt.Errorf("service not found: %s", c.config.ServiceName)
+		}
+		return instances, nil
+	}
+
+	info, err := c.registry.GetService(ctx, c.config.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	return []rpcpkg.ServiceInfo{*info}, nil
+}
+
+// applyInstances balances over the healthy instances, must be called with discoveryMu held
+func (c *Client) applyInstances() error {
+	var targets []string
+	for _, info := range c.instances {
+		if info.Health.Status == "healthy" {
+			targets = append(targets, info.Target())
+		}
+	}
+	sort.Strings(targets)
+	c.balancer.update(targets)
+
+	if len(targets) == 0 {
+		return fmt.Errorf("service %s has no healthy instance", c.config.ServiceName)
+	}
+	return nil
+}
+
+// watchRegistry applies the updates of the registry until the client or registry closes
+func (c *Client) watchRegistry(registry rpcpkg.ServiceRegistry) {
+	updates, err := registry.Watch(c.ctx, c.config.ServiceName)
+	if err != nil {
+		c.logger.Warn("Failed to watch service registry", "service", c.config.ServiceName, "error", err)
+		return
+	}
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case info, ok := <-updates:
+			if !ok {
+				return
+			}
+			c.applyUpdate(registry, info)
+		}
+	}
+}
+
+// applyUpdate adds, updates or, on an empty address, removes an instance
+func (c *Client) applyUpdate(registry rpcpkg.ServiceRegistry, info rpcpkg.ServiceInfo) {
+	c.discoveryMu.Lock()
+	defer c.discoveryMu.Unlock()
+
+	// The registry was replaced, or the first refresh has not happened yet
+	if c.registry != registry || c.instances == nil {
+		return
+	}
+
+	if info.Address == "" {
+		delete(c.instances, info.InstanceID)
+	} else {
+		c.instances[info.InstanceID] = info
+	}
+	if err := c.applyInstances(); err != nil {
+		c.logger.Warn("Service instances updated", "service", c.config.ServiceName, "error", err)
+	}
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// HedgingConfig configures hedged calls: when an instance has not answered after the
+// delay the call is also sent to another instance, and the first answer wins. Only
+// idempotent methods may be hedged.
+type HedgingConfig struct {
+	// Methods are the hedged methods, full gRPC method names for generated clients or
+	// method names for Call
+	Methods []string
+	// Delay before each additional attempt, defaults to 100 milliseconds
+	Delay time.Duration
+	// MaxAttempts bounds the attempts sent at once, defaults to 2
+	MaxAttempts int
+}
+
+// hedged reports whether calls of the method are hedged; the reply must be a pointer so
+// each attempt decodes into its own copy
+func (c *Client) hedged(method string, reply interface{}) bool {
+	if c.config.Hedging == nil || reply == nil || reflect.TypeOf(reply).Kind() != reflect.Pointer {
+		return false
+	}
+	for _, m := range c.config.Hedging.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// hedgeResult is the outcome of one attempt of a hedged call
+type hedgeResult struct {
+	reply interface{}
+	err   error
+}
+
+// hedge makes a call on one instance, sending it to another instance each time the delay
+// passes without an answer, and keeps the first successful reply
+func (c *Client) hedge(ctx context.Context, method string, reply interface{}, tried *triedEndpoints, call callFunc) error {
+	delay := c.config.Hedging.Delay
+	if delay <= 0 {
+		delay = 100 * time.Millisecond
+	}
+	maxAttempts := c.config.Hedging.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 2
+	}
+
+	// Losing attempts are canceled once a reply wins
+	hedgeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult, maxAttempts)
+	launched := 0
+	launch := func() {
+		launched++
+		attemptReply := newReply(reply)
+		go func() {
+			err := c.attempt(hedgeCtx, method, attemptReply, tried, call)
+			results <- hedgeResult{reply: attemptReply, err: err}
+		}()
+	}
+	launch()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var lastErr error
+	for finished := 0; finished < launched; {
+		select {
+		case <-timer.C:
+			// Only hedge to instances the call has not been sent to
+			if launched < maxAttempts && tried.len() < c.balancer.size() {
+				launch()
+				timer.Reset(delay)
+			}
+		case result := <-results:
+			finished++
+			if result.err == nil {
+				copyReply(reply, result.reply)
+				return nil
+			}
+			lastErr = result.err
+		}
+	}
+	return lastErr
+}
+
+// newReply allocates an empty reply of the same type
+func newReply(reply interface{}) interface{} {
+	if message, ok := reply.(proto.Message); ok {
+		return message.ProtoReflect().New().Interface()
+	}
+	return reflect.New(reflect.TypeOf(reply).Elem()).Interface()
+}
+
+// copyReply copies the winning attempt's reply into the caller's
+func copyReply(dst, src interface{}) {
+	if message, ok := dst.(proto.Message); ok {
+		proto.Reset(message)
+		proto.Merge(message, src.(proto.Message))
+		return
+	}
+	reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(src).Elem())
+}
+
+// triedEndpoints are the instances a call was sent to, shared by its attempts
+type triedEndpoints struct {
+	mu        sync.Mutex
+	addresses map[string]bool
+}
+
+func newTriedEndpoints() *triedEndpoints {
+	return &triedEndpoints{addresses: make(map[string]bool)}
+}
+
+func (t *triedEndpoints) add(address string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addresses[address] = true
+}
+
+func (t *triedEndpoints) contains(address string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.addresses[address]
+}
+
+func (t *triedEndpoints) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.addresses)
+}
@@ -19,17 +19,21 @@ type ConnectionPool struct {
 	timeout time.Duration
 	logger  logging.Logger
 
+	dialOptions []grpc.DialOption
+
 	connections chan *grpc.ClientConn
 	mu          sync.RWMutex
 	closed      bool
 }
 
-// NewConnectionPool creates a new connection pool
-func NewConnectionPool(maxSize int, timeout time.Duration, logger logging.Logger) *ConnectionPool {
+// NewConnectionPool creates a new connection pool, dialling with options on top of
+// plaintext transport credentials
+func NewConnectionPool(maxSize int, timeout time.Duration, logger logging.Logger, options ...grpc.DialOption) *ConnectionPool {
 	return &ConnectionPool{
 		maxSize:     maxSize,
 		timeout:     timeout,
 		logger:      logger,
+		dialOptions: options,
 		connections: make(chan *grpc.ClientConn, maxSize),
 	}
 }
@@ -109,9 +113,8 @@ func (p *ConnectionPool) ReturnConnection(conn *grpc.ClientConn, failed bool) {
 
 // createConnection creates a new gRPC connection
 func (p *ConnectionPool) createConnection(address string) (*grpc.ClientConn, error) {
-	conn, err := grpc.NewClient(address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	options := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, p.dialOptions...)
+	conn, err := grpc.NewClient(address, options...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial gRPC server: %w", err)
 	}
@@ -23,6 +23,8 @@ type RedisRegistry struct {
 	processMap map[string]logging.ProcessName
 }
 
+var _ rpc.InstanceRegistry = (*RedisRegistry)(nil)
+
 // RedisRegistryConfig holds configuration for the Redis registry
 type RedisRegistryConfig struct {
 	// Redis configuration
@@ -101,9 +103,10 @@ func NewRedisRegistry(logger logging.Logger, config RedisRegistryConfig) (*Redis
 	return registry, nil
 }
 
-// Register registers a service in the Redis registry
+// Register registers a service in the Redis registry, or one of its instances when the
+// instance ID is set
 func (r *RedisRegistry) Register(ctx context.Context, info rpc.ServiceInfo) error {
-	key := r.serviceKey(info.Name)
+	key := r.instanceKey(info.Name, info.InstanceID)
 
 	// Update the last seen timestamp
 	info.LastSeen = time.Now()
@@ -120,7 +123,7 @@ func (r *RedisRegistry) Register(ctx context.Context, info rpc.ServiceInfo) erro
 		return fmt.Errorf("failed to register service in Redis: %w", err)
 	}
 
-	r.logger.Infof("Registered service: %s at %s:%d", info.Name, info.Address, info.Port)
+	r.logger.Infof("Registered service: %s%s at %s:%d", info.Name, instanceSuffix(info.InstanceID), info.Address, info.Port)
 
 	// Notify watchers
 	r.notifyWatchers(info.Name, info)
@@ -145,6 +148,51 @@ func (r *RedisRegistry) Deregister(ctx context.Context, name string) error {
 	return nil
 }
 
+// DeregisterInstance removes one instance of a service from the Redis registry
+func (r *RedisRegistry) DeregisterInstance(ctx context.Context, name, instanceID string) error {
+	err := r.client.Del(ctx, r.instanceKey(name, instanceID))
+	if err != nil {
+		return fmt.Errorf("failed to deregister service instance from Redis: %w", err)
+	}
+
+	r.logger.Infof("Deregistered service: %s%s", name, instanceSuffix(instanceID))
+
+	// Watchers drop the instance on an empty address
+	r.notifyWatchers(name, rpc.ServiceInfo{Name: name, InstanceID: instanceID})
+
+	return nil
+}
+
+// ListInstances retrieves every registered instance of a service, including the one
+// registered without an instance ID
+func (r *RedisRegistry) ListInstances(ctx context.Context, name string) ([]rpc.ServiceInfo, error) {
+	keys, err := r.client.ScanAll(ctx, &redis.ScanOptions{Pattern: r.serviceKey(name) + instanceSeparator + "*", Count: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan service instances: %w", err)
+	}
+	keys = append(keys, r.serviceKey(name))
+
+	var instances []rpc.ServiceInfo
+	for _, key := range keys {
+		value, exists, err := r.client.GetWithExists(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service instance from Redis: %w", err)
+		}
+		if !exists {
+			continue
+		}
+
+		var info rpc.ServiceInfo
+		if err := json.Unmarshal([]byte(value), &info); err != nil {
+			r.logger.Warnf("Failed to unmarshal service info for key %s: %v", key, err)
+			continue
+		}
+		instances = append(instances, info)
+	}
+
+	return instances, nil
+}
+
 // GetService retrieves a specific service from the registry
 func (r *RedisRegistry) GetService(ctx context.Context, name string) (*rpc.ServiceInfo, error) {
 	key := r.serviceKey(name)
@@ -284,11 +332,30 @@ func (r *RedisRegistry) UpdateServiceHealth(ctx context.Context, name string, he
 	return r.Register(ctx, *info)
 }
 
+// instanceSeparator separates the instance ID from the service name in instance keys
+const instanceSeparator = "/"
+
 // serviceKey generates the Redis key for a service
 func (r *RedisRegistry) serviceKey(name string) string {
 	return r.config.KeyPrefix + name
 }
 
+// instanceKey generates the Redis key for an instance of a service
+func (r *RedisRegistry) instanceKey(name, instanceID string) string {
+	if instanceID == "" {
+		return r.serviceKey(name)
+	}
+	return r.serviceKey(name) + instanceSeparator + instanceID
+}
+
+// instanceSuffix formats an instance ID for logs
+func instanceSuffix(instanceID string) string {
+	if instanceID == "" {
+		return ""
+	}
+	return instanceSeparator + instanceID
+}
+
 // notifyWatchers notifies all watchers of a service about changes
 func (r *RedisRegistry) notifyWatchers(serviceName string, info rpc.ServiceInfo) {
 	r.watcherMu.RLock()
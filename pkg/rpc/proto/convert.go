@@ -0,0 +1,322 @@
+package proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+// Conversions between the messages and the types the services work with. Zero times are
+// sent as unset timestamps and job IDs as decimal strings.
+
+// NewSubmitTaskRequest converts a scheduler's task request
+func NewSubmitTaskRequest(req *types.SchedulerTaskRequest) *SubmitTaskRequest {
+	return &SubmitTaskRequest{
+		TaskData: NewTaskData(&req.SendTaskDataToKeeper),
+		Source:   req.Source,
+	}
+}
+
+// ToTypes converts the request back to a scheduler's task request
+func (r *SubmitTaskRequest) ToTypes() (*types.SchedulerTaskRequest, error) {
+	if r.GetTaskData() == nil {
+		return nil, fmt.Errorf("task data is required")
+	}
+	taskData, err := r.GetTaskData().ToTypes()
+	if err != nil {
+		return nil, err
+	}
+	return &types.SchedulerTaskRequest{SendTaskDataToKeeper: *taskData, Source: r.GetSource()}, nil
+}
+
+// NewSubmitTaskResponse converts the dispatcher's response
+func NewSubmitTaskResponse(resp *types.TaskManagerAPIResponse) *SubmitTaskResponse {
+	return &SubmitTaskResponse{
+		Success:   resp.Success,
+		TaskId:    resp.TaskID,
+		Message:   resp.Message,
+		Timestamp: resp.Timestamp,
+		Error:     resp.Error,
+		Details:   resp.Details,
+	}
+}
+
+// ToTypes converts the response back to the dispatcher's response
+func (r *SubmitTaskResponse) ToTypes() *types.TaskManagerAPIResponse {
+	return &types.TaskManagerAPIResponse{
+		Success:   r.GetSuccess(),
+		TaskID:    r.GetTaskId(),
+		Message:   r.GetMessage(),
+		Timestamp: r.GetTimestamp(),
+		Error:     r.GetError(),
+		Details:   r.GetDetails(),
+	}
+}
+
+// NewTaskData converts a batch of tasks for a performer
+func NewTaskData(data *types.SendTaskDataToKeeper) *TaskData {
+	taskData := &TaskData{
+		TaskId: data.TaskID,
+		PerformerData: &PerformerData{
+			OperatorId:    data.PerformerData.OperatorID,
+			KeeperAddress: data.PerformerData.KeeperAddress,
+			IsImua:        data.PerformerData.IsImua,
+		},
+		SchedulerId:      int64(data.SchedulerID),
+		ManagerSignature: data.ManagerSignature,
+	}
+	for i := range data.TargetData {
+		taskData.TargetData = append(taskData.TargetData, NewTaskTargetData(&data.TargetData[i]))
+	}
+	for i := range data.TriggerData {
+		taskData.TriggerData = append(taskData.TriggerData, NewTaskTriggerData(&data.TriggerData[i]))
+	}
+	return taskData
+}
+
+// ToTypes converts the batch back
+func (d *TaskData) ToTypes() (*types.SendTaskDataToKeeper, error) {
+	data := &types.SendTaskDataToKeeper{
+		TaskID: d.GetTaskId(),
+		PerformerData: types.PerformerData{
+			OperatorID:    d.GetPerformerData().GetOperatorId(),
+			KeeperAddress: d.GetPerformerData().GetKeeperAddress(),
+			IsImua:        d.GetPerformerData().GetIsImua(),
+		},
+		SchedulerID:      int(d.GetSchedulerId()),
+		ManagerSignature: d.GetManagerSignature(),
+	}
+	for _, target := range d.GetTargetData() {
+		targetData, err := target.ToTypes()
+		if err != nil {
+			return nil, err
+		}
+		data.TargetData = append(data.TargetData, *targetData)
+	}
+	for _, trigger := range d.GetTriggerData() {
+		data.TriggerData = append(data.TriggerData, *trigger.ToTypes())
+	}
+	return data, nil
+}
+
+// NewTaskTargetData converts the action of a task
+func NewTaskTargetData(data *types.TaskTargetData) *TaskTargetData {
+	return &TaskTargetData{
+		JobId:                     bigIntString(data.JobID),
+		TaskId:                    data.TaskID,
+		TaskDefinitionId:          int32(data.TaskDefinitionID),
+		TargetChainId:             data.TargetChainID,
+		TargetContractAddress:     data.TargetContractAddress,
+		TargetFunction:            data.TargetFunction,
+		Abi:                       data.ABI,
+		ArgType:                   int32(data.ArgType),
+		Arguments:                 data.Arguments,
+		DynamicArgumentsScriptUrl: data.DynamicArgumentsScriptUrl,
+		IsImua:                    data.IsImua,
+		ScriptStorage:             data.ScriptStorage,
+		ScriptLanguage:            data.ScriptLanguage,
+	}
+}
+
+// ToTypes converts the action back
+func (d *TaskTargetData) ToTypes() (*types.TaskTargetData, error) {
+	jobID, err := parseBigInt(d.GetJobId())
+	if err != nil {
+		return nil, err
+	}
+	return &types.TaskTargetData{
+		JobID:                     jobID,
+		TaskID:                    d.GetTaskId(),
+		TaskDefinitionID:          int(d.GetTaskDefinitionId()),
+		TargetChainID:             d.GetTargetChainId(),
+		TargetContractAddress:     d.GetTargetContractAddress(),
+		TargetFunction:            d.GetTargetFunction(),
+		ABI:                       d.GetAbi(),
+		ArgType:                   int(d.GetArgType()),
+		Arguments:                 d.GetArguments(),
+		DynamicArgumentsScriptUrl: d.GetDynamicArgumentsScriptUrl(),
+		IsImua:                    d.GetIsImua(),
+		ScriptStorage:             d.GetScriptStorage(),
+		ScriptLanguage:            d.GetScriptLanguage(),
+	}, nil
+}
+
+// NewTaskTriggerData converts the trigger of a task
+func NewTaskTriggerData(data *types.TaskTriggerData) *TaskTriggerData {
+	return &TaskTriggerData{
+		TaskId:                      data.TaskID,
+		TaskDefinitionId:            int32(data.TaskDefinitionID),
+		Recurring:                   data.Recurring,
+		ExpirationTime:              timestamp(data.ExpirationTime),
+		CurrentTriggerTimestamp:     timestamp(data.CurrentTriggerTimestamp),
+		NextTriggerTimestamp:        timestamp(data.NextTriggerTimestamp),
+		TimeScheduleType:            data.TimeScheduleType,
+		TimeCronExpression:          data.TimeCronExpression,
+		TimeSpecificSchedule:        data.TimeSpecificSchedule,
+		TimeInterval:                data.TimeInterval,
+		EventChainId:                data.EventChainId,
+		EventTxHash:                 data.EventTxHash,
+		EventTriggerContractAddress: data.EventTriggerContractAddress,
+		EventTriggerName:            data.EventTriggerName,
+		ConditionType:               data.ConditionType,
+		ConditionSourceType:         data.ConditionSourceType,
+		ConditionSourceUrl:          data.ConditionSourceUrl,
+		ConditionUpperLimit:         int64(data.ConditionUpperLimit),
+		ConditionLowerLimit:         int64(data.ConditionLowerLimit),
+		ConditionSatisfiedValue:     int64(data.ConditionSatisfiedValue),
+	}
+}
+
+// ToTypes converts the trigger back
+func (d *TaskTriggerData) ToTypes() *types.TaskTriggerData {
+	return &types.TaskTriggerData{
+		TaskID:                      d.GetTaskId(),
+		TaskDefinitionID:            int(d.GetTaskDefinitionId()),
+		Recurring:                   d.GetRecurring(),
+		ExpirationTime:              fromTimestamp(d.GetExpirationTime()),
+		CurrentTriggerTimestamp:     fromTimestamp(d.GetCurrentTriggerTimestamp()),
+		NextTriggerTimestamp:        fromTimestamp(d.GetNextTriggerTimestamp()),
+		TimeScheduleType:            d.GetTimeScheduleType(),
+		TimeCronExpression:          d.GetTimeCronExpression(),
+		TimeSpecificSchedule:        d.GetTimeSpecificSchedule(),
+		TimeInterval:                d.GetTimeInterval(),
+		EventChainId:                d.GetEventChainId(),
+		EventTxHash:                 d.GetEventTxHash(),
+		EventTriggerContractAddress: d.GetEventTriggerContractAddress(),
+		EventTriggerName:            d.GetEventTriggerName(),
+		ConditionType:               d.GetConditionType(),
+		ConditionSourceType:         d.GetConditionSourceType(),
+		ConditionSourceUrl:          d.GetConditionSourceUrl(),
+		ConditionUpperLimit:         int(d.GetConditionUpperLimit()),
+		ConditionLowerLimit:         int(d.GetConditionLowerLimit()),
+		ConditionSatisfiedValue:     int(d.GetConditionSatisfiedValue()),
+	}
+}
+
+// NewScheduleJobRequest converts the data a condition scheduler schedules a job with
+func NewScheduleJobRequest(data *types.ScheduleConditionJobData) *ScheduleJobRequest {
+	return &ScheduleJobRequest{
+		JobId:            bigIntString(data.JobID),
+		TaskDefinitionId: int32(data.TaskDefinitionID),
+		LastExecutedAt:   timestamp(data.LastExecutedAt),
+		TaskTargetData:   NewTaskTargetData(&data.TaskTargetData),
+		EventWorkerData: &EventWorkerData{
+			ExpirationTime:         timestamp(data.EventWorkerData.ExpirationTime),
+			Recurring:              data.EventWorkerData.Recurring,
+			TriggerChainId:         data.EventWorkerData.TriggerChainID,
+			TriggerContractAddress: data.EventWorkerData.TriggerContractAddress,
+			TriggerEvent:           data.EventWorkerData.TriggerEvent,
+			EventFilterParaName:    data.EventWorkerData.EventFilterParaName,
+			EventFilterValue:       data.EventWorkerData.EventFilterValue,
+		},
+		ConditionWorkerData: &ConditionWorkerData{
+			ExpirationTime:   timestamp(data.ConditionWorkerData.ExpirationTime),
+			Recurring:        data.ConditionWorkerData.Recurring,
+			ConditionType:    data.ConditionWorkerData.ConditionType,
+			SelectedKeyRoute: data.ConditionWorkerData.SelectedKeyRoute,
+			UpperLimit:       data.ConditionWorkerData.UpperLimit,
+			LowerLimit:       data.ConditionWorkerData.LowerLimit,
+			ValueSourceType:  data.ConditionWorkerData.ValueSourceType,
+			ValueSourceUrl:   data.ConditionWorkerData.ValueSourceUrl,
+		},
+		IsImua: data.IsImua,
+	}
+}
+
+// ToTypes converts the request back, the job ID is copied to the worker data
+func (r *ScheduleJobRequest) ToTypes() (*types.ScheduleConditionJobData, error) {
+	jobID, err := parseBigInt(r.GetJobId())
+	if err != nil {
+		return nil, err
+	}
+	if jobID == nil {
+		return nil, fmt.Errorf("job ID is required")
+	}
+	target := &types.TaskTargetData{}
+	if r.GetTaskTargetData() != nil {
+		if target, err = r.GetTaskTargetData().ToTypes(); err != nil {
+			return nil, err
+		}
+	}
+	event := r.GetEventWorkerData()
+	condition := r.GetConditionWorkerData()
+	return &types.ScheduleConditionJobData{
+		JobID:            jobID,
+		TaskDefinitionID: int(r.GetTaskDefinitionId()),
+		LastExecutedAt:   fromTimestamp(r.GetLastExecutedAt()),
+		TaskTargetData:   *target,
+		EventWorkerData: types.EventWorkerData{
+			JobID:                  jobID,
+			ExpirationTime:         fromTimestamp(event.GetExpirationTime()),
+			Recurring:              event.GetRecurring(),
+			TriggerChainID:         event.GetTriggerChainId(),
+			TriggerContractAddress: event.GetTriggerContractAddress(),
+			TriggerEvent:           event.GetTriggerEvent(),
+			EventFilterParaName:    event.GetEventFilterParaName(),
+			EventFilterValue:       event.GetEventFilterValue(),
+		},
+		ConditionWorkerData: types.ConditionWorkerData{
+			JobID:            jobID,
+			ExpirationTime:   fromTimestamp(condition.GetExpirationTime()),
+			Recurring:        condition.GetRecurring(),
+			ConditionType:    condition.GetConditionType(),
+			SelectedKeyRoute: condition.GetSelectedKeyRoute(),
+			UpperLimit:       condition.GetUpperLimit(),
+			LowerLimit:       condition.GetLowerLimit(),
+			ValueSourceType:  condition.GetValueSourceType(),
+			ValueSourceUrl:   condition.GetValueSourceUrl(),
+		},
+		IsImua: r.GetIsImua(),
+	}, nil
+}
+
+// NewStats converts scheduler statistics to a struct, going through JSON so any value with
+// a JSON encoding is accepted
+func NewStats(stats map[string]interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stats: %w", err)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stats: %w", err)
+	}
+	return structpb.NewStruct(values)
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func bigIntString(b *types.BigInt) string {
+	if b == nil || b.Int == nil {
+		return ""
+	}
+	return b.String()
+}
+
+func parseBigInt(s string) (*types.BigInt, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, ok := new(types.BigInt).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid job ID: %s", s)
+	}
+	return b, nil
+}
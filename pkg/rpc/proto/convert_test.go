@@ -0,0 +1,69 @@
+package proto
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/types"
+)
+
+func TestSubmitTaskRequest_RoundTrip(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	req := &types.SchedulerTaskRequest{
+		SendTaskDataToKeeper: types.SendTaskDataToKeeper{
+			TaskID:        []int64{42},
+			PerformerData: types.PerformerData{OperatorID: 3, KeeperAddress: "0xkeeper"},
+			TargetData: []types.TaskTargetData{{
+				JobID:                 types.NewBigInt(new(big.Int).SetUint64(1 << 63)),
+				TaskID:                42,
+				TaskDefinitionID:      1,
+				TargetChainID:         "84532",
+				TargetContractAddress: "0xtarget",
+				TargetFunction:        "execute",
+				Arguments:             []string{"1", "2"},
+				ScriptStorage:         map[string]string{"counter": "7"},
+			}},
+			TriggerData: []types.TaskTriggerData{{
+				TaskID:                  42,
+				TaskDefinitionID:        1,
+				ExpirationTime:          now.Add(time.Hour),
+				CurrentTriggerTimestamp: now,
+				TimeScheduleType:        "interval",
+				TimeInterval:            60,
+			}},
+			SchedulerID:      1,
+			ManagerSignature: "0xsig",
+		},
+		Source: "time_scheduler",
+	}
+
+	data, err := proto.Marshal(NewSubmitTaskRequest(req))
+	require.NoError(t, err)
+	var decoded SubmitTaskRequest
+	require.NoError(t, proto.Unmarshal(data, &decoded))
+
+	got, err := decoded.ToTypes()
+	require.NoError(t, err)
+	assert.Equal(t, req.Source, got.Source)
+	assert.Equal(t, req.SendTaskDataToKeeper.TaskID, got.SendTaskDataToKeeper.TaskID)
+	assert.Equal(t, req.SendTaskDataToKeeper.PerformerData, got.SendTaskDataToKeeper.PerformerData)
+	assert.Equal(t, req.SendTaskDataToKeeper.TargetData[0].JobID.String(), got.SendTaskDataToKeeper.TargetData[0].JobID.String())
+	assert.Equal(t, req.SendTaskDataToKeeper.TargetData[0].ScriptStorage, got.SendTaskDataToKeeper.TargetData[0].ScriptStorage)
+	assert.Equal(t, req.SendTaskDataToKeeper.TriggerData[0], got.SendTaskDataToKeeper.TriggerData[0])
+	assert.True(t, got.SendTaskDataToKeeper.TriggerData[0].NextTriggerTimestamp.IsZero())
+}
+
+func TestSubmitTaskRequest_Invalid(t *testing.T) {
+	_, err := (&SubmitTaskRequest{}).ToTypes()
+	assert.Error(t, err)
+
+	_, err = (&SubmitTaskRequest{TaskData: &TaskData{
+		TargetData: []*TaskTargetData{{JobId: "not-a-number"}},
+	}}).ToTypes()
+	assert.Error(t, err)
+}
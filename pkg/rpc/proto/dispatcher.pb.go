@@ -0,0 +1,1022 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: pkg/rpc/proto/dispatcher.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PerformerData is the keeper a batch of tasks is sent to
+type PerformerData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OperatorId    int64                  `protobuf:"varint,1,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	KeeperAddress string                 `protobuf:"bytes,2,opt,name=keeper_address,json=keeperAddress,proto3" json:"keeper_address,omitempty"`
+	IsImua        bool                   `protobuf:"varint,3,opt,name=is_imua,json=isImua,proto3" json:"is_imua,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PerformerData) Reset() {
+	*x = PerformerData{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PerformerData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PerformerData) ProtoMessage() {}
+
+func (x *PerformerData) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PerformerData.ProtoReflect.Descriptor instead.
+func (*PerformerData) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PerformerData) GetOperatorId() int64 {
+	if x != nil {
+		return x.OperatorId
+	}
+	return 0
+}
+
+func (x *PerformerData) GetKeeperAddress() string {
+	if x != nil {
+		return x.KeeperAddress
+	}
+	return ""
+}
+
+func (x *PerformerData) GetIsImua() bool {
+	if x != nil {
+		return x.IsImua
+	}
+	return false
+}
+
+// TaskTargetData is the action a task performs
+type TaskTargetData struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	JobId                     string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TaskId                    int64                  `protobuf:"varint,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TaskDefinitionId          int32                  `protobuf:"varint,3,opt,name=task_definition_id,json=taskDefinitionId,proto3" json:"task_definition_id,omitempty"`
+	TargetChainId             string                 `protobuf:"bytes,4,opt,name=target_chain_id,json=targetChainId,proto3" json:"target_chain_id,omitempty"`
+	TargetContractAddress     string                 `protobuf:"bytes,5,opt,name=target_contract_address,json=targetContractAddress,proto3" json:"target_contract_address,omitempty"`
+	TargetFunction            string                 `protobuf:"bytes,6,opt,name=target_function,json=targetFunction,proto3" json:"target_function,omitempty"`
+	Abi                       string                 `protobuf:"bytes,7,opt,name=abi,proto3" json:"abi,omitempty"`
+	ArgType                   int32                  `protobuf:"varint,8,opt,name=arg_type,json=argType,proto3" json:"arg_type,omitempty"`
+	Arguments                 []string               `protobuf:"bytes,9,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	DynamicArgumentsScriptUrl string                 `protobuf:"bytes,10,opt,name=dynamic_arguments_script_url,json=dynamicArgumentsScriptUrl,proto3" json:"dynamic_arguments_script_url,omitempty"`
+	IsImua                    bool                   `protobuf:"varint,11,opt,name=is_imua,json=isImua,proto3" json:"is_imua,omitempty"`
+	// Storage passed to custom scripts (task definition 7)
+	ScriptStorage  map[string]string `protobuf:"bytes,12,rep,name=script_storage,json=scriptStorage,proto3" json:"script_storage,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ScriptLanguage string            `protobuf:"bytes,13,opt,name=script_language,json=scriptLanguage,proto3" json:"script_language,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TaskTargetData) Reset() {
+	*x = TaskTargetData{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskTargetData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskTargetData) ProtoMessage() {}
+
+func (x *TaskTargetData) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskTargetData.ProtoReflect.Descriptor instead.
+func (*TaskTargetData) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TaskTargetData) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *TaskTargetData) GetTaskId() int64 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskTargetData) GetTaskDefinitionId() int32 {
+	if x != nil {
+		return x.TaskDefinitionId
+	}
+	return 0
+}
+
+func (x *TaskTargetData) GetTargetChainId() string {
+	if x != nil {
+		return x.TargetChainId
+	}
+	return ""
+}
+
+func (x *TaskTargetData) GetTargetContractAddress() string {
+	if x != nil {
+		return x.TargetContractAddress
+	}
+	return ""
+}
+
+func (x *TaskTargetData) GetTargetFunction() string {
+	if x != nil {
+		return x.TargetFunction
+	}
+	return ""
+}
+
+func (x *TaskTargetData) GetAbi() string {
+	if x != nil {
+		return x.Abi
+	}
+	return ""
+}
+
+func (x *TaskTargetData) GetArgType() int32 {
+	if x != nil {
+		return x.ArgType
+	}
+	return 0
+}
+
+func (x *TaskTargetData) GetArguments() []string {
+	if x != nil {
+		return x.Arguments
+	}
+	return nil
+}
+
+func (x *TaskTargetData) GetDynamicArgumentsScriptUrl() string {
+	if x != nil {
+		return x.DynamicArgumentsScriptUrl
+	}
+	return ""
+}
+
+func (x *TaskTargetData) GetIsImua() bool {
+	if x != nil {
+		return x.IsImua
+	}
+	return false
+}
+
+func (x *TaskTargetData) GetScriptStorage() map[string]string {
+	if x != nil {
+		return x.ScriptStorage
+	}
+	return nil
+}
+
+func (x *TaskTargetData) GetScriptLanguage() string {
+	if x != nil {
+		return x.ScriptLanguage
+	}
+	return ""
+}
+
+// TaskTriggerData is the trigger keepers validate a task against
+type TaskTriggerData struct {
+	state                       protoimpl.MessageState `protogen:"open.v1"`
+	TaskId                      int64                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TaskDefinitionId            int32                  `protobuf:"varint,2,opt,name=task_definition_id,json=taskDefinitionId,proto3" json:"task_definition_id,omitempty"`
+	Recurring                   bool                   `protobuf:"varint,3,opt,name=recurring,proto3" json:"recurring,omitempty"`
+	ExpirationTime              *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expiration_time,json=expirationTime,proto3" json:"expiration_time,omitempty"`
+	CurrentTriggerTimestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=current_trigger_timestamp,json=currentTriggerTimestamp,proto3" json:"current_trigger_timestamp,omitempty"`
+	NextTriggerTimestamp        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=next_trigger_timestamp,json=nextTriggerTimestamp,proto3" json:"next_trigger_timestamp,omitempty"`
+	TimeScheduleType            string                 `protobuf:"bytes,7,opt,name=time_schedule_type,json=timeScheduleType,proto3" json:"time_schedule_type,omitempty"`
+	TimeCronExpression          string                 `protobuf:"bytes,8,opt,name=time_cron_expression,json=timeCronExpression,proto3" json:"time_cron_expression,omitempty"`
+	TimeSpecificSchedule        string                 `protobuf:"bytes,9,opt,name=time_specific_schedule,json=timeSpecificSchedule,proto3" json:"time_specific_schedule,omitempty"`
+	TimeInterval                int64                  `protobuf:"varint,10,opt,name=time_interval,json=timeInterval,proto3" json:"time_interval,omitempty"`
+	EventChainId                string                 `protobuf:"bytes,11,opt,name=event_chain_id,json=eventChainId,proto3" json:"event_chain_id,omitempty"`
+	EventTxHash                 string                 `protobuf:"bytes,12,opt,name=event_tx_hash,json=eventTxHash,proto3" json:"event_tx_hash,omitempty"`
+	EventTriggerContractAddress string                 `protobuf:"bytes,13,opt,name=event_trigger_contract_address,json=eventTriggerContractAddress,proto3" json:"event_trigger_contract_address,omitempty"`
+	EventTriggerName            string                 `protobuf:"bytes,14,opt,name=event_trigger_name,json=eventTriggerName,proto3" json:"event_trigger_name,omitempty"`
+	ConditionType               string                 `protobuf:"bytes,15,opt,name=condition_type,json=conditionType,proto3" json:"condition_type,omitempty"`
+	ConditionSourceType         string                 `protobuf:"bytes,16,opt,name=condition_source_type,json=conditionSourceType,proto3" json:"condition_source_type,omitempty"`
+	ConditionSourceUrl          string                 `protobuf:"bytes,17,opt,name=condition_source_url,json=conditionSourceUrl,proto3" json:"condition_source_url,omitempty"`
+	ConditionUpperLimit         int64                  `protobuf:"varint,18,opt,name=condition_upper_limit,json=conditionUpperLimit,proto3" json:"condition_upper_limit,omitempty"`
+	ConditionLowerLimit         int64                  `protobuf:"varint,19,opt,name=condition_lower_limit,json=conditionLowerLimit,proto3" json:"condition_lower_limit,omitempty"`
+	ConditionSatisfiedValue     int64                  `protobuf:"varint,20,opt,name=condition_satisfied_value,json=conditionSatisfiedValue,proto3" json:"condition_satisfied_value,omitempty"`
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
+}
+
+func (x *TaskTriggerData) Reset() {
+	*x = TaskTriggerData{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskTriggerData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskTriggerData) ProtoMessage() {}
+
+func (x *TaskTriggerData) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskTriggerData.ProtoReflect.Descriptor instead.
+func (*TaskTriggerData) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TaskTriggerData) GetTaskId() int64 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskTriggerData) GetTaskDefinitionId() int32 {
+	if x != nil {
+		return x.TaskDefinitionId
+	}
+	return 0
+}
+
+func (x *TaskTriggerData) GetRecurring() bool {
+	if x != nil {
+		return x.Recurring
+	}
+	return false
+}
+
+func (x *TaskTriggerData) GetExpirationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpirationTime
+	}
+	return nil
+}
+
+func (x *TaskTriggerData) GetCurrentTriggerTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CurrentTriggerTimestamp
+	}
+	return nil
+}
+
+func (x *TaskTriggerData) GetNextTriggerTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextTriggerTimestamp
+	}
+	return nil
+}
+
+func (x *TaskTriggerData) GetTimeScheduleType() string {
+	if x != nil {
+		return x.TimeScheduleType
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetTimeCronExpression() string {
+	if x != nil {
+		return x.TimeCronExpression
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetTimeSpecificSchedule() string {
+	if x != nil {
+		return x.TimeSpecificSchedule
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetTimeInterval() int64 {
+	if x != nil {
+		return x.TimeInterval
+	}
+	return 0
+}
+
+func (x *TaskTriggerData) GetEventChainId() string {
+	if x != nil {
+		return x.EventChainId
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetEventTxHash() string {
+	if x != nil {
+		return x.EventTxHash
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetEventTriggerContractAddress() string {
+	if x != nil {
+		return x.EventTriggerContractAddress
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetEventTriggerName() string {
+	if x != nil {
+		return x.EventTriggerName
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetConditionType() string {
+	if x != nil {
+		return x.ConditionType
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetConditionSourceType() string {
+	if x != nil {
+		return x.ConditionSourceType
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetConditionSourceUrl() string {
+	if x != nil {
+		return x.ConditionSourceUrl
+	}
+	return ""
+}
+
+func (x *TaskTriggerData) GetConditionUpperLimit() int64 {
+	if x != nil {
+		return x.ConditionUpperLimit
+	}
+	return 0
+}
+
+func (x *TaskTriggerData) GetConditionLowerLimit() int64 {
+	if x != nil {
+		return x.ConditionLowerLimit
+	}
+	return 0
+}
+
+func (x *TaskTriggerData) GetConditionSatisfiedValue() int64 {
+	if x != nil {
+		return x.ConditionSatisfiedValue
+	}
+	return 0
+}
+
+// TaskData is a batch of tasks for one performer, signed by the dispatcher
+type TaskData struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TaskId           []int64                `protobuf:"varint,1,rep,packed,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	PerformerData    *PerformerData         `protobuf:"bytes,2,opt,name=performer_data,json=performerData,proto3" json:"performer_data,omitempty"`
+	TargetData       []*TaskTargetData      `protobuf:"bytes,3,rep,name=target_data,json=targetData,proto3" json:"target_data,omitempty"`
+	TriggerData      []*TaskTriggerData     `protobuf:"bytes,4,rep,name=trigger_data,json=triggerData,proto3" json:"trigger_data,omitempty"`
+	SchedulerId      int64                  `protobuf:"varint,5,opt,name=scheduler_id,json=schedulerId,proto3" json:"scheduler_id,omitempty"`
+	ManagerSignature string                 `protobuf:"bytes,6,opt,name=manager_signature,json=managerSignature,proto3" json:"manager_signature,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TaskData) Reset() {
+	*x = TaskData{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskData) ProtoMessage() {}
+
+func (x *TaskData) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskData.ProtoReflect.Descriptor instead.
+func (*TaskData) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TaskData) GetTaskId() []int64 {
+	if x != nil {
+		return x.TaskId
+	}
+	return nil
+}
+
+func (x *TaskData) GetPerformerData() *PerformerData {
+	if x != nil {
+		return x.PerformerData
+	}
+	return nil
+}
+
+func (x *TaskData) GetTargetData() []*TaskTargetData {
+	if x != nil {
+		return x.TargetData
+	}
+	return nil
+}
+
+func (x *TaskData) GetTriggerData() []*TaskTriggerData {
+	if x != nil {
+		return x.TriggerData
+	}
+	return nil
+}
+
+func (x *TaskData) GetSchedulerId() int64 {
+	if x != nil {
+		return x.SchedulerId
+	}
+	return 0
+}
+
+func (x *TaskData) GetManagerSignature() string {
+	if x != nil {
+		return x.ManagerSignature
+	}
+	return ""
+}
+
+// SubmitTaskRequest submits a batch of tasks from a scheduler
+type SubmitTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskData      *TaskData              `protobuf:"bytes,1,opt,name=task_data,json=taskData,proto3" json:"task_data,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitTaskRequest) Reset() {
+	*x = SubmitTaskRequest{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitTaskRequest) ProtoMessage() {}
+
+func (x *SubmitTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitTaskRequest.ProtoReflect.Descriptor instead.
+func (*SubmitTaskRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SubmitTaskRequest) GetTaskData() *TaskData {
+	if x != nil {
+		return x.TaskData
+	}
+	return nil
+}
+
+func (x *SubmitTaskRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// SubmitTaskResponse is the dispatcher's answer to a submission
+type SubmitTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	TaskId        []int64                `protobuf:"varint,2,rep,packed,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Details       string                 `protobuf:"bytes,6,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitTaskResponse) Reset() {
+	*x = SubmitTaskResponse{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitTaskResponse) ProtoMessage() {}
+
+func (x *SubmitTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitTaskResponse.ProtoReflect.Descriptor instead.
+func (*SubmitTaskResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SubmitTaskResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SubmitTaskResponse) GetTaskId() []int64 {
+	if x != nil {
+		return x.TaskId
+	}
+	return nil
+}
+
+func (x *SubmitTaskResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SubmitTaskResponse) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *SubmitTaskResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SubmitTaskResponse) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+// SubmitBatchRequest submits several task batches at once
+type SubmitBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*SubmitTaskRequest   `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitBatchRequest) Reset() {
+	*x = SubmitBatchRequest{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBatchRequest) ProtoMessage() {}
+
+func (x *SubmitBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBatchRequest.ProtoReflect.Descriptor instead.
+func (*SubmitBatchRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubmitBatchRequest) GetRequests() []*SubmitTaskRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// SubmitBatchResponse has one response per request, in order
+type SubmitBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Responses     []*SubmitTaskResponse  `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+	Submitted     int32                  `protobuf:"varint,2,opt,name=submitted,proto3" json:"submitted,omitempty"`
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitBatchResponse) Reset() {
+	*x = SubmitBatchResponse{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBatchResponse) ProtoMessage() {}
+
+func (x *SubmitBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBatchResponse.ProtoReflect.Descriptor instead.
+func (*SubmitBatchResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SubmitBatchResponse) GetResponses() []*SubmitTaskResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+func (x *SubmitBatchResponse) GetSubmitted() int32 {
+	if x != nil {
+		return x.Submitted
+	}
+	return 0
+}
+
+func (x *SubmitBatchResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// StreamSubmitRequest is a task batch sent on a submit stream
+type StreamSubmitRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sequence is chosen by the client and echoed in the response
+	Sequence      uint64             `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Request       *SubmitTaskRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSubmitRequest) Reset() {
+	*x = StreamSubmitRequest{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSubmitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSubmitRequest) ProtoMessage() {}
+
+func (x *StreamSubmitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSubmitRequest.ProtoReflect.Descriptor instead.
+func (*StreamSubmitRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StreamSubmitRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StreamSubmitRequest) GetRequest() *SubmitTaskRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+// StreamSubmitResponse answers the request with the same sequence
+type StreamSubmitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Response      *SubmitTaskResponse    `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSubmitResponse) Reset() {
+	*x = StreamSubmitResponse{}
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSubmitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSubmitResponse) ProtoMessage() {}
+
+func (x *StreamSubmitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rpc_proto_dispatcher_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSubmitResponse.ProtoReflect.Descriptor instead.
+func (*StreamSubmitResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamSubmitResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StreamSubmitResponse) GetResponse() *SubmitTaskResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+var File_pkg_rpc_proto_dispatcher_proto protoreflect.FileDescriptor
+
+const file_pkg_rpc_proto_dispatcher_proto_rawDesc = "" +
+	"\n" +
+	"\x1epkg/rpc/proto/dispatcher.proto\x12\x03rpc\x1a\x1fgoogle/protobuf/timestamp.proto\"p\n" +
+	"\rPerformerData\x12\x1f\n" +
+	"\voperator_id\x18\x01 \x01(\x03R\n" +
+	"operatorId\x12%\n" +
+	"\x0ekeeper_address\x18\x02 \x01(\tR\rkeeperAddress\x12\x17\n" +
+	"\ais_imua\x18\x03 \x01(\bR\x06isImua\"\xd6\x04\n" +
+	"\x0eTaskTargetData\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\x03R\x06taskId\x12,\n" +
+	"\x12task_definition_id\x18\x03 \x01(\x05R\x10taskDefinitionId\x12&\n" +
+	"\x0ftarget_chain_id\x18\x04 \x01(\tR\rtargetChainId\x126\n" +
+	"\x17target_contract_address\x18\x05 \x01(\tR\x15targetContractAddress\x12'\n" +
+	"\x0ftarget_function\x18\x06 \x01(\tR\x0etargetFunction\x12\x10\n" +
+	"\x03abi\x18\a \x01(\tR\x03abi\x12\x19\n" +
+	"\barg_type\x18\b \x01(\x05R\aargType\x12\x1c\n" +
+	"\targuments\x18\t \x03(\tR\targuments\x12?\n" +
+	"\x1cdynamic_arguments_script_url\x18\n" +
+	" \x01(\tR\x19dynamicArgumentsScriptUrl\x12\x17\n" +
+	"\ais_imua\x18\v \x01(\bR\x06isImua\x12M\n" +
+	"\x0escript_storage\x18\f \x03(\v2&.rpc.TaskTargetData.ScriptStorageEntryR\rscriptStorage\x12'\n" +
+	"\x0fscript_language\x18\r \x01(\tR\x0escriptLanguage\x1a@\n" +
+	"\x12ScriptStorageEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8e\b\n" +
+	"\x0fTaskTriggerData\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x03R\x06taskId\x12,\n" +
+	"\x12task_definition_id\x18\x02 \x01(\x05R\x10taskDefinitionId\x12\x1c\n" +
+	"\trecurring\x18\x03 \x01(\bR\trecurring\x12C\n" +
+	"\x0fexpiration_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0eexpirationTime\x12V\n" +
+	"\x19current_trigger_timestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x17currentTriggerTimestamp\x12P\n" +
+	"\x16next_trigger_timestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x14nextTriggerTimestamp\x12,\n" +
+	"\x12time_schedule_type\x18\a \x01(\tR\x10timeScheduleType\x120\n" +
+	"\x14time_cron_expression\x18\b \x01(\tR\x12timeCronExpression\x124\n" +
+	"\x16time_specific_schedule\x18\t \x01(\tR\x14timeSpecificSchedule\x12#\n" +
+	"\rtime_interval\x18\n" +
+	" \x01(\x03R\ftimeInterval\x12$\n" +
+	"\x0eevent_chain_id\x18\v \x01(\tR\feventChainId\x12\"\n" +
+	"\revent_tx_hash\x18\f \x01(\tR\veventTxHash\x12C\n" +
+	"\x1eevent_trigger_contract_address\x18\r \x01(\tR\x1beventTriggerContractAddress\x12,\n" +
+	"\x12event_trigger_name\x18\x0e \x01(\tR\x10eventTriggerName\x12%\n" +
+	"\x0econdition_type\x18\x0f \x01(\tR\rconditionType\x122\n" +
+	"\x15condition_source_type\x18\x10 \x01(\tR\x13conditionSourceType\x120\n" +
+	"\x14condition_source_url\x18\x11 \x01(\tR\x12conditionSourceUrl\x122\n" +
+	"\x15condition_upper_limit\x18\x12 \x01(\x03R\x13conditionUpperLimit\x122\n" +
+	"\x15condition_lower_limit\x18\x13 \x01(\x03R\x13conditionLowerLimit\x12:\n" +
+	"\x19condition_satisfied_value\x18\x14 \x01(\x03R\x17conditionSatisfiedValue\"\x9d\x02\n" +
+	"\bTaskData\x12\x17\n" +
+	"\atask_id\x18\x01 \x03(\x03R\x06taskId\x129\n" +
+	"\x0eperformer_data\x18\x02 \x01(\v2\x12.rpc.PerformerDataR\rperformerData\x124\n" +
+	"\vtarget_data\x18\x03 \x03(\v2\x13.rpc.TaskTargetDataR\n" +
+	"targetData\x127\n" +
+	"\ftrigger_data\x18\x04 \x03(\v2\x14.rpc.TaskTriggerDataR\vtriggerData\x12!\n" +
+	"\fscheduler_id\x18\x05 \x01(\x03R\vschedulerId\x12+\n" +
+	"\x11manager_signature\x18\x06 \x01(\tR\x10managerSignature\"W\n" +
+	"\x11SubmitTaskRequest\x12*\n" +
+	"\ttask_data\x18\x01 \x01(\v2\r.rpc.TaskDataR\btaskData\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\"\xaf\x01\n" +
+	"\x12SubmitTaskResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x17\n" +
+	"\atask_id\x18\x02 \x03(\x03R\x06taskId\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x18\n" +
+	"\adetails\x18\x06 \x01(\tR\adetails\"H\n" +
+	"\x12SubmitBatchRequest\x122\n" +
+	"\brequests\x18\x01 \x03(\v2\x16.rpc.SubmitTaskRequestR\brequests\"\x82\x01\n" +
+	"\x13SubmitBatchResponse\x125\n" +
+	"\tresponses\x18\x01 \x03(\v2\x17.rpc.SubmitTaskResponseR\tresponses\x12\x1c\n" +
+	"\tsubmitted\x18\x02 \x01(\x05R\tsubmitted\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\"c\n" +
+	"\x13StreamSubmitRequest\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x120\n" +
+	"\arequest\x18\x02 \x01(\v2\x16.rpc.SubmitTaskRequestR\arequest\"g\n" +
+	"\x14StreamSubmitResponse\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x123\n" +
+	"\bresponse\x18\x02 \x01(\v2\x17.rpc.SubmitTaskResponseR\bresponse2\xe1\x01\n" +
+	"\x15TaskDispatcherService\x12=\n" +
+	"\n" +
+	"SubmitTask\x12\x16.rpc.SubmitTaskRequest\x1a\x17.rpc.SubmitTaskResponse\x12@\n" +
+	"\vSubmitBatch\x12\x17.rpc.SubmitBatchRequest\x1a\x18.rpc.SubmitBatchResponse\x12G\n" +
+	"\fStreamSubmit\x12\x18.rpc.StreamSubmitRequest\x1a\x19.rpc.StreamSubmitResponse(\x010\x01B4Z2github.com/trigg3rX/triggerx-backend/pkg/rpc/protob\x06proto3"
+
+var (
+	file_pkg_rpc_proto_dispatcher_proto_rawDescOnce sync.Once
+	file_pkg_rpc_proto_dispatcher_proto_rawDescData []byte
+)
+
+func file_pkg_rpc_proto_dispatcher_proto_rawDescGZIP() []byte {
+	file_pkg_rpc_proto_dispatcher_proto_rawDescOnce.Do(func() {
+		file_pkg_rpc_proto_dispatcher_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_rpc_proto_dispatcher_proto_rawDesc), len(file_pkg_rpc_proto_dispatcher_proto_rawDesc)))
+	})
+	return file_pkg_rpc_proto_dispatcher_proto_rawDescData
+}
+
+var file_pkg_rpc_proto_dispatcher_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_pkg_rpc_proto_dispatcher_proto_goTypes = []any{
+	(*PerformerData)(nil),         // 0: rpc.PerformerData
+	(*TaskTargetData)(nil),        // 1: rpc.TaskTargetData
+	(*TaskTriggerData)(nil),       // 2: rpc.TaskTriggerData
+	(*TaskData)(nil),              // 3: rpc.TaskData
+	(*SubmitTaskRequest)(nil),     // 4: rpc.SubmitTaskRequest
+	(*SubmitTaskResponse)(nil),    // 5: rpc.SubmitTaskResponse
+	(*SubmitBatchRequest)(nil),    // 6: rpc.SubmitBatchRequest
+	(*SubmitBatchResponse)(nil),   // 7: rpc.SubmitBatchResponse
+	(*StreamSubmitRequest)(nil),   // 8: rpc.StreamSubmitRequest
+	(*StreamSubmitResponse)(nil),  // 9: rpc.StreamSubmitResponse
+	nil,                           // 10: rpc.TaskTargetData.ScriptStorageEntry
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+}
+var file_pkg_rpc_proto_dispatcher_proto_depIdxs = []int32{
+	10, // 0: rpc.TaskTargetData.script_storage:type_name -> rpc.TaskTargetData.ScriptStorageEntry
+	11, // 1: rpc.TaskTriggerData.expiration_time:type_name -> google.protobuf.Timestamp
+	11, // 2: rpc.TaskTriggerData.current_trigger_timestamp:type_name -> google.protobuf.Timestamp
+	11, // 3: rpc.TaskTriggerData.next_trigger_timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 4: rpc.TaskData.performer_data:type_name -> rpc.PerformerData
+	1,  // 5: rpc.TaskData.target_data:type_name -> rpc.TaskTargetData
+	2,  // 6: rpc.TaskData.trigger_data:type_name -> rpc.TaskTriggerData
+	3,  // 7: rpc.SubmitTaskRequest.task_data:type_name -> rpc.TaskData
+	4,  // 8: rpc.SubmitBatchRequest.requests:type_name -> rpc.SubmitTaskRequest
+	5,  // 9: rpc.SubmitBatchResponse.responses:type_name -> rpc.SubmitTaskResponse
+	4,  // 10: rpc.StreamSubmitRequest.request:type_name -> rpc.SubmitTaskRequest
+	5,  // 11: rpc.StreamSubmitResponse.response:type_name -> rpc.SubmitTaskResponse
+	4,  // 12: rpc.TaskDispatcherService.SubmitTask:input_type -> rpc.SubmitTaskRequest
+	6,  // 13: rpc.TaskDispatcherService.SubmitBatch:input_type -> rpc.SubmitBatchRequest
+	8,  // 14: rpc.TaskDispatcherService.StreamSubmit:input_type -> rpc.StreamSubmitRequest
+	5,  // 15: rpc.TaskDispatcherService.SubmitTask:output_type -> rpc.SubmitTaskResponse
+	7,  // 16: rpc.TaskDispatcherService.SubmitBatch:output_type -> rpc.SubmitBatchResponse
+	9,  // 17: rpc.TaskDispatcherService.StreamSubmit:output_type -> rpc.StreamSubmitResponse
+	15, // [15:18] is the sub-list for method output_type
+	12, // [12:15] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_pkg_rpc_proto_dispatcher_proto_init() }
+func file_pkg_rpc_proto_dispatcher_proto_init() {
+	if File_pkg_rpc_proto_dispatcher_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_rpc_proto_dispatcher_proto_rawDesc), len(file_pkg_rpc_proto_dispatcher_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_rpc_proto_dispatcher_proto_goTypes,
+		DependencyIndexes: file_pkg_rpc_proto_dispatcher_proto_depIdxs,
+		MessageInfos:      file_pkg_rpc_proto_dispatcher_proto_msgTypes,
+	}.Build()
+	File_pkg_rpc_proto_dispatcher_proto = out.File
+	file_pkg_rpc_proto_dispatcher_proto_goTypes = nil
+	file_pkg_rpc_proto_dispatcher_proto_depIdxs = nil
+}
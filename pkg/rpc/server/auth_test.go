@@ -0,0 +1,396 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/client"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
+)
+
+const bufnetTarget = "passthrough:///bufnet"
+
+// callerHandler answers every method with the caller identity the server authenticated
+type callerHandler struct{}
+
+func (callerHandler) Handle(ctx context.Context, method string, request interface{}) (interface{}, error) {
+	identity, _ := rpcpkg.CallerFromContext(ctx)
+	return map[string]string{"service": identity.Service, "source": identity.Source}, nil
+}
+
+func (callerHandler) GetMethods() []rpcpkg.RPCMethod {
+	return nil
+}
+
+func startTestServer(t *testing.T, config Config) *bufconn.Listener {
+	t.Helper()
+	config.Name = "task-dispatcher"
+	srv := NewServer(config, logging.NewNoOpLogger())
+	srv.RegisterHandler("task-dispatcher", callerHandler{})
+
+	listener := bufconn.Listen(1 << 20)
+	require.NoError(t, srv.StartWithListener(context.Background(), listener))
+	t.Cleanup(func() { _ = srv.Stop(context.Background()) })
+	return listener
+}
+
+func dialTestServer(t *testing.T, listener *bufconn.Listener, options ...grpc.DialOption) rpcproto.GenericServiceClient {
+	t.Helper()
+	options = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	}, options...)
+	conn, err := grpc.NewClient(bufnetTarget, options...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return rpcproto.NewGenericServiceClient(conn)
+}
+
+func callMethod(t *testing.T, conn rpcproto.GenericServiceClient, method string) (rpcpkg.CallerIdentity, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := conn.Call(ctx, &rpcproto.RPCRequest{Method: method})
+	if err != nil {
+		return rpcpkg.CallerIdentity{}, err
+	}
+	var identity rpcpkg.CallerIdentity
+	require.NoError(t, json.Unmarshal(resp.Result.Value, &identity))
+	return identity, nil
+}
+
+// testPKI issues the server and client certificates of a test CA
+type testPKI struct {
+	ca *mtls.CA
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	ca, err := mtls.NewCA("triggerx-test-ca", time.Hour)
+	require.NoError(t, err)
+	return &testPKI{ca: ca}
+}
+
+func (p *testPKI) config(t *testing.T, service string) mtls.Config {
+	t.Helper()
+	certPEM, keyPEM, err := p.ca.Issue(service, []string{"bufnet", "127.0.0.1"}, time.Hour)
+	require.NoError(t, err)
+	return mtls.Config{CertPEM: certPEM, KeyPEM: keyPEM, CAPEM: p.ca.CertPEM()}
+}
+
+func (p *testPKI) clientCredentials(t *testing.T, service string) grpc.DialOption {
+	t.Helper()
+	creds, err := mtls.ClientCredentials(p.config(t, service))
+	require.NoError(t, err)
+	return grpc.WithTransportCredentials(creds)
+}
+
+func newTokenSigner(t *testing.T) (*jwt.ServiceTokenSigner, ed25519.PublicKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return jwt.NewServiceTokenSigner(private), public
+}
+
+func TestAuthInterceptor_MTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	listener := startTestServer(t, Config{
+		TLS: pki.config(t, "task-dispatcher"),
+		Auth: &AuthConfig{
+			MethodPolicy: map[string][]string{
+				"SubmitTask": {"time-scheduler", "condition-scheduler"},
+				"Drain":      {"*"},
+			},
+		},
+	})
+
+	scheduler := dialTestServer(t, listener, pki.clientCredentials(t, "time-scheduler"))
+	identity, err := callMethod(t, scheduler, "SubmitTask")
+	require.NoError(t, err)
+	assert.Equal(t, rpcpkg.CallerIdentity{Service: "time-scheduler", Source: rpcpkg.CallerSourceMTLS}, identity)
+
+	keeper := dialTestServer(t, listener, pki.clientCredentials(t, "keeper"))
+	_, err = callMethod(t, keeper, "SubmitTask")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Wildcard and unlisted methods are open to every service with a certificate
+	_, err = callMethod(t, keeper, "Drain")
+	assert.NoError(t, err)
+	_, err = callMethod(t, keeper, "GetStatus")
+	assert.NoError(t, err)
+
+	t.Run("client without certificate", func(t *testing.T) {
+		pool := x509.NewCertPool()
+		require.True(t, pool.AppendCertsFromPEM(pki.ca.CertPEM()))
+		conn := dialTestServer(t, listener, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		})))
+		_, err := callMethod(t, conn, "GetStatus")
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("certificate of another CA", func(t *testing.T) {
+		other := newTestPKI(t)
+		cfg := other.config(t, "time-scheduler")
+		cfg.CAPEM = pki.ca.CertPEM()
+		creds, err := mtls.ClientCredentials(cfg)
+		require.NoError(t, err)
+		conn := dialTestServer(t, listener, grpc.WithTransportCredentials(creds))
+		_, err = callMethod(t, conn, "SubmitTask")
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
+
+func TestAuthInterceptor_ServiceTokens(t *testing.T) {
+	oldSigner, oldKey := newTokenSigner(t)
+	signer, key := newTokenSigner(t)
+	listener := startTestServer(t, Config{
+		Auth: &AuthConfig{
+			TokenVerifier: jwt.NewServiceTokenVerifier("task-dispatcher", key, oldKey),
+			MethodPolicy:  map[string][]string{"SubmitTask": {"time-scheduler"}},
+		},
+	})
+
+	dialWithToken := func(signer *jwt.ServiceTokenSigner, service, audience string, methods []string) rpcproto.GenericServiceClient {
+		creds := jwt.NewServiceTokenCredentials(signer, service, audience, methods, time.Minute, false)
+		return dialTestServer(t, listener,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithPerRPCCredentials(creds),
+		)
+	}
+
+	scheduler := dialWithToken(signer, "time-scheduler", "task-dispatcher", []string{"SubmitTask"})
+	identity, err := callMethod(t, scheduler, "SubmitTask")
+	require.NoError(t, err)
+	assert.Equal(t, rpcpkg.CallerIdentity{Service: "time-scheduler", Source: rpcpkg.CallerSourceToken}, identity)
+
+	// The token only allows the methods it was signed for
+	_, err = callMethod(t, scheduler, "CancelTask")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Tokens of a rotated out key are still accepted while the key is trusted
+	rotated := dialWithToken(oldSigner, "time-scheduler", "task-dispatcher", []string{"*"})
+	_, err = callMethod(t, rotated, "SubmitTask")
+	assert.NoError(t, err)
+
+	keeper := dialWithToken(signer, "keeper", "task-dispatcher", []string{"*"})
+	_, err = callMethod(t, keeper, "SubmitTask")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	wrongAudience := dialWithToken(signer, "time-scheduler", "task-monitor", []string{"*"})
+	_, err = callMethod(t, wrongAudience, "SubmitTask")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	untrustedSigner, _ := newTokenSigner(t)
+	untrusted := dialWithToken(untrustedSigner, "time-scheduler", "task-dispatcher", []string{"*"})
+	_, err = callMethod(t, untrusted, "SubmitTask")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	anonymous := dialTestServer(t, listener, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	_, err = callMethod(t, anonymous, "SubmitTask")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Health checks are served without authentication
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = anonymous.HealthCheck(ctx, &rpcproto.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestNewServiceAuthConfig(t *testing.T) {
+	assert.Nil(t, NewServiceAuthConfig("task-dispatcher", nil, nil).TokenVerifier)
+
+	signer, key := newTokenSigner(t)
+	listener := startTestServer(t, Config{
+		Auth: NewServiceAuthConfig("task-dispatcher", []ed25519.PublicKey{key}, map[string][]string{
+			"SubmitTask": {"time-scheduler", "condition-scheduler"},
+		}),
+	})
+	dialAs := func(service string) rpcproto.GenericServiceClient {
+		return dialTestServer(t, listener,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithPerRPCCredentials(jwt.NewServiceTokenCredentials(signer, service, "task-dispatcher", []string{"*"}, time.Minute, false)),
+		)
+	}
+
+	_, err := callMethod(t, dialAs("condition-scheduler"), "SubmitTask")
+	assert.NoError(t, err)
+	_, err = callMethod(t, dialAs("task-monitor"), "SubmitTask")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Methods missing from the policy are denied to every service
+	_, err = callMethod(t, dialAs("time-scheduler"), "CancelTask")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAuthInterceptor_TokenMustMatchCertificate(t *testing.T) {
+	pki := newTestPKI(t)
+	signer, key := newTokenSigner(t)
+	listener := startTestServer(t, Config{
+		TLS:  pki.config(t, "task-dispatcher"),
+		Auth: &AuthConfig{TokenVerifier: jwt.NewServiceTokenVerifier("task-dispatcher", key)},
+	})
+
+	creds := jwt.NewServiceTokenCredentials(signer, "time-scheduler", "task-dispatcher", []string{"*"}, time.Minute, true)
+	matching := dialTestServer(t, listener, pki.clientCredentials(t, "time-scheduler"), grpc.WithPerRPCCredentials(creds))
+	identity, err := callMethod(t, matching, "SubmitTask")
+	require.NoError(t, err)
+	assert.Equal(t, rpcpkg.CallerSourceToken, identity.Source)
+
+	mismatched := dialTestServer(t, listener, pki.clientCredentials(t, "keeper"), grpc.WithPerRPCCredentials(creds))
+	_, err = callMethod(t, mismatched, "SubmitTask")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	signer, key := newTokenSigner(t)
+	listener := startTestServer(t, Config{
+		Auth: &AuthConfig{TokenVerifier: jwt.NewServiceTokenVerifier("task-dispatcher", key)},
+		RateLimit: &RateLimitConfig{
+			RequestsPerSecond: 0.001,
+			Burst:             2,
+			CallerLimits:      map[string]float64{"health": 1000},
+		},
+	})
+
+	dial := func(service string) rpcproto.GenericServiceClient {
+		creds := jwt.NewServiceTokenCredentials(signer, service, "task-dispatcher", []string{"*"}, time.Minute, false)
+		return dialTestServer(t, listener,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithPerRPCCredentials(creds),
+		)
+	}
+
+	scheduler := dial("time-scheduler")
+	for i := 0; i < 2; i++ {
+		_, err := callMethod(t, scheduler, "SubmitTask")
+		require.NoError(t, err)
+	}
+	_, err := callMethod(t, scheduler, "SubmitTask")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Every caller has its own bucket
+	_, err = callMethod(t, dial("condition-scheduler"), "SubmitTask")
+	assert.NoError(t, err)
+
+	health := dial("health")
+	for i := 0; i < 5; i++ {
+		_, err := callMethod(t, health, "SubmitTask")
+		assert.NoError(t, err)
+	}
+}
+
+func TestCallerLimiter_DropsIdleBuckets(t *testing.T) {
+	limiter := newCallerLimiter(RateLimitConfig{RequestsPerSecond: 1, IdleTimeout: time.Minute})
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	limiter.lastSweep = now
+
+	assert.True(t, limiter.allow("keeper"))
+	assert.False(t, limiter.allow("keeper"))
+	assert.Len(t, limiter.buckets, 1)
+
+	now = now.Add(2 * time.Minute)
+	assert.True(t, limiter.allow("time-scheduler"))
+	assert.Len(t, limiter.buckets, 1, "idle bucket of keeper is dropped")
+}
+
+func TestClient_MTLSWithServiceToken(t *testing.T) {
+	pki := newTestPKI(t)
+	signer, key := newTokenSigner(t)
+	listener := startTestServer(t, Config{
+		TLS: pki.config(t, "task-dispatcher"),
+		Auth: &AuthConfig{
+			TokenVerifier: jwt.NewServiceTokenVerifier("task-dispatcher", key),
+			MethodPolicy:  map[string][]string{"SubmitTask": {"time-scheduler"}},
+			DenyUnlisted:  true,
+		},
+	})
+
+	c := client.NewClient(client.Config{
+		ServiceName: bufnetTarget,
+		TLS:         pki.config(t, "time-scheduler"),
+		Credentials: jwt.NewServiceTokenCredentials(signer, "time-scheduler", "task-dispatcher", []string{"SubmitTask"}, time.Minute, true),
+		MaxRetries:  1,
+		DialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+		},
+	}, logging.NewNoOpLogger())
+	defer func() { _ = c.Close() }()
+
+	var identity rpcpkg.CallerIdentity
+	require.NoError(t, c.Call(context.Background(), "SubmitTask", map[string]string{"task": "1"}, &identity))
+	assert.Equal(t, rpcpkg.CallerIdentity{Service: "time-scheduler", Source: rpcpkg.CallerSourceToken}, identity)
+
+	err := c.Call(context.Background(), "GetStatus", nil, &identity)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+// testServerStream is a server stream carrying only a context
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context { return s.ctx }
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	signer, key := newTokenSigner(t)
+	interceptor := AuthStreamInterceptor(AuthConfig{
+		TokenVerifier: jwt.NewServiceTokenVerifier("task-monitor", key),
+		MethodPolicy:  map[string][]string{rpcproto.TaskMonitorService_WatchTasks_FullMethodName: {"keeper"}},
+	})
+	info := &grpc.StreamServerInfo{FullMethod: rpcproto.TaskMonitorService_WatchTasks_FullMethodName, IsServerStream: true}
+
+	open := func(service string) (rpcpkg.CallerIdentity, error) {
+		ctx := context.Background()
+		if service != "" {
+			creds := jwt.NewServiceTokenCredentials(signer, service, "task-monitor", []string{"*"}, time.Minute, false)
+			md, err := creds.GetRequestMetadata(ctx)
+			require.NoError(t, err)
+			ctx = metadata.NewIncomingContext(ctx, metadata.New(md))
+		}
+		var identity rpcpkg.CallerIdentity
+		err := interceptor(nil, &testServerStream{ctx: ctx}, info, func(_ interface{}, ss grpc.ServerStream) error {
+			identity, _ = rpcpkg.CallerFromContext(ss.Context())
+			return nil
+		})
+		return identity, err
+	}
+
+	identity, err := open("keeper")
+	require.NoError(t, err)
+	assert.Equal(t, rpcpkg.CallerIdentity{Service: "keeper", Source: rpcpkg.CallerSourceToken}, identity)
+
+	_, err = open("time-scheduler")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = open("")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
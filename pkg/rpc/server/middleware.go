@@ -2,14 +2,23 @@ package server
 
 import (
 	"context"
+	"crypto/ed25519"
+	"fmt"
+	"net"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/jwt"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
 	metricspkg "github.com/trigg3rX/triggerx-backend/pkg/rpc/metrics"
+	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 )
 
 // LoggingInterceptor provides request/response logging for gRPC
@@ -41,6 +50,30 @@ func LoggingInterceptor(logger logging.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
+// LoggingStreamInterceptor logs when streams open and close
+func LoggingStreamInterceptor(logger logging.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		logger.Debug("gRPC stream opened", "method", info.FullMethod)
+
+		err := handler(srv, ss)
+
+		duration := time.Since(start)
+		if err != nil && status.Code(err) != codes.Canceled {
+			logger.Error("gRPC stream failed",
+				"method", info.FullMethod,
+				"duration", duration,
+				"error", err)
+		} else {
+			logger.Debug("gRPC stream closed",
+				"method", info.FullMethod,
+				"duration", duration)
+		}
+		return err
+	}
+}
+
 // MetricsInterceptor provides metrics collection for gRPC
 func MetricsInterceptor(collector metricspkg.Collector, serviceName string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -64,15 +97,166 @@ func MetricsInterceptor(collector metricspkg.Collector, serviceName string) grpc
 	}
 }
 
-// AuthInterceptor provides authentication for gRPC
-func AuthInterceptor() grpc.UnaryServerInterceptor {
+// AuthConfig configures how callers are authenticated and which methods they may call
+type AuthConfig struct {
+	// TokenVerifier accepts Ed25519 service tokens from the authorization metadata when set.
+	// A caller presenting both a client certificate and a token must use the same service name.
+	TokenVerifier *jwt.ServiceTokenVerifier
+	// AllowAnonymous lets callers with neither a client certificate nor a token through
+	AllowAnonymous bool
+	// MethodPolicy maps method names to the services allowed to call them, "*" allowing
+	// every authenticated service. The name is the method of a generic Call, e.g.
+	// "SubmitTask", or the full gRPC method for any other RPC.
+	MethodPolicy map[string][]string
+	// DenyUnlisted rejects methods missing from MethodPolicy instead of allowing them to
+	// every authenticated caller
+	DenyUnlisted bool
+	// PublicMethods are full gRPC methods served without authentication
+	PublicMethods []string
+}
+
+// NewServiceAuthConfig authenticates the callers of service by their client certificate, or
+// by service tokens addressed to service and signed with one of keys when keys are set.
+// Only the methods of policy are served, to the services it lists.
+func NewServiceAuthConfig(service string, keys []ed25519.PublicKey, policy map[string][]string) *AuthConfig {
+	config := &AuthConfig{MethodPolicy: policy, DenyUnlisted: true}
+	if len(keys) > 0 {
+		config.TokenVerifier = jwt.NewServiceTokenVerifier(service, keys...)
+	}
+	return config
+}
+
+// defaultPublicMethods are served without authentication
+var defaultPublicMethods = []string{
+	rpcproto.GenericService_HealthCheck_FullMethodName,
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+// AuthInterceptor authenticates the caller from its verified client certificate or service
+// token, puts its identity in the context and enforces the method policy
+func AuthInterceptor(config AuthConfig) grpc.UnaryServerInterceptor {
+	public := config.publicMethods()
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// TODO: Add JWT authentication logic here
-		// For now, just pass through
+		if public[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authorize(ctx, config, RequestMethod(info, req))
+		if err != nil {
+			return nil, err
+		}
 		return handler(ctx, req)
 	}
 }
 
+// AuthStreamInterceptor is AuthInterceptor for streams, authorizing the full gRPC method
+// once when the stream is opened
+func AuthStreamInterceptor(config AuthConfig) grpc.StreamServerInterceptor {
+	public := config.publicMethods()
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if public[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authorize(ss.Context(), config, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the replaced context
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// publicMethods returns the methods served without authentication
+func (c AuthConfig) publicMethods() map[string]bool {
+	public := make(map[string]bool)
+	for _, method := range append(append([]string{}, defaultPublicMethods...), c.PublicMethods...) {
+		public[method] = true
+	}
+	return public
+}
+
+// authorize authenticates the caller of method and returns the context with its identity
+func authorize(ctx context.Context, config AuthConfig, method string) (context.Context, error) {
+	identity, tokenClaims, err := authenticate(ctx, config)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+	}
+
+	if tokenClaims != nil && !tokenClaims.AllowsMethod(method) {
+		return nil, status.Errorf(codes.PermissionDenied, "token of %s does not allow %s", identity.Service, method)
+	}
+	if !config.allows(identity, method) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s", identity.Service, method)
+	}
+
+	return context.WithValue(ctx, rpcpkg.CallerIdentityKey, identity), nil
+}
+
+// RequestMethod returns the method name authorization and rate limiting apply to: the
+// method of a generic Call, or the full gRPC method
+func RequestMethod(info *grpc.UnaryServerInfo, req interface{}) string {
+	if call, ok := req.(*rpcproto.RPCRequest); ok && info.FullMethod == rpcproto.GenericService_Call_FullMethodName {
+		return call.Method
+	}
+	return info.FullMethod
+}
+
+// authenticate identifies the caller, returning the claims of its token if it sent one
+func authenticate(ctx context.Context, config AuthConfig) (rpcpkg.CallerIdentity, *jwt.ServiceClaims, error) {
+	certService, hasCert := mtls.PeerService(ctx)
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = values[0]
+		}
+	}
+
+	if token != "" && config.TokenVerifier != nil {
+		claims, err := config.TokenVerifier.Verify(token)
+		if err != nil {
+			return rpcpkg.CallerIdentity{}, nil, err
+		}
+		if hasCert && claims.Service != certService {
+			return rpcpkg.CallerIdentity{}, nil, fmt.Errorf("token service %s does not match certificate %s", claims.Service, certService)
+		}
+		return rpcpkg.CallerIdentity{Service: claims.Service, Source: rpcpkg.CallerSourceToken}, claims, nil
+	}
+	if hasCert {
+		return rpcpkg.CallerIdentity{Service: certService, Source: rpcpkg.CallerSourceMTLS}, nil, nil
+	}
+	if config.AllowAnonymous {
+		return rpcpkg.CallerIdentity{Source: rpcpkg.CallerSourceAnonymous}, nil, nil
+	}
+	return rpcpkg.CallerIdentity{}, nil, fmt.Errorf("no client certificate or service token")
+}
+
+// allows checks the method policy
+func (c AuthConfig) allows(identity rpcpkg.CallerIdentity, method string) bool {
+	allowed, listed := c.MethodPolicy[method]
+	if !listed {
+		return !c.DenyUnlisted
+	}
+	for _, service := range allowed {
+		if service == identity.Service || (service == "*" && identity.Source != rpcpkg.CallerSourceAnonymous) {
+			return true
+		}
+	}
+	return false
+}
+
 // RecoveryInterceptor provides panic recovery for gRPC
 func RecoveryInterceptor(logger logging.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
@@ -88,6 +272,21 @@ func RecoveryInterceptor(logger logging.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
+// RecoveryStreamInterceptor provides panic recovery for gRPC streams
+func RecoveryStreamInterceptor(logger logging.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("gRPC stream panic recovered",
+					"method", info.FullMethod,
+					"panic", r)
+				err = status.Errorf(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
 // TimeoutInterceptor provides request timeout for gRPC
 func TimeoutInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -97,11 +296,42 @@ func TimeoutInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
 	}
 }
 
-// RateLimitInterceptor provides rate limiting for gRPC
-func RateLimitInterceptor() grpc.UnaryServerInterceptor {
+// RateLimitInterceptor limits the request rate of every caller with a token bucket, keyed by
+// the service name set by AuthInterceptor or by the peer address of anonymous callers
+func RateLimitInterceptor(config RateLimitConfig) grpc.UnaryServerInterceptor {
+	limiter := newCallerLimiter(config)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// TODO: Add rate limiting logic here
-		// For now, just pass through
+		caller := callerKey(ctx)
+		if !limiter.allow(caller) {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", caller)
+		}
 		return handler(ctx, req)
 	}
 }
+
+// RateLimitStreamInterceptor limits the rate at which every caller opens streams, messages
+// on an open stream are paced by flow control instead
+func RateLimitStreamInterceptor(config RateLimitConfig) grpc.StreamServerInterceptor {
+	limiter := newCallerLimiter(config)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		caller := callerKey(ss.Context())
+		if !limiter.allow(caller) {
+			return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", caller)
+		}
+		return handler(srv, ss)
+	}
+}
+
+// callerKey is the authenticated service name, or the peer host of anonymous callers
+func callerKey(ctx context.Context) string {
+	if identity, ok := rpcpkg.CallerFromContext(ctx); ok && identity.Service != "" {
+		return identity.Service
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return rpcpkg.CallerSourceAnonymous
+}
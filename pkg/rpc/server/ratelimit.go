@@ -0,0 +1,84 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitConfig configures the token bucket every caller gets
+type RateLimitConfig struct {
+	// RequestsPerSecond is the rate the bucket refills at
+	RequestsPerSecond float64
+	// Burst is the bucket size, defaulting to one second of requests
+	Burst int
+	// CallerLimits overrides RequestsPerSecond for specific callers, keyed by service name.
+	// Their bucket holds at least one second of requests.
+	CallerLimits map[string]float64
+	// IdleTimeout is how long an unused bucket is kept, defaulting to 10 minutes
+	IdleTimeout time.Duration
+}
+
+// callerLimiter keeps one token bucket per caller, dropping buckets left idle
+type callerLimiter struct {
+	config    RateLimitConfig
+	mu        sync.Mutex
+	buckets   map[string]*callerBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type callerBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newCallerLimiter(config RateLimitConfig) *callerLimiter {
+	if config.Burst <= 0 {
+		config.Burst = int(config.RequestsPerSecond)
+		if config.Burst < 1 {
+			config.Burst = 1
+		}
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = 10 * time.Minute
+	}
+	return &callerLimiter{
+		config:    config,
+		buckets:   make(map[string]*callerBucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// allow takes a token from the caller's bucket
+func (l *callerLimiter) allow(caller string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) > l.config.IdleTimeout {
+		for key, bucket := range l.buckets {
+			if now.Sub(bucket.lastSeen) > l.config.IdleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[caller]
+	if !ok {
+		limit, burst := l.config.RequestsPerSecond, l.config.Burst
+		if callerLimit, ok := l.config.CallerLimits[caller]; ok {
+			limit = callerLimit
+			if int(callerLimit) > burst {
+				burst = int(callerLimit)
+			}
+		}
+		bucket = &callerBucket{limiter: rate.NewLimiter(rate.Limit(limit), burst)}
+		l.buckets[caller] = bucket
+	}
+	bucket.lastSeen = now
+	return bucket.limiter.AllowN(now, 1)
+}
@@ -8,11 +8,13 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/trigg3rX/triggerx-backend/pkg/logging"
 	"github.com/trigg3rX/triggerx-backend/pkg/retry"
 	rpcpkg "github.com/trigg3rX/triggerx-backend/pkg/rpc"
+	"github.com/trigg3rX/triggerx-backend/pkg/rpc/auth/mtls"
 	rpcproto "github.com/trigg3rX/triggerx-backend/pkg/rpc/proto"
 )
 
@@ -21,7 +23,9 @@ type Server struct {
 	config       Config
 	logger       logging.Logger
 	handlers     map[string]rpcpkg.RPCHandler
+	services     []typedService
 	interceptors []grpc.UnaryServerInterceptor
+	streams      []grpc.StreamServerInterceptor
 	registry     rpcpkg.ServiceRegistry
 
 	// Server state
@@ -34,6 +38,12 @@ type Server struct {
 	serviceInfo rpcpkg.ServiceInfo
 }
 
+// typedService is a generated gRPC service registered next to the generic handlers
+type typedService struct {
+	desc *grpc.ServiceDesc
+	impl interface{}
+}
+
 // Config holds server configuration
 type Config struct {
 	Name        string
@@ -43,6 +53,24 @@ type Config struct {
 	Timeout     time.Duration
 	MaxRequests int
 	Metadata    map[string]string
+
+	// TLS serves over mutual TLS when a certificate is configured, callers must present a
+	// certificate signed by the CA
+	TLS mtls.Config
+	// Auth authenticates callers and enforces the method policy when set
+	Auth *AuthConfig
+	// RateLimit limits the request rate of every caller when set, for streams the rate at
+	// which they are opened
+	RateLimit *RateLimitConfig
+
+	// StreamWindowSize and ConnWindowSize set the HTTP/2 flow control windows, the number of
+	// bytes a sender may have in flight on a stream and on a connection. gRPC's defaults
+	// apply when zero.
+	StreamWindowSize int32
+	ConnWindowSize   int32
+	// KeepaliveInterval pings idle connections so long lived streams notice a dead peer,
+	// defaults to 30 seconds
+	KeepaliveInterval time.Duration
 }
 
 // NewServer creates a new gRPC server
@@ -53,6 +81,9 @@ func NewServer(config Config, logger logging.Logger) *Server {
 	if config.MaxRequests == 0 {
 		config.MaxRequests = 1000
 	}
+	if config.KeepaliveInterval == 0 {
+		config.KeepaliveInterval = 30 * time.Second
+	}
 
 	return &Server{
 		config:   config,
@@ -75,11 +106,24 @@ func (s *Server) RegisterHandler(serviceName string, handler rpcpkg.RPCHandler)
 	s.handlers[serviceName] = handler
 }
 
+// RegisterService registers a generated gRPC service, implementing grpc.ServiceRegistrar so
+// the generated Register functions accept the server. It is served once the server starts.
+func (s *Server) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.services = append(s.services, typedService{desc: desc, impl: impl})
+}
+
 // AddInterceptor adds a gRPC interceptor to the server
 func (s *Server) AddInterceptor(interceptor grpc.UnaryServerInterceptor) {
 	s.interceptors = append(s.interceptors, interceptor)
 }
 
+// AddStreamInterceptor adds a gRPC stream interceptor to the server
+func (s *Server) AddStreamInterceptor(interceptor grpc.StreamServerInterceptor) {
+	s.streams = append(s.streams, interceptor)
+}
+
 // SetRegistry sets the service registry
 func (s *Server) SetRegistry(registry rpcpkg.ServiceRegistry) {
 	s.registry = registry
@@ -94,11 +138,77 @@ func (s *Server) Start(ctx context.Context) error {
 		return fmt.Errorf("server is already running")
 	}
 
-	// Create gRPC server with interceptors
-	s.grpcServer = grpc.NewServer(
-		grpc.UnaryInterceptor(s.chainUnaryInterceptors()),
+	// Create listener
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.config.Address, s.config.Port))
+	if err != nil {
+		return fmt.Errorf("failed to create listener: %w", err)
+	}
+	return s.serve(ctx, listener)
+}
+
+// StartWithListener starts the gRPC server on an existing listener
+func (s *Server) StartWithListener(ctx context.Context, listener net.Listener) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return fmt.Errorf("server is already running")
+	}
+	return s.serve(ctx, listener)
+}
+
+// serve registers the handlers and serves on listener, s.mu must be held
+func (s *Server) serve(ctx context.Context, listener net.Listener) error {
+	// Authentication runs before the interceptors added, rate limiting after it so callers
+	// are keyed by their identity
+	var interceptors []grpc.UnaryServerInterceptor
+	if s.config.Auth != nil {
+		interceptors = append(interceptors, AuthInterceptor(*s.config.Auth))
+	}
+	if s.config.RateLimit != nil {
+		interceptors = append(interceptors, RateLimitInterceptor(*s.config.RateLimit))
+	}
+	interceptors = append(interceptors, s.interceptors...)
+
+	var streams []grpc.StreamServerInterceptor
+	if s.config.Auth != nil {
+		streams = append(streams, AuthStreamInterceptor(*s.config.Auth))
+	}
+	if s.config.RateLimit != nil {
+		streams = append(streams, RateLimitStreamInterceptor(*s.config.RateLimit))
+	}
+	streams = append(streams, s.streams...)
+
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.chainUnaryInterceptors(interceptors)),
+		grpc.ChainStreamInterceptor(streams...),
 		grpc.MaxConcurrentStreams(uint32(s.config.MaxRequests)),
-	)
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    s.config.KeepaliveInterval,
+			Timeout: s.config.KeepaliveInterval / 2,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             s.config.KeepaliveInterval / 2,
+			PermitWithoutStream: true,
+		}),
+	}
+	if s.config.StreamWindowSize > 0 {
+		options = append(options, grpc.InitialWindowSize(s.config.StreamWindowSize))
+	}
+	if s.config.ConnWindowSize > 0 {
+		options = append(options, grpc.InitialConnWindowSize(s.config.ConnWindowSize))
+	}
+	if s.config.TLS.Enabled() {
+		creds, err := mtls.ServerCredentials(s.config.TLS)
+		if err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("failed to load TLS configuration: %w", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	// Create gRPC server with interceptors
+	s.grpcServer = grpc.NewServer(options...)
 
 	// Register handlers
 	for serviceName, handler := range s.handlers {
@@ -106,15 +216,14 @@ func (s *Server) Start(ctx context.Context) error {
 		rpcproto.RegisterGenericServiceServer(s.grpcServer, genericService)
 		s.logger.Info("Registered gRPC handler", "service", serviceName)
 	}
+	for _, service := range s.services {
+		s.grpcServer.RegisterService(service.desc, service.impl)
+		s.logger.Info("Registered gRPC service", "service", service.desc.ServiceName)
+	}
 
 	// Enable reflection for debugging
 	reflection.Register(s.grpcServer)
 
-	// Create listener
-	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.config.Address, s.config.Port))
-	if err != nil {
-		return fmt.Errorf("failed to create listener: %w", err)
-	}
 	s.listener = listener
 
 	// Update service info
@@ -195,20 +304,20 @@ func (s *Server) Stop(ctx context.Context) error {
 }
 
 // chainUnaryInterceptors chains all unary interceptors
-func (s *Server) chainUnaryInterceptors() grpc.UnaryServerInterceptor {
+func (s *Server) chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		// Apply interceptors in order
-		for i := len(s.interceptors) - 1; i >= 0; i-- {
-			handler = s.wrapUnaryHandler(s.interceptors[i], handler)
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			handler = s.wrapUnaryHandler(interceptors[i], info, handler)
 		}
 		return handler(ctx, req)
 	}
 }
 
 // wrapUnaryHandler wraps a unary handler with an interceptor
-func (s *Server) wrapUnaryHandler(interceptor grpc.UnaryServerInterceptor, handler grpc.UnaryHandler) grpc.UnaryHandler {
+func (s *Server) wrapUnaryHandler(interceptor grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
 	return func(ctx context.Context, req interface{}) (interface{}, error) {
-		return interceptor(ctx, req, &grpc.UnaryServerInfo{}, handler)
+		return interceptor(ctx, req, info, handler)
 	}
 }
 
@@ -0,0 +1,194 @@
+package stream
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSequenceExpired is returned when a subscriber resumes from a sequence no longer retained
+	ErrSequenceExpired = errors.New("sequence is no longer retained")
+	// ErrSlowConsumer closes a subscription whose buffer filled up, the subscriber should
+	// resume from the last sequence it handled
+	ErrSlowConsumer = errors.New("subscriber is too slow")
+	// ErrClosed closes the subscriptions of a closed broadcaster
+	ErrClosed = errors.New("broadcaster is closed")
+)
+
+// Event is a value with the sequence number it was published at, sequences start at 1
+type Event[T any] struct {
+	Sequence uint64
+	Value    T
+}
+
+// Broadcaster fans out published values to subscribers, keeping the latest values so a
+// subscriber that reconnects can resume from the last sequence it handled. Sequences are
+// only meaningful within the broadcaster's epoch, which changes when the process restarts.
+type Broadcaster[T any] struct {
+	epoch       string
+	mu          sync.Mutex
+	next        uint64
+	history     []Event[T]
+	historySize int
+	bufferSize  int
+	subscribers map[*Subscription[T]]struct{}
+	closed      bool
+}
+
+// NewBroadcaster creates a broadcaster retaining historySize values, with a buffer of
+// bufferSize values per subscriber
+func NewBroadcaster[T any](historySize, bufferSize int) *Broadcaster[T] {
+	if historySize <= 0 {
+		historySize = 1024
+	}
+	if bufferSize <= 0 {
+		bufferSize = 256
+	}
+	return &Broadcaster[T]{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		next:        1,
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription[T]]struct{}),
+	}
+}
+
+// Publish sends a value to every subscriber and returns its sequence. Subscribers whose
+// buffer is full are closed with ErrSlowConsumer instead of blocking the publisher.
+func (b *Broadcaster[T]) Publish(value T) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0
+	}
+
+	event := Event[T]{Sequence: b.next, Value: value}
+	b.next++
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			b.closeLocked(sub, ErrSlowConsumer)
+		}
+	}
+	return event.Sequence
+}
+
+// Subscribe starts a subscription at the given sequence, replaying the retained values
+// published since. Zero subscribes to new values only.
+func (b *Broadcaster[T]) Subscribe(from uint64) (*Subscription[T], error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+	if from == 0 {
+		from = b.next
+	}
+	if from > b.next {
+		return nil, ErrSequenceExpired
+	}
+
+	var replay []Event[T]
+	if from < b.next {
+		if len(b.history) == 0 || b.history[0].Sequence > from {
+			return nil, ErrSequenceExpired
+		}
+		replay = b.history[from-b.history[0].Sequence:]
+	}
+
+	size := b.bufferSize
+	if len(replay) > size {
+		size = len(replay) + b.bufferSize
+	}
+	sub := &Subscription[T]{
+		broadcaster: b,
+		start:       from,
+		events:      make(chan Event[T], size),
+		done:        make(chan struct{}),
+	}
+	for _, event := range replay {
+		sub.events <- event
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Epoch identifies the sequence numbering of the broadcaster
+func (b *Broadcaster[T]) Epoch() string {
+	return b.epoch
+}
+
+// Head returns the sequence of the latest published value
+func (b *Broadcaster[T]) Head() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.next - 1
+}
+
+// Close closes every subscription with ErrClosed
+func (b *Broadcaster[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.closeLocked(sub, ErrClosed)
+	}
+}
+
+func (b *Broadcaster[T]) closeLocked(sub *Subscription[T], err error) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	sub.err = err
+	close(sub.done)
+}
+
+// Subscription receives the values of a broadcaster
+type Subscription[T any] struct {
+	broadcaster *Broadcaster[T]
+	start       uint64
+	events      chan Event[T]
+	done        chan struct{}
+	err         error
+}
+
+// Start is the sequence of the first value the subscription receives
+func (s *Subscription[T]) Start() uint64 {
+	return s.start
+}
+
+// Events delivers the values in sequence order
+func (s *Subscription[T]) Events() <-chan Event[T] {
+	return s.events
+}
+
+// Done is closed when the subscription is closed by the broadcaster, Err tells why
+func (s *Subscription[T]) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns why the subscription was closed
+func (s *Subscription[T]) Err() error {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription
+func (s *Subscription[T]) Close() {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	s.broadcaster.closeLocked(s, nil)
+}
@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive[T any](t *testing.T, sub *Subscription[T], n int) []uint64 {
+	t.Helper()
+	sequences := make([]uint64, 0, n)
+	for i := 0; i < n; i++ {
+		select {
+		case event := <-sub.Events():
+			sequences = append(sequences, event.Sequence)
+		default:
+			t.Fatalf("expected %d events, got %d", n, i)
+		}
+	}
+	return sequences
+}
+
+func TestBroadcaster_SubscribeLiveOnly(t *testing.T) {
+	b := NewBroadcaster[string](8, 8)
+	b.Publish("a")
+
+	sub, err := b.Subscribe(0)
+	require.NoError(t, err)
+	defer sub.Close()
+	assert.Equal(t, uint64(2), sub.Start())
+
+	b.Publish("b")
+	assert.Equal(t, []uint64{2}, receive(t, sub, 1))
+}
+
+func TestBroadcaster_ResumeReplaysHistory(t *testing.T) {
+	b := NewBroadcaster[int](8, 8)
+	for i := 0; i < 5; i++ {
+		b.Publish(i)
+	}
+	assert.Equal(t, uint64(5), b.Head())
+
+	sub, err := b.Subscribe(3)
+	require.NoError(t, err)
+	defer sub.Close()
+	b.Publish(5)
+	assert.Equal(t, []uint64{3, 4, 5, 6}, receive(t, sub, 4))
+}
+
+func TestBroadcaster_SequenceExpired(t *testing.T) {
+	b := NewBroadcaster[int](2, 8)
+	for i := 0; i < 5; i++ {
+		b.Publish(i)
+	}
+
+	_, err := b.Subscribe(2)
+	assert.ErrorIs(t, err, ErrSequenceExpired)
+
+	// A sequence ahead of the broadcaster belongs to another epoch
+	_, err = b.Subscribe(10)
+	assert.ErrorIs(t, err, ErrSequenceExpired)
+
+	sub, err := b.Subscribe(4)
+	require.NoError(t, err)
+	sub.Close()
+}
+
+func TestBroadcaster_SlowConsumer(t *testing.T) {
+	b := NewBroadcaster[int](8, 2)
+	sub, err := b.Subscribe(0)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		b.Publish(i)
+	}
+	<-sub.Done()
+	assert.ErrorIs(t, sub.Err(), ErrSlowConsumer)
+
+	// The subscriber resumes after the events it drained
+	assert.Equal(t, []uint64{1, 2}, receive(t, sub, 2))
+	resumed, err := b.Subscribe(3)
+	require.NoError(t, err)
+	defer resumed.Close()
+	assert.Equal(t, []uint64{3}, receive(t, resumed, 1))
+}
+
+func TestBroadcaster_Close(t *testing.T) {
+	b := NewBroadcaster[int](8, 8)
+	sub, err := b.Subscribe(0)
+	require.NoError(t, err)
+
+	b.Close()
+	<-sub.Done()
+	assert.ErrorIs(t, sub.Err(), ErrClosed)
+	assert.Zero(t, b.Publish(1))
+
+	_, err = b.Subscribe(0)
+	assert.ErrorIs(t, err, ErrClosed)
+}
@@ -0,0 +1,143 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+)
+
+// Receiver is the client side of a server stream, implemented by the generated clients
+type Receiver[M any] interface {
+	Header() (metadata.MD, error)
+	Recv() (M, error)
+}
+
+// OpenFunc opens a stream starting at the given sequence, zero for new values only
+type OpenFunc[M any] func(ctx context.Context, from uint64) (Receiver[M], error)
+
+// handlerError marks errors returned by the handler so they are not retried
+type handlerError struct{ err error }
+
+func (e handlerError) Error() string { return e.err.Error() }
+func (e handlerError) Unwrap() error { return e.err }
+
+// Follow receives a server stream until ctx is done, reconnecting with backoff when the
+// stream breaks and resuming after the last message handled. sequence extracts the
+// sequence number of a message. It returns the sequence to resume from with the error
+// that stopped it: a handler error, a non retryable status such as OutOfRange when the
+// server no longer retains the sequence, or running out of retries.
+func Follow[M any](ctx context.Context, from uint64, open OpenFunc[M], sequence func(M) uint64, handle func(M) error, config *retry.RetryConfig, logger logging.Logger) (uint64, error) {
+	if config == nil {
+		config = retry.DefaultRetryConfig()
+	}
+
+	var epoch string
+	delay := config.InitialDelay
+	failures := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return from, err
+		}
+
+		progressed, err := followOnce(ctx, &epoch, &from, open, sequence, handle)
+		var handlerErr handlerError
+		if errors.As(err, &handlerErr) {
+			return from, handlerErr.err
+		}
+		if ctx.Err() != nil {
+			return from, ctx.Err()
+		}
+		if !retryable(err) {
+			return from, err
+		}
+
+		if progressed {
+			delay = config.InitialDelay
+			failures = 0
+		}
+		failures++
+		if config.MaxRetries > 0 && failures > config.MaxRetries {
+			return from, fmt.Errorf("stream failed after %d attempts: %w", config.MaxRetries, err)
+		}
+
+		sleepDuration := retry.CalculateDelayWithJitter(delay, config.JitterFactor)
+		if config.LogRetryAttempt && logger != nil {
+			logger.Warn("Stream broke, resuming", "from", from, "error", err, "retry_in", sleepDuration)
+		}
+		select {
+		case <-time.After(sleepDuration):
+			delay = retry.CalculateNextDelay(delay, config.BackoffFactor, config.MaxDelay)
+		case <-ctx.Done():
+			return from, ctx.Err()
+		}
+	}
+}
+
+// followOnce receives one stream until it breaks, advancing from past every handled message
+func followOnce[M any](ctx context.Context, epoch *string, from *uint64, open OpenFunc[M], sequence func(M) uint64, handle func(M) error) (bool, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	receiver, err := open(streamCtx, *from)
+	if err != nil {
+		return false, err
+	}
+	header, err := receiver.Header()
+	if err != nil {
+		return false, err
+	}
+	if values := header.Get(EpochHeader); len(values) > 0 {
+		if *epoch != "" && values[0] != *epoch {
+			return false, status.Errorf(codes.OutOfRange, "stream epoch changed from %s to %s: %v", *epoch, values[0], ErrSequenceExpired)
+		}
+		*epoch = values[0]
+	}
+	if values := header.Get(StartHeader); len(values) > 0 && *from == 0 {
+		if start, err := strconv.ParseUint(values[0], 10, 64); err == nil {
+			*from = start
+		}
+	}
+
+	progressed := false
+	for {
+		message, err := receiver.Recv()
+		if err != nil {
+			return progressed, err
+		}
+		seq := sequence(message)
+		if seq != 0 && seq < *from {
+			// Already handled before the reconnect
+			continue
+		}
+		if err := handle(message); err != nil {
+			return progressed, handlerError{err: err}
+		}
+		if seq != 0 {
+			*from = seq + 1
+		}
+		progressed = true
+	}
+}
+
+// retryable reports whether a broken stream should be reopened
+func retryable(err error) bool {
+	if err == nil || errors.Is(err, io.EOF) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.Unknown, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
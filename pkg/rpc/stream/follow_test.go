@@ -0,0 +1,129 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/trigg3rX/triggerx-backend/pkg/logging"
+	"github.com/trigg3rX/triggerx-backend/pkg/retry"
+)
+
+// fakeReceiver serves a subscription and breaks after limit events
+type fakeReceiver struct {
+	b     *Broadcaster[int]
+	sub   *Subscription[int]
+	limit int
+}
+
+func (r *fakeReceiver) Header() (metadata.MD, error) {
+	return metadata.Pairs(EpochHeader, r.b.Epoch(), StartHeader, strconv.FormatUint(r.sub.Start(), 10)), nil
+}
+
+func (r *fakeReceiver) Recv() (Event[int], error) {
+	if r.limit == 0 {
+		r.sub.Close()
+		return Event[int]{}, status.Error(codes.Unavailable, "connection reset")
+	}
+	select {
+	case event := <-r.sub.Events():
+		r.limit--
+		return event, nil
+	case <-r.sub.Done():
+		return Event[int]{}, io.EOF
+	}
+}
+
+func testRetryConfig() *retry.RetryConfig {
+	return &retry.RetryConfig{
+		MaxRetries:    3,
+		InitialDelay:  time.Millisecond,
+		MaxDelay:      10 * time.Millisecond,
+		BackoffFactor: 2,
+	}
+}
+
+func TestFollow_ResumesAfterBreak(t *testing.T) {
+	b := NewBroadcaster[int](16, 16)
+	for i := 1; i <= 6; i++ {
+		b.Publish(i)
+	}
+
+	var opened []uint64
+	open := func(ctx context.Context, from uint64) (Receiver[Event[int]], error) {
+		opened = append(opened, from)
+		sub, err := b.Subscribe(from)
+		if err != nil {
+			return nil, Status(err)
+		}
+		return &fakeReceiver{b: b, sub: sub, limit: 2}, nil
+	}
+
+	var handled []int
+	done := errors.New("done")
+	next, err := Follow(context.Background(), 1, open,
+		func(e Event[int]) uint64 { return e.Sequence },
+		func(e Event[int]) error {
+			handled = append(handled, e.Value)
+			if len(handled) == 6 {
+				return done
+			}
+			return nil
+		},
+		testRetryConfig(), logging.NewNoOpLogger())
+
+	assert.ErrorIs(t, err, done)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, handled)
+	assert.Equal(t, []uint64{1, 3, 5}, opened)
+	// The failing event was not handled, so the caller resumes from it
+	assert.Equal(t, uint64(6), next)
+}
+
+func TestFollow_StopsWhenSequenceExpired(t *testing.T) {
+	b := NewBroadcaster[int](2, 16)
+	for i := 1; i <= 6; i++ {
+		b.Publish(i)
+	}
+
+	open := func(ctx context.Context, from uint64) (Receiver[Event[int]], error) {
+		sub, err := b.Subscribe(from)
+		if err != nil {
+			return nil, Status(err)
+		}
+		return &fakeReceiver{b: b, sub: sub, limit: -1}, nil
+	}
+
+	next, err := Follow(context.Background(), 1, open,
+		func(e Event[int]) uint64 { return e.Sequence },
+		func(e Event[int]) error { return nil },
+		testRetryConfig(), logging.NewNoOpLogger())
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+	assert.Equal(t, uint64(1), next)
+}
+
+func TestFollow_GivesUpAfterRetries(t *testing.T) {
+	attempts := 0
+	open := func(ctx context.Context, from uint64) (Receiver[Event[int]], error) {
+		attempts++
+		return nil, status.Error(codes.Unavailable, "connection refused")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := Follow(ctx, 0, open,
+		func(e Event[int]) uint64 { return e.Sequence },
+		func(e Event[int]) error { return nil },
+		testRetryConfig(), logging.NewNoOpLogger())
+	require.Error(t, err)
+	assert.Equal(t, codes.Unavailable, status.Code(errors.Unwrap(err)))
+	assert.Equal(t, 4, attempts)
+}
@@ -0,0 +1,65 @@
+package stream
+
+import (
+	"errors"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// EpochHeader carries the broadcaster's epoch in the stream header
+	EpochHeader = "x-stream-epoch"
+	// StartHeader carries the sequence of the first value sent on the stream
+	StartHeader = "x-stream-start"
+)
+
+// Serve streams the values of a broadcaster from the given sequence, zero for new values
+// only, until the client goes away. The epoch and first sequence are sent in the header so
+// the client can resume; send may skip values the client did not ask for by returning nil.
+func Serve[T any](ss grpc.ServerStream, b *Broadcaster[T], from uint64, send func(Event[T]) error) error {
+	sub, err := b.Subscribe(from)
+	if err != nil {
+		return Status(err)
+	}
+	defer sub.Close()
+
+	if err := ss.SendHeader(metadata.Pairs(
+		EpochHeader, b.Epoch(),
+		StartHeader, strconv.FormatUint(sub.Start(), 10),
+	)); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ss.Context().Done():
+			return status.FromContextError(ss.Context().Err()).Err()
+		case <-sub.Done():
+			return Status(sub.Err())
+		case event := <-sub.Events():
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Status converts the errors of a broadcaster to gRPC statuses
+func Status(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrSequenceExpired):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, ErrSlowConsumer):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, ErrClosed):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return err
+	}
+}
@@ -2,6 +2,8 @@ package tracing
 
 import (
 	"context"
+	"io"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -126,6 +128,111 @@ func TraceClientInterceptor(serviceName string) grpc.UnaryClientInterceptor {
 	}
 }
 
+// TraceStreamInterceptor provides OpenTelemetry tracing for gRPC streams, one span covering
+// the lifetime of the stream
+func TraceStreamInterceptor(serviceName string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ExtractTraceContext(ss.Context())
+
+		tracer := otel.Tracer(serviceName)
+		ctx, span := tracer.Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("grpc.method", info.FullMethod),
+				attribute.String("grpc.service", serviceName),
+				attribute.Bool("grpc.client_stream", info.IsClientStream),
+				attribute.Bool("grpc.server_stream", info.IsServerStream),
+			),
+		)
+		defer span.End()
+
+		startTime := time.Now()
+		err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+		span.SetAttributes(attribute.Int64("grpc.duration_ms", time.Since(startTime).Milliseconds()))
+		setSpanStatus(span, err)
+		return err
+	}
+}
+
+// TraceClientStreamInterceptor provides OpenTelemetry tracing for gRPC client streams, the
+// span ends when the stream does
+func TraceClientStreamInterceptor(serviceName string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		tracer := otel.Tracer(serviceName)
+		ctx, span := tracer.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("grpc.method", method),
+				attribute.String("grpc.service", serviceName),
+				attribute.String("grpc.target", cc.Target()),
+			),
+		)
+
+		stream, err := streamer(WithTraceContext(ctx), desc, cc, method, opts...)
+		if err != nil {
+			setSpanStatus(span, err)
+			span.End()
+			return nil, err
+		}
+		return &tracedClientStream{ClientStream: stream, span: span, serverStreams: desc.ServerStreams}, nil
+	}
+}
+
+// tracedServerStream carries the span context to the stream handler
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context holding the span
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// tracedClientStream ends its span when the stream finishes
+type tracedClientStream struct {
+	grpc.ClientStream
+	span          trace.Span
+	serverStreams bool
+	once          sync.Once
+}
+
+// RecvMsg receives a message, ending the span with the stream
+func (s *tracedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		if err == io.EOF {
+			err = nil
+		}
+		s.finish(err)
+	} else if !s.serverStreams {
+		s.finish(nil)
+	}
+	return err
+}
+
+func (s *tracedClientStream) finish(err error) {
+	s.once.Do(func() {
+		setSpanStatus(s.span, err)
+		s.span.End()
+	})
+}
+
+// setSpanStatus records the gRPC status of a finished call on its span
+func setSpanStatus(span trace.Span, err error) {
+	if err != nil {
+		st, _ := status.FromError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(
+			attribute.Int("grpc.status_code", int(st.Code())),
+			attribute.String("grpc.status_message", st.Message()),
+		)
+	} else {
+		span.SetStatus(codes.Ok, "")
+		span.SetAttributes(attribute.Int("grpc.status_code", int(codes.Ok)))
+	}
+}
+
 // MetadataTextMapCarrier implements the OpenTelemetry TextMapCarrier interface for gRPC metadata
 type MetadataTextMapCarrier metadata.MD
 
@@ -2,6 +2,8 @@ package rpc
 
 import (
 	"context"
+	"net"
+	"strconv"
 	"time"
 )
 
@@ -11,17 +13,61 @@ type contextKey string
 const (
 	// JWTClaimsKey is the context key for JWT claims
 	JWTClaimsKey contextKey = "jwt_claims"
+	// CallerIdentityKey is the context key for the authenticated caller of an RPC
+	CallerIdentityKey contextKey = "caller_identity"
 )
 
+// Sources a caller is authenticated from
+const (
+	CallerSourceMTLS      = "mtls"
+	CallerSourceToken     = "token"
+	CallerSourceAnonymous = "anonymous"
+)
+
+// Names services authenticate as: the common name of their client certificate and the
+// subject and audience of their service tokens
+const (
+	ServiceDBServer           = "dbserver"
+	ServiceTaskDispatcher     = "task-dispatcher"
+	ServiceTaskMonitor        = "task-monitor"
+	ServiceTimeScheduler      = "time-scheduler"
+	ServiceConditionScheduler = "condition-scheduler"
+)
+
+// CallerIdentity is the authenticated caller of an RPC
+type CallerIdentity struct {
+	Service string `json:"service"`
+	Source  string `json:"source"`
+}
+
+// CallerFromContext returns the caller identity set by the server's auth interceptor
+func CallerFromContext(ctx context.Context) (CallerIdentity, bool) {
+	identity, ok := ctx.Value(CallerIdentityKey).(CallerIdentity)
+	return identity, ok
+}
+
 // ServiceInfo represents information about an RPC service
 type ServiceInfo struct {
-	Name     string            `json:"name"`
-	Version  string            `json:"version"`
-	Address  string            `json:"address"`
-	Port     int               `json:"port"`
-	Metadata map[string]string `json:"metadata,omitempty"`
-	LastSeen time.Time         `json:"last_seen"`
-	Health   HealthStatus      `json:"health"`
+	Name       string            `json:"name"`
+	InstanceID string            `json:"instance_id,omitempty"` // empty when the service runs a single instance
+	Version    string            `json:"version"`
+	Address    string            `json:"address"`
+	Port       int               `json:"port"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	LastSeen   time.Time         `json:"last_seen"`
+	Health     HealthStatus      `json:"health"`
+}
+
+// Target returns the address to dial the service at, adding the port unless the address
+// already has one
+func (s ServiceInfo) Target() string {
+	if s.Port == 0 {
+		return s.Address
+	}
+	if _, _, err := net.SplitHostPort(s.Address); err == nil {
+		return s.Address
+	}
+	return net.JoinHostPort(s.Address, strconv.Itoa(s.Port))
 }
 
 // HealthStatus represents the health status of a service
@@ -50,6 +96,13 @@ type ServiceRegistry interface {
 	Watch(ctx context.Context, name string) (<-chan ServiceInfo, error)
 }
 
+// InstanceRegistry is a service registry tracking every instance of a service. Watch
+// delivers each instance on change, with an empty address once it is deregistered.
+type InstanceRegistry interface {
+	ServiceRegistry
+	ListInstances(ctx context.Context, name string) ([]ServiceInfo, error)
+}
+
 // RPCHandler defines the interface for RPC method handlers
 type RPCHandler interface {
 	Handle(ctx context.Context, method string, request interface{}) (interface{}, error)
@@ -202,9 +202,17 @@ type ApiKey struct {
 	IsActive     bool      `json:"is_active"`
 	IsKeeper     bool      `json:"is_keeper"`
 	RateLimit    int       `json:"rate_limit"`
+	Tier         string    `json:"tier,omitempty"`
+	KeyHash      []byte    `json:"-"`
+	KeySalt      []byte    `json:"-"`
+	Scopes       []string  `json:"scopes,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitzero"`
+	AllowedIPs   []string  `json:"allowed_ips,omitempty"`
+	ReplacedBy   string    `json:"replaced_by,omitempty"`
 	SuccessCount int64     `json:"success_count"`
 	FailedCount  int64     `json:"failed_count"`
 	LastUsed     time.Time `json:"last_used"`
+	LastUsedIP   string    `json:"last_used_ip,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
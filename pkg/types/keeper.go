@@ -12,7 +12,7 @@ type KeeperHealthCheckIn struct {
 	ConsensusAddress string    `json:"consensus_address" validate:"required,eth_addr"`
 	Version          string    `json:"version" validate:"required"`
 	Timestamp        time.Time `json:"timestamp" validate:"required"`
-	Signature        string    `json:"signature" validate:"required"`
+	Signature        string    `json:"signature" validate:"required" eip712:"-"`
 	PeerID           string    `json:"peer_id" validate:"required"`
 	IsImua           bool      `json:"is_imua" validate:"required"`
 }
@@ -23,6 +23,13 @@ type KeeperHealthCheckInResponse struct {
 	Data   string `json:"data"`
 }
 
+// TaskErrorReport is the part of a keeper's task error report covered by its signature
+type TaskErrorReport struct {
+	TaskID        int64  `json:"task_id"`
+	KeeperAddress string `json:"keeper_address"`
+	Error         string `json:"error"`
+}
+
 // Data from performer's action execution
 type PerformerActionData struct {
 	TaskID       int64  `json:"task_id"`
@@ -59,18 +66,50 @@ type ScriptMetadata struct {
 	GasEstimate uint64 `json:"gas_estimate,omitempty"`
 }
 
+// Proof formats. Proofs made before the format was versioned carry no version and are TLS proofs.
+const (
+	ProofVersionTLS     = 1
+	ProofVersionReceipt = 2
+)
+
 // Data from keeper's proof generation for execution done above
 type ProofData struct {
+	Version              int       `json:"version,omitempty"`
 	TaskID               int64     `json:"task_id"`
 	ProofOfTask          string    `json:"proof_of_task"`
 	CertificateHash      string    `json:"certificate_hash"`
 	CertificateTimestamp time.Time `json:"certificate_timestamp"`
+
+	// Set on receipt proofs only
+	ReceiptProof *ReceiptProof `json:"receipt_proof,omitempty"`
+}
+
+// ProofVersion returns the format of the proof, TLS for unversioned proofs
+func (p *ProofData) ProofVersion() int {
+	if p.Version == 0 {
+		return ProofVersionTLS
+	}
+	return p.Version
+}
+
+// ReceiptProof proves the performer's action transaction and its receipt are included in a block.
+// Byte fields are 0x prefixed hex.
+type ReceiptProof struct {
+	ChainID          string   `json:"chain_id"`
+	BlockNumber      uint64   `json:"block_number"`
+	BlockHash        string   `json:"block_hash"`
+	Header           string   `json:"header"` // RLP encoded block header
+	TransactionIndex uint64   `json:"transaction_index"`
+	Transaction      string   `json:"transaction"`       // Consensus encoded transaction
+	TransactionProof []string `json:"transaction_proof"` // Trie nodes from the header's transactions root
+	Receipt          string   `json:"receipt"`           // Consensus encoded receipt
+	ReceiptProof     []string `json:"receipt_proof"`     // Trie nodes from the header's receipts root
 }
 
 type PerformerSignatureData struct {
 	TaskID                  int64  `json:"task_id"`
 	PerformerSigningAddress string `json:"performer_signing_address"`
-	PerformerSignature      string `json:"performer_signature"`
+	PerformerSignature      string `json:"performer_signature" eip712:"-"`
 }
 
 // Data to Upload to IPFS
@@ -79,6 +118,9 @@ type IPFSData struct {
 	ActionData         *PerformerActionData    `json:"action_data"`
 	ProofData          *ProofData              `json:"proof_data"`
 	PerformerSignature *PerformerSignatureData `json:"performer_signature_data"`
+
+	// StoredSize is the size in bytes of the content the data was fetched as
+	StoredSize int64 `json:"-"`
 }
 
 // Data to Broadcast to Attesters from performer
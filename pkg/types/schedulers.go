@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strconv"
 	"time"
 )
 
@@ -61,6 +62,11 @@ type ScheduleTimeTaskData struct {
 	IsImua                 bool           `json:"is_imua"`
 }
 
+// JobLifecycleNotice tells the time scheduler that the DBServer paused or resumed a job
+type JobLifecycleNotice struct {
+	JobID *BigInt `json:"job_id"`
+}
+
 // Data to pass to condition scheduler
 type ScheduleConditionJobData struct {
 	JobID               *BigInt            `json:"job_id"`
@@ -106,13 +112,34 @@ type PerformerData struct {
 	IsImua        bool   `json:"is_imua"`
 }
 
+// PerformerHistory is a performer with its availability over a trailing window, as served by the health service
+type PerformerHistory struct {
+	PerformerData
+	IsActive      bool      `json:"is_active"`
+	LastCheckedIn time.Time `json:"last_checked_in"`
+	UptimePercent float64   `json:"uptime_percent"`
+	OutageCount   int       `json:"outage_count"`
+}
+
 type SendTaskDataToKeeper struct {
 	TaskID           []int64           `json:"task_id"`
 	PerformerData    PerformerData     `json:"performer_data"`
 	TargetData       []TaskTargetData  `json:"target_data"`
 	TriggerData      []TaskTriggerData `json:"trigger_data"`
 	SchedulerID      int               `json:"scheduler_id"`
-	ManagerSignature string            `json:"manager_signature"`
+	ManagerSignature string            `json:"manager_signature" eip712:"-"`
+}
+
+// SigningChainID is the chain the task is signed for, the chain of its first target
+func (t *SendTaskDataToKeeper) SigningChainID() int64 {
+	if t == nil || len(t.TargetData) == 0 {
+		return 0
+	}
+	chainID, err := strconv.ParseInt(t.TargetData[0].TargetChainID, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return chainID
 }
 
 // SchedulerTaskRequest represents the request format for TaskManager
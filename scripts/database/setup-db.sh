@@ -1,14 +1,24 @@
 #!/bin/bash
 
 # Execute the CQL script
-echo "Initializing database schema..."
+echo "Creating keyspace..."
 docker exec -i triggerx-scylla cqlsh < scripts/database/init-db.cql
 
 # Check if the keyspace was created
 if [ $? -eq 0 ]; then
-    echo "Database schema initialized successfully"
+    echo "Keyspace created successfully"
 else
-    echo "Failed to initialize database schema"
+    echo "Failed to create keyspace"
+    exit 1
+fi
+
+echo "Applying schema migrations..."
+go run ./cmd/dbserver/migrate up
+
+if [ $? -eq 0 ]; then
+    echo "Schema migrations applied successfully"
+else
+    echo "Failed to apply schema migrations"
     exit 1
 fi
 